@@ -43,7 +43,10 @@ func main() {
 
 	// 如果启用交易且模式为 auto 或 hybrid，自动启动交易循环
 	if c.Trading.Enabled && (c.Trading.Mode == "auto" || c.Trading.Mode == "hybrid") {
-		if ctx.TradingLoop != nil {
+		if ctx.RiskManager != nil && ctx.RiskManager.Halt() != nil {
+			// 全局交易暂停（Kill Switch）期间不自动启动，解除后需手动启动
+			logx.Errorf("Trading is halted, trading loop will not start automatically")
+		} else if ctx.TradingLoop != nil {
 			if err := ctx.TradingLoop.Start(context.Background()); err != nil {
 				logx.Errorf("Failed to start trading loop: %v", err)
 				// 不中断启动，允许 API 服务器继续运行
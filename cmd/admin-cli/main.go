@@ -5,13 +5,18 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 
 	"github.com/iluyuns/alpha-trade/internal/config"
+	"github.com/iluyuns/alpha-trade/internal/infra/tradinghalt"
+	risklogic "github.com/iluyuns/alpha-trade/internal/logic/risk"
 	"github.com/iluyuns/alpha-trade/internal/pkg/crypto"
 	"github.com/iluyuns/alpha-trade/internal/query"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
 	_ "github.com/lib/pq"
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
@@ -64,6 +69,8 @@ func main() {
 		handleResetPasskey(usersQuery, webauthnQuery, flag.Args()[1:])
 	case "set-password":
 		handleSetPassword(usersQuery, flag.Args()[1:])
+	case "simulate-order":
+		handleSimulateOrder(db, c, flag.Args()[1:])
 	default:
 		printUsage()
 		os.Exit(1)
@@ -75,6 +82,7 @@ func printUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  admin-cli -f <config> -p <pass> reset-passkey --user <username>")
 	fmt.Println("  admin-cli -f <config> -p <pass> set-password --user <username> --pass <new_password>")
+	fmt.Println("  admin-cli -f <config> -p <pass> simulate-order --account <id> --symbol <symbol> --side <BUY|SELL> --qty <qty> --current-price <price> [--price <price>] [--strategy <id>] [--market SPOT|FUTURE] [--type LIMIT|MARKET] [--leverage <n>] [--reduce-only]")
 }
 
 func handleResetPasskey(u *query.UsersCustom, w *query.WebauthnCredentialsCustom, args []string) {
@@ -155,6 +163,56 @@ func handleSetPassword(u *query.UsersCustom, args []string) {
 	fmt.Printf("Password for %s updated successfully.\n", *username)
 }
 
+// handleSimulateOrder 模拟下单前风控检查（与 POST /api/v1/risk/simulate 相同，不写入任何状态）
+func handleSimulateOrder(db *sql.DB, c config.Config, args []string) {
+	fs := flag.NewFlagSet("simulate-order", flag.ExitOnError)
+	req := types.RiskSimulateReq{}
+	fs.StringVar(&req.AccountID, "account", "", "account id")
+	fs.StringVar(&req.StrategyID, "strategy", "", "strategy id")
+	fs.StringVar(&req.Symbol, "symbol", "", "trading symbol")
+	fs.StringVar(&req.MarketType, "market", "SPOT", "market type (SPOT / FUTURE)")
+	fs.StringVar(&req.Side, "side", "", "order side (BUY / SELL)")
+	fs.StringVar(&req.Type, "type", "LIMIT", "order type (LIMIT / MARKET / IOC / FOK)")
+	fs.StringVar(&req.Price, "price", "", "limit price")
+	fs.StringVar(&req.Quantity, "qty", "", "order quantity")
+	fs.StringVar(&req.CurrentPrice, "current-price", "", "current market price")
+	fs.StringVar(&req.ProtectPrice, "protect-price", "", "protect price")
+	fs.Int64Var(&req.Leverage, "leverage", 0, "leverage (futures)")
+	fs.BoolVar(&req.ReduceOnly, "reduce-only", false, "reduce-only order")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	manager, _, err := svc.NewRiskManager(db, c)
+	if err != nil {
+		fmt.Printf("Error initializing risk manager: %v\n", err)
+		return
+	}
+
+	// 与运行中的服务保持一致：策略绩效降级与全局交易暂停
+	if c.Risk.Performance.Enabled {
+		tracker := svc.NewPerformanceTracker(db, c.Risk.Performance)
+		if err := tracker.Load(ctx); err != nil {
+			fmt.Printf("Warning: failed to load strategy performance: %v\n", err)
+		}
+		manager.SetPerformanceTracker(tracker)
+	}
+	halt, err := tradinghalt.NewPostgresRepo(db).ActiveHalt(ctx)
+	if err != nil {
+		fmt.Printf("Warning: failed to load trading halt: %v\n", err)
+	}
+	manager.SetHalt(halt)
+
+	l := risklogic.NewRiskSimulateLogic(ctx, &svc.ServiceContext{Config: c, RiskManager: manager})
+	resp, err := l.RiskSimulate(&req)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	out, _ := json.MarshalIndent(resp, "", "  ")
+	fmt.Println(string(out))
+}
+
 func generateRandomString(n int) string {
 	b := make([]byte, n)
 	if _, err := rand.Read(b); err != nil {
@@ -70,6 +70,19 @@ func main() {
 	strat := strategy.NewSimpleVolatility(*symbol, thresholdValue)
 	engine := strategy.NewEngine(strat, exchange, accountID)
 
+	// 模拟交易所的成交按顺序回调策略（策略依据成交维护持仓）
+	delivered := 0
+	deliverFills := func() {
+		trades, err := exchange.ListTrades(ctx, *symbol, time.Time{})
+		if err != nil {
+			log.Printf("List trades failed: %v", err)
+			return
+		}
+		for ; delivered < len(trades); delivered++ {
+			engine.HandleFill(ctx, strat.Name(), trades[delivered])
+		}
+	}
+
 	// 5. 回测循环
 	log.Printf("Starting backtest with %s strategy (threshold: %s)", strat.Name(), *threshold)
 	log.Println("=" + repeat("=", 60))
@@ -86,14 +99,17 @@ func main() {
 			continue
 		}
 
-		// 更新交易所价格
-		exchange.SetPrice(candle.Symbol, candle.Close)
+		// 按 K 线撮合挂单（条件单按最高/最低价触发）并更新交易所价格为收盘价
+		exchange.ProcessCandle(candle)
+		deliverFills()
 
 		// 风控检查（示例：检查每个信号）
 		// 实际应该在策略引擎内部集成风控
 
 		// 处理K线
-		if err := engine.ProcessCandle(ctx, candle); err != nil {
+		err = engine.ProcessCandle(ctx, candle)
+		deliverFills()
+		if err != nil {
 			log.Printf("[%s] Error processing candle: %v",
 				candle.OpenTime.Format("2006-01-02 15:04"), err)
 			stats.Errors++
@@ -49,6 +49,34 @@ type Config struct {
 		StrategyParams map[string]interface{} `json:",optional"` // 策略参数
 	}
 
+	// KillSwitch 紧急停止配置（全局交易暂停持久化到 trading_halts，重启后仍生效）
+	KillSwitch struct {
+		RequireSecondApproval bool     `json:",optional,default=false"` // 解除暂停需第二位管理员确认
+		NotifyEmails          []string `json:",optional"`               // 通知邮箱（为空时仅记录日志）
+		SyncSeconds           int      `json:",optional,default=5"`     // 多进程部署时同步暂停状态的间隔（秒）
+	}
+
+	// Reconcile 启动对账配置（以交易所为准校对订单、成交与风控持仓，对账完成前暂停非只减仓订单）
+	Reconcile struct {
+		Enabled           bool     `json:",optional,default=true"` // 启动时自动对账
+		LookbackMinutes   int      `json:",optional,default=1440"` // 成交回溯窗口（分钟）
+		PositionTolerance float64  `json:",optional,default=0.01"` // 持仓名义价值允许的相对偏差
+		QuoteAsset        string   `json:",optional,default=USDT"` // 计价资产
+		NotifyEmails      []string `json:",optional"`              // 差异告警邮箱（为空时仅记录日志）
+	}
+
+	// OMS 订单管理配置
+	OMS struct {
+		SubmitTimeoutSeconds int `json:",optional,default=10"` // 网关下单超时（超时后订单结果未知，待同步确认）
+		ResolveGraceSeconds  int `json:",optional,default=60"` // 启动解析未确认意图的宽限期（须大于下单超时，期内查无此单的意图保留待同步）
+	}
+
+	// Ledger 持仓账本配置（按成交记账净持仓、持仓成本与盈亏，持仓归零时写入 settlements）
+	Ledger struct {
+		CostMethod  string `json:",optional,default=WEIGHTED_AVERAGE"` // 持仓成本计算方法: WEIGHTED_AVERAGE, FIFO
+		SyncSeconds int    `json:",optional,default=10"`               // 拉取交易所成交的间隔（秒）
+	}
+
 	// Binance API 配置
 	Binance struct {
 		APIKey    string `json:",optional,env=BINANCE_API_KEY"`
@@ -67,6 +95,44 @@ type Config struct {
 		MaxTotalExposurePercent  float64 `json:",optional,default=0.7"` // 70%
 		MinCashReservePercent    float64 `json:",optional,default=0.3"` // 30%
 		MaxLeverage int `json:",optional,default=2"`
+
+		// Performance 策略绩效降级（见 RISK_PROTOCOL 7.2 动态风险降级）
+		Performance PerformanceConfig `json:",optional"`
+
+		// RuleSets 按账户装配的规则集（key: AccountID，default 为默认规则集，未配置时启用全部内置规则）
+		// strategy_configs 表中 risk.rule.* 配置在启动时覆盖同账户的 YAML 规则集
+		RuleSets map[string][]RiskRuleConfig `json:",optional"`
+
+		// Records 风控决策记录（写入 risk_records，Block / Reduce 全量记录）
+		Records RiskRecordsConfig `json:",optional"`
+
+		// History 风控状态历史（写入 risk_state_history，用于回撤曲线与熔断复盘）
+		History RiskHistoryConfig `json:",optional"`
+
+		// Sessions 按账户的交易日定义（key: AccountID，default 为默认定义，未配置时 UTC 0 点重置）
+		// 单独配置的账户由调度器按时主动滚动当日统计，并将交易日开始净值写入 asset_snapshots
+		Sessions            map[string]RiskSessionConfig `json:",optional"`
+		SessionCheckSeconds int                          `json:",optional,default=60"` // 滚动检查间隔（秒）
+	}
+
+	// Sizing 仓位计算配置
+	Sizing struct {
+		Enabled    bool                    `json:",optional,default=false"` // 是否启用（否则策略使用固定数量）
+		ATRPeriod  int                     `json:",optional,default=14"`    // ATR 周期
+		Default    SizingConfig            `json:",optional"`               // 默认配置
+		Strategies map[string]SizingConfig `json:",optional"`               // 按策略名称覆盖
+	}
+
+	// Stops 止损管理配置（见 RISK_PROTOCOL 6. 防扫单与防插针优化）
+	Stops struct {
+		ConfirmWindowMs   int64                       `json:",optional,default=1500"` // 确认延迟窗口（毫秒）
+		BypassMovePercent float64                     `json:",optional,default=0.03"` // 瞬间波动超过该比例时立即平仓
+		ATRPeriod         int                         `json:",optional,default=14"`   // 追踪止损 ATR 周期
+		Default           StopPolicyConfig            `json:",optional"`              // 默认止损移动策略
+		Strategies        map[string]StopPolicyConfig `json:",optional"`              // 按策略名称覆盖
+
+		// MarkPriceSource 标记价格来源：book_ticker（现货买一卖一中间价）/ futures_mark（合约 markPrice@1s）/ kline（K 线收盘价）
+		MarkPriceSource string `json:",optional,default=book_ticker,options=book_ticker|futures_mark|kline"`
 	}
 
 	// Redis 配置（用于 RiskRepo）
@@ -74,3 +140,69 @@ type Config struct {
 		URL string `json:",optional,env=REDIS_URL,default=redis://localhost:6379/0"`
 	}
 }
+
+// SizingConfig 单个策略的仓位计算配置
+type SizingConfig struct {
+	Mode         string  `json:",optional,default=volatility"` // volatility/fixed_fractional/kelly
+	RiskPerTrade float64 `json:",optional,default=0.01"`       // 每笔风险占净值比例（1%）
+	ATRMultiple  float64 `json:",optional,default=2"`          // 止损距离 = ATR * N
+	Fraction     float64 `json:",optional,default=0.1"`        // 固定比例模式：名义价值占比
+	WinRate      float64 `json:",optional"`                    // Kelly 模式：胜率
+	PayoffRatio  float64 `json:",optional"`                    // Kelly 模式：盈亏比
+	KellyCap     float64 `json:",optional,default=0.25"`       // Kelly 比例上限
+	LotSize      string  `json:",optional,default=0.00001"`    // 数量步长
+	MinQty       string  `json:",optional"`                    // 最小下单数量
+}
+
+// RiskRecordsConfig 风控决策记录配置
+type RiskRecordsConfig struct {
+	Enabled         bool    `json:",optional,default=true"` // 是否启用
+	AllowSampleRate float64 `json:",optional,default=0"`    // 放行决策抽样比例（0 不记录，1 全部记录）
+	BufferSize      int     `json:",optional,default=1024"` // 异步缓冲容量（满时丢弃并计数）
+	BatchSize       int     `json:",optional,default=100"`  // 单次批量写入条数
+	FlushIntervalMs int64   `json:",optional,default=1000"` // 定时写入间隔（毫秒）
+}
+
+// RiskHistoryConfig 风控状态历史配置
+type RiskHistoryConfig struct {
+	Enabled               bool  `json:",optional,default=true"` // 是否启用
+	SnapshotIntervalSec   int64 `json:",optional,default=60"`   // 定时快照间隔（秒）
+	PeriodicRetentionDays int   `json:",optional,default=30"`   // 定时快照保留天数
+	EventRetentionDays    int   `json:",optional,default=365"`  // 变更 / 日切 / 熔断快照保留天数
+	BufferSize            int   `json:",optional,default=1024"` // 异步缓冲容量
+	BatchSize             int   `json:",optional,default=100"`  // 单次批量写入条数
+	FlushIntervalMs       int64 `json:",optional,default=1000"` // 定时写入间隔（毫秒）
+}
+
+// RiskSessionConfig 交易日定义配置
+type RiskSessionConfig struct {
+	Timezone       string `json:",optional,default=UTC"` // IANA 时区（如 Asia/Shanghai）
+	ResetHour      int    `json:",optional"`             // 每日重置时刻（0-23）
+	WeeklyResetDay string `json:",optional"`             // 每周重置日（Monday ~ Sunday，空表示不启用）
+}
+
+// PerformanceConfig 策略绩效降级配置
+type PerformanceConfig struct {
+	Enabled            bool    `json:",optional,default=true"` // 是否启用
+	Window             int     `json:",optional,default=10"`   // 滚动窗口交易笔数
+	MinWinRate         float64 `json:",optional,default=0.3"`  // 胜率低于该值进入减半模式
+	HalfSizeMultiplier float64 `json:",optional,default=0.5"`  // 减半模式仓位系数
+	LossStreak         int     `json:",optional,default=5"`    // 连续亏损达到该次数进入冷静期
+	CoolingOffHours    int     `json:",optional,default=24"`   // 冷静期时长（小时）
+}
+
+// RiskRuleConfig 单条风控规则配置
+type RiskRuleConfig struct {
+	Name     string             // 规则名称：circuit_breaker / strategy_performance / position_limit
+	Priority int                `json:",optional"`              // 优先级（越小越先评估，0 使用默认值）
+	Scope    string             `json:",optional"`              // 作用域：system / account / strategy / symbol
+	Target   string             `json:",optional"`              // 作用对象（策略 ID / 交易对，空表示不限定）
+	Enabled  bool               `json:",optional,default=true"` // 是否启用
+	Params   map[string]float64 `json:",optional"`              // 覆盖的风控参数（如 max_leverage）
+}
+
+// StopPolicyConfig 止损移动策略配置
+type StopPolicyConfig struct {
+	BreakevenR          float64 `json:",optional,default=1.2"` // 浮盈达到 N 倍 R 时移至保本（0 关闭）
+	TrailingATRMultiple float64 `json:",optional"`             // 保本后按 ATR * N 追踪（0 关闭）
+}
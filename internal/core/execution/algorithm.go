@@ -0,0 +1,81 @@
+package execution
+
+import (
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// AlgorithmTWAP 时间加权平均执行
+const AlgorithmTWAP = "TWAP"
+
+// Algorithm 执行算法（可插拔，后续可扩展 VWAP / POV）
+type Algorithm interface {
+	// Name 算法名称（持久化到母单）
+	Name() string
+
+	// NextSlice 计算当前时刻应下发的子单数量（零表示本轮不下单）
+	NextSlice(parent *model.ParentOrder, now time.Time) model.Money
+}
+
+// TWAP 时间加权平均执行：将母单在 [StartTime, EndTime] 内均分为 Slices 笔
+// 每个时间片的累计目标 = 总量 × 已到达时间片数 / Slices，最后一片下发全部剩余
+type TWAP struct {
+	LotSize model.Money // 数量步长（零值表示不取整）
+}
+
+// NewTWAP 创建 TWAP 算法
+func NewTWAP(lotSize model.Money) *TWAP {
+	return &TWAP{
+		LotSize: lotSize,
+	}
+}
+
+// Name 实现 Algorithm
+func (t *TWAP) Name() string {
+	return AlgorithmTWAP
+}
+
+// NextSlice 实现 Algorithm
+func (t *TWAP) NextSlice(parent *model.ParentOrder, now time.Time) model.Money {
+	remaining := parent.Remaining()
+	if !remaining.IsPositive() || now.Before(parent.StartTime) {
+		return model.Zero()
+	}
+
+	// 到达结束时间或单片执行：下发全部剩余
+	duration := parent.EndTime.Sub(parent.StartTime)
+	if parent.Slices <= 1 || duration <= 0 || !now.Before(parent.EndTime) {
+		return remaining
+	}
+
+	slot := duration / time.Duration(parent.Slices)
+	reached := int(now.Sub(parent.StartTime)/slot) + 1
+	if reached >= parent.Slices {
+		return remaining
+	}
+
+	target := parent.Quantity.
+		Mul(model.NewMoneyFromInt(int64(reached))).
+		Div(model.NewMoneyFromInt(int64(parent.Slices)))
+	slice := target.Sub(parent.Submitted)
+	if !t.LotSize.IsZero() {
+		slice = slice.FloorToStep(t.LotSize)
+	}
+	if !slice.IsPositive() {
+		return model.Zero()
+	}
+	return slice.Min(remaining)
+}
+
+// ShouldSplit 判断平仓是否需要拆单执行（见 RISK_PROTOCOL 5.2 流动性枯竭）
+// depth 为 1% 价格范围内的盘口深度，quantity 超过 depth × ratio 时需 TWAP 拆分
+func ShouldSplit(quantity, depth model.Money, ratio float64) bool {
+	if !depth.IsPositive() {
+		return false
+	}
+	if ratio <= 0 {
+		ratio = 0.2
+	}
+	return quantity.GT(depth.Mul(model.NewMoneyFromFloat(ratio)))
+}
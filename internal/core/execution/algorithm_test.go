@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestTWAP_NextSlice(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	parent := &model.ParentOrder{
+		Quantity:  model.MustMoney("1"),
+		Submitted: model.Zero(),
+		StartTime: start,
+		EndTime:   start.Add(40 * time.Second),
+		Slices:    4,
+	}
+	twap := NewTWAP(model.MustMoney("0.001"))
+
+	if got := twap.NextSlice(parent, start); !got.EQ(model.MustMoney("0.25")) {
+		t.Fatalf("first slice: got %s, want 0.25", got)
+	}
+	parent.Submitted = model.MustMoney("0.25")
+
+	// 同一时间片内不重复下发
+	if got := twap.NextSlice(parent, start.Add(5*time.Second)); !got.IsZero() {
+		t.Errorf("expected no slice within same slot, got %s", got)
+	}
+
+	// 错过一个时间片后补齐累计目标
+	if got := twap.NextSlice(parent, start.Add(25*time.Second)); !got.EQ(model.MustMoney("0.5")) {
+		t.Errorf("catch-up slice: got %s, want 0.5", got)
+	}
+
+	// 最后一片下发全部剩余
+	if got := twap.NextSlice(parent, start.Add(31*time.Second)); !got.EQ(model.MustMoney("0.75")) {
+		t.Errorf("last slice: got %s, want 0.75", got)
+	}
+}
+
+func TestTWAP_LotSize(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	parent := &model.ParentOrder{
+		Quantity:  model.MustMoney("1"),
+		Submitted: model.Zero(),
+		StartTime: start,
+		EndTime:   start.Add(30 * time.Second),
+		Slices:    3,
+	}
+
+	got := NewTWAP(model.MustMoney("0.01")).NextSlice(parent, start)
+	if !got.EQ(model.MustMoney("0.33")) {
+		t.Errorf("slice should be floored to lot size: got %s, want 0.33", got)
+	}
+}
+
+func TestShouldSplit(t *testing.T) {
+	depth := model.MustMoney("10")
+
+	if ShouldSplit(model.MustMoney("2"), depth, 0.2) {
+		t.Error("exit equal to 20% of depth should not be split")
+	}
+	if !ShouldSplit(model.MustMoney("2.1"), depth, 0.2) {
+		t.Error("exit above 20% of depth should be split")
+	}
+	if ShouldSplit(model.MustMoney("100"), model.Zero(), 0.2) {
+		t.Error("unknown depth should not trigger split")
+	}
+}
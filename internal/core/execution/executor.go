@@ -0,0 +1,586 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iluyuns/alpha-trade/internal/core/oms"
+	"github.com/iluyuns/alpha-trade/internal/core/position"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+var (
+	// ErrInvalidParent 母单参数无效
+	ErrInvalidParent = errors.New("execution: invalid parent order")
+	// ErrUnknownAlgorithm 未注册的执行算法
+	ErrUnknownAlgorithm = errors.New("execution: unknown algorithm")
+	// ErrParentNotFound 母单不存在
+	ErrParentNotFound = errors.New("execution: parent order not found")
+	// ErrParentNotActive 母单已结束
+	ErrParentNotActive = errors.New("execution: parent order not active")
+)
+
+// Config 执行器配置
+type Config struct {
+	DefaultSlices   int           // 默认子单数量（默认 10）
+	DefaultDuration time.Duration // 默认执行时长（默认 60s）
+	MaxChildErrors  int           // 子单连续失败次数上限，超过后母单标记失败（默认 3）
+	SplitRatio      float64       // 平仓数量超过盘口深度 × SplitRatio 时拆单执行（默认 0.2，见 ShouldSplit）
+}
+
+// DepthSource 盘口深度来源（由现货网关实现）
+// 返回 1% 价格范围内按成交方向可吃到的数量：卖出取买盘，买入取卖盘
+type DepthSource interface {
+	Depth(ctx context.Context, symbol string, side model.OrderSide) (model.Money, error)
+}
+
+// SubmitRequest 母单提交请求
+type SubmitRequest struct {
+	AccountID  string
+	StrategyID string
+	Symbol     string
+	Side       model.OrderSide
+	Quantity   model.Money
+	Algorithm  string        // 为空时使用 TWAP
+	Duration   time.Duration // 为零时使用默认执行时长
+	Slices     int           // 为零时使用默认子单数量
+	ReduceOnly bool
+	Priority   oms.Priority
+}
+
+// AmendRequest 母单修改请求（零值字段保持不变）
+type AmendRequest struct {
+	Quantity model.Money // 新总数量，不得小于已下发数量
+	EndTime  time.Time   // 新的计划完成时间
+}
+
+// parentState 内部跟踪状态
+type parentState struct {
+	parent      *model.ParentOrder
+	priority    oms.Priority
+	childErrors int // 连续失败次数
+}
+
+// Executor 算法执行器
+// 职责：
+// 1. 持有母单，按执行算法调度子单经 OMS 下发（子单同样经过风控）
+// 2. 支持母单撤销与修改（数量/完成时间）
+// 3. 持久化母单与子单关联，重启后恢复执行进度
+type Executor struct {
+	mu         sync.Mutex
+	manager    *oms.Manager
+	repo       port.ExecutionRepo
+	config     Config
+	depth      DepthSource                  // 可选，nil 时平仓不拆单
+	algorithms map[string]Algorithm         // key: Algorithm.Name()
+	parents    map[string]*parentState      // key: ParentID
+	children   map[string]*model.ChildOrder // key: ClientOrderID，未结束的子单
+	lastMarks  map[string]model.Money       // key: Symbol
+
+	updatesMu sync.Mutex
+	updates   []*model.Order // 待处理的子单回报（见 HandleOrderUpdate）
+}
+
+// NewExecutor 创建算法执行器（默认注册 TWAP）
+func NewExecutor(manager *oms.Manager, repo port.ExecutionRepo, config Config) *Executor {
+	if config.DefaultSlices == 0 {
+		config.DefaultSlices = 10
+	}
+	if config.DefaultDuration == 0 {
+		config.DefaultDuration = 60 * time.Second
+	}
+	if config.MaxChildErrors == 0 {
+		config.MaxChildErrors = 3
+	}
+	if config.SplitRatio <= 0 {
+		config.SplitRatio = 0.2
+	}
+
+	e := &Executor{
+		manager:    manager,
+		repo:       repo,
+		config:     config,
+		algorithms: make(map[string]Algorithm),
+		parents:    make(map[string]*parentState),
+		children:   make(map[string]*model.ChildOrder),
+		lastMarks:  make(map[string]model.Money),
+	}
+	e.RegisterAlgorithm(NewTWAP(model.Zero()))
+	return e
+}
+
+// RegisterAlgorithm 注册执行算法（同名覆盖）
+func (e *Executor) RegisterAlgorithm(algo Algorithm) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.algorithms[algo.Name()] = algo
+}
+
+// SetDepthSource 设置盘口深度来源（止损平仓数量超过深度阈值时经母单拆分执行）
+func (e *Executor) SetDepthSource(source DepthSource) {
+	e.depth = source
+}
+
+// OnMarkPrice 更新标记价格（子单风控计算使用）
+func (e *Executor) OnMarkPrice(symbol string, mark model.Money) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastMarks[symbol] = mark
+}
+
+// Restore 从仓储恢复执行中的母单及其未结束的子单（服务重启后调用）
+// 优先级不持久化：只减仓母单按高优先级恢复，其余按普通优先级
+func (e *Executor) Restore(ctx context.Context) error {
+	parents, err := e.repo.ListActiveParents(ctx)
+	if err != nil {
+		return fmt.Errorf("restore parent orders failed: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, parent := range parents {
+		children, err := e.repo.ListChildren(ctx, parent.ID)
+		if err != nil {
+			return fmt.Errorf("restore children of parent %s failed: %w", parent.ID, err)
+		}
+		for _, child := range children {
+			if !childClosed(child.Status) {
+				e.children[child.ClientOrderID] = child
+			}
+		}
+
+		priority := oms.PriorityNormal
+		if parent.ReduceOnly {
+			priority = oms.PriorityHigh
+		}
+		e.parents[parent.ID] = &parentState{parent: parent, priority: priority}
+	}
+	return nil
+}
+
+// HandleOrderUpdate 子单状态回调（挂到 OMS 的 OrderUpdateHandler），更新子单与母单的成交进度
+// OMS 在下单过程中同步回调，此时执行器可能正持有锁：回报先入队，能获取锁时立即处理，否则由持锁的调度或下一轮 Tick 处理
+func (e *Executor) HandleOrderUpdate(ctx context.Context, order *model.Order) {
+	e.updatesMu.Lock()
+	e.updates = append(e.updates, order)
+	e.updatesMu.Unlock()
+
+	if e.mu.TryLock() {
+		e.drainUpdates(ctx, time.Now())
+		e.mu.Unlock()
+	}
+}
+
+// SplitExit 平仓数量超过盘口深度阈值时提交只减仓的高优先级母单拆分执行，返回是否已拆单
+// 未设置深度来源或获取深度失败时不拆单，由调用方直接市价平仓（止损优先成交）
+func (e *Executor) SplitExit(ctx context.Context, req *position.ExitRequest, now time.Time) (bool, error) {
+	if e.depth == nil {
+		return false, nil
+	}
+	stop := req.Stop
+	depth, err := e.depth.Depth(ctx, stop.Symbol, stop.ExitSide())
+	if err != nil || !ShouldSplit(stop.Quantity, depth, e.config.SplitRatio) {
+		return false, nil
+	}
+
+	if req.MarkPrice.IsPositive() {
+		e.OnMarkPrice(stop.Symbol, req.MarkPrice)
+	}
+	_, err = e.Submit(ctx, &SubmitRequest{
+		AccountID:  stop.AccountID,
+		StrategyID: stop.StrategyID,
+		Symbol:     stop.Symbol,
+		Side:       stop.ExitSide(),
+		Quantity:   stop.Quantity,
+		ReduceOnly: true,
+		Priority:   oms.PriorityHigh,
+	}, now)
+	if err != nil {
+		return false, fmt.Errorf("submit split exit failed: %w", err)
+	}
+	return true, nil
+}
+
+// Submit 提交母单并立即调度第一笔子单
+func (e *Executor) Submit(ctx context.Context, req *SubmitRequest, now time.Time) (*model.ParentOrder, error) {
+	if req.Symbol == "" || req.AccountID == "" || !req.Quantity.IsPositive() {
+		return nil, ErrInvalidParent
+	}
+
+	algorithm := req.Algorithm
+	if algorithm == "" {
+		algorithm = AlgorithmTWAP
+	}
+	duration := req.Duration
+	if duration == 0 {
+		duration = e.config.DefaultDuration
+	}
+	slices := req.Slices
+	if slices == 0 {
+		slices = e.config.DefaultSlices
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.algorithms[algorithm]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, algorithm)
+	}
+
+	parent := &model.ParentOrder{
+		ID:         uuid.Must(uuid.NewV7()).String(),
+		AccountID:  req.AccountID,
+		StrategyID: req.StrategyID,
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		Algorithm:  algorithm,
+		Quantity:   req.Quantity,
+		Submitted:  model.Zero(),
+		Filled:     model.Zero(),
+		StartTime:  now,
+		EndTime:    now.Add(duration),
+		Slices:     slices,
+		ReduceOnly: req.ReduceOnly,
+		Status:     model.ParentStatusActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := e.repo.SaveParent(ctx, parent); err != nil {
+		return nil, err
+	}
+
+	state := &parentState{parent: parent, priority: req.Priority}
+	e.parents[parent.ID] = state
+	e.step(ctx, state, now)
+	e.drainUpdates(ctx, now)
+
+	copied := *parent
+	return &copied, nil
+}
+
+// Tick 调度所有执行中的母单（由定时器或回测循环驱动）
+func (e *Executor) Tick(ctx context.Context, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.drainUpdates(ctx, now)
+	for _, state := range e.parents {
+		e.step(ctx, state, now)
+	}
+	e.drainUpdates(ctx, now)
+}
+
+// Cancel 撤销母单，并撤销未完成的子单
+func (e *Executor) Cancel(ctx context.Context, parentID string, now time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, ok := e.parents[parentID]
+	if !ok {
+		return e.missing(ctx, parentID)
+	}
+
+	children, err := e.repo.ListChildren(ctx, parentID)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, child := range children {
+		order, err := e.manager.GetOrder(ctx, child.ClientOrderID)
+		if err != nil || order.IsClosed() {
+			continue
+		}
+		if err := e.manager.CancelOrder(ctx, child.ClientOrderID); err != nil {
+			lastErr = err
+			continue
+		}
+		child.Status = model.OrderStatusCancelled
+		_ = e.repo.SaveChild(ctx, child)
+	}
+
+	parent := state.parent
+	parent.Status = model.ParentStatusCancelled
+	parent.UpdatedAt = now
+	if err := e.repo.SaveParent(ctx, parent); err != nil {
+		return err
+	}
+	e.forget(parentID)
+
+	return lastErr
+}
+
+// CancelAll 撤销全部执行中的母单（Kill Switch），返回撤销的母单数
+func (e *Executor) CancelAll(ctx context.Context, now time.Time) (int, error) {
+	var (
+		cancelled int
+		lastErr   error
+	)
+	for _, parent := range e.ListActive() {
+		err := e.Cancel(ctx, parent.ID, now)
+		switch {
+		case errors.Is(err, ErrParentNotActive), errors.Is(err, ErrParentNotFound):
+			// 已并发完成或撤销
+		case err != nil:
+			lastErr = fmt.Errorf("cancel parent %s failed: %w", parent.ID, err)
+		default:
+			cancelled++
+		}
+	}
+	return cancelled, lastErr
+}
+
+// Amend 修改母单总数量或计划完成时间
+func (e *Executor) Amend(ctx context.Context, parentID string, req *AmendRequest, now time.Time) (*model.ParentOrder, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, ok := e.parents[parentID]
+	if !ok {
+		return nil, e.missing(ctx, parentID)
+	}
+
+	parent := state.parent
+	if !req.Quantity.IsZero() {
+		if req.Quantity.LT(parent.Submitted) {
+			return nil, fmt.Errorf("%w: quantity %s below submitted %s", ErrInvalidParent, req.Quantity, parent.Submitted)
+		}
+		parent.Quantity = req.Quantity
+	}
+	if !req.EndTime.IsZero() {
+		if !req.EndTime.After(now) {
+			return nil, fmt.Errorf("%w: end time in the past", ErrInvalidParent)
+		}
+		parent.EndTime = req.EndTime
+	}
+	if !parent.Filled.LT(parent.Quantity) {
+		parent.Status = model.ParentStatusCompleted
+	}
+	parent.UpdatedAt = now
+
+	if err := e.repo.SaveParent(ctx, parent); err != nil {
+		return nil, err
+	}
+	if !parent.IsActive() {
+		e.forget(parentID)
+	}
+
+	copied := *parent
+	return &copied, nil
+}
+
+// Get 查询母单（包括已结束的母单）
+func (e *Executor) Get(ctx context.Context, parentID string) (*model.ParentOrder, error) {
+	e.mu.Lock()
+	state, ok := e.parents[parentID]
+	if ok {
+		copied := *state.parent
+		e.mu.Unlock()
+		return &copied, nil
+	}
+	e.mu.Unlock()
+
+	return e.repo.GetParent(ctx, parentID)
+}
+
+// Children 查询母单下发的子单
+func (e *Executor) Children(ctx context.Context, parentID string) ([]*model.ChildOrder, error) {
+	return e.repo.ListChildren(ctx, parentID)
+}
+
+// ListActive 列出执行中的母单
+func (e *Executor) ListActive() []*model.ParentOrder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	parents := make([]*model.ParentOrder, 0, len(e.parents))
+	for _, state := range e.parents {
+		copied := *state.parent
+		parents = append(parents, &copied)
+	}
+	return parents
+}
+
+// Start 启动定时调度（后台 goroutine）
+func (e *Executor) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				e.Tick(ctx, now)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// step 调度单个母单（调用方持有锁）
+func (e *Executor) step(ctx context.Context, state *parentState, now time.Time) {
+	parent := state.parent
+	if !parent.IsActive() {
+		return
+	}
+
+	algo, ok := e.algorithms[parent.Algorithm]
+	if !ok {
+		e.finish(ctx, state, model.ParentStatusFailed, ErrUnknownAlgorithm.Error(), now)
+		return
+	}
+
+	quantity := algo.NextSlice(parent, now)
+	if !quantity.IsPositive() {
+		return
+	}
+
+	// 无标记价格时无法完成风控计算，等待下一轮
+	mark, ok := e.lastMarks[parent.Symbol]
+	if !ok || !mark.IsPositive() {
+		return
+	}
+
+	seq := parent.ChildCount + 1
+	clientOrderID, err := model.NewClientOrderID(model.OrderIntentTWAPChild, parent.StrategyID, parent.Symbol)
+	if err != nil {
+		e.finish(ctx, state, model.ParentStatusFailed, err.Error(), now)
+		return
+	}
+	order, err := e.manager.PlaceOrder(ctx, &oms.PlaceOrderRequest{
+		ClientOrderID: clientOrderID,
+		Symbol:        parent.Symbol,
+		Side:          parent.Side,
+		Type:          model.OrderTypeMarket,
+		Price:         mark,
+		Quantity:      quantity,
+		CurrentPrice:  mark,
+		AccountID:     parent.AccountID,
+		ReduceOnly:    parent.ReduceOnly,
+		Priority:      state.priority,
+	})
+	if err != nil {
+		state.childErrors++
+		parent.LastError = err.Error()
+		parent.UpdatedAt = now
+		if state.childErrors >= e.config.MaxChildErrors {
+			e.finish(ctx, state, model.ParentStatusFailed, parent.LastError, now)
+			return
+		}
+		_ = e.repo.SaveParent(ctx, parent)
+		return
+	}
+	state.childErrors = 0
+
+	e.children[order.ClientOrderID] = &model.ChildOrder{
+		ParentID:      parent.ID,
+		ClientOrderID: order.ClientOrderID,
+		Seq:           seq,
+		Quantity:      order.Quantity,
+		Filled:        model.Zero(),
+		Status:        model.OrderStatusPending,
+		CreatedAt:     now,
+	}
+
+	// 风控降档时以实际下单数量计入；成交数量按子单回报累计
+	parent.Submitted = parent.Submitted.Add(order.Quantity)
+	parent.ChildCount = seq
+	e.applyChildUpdate(ctx, order, now)
+}
+
+// drainUpdates 处理队列中的子单回报（调用方持有锁）
+func (e *Executor) drainUpdates(ctx context.Context, now time.Time) {
+	for {
+		e.updatesMu.Lock()
+		updates := e.updates
+		e.updates = nil
+		e.updatesMu.Unlock()
+
+		if len(updates) == 0 {
+			return
+		}
+		for _, order := range updates {
+			e.applyChildUpdate(ctx, order, now)
+		}
+	}
+}
+
+// applyChildUpdate 按子单回报更新子单状态与母单成交数量（调用方持有锁）
+// 子单被拒绝或撤销时，未成交部分退回母单剩余数量，由执行算法重新调度；母单按成交数量判断完成
+func (e *Executor) applyChildUpdate(ctx context.Context, order *model.Order, now time.Time) {
+	child, ok := e.children[order.ClientOrderID]
+	if !ok {
+		return
+	}
+	state, ok := e.parents[child.ParentID]
+	if !ok {
+		delete(e.children, order.ClientOrderID)
+		return
+	}
+	if order.Filled.LT(child.Filled) {
+		return // 过期回报
+	}
+
+	parent := state.parent
+	parent.Filled = parent.Filled.Add(order.Filled.Sub(child.Filled))
+	child.Filled = order.Filled
+	child.Status = order.Status
+	if childClosed(order.Status) {
+		delete(e.children, order.ClientOrderID)
+		if unfilled := child.Quantity.Sub(child.Filled); unfilled.IsPositive() {
+			parent.Submitted = parent.Submitted.Sub(unfilled)
+		}
+	}
+	parent.UpdatedAt = now
+	_ = e.repo.SaveChild(ctx, child)
+
+	if !parent.Filled.LT(parent.Quantity) {
+		e.finish(ctx, state, model.ParentStatusCompleted, parent.LastError, now)
+		return
+	}
+	_ = e.repo.SaveParent(ctx, parent)
+}
+
+// missing 区分母单已结束与不存在
+func (e *Executor) missing(ctx context.Context, parentID string) error {
+	if _, err := e.repo.GetParent(ctx, parentID); err == nil {
+		return ErrParentNotActive
+	}
+	return ErrParentNotFound
+}
+
+// finish 结束母单并移出调度（调用方持有锁）
+func (e *Executor) finish(ctx context.Context, state *parentState, status model.ParentStatus, lastError string, now time.Time) {
+	parent := state.parent
+	parent.Status = status
+	parent.LastError = lastError
+	parent.UpdatedAt = now
+	_ = e.repo.SaveParent(ctx, parent)
+	e.forget(parent.ID)
+}
+
+// forget 母单结束后移出调度并停止跟踪其子单（调用方持有锁）
+func (e *Executor) forget(parentID string) {
+	delete(e.parents, parentID)
+	for id, child := range e.children {
+		if child.ParentID == parentID {
+			delete(e.children, id)
+		}
+	}
+}
+
+// childClosed 子单是否已结束（成交、撤销或拒绝）
+func childClosed(status model.OrderStatus) bool {
+	return status == model.OrderStatusFilled ||
+		status == model.OrderStatusCancelled ||
+		status == model.OrderStatusRejected
+}
@@ -0,0 +1,329 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/core/oms"
+	"github.com/iluyuns/alpha-trade/internal/core/position"
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
+	execrepo "github.com/iluyuns/alpha-trade/internal/infra/execution"
+	"github.com/iluyuns/alpha-trade/internal/infra/order"
+	"github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+func newTestExecutor(t *testing.T, accountID string) (*Executor, *execrepo.MemoryRepo, *order.MemoryRepo) {
+	t.Helper()
+	executor, repo, orderRepo, _ := newTestExecutorWithExchange(t, accountID)
+	return executor, repo, orderRepo
+}
+
+// newTestExecutorWithExchange 创建执行器，子单回报经 OMS 的 OrderUpdateHandler 同步回调执行器
+func newTestExecutorWithExchange(t *testing.T, accountID string) (*Executor, *execrepo.MemoryRepo, *order.MemoryRepo, *mock.SpotExchange) {
+	t.Helper()
+	ctx := context.Background()
+
+	exchange := mock.NewSpotExchange(map[string]model.Money{
+		"USDT": model.MustMoney("10000"),
+		"BTC":  model.MustMoney("1"),
+	})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+
+	orderRepo := order.NewMemoryRepo()
+	riskRepo := risk.NewMemoryRiskRepo()
+	riskMgr := risklogic.NewManager(riskRepo, risklogic.RiskConfig{
+		MaxSinglePositionPercent: 0.3,
+		MaxConsecutiveLosses:     3,
+	})
+	_ = riskRepo.SaveState(ctx, model.NewRiskState(accountID, model.MustMoney("10000")))
+
+	manager := oms.NewManager(exchange, orderRepo, riskMgr, oms.Config{AutoSync: false})
+	repo := execrepo.NewMemoryRepo()
+
+	executor := NewExecutor(manager, repo, Config{})
+	manager.SetOrderUpdateHandler(executor.HandleOrderUpdate)
+	executor.OnMarkPrice("BTCUSDT", model.MustMoney("50000"))
+	return executor, repo, orderRepo, exchange
+}
+
+func TestExecutor_TWAPExit(t *testing.T) {
+	ctx := context.Background()
+	executor, repo, orderRepo := newTestExecutor(t, "twap-exit-account")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 平仓数量远超单标的仓位限制，只减仓子单不受影响
+	parent, err := executor.Submit(ctx, &SubmitRequest{
+		AccountID:  "twap-exit-account",
+		Symbol:     "BTCUSDT",
+		Side:       model.OrderSideSell,
+		Quantity:   model.MustMoney("0.8"),
+		Duration:   40 * time.Second,
+		Slices:     4,
+		ReduceOnly: true,
+		Priority:   oms.PriorityHigh,
+	}, start)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if parent.ChildCount != 1 || !parent.Submitted.EQ(model.MustMoney("0.2")) {
+		t.Fatalf("first child not placed on submit: count=%d submitted=%s", parent.ChildCount, parent.Submitted)
+	}
+
+	for i := 1; i <= 3; i++ {
+		executor.Tick(ctx, start.Add(time.Duration(i)*10*time.Second))
+	}
+
+	final, err := executor.Get(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if final.Status != model.ParentStatusCompleted {
+		t.Errorf("parent status: got %s, want COMPLETED", final.Status)
+	}
+	if !final.Filled.EQ(model.MustMoney("0.8")) || final.ChildCount != 4 {
+		t.Errorf("unexpected progress: filled=%s children=%d", final.Filled, final.ChildCount)
+	}
+
+	children, _ := repo.ListChildren(ctx, parent.ID)
+	if len(children) != 4 {
+		t.Fatalf("expected 4 persisted children, got %d", len(children))
+	}
+	for i, child := range children {
+		if child.Seq != i+1 {
+			t.Errorf("child %d has seq %d", i, child.Seq)
+		}
+		saved, err := orderRepo.GetOrder(ctx, child.ClientOrderID)
+		if err != nil {
+			t.Fatalf("child order not saved by OMS: %v", err)
+		}
+		if !saved.ReduceOnly || saved.Side != model.OrderSideSell {
+			t.Errorf("unexpected child order: side=%s reduceOnly=%v", saved.Side, saved.ReduceOnly)
+		}
+	}
+	if len(executor.ListActive()) != 0 {
+		t.Error("completed parent should leave the schedule")
+	}
+}
+
+func TestExecutor_CancelAndAmend(t *testing.T) {
+	ctx := context.Background()
+	executor, _, _ := newTestExecutor(t, "twap-amend-account")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	parent, err := executor.Submit(ctx, &SubmitRequest{
+		AccountID: "twap-amend-account",
+		Symbol:    "BTCUSDT",
+		Side:      model.OrderSideBuy,
+		Quantity:  model.MustMoney("0.04"),
+		Duration:  40 * time.Second,
+		Slices:    4,
+	}, start)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	if _, err := executor.Amend(ctx, parent.ID, &AmendRequest{Quantity: model.MustMoney("0.005")}, start); !errors.Is(err, ErrInvalidParent) {
+		t.Errorf("amend below submitted should fail, got %v", err)
+	}
+
+	amended, err := executor.Amend(ctx, parent.ID, &AmendRequest{Quantity: model.MustMoney("0.02")}, start)
+	if err != nil {
+		t.Fatalf("Amend failed: %v", err)
+	}
+	if !amended.Remaining().EQ(model.MustMoney("0.01")) {
+		t.Errorf("remaining after amend: got %s, want 0.01", amended.Remaining())
+	}
+
+	if err := executor.Cancel(ctx, parent.ID, start.Add(time.Second)); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+
+	// 撤销后不再调度
+	executor.Tick(ctx, start.Add(30*time.Second))
+	cancelled, _ := executor.Get(ctx, parent.ID)
+	if cancelled.Status != model.ParentStatusCancelled || cancelled.ChildCount != 1 {
+		t.Errorf("unexpected cancelled parent: status=%s children=%d", cancelled.Status, cancelled.ChildCount)
+	}
+
+	if err := executor.Cancel(ctx, parent.ID, start); !errors.Is(err, ErrParentNotActive) {
+		t.Errorf("cancelling finished parent: got %v, want ErrParentNotActive", err)
+	}
+}
+
+func TestExecutor_Restore(t *testing.T) {
+	ctx := context.Background()
+	executor, repo, _ := newTestExecutor(t, "twap-restore-account")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	parent, err := executor.Submit(ctx, &SubmitRequest{
+		AccountID:  "twap-restore-account",
+		Symbol:     "BTCUSDT",
+		Side:       model.OrderSideSell,
+		Quantity:   model.MustMoney("0.4"),
+		Duration:   20 * time.Second,
+		Slices:     2,
+		ReduceOnly: true,
+		Priority:   oms.PriorityHigh,
+	}, start)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// 模拟重启：新执行器从同一仓储恢复
+	restarted, _, _ := newTestExecutor(t, "twap-restore-account")
+	restarted.repo = repo
+	if err := restarted.Restore(ctx); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restarted.Tick(ctx, start.Add(10*time.Second))
+
+	final, _ := restarted.Get(ctx, parent.ID)
+	if final.Status != model.ParentStatusCompleted || !final.Submitted.EQ(model.MustMoney("0.4")) {
+		t.Errorf("restored parent not completed: status=%s submitted=%s", final.Status, final.Submitted)
+	}
+}
+
+func TestExecutor_CancelAll(t *testing.T) {
+	ctx := context.Background()
+	executor, _, _ := newTestExecutor(t, "twap-cancel-all-account")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		_, err := executor.Submit(ctx, &SubmitRequest{
+			AccountID: "twap-cancel-all-account",
+			Symbol:    "BTCUSDT",
+			Side:      model.OrderSideBuy,
+			Quantity:  model.MustMoney("0.02"),
+			Duration:  40 * time.Second,
+			Slices:    4,
+		}, start)
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+
+	cancelled, err := executor.CancelAll(ctx, start.Add(time.Second))
+	if err != nil {
+		t.Fatalf("CancelAll failed: %v", err)
+	}
+	if cancelled != 2 || len(executor.ListActive()) != 0 {
+		t.Errorf("expected 2 parents cancelled, got %d (active %d)", cancelled, len(executor.ListActive()))
+	}
+}
+
+func TestExecutor_ChildOrderUpdates(t *testing.T) {
+	ctx := context.Background()
+	executor, repo, _, exchange := newTestExecutorWithExchange(t, "twap-child-account")
+	exchange.SetInstantFill(false)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	parent, err := executor.Submit(ctx, &SubmitRequest{
+		AccountID:  "twap-child-account",
+		Symbol:     "BTCUSDT",
+		Side:       model.OrderSideSell,
+		Quantity:   model.MustMoney("0.4"),
+		Duration:   20 * time.Second,
+		Slices:     2,
+		ReduceOnly: true,
+		Priority:   oms.PriorityHigh,
+	}, start)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if !parent.Filled.IsZero() || !parent.Submitted.EQ(model.MustMoney("0.2")) {
+		t.Fatalf("unfilled child counted: filled=%s submitted=%s", parent.Filled, parent.Submitted)
+	}
+
+	children, _ := repo.ListChildren(ctx, parent.ID)
+	first := children[0].ClientOrderID
+
+	// 部分成交后撤单：成交计入母单，未成交部分退回剩余数量
+	executor.HandleOrderUpdate(ctx, &model.Order{ClientOrderID: first, Status: model.OrderStatusPartialFilled, Filled: model.MustMoney("0.05")})
+	executor.HandleOrderUpdate(ctx, &model.Order{ClientOrderID: first, Status: model.OrderStatusCancelled, Filled: model.MustMoney("0.05")})
+
+	got, _ := executor.Get(ctx, parent.ID)
+	if !got.Filled.EQ(model.MustMoney("0.05")) || !got.Remaining().EQ(model.MustMoney("0.35")) {
+		t.Errorf("after cancel: filled=%s remaining=%s, want 0.05, 0.35", got.Filled, got.Remaining())
+	}
+	children, _ = repo.ListChildren(ctx, parent.ID)
+	if children[0].Status != model.OrderStatusCancelled || !children[0].Filled.EQ(model.MustMoney("0.05")) {
+		t.Errorf("child not updated: status=%s filled=%s", children[0].Status, children[0].Filled)
+	}
+
+	// 结束时间到达后下发全部剩余；全部下发但未成交时母单仍在执行
+	executor.Tick(ctx, start.Add(20*time.Second))
+	got, _ = executor.Get(ctx, parent.ID)
+	if got.Status != model.ParentStatusActive || !got.Remaining().IsZero() {
+		t.Fatalf("parent completed before fills: status=%s remaining=%s", got.Status, got.Remaining())
+	}
+
+	// 过期回报不回退成交数量；子单成交后母单按成交数量完成
+	children, _ = repo.ListChildren(ctx, parent.ID)
+	second := children[1].ClientOrderID
+	executor.HandleOrderUpdate(ctx, &model.Order{ClientOrderID: second, Status: model.OrderStatusPartialFilled, Filled: model.MustMoney("0.2")})
+	executor.HandleOrderUpdate(ctx, &model.Order{ClientOrderID: second, Status: model.OrderStatusSubmitted, Filled: model.Zero()})
+	executor.HandleOrderUpdate(ctx, &model.Order{ClientOrderID: second, Status: model.OrderStatusFilled, Filled: model.MustMoney("0.35")})
+
+	final, _ := executor.Get(ctx, parent.ID)
+	if final.Status != model.ParentStatusCompleted || !final.Filled.EQ(model.MustMoney("0.4")) {
+		t.Errorf("parent not completed on fills: status=%s filled=%s", final.Status, final.Filled)
+	}
+}
+
+type fixedDepth model.Money
+
+func (d fixedDepth) Depth(ctx context.Context, symbol string, side model.OrderSide) (model.Money, error) {
+	return model.Money(d), nil
+}
+
+func TestPositionExitAdapter_SplitsLargeExits(t *testing.T) {
+	ctx := context.Background()
+	executor, _, orderRepo := newTestExecutor(t, "split-exit-account")
+	executor.SetDepthSource(fixedDepth(model.MustMoney("1")))
+
+	exits := oms.NewPositionExitAdapter(executor.manager)
+	exits.SetExitSplitter(executor)
+	exit := func(qty string) *position.ExitRequest {
+		return &position.ExitRequest{
+			Stop: model.StopLevel{
+				AccountID: "split-exit-account",
+				Symbol:    "BTCUSDT",
+				Side:      model.OrderSideBuy,
+				Quantity:  model.MustMoney(qty),
+			},
+			MarkPrice: model.MustMoney("50000"),
+		}
+	}
+
+	// 未超过深度 × 0.2：直接市价平仓
+	if err := exits.ExecuteExit(ctx, exit("0.1")); err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+	if n := len(executor.ListActive()); n != 0 {
+		t.Fatalf("small exit should not be split, %d parents active", n)
+	}
+
+	// 超过阈值：提交只减仓母单拆分执行
+	if err := exits.ExecuteExit(ctx, exit("0.5")); err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+	active := executor.ListActive()
+	if len(active) != 1 || !active[0].ReduceOnly || active[0].Side != model.OrderSideSell || !active[0].Quantity.EQ(model.MustMoney("0.5")) {
+		t.Fatalf("large exit not split into a reduce-only parent: %+v", active)
+	}
+
+	orders, _ := orderRepo.ListOrdersBySymbol(ctx, "BTCUSDT", 100)
+	var direct int
+	for _, o := range orders {
+		if o.Quantity.EQ(model.MustMoney("0.5")) {
+			direct++
+		}
+	}
+	if direct != 0 {
+		t.Error("large exit should not be placed as a single market order")
+	}
+}
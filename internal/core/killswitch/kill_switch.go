@@ -0,0 +1,358 @@
+package killswitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+var (
+	// ErrNotHalted 当前未处于全局交易暂停
+	ErrNotHalted = errors.New("killswitch: trading is not halted")
+
+	// ErrReleaseNotVerified 解除暂停需要提级认证
+	ErrReleaseNotVerified = errors.New("killswitch: releasing halt requires step-up verification")
+
+	// ErrSameApprover 双人确认时解除需由另一位管理员确认
+	ErrSameApprover = errors.New("killswitch: release must be approved by a different admin")
+)
+
+// HaltGuard 下单拦截开关（由 risk.Manager 实现，暂停期间拒绝非只减仓订单）
+type HaltGuard interface {
+	SetHalt(halt *model.TradingHalt)
+}
+
+// OrderCanceller 撤销全部活跃订单（由 oms.Manager 实现）
+type OrderCanceller interface {
+	CancelAllOrders(ctx context.Context) (int, error)
+}
+
+// AlgoCanceller 撤销全部执行中的母单（由 execution.Executor 实现）
+type AlgoCanceller interface {
+	CancelAll(ctx context.Context, now time.Time) (int, error)
+}
+
+// PositionFlattener 市价平掉全部持仓（由 oms.PositionFlattenAdapter 按持仓账本实现，经 OMS 以只减仓方式下单）
+type PositionFlattener interface {
+	FlattenAll(ctx context.Context, now time.Time) (int, error)
+}
+
+// Config Kill Switch 配置
+type Config struct {
+	RequireSecondApproval bool // 解除暂停需第二位管理员确认
+}
+
+// TriggerRequest 触发请求
+type TriggerRequest struct {
+	UserID  int64  // 触发人
+	Reason  string // 触发原因
+	Flatten bool   // 是否市价平掉全部持仓
+}
+
+// Result 触发结果（处置失败不中断，逐项记录到 Errors）
+type Result struct {
+	Halt               *model.TradingHalt
+	CancelledOrders    int
+	CancelledAlgos     int
+	FlattenedPositions int
+	Errors             []string
+}
+
+// ReleaseRequest 解除请求
+type ReleaseRequest struct {
+	UserID   int64 // 操作人
+	Verified bool  // 是否通过提级认证
+}
+
+// KillSwitch 人工紧急停止
+// 触发：持久化全局暂停 -> 风控拦截非只减仓订单 -> 停止交易循环 -> 撤销母单与活跃订单 -> 可选平仓 -> 通知
+// 解除：需提级认证；启用双人确认时由发起人申请、另一位管理员确认后生效（不会自动重启交易循环）
+type KillSwitch struct {
+	mu     sync.Mutex // 串行化触发与解除
+	repo   port.TradingHaltRepo
+	guard  HaltGuard
+	config Config
+
+	// 处置组件（可选，未设置时跳过）
+	orders    OrderCanceller
+	algos     AlgoCanceller
+	positions PositionFlattener
+
+	notifiers []Notifier
+	onHalt    func()          // 暂停生效后的回调（停止交易循环）
+	onError   func(err error) // 错误回调（通知失败、暂停持久化失败，可选）
+
+	// local 持久化失败的暂停，仅在本进程内生效
+	// 同步不会因持久化中没有暂停而清除，每次同步重试持久化，直至成功或显式解除
+	local *model.TradingHalt
+}
+
+// NewKillSwitch 创建 Kill Switch
+func NewKillSwitch(repo port.TradingHaltRepo, guard HaltGuard, config Config) *KillSwitch {
+	return &KillSwitch{
+		repo:   repo,
+		guard:  guard,
+		config: config,
+	}
+}
+
+// SetOrderCanceller 设置活跃订单撤销组件
+func (k *KillSwitch) SetOrderCanceller(orders OrderCanceller) {
+	k.orders = orders
+}
+
+// SetAlgoCanceller 设置算法母单撤销组件
+func (k *KillSwitch) SetAlgoCanceller(algos AlgoCanceller) {
+	k.algos = algos
+}
+
+// SetPositionFlattener 设置平仓组件
+func (k *KillSwitch) SetPositionFlattener(positions PositionFlattener) {
+	k.positions = positions
+}
+
+// AddNotifier 添加通知渠道（触发、申请解除、解除时通知全部渠道）
+func (k *KillSwitch) AddNotifier(notifier Notifier) {
+	k.notifiers = append(k.notifiers, notifier)
+}
+
+// OnHalt 设置暂停生效后的回调（用于停止交易循环）
+func (k *KillSwitch) OnHalt(fn func()) {
+	k.onHalt = fn
+}
+
+// OnError 设置错误回调（通知失败、暂停持久化失败，用于日志告警）
+func (k *KillSwitch) OnError(fn func(err error)) {
+	k.onError = fn
+}
+
+// Restore 从持久化恢复暂停状态（启动时调用，暂停期间重启后仍拦截下单）
+func (k *KillSwitch) Restore(ctx context.Context) error {
+	_, err := k.Status(ctx)
+	return err
+}
+
+// Start 定时从持久化同步暂停状态（多进程部署时其他进程的触发与解除在 interval 内生效；同时重试持久化本地暂停）
+func (k *KillSwitch) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = k.Restore(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Status 当前生效的暂停（未暂停时返回 nil），并同步到风控拦截开关
+// 存在未持久化的本地暂停时先重试持久化，仍失败时以本地暂停为准
+func (k *KillSwitch) Status(ctx context.Context) (*model.TradingHalt, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.local != nil {
+		k.persistLocal(ctx, time.Now())
+	}
+
+	halt, err := k.repo.ActiveHalt(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load trading halt failed: %w", err)
+	}
+	if halt == nil && k.local != nil {
+		halt = k.local
+	}
+	k.guard.SetHalt(halt)
+	return halt, nil
+}
+
+// Trigger 触发全局交易暂停
+// 先持久化并拦截下单，再撤单与平仓，避免处置期间策略继续开仓
+// 持久化失败时仍在本进程内暂停（同步不会清除），经 OnError 与通知渠道告警，并在每次同步时重试持久化
+// 已处于暂停时沿用原记录，重新执行撤单与平仓（用于重试失败的处置）
+func (k *KillSwitch) Trigger(ctx context.Context, req TriggerRequest, now time.Time) *Result {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	result := &Result{}
+	fail := func(step string, err error) {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", step, err))
+	}
+
+	// 1. 持久化暂停并立即拦截下单
+	halt, err := k.repo.ActiveHalt(ctx)
+	if err != nil {
+		fail("load halt", err)
+	}
+	if halt == nil && k.local != nil {
+		halt = k.local
+	}
+	created := halt == nil
+	if created {
+		halt = &model.TradingHalt{
+			Status:   model.TradingHaltStatusActive,
+			Reason:   req.Reason,
+			HaltedBy: req.UserID,
+			HaltedAt: now,
+		}
+		if err := k.repo.SaveHalt(ctx, halt); err != nil {
+			// 其他进程同时触发时沿用其记录
+			if existing, _ := k.repo.ActiveHalt(ctx); existing != nil {
+				halt, created = existing, false
+			} else {
+				fail("persist halt", err)
+				k.local = halt
+				k.reportError(fmt.Errorf("persist trading halt failed, halt held in this process only: %w", err))
+			}
+		}
+	}
+	halt.Flattened = halt.Flattened || req.Flatten
+	k.guard.SetHalt(halt)
+	result.Halt = halt
+
+	// 2. 停止交易循环
+	if k.onHalt != nil {
+		k.onHalt()
+	}
+
+	// 3. 撤销母单（避免继续拆单）与活跃订单
+	if k.algos != nil {
+		n, err := k.algos.CancelAll(ctx, now)
+		result.CancelledAlgos = n
+		if err != nil {
+			fail("cancel algo orders", err)
+		}
+	}
+	if k.orders != nil {
+		n, err := k.orders.CancelAllOrders(ctx)
+		result.CancelledOrders = n
+		if err != nil {
+			fail("cancel orders", err)
+		}
+	}
+
+	// 4. 市价平仓（只减仓订单在暂停期间放行）
+	if req.Flatten && k.positions != nil {
+		n, err := k.positions.FlattenAll(ctx, now)
+		result.FlattenedPositions = n
+		if err != nil {
+			fail("flatten positions", err)
+		}
+	}
+
+	// 5. 记录处置结果（重复触发时累加）
+	halt.CancelledOrders += result.CancelledOrders
+	halt.FlattenedPositions += result.FlattenedPositions
+	if halt.ID != 0 {
+		if err := k.repo.UpdateHalt(ctx, halt); err != nil {
+			fail("update halt", err)
+		}
+	}
+
+	event := EventTriggered
+	if !created {
+		event = EventRetriggered
+	}
+	k.notify(ctx, &Event{Type: event, Halt: *halt, Result: result, UserID: req.UserID, At: now})
+	return result
+}
+
+// Release 解除全局交易暂停，返回更新后的暂停记录
+// 启用双人确认时，首次调用进入 RELEASE_PENDING，由另一位管理员再次调用后解除
+func (k *KillSwitch) Release(ctx context.Context, req ReleaseRequest, now time.Time) (*model.TradingHalt, error) {
+	if !req.Verified {
+		return nil, ErrReleaseNotVerified
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	halt, err := k.repo.ActiveHalt(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load trading halt failed: %w", err)
+	}
+	if halt == nil {
+		halt = k.local
+	}
+	if halt == nil {
+		k.guard.SetHalt(nil)
+		return nil, ErrNotHalted
+	}
+
+	event := EventReleased
+	switch {
+	case !k.config.RequireSecondApproval:
+		halt.Status = model.TradingHaltStatusReleased
+		halt.ReleaseRequestedBy = req.UserID
+		halt.ReleaseRequestedAt = now
+		halt.ReleasedBy = req.UserID
+		halt.ReleasedAt = now
+
+	case halt.Status == model.TradingHaltStatusActive:
+		halt.Status = model.TradingHaltStatusReleasePending
+		halt.ReleaseRequestedBy = req.UserID
+		halt.ReleaseRequestedAt = now
+		event = EventReleaseRequested
+
+	case halt.ReleaseRequestedBy == req.UserID:
+		return nil, ErrSameApprover
+
+	default:
+		halt.Status = model.TradingHaltStatusReleased
+		halt.ReleasedBy = req.UserID
+		halt.ReleasedAt = now
+	}
+
+	if halt != k.local {
+		if err := k.repo.UpdateHalt(ctx, halt); err != nil {
+			return nil, fmt.Errorf("update trading halt failed: %w", err)
+		}
+	} else if !halt.IsActive() {
+		k.local = nil
+	}
+	k.guard.SetHalt(halt)
+
+	k.notify(ctx, &Event{Type: event, Halt: *halt, UserID: req.UserID, At: now})
+	return halt, nil
+}
+
+// persistLocal 重试持久化本地暂停（调用方持有锁），成功或其他进程已持久化暂停时清除本地暂停
+func (k *KillSwitch) persistLocal(ctx context.Context, now time.Time) {
+	if existing, err := k.repo.ActiveHalt(ctx); err == nil && existing != nil {
+		k.local = nil
+		return
+	}
+	if err := k.repo.SaveHalt(ctx, k.local); err != nil {
+		k.reportError(fmt.Errorf("persist trading halt failed, halt held in this process only: %w", err))
+		return
+	}
+
+	halt := *k.local
+	k.local = nil
+	k.notify(ctx, &Event{Type: EventPersisted, Halt: halt, UserID: halt.HaltedBy, At: now})
+}
+
+// reportError 回调错误（未设置 OnError 时忽略）
+func (k *KillSwitch) reportError(err error) {
+	if k.onError != nil {
+		k.onError(err)
+	}
+}
+
+// notify 通知全部渠道（单个渠道失败不影响其他渠道）
+func (k *KillSwitch) notify(ctx context.Context, event *Event) {
+	for _, notifier := range k.notifiers {
+		if err := notifier.Notify(ctx, event); err != nil {
+			k.reportError(fmt.Errorf("notify %s failed: %w", event.Type, err))
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package killswitch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+	"github.com/iluyuns/alpha-trade/internal/infra/tradinghalt"
+)
+
+type mockCanceller struct {
+	calls int
+	count int
+	err   error
+}
+
+func (c *mockCanceller) CancelAllOrders(ctx context.Context) (int, error) {
+	c.calls++
+	return c.count, c.err
+}
+
+func (c *mockCanceller) CancelAll(ctx context.Context, now time.Time) (int, error) {
+	c.calls++
+	return c.count, c.err
+}
+
+func (c *mockCanceller) FlattenAll(ctx context.Context, now time.Time) (int, error) {
+	c.calls++
+	return c.count, c.err
+}
+
+func newOpeningOrder() *risklogic.OrderContext {
+	return &risklogic.OrderContext{
+		AccountID:    "acc-1",
+		Symbol:       "BTCUSDT",
+		MarketType:   model.MarketTypeSpot,
+		Side:         model.OrderSideBuy,
+		Quantity:     model.MustMoney("0.01"),
+		Price:        model.MustMoney("50000"),
+		CurrentPrice: model.MustMoney("50000"),
+	}
+}
+
+func newTestKillSwitch(repo *tradinghalt.MemoryRepo, config Config) (*KillSwitch, *risklogic.Manager) {
+	riskMgr := risklogic.NewManager(riskrepo.NewMemoryRiskRepo(), risklogic.RiskConfig{
+		MaxSinglePositionPercent: 1,
+		MaxTotalExposurePercent:  1,
+	})
+	return NewKillSwitch(repo, riskMgr, config), riskMgr
+}
+
+func TestKillSwitch_Trigger(t *testing.T) {
+	ctx := context.Background()
+	repo := tradinghalt.NewMemoryRepo()
+	ks, riskMgr := newTestKillSwitch(repo, Config{})
+
+	orders := &mockCanceller{count: 3}
+	algos := &mockCanceller{count: 1}
+	positions := &mockCanceller{count: 2, err: errors.New("gateway down")}
+	ks.SetOrderCanceller(orders)
+	ks.SetAlgoCanceller(algos)
+	ks.SetPositionFlattener(positions)
+
+	var stopped bool
+	ks.OnHalt(func() { stopped = true })
+	var events []*Event
+	ks.AddNotifier(NotifierFunc(func(ctx context.Context, event *Event) error {
+		events = append(events, event)
+		return nil
+	}))
+
+	now := time.Now()
+	result := ks.Trigger(ctx, TriggerRequest{UserID: 1, Reason: "exchange incident", Flatten: true}, now)
+
+	if result.CancelledOrders != 3 || result.CancelledAlgos != 1 || result.FlattenedPositions != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	// 平仓失败记录到结果，不中断
+	if len(result.Errors) != 1 {
+		t.Errorf("expected 1 error, got %v", result.Errors)
+	}
+	if !stopped {
+		t.Error("expected trading loop stopped")
+	}
+	if len(events) != 1 || events[0].Type != EventTriggered {
+		t.Fatalf("expected triggered event, got %+v", events)
+	}
+
+	// 持久化并拦截开仓订单
+	halt, _ := repo.ActiveHalt(ctx)
+	if halt == nil || halt.Reason != "exchange incident" || halt.CancelledOrders != 3 || !halt.Flattened {
+		t.Fatalf("unexpected persisted halt: %+v", halt)
+	}
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); !decision.IsBlocked() {
+		t.Error("expected opening order blocked after trigger")
+	}
+
+	// 再次触发沿用原记录，重新撤单
+	result = ks.Trigger(ctx, TriggerRequest{UserID: 2, Reason: "retry"}, now.Add(time.Minute))
+	if result.Halt.ID != halt.ID || orders.calls != 2 || positions.calls != 1 {
+		t.Errorf("expected retrigger on same halt without flatten, got halt=%d orders=%d positions=%d",
+			result.Halt.ID, orders.calls, positions.calls)
+	}
+	if events[1].Type != EventRetriggered {
+		t.Errorf("expected retriggered event, got %s", events[1].Type)
+	}
+	if halt, _ = repo.ActiveHalt(ctx); halt.CancelledOrders != 6 || halt.Reason != "exchange incident" {
+		t.Errorf("unexpected halt after retrigger: %+v", halt)
+	}
+}
+
+func TestKillSwitch_RestoreAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	repo := tradinghalt.NewMemoryRepo()
+
+	ks, _ := newTestKillSwitch(repo, Config{})
+	ks.Trigger(ctx, TriggerRequest{UserID: 1, Reason: "manual"}, time.Now())
+
+	// 新进程从持久化恢复暂停
+	restarted, riskMgr := newTestKillSwitch(repo, Config{})
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); decision.IsBlocked() {
+		t.Fatal("expected allowed before restore")
+	}
+	if err := restarted.Restore(ctx); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); !decision.IsBlocked() {
+		t.Error("expected opening order blocked after restore")
+	}
+}
+
+// flakyHaltRepo 写入失败的暂停仓储（模拟数据库不可用）
+type flakyHaltRepo struct {
+	*tradinghalt.MemoryRepo
+	saveErr error
+}
+
+func (r *flakyHaltRepo) SaveHalt(ctx context.Context, halt *model.TradingHalt) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	return r.MemoryRepo.SaveHalt(ctx, halt)
+}
+
+func TestKillSwitch_UnpersistedHaltSurvivesSync(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyHaltRepo{MemoryRepo: tradinghalt.NewMemoryRepo(), saveErr: errors.New("db down")}
+	riskMgr := risklogic.NewManager(riskrepo.NewMemoryRiskRepo(), risklogic.RiskConfig{
+		MaxSinglePositionPercent: 1,
+		MaxTotalExposurePercent:  1,
+	})
+	ks := NewKillSwitch(repo, riskMgr, Config{})
+
+	var reported []error
+	ks.OnError(func(err error) { reported = append(reported, err) })
+	var events []*Event
+	ks.AddNotifier(NotifierFunc(func(ctx context.Context, event *Event) error {
+		events = append(events, event)
+		return nil
+	}))
+
+	result := ks.Trigger(ctx, TriggerRequest{UserID: 1, Reason: "manual"}, time.Now())
+	if len(result.Errors) == 0 || len(reported) != 1 {
+		t.Fatalf("persist failure not reported: errors=%v reported=%v", result.Errors, reported)
+	}
+	if len(events) != 1 || !strings.Contains(events[0].Subject(), "NOT PERSISTED") {
+		t.Fatalf("notification should flag the unpersisted halt, got %v", events)
+	}
+
+	// 同步时持久化中没有暂停，本地暂停仍然生效，并继续重试持久化
+	halt, err := ks.Status(ctx)
+	if err != nil || halt == nil {
+		t.Fatalf("Status = %v, %v; want local halt", halt, err)
+	}
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); !decision.IsBlocked() {
+		t.Fatal("sync cleared the local halt")
+	}
+	if len(reported) != 2 {
+		t.Errorf("retry failure not reported, got %d errors", len(reported))
+	}
+
+	// 数据库恢复后下一次同步完成持久化并通知
+	repo.saveErr = nil
+	if err := ks.Restore(ctx); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	persisted, _ := repo.ActiveHalt(ctx)
+	if persisted == nil || persisted.Reason != "manual" {
+		t.Fatalf("halt not persisted on retry: %+v", persisted)
+	}
+	if last := events[len(events)-1]; last.Type != EventPersisted {
+		t.Errorf("last event = %s, want %s", last.Type, EventPersisted)
+	}
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); !decision.IsBlocked() {
+		t.Error("expected opening order blocked after persisting")
+	}
+}
+
+func TestKillSwitch_ReleaseUnpersistedHalt(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyHaltRepo{MemoryRepo: tradinghalt.NewMemoryRepo(), saveErr: errors.New("db down")}
+	riskMgr := risklogic.NewManager(riskrepo.NewMemoryRiskRepo(), risklogic.RiskConfig{
+		MaxSinglePositionPercent: 1,
+		MaxTotalExposurePercent:  1,
+	})
+	ks := NewKillSwitch(repo, riskMgr, Config{})
+	ks.Trigger(ctx, TriggerRequest{UserID: 1, Reason: "manual"}, time.Now())
+
+	if _, err := ks.Release(ctx, ReleaseRequest{UserID: 2, Verified: true}, time.Now()); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if halt, _ := ks.Status(ctx); halt != nil {
+		t.Errorf("released local halt still active: %+v", halt)
+	}
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); decision.IsBlocked() {
+		t.Error("expected opening order allowed after release")
+	}
+}
+
+func TestKillSwitch_ReleaseRequiresSecondApproval(t *testing.T) {
+	ctx := context.Background()
+	repo := tradinghalt.NewMemoryRepo()
+	ks, riskMgr := newTestKillSwitch(repo, Config{RequireSecondApproval: true})
+
+	var events []EventType
+	ks.AddNotifier(NotifierFunc(func(ctx context.Context, event *Event) error {
+		events = append(events, event.Type)
+		return nil
+	}))
+
+	now := time.Now()
+	if _, err := ks.Release(ctx, ReleaseRequest{UserID: 1, Verified: true}, now); !errors.Is(err, ErrNotHalted) {
+		t.Fatalf("expected ErrNotHalted, got %v", err)
+	}
+
+	ks.Trigger(ctx, TriggerRequest{UserID: 1, Reason: "manual"}, now)
+
+	if _, err := ks.Release(ctx, ReleaseRequest{UserID: 1}, now); !errors.Is(err, ErrReleaseNotVerified) {
+		t.Fatalf("expected ErrReleaseNotVerified, got %v", err)
+	}
+
+	// 第一位管理员申请解除，仍处于暂停
+	halt, err := ks.Release(ctx, ReleaseRequest{UserID: 1, Verified: true}, now)
+	if err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if halt.Status != model.TradingHaltStatusReleasePending || halt.ReleaseRequestedBy != 1 {
+		t.Fatalf("unexpected pending halt: %+v", halt)
+	}
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); !decision.IsBlocked() {
+		t.Error("expected blocked while release is pending")
+	}
+
+	// 发起人不能自行确认
+	if _, err := ks.Release(ctx, ReleaseRequest{UserID: 1, Verified: true}, now); !errors.Is(err, ErrSameApprover) {
+		t.Fatalf("expected ErrSameApprover, got %v", err)
+	}
+
+	// 第二位管理员确认后解除
+	halt, err = ks.Release(ctx, ReleaseRequest{UserID: 2, Verified: true}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if halt.Status != model.TradingHaltStatusReleased || halt.ReleasedBy != 2 {
+		t.Fatalf("unexpected released halt: %+v", halt)
+	}
+	if decision, _ := riskMgr.CheckPreTrade(ctx, newOpeningOrder()); decision.IsBlocked() {
+		t.Errorf("expected allowed after release, got blocked: %s", decision.Reason)
+	}
+	if active, _ := repo.ActiveHalt(ctx); active != nil {
+		t.Errorf("expected no active halt, got %+v", active)
+	}
+
+	want := []EventType{EventTriggered, EventReleaseRequested, EventReleased}
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("events[%d] = %s, want %s", i, events[i], want[i])
+		}
+	}
+}
+
+func TestKillSwitch_ReleaseSingleApproval(t *testing.T) {
+	ctx := context.Background()
+	ks, riskMgr := newTestKillSwitch(tradinghalt.NewMemoryRepo(), Config{})
+
+	var notifyErr error
+	ks.AddNotifier(NotifierFunc(func(ctx context.Context, event *Event) error {
+		return errors.New("smtp down")
+	}))
+	ks.OnError(func(err error) { notifyErr = err })
+
+	now := time.Now()
+	ks.Trigger(ctx, TriggerRequest{UserID: 1, Reason: "manual"}, now)
+	if notifyErr == nil {
+		t.Error("expected notify error reported")
+	}
+
+	halt, err := ks.Release(ctx, ReleaseRequest{UserID: 1, Verified: true}, now)
+	if err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if halt.Status != model.TradingHaltStatusReleased || halt.ReleasedBy != 1 {
+		t.Fatalf("unexpected released halt: %+v", halt)
+	}
+	if riskMgr.Halt() != nil {
+		t.Error("expected risk manager halt cleared")
+	}
+}
@@ -0,0 +1,102 @@
+package killswitch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/pkg/email"
+)
+
+// EventType Kill Switch 事件类型
+type EventType string
+
+const (
+	EventTriggered        EventType = "HALT_TRIGGERED"    // 触发暂停
+	EventRetriggered      EventType = "HALT_RETRIGGERED"  // 暂停期间再次触发（重试撤单与平仓）
+	EventReleaseRequested EventType = "RELEASE_REQUESTED" // 申请解除，等待第二位管理员确认
+	EventReleased         EventType = "HALT_RELEASED"     // 已解除
+	EventPersisted        EventType = "HALT_PERSISTED"    // 持久化失败的暂停经重试后已持久化
+)
+
+// Event Kill Switch 事件（通知内容）
+type Event struct {
+	Type   EventType
+	Halt   model.TradingHalt
+	Result *Result // 触发事件的处置结果（解除事件为 nil）
+	UserID int64   // 操作人
+	At     time.Time
+}
+
+// Subject 通知标题
+func (e *Event) Subject() string {
+	switch e.Type {
+	case EventTriggered, EventRetriggered:
+		if e.Halt.ID == 0 {
+			return fmt.Sprintf("[alpha-trade] KILL SWITCH: trading halted (%s), NOT PERSISTED: halt holds in this process only", e.Halt.Reason)
+		}
+		return fmt.Sprintf("[alpha-trade] KILL SWITCH: trading halted (%s)", e.Halt.Reason)
+	case EventPersisted:
+		return "[alpha-trade] KILL SWITCH: trading halt persisted after retry"
+	case EventReleaseRequested:
+		return "[alpha-trade] Trading halt release requested, awaiting second approval"
+	default:
+		return "[alpha-trade] Trading halt released"
+	}
+}
+
+// Body 通知正文
+func (e *Event) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", e.Type)
+	fmt.Fprintf(&b, "time: %s\n", e.At.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "operator: %d\n", e.UserID)
+	fmt.Fprintf(&b, "halt: #%d %s (reason: %s, halted by %d at %s)\n",
+		e.Halt.ID, e.Halt.Status, e.Halt.Reason, e.Halt.HaltedBy, e.Halt.HaltedAt.UTC().Format(time.RFC3339))
+
+	if r := e.Result; r != nil {
+		fmt.Fprintf(&b, "cancelled orders: %d, cancelled algo orders: %d, flattened positions: %d\n",
+			r.CancelledOrders, r.CancelledAlgos, r.FlattenedPositions)
+		for _, msg := range r.Errors {
+			fmt.Fprintf(&b, "error: %s\n", msg)
+		}
+	}
+	return b.String()
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Notify(ctx context.Context, event *Event) error
+}
+
+// NotifierFunc 函数适配为通知渠道
+type NotifierFunc func(ctx context.Context, event *Event) error
+
+// Notify 实现 Notifier
+func (f NotifierFunc) Notify(ctx context.Context, event *Event) error {
+	return f(ctx, event)
+}
+
+// EmailNotifier 邮件通知渠道
+type EmailNotifier struct {
+	service    email.EmailService
+	sender     string
+	recipients []string
+}
+
+// NewEmailNotifier 创建邮件通知渠道
+func NewEmailNotifier(service email.EmailService, sender string, recipients []string) *EmailNotifier {
+	return &EmailNotifier{
+		service:    service,
+		sender:     sender,
+		recipients: recipients,
+	}
+}
+
+// Notify 实现 Notifier
+func (n *EmailNotifier) Notify(ctx context.Context, event *Event) error {
+	_, err := n.service.SendEmail(ctx, n.sender, n.recipients, event.Subject(), event.Body())
+	return err
+}
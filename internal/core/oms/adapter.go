@@ -2,7 +2,10 @@ package oms
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/iluyuns/alpha-trade/internal/core/position"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
 	"github.com/iluyuns/alpha-trade/internal/strategy"
 )
@@ -32,8 +35,186 @@ func (a *StrategyOMSAdapter) PlaceOrder(ctx context.Context, req *strategy.Place
 		Quantity:      req.Quantity,
 		CurrentPrice:  req.CurrentPrice,
 		AccountID:     req.AccountID,
+		StrategyID:    req.StrategyID,
 		ProtectPrice:  req.ProtectPrice,
+		ExpireAt:      req.ExpireAt,
+		MaxAge:        req.MaxAge,
+		ExpirePolicy:  req.ExpirePolicy,
 	}
 
 	return a.manager.PlaceOrder(ctx, omsReq)
 }
+
+// StrategyExpiryHandler 将挂单超时事件转发给策略引擎（通知下单策略）
+func StrategyExpiryHandler(engine *strategy.Engine) ExpiryHandler {
+	return func(ctx context.Context, event *ExpiryEvent) {
+		engine.HandleOrderExpired(ctx, event.Order, event.Replacement)
+	}
+}
+
+// ExitSplitter 大额平仓拆单执行（由 execution.Executor 实现）
+// 平仓数量超过盘口深度阈值时提交母单拆分执行并返回 true，否则返回 false 由调用方直接下单
+type ExitSplitter interface {
+	SplitExit(ctx context.Context, req *position.ExitRequest, now time.Time) (bool, error)
+}
+
+// PositionExitAdapter 平仓适配器，实现 position.ExitExecutor
+// 止损/止盈触发后以市价、只减仓、高优先级方式经 OMS 下单；设置拆单执行后，超过盘口深度阈值的平仓改为 TWAP 拆分
+type PositionExitAdapter struct {
+	manager  *Manager
+	splitter ExitSplitter // 可选
+}
+
+// NewPositionExitAdapter 创建平仓适配器
+func NewPositionExitAdapter(manager *Manager) *PositionExitAdapter {
+	return &PositionExitAdapter{
+		manager: manager,
+	}
+}
+
+// SetExitSplitter 设置大额平仓拆单执行（见 RISK_PROTOCOL 5.2 流动性枯竭）
+func (a *PositionExitAdapter) SetExitSplitter(splitter ExitSplitter) {
+	a.splitter = splitter
+}
+
+// ExecuteExit 实现 position.ExitExecutor
+func (a *PositionExitAdapter) ExecuteExit(ctx context.Context, req *position.ExitRequest) error {
+	if a.splitter != nil {
+		split, err := a.splitter.SplitExit(ctx, req, time.Now())
+		if err != nil || split {
+			return err
+		}
+	}
+
+	clientOrderID, err := model.NewClientOrderID(model.OrderIntentStop, req.Stop.StrategyID, req.Stop.Symbol)
+	if err != nil {
+		return err
+	}
+	_, err = a.manager.PlaceOrder(ctx, &PlaceOrderRequest{
+		ClientOrderID: clientOrderID,
+		Symbol:        req.Stop.Symbol,
+		Side:          req.Stop.ExitSide(),
+		Type:          model.OrderTypeMarket,
+		Price:         req.MarkPrice,
+		Quantity:      req.Stop.Quantity,
+		CurrentPrice:  req.MarkPrice,
+		AccountID:     req.Stop.AccountID,
+		StrategyID:    req.Stop.StrategyID,
+		ReduceOnly:    true,
+		Priority:      PriorityHigh,
+	})
+	return err
+}
+
+// PositionBook 持仓来源（由 position.Ledger 实现）
+type PositionBook interface {
+	Positions() []model.Position
+}
+
+// PositionFlattenAdapter Kill Switch 平仓适配器，实现 killswitch.PositionFlattener
+// 按持仓账本中的全部非零持仓以市价、只减仓、高优先级方式经 OMS 下单（不要求持仓登记止损）
+type PositionFlattenAdapter struct {
+	manager   *Manager
+	positions PositionBook
+	stops     *position.StopManager // 可选，平仓后移除对应止损，避免重复平仓
+}
+
+// NewPositionFlattenAdapter 创建 Kill Switch 平仓适配器
+func NewPositionFlattenAdapter(manager *Manager, positions PositionBook) *PositionFlattenAdapter {
+	return &PositionFlattenAdapter{
+		manager:   manager,
+		positions: positions,
+	}
+}
+
+// SetStopManager 设置止损管理器（平仓指令发出后移除同一策略与标的的止损）
+func (a *PositionFlattenAdapter) SetStopManager(stops *position.StopManager) {
+	a.stops = stops
+}
+
+// FlattenAll 实现 killswitch.PositionFlattener，返回发出平仓指令的持仓数
+// 按最新标记价格计价（未标记时使用持仓均价）；已有平仓订单在途（活跃，或已成交但账本尚未同步）的持仓不重复下单。
+// 单笔失败不中断，返回最后一个错误
+func (a *PositionFlattenAdapter) FlattenAll(ctx context.Context, now time.Time) (int, error) {
+	var (
+		flattened int
+		lastErr   error
+	)
+	for _, p := range a.positions.Positions() {
+		if p.IsFlat() {
+			continue
+		}
+		pending, err := a.exitPending(ctx, p)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if pending {
+			continue
+		}
+
+		side, price := model.OrderSideBuy, p.MarkPrice
+		if p.IsLong() {
+			side = model.OrderSideSell
+		}
+		if price.IsZero() {
+			price = p.AvgCost
+		}
+		if _, err := a.manager.PlaceOrder(ctx, &PlaceOrderRequest{
+			Symbol:       p.Symbol,
+			Side:         side,
+			Type:         model.OrderTypeMarket,
+			Price:        price,
+			Quantity:     p.Quantity.Abs(),
+			CurrentPrice: price,
+			AccountID:    p.AccountID,
+			StrategyID:   p.StrategyID,
+			Intent:       model.OrderIntentStop,
+			ReduceOnly:   true,
+			Priority:     PriorityHigh,
+		}); err != nil {
+			lastErr = fmt.Errorf("flatten %s/%s failed: %w", p.StrategyID, p.Symbol, err)
+			continue
+		}
+		flattened++
+
+		if err := a.removeStops(ctx, p); err != nil {
+			lastErr = err
+		}
+	}
+	return flattened, lastErr
+}
+
+// exitPending 持仓是否已有在途的只减仓订单
+func (a *PositionFlattenAdapter) exitPending(ctx context.Context, p model.Position) (bool, error) {
+	orders, err := a.manager.orderRepo.ListOrdersBySymbol(ctx, p.Symbol, 100)
+	if err != nil {
+		return false, fmt.Errorf("list orders of %s failed: %w", p.Symbol, err)
+	}
+	for _, order := range orders {
+		if !order.ReduceOnly || order.StrategyID != p.StrategyID {
+			continue
+		}
+		if order.IsActive() || order.IsFilled() && order.CreatedAt.After(p.UpdatedAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// removeStops 移除持仓对应的止损
+func (a *PositionFlattenAdapter) removeStops(ctx context.Context, p model.Position) error {
+	if a.stops == nil {
+		return nil
+	}
+	var lastErr error
+	for _, level := range a.stops.List() {
+		if level.StrategyID != p.StrategyID || level.Symbol != p.Symbol {
+			continue
+		}
+		if err := a.stops.Remove(ctx, level.PositionID); err != nil {
+			lastErr = fmt.Errorf("remove stop %s failed: %w", level.PositionID, err)
+		}
+	}
+	return lastErr
+}
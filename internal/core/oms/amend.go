@@ -0,0 +1,256 @@
+package oms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	riskmgr "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	"github.com/iluyuns/alpha-trade/internal/pkg/metrics"
+)
+
+var (
+	// ErrAmendNotAllowed 订单不可改单（已完结、未经交易所确认、为市价单或修改了订单类型没有的价格）
+	ErrAmendNotAllowed = errors.New("oms: order cannot be amended")
+
+	// ErrAmendRejected 改单后的新订单被拒绝，已按原价格与剩余数量恢复原订单
+	ErrAmendRejected = errors.New("oms: amended order rejected, original order restored")
+)
+
+// AmendOrderRequest 改单请求
+type AmendOrderRequest struct {
+	ClientOrderID string      // 原订单客户端订单 ID
+	Price         model.Money // 新价格（零值表示不变）
+	StopPrice     model.Money // 新触发价（仅条件单，零值表示不变）
+	Quantity      model.Money // 新的订单总数量（含原订单已成交部分，零值表示不变）
+	CurrentPrice  model.Money // 当前市价（用于风控计算）
+	AccountID     string
+}
+
+// AmendOrder 改单（原子撤单并下单，避免先撤后下期间账簿中没有订单）
+// 新订单的数量为总数量减去原订单已成交部分，经风控检查并以新的客户端订单 ID 写前记录后提交：
+//   - 原订单撤销失败：原订单保持不变，新订单记为拒绝
+//   - 原订单已撤销但新订单被拒绝：回滚，按原价格与剩余数量重新下单，返回恢复的订单与 ErrAmendRejected
+//   - 结果未知：新订单记为 UNKNOWN，由同步确认两笔订单的状态
+//
+// 成功时返回新订单，原订单记为已撤销（事件说明中记录替换关系）
+func (m *Manager) AmendOrder(ctx context.Context, req *AmendOrderRequest) (*model.Order, error) {
+	return m.amendOrder(ctx, req, false)
+}
+
+// amendOrder 改单（repriced 为超时改价，新订单的改价次数加一）
+func (m *Manager) amendOrder(ctx context.Context, req *AmendOrderRequest, repriced bool) (*model.Order, error) {
+	// 同一时刻只执行一笔改单，避免同一订单被并发替换
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	original, err := m.orderRepo.GetOrder(ctx, req.ClientOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("get order failed: %w", err)
+	}
+	if m.reconciling.Load() && !original.ReduceOnly {
+		return nil, ErrReconciling
+	}
+	if original.Status != model.OrderStatusSubmitted && original.Status != model.OrderStatusPartialFilled {
+		return nil, fmt.Errorf("%w: %s is %s", ErrAmendNotAllowed, original.ClientOrderID, original.Status)
+	}
+	if original.Type == model.OrderTypeMarket {
+		return nil, fmt.Errorf("%w: %s is a market order", ErrAmendNotAllowed, original.ClientOrderID)
+	}
+	if !req.Price.IsZero() && !original.Type.HasLimitPrice() {
+		return nil, fmt.Errorf("%w: %s (%s) has no limit price", ErrAmendNotAllowed, original.ClientOrderID, original.Type)
+	}
+	if !req.StopPrice.IsZero() && !original.Type.IsConditional() {
+		return nil, fmt.Errorf("%w: %s (%s) has no stop price", ErrAmendNotAllowed, original.ClientOrderID, original.Type)
+	}
+
+	price, stopPrice, total := original.Price, original.StopPrice, original.Quantity
+	if !req.Price.IsZero() {
+		price = req.Price
+	}
+	if !req.StopPrice.IsZero() {
+		stopPrice = req.StopPrice
+	}
+	if !req.Quantity.IsZero() {
+		total = req.Quantity
+	}
+	if price.EQ(original.Price) && stopPrice.EQ(original.StopPrice) && total.EQ(original.Quantity) {
+		return original, nil
+	}
+	quantity := total.Sub(original.Filled)
+	if !quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: quantity %s does not exceed filled %s", ErrAmendNotAllowed, total, original.Filled)
+	}
+
+	// 1. 风控检查（按新订单检查）
+	decision, err := m.riskMgr.CheckPreTrade(ctx, &riskmgr.OrderContext{
+		ClientOrderID: original.ClientOrderID,
+		Symbol:        original.Symbol,
+		MarketType:    model.MarketTypeSpot,
+		Side:          original.Side,
+		Type:          original.Type,
+		Price:         price,
+		Quantity:      quantity,
+		CurrentPrice:  req.CurrentPrice,
+		AccountID:     req.AccountID,
+		ProtectPrice:  original.ProtectPrice,
+		ReduceOnly:    original.ReduceOnly,
+		StrategyID:    original.StrategyID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("risk check failed: %w", err)
+	}
+	if !decision.IsAllowed() {
+		return nil, fmt.Errorf("%w by risk manager: %s", riskmgr.ErrOrderRejected, decision.Reason)
+	}
+
+	// 2. 写前日志：新订单意图
+	newID, err := model.NewClientOrderID(intentOf(original.ClientOrderID), original.StrategyID, original.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("generate client order id failed: %w", err)
+	}
+	now := time.Now()
+	intent := &model.Order{
+		ClientOrderID: newID,
+		Symbol:        original.Symbol,
+		MarketType:    model.MarketTypeSpot,
+		Side:          original.Side,
+		Type:          original.Type,
+		Price:         price,
+		StopPrice:     stopPrice,
+		Quantity:      quantity,
+		Filled:        model.Zero(),
+		Status:        model.OrderStatusPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		ReduceOnly:    original.ReduceOnly,
+		ProtectPrice:  original.ProtectPrice,
+		ExpireAt:      original.ExpireAt,
+		MaxAge:        original.MaxAge,
+		ExpirePolicy:  original.ExpirePolicy,
+		Reprices:      original.Reprices,
+		StrategyID:    original.StrategyID,
+	}
+	if repriced {
+		intent.Reprices++
+	}
+	if err := m.orderRepo.SaveOrder(ctx, intent); err != nil {
+		return nil, fmt.Errorf("save order intent failed: %w", err)
+	}
+
+	// 3. 调用 Gateway 改单
+	result, err := m.spotGateway.AmendOrder(ctx, &port.SpotAmendOrderRequest{
+		ClientOrderID:    original.ClientOrderID,
+		NewClientOrderID: newID,
+		Symbol:           original.Symbol,
+		Side:             original.Side,
+		Type:             original.Type,
+		Price:            price,
+		StopPrice:        stopPrice,
+		Quantity:         quantity,
+	})
+	recordCtx := context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+	case errors.Is(err, port.ErrAmendCancelFailed):
+		m.recordIntentFailure(recordCtx, newID, model.OrderStatusRejected, err)
+		return nil, fmt.Errorf("gateway amend order failed: %w", err)
+	case errors.Is(err, port.ErrAmendNewOrderRejected):
+		metrics.DefaultMetrics.OrdersRejected.Inc()
+		m.recordIntentFailure(recordCtx, newID, model.OrderStatusRejected, err)
+		cancelled := m.recordReplaced(recordCtx, original, result, "amend rejected: "+err.Error())
+		return m.restoreOrder(ctx, cancelled, req, err)
+	default:
+		// 撤单与下单结果均未知，原订单保持活跃，由同步按交易所状态确认
+		m.recordIntentFailure(recordCtx, newID, model.OrderStatusUnknown, err)
+		return nil, fmt.Errorf("gateway amend order failed: %w: %s: %v", port.ErrOrderOutcomeUnknown, newID, err)
+	}
+
+	// 4. 原订单记为已撤销，确认新订单
+	m.recordReplaced(recordCtx, original, result, "replaced by "+newID)
+	order := result.Order
+	update := model.OrderUpdate{
+		Status:     order.Status,
+		ExchangeID: order.ExchangeID,
+		Filled:     order.Filled,
+		Sequence:   order.Sequence,
+		UpdateTime: order.ExchangeUpdateTime,
+		Source:     model.OrderEventSourceAmend,
+		Reason:     "replaces " + original.ClientOrderID,
+	}
+	if _, err := m.applyUpdate(recordCtx, newID, update); err != nil && !errors.Is(err, model.ErrStaleOrderUpdate) {
+		return nil, fmt.Errorf("save order failed: %w", err)
+	}
+	order.ReduceOnly = original.ReduceOnly
+	order.StrategyID = original.StrategyID
+	return order, nil
+}
+
+// restoreOrder 回滚：新订单被拒绝时按原价格与剩余数量重新下单
+func (m *Manager) restoreOrder(ctx context.Context, cancelled *model.Order, req *AmendOrderRequest, amendErr error) (*model.Order, error) {
+	remaining := cancelled.Quantity.Sub(cancelled.Filled)
+	if !remaining.IsPositive() || cancelled.PastDeadline(time.Now()) {
+		// 撤销前已全部成交或已过截止时间，无需恢复
+		return nil, fmt.Errorf("%w: %v", ErrAmendRejected, amendErr)
+	}
+
+	restored, err := m.PlaceOrder(ctx, &PlaceOrderRequest{
+		Intent:       intentOf(cancelled.ClientOrderID),
+		Symbol:       cancelled.Symbol,
+		Side:         cancelled.Side,
+		Type:         cancelled.Type,
+		Price:        cancelled.Price,
+		StopPrice:    cancelled.StopPrice,
+		Quantity:     remaining,
+		CurrentPrice: req.CurrentPrice,
+		AccountID:    req.AccountID,
+		StrategyID:   cancelled.StrategyID,
+		ProtectPrice: cancelled.ProtectPrice,
+		ReduceOnly:   cancelled.ReduceOnly,
+		Priority:     PriorityHigh, // 恢复原订单，不接受风控降档
+		ExpireAt:     cancelled.ExpireAt,
+		MaxAge:       cancelled.MaxAge,
+		ExpirePolicy: cancelled.ExpirePolicy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("amended order rejected (%v) and restore of %s failed: %w", amendErr, cancelled.ClientOrderID, err)
+	}
+	return restored, fmt.Errorf("%w: %s restored as %s: %v", ErrAmendRejected, cancelled.ClientOrderID, restored.ClientOrderID, amendErr)
+}
+
+// recordReplaced 原订单记为已撤销（以交易所返回的最终成交数量为准），返回撤销后的原订单
+func (m *Manager) recordReplaced(ctx context.Context, original *model.Order, result *port.AmendOrderResult, reason string) *model.Order {
+	cancelled := *original
+	cancelled.Status = model.OrderStatusCancelled
+	if result != nil && result.Cancelled != nil && result.Cancelled.Filled.GT(cancelled.Filled) {
+		cancelled.Filled = result.Cancelled.Filled
+	}
+
+	update := model.OrderUpdate{
+		Status: model.OrderStatusCancelled,
+		Filled: cancelled.Filled,
+		Source: model.OrderEventSourceAmend,
+		Reason: reason,
+	}
+	if _, err := m.applyUpdate(ctx, original.ClientOrderID, update); err == nil {
+		metrics.DefaultMetrics.OrdersCancelled.Inc()
+	}
+	return &cancelled
+}
+
+// recordIntentFailure 记录新订单意图的失败结果（未能记录时意图保持 PENDING，由同步处理）
+func (m *Manager) recordIntentFailure(ctx context.Context, clientOrderID string, status model.OrderStatus, cause error) {
+	update := model.OrderUpdate{Status: status, Source: model.OrderEventSourceAmend, Reason: cause.Error()}
+	_, _ = m.applyUpdate(ctx, clientOrderID, update)
+}
+
+// intentOf 从客户端订单 ID 解析下单意图（非编码格式时视为人工下单）
+func intentOf(clientOrderID string) model.OrderIntent {
+	if id, err := model.DecodeClientOrderID(clientOrderID); err == nil {
+		return id.Intent
+	}
+	return model.OrderIntentManual
+}
@@ -0,0 +1,159 @@
+package oms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
+	"github.com/iluyuns/alpha-trade/internal/infra/order"
+	"github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+// rejectingAmendGateway 撤销原订单后拒绝新订单的模拟交易所
+type rejectingAmendGateway struct {
+	*mock.SpotExchange
+}
+
+func (g *rejectingAmendGateway) AmendOrder(ctx context.Context, req *port.SpotAmendOrderRequest) (*port.AmendOrderResult, error) {
+	if err := g.CancelOrder(ctx, &port.SpotCancelOrderRequest{ClientOrderID: req.ClientOrderID, Symbol: req.Symbol}); err != nil {
+		return nil, fmt.Errorf("%w: %v", port.ErrAmendCancelFailed, err)
+	}
+	cancelled, _ := g.GetOrder(ctx, req.ClientOrderID)
+	return &port.AmendOrderResult{Cancelled: cancelled}, fmt.Errorf("%w: price filter", port.ErrAmendNewOrderRejected)
+}
+
+func newAmendTestManager(t *testing.T, gateway port.SpotGateway, exchange *mock.SpotExchange) (*Manager, port.OrderRepo, *model.Order) {
+	t.Helper()
+	ctx := context.Background()
+
+	exchange.SetInstantFill(false)
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	orderRepo := order.NewMemoryRepo()
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(gateway, orderRepo, riskMgr, Config{AutoSync: false})
+
+	resting, err := oms.PlaceOrder(ctx, &PlaceOrderRequest{
+		StrategyID:   "grid",
+		Symbol:       "BTCUSDT",
+		Side:         model.OrderSideBuy,
+		Type:         model.OrderTypeLimit,
+		Price:        model.MustMoney("49000"),
+		Quantity:     model.MustMoney("0.02"),
+		CurrentPrice: model.MustMoney("50000"),
+		AccountID:    "amend-account",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if resting.Status != model.OrderStatusSubmitted {
+		t.Fatalf("resting order status = %s, want SUBMITTED", resting.Status)
+	}
+	return oms, orderRepo, resting
+}
+
+func TestManager_AmendOrder(t *testing.T) {
+	ctx := context.Background()
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+	oms, orderRepo, resting := newAmendTestManager(t, exchange, exchange)
+
+	amended, err := oms.AmendOrder(ctx, &AmendOrderRequest{
+		ClientOrderID: resting.ClientOrderID,
+		Price:         model.MustMoney("49500"),
+		CurrentPrice:  model.MustMoney("50000"),
+		AccountID:     "amend-account",
+	})
+	if err != nil {
+		t.Fatalf("AmendOrder failed: %v", err)
+	}
+	if amended.ClientOrderID == resting.ClientOrderID || !amended.Price.EQ(model.MustMoney("49500")) {
+		t.Errorf("amended order = %s @ %s", amended.ClientOrderID, amended.Price)
+	}
+	if c, err := model.DecodeClientOrderID(amended.ClientOrderID); err != nil || c.StrategyCode != model.StrategyCode("grid") {
+		t.Errorf("amended order should keep strategy attribution: %+v, %v", c, err)
+	}
+
+	original, _ := orderRepo.GetOrder(ctx, resting.ClientOrderID)
+	if original.Status != model.OrderStatusCancelled {
+		t.Errorf("original status = %s, want CANCELLED", original.Status)
+	}
+	saved, _ := orderRepo.GetOrder(ctx, amended.ClientOrderID)
+	if saved.Status != model.OrderStatusSubmitted || saved.ExchangeID == "" {
+		t.Errorf("new order status = %s, exchangeID = %q", saved.Status, saved.ExchangeID)
+	}
+	events, _ := orderRepo.ListOrderEvents(ctx, resting.ClientOrderID)
+	if last := events[len(events)-1]; last.Source != model.OrderEventSourceAmend || last.Reason != "replaced by "+amended.ClientOrderID {
+		t.Errorf("original last event = %s %q", last.Source, last.Reason)
+	}
+
+	// 已撤销的原订单不可再改
+	_, err = oms.AmendOrder(ctx, &AmendOrderRequest{ClientOrderID: resting.ClientOrderID, Price: model.MustMoney("49600")})
+	if !errors.Is(err, ErrAmendNotAllowed) {
+		t.Errorf("expected ErrAmendNotAllowed, got %v", err)
+	}
+}
+
+func TestManager_AmendOrder_CancelFailed(t *testing.T) {
+	ctx := context.Background()
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+	oms, orderRepo, resting := newAmendTestManager(t, exchange, exchange)
+
+	// 交易所端已撤销（本地尚未同步）：撤单失败，不提交新订单
+	if err := exchange.CancelOrder(ctx, &port.SpotCancelOrderRequest{ClientOrderID: resting.ClientOrderID}); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	_, err := oms.AmendOrder(ctx, &AmendOrderRequest{
+		ClientOrderID: resting.ClientOrderID,
+		Quantity:      model.MustMoney("0.03"),
+		CurrentPrice:  model.MustMoney("50000"),
+		AccountID:     "amend-account",
+	})
+	if !errors.Is(err, port.ErrAmendCancelFailed) {
+		t.Fatalf("expected ErrAmendCancelFailed, got %v", err)
+	}
+
+	open, _ := exchange.ListOpenOrders(ctx, "BTCUSDT")
+	if len(open) != 0 {
+		t.Errorf("no new order should be placed, got %d open", len(open))
+	}
+	active, _ := orderRepo.ListActiveOrders(ctx)
+	if len(active) != 1 || active[0].ClientOrderID != resting.ClientOrderID {
+		t.Errorf("only the original order should stay active locally, got %d", len(active))
+	}
+}
+
+func TestManager_AmendOrder_RollbackOnReject(t *testing.T) {
+	ctx := context.Background()
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+	gateway := &rejectingAmendGateway{SpotExchange: exchange}
+	oms, orderRepo, resting := newAmendTestManager(t, gateway, exchange)
+
+	restored, err := oms.AmendOrder(ctx, &AmendOrderRequest{
+		ClientOrderID: resting.ClientOrderID,
+		Price:         model.MustMoney("1"),
+		CurrentPrice:  model.MustMoney("50000"),
+		AccountID:     "amend-account",
+	})
+	if !errors.Is(err, ErrAmendRejected) {
+		t.Fatalf("expected ErrAmendRejected, got %v", err)
+	}
+	if restored == nil {
+		t.Fatal("expected restored order")
+	}
+	if !restored.Price.EQ(resting.Price) || !restored.Quantity.EQ(resting.Quantity) {
+		t.Errorf("restored order = %s @ %s, want %s @ %s", restored.Quantity, restored.Price, resting.Quantity, resting.Price)
+	}
+
+	open, _ := exchange.ListOpenOrders(ctx, "BTCUSDT")
+	if len(open) != 1 || open[0].ClientOrderID != restored.ClientOrderID {
+		t.Errorf("restored order should be the only open order, got %d", len(open))
+	}
+	original, _ := orderRepo.GetOrder(ctx, resting.ClientOrderID)
+	if original.Status != model.OrderStatusCancelled {
+		t.Errorf("original status = %s, want CANCELLED", original.Status)
+	}
+}
@@ -0,0 +1,159 @@
+package oms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+// ExpiryAction 挂单超时的处理结果
+type ExpiryAction int
+
+const (
+	ExpiryActionCancelled ExpiryAction = iota + 1 // 已撤单
+	ExpiryActionRepriced                          // 已按最新价改单
+)
+
+func (a ExpiryAction) String() string {
+	switch a {
+	case ExpiryActionCancelled:
+		return "CANCELLED"
+	case ExpiryActionRepriced:
+		return "REPRICED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ExpiryEvent 挂单超时事件（处理后通知下单策略）
+type ExpiryEvent struct {
+	Order       *model.Order // 超时的订单（处理后的状态）
+	Action      ExpiryAction
+	Replacement *model.Order // 改价后的新订单（撤单时为 nil）
+	Reason      string       // 超时原因
+}
+
+// ExpiryHandler 挂单超时回调
+type ExpiryHandler func(ctx context.Context, event *ExpiryEvent)
+
+// PriceSource 最新价格来源（超时改价使用，由 position.Ledger 实现）
+type PriceSource interface {
+	MarkPrice(symbol string) (model.Money, bool)
+}
+
+// SetExpiryHandler 设置挂单超时回调
+func (m *Manager) SetExpiryHandler(handler ExpiryHandler) {
+	m.expiryHandler = handler
+}
+
+// SetPriceSource 设置超时改价的价格来源（未设置时超时挂单一律撤单）
+func (m *Manager) SetPriceSource(prices PriceSource) {
+	m.prices = prices
+}
+
+// ExpireOrders 处理超时挂单（SyncActiveOrders 在同步后调用），返回处理的订单数
+// 过截止时间的订单撤单；超过最长挂单时长的订单按 ExpirePolicy 处理：
+//   - CANCEL（默认）：撤单
+//   - REPRICE：按最新价改单，新订单重新计算挂单时长；非限价单、改价次数达到 MaxReprices、
+//     没有最新价格、价格未变或新价格被风控拒绝时撤单
+//
+// 每笔处理后回调 ExpiryHandler。单笔失败不中断，失败的订单保持挂单，下次同步重试，返回最后一个错误
+func (m *Manager) ExpireOrders(ctx context.Context, now time.Time) (int, error) {
+	orders, err := m.orderRepo.ListActiveOrders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list active orders failed: %w", err)
+	}
+
+	var (
+		handled int
+		lastErr error
+	)
+	for _, order := range orders {
+		if order.Status != model.OrderStatusSubmitted && order.Status != model.OrderStatusPartialFilled {
+			continue
+		}
+		if !order.PastDeadline(now) && !order.Stale(now) {
+			continue
+		}
+
+		event, err := m.expireOrder(ctx, order, now)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		handled++
+		if m.expiryHandler != nil {
+			m.expiryHandler(ctx, event)
+		}
+	}
+	return handled, lastErr
+}
+
+// expireOrder 撤销或改价单笔超时挂单
+func (m *Manager) expireOrder(ctx context.Context, order *model.Order, now time.Time) (*ExpiryEvent, error) {
+	event := &ExpiryEvent{Order: order, Reason: "max age exceeded"}
+	if order.PastDeadline(now) {
+		event.Reason = "good-till-time reached"
+	} else if price, ok := m.repricePrice(order); ok {
+		replacement, err := m.amendOrder(ctx, &AmendOrderRequest{
+			ClientOrderID: order.ClientOrderID,
+			Price:         price,
+			CurrentPrice:  price,
+			AccountID:     m.config.AccountID,
+		}, true)
+		switch {
+		case err == nil:
+			event.Action = ExpiryActionRepriced
+			event.Order = m.latestOrder(ctx, order)
+			event.Replacement = replacement
+			return event, nil
+		case errors.Is(err, ErrAmendRejected) && replacement == nil:
+			// 原订单已撤销且未恢复
+			event.Action = ExpiryActionCancelled
+			event.Order = m.latestOrder(ctx, order)
+			event.Reason += ", reprice rejected"
+			return event, nil
+		case errors.Is(err, ErrAmendRejected):
+			// 已按原价格恢复：撤销恢复的订单
+			order = replacement
+			event.Reason += ", reprice rejected"
+		case errors.Is(err, port.ErrAmendCancelFailed) || errors.Is(err, port.ErrOrderOutcomeUnknown):
+			return nil, fmt.Errorf("reprice expired order %s failed: %w", order.ClientOrderID, err)
+		default:
+			// 未提交到交易所（风控拒绝等），原订单保持不变，改为撤单
+			event.Reason += ", reprice not allowed"
+		}
+	}
+
+	if err := m.CancelOrder(ctx, order.ClientOrderID); err != nil {
+		return nil, fmt.Errorf("cancel expired order %s failed: %w", order.ClientOrderID, err)
+	}
+	event.Action = ExpiryActionCancelled
+	event.Order = m.latestOrder(ctx, order)
+	return event, nil
+}
+
+// repricePrice 超时改价的新价格（不按改价处理时返回 false）
+func (m *Manager) repricePrice(order *model.Order) (model.Money, bool) {
+	if order.ExpirePolicy != model.ExpirePolicyReprice || order.Type != model.OrderTypeLimit ||
+		order.Reprices >= m.config.MaxReprices || m.prices == nil {
+		return model.Zero(), false
+	}
+	price, ok := m.prices.MarkPrice(order.Symbol)
+	if !ok || !price.IsPositive() || price.EQ(order.Price) {
+		return model.Zero(), false
+	}
+	return price, true
+}
+
+// latestOrder 本地订单的最新状态（查询失败时返回 fallback）
+func (m *Manager) latestOrder(ctx context.Context, fallback *model.Order) *model.Order {
+	if order, err := m.orderRepo.GetOrder(ctx, fallback.ClientOrderID); err == nil {
+		return order
+	}
+	return fallback
+}
@@ -0,0 +1,165 @@
+package oms
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
+	"github.com/iluyuns/alpha-trade/internal/infra/order"
+	"github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+// fixedPrices 固定价格来源
+type fixedPrices map[string]model.Money
+
+func (p fixedPrices) MarkPrice(symbol string) (model.Money, bool) {
+	price, ok := p[symbol]
+	return price, ok
+}
+
+func newExpiryTestManager(t *testing.T, config Config) (*Manager, *[]*ExpiryEvent) {
+	t.Helper()
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+	exchange.SetInstantFill(false)
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+
+	config.AccountID = "expiry-account"
+	oms := NewManager(exchange, order.NewMemoryRepo(), riskMgr, config)
+	var events []*ExpiryEvent
+	oms.SetExpiryHandler(func(ctx context.Context, event *ExpiryEvent) {
+		events = append(events, event)
+	})
+	return oms, &events
+}
+
+func placeResting(t *testing.T, oms *Manager, req *PlaceOrderRequest) *model.Order {
+	t.Helper()
+	req.StrategyID = "grid"
+	req.Symbol = "BTCUSDT"
+	req.Side = model.OrderSideBuy
+	req.Type = model.OrderTypeLimit
+	req.Price = model.MustMoney("49000")
+	req.Quantity = model.MustMoney("0.02")
+	req.CurrentPrice = model.MustMoney("50000")
+	req.AccountID = "expiry-account"
+	resting, err := oms.PlaceOrder(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if resting.Status != model.OrderStatusSubmitted {
+		t.Fatalf("resting order status = %s, want SUBMITTED", resting.Status)
+	}
+	return resting
+}
+
+func TestManager_ExpireOrders_Cancel(t *testing.T) {
+	ctx := context.Background()
+	oms, events := newExpiryTestManager(t, Config{})
+	resting := placeResting(t, oms, &PlaceOrderRequest{MaxAge: time.Minute})
+	gtc := placeResting(t, oms, &PlaceOrderRequest{})
+
+	if n, err := oms.ExpireOrders(ctx, time.Now().Add(30*time.Second)); err != nil || n != 0 {
+		t.Fatalf("ExpireOrders before max age = %d, %v", n, err)
+	}
+
+	n, err := oms.ExpireOrders(ctx, time.Now().Add(2*time.Minute))
+	if err != nil || n != 1 {
+		t.Fatalf("ExpireOrders = %d, %v, want 1", n, err)
+	}
+	expired, _ := oms.GetOrder(ctx, resting.ClientOrderID)
+	if expired.Status != model.OrderStatusCancelled {
+		t.Errorf("stale order status = %s, want CANCELLED", expired.Status)
+	}
+	if kept, _ := oms.GetOrder(ctx, gtc.ClientOrderID); kept.Status != model.OrderStatusSubmitted {
+		t.Errorf("order without expiry should keep resting, got %s", kept.Status)
+	}
+
+	if len(*events) != 1 {
+		t.Fatalf("expected 1 expiry event, got %d", len(*events))
+	}
+	event := (*events)[0]
+	if event.Action != ExpiryActionCancelled || event.Order.ClientOrderID != resting.ClientOrderID || event.Replacement != nil {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.Order.StrategyID != "grid" {
+		t.Errorf("event should carry owning strategy, got %q", event.Order.StrategyID)
+	}
+}
+
+func TestManager_ExpireOrders_Reprice(t *testing.T) {
+	ctx := context.Background()
+	oms, events := newExpiryTestManager(t, Config{MaxReprices: 1})
+	oms.SetPriceSource(fixedPrices{"BTCUSDT": model.MustMoney("49500")})
+	resting := placeResting(t, oms, &PlaceOrderRequest{
+		MaxAge:       time.Minute,
+		ExpirePolicy: model.ExpirePolicyReprice,
+	})
+
+	if _, err := oms.ExpireOrders(ctx, time.Now().Add(2*time.Minute)); err != nil {
+		t.Fatalf("ExpireOrders failed: %v", err)
+	}
+	if len(*events) != 1 || (*events)[0].Action != ExpiryActionRepriced {
+		t.Fatalf("expected a reprice event, got %+v", *events)
+	}
+	replacement := (*events)[0].Replacement
+	if !replacement.Price.EQ(model.MustMoney("49500")) {
+		t.Errorf("repriced order price = %s, want 49500", replacement.Price)
+	}
+	original, _ := oms.GetOrder(ctx, resting.ClientOrderID)
+	if original.Status != model.OrderStatusCancelled {
+		t.Errorf("original status = %s, want CANCELLED", original.Status)
+	}
+	stored, _ := oms.GetOrder(ctx, replacement.ClientOrderID)
+	if stored.Reprices != 1 || stored.MaxAge != time.Minute || stored.ExpirePolicy != model.ExpirePolicyReprice {
+		t.Errorf("replacement should inherit expiry settings: reprices=%d maxAge=%s policy=%s",
+			stored.Reprices, stored.MaxAge, stored.ExpirePolicy)
+	}
+
+	// 达到改价次数上限：撤单
+	if _, err := oms.ExpireOrders(ctx, time.Now().Add(2*time.Minute)); err != nil {
+		t.Fatalf("ExpireOrders failed: %v", err)
+	}
+	if len(*events) != 2 || (*events)[1].Action != ExpiryActionCancelled {
+		t.Fatalf("expected cancel after max reprices, got %+v", *events)
+	}
+	if stored, _ := oms.GetOrder(ctx, replacement.ClientOrderID); stored.Status != model.OrderStatusCancelled {
+		t.Errorf("replacement status = %s, want CANCELLED", stored.Status)
+	}
+}
+
+func TestManager_ExpireOrders_GoodTillTime(t *testing.T) {
+	ctx := context.Background()
+	oms, events := newExpiryTestManager(t, Config{})
+	oms.SetPriceSource(fixedPrices{"BTCUSDT": model.MustMoney("49500")})
+	deadline := time.Now().Add(time.Hour)
+	resting := placeResting(t, oms, &PlaceOrderRequest{
+		ExpireAt:     deadline,
+		ExpirePolicy: model.ExpirePolicyReprice,
+	})
+
+	// 截止时间到达时不改价，直接撤单
+	if _, err := oms.ExpireOrders(ctx, deadline); err != nil {
+		t.Fatalf("ExpireOrders failed: %v", err)
+	}
+	if len(*events) != 1 || (*events)[0].Action != ExpiryActionCancelled {
+		t.Fatalf("expected cancel at good-till-time, got %+v", *events)
+	}
+	if expired, _ := oms.GetOrder(ctx, resting.ClientOrderID); expired.Status != model.OrderStatusCancelled {
+		t.Errorf("status = %s, want CANCELLED", expired.Status)
+	}
+
+	if _, err := oms.PlaceOrder(ctx, &PlaceOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     model.OrderSideBuy,
+		Type:     model.OrderTypeLimit,
+		Price:    model.MustMoney("49000"),
+		Quantity: model.MustMoney("0.02"),
+		ExpireAt: time.Now().Add(-time.Second),
+	}); err == nil {
+		t.Error("expected order with past good-till-time to be rejected")
+	}
+}
@@ -2,8 +2,11 @@ package oms
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
@@ -12,6 +15,12 @@ import (
 	"github.com/iluyuns/alpha-trade/internal/pkg/metrics"
 )
 
+// OrderUpdateHandler 订单状态变化回调（状态或成交数量变化后的最新订单）
+type OrderUpdateHandler func(ctx context.Context, order *model.Order)
+
+// ErrReconciling 对账进行中，暂停非只减仓订单（本地订单与持仓可能与交易所不一致）
+var ErrReconciling = errors.New("oms: reconciliation in progress, trading is blocked")
+
 // Manager 订单管理系统（Order Management System）
 // 职责：
 // 1. 订单状态同步（Gateway <-> OrderRepo）
@@ -31,12 +40,31 @@ type Manager struct {
 	// 状态同步
 	syncInterval time.Duration // 状态同步间隔
 	stopChan     chan struct{}
+
+	// 对账进行中（拒绝非只减仓订单）
+	reconciling atomic.Bool
+
+	// 订单组（OCO / 括号单，可选）
+	orderListRepo port.OrderListRepo
+	listMu        sync.Mutex // 串行化订单组的挂出、触发与同步
+
+	// 挂单超时（可选）
+	expiryHandler ExpiryHandler // 超时处理后通知下单策略
+	prices        PriceSource   // 超时改价的最新价格来源（nil 时无法改价，一律撤单）
+
+	// 订单状态变化回调（可选，通知下单策略）
+	updateHandler OrderUpdateHandler
 }
 
 // Config OMS 配置
 type Config struct {
-	SyncInterval time.Duration // 订单状态同步间隔（默认 5 秒）
-	AutoSync     bool          // 是否自动同步订单状态
+	SyncInterval  time.Duration // 订单状态同步间隔（默认 5 秒）
+	AutoSync      bool          // 是否自动同步订单状态
+	IntentTimeout time.Duration // 未确认下单意图的超时（超时后交易所仍查无此单视为未下单并标记拒绝，默认 1 分钟）
+	SubmitTimeout time.Duration // 网关下单超时（超时后订单结果未知，记为 UNKNOWN 待同步确认，默认 10 秒）
+	ResolveGrace  time.Duration // 启动解析未确认意图的宽限期（意图写入后超过该时长仍查无此单才标记拒绝，须大于 SubmitTimeout，默认 IntentTimeout）
+	MaxReprices   int           // 挂单超时改价的次数上限（达到后撤单，默认 3）
+	AccountID     string        // 账户 ID（超时改价时的风控检查使用）
 }
 
 // NewManager 创建订单管理器
@@ -49,6 +77,22 @@ func NewManager(
 	if config.SyncInterval == 0 {
 		config.SyncInterval = 5 * time.Second
 	}
+	if config.IntentTimeout == 0 {
+		config.IntentTimeout = time.Minute
+	}
+	if config.SubmitTimeout == 0 {
+		config.SubmitTimeout = 10 * time.Second
+	}
+	if config.ResolveGrace == 0 {
+		config.ResolveGrace = config.IntentTimeout
+	}
+	// 宽限期不超过下单超时时，崩溃前仍在途的请求可能在解析后才被交易所接受
+	if config.ResolveGrace <= config.SubmitTimeout {
+		config.ResolveGrace = 2 * config.SubmitTimeout
+	}
+	if config.MaxReprices == 0 {
+		config.MaxReprices = 3
+	}
 
 	return &Manager{
 		spotGateway: spotGateway,
@@ -60,8 +104,67 @@ func NewManager(
 }
 
 // PlaceOrder 下单（集成风控检查）
-// 流程：RiskManager.CheckPreTrade -> Gateway.PlaceOrder -> OrderRepo.SaveOrder
+// 流程：RiskManager.CheckPreTrade -> OrderRepo.SaveOrder（PENDING 意图）-> Gateway.PlaceOrder -> OrderRepo.ApplyUpdate
+// 网关超时时订单记为 UNKNOWN 并返回包装 port.ErrOrderOutcomeUnknown 的错误；
+// 以相同 ClientOrderID 重试是安全的：已确认的订单直接返回，未确认的意图先向交易所查询，查无此单时才按意图记录的数量与价格重新提交
 func (m *Manager) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*model.Order, error) {
+	if m.reconciling.Load() && !req.ReduceOnly {
+		return nil, ErrReconciling
+	}
+	if req.Type.IsConditional() && !req.StopPrice.IsPositive() {
+		return nil, fmt.Errorf("%s order requires a stop price", req.Type)
+	}
+	if !req.ExpireAt.IsZero() && !req.ExpireAt.After(time.Now()) {
+		return nil, fmt.Errorf("good-till-time %s already passed", req.ExpireAt.Format(time.RFC3339))
+	}
+
+	// 重试：同一 ClientOrderID 已有记录
+	var intent *model.Order
+	if req.ClientOrderID != "" {
+		existing, err := m.orderRepo.GetOrder(ctx, req.ClientOrderID)
+		switch {
+		case err == nil && !existing.Status.IsUnconfirmed():
+			if existing.Status == model.OrderStatusRejected {
+				return nil, fmt.Errorf("order already rejected: %s", existing.ClientOrderID)
+			}
+			return existing, nil
+		case err == nil:
+			order, err := m.confirmIntent(ctx, existing)
+			if err == nil {
+				return order, nil
+			}
+			if !errors.Is(err, port.ErrExchangeOrderNotFound) {
+				return nil, fmt.Errorf("%w: %s: %v", port.ErrOrderOutcomeUnknown, existing.ClientOrderID, err)
+			}
+			intent = existing // 交易所查无此单：沿用已写入的意图重新提交
+		case !errors.Is(err, port.ErrOrderNotFound):
+			return nil, fmt.Errorf("get order failed: %w", err)
+		}
+	}
+
+	if req.ClientOrderID == "" {
+		orderIntent := req.Intent
+		if orderIntent == 0 {
+			orderIntent = model.OrderIntentManual
+		}
+		clientOrderID, err := model.NewClientOrderID(orderIntent, req.StrategyID, req.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("generate client order id failed: %w", err)
+		}
+		req.ClientOrderID = clientOrderID
+	}
+
+	// 0. 绩效降级：减半模式下按策略仓位系数压缩开仓数量（见 RISK_PROTOCOL 7.2）
+	// 重新提交已写入的意图时沿用意图记录的数量与价格，不再压缩或降档（交易所按 ClientOrderID 去重，提交内容须与意图一致）
+	quantity, price, stopPrice := req.Quantity, req.Price, req.StopPrice
+	if intent != nil {
+		quantity, price, stopPrice = intent.Quantity, intent.Price, intent.StopPrice
+	} else if !req.ReduceOnly {
+		if multiplier := m.riskMgr.SizeMultiplier(req.StrategyID); multiplier > 0 && multiplier < 1 {
+			quantity = quantity.Mul(model.NewMoneyFromFloat(multiplier))
+		}
+	}
+
 	// 1. 风控检查
 	orderCtx := &riskmgr.OrderContext{
 		ClientOrderID: req.ClientOrderID,
@@ -69,11 +172,13 @@ func (m *Manager) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*mode
 		MarketType:    model.MarketTypeSpot,
 		Side:          req.Side,
 		Type:          req.Type,
-		Price:         req.Price,
-		Quantity:      req.Quantity,
+		Price:         price,
+		Quantity:      quantity,
 		CurrentPrice:  req.CurrentPrice,
 		AccountID:     req.AccountID,
 		ProtectPrice:  req.ProtectPrice,
+		ReduceOnly:    req.ReduceOnly,
+		StrategyID:    req.StrategyID,
 	}
 
 	decision, err := m.riskMgr.CheckPreTrade(ctx, orderCtx)
@@ -82,41 +187,92 @@ func (m *Manager) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*mode
 	}
 
 	if !decision.IsAllowed() {
-		return nil, fmt.Errorf("order rejected by risk manager: %s", decision.Reason)
+		return nil, fmt.Errorf("%w by risk manager: %s", riskmgr.ErrOrderRejected, decision.Reason)
 	}
 
-	// 2. 如果风控建议降档，使用建议数量
-	quantity := req.Quantity
-	if decision.ShouldReduce() && decision.SuggestedQuantity != "" {
+	// 2. 如果风控建议降档，使用建议数量（高优先级平仓必须全量执行，不降档）
+	if intent == nil && req.Priority != PriorityHigh && decision.ShouldReduce() && decision.SuggestedQuantity != "" {
 		suggestedQty, err := model.NewMoney(decision.SuggestedQuantity)
 		if err == nil {
 			quantity = suggestedQty
 		}
 	}
 
-	// 3. 调用 Gateway 下单
+	// 3. 写前日志：调用 Gateway 前持久化下单意图，网关调用后崩溃或保存失败时，重启可按 ClientOrderID 向交易所查询
+	if intent == nil {
+		now := time.Now()
+		intent = &model.Order{
+			ClientOrderID: req.ClientOrderID,
+			Symbol:        req.Symbol,
+			MarketType:    model.MarketTypeSpot,
+			Side:          req.Side,
+			Type:          req.Type,
+			Price:         price,
+			StopPrice:     stopPrice,
+			Quantity:      quantity,
+			Filled:        model.Zero(),
+			Status:        model.OrderStatusPending,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			ReduceOnly:    req.ReduceOnly,
+			ProtectPrice:  req.ProtectPrice,
+			ExpireAt:      req.ExpireAt,
+			MaxAge:        req.MaxAge,
+			ExpirePolicy:  req.ExpirePolicy,
+			StrategyID:    req.StrategyID,
+		}
+		if err := m.orderRepo.SaveOrder(ctx, intent); err != nil {
+			return nil, fmt.Errorf("save order intent failed: %w", err)
+		}
+	}
+
+	// 4. 调用 Gateway 下单（使用意图的 ClientOrderID，交易所按该 ID 去重）
 	gatewayReq := &port.SpotPlaceOrderRequest{
 		ClientOrderID: req.ClientOrderID,
 		Symbol:        req.Symbol,
 		Side:          req.Side,
 		Type:          req.Type,
-		Price:         req.Price,
+		Price:         price,
+		StopPrice:     stopPrice,
 		Quantity:      quantity,
 		ProtectPrice:  req.ProtectPrice,
 	}
 
 	startTime := time.Now()
-	order, err := m.spotGateway.PlaceOrder(ctx, gatewayReq)
+	submitCtx, cancel := context.WithTimeout(ctx, m.config.SubmitTimeout)
+	order, err := m.spotGateway.PlaceOrder(submitCtx, gatewayReq)
+	cancel()
+	// 请求可能已超时或取消，记录结果时不受其影响
+	recordCtx := context.WithoutCancel(ctx)
 	if err != nil {
+		if isOutcomeUnknown(err) {
+			// 结果未知：订单可能已生效，记为 UNKNOWN 待同步确认，不计为拒绝
+			update := model.OrderUpdate{Status: model.OrderStatusUnknown, Source: model.OrderEventSourceSubmit, Reason: err.Error()}
+			_, _ = m.applyUpdate(recordCtx, req.ClientOrderID, update)
+			return nil, fmt.Errorf("gateway place order failed: %w: %s: %v", port.ErrOrderOutcomeUnknown, req.ClientOrderID, err)
+		}
 		metrics.DefaultMetrics.OrdersRejected.Inc()
+		// 记录失败（未能记录时意图保持 PENDING，由同步按交易所查询结果处理）
+		update := model.OrderUpdate{Status: model.OrderStatusRejected, Source: model.OrderEventSourceSubmit, Reason: err.Error()}
+		_, _ = m.applyUpdate(recordCtx, req.ClientOrderID, update)
 		return nil, fmt.Errorf("gateway place order failed: %w", err)
 	}
 
 	// 记录订单延迟
 	metrics.DefaultMetrics.OrderLatency.Observe(time.Since(startTime).Seconds())
-
-	// 4. 持久化订单
-	if err := m.orderRepo.SaveOrder(ctx, order); err != nil {
+	order.ReduceOnly = req.ReduceOnly
+	order.StrategyID = req.StrategyID
+
+	// 5. 按网关返回确认意图（写入交易所订单 ID 并推进状态）
+	update := model.OrderUpdate{
+		Status:     order.Status,
+		ExchangeID: order.ExchangeID,
+		Filled:     order.Filled,
+		Sequence:   order.Sequence,
+		UpdateTime: order.ExchangeUpdateTime,
+		Source:     model.OrderEventSourceSubmit,
+	}
+	if _, err := m.applyUpdate(recordCtx, order.ClientOrderID, update); err != nil && !errors.Is(err, model.ErrStaleOrderUpdate) {
 		return nil, fmt.Errorf("save order failed: %w", err)
 	}
 
@@ -129,6 +285,33 @@ func (m *Manager) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*mode
 	return order, nil
 }
 
+// SetOrderUpdateHandler 设置订单状态变化回调
+func (m *Manager) SetOrderUpdateHandler(handler OrderUpdateHandler) {
+	m.updateHandler = handler
+}
+
+// applyUpdate 按状态机应用订单更新，状态或成交数量变化时回调 OrderUpdateHandler
+func (m *Manager) applyUpdate(ctx context.Context, clientOrderID string, update model.OrderUpdate) (*model.OrderEvent, error) {
+	event, err := m.orderRepo.ApplyUpdate(ctx, clientOrderID, update)
+	if err != nil || event == nil || m.updateHandler == nil {
+		return event, err
+	}
+	if order, err := m.orderRepo.GetOrder(ctx, clientOrderID); err == nil {
+		m.updateHandler(ctx, order)
+	}
+	return event, nil
+}
+
+// SetReconciling 设置对账状态（对账期间拒绝非只减仓订单，实现 reconcile.TradingGate）
+func (m *Manager) SetReconciling(reconciling bool) {
+	m.reconciling.Store(reconciling)
+}
+
+// Reconciling 是否处于对账中
+func (m *Manager) Reconciling() bool {
+	return m.reconciling.Load()
+}
+
 // CancelOrder 撤单
 func (m *Manager) CancelOrder(ctx context.Context, clientOrderID string) error {
 	// 1. 从 OrderRepo 获取订单
@@ -152,8 +335,9 @@ func (m *Manager) CancelOrder(ctx context.Context, clientOrderID string) error {
 		return fmt.Errorf("gateway cancel order failed: %w", err)
 	}
 
-	// 4. 更新订单状态
-	if err := m.orderRepo.UpdateOrderStatus(ctx, clientOrderID, model.OrderStatusCancelled); err != nil {
+	// 4. 更新订单状态（撤单期间已成交时状态机拒绝回退）
+	update := model.OrderUpdate{Status: model.OrderStatusCancelled, Source: model.OrderEventSourceCancel}
+	if _, err := m.applyUpdate(ctx, clientOrderID, update); err != nil {
 		return fmt.Errorf("update order status failed: %w", err)
 	}
 
@@ -163,38 +347,144 @@ func (m *Manager) CancelOrder(ctx context.Context, clientOrderID string) error {
 	return nil
 }
 
+// CancelAllOrders 撤销全部活跃订单（Kill Switch），返回成功撤销的订单数
+// 单笔失败不中断，继续撤销其余订单，返回最后一个错误
+func (m *Manager) CancelAllOrders(ctx context.Context) (int, error) {
+	orders, err := m.orderRepo.ListActiveOrders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list active orders failed: %w", err)
+	}
+
+	var (
+		cancelled int
+		lastErr   error
+	)
+	for _, order := range orders {
+		if err := m.CancelOrder(ctx, order.ClientOrderID); err != nil {
+			lastErr = fmt.Errorf("cancel order %s failed: %w", order.ClientOrderID, err)
+			continue
+		}
+		cancelled++
+	}
+	return cancelled, lastErr
+}
+
 // SyncOrderStatus 同步订单状态（从 Gateway 同步到 OrderRepo）
+// 未确认的下单意图超过 IntentTimeout 仍查无此单时标记为拒绝
 func (m *Manager) SyncOrderStatus(ctx context.Context, clientOrderID string) error {
+	return m.syncOrderStatus(ctx, clientOrderID, m.config.IntentTimeout)
+}
+
+// syncOrderStatus 同步订单状态（intentTimeout 为未确认意图查无此单时判定为未下单的最短时长）
+func (m *Manager) syncOrderStatus(ctx context.Context, clientOrderID string, intentTimeout time.Duration) error {
 	// 1. 从 Gateway 查询最新状态
 	gatewayOrder, err := m.spotGateway.GetOrder(ctx, clientOrderID)
 	if err != nil {
+		if errors.Is(err, port.ErrExchangeOrderNotFound) {
+			return m.expireIntent(ctx, clientOrderID, intentTimeout, err)
+		}
 		return fmt.Errorf("get order from gateway failed: %w", err)
 	}
 
 	// 2. 从 OrderRepo 获取本地状态
-	localOrder, err := m.orderRepo.GetOrder(ctx, clientOrderID)
-	if err != nil {
+	if _, err := m.orderRepo.GetOrder(ctx, clientOrderID); err != nil {
 		// 本地不存在，直接保存
 		return m.orderRepo.SaveOrder(ctx, gatewayOrder)
 	}
 
-	// 3. 比较状态，如有变化则更新
-	if localOrder.Status != gatewayOrder.Status {
-		if err := m.orderRepo.UpdateOrderStatus(ctx, clientOrderID, gatewayOrder.Status); err != nil {
-			return fmt.Errorf("update order status failed: %w", err)
+	// 3. 按状态机应用交易所状态（乱序到达的过期回报直接忽略）
+	if _, err := m.applyUpdate(ctx, clientOrderID, syncUpdate(gatewayOrder)); err != nil {
+		if errors.Is(err, model.ErrStaleOrderUpdate) {
+			return nil
 		}
+		return fmt.Errorf("update order status failed: %w", err)
 	}
 
-	// 4. 更新成交数量
-	if !gatewayOrder.Filled.EQ(localOrder.Filled) {
-		if err := m.orderRepo.UpdateFilled(ctx, clientOrderID, gatewayOrder.Filled); err != nil {
-			return fmt.Errorf("update filled quantity failed: %w", err)
+	return nil
+}
+
+// ResolvePendingOrders 解析未确认的下单意图（启动时调用，返回已解析的数量）
+// 交易所存在该订单时按其状态确认；查无此单且写入超过 ResolveGrace 时标记为拒绝，
+// 宽限期内的意图可能仍在途（崩溃前发出的请求尚未被交易所处理），保留由自动同步按意图超时继续处理
+// 单笔失败不中断，返回最后一个错误
+func (m *Manager) ResolvePendingOrders(ctx context.Context) (int, error) {
+	orders, err := m.orderRepo.ListActiveOrders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list active orders failed: %w", err)
+	}
+
+	var (
+		resolved int
+		lastErr  error
+	)
+	for _, order := range orders {
+		if !order.Status.IsUnconfirmed() {
+			continue
 		}
+		err := m.syncOrderStatus(ctx, order.ClientOrderID, m.config.ResolveGrace)
+		if errors.Is(err, port.ErrExchangeOrderNotFound) {
+			continue // 宽限期内
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("resolve order %s failed: %w", order.ClientOrderID, err)
+			continue
+		}
+		resolved++
+	}
+	return resolved, lastErr
+}
+
+// confirmIntent 向交易所查询未确认的意图，存在时按交易所状态更新本地订单并返回
+func (m *Manager) confirmIntent(ctx context.Context, intent *model.Order) (*model.Order, error) {
+	gatewayOrder, err := m.spotGateway.GetOrder(ctx, intent.ClientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.applyUpdate(ctx, intent.ClientOrderID, syncUpdate(gatewayOrder)); err != nil && !errors.Is(err, model.ErrStaleOrderUpdate) {
+		return nil, fmt.Errorf("update order status failed: %w", err)
+	}
+	return m.orderRepo.GetOrder(ctx, intent.ClientOrderID)
+}
+
+// expireIntent 交易所查无此单：未确认的意图超过 intentTimeout 时标记为拒绝，否则返回 notFound（仍可能在途）
+func (m *Manager) expireIntent(ctx context.Context, clientOrderID string, intentTimeout time.Duration, notFound error) error {
+	order, err := m.orderRepo.GetOrder(ctx, clientOrderID)
+	if err != nil || !order.Status.IsUnconfirmed() || time.Since(order.CreatedAt) < intentTimeout {
+		return fmt.Errorf("get order from gateway failed: %w", notFound)
 	}
 
+	update := model.OrderUpdate{
+		Status: model.OrderStatusRejected,
+		Source: model.OrderEventSourceSync,
+		Reason: "order intent not found on exchange",
+	}
+	if _, err := m.applyUpdate(ctx, clientOrderID, update); err != nil && !errors.Is(err, model.ErrStaleOrderUpdate) {
+		return fmt.Errorf("update order status failed: %w", err)
+	}
 	return nil
 }
 
+// syncUpdate 按交易所查询结果构造订单更新
+func syncUpdate(gatewayOrder *model.Order) model.OrderUpdate {
+	return model.OrderUpdate{
+		Status:     gatewayOrder.Status,
+		ExchangeID: gatewayOrder.ExchangeID,
+		Filled:     gatewayOrder.Filled,
+		Sequence:   gatewayOrder.Sequence,
+		UpdateTime: gatewayOrder.ExchangeUpdateTime,
+		Source:     model.OrderEventSourceSync,
+	}
+}
+
+// isOutcomeUnknown 下单结果是否未知（网关明确标记或请求超时）
+func isOutcomeUnknown(err error) bool {
+	if errors.Is(err, port.ErrOrderOutcomeUnknown) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
 // SyncActiveOrders 同步所有活跃订单状态
 func (m *Manager) SyncActiveOrders(ctx context.Context) error {
 	// 1. 获取所有活跃订单
@@ -212,6 +502,16 @@ func (m *Manager) SyncActiveOrders(ctx context.Context) error {
 		}
 	}
 
+	// 3. 按最新状态处理超时挂单（撤单或改价）
+	if _, err := m.ExpireOrders(ctx, time.Now()); err != nil {
+		lastErr = err
+	}
+
+	// 4. 按子订单最新状态推进订单组
+	if err := m.SyncOrderLists(ctx); err != nil {
+		lastErr = err
+	}
+
 	return lastErr
 }
 
@@ -267,13 +567,33 @@ func (m *Manager) GetOrder(ctx context.Context, clientOrderID string) (*model.Or
 
 // PlaceOrderRequest 下单请求
 type PlaceOrderRequest struct {
-	ClientOrderID string
+	ClientOrderID string            // 客户端订单 ID（为空时按 Intent 生成，见 model.ClientOrderID）
+	Intent        model.OrderIntent // 下单意图（仅在生成客户端订单 ID 时使用，默认 MANUAL）
 	Symbol        string
 	Side          model.OrderSide
 	Type          model.OrderType
 	Price         model.Money
+	StopPrice     model.Money // 触发价（止损/止盈类条件单必填）
 	Quantity      model.Money
 	CurrentPrice  model.Money // 当前市价（用于风控计算）
 	AccountID     string
+	StrategyID    string      // 下单策略（绩效风控降级、风控状态分级统计，并写入 orders.strategy_id）
 	ProtectPrice  model.Money // 保护价
+	ReduceOnly    bool        // 只减仓（止损/止盈平仓）
+	Priority      Priority    // 订单优先级
+
+	// 挂单时效（由 OMS 同步时执行，见 ExpireOrders）
+	ExpireAt     time.Time          // 截止时间（good-till-time，零值表示不限）
+	MaxAge       time.Duration      // 最长挂单时长（零值表示不限）
+	ExpirePolicy model.ExpirePolicy // 超过最长挂单时长后的处理方式（零值按撤单处理）
 }
+
+// Priority 订单优先级
+type Priority int
+
+const (
+	// PriorityNormal 普通订单
+	PriorityNormal Priority = iota
+	// PriorityHigh 保护性平仓（止损/止盈），不接受风控降档
+	PriorityHigh
+)
@@ -2,14 +2,19 @@ package oms
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/iluyuns/alpha-trade/internal/core/killswitch"
+	"github.com/iluyuns/alpha-trade/internal/core/position"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
 	"github.com/iluyuns/alpha-trade/internal/infra/order"
 	"github.com/iluyuns/alpha-trade/internal/infra/risk"
+	"github.com/iluyuns/alpha-trade/internal/infra/tradinghalt"
 	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
 )
 
@@ -263,3 +268,553 @@ func TestManager_SyncActiveOrders(t *testing.T) {
 
 	t.Logf("Active orders after sync: %d", len(activeOrders))
 }
+
+func TestPositionExitAdapter_DuringCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	accountID := "exit-test-account"
+
+	exchange := mock.NewSpotExchange(map[string]model.Money{
+		"USDT": model.MustMoney("10000"),
+		"BTC":  model.MustMoney("0.5"),
+	})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("48000"))
+
+	orderRepo := order.NewMemoryRepo()
+	riskRepo := risk.NewMemoryRiskRepo()
+	riskMgr := risklogic.NewManager(riskRepo, risklogic.RiskConfig{
+		MaxSinglePositionPercent: 0.3,
+		MaxConsecutiveLosses:     3,
+	})
+
+	// 熔断中：开仓被拒，止损平仓放行
+	state := model.NewRiskState(accountID, model.MustMoney("10000"))
+	state.OpenCircuitBreaker(1 * time.Hour)
+	_ = riskRepo.SaveState(ctx, state)
+
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AutoSync: false})
+
+	_, err := oms.PlaceOrder(ctx, &PlaceOrderRequest{
+		ClientOrderID: "exit-open-order",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeMarket,
+		Quantity:      model.MustMoney("0.01"),
+		CurrentPrice:  model.MustMoney("48000"),
+		AccountID:     accountID,
+	})
+	if err == nil {
+		t.Fatal("opening order should be rejected while circuit breaker is open")
+	}
+
+	adapter := NewPositionExitAdapter(oms)
+	err = adapter.ExecuteExit(ctx, &position.ExitRequest{
+		Stop: model.StopLevel{
+			PositionID: "exit-position",
+			AccountID:  accountID,
+			Symbol:     "BTCUSDT",
+			Side:       model.OrderSideBuy,
+			Quantity:   model.MustMoney("0.5"), // 超过单标的仓位限制，平仓不受影响
+			StopLoss:   model.MustMoney("49000"),
+		},
+		Reason:    position.ExitStopLoss,
+		MarkPrice: model.MustMoney("48000"),
+	})
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+
+	orders, err := orderRepo.ListOrdersBySymbol(ctx, "BTCUSDT", 10)
+	if err != nil {
+		t.Fatalf("ListOrdersBySymbol failed: %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("expected 1 exit order, got %d", len(orders))
+	}
+	if exit := orders[0]; exit.Side != model.OrderSideSell || !exit.ReduceOnly || !exit.Quantity.EQ(model.MustMoney("0.5")) {
+		t.Errorf("unexpected exit order: side=%s reduceOnly=%v qty=%s", exit.Side, exit.ReduceOnly, exit.Quantity)
+	}
+}
+
+func TestPositionFlattenAdapter_KillSwitchWithoutStops(t *testing.T) {
+	ctx := context.Background()
+	accountID := "flatten-test-account"
+
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	orderRepo := order.NewMemoryRepo()
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AccountID: accountID})
+
+	// 策略开仓但未登记止损
+	if _, err := oms.PlaceOrder(ctx, &PlaceOrderRequest{
+		Symbol:       "BTCUSDT",
+		Side:         model.OrderSideBuy,
+		Type:         model.OrderTypeMarket,
+		Quantity:     model.MustMoney("0.02"),
+		CurrentPrice: model.MustMoney("50000"),
+		AccountID:    accountID,
+		StrategyID:   "ma_cross",
+		Intent:       model.OrderIntentEntry,
+	}); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	ledger := position.NewLedger(exchange, position.LedgerConfig{AccountID: accountID, Symbols: []string{"BTCUSDT"}})
+	ledger.SetOrderRepo(orderRepo)
+	if _, err := ledger.Sync(ctx, time.Now()); err != nil {
+		t.Fatalf("ledger Sync failed: %v", err)
+	}
+	if qty := ledger.Position("ma_cross", "BTCUSDT").Quantity; !qty.EQ(model.MustMoney("0.02")) {
+		t.Fatalf("ledger position = %s, want 0.02", qty)
+	}
+
+	stops := position.NewStopManager(NewPositionExitAdapter(oms), nil, position.StopConfig{})
+	flattener := NewPositionFlattenAdapter(oms, ledger)
+	flattener.SetStopManager(stops)
+	ks := killswitch.NewKillSwitch(tradinghalt.NewMemoryRepo(), riskMgr, killswitch.Config{})
+	ks.SetOrderCanceller(oms)
+	ks.SetPositionFlattener(flattener)
+
+	result := ks.Trigger(ctx, killswitch.TriggerRequest{UserID: 1, Reason: "test", Flatten: true}, time.Now())
+	if len(result.Errors) != 0 || result.FlattenedPositions != 1 {
+		t.Fatalf("Trigger flattened=%d errors=%v, want 1 position", result.FlattenedPositions, result.Errors)
+	}
+
+	orders, err := orderRepo.ListOrdersBySymbol(ctx, "BTCUSDT", 10)
+	if err != nil {
+		t.Fatalf("ListOrdersBySymbol failed: %v", err)
+	}
+	var exits []*model.Order
+	for _, o := range orders {
+		if o.ReduceOnly {
+			exits = append(exits, o)
+		}
+	}
+	if len(exits) != 1 {
+		t.Fatalf("expected 1 reduce-only exit order, got %d", len(exits))
+	}
+	if exit := exits[0]; exit.Side != model.OrderSideSell || exit.Type != model.OrderTypeMarket ||
+		!exit.Quantity.EQ(model.MustMoney("0.02")) || exit.StrategyID != "ma_cross" {
+		t.Errorf("unexpected exit order: side=%s type=%s qty=%s strategy=%s", exit.Side, exit.Type, exit.Quantity, exit.StrategyID)
+	}
+
+	// 账本同步前再次触发：平仓订单已在途，不重复下单
+	if result := ks.Trigger(ctx, killswitch.TriggerRequest{UserID: 1, Reason: "again", Flatten: true}, time.Now()); result.FlattenedPositions != 0 {
+		t.Errorf("retrigger flattened %d positions, want 0", result.FlattenedPositions)
+	}
+
+	if _, err := ledger.Sync(ctx, time.Now()); err != nil {
+		t.Fatalf("ledger Sync failed: %v", err)
+	}
+	if p := ledger.Position("ma_cross", "BTCUSDT"); !p.IsFlat() {
+		t.Errorf("position should be flat after kill switch, got %s", p.Quantity)
+	}
+}
+
+func TestManager_PlaceOrder_HalfSizeMode(t *testing.T) {
+	ctx := context.Background()
+	accountID := "half-size-account"
+
+	exchange := mock.NewSpotExchange(map[string]model.Money{
+		"USDT": model.MustMoney("10000"),
+	})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+
+	orderRepo := order.NewMemoryRepo()
+	riskRepo := risk.NewMemoryRiskRepo()
+	riskMgr := risklogic.NewManager(riskRepo, risklogic.RiskConfig{
+		MaxSinglePositionPercent: 0.3,
+	})
+	_ = riskRepo.SaveState(ctx, model.NewRiskState(accountID, model.MustMoney("10000")))
+
+	// 最近 10 笔仅 2 笔盈利，策略进入减半模式
+	tracker := risklogic.NewPerformanceTracker(nil, risklogic.PerformanceConfig{})
+	base := time.Now().Add(-time.Hour)
+	for i, pnl := range []string{"50", "-10", "-10", "-10", "-10", "50", "-10", "-10", "-10", "-10"} {
+		_ = tracker.Record(ctx, &model.Settlement{
+			StrategyID:  "SimpleVolatility",
+			RealizedPnL: model.MustMoney(pnl),
+			ClosedAt:    base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	riskMgr.SetPerformanceTracker(tracker)
+
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AutoSync: false})
+
+	placed, err := oms.PlaceOrder(ctx, &PlaceOrderRequest{
+		ClientOrderID: "half-size-order",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeMarket,
+		Quantity:      model.MustMoney("0.04"),
+		CurrentPrice:  model.MustMoney("50000"),
+		AccountID:     accountID,
+		StrategyID:    "SimpleVolatility",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if !placed.Quantity.EQ(model.MustMoney("0.02")) {
+		t.Errorf("half-size mode should halve quantity: got %s, want 0.02", placed.Quantity)
+	}
+
+	saved, _ := orderRepo.GetOrder(ctx, "half-size-order")
+	if saved == nil || saved.StrategyID != "SimpleVolatility" {
+		t.Errorf("expected order persisted with strategy id, got %+v", saved)
+	}
+}
+
+// cancelGateway 记录撤单请求的模拟交易所（Mock Exchange 订单立即成交，无法挂单）
+type cancelGateway struct {
+	*mock.SpotExchange
+	cancelled []string
+}
+
+func (g *cancelGateway) CancelOrder(ctx context.Context, req *port.SpotCancelOrderRequest) error {
+	if req.ClientOrderID == "cancel-all-fail" {
+		return fmt.Errorf("order %s not found", req.ClientOrderID)
+	}
+	g.cancelled = append(g.cancelled, req.ClientOrderID)
+	return nil
+}
+
+func TestManager_CancelAllOrders(t *testing.T) {
+	ctx := context.Background()
+
+	exchange := &cancelGateway{SpotExchange: mock.NewSpotExchange(map[string]model.Money{
+		"USDT": model.MustMoney("10000"),
+	})}
+	orderRepo := order.NewMemoryRepo()
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AutoSync: false})
+
+	for _, id := range []string{"cancel-all-1", "cancel-all-fail", "cancel-all-2"} {
+		err := orderRepo.SaveOrder(ctx, &model.Order{
+			ClientOrderID: id,
+			Symbol:        "BTCUSDT",
+			MarketType:    model.MarketTypeSpot,
+			Side:          model.OrderSideBuy,
+			Type:          model.OrderTypeLimit,
+			Price:         model.MustMoney("49000"),
+			Quantity:      model.MustMoney("0.01"),
+			Status:        model.OrderStatusPending,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("SaveOrder failed: %v", err)
+		}
+	}
+
+	// 单笔失败不中断其余撤单
+	cancelled, err := oms.CancelAllOrders(ctx)
+	if err == nil {
+		t.Error("expected error for failed cancel")
+	}
+	if cancelled != 2 || len(exchange.cancelled) != 2 {
+		t.Fatalf("expected 2 orders cancelled, got %d (%v)", cancelled, exchange.cancelled)
+	}
+
+	active, _ := orderRepo.ListActiveOrders(ctx)
+	if len(active) != 1 || active[0].ClientOrderID != "cancel-all-fail" {
+		t.Errorf("expected only failed order left active, got %d", len(active))
+	}
+}
+
+// stalePollGateway 返回固定订单快照的模拟交易所（模拟迟到的轮询结果）
+type stalePollGateway struct {
+	*mock.SpotExchange
+	snapshot *model.Order
+}
+
+func (g *stalePollGateway) GetOrder(ctx context.Context, clientOrderID string) (*model.Order, error) {
+	snapshot := *g.snapshot
+	return &snapshot, nil
+}
+
+func TestManager_SyncOrderStatus_IgnoresLatePoll(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	exchange := &stalePollGateway{SpotExchange: mock.NewSpotExchange(nil)}
+	orderRepo := order.NewMemoryRepo()
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AutoSync: false})
+
+	err := orderRepo.SaveOrder(ctx, &model.Order{
+		ClientOrderID:      "late-poll-order",
+		Symbol:             "BTCUSDT",
+		Quantity:           model.MustMoney("0.01"),
+		Filled:             model.MustMoney("0.01"),
+		Status:             model.OrderStatusFilled,
+		ExchangeUpdateTime: now,
+	})
+	if err != nil {
+		t.Fatalf("SaveOrder failed: %v", err)
+	}
+
+	// 更新时间早于已应用状态的轮询结果：忽略
+	exchange.snapshot = &model.Order{
+		ClientOrderID:      "late-poll-order",
+		Symbol:             "BTCUSDT",
+		Filled:             model.Zero(),
+		Status:             model.OrderStatusSubmitted,
+		ExchangeUpdateTime: now.Add(-time.Second),
+	}
+	if err := oms.SyncOrderStatus(ctx, "late-poll-order"); err != nil {
+		t.Fatalf("SyncOrderStatus failed: %v", err)
+	}
+
+	// 无更新时间的回退：状态机拒绝
+	exchange.snapshot.ExchangeUpdateTime = time.Time{}
+	if err := oms.SyncOrderStatus(ctx, "late-poll-order"); err == nil {
+		t.Error("expected error for illegal transition")
+	}
+
+	o, _ := orderRepo.GetOrder(ctx, "late-poll-order")
+	if !o.IsFilled() {
+		t.Errorf("Order should stay filled: status=%s", o.Status)
+	}
+	events, _ := orderRepo.ListOrderEvents(ctx, "late-poll-order")
+	if len(events) != 1 {
+		t.Errorf("expected only creation event, got %d", len(events))
+	}
+}
+
+func TestManager_PlaceOrder_BlockedWhileReconciling(t *testing.T) {
+	ctx := context.Background()
+
+	exchange := mock.NewSpotExchange(map[string]model.Money{
+		"USDT": model.MustMoney("10000"),
+		"BTC":  model.MustMoney("1"),
+	})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(exchange, order.NewMemoryRepo(), riskMgr, Config{AutoSync: false})
+	oms.SetReconciling(true)
+
+	req := &PlaceOrderRequest{
+		Symbol:       "BTCUSDT",
+		Side:         model.OrderSideBuy,
+		Type:         model.OrderTypeMarket,
+		Quantity:     model.MustMoney("0.01"),
+		CurrentPrice: model.MustMoney("50000"),
+		AccountID:    "reconcile-account",
+	}
+	if _, err := oms.PlaceOrder(ctx, req); !errors.Is(err, ErrReconciling) {
+		t.Fatalf("expected ErrReconciling, got %v", err)
+	}
+
+	// 只减仓订单不受对账暂停限制
+	exit := *req
+	exit.Side = model.OrderSideSell
+	exit.ReduceOnly = true
+	if _, err := oms.PlaceOrder(ctx, &exit); err != nil {
+		t.Fatalf("reduce-only order should pass: %v", err)
+	}
+
+	oms.SetReconciling(false)
+	if _, err := oms.PlaceOrder(ctx, req); err != nil {
+		t.Fatalf("PlaceOrder failed after reconcile: %v", err)
+	}
+}
+
+// timeoutGateway 下单已送达交易所但响应超时的模拟交易所
+type timeoutGateway struct {
+	*mock.SpotExchange
+	repo      port.OrderRepo
+	placed    int
+	intentErr error // 网关调用时本地意图的检查结果
+}
+
+func (g *timeoutGateway) PlaceOrder(ctx context.Context, req *port.SpotPlaceOrderRequest) (*model.Order, error) {
+	g.placed++
+	if intent, err := g.repo.GetOrder(ctx, req.ClientOrderID); err != nil {
+		g.intentErr = err
+	} else if intent.Status != model.OrderStatusPending {
+		g.intentErr = fmt.Errorf("intent status %s, want PENDING", intent.Status)
+	}
+	if _, err := g.SpotExchange.PlaceOrder(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, context.DeadlineExceeded
+}
+
+func TestManager_PlaceOrder_TimeoutIsUnknownAndRetrySafe(t *testing.T) {
+	ctx := context.Background()
+
+	orderRepo := order.NewMemoryRepo()
+	exchange := &timeoutGateway{
+		SpotExchange: mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")}),
+		repo:         orderRepo,
+	}
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AutoSync: false})
+
+	req := &PlaceOrderRequest{
+		ClientOrderID: "timeout-order",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeMarket,
+		Quantity:      model.MustMoney("0.01"),
+		CurrentPrice:  model.MustMoney("50000"),
+		AccountID:     "intent-account",
+	}
+	if _, err := oms.PlaceOrder(ctx, req); !errors.Is(err, port.ErrOrderOutcomeUnknown) {
+		t.Fatalf("expected ErrOrderOutcomeUnknown, got %v", err)
+	}
+	if exchange.intentErr != nil {
+		t.Fatalf("intent not persisted before gateway call: %v", exchange.intentErr)
+	}
+
+	saved, err := orderRepo.GetOrder(ctx, "timeout-order")
+	if err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+	if saved.Status != model.OrderStatusUnknown {
+		t.Errorf("status = %s, want UNKNOWN", saved.Status)
+	}
+
+	// 相同 ClientOrderID 重试：向交易所查询确认，不重复下单
+	retried, err := oms.PlaceOrder(ctx, req)
+	if err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if exchange.placed != 1 {
+		t.Errorf("gateway PlaceOrder called %d times, want 1", exchange.placed)
+	}
+	if !retried.IsFilled() || retried.ExchangeID == "" {
+		t.Errorf("retry should return confirmed order: status=%s exchangeID=%q", retried.Status, retried.ExchangeID)
+	}
+
+	events, _ := orderRepo.ListOrderEvents(ctx, "timeout-order")
+	var path []string
+	for _, e := range events {
+		path = append(path, e.ToStatus.String())
+	}
+	if got := fmt.Sprint(path); got != "[PENDING UNKNOWN FILLED]" {
+		t.Errorf("event path = %s", got)
+	}
+}
+
+func TestManager_ResolvePendingOrders(t *testing.T) {
+	ctx := context.Background()
+
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	orderRepo := order.NewMemoryRepo()
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AutoSync: false})
+
+	// 崩溃前：一笔已送达交易所但未确认，一笔早已写入但未送达，一笔刚写入（宽限期内，请求可能仍在途）
+	now := time.Now()
+	createdAt := map[string]time.Time{
+		"crash-sent":    now,
+		"crash-unsent":  now.Add(-2 * time.Minute),
+		"recent-unsent": now,
+	}
+	for id, created := range createdAt {
+		err := orderRepo.SaveOrder(ctx, &model.Order{
+			ClientOrderID: id,
+			Symbol:        "BTCUSDT",
+			Side:          model.OrderSideBuy,
+			Type:          model.OrderTypeMarket,
+			Quantity:      model.MustMoney("0.01"),
+			Filled:        model.Zero(),
+			Status:        model.OrderStatusPending,
+			CreatedAt:     created,
+		})
+		if err != nil {
+			t.Fatalf("SaveOrder failed: %v", err)
+		}
+	}
+	_, err := exchange.PlaceOrder(ctx, &port.SpotPlaceOrderRequest{
+		ClientOrderID: "crash-sent",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeMarket,
+		Quantity:      model.MustMoney("0.01"),
+	})
+	if err != nil {
+		t.Fatalf("exchange PlaceOrder failed: %v", err)
+	}
+
+	// 运行期间同步：刚写入的意图可能仍在途，查无此单时不判定
+	if err := oms.SyncOrderStatus(ctx, "recent-unsent"); !errors.Is(err, port.ErrExchangeOrderNotFound) {
+		t.Errorf("expected ErrExchangeOrderNotFound within intent timeout, got %v", err)
+	}
+
+	resolved, err := oms.ResolvePendingOrders(ctx)
+	if err != nil {
+		t.Fatalf("ResolvePendingOrders failed: %v", err)
+	}
+	if resolved != 2 {
+		t.Errorf("resolved = %d, want 2", resolved)
+	}
+
+	sent, _ := orderRepo.GetOrder(ctx, "crash-sent")
+	if !sent.IsFilled() {
+		t.Errorf("crash-sent status = %s, want FILLED", sent.Status)
+	}
+	unsent, _ := orderRepo.GetOrder(ctx, "crash-unsent")
+	if unsent.Status != model.OrderStatusRejected {
+		t.Errorf("crash-unsent status = %s, want REJECTED", unsent.Status)
+	}
+	recent, _ := orderRepo.GetOrder(ctx, "recent-unsent")
+	if recent.Status != model.OrderStatusPending {
+		t.Errorf("recent-unsent status = %s, want PENDING within resolve grace", recent.Status)
+	}
+}
+
+func TestManager_RetryResubmitsPersistedIntent(t *testing.T) {
+	ctx := context.Background()
+
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	orderRepo := order.NewMemoryRepo()
+	riskRepo := risk.NewMemoryRiskRepo()
+	riskMgr := risklogic.NewManager(riskRepo, risklogic.RiskConfig{MaxSinglePositionPercent: 1})
+	_ = riskRepo.SaveState(ctx, model.NewRiskState("retry-account", model.MustMoney("10000")))
+	oms := NewManager(exchange, orderRepo, riskMgr, Config{AutoSync: false})
+
+	// 崩溃前写入的意图未送达交易所
+	err := orderRepo.SaveOrder(ctx, &model.Order{
+		ClientOrderID: "retry-intent",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeLimit,
+		Price:         model.MustMoney("49000"),
+		Quantity:      model.MustMoney("0.01"),
+		Filled:        model.Zero(),
+		Status:        model.OrderStatusPending,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("SaveOrder failed: %v", err)
+	}
+
+	// 重试请求的数量与价格已重新计算：按意图记录的内容提交
+	placed, err := oms.PlaceOrder(ctx, &PlaceOrderRequest{
+		ClientOrderID: "retry-intent",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeLimit,
+		Price:         model.MustMoney("49500"),
+		Quantity:      model.MustMoney("0.02"),
+		CurrentPrice:  model.MustMoney("50000"),
+		AccountID:     "retry-account",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder retry failed: %v", err)
+	}
+	if !placed.Quantity.EQ(model.MustMoney("0.01")) || !placed.Price.EQ(model.MustMoney("49000")) {
+		t.Errorf("resubmitted %s @ %s, want persisted 0.01 @ 49000", placed.Quantity, placed.Price)
+	}
+	onExchange, err := exchange.GetOrder(ctx, "retry-intent")
+	if err != nil {
+		t.Fatalf("exchange GetOrder failed: %v", err)
+	}
+	if !onExchange.Quantity.EQ(model.MustMoney("0.01")) || !onExchange.Price.EQ(model.MustMoney("49000")) {
+		t.Errorf("exchange order %s @ %s, want 0.01 @ 49000", onExchange.Quantity, onExchange.Price)
+	}
+}
@@ -0,0 +1,613 @@
+package oms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	riskmgr "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+var (
+	// ErrOrderListsDisabled 未设置订单组仓储，不支持 OCO 与括号单
+	ErrOrderListsDisabled = errors.New("oms: order lists are not enabled")
+
+	// ErrInvalidOrderList 止盈止损价格不合法（平多要求止盈价高于止损价，平空相反）
+	ErrInvalidOrderList = errors.New("oms: invalid order list")
+
+	// ErrOrderListClosed 订单组已完结
+	ErrOrderListClosed = errors.New("oms: order list already closed")
+)
+
+// ExitPrices 止盈止损价格（OCO 与括号单共用）
+type ExitPrices struct {
+	TakeProfitPrice model.Money // 止盈限价
+	StopPrice       model.Money // 止损触发价
+	StopLimitPrice  model.Money // 止损触发后的限价（零值表示市价止损）
+}
+
+// PlaceOCORequest OCO 下单请求（为已有持仓挂出止盈止损）
+type PlaceOCORequest struct {
+	ExitPrices
+	Symbol       string
+	Side         model.OrderSide // 止盈止损方向（平多为卖出）
+	Quantity     model.Money
+	CurrentPrice model.Money // 当前市价（用于风控计算）
+	AccountID    string
+	StrategyID   string
+}
+
+// PlaceBracketRequest 括号单下单请求（入场单成交后按成交数量挂出止盈止损 OCO）
+type PlaceBracketRequest struct {
+	ExitPrices
+	Symbol       string
+	Side         model.OrderSide // 入场方向
+	Type         model.OrderType // 入场单类型
+	Price        model.Money     // 入场限价
+	Quantity     model.Money
+	CurrentPrice model.Money // 当前市价（用于风控计算）
+	AccountID    string
+	StrategyID   string
+	ProtectPrice model.Money // 入场单保护价
+}
+
+// SetOrderListRepo 设置订单组仓储（启用 OCO 与括号单）
+func (m *Manager) SetOrderListRepo(repo port.OrderListRepo) {
+	m.orderListRepo = repo
+}
+
+// PlaceOCO 为已有持仓挂出止盈止损 OCO
+// 网关支持原生 OCO 时由交易所挂出两条腿并互相撤销，否则本地模拟：挂出止盈限价单，止损由 CheckTriggers 触发
+func (m *Manager) PlaceOCO(ctx context.Context, req *PlaceOCORequest) (*model.OrderList, error) {
+	if m.orderListRepo == nil {
+		return nil, ErrOrderListsDisabled
+	}
+	if !req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: quantity is required", ErrInvalidOrderList)
+	}
+	if err := req.ExitPrices.validate(req.Side); err != nil {
+		return nil, err
+	}
+
+	listID, err := model.NewClientOrderID(model.OrderIntentExit, req.StrategyID, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("generate list id failed: %w", err)
+	}
+	now := time.Now()
+	list := &model.OrderList{
+		ListID:          listID,
+		Type:            model.OrderListTypeOCO,
+		Status:          model.OrderListStatusPending,
+		Symbol:          req.Symbol,
+		AccountID:       req.AccountID,
+		StrategyID:      req.StrategyID,
+		ExitSide:        req.Side,
+		Quantity:        req.Quantity,
+		TakeProfitPrice: req.TakeProfitPrice,
+		StopPrice:       req.StopPrice,
+		StopLimitPrice:  req.StopLimitPrice,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	m.listMu.Lock()
+	defer m.listMu.Unlock()
+
+	if err := m.armOrderList(ctx, list, req.CurrentPrice); err != nil {
+		return list, err
+	}
+	return list, nil
+}
+
+// PlaceBracket 括号单：提交入场单，成交后由 SyncOrderLists 按成交数量挂出止盈止损
+// 入场单下单失败时订单组记为已撤销；入场单当场成交时立即挂出止盈止损
+func (m *Manager) PlaceBracket(ctx context.Context, req *PlaceBracketRequest) (*model.OrderList, error) {
+	if m.orderListRepo == nil {
+		return nil, ErrOrderListsDisabled
+	}
+	exitSide := model.OrderSideSell
+	if req.Side == model.OrderSideSell {
+		exitSide = model.OrderSideBuy
+	}
+	if err := req.ExitPrices.validate(exitSide); err != nil {
+		return nil, err
+	}
+
+	listID, err := model.NewClientOrderID(model.OrderIntentEntry, req.StrategyID, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("generate list id failed: %w", err)
+	}
+	entryID, err := model.NewClientOrderID(model.OrderIntentEntry, req.StrategyID, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("generate client order id failed: %w", err)
+	}
+	now := time.Now()
+	list := &model.OrderList{
+		ListID:             listID,
+		Type:               model.OrderListTypeBracket,
+		Status:             model.OrderListStatusPending,
+		Symbol:             req.Symbol,
+		AccountID:          req.AccountID,
+		StrategyID:         req.StrategyID,
+		EntryClientOrderID: entryID,
+		ExitSide:           exitSide,
+		Quantity:           model.Zero(),
+		TakeProfitPrice:    req.TakeProfitPrice,
+		StopPrice:          req.StopPrice,
+		StopLimitPrice:     req.StopLimitPrice,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	m.listMu.Lock()
+	defer m.listMu.Unlock()
+
+	// 先持久化订单组，入场单成交后重启仍能挂出止盈止损
+	if err := m.orderListRepo.SaveOrderList(ctx, list); err != nil {
+		return nil, fmt.Errorf("save order list failed: %w", err)
+	}
+
+	entry, err := m.PlaceOrder(ctx, &PlaceOrderRequest{
+		ClientOrderID: entryID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Price:         req.Price,
+		Quantity:      req.Quantity,
+		CurrentPrice:  req.CurrentPrice,
+		AccountID:     req.AccountID,
+		StrategyID:    req.StrategyID,
+		ProtectPrice:  req.ProtectPrice,
+	})
+	if err != nil {
+		// 入场单结果未知时订单组保持 PENDING，由同步按入场单的确认结果处理
+		if !errors.Is(err, port.ErrOrderOutcomeUnknown) {
+			m.closeOrderList(context.WithoutCancel(ctx), list, model.OrderListStatusCancelled, "entry rejected: "+err.Error())
+		}
+		return list, err
+	}
+
+	if entry.IsFilled() {
+		list.Quantity = entry.Filled
+		if err := m.armOrderList(ctx, list, req.CurrentPrice); err != nil {
+			return list, err
+		}
+	}
+	return list, nil
+}
+
+// CancelOrderList 撤销订单组（括号单撤销未成交的入场单，已挂出的止盈止损一并撤销）
+func (m *Manager) CancelOrderList(ctx context.Context, listID string) error {
+	if m.orderListRepo == nil {
+		return ErrOrderListsDisabled
+	}
+
+	m.listMu.Lock()
+	defer m.listMu.Unlock()
+
+	list, err := m.orderListRepo.GetOrderList(ctx, listID)
+	if err != nil {
+		return fmt.Errorf("get order list failed: %w", err)
+	}
+	if !list.IsActive() {
+		return fmt.Errorf("%w: %s is %s", ErrOrderListClosed, listID, list.Status)
+	}
+
+	switch {
+	case list.Status == model.OrderListStatusPending && list.EntryClientOrderID != "":
+		if err := m.cancelIfActive(ctx, list.EntryClientOrderID); err != nil {
+			return err
+		}
+	case list.Native:
+		gateway, ok := m.spotGateway.(port.SpotOCOGateway)
+		if !ok {
+			return fmt.Errorf("gateway does not support native oco: %s", listID)
+		}
+		if err := gateway.CancelOCO(ctx, list.Symbol, list.ListID); err != nil {
+			return fmt.Errorf("gateway cancel oco failed: %w", err)
+		}
+		// 两条腿的状态以交易所为准（撤销期间已成交时状态机拒绝回退）
+		for _, id := range []string{list.TakeProfitClientOrderID, list.StopLossClientOrderID} {
+			update := model.OrderUpdate{Status: model.OrderStatusCancelled, Source: model.OrderEventSourceCancel, Reason: "order list cancelled"}
+			_, _ = m.applyUpdate(ctx, id, update)
+		}
+	default:
+		if err := m.cancelIfActive(ctx, list.TakeProfitClientOrderID); err != nil {
+			return err
+		}
+	}
+
+	return m.closeOrderList(ctx, list, model.OrderListStatusCancelled, "cancelled")
+}
+
+// CheckTriggers 按最新价格检查本地模拟的止损（返回触发的订单组数量）
+// 触发时先撤销止盈腿，再以预生成的 ClientOrderID 按剩余数量提交只减仓止损单（价格跳空越过触发价同样触发）
+// 单笔失败不中断，返回最后一个错误
+func (m *Manager) CheckTriggers(ctx context.Context, symbol string, price model.Money) (int, error) {
+	if m.orderListRepo == nil {
+		return 0, nil
+	}
+
+	m.listMu.Lock()
+	defer m.listMu.Unlock()
+
+	lists, err := m.orderListRepo.ListActiveOrderLists(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list active order lists failed: %w", err)
+	}
+
+	var (
+		triggered int
+		lastErr   error
+	)
+	for _, list := range lists {
+		if list.Symbol != symbol || list.Status != model.OrderListStatusActive || list.Native || !list.StopTriggered(price) {
+			continue
+		}
+		if err := m.triggerStop(ctx, list, price); err != nil {
+			lastErr = fmt.Errorf("trigger stop of %s failed: %w", list.ListID, err)
+			continue
+		}
+		triggered++
+	}
+	return triggered, lastErr
+}
+
+// SyncOrderLists 按子订单状态推进订单组（由 SyncActiveOrders 在同步订单后调用）
+//   - 括号单入场单成交：按成交数量挂出止盈止损；未成交即完结：订单组撤销
+//   - 原生 OCO 任一腿成交：订单组完结（另一腿由交易所撤销）
+//   - 本地模拟止盈腿成交：订单组完结，止损随之解除
+//
+// 单笔失败不中断，返回最后一个错误
+func (m *Manager) SyncOrderLists(ctx context.Context) error {
+	if m.orderListRepo == nil {
+		return nil
+	}
+
+	m.listMu.Lock()
+	defer m.listMu.Unlock()
+
+	lists, err := m.orderListRepo.ListActiveOrderLists(ctx)
+	if err != nil {
+		return fmt.Errorf("list active order lists failed: %w", err)
+	}
+
+	var lastErr error
+	for _, list := range lists {
+		var err error
+		switch {
+		case list.Status == model.OrderListStatusPending && list.EntryClientOrderID != "":
+			err = m.syncBracketEntry(ctx, list)
+		case list.Native:
+			err = m.syncNativeLegs(ctx, list)
+		default:
+			err = m.syncEmulatedLegs(ctx, list)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("sync order list %s failed: %w", list.ListID, err)
+		}
+	}
+	return lastErr
+}
+
+// armOrderList 挂出止盈止损（写前持久化子订单 ID，结果未知时订单组保持 ACTIVE 由同步确认）
+func (m *Manager) armOrderList(ctx context.Context, list *model.OrderList, currentPrice model.Money) error {
+	tpID, err := model.NewClientOrderID(model.OrderIntentExit, list.StrategyID, list.Symbol)
+	if err != nil {
+		return fmt.Errorf("generate client order id failed: %w", err)
+	}
+	slID, err := model.NewClientOrderID(model.OrderIntentStop, list.StrategyID, list.Symbol)
+	if err != nil {
+		return fmt.Errorf("generate client order id failed: %w", err)
+	}
+
+	ocoGateway, native := m.spotGateway.(port.SpotOCOGateway)
+	list.TakeProfitClientOrderID = tpID
+	list.StopLossClientOrderID = slID
+	list.Native = native
+	list.Status = model.OrderListStatusActive
+	list.UpdatedAt = time.Now()
+	if err := m.orderListRepo.SaveOrderList(ctx, list); err != nil {
+		return fmt.Errorf("save order list failed: %w", err)
+	}
+
+	if native {
+		err = m.placeNativeOCO(ctx, ocoGateway, list, currentPrice)
+	} else {
+		err = m.placeTakeProfit(ctx, list, currentPrice)
+	}
+	recordCtx := context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+		return m.orderListRepo.SaveOrderList(recordCtx, list)
+	case errors.Is(err, port.ErrOrderOutcomeUnknown):
+		return err
+	default:
+		m.closeOrderList(recordCtx, list, model.OrderListStatusCancelled, "arm failed: "+err.Error())
+		return err
+	}
+}
+
+// placeNativeOCO 由交易所挂出 OCO（两条腿写前记录为 PENDING 意图）
+func (m *Manager) placeNativeOCO(ctx context.Context, gateway port.SpotOCOGateway, list *model.OrderList, currentPrice model.Money) error {
+	// 止盈止损为只减仓订单，按止盈腿检查
+	decision, err := m.riskMgr.CheckPreTrade(ctx, &riskmgr.OrderContext{
+		ClientOrderID: list.TakeProfitClientOrderID,
+		Symbol:        list.Symbol,
+		MarketType:    model.MarketTypeSpot,
+		Side:          list.ExitSide,
+		Type:          model.OrderTypeLimit,
+		Price:         list.TakeProfitPrice,
+		Quantity:      list.Quantity,
+		CurrentPrice:  currentPrice,
+		AccountID:     list.AccountID,
+		ReduceOnly:    true,
+		StrategyID:    list.StrategyID,
+	})
+	if err != nil {
+		return fmt.Errorf("risk check failed: %w", err)
+	}
+	if !decision.IsAllowed() {
+		return fmt.Errorf("%w by risk manager: %s", riskmgr.ErrOrderRejected, decision.Reason)
+	}
+
+	// 原生止损腿由交易所按触发价触发
+	stopType := model.OrderTypeStopMarket
+	if !list.StopLimitPrice.IsZero() {
+		stopType = model.OrderTypeStopLimit
+	}
+	stopLoss := exitOrder(list, list.StopLossClientOrderID, stopType, list.StopLimitPrice)
+	stopLoss.StopPrice = list.StopPrice
+	legs := []*model.Order{
+		exitOrder(list, list.TakeProfitClientOrderID, model.OrderTypeLimit, list.TakeProfitPrice),
+		stopLoss,
+	}
+	for _, leg := range legs {
+		if err := m.orderRepo.SaveOrder(ctx, leg); err != nil {
+			return fmt.Errorf("save order intent failed: %w", err)
+		}
+	}
+
+	result, err := gateway.PlaceOCO(ctx, &port.SpotOCORequest{
+		ListClientOrderID:       list.ListID,
+		Symbol:                  list.Symbol,
+		Side:                    list.ExitSide,
+		Quantity:                list.Quantity,
+		TakeProfitClientOrderID: list.TakeProfitClientOrderID,
+		TakeProfitPrice:         list.TakeProfitPrice,
+		StopLossClientOrderID:   list.StopLossClientOrderID,
+		StopPrice:               list.StopPrice,
+		StopLimitPrice:          list.StopLimitPrice,
+	})
+	recordCtx := context.WithoutCancel(ctx)
+	if err != nil {
+		status := model.OrderStatusRejected
+		if isOutcomeUnknown(err) {
+			status = model.OrderStatusUnknown
+			err = fmt.Errorf("gateway place oco failed: %w: %s: %v", port.ErrOrderOutcomeUnknown, list.ListID, err)
+		} else {
+			err = fmt.Errorf("gateway place oco failed: %w", err)
+		}
+		for _, leg := range legs {
+			update := model.OrderUpdate{Status: status, Source: model.OrderEventSourceSubmit, Reason: err.Error()}
+			_, _ = m.applyUpdate(recordCtx, leg.ClientOrderID, update)
+		}
+		return err
+	}
+
+	list.ExchangeListID = result.ExchangeListID
+	for _, order := range []*model.Order{result.TakeProfit, result.StopLoss} {
+		if order == nil {
+			continue
+		}
+		update := model.OrderUpdate{
+			Status:     order.Status,
+			ExchangeID: order.ExchangeID,
+			Filled:     order.Filled,
+			Source:     model.OrderEventSourceSubmit,
+			Reason:     "oco " + list.ListID,
+		}
+		if _, err := m.applyUpdate(recordCtx, order.ClientOrderID, update); err != nil && !errors.Is(err, model.ErrStaleOrderUpdate) {
+			return fmt.Errorf("save order failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// placeTakeProfit 本地模拟：挂出只减仓止盈限价单（止损由 CheckTriggers 触发）
+func (m *Manager) placeTakeProfit(ctx context.Context, list *model.OrderList, currentPrice model.Money) error {
+	_, err := m.PlaceOrder(ctx, &PlaceOrderRequest{
+		ClientOrderID: list.TakeProfitClientOrderID,
+		Symbol:        list.Symbol,
+		Side:          list.ExitSide,
+		Type:          model.OrderTypeLimit,
+		Price:         list.TakeProfitPrice,
+		Quantity:      list.Quantity,
+		CurrentPrice:  currentPrice,
+		AccountID:     list.AccountID,
+		StrategyID:    list.StrategyID,
+		ReduceOnly:    true,
+		Priority:      PriorityHigh,
+	})
+	return err
+}
+
+// triggerStop 本地模拟的止损触发：撤销止盈腿后按剩余数量提交止损单
+func (m *Manager) triggerStop(ctx context.Context, list *model.OrderList, price model.Money) error {
+	// 1. 撤销止盈腿（撤单失败时按交易所状态确认，止盈已成交则订单组完结）
+	remaining := list.Quantity
+	tp, err := m.orderRepo.GetOrder(ctx, list.TakeProfitClientOrderID)
+	switch {
+	case err == nil:
+		if tp.IsActive() {
+			if err := m.CancelOrder(ctx, tp.ClientOrderID); err != nil {
+				_ = m.SyncOrderStatus(ctx, tp.ClientOrderID)
+			}
+			if tp, err = m.orderRepo.GetOrder(ctx, tp.ClientOrderID); err != nil {
+				return fmt.Errorf("get order failed: %w", err)
+			}
+		}
+		if tp.IsFilled() {
+			return m.closeOrderList(ctx, list, model.OrderListStatusDone, "take profit filled")
+		}
+		if tp.IsActive() {
+			return fmt.Errorf("cancel take profit %s failed, status %s", tp.ClientOrderID, tp.Status)
+		}
+		remaining = remaining.Sub(tp.Filled)
+	case !errors.Is(err, port.ErrOrderNotFound):
+		return fmt.Errorf("get order failed: %w", err)
+	}
+
+	// 2. 提交止损单（预生成的 ClientOrderID 保证重试幂等）
+	orderType := model.OrderTypeMarket
+	if !list.StopLimitPrice.IsZero() {
+		orderType = model.OrderTypeLimit
+	}
+	_, err = m.PlaceOrder(ctx, &PlaceOrderRequest{
+		ClientOrderID: list.StopLossClientOrderID,
+		Symbol:        list.Symbol,
+		Side:          list.ExitSide,
+		Type:          orderType,
+		Price:         list.StopLimitPrice,
+		Quantity:      remaining,
+		CurrentPrice:  price,
+		AccountID:     list.AccountID,
+		StrategyID:    list.StrategyID,
+		ReduceOnly:    true,
+		Priority:      PriorityHigh,
+	})
+	if err != nil {
+		if !errors.Is(err, port.ErrOrderOutcomeUnknown) {
+			// 止损单被拒绝：订单组撤销，避免每个行情反复提交同一笔被拒绝的订单
+			m.closeOrderList(context.WithoutCancel(ctx), list, model.OrderListStatusCancelled, "stop loss rejected: "+err.Error())
+		}
+		return err
+	}
+	return m.closeOrderList(ctx, list, model.OrderListStatusDone, "stop loss triggered at "+price.String())
+}
+
+// syncBracketEntry 括号单：入场单成交后挂出止盈止损
+func (m *Manager) syncBracketEntry(ctx context.Context, list *model.OrderList) error {
+	entry, err := m.orderRepo.GetOrder(ctx, list.EntryClientOrderID)
+	if err != nil {
+		return fmt.Errorf("get entry order failed: %w", err)
+	}
+	if !entry.IsClosed() {
+		return nil
+	}
+	if !entry.Filled.IsPositive() {
+		return m.closeOrderList(ctx, list, model.OrderListStatusCancelled, "entry "+entry.Status.String())
+	}
+
+	// 入场单部分成交后撤销的，按已成交数量保护
+	list.Quantity = entry.Filled
+	return m.armOrderList(ctx, list, list.TakeProfitPrice)
+}
+
+// syncNativeLegs 原生 OCO：任一腿成交即完结，两条腿均未成交即完结视为撤销
+func (m *Manager) syncNativeLegs(ctx context.Context, list *model.OrderList) error {
+	tp, err := m.orderRepo.GetOrder(ctx, list.TakeProfitClientOrderID)
+	if err != nil {
+		return fmt.Errorf("get take profit order failed: %w", err)
+	}
+	sl, err := m.orderRepo.GetOrder(ctx, list.StopLossClientOrderID)
+	if err != nil {
+		return fmt.Errorf("get stop loss order failed: %w", err)
+	}
+
+	switch {
+	case tp.IsFilled():
+		return m.closeOrderList(ctx, list, model.OrderListStatusDone, "take profit filled")
+	case sl.IsFilled():
+		return m.closeOrderList(ctx, list, model.OrderListStatusDone, "stop loss filled")
+	case tp.IsClosed() && sl.IsClosed():
+		return m.closeOrderList(ctx, list, model.OrderListStatusCancelled, "legs closed on exchange")
+	}
+	return nil
+}
+
+// syncEmulatedLegs 本地模拟：止盈腿成交或被撤销时订单组完结，止损随之解除；止盈腿未记录（挂出前崩溃）时重新挂出
+func (m *Manager) syncEmulatedLegs(ctx context.Context, list *model.OrderList) error {
+	tp, err := m.orderRepo.GetOrder(ctx, list.TakeProfitClientOrderID)
+	if errors.Is(err, port.ErrOrderNotFound) {
+		if err := m.placeTakeProfit(ctx, list, list.TakeProfitPrice); err != nil && !errors.Is(err, port.ErrOrderOutcomeUnknown) {
+			m.closeOrderList(context.WithoutCancel(ctx), list, model.OrderListStatusCancelled, "arm failed: "+err.Error())
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get take profit order failed: %w", err)
+	}
+
+	switch {
+	case tp.IsFilled():
+		return m.closeOrderList(ctx, list, model.OrderListStatusDone, "take profit filled")
+	case tp.IsClosed():
+		return m.closeOrderList(ctx, list, model.OrderListStatusCancelled, "take profit "+tp.Status.String())
+	}
+	return nil
+}
+
+// cancelIfActive 撤销仍活跃的子订单（已完结或未记录时跳过）
+func (m *Manager) cancelIfActive(ctx context.Context, clientOrderID string) error {
+	order, err := m.orderRepo.GetOrder(ctx, clientOrderID)
+	if errors.Is(err, port.ErrOrderNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get order failed: %w", err)
+	}
+	if !order.IsActive() {
+		return nil
+	}
+	return m.CancelOrder(ctx, clientOrderID)
+}
+
+// closeOrderList 订单组完结
+func (m *Manager) closeOrderList(ctx context.Context, list *model.OrderList, status model.OrderListStatus, reason string) error {
+	list.Status = status
+	list.Reason = reason
+	list.UpdatedAt = time.Now()
+	if err := m.orderListRepo.SaveOrderList(ctx, list); err != nil {
+		return fmt.Errorf("save order list failed: %w", err)
+	}
+	return nil
+}
+
+// exitOrder 构造订单组子订单的下单意图
+func exitOrder(list *model.OrderList, clientOrderID string, orderType model.OrderType, price model.Money) *model.Order {
+	now := time.Now()
+	return &model.Order{
+		ClientOrderID: clientOrderID,
+		Symbol:        list.Symbol,
+		MarketType:    model.MarketTypeSpot,
+		Side:          list.ExitSide,
+		Type:          orderType,
+		Price:         price,
+		Quantity:      list.Quantity,
+		Filled:        model.Zero(),
+		Status:        model.OrderStatusPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		ReduceOnly:    true,
+		StrategyID:    list.StrategyID,
+	}
+}
+
+// validate 校验止盈止损价格（exitSide 为止盈止损方向）
+func (p ExitPrices) validate(exitSide model.OrderSide) error {
+	if !p.TakeProfitPrice.IsPositive() || !p.StopPrice.IsPositive() {
+		return fmt.Errorf("%w: take profit and stop price are required", ErrInvalidOrderList)
+	}
+	if exitSide == model.OrderSideSell && !p.TakeProfitPrice.GT(p.StopPrice) {
+		return fmt.Errorf("%w: take profit %s must be above stop %s", ErrInvalidOrderList, p.TakeProfitPrice, p.StopPrice)
+	}
+	if exitSide == model.OrderSideBuy && !p.TakeProfitPrice.LT(p.StopPrice) {
+		return fmt.Errorf("%w: take profit %s must be below stop %s", ErrInvalidOrderList, p.TakeProfitPrice, p.StopPrice)
+	}
+	return nil
+}
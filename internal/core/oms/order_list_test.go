@@ -0,0 +1,290 @@
+package oms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
+	"github.com/iluyuns/alpha-trade/internal/infra/order"
+	"github.com/iluyuns/alpha-trade/internal/infra/orderlist"
+	"github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+// ocoGateway 支持原生 OCO 的模拟交易所（止盈腿为限价挂单，止损腿为止损条件单）
+type ocoGateway struct {
+	*mock.SpotExchange
+}
+
+func (g *ocoGateway) PlaceOCO(ctx context.Context, req *port.SpotOCORequest) (*port.SpotOCOResult, error) {
+	tp, err := g.PlaceOrder(ctx, &port.SpotPlaceOrderRequest{ClientOrderID: req.TakeProfitClientOrderID, Symbol: req.Symbol,
+		Side: req.Side, Type: model.OrderTypeLimit, Price: req.TakeProfitPrice, Quantity: req.Quantity})
+	if err != nil {
+		return nil, err
+	}
+	stopType := model.OrderTypeStopMarket
+	if !req.StopLimitPrice.IsZero() {
+		stopType = model.OrderTypeStopLimit
+	}
+	sl, err := g.PlaceOrder(ctx, &port.SpotPlaceOrderRequest{ClientOrderID: req.StopLossClientOrderID, Symbol: req.Symbol,
+		Side: req.Side, Type: stopType, Price: req.StopLimitPrice, StopPrice: req.StopPrice, Quantity: req.Quantity})
+	if err != nil {
+		return nil, err
+	}
+	return &port.SpotOCOResult{ExchangeListID: "OCO-1", TakeProfit: tp, StopLoss: sl}, nil
+}
+
+func (g *ocoGateway) CancelOCO(ctx context.Context, symbol, listClientOrderID string) error {
+	return fmt.Errorf("not implemented")
+}
+
+func newOrderListTestManager(gateway port.SpotGateway, exchange *mock.SpotExchange) (*Manager, port.OrderRepo, port.OrderListRepo) {
+	exchange.SetInstantFill(false)
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	orderRepo := order.NewMemoryRepo()
+	listRepo := orderlist.NewMemoryRepo()
+	riskMgr := risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+	oms := NewManager(gateway, orderRepo, riskMgr, Config{AutoSync: false})
+	oms.SetOrderListRepo(listRepo)
+	return oms, orderRepo, listRepo
+}
+
+func newTestExchange() *mock.SpotExchange {
+	return mock.NewSpotExchange(map[string]model.Money{
+		"USDT": model.MustMoney("10000"),
+		"BTC":  model.MustMoney("1"),
+	})
+}
+
+func exitRequest() *PlaceOCORequest {
+	return &PlaceOCORequest{
+		ExitPrices: ExitPrices{
+			TakeProfitPrice: model.MustMoney("52000"),
+			StopPrice:       model.MustMoney("49000"),
+		},
+		Symbol:       "BTCUSDT",
+		Side:         model.OrderSideSell,
+		Quantity:     model.MustMoney("0.1"),
+		CurrentPrice: model.MustMoney("50000"),
+		AccountID:    "oco-account",
+		StrategyID:   "grid",
+	}
+}
+
+func TestManager_PlaceOCO_EmulatedStopTrigger(t *testing.T) {
+	ctx := context.Background()
+	exchange := newTestExchange()
+	oms, orderRepo, listRepo := newOrderListTestManager(exchange, exchange)
+
+	list, err := oms.PlaceOCO(ctx, exitRequest())
+	if err != nil {
+		t.Fatalf("PlaceOCO failed: %v", err)
+	}
+	if list.Status != model.OrderListStatusActive || list.Native {
+		t.Fatalf("list status = %s, native = %v", list.Status, list.Native)
+	}
+	tp, err := orderRepo.GetOrder(ctx, list.TakeProfitClientOrderID)
+	if err != nil || tp.Status != model.OrderStatusSubmitted || !tp.ReduceOnly {
+		t.Fatalf("take profit leg = %+v (%v)", tp, err)
+	}
+
+	// 未触及止损价
+	if n, err := oms.CheckTriggers(ctx, "BTCUSDT", model.MustMoney("49500")); err != nil || n != 0 {
+		t.Fatalf("CheckTriggers above stop = %d (%v)", n, err)
+	}
+	if _, err := orderRepo.GetOrder(ctx, list.StopLossClientOrderID); !errors.Is(err, port.ErrOrderNotFound) {
+		t.Fatalf("stop loss should not be placed before trigger, got %v", err)
+	}
+
+	// 价格跳空越过止损价：撤销止盈腿并提交止损单
+	if n, err := oms.CheckTriggers(ctx, "BTCUSDT", model.MustMoney("48000")); err != nil || n != 1 {
+		t.Fatalf("CheckTriggers below stop = %d (%v)", n, err)
+	}
+	tp, _ = orderRepo.GetOrder(ctx, list.TakeProfitClientOrderID)
+	if tp.Status != model.OrderStatusCancelled {
+		t.Errorf("take profit status = %s, want CANCELLED", tp.Status)
+	}
+	sl, err := orderRepo.GetOrder(ctx, list.StopLossClientOrderID)
+	if err != nil || sl.Type != model.OrderTypeMarket || !sl.Quantity.EQ(model.MustMoney("0.1")) || !sl.ReduceOnly {
+		t.Fatalf("stop loss leg = %+v (%v)", sl, err)
+	}
+	saved, _ := listRepo.GetOrderList(ctx, list.ListID)
+	if saved.Status != model.OrderListStatusDone {
+		t.Errorf("list status = %s, want DONE", saved.Status)
+	}
+
+	// 已完结的订单组不再触发
+	if n, _ := oms.CheckTriggers(ctx, "BTCUSDT", model.MustMoney("47000")); n != 0 {
+		t.Errorf("completed list triggered again: %d", n)
+	}
+}
+
+func TestManager_PlaceOCO_TakeProfitFilled(t *testing.T) {
+	ctx := context.Background()
+	exchange := newTestExchange()
+	oms, orderRepo, listRepo := newOrderListTestManager(exchange, exchange)
+
+	list, err := oms.PlaceOCO(ctx, exitRequest())
+	if err != nil {
+		t.Fatalf("PlaceOCO failed: %v", err)
+	}
+
+	// 止盈成交：同步后订单组完结，止损随之解除
+	if err := exchange.FillOrder(list.TakeProfitClientOrderID); err != nil {
+		t.Fatalf("FillOrder failed: %v", err)
+	}
+	if err := oms.SyncActiveOrders(ctx); err != nil {
+		t.Fatalf("SyncActiveOrders failed: %v", err)
+	}
+	saved, _ := listRepo.GetOrderList(ctx, list.ListID)
+	if saved.Status != model.OrderListStatusDone || saved.Reason != "take profit filled" {
+		t.Fatalf("list = %s %q, want DONE", saved.Status, saved.Reason)
+	}
+	if n, _ := oms.CheckTriggers(ctx, "BTCUSDT", model.MustMoney("40000")); n != 0 {
+		t.Errorf("stop should be disarmed, triggered %d", n)
+	}
+	if _, err := orderRepo.GetOrder(ctx, list.StopLossClientOrderID); !errors.Is(err, port.ErrOrderNotFound) {
+		t.Errorf("stop loss should never be placed, got %v", err)
+	}
+}
+
+func TestManager_PlaceOCO_Native(t *testing.T) {
+	ctx := context.Background()
+	exchange := newTestExchange()
+	oms, orderRepo, listRepo := newOrderListTestManager(&ocoGateway{SpotExchange: exchange}, exchange)
+
+	req := exitRequest()
+	req.StopLimitPrice = model.MustMoney("48900")
+	list, err := oms.PlaceOCO(ctx, req)
+	if err != nil {
+		t.Fatalf("PlaceOCO failed: %v", err)
+	}
+	if !list.Native || list.ExchangeListID != "OCO-1" {
+		t.Fatalf("list native = %v, exchange list id = %q", list.Native, list.ExchangeListID)
+	}
+	for _, id := range []string{list.TakeProfitClientOrderID, list.StopLossClientOrderID} {
+		leg, err := orderRepo.GetOrder(ctx, id)
+		if err != nil || leg.Status != model.OrderStatusSubmitted || leg.ExchangeID == "" {
+			t.Fatalf("leg %s = %+v (%v)", id, leg, err)
+		}
+	}
+	stopLeg, _ := orderRepo.GetOrder(ctx, list.StopLossClientOrderID)
+	if stopLeg.Type != model.OrderTypeStopLimit || !stopLeg.StopPrice.EQ(req.StopPrice) || !stopLeg.Price.EQ(req.StopLimitPrice) {
+		t.Errorf("stop leg = %s stop %s limit %s, want STOP_LOSS_LIMIT stop %s limit %s",
+			stopLeg.Type, stopLeg.StopPrice, stopLeg.Price, req.StopPrice, req.StopLimitPrice)
+	}
+
+	// 原生 OCO 由交易所触发止损，本地不触发
+	if n, _ := oms.CheckTriggers(ctx, "BTCUSDT", model.MustMoney("48000")); n != 0 {
+		t.Fatalf("native list must not be triggered locally, got %d", n)
+	}
+
+	// 交易所止损腿成交并撤销止盈腿
+	if err := exchange.FillOrder(list.StopLossClientOrderID); err != nil {
+		t.Fatalf("FillOrder failed: %v", err)
+	}
+	if err := exchange.CancelOrder(ctx, &port.SpotCancelOrderRequest{ClientOrderID: list.TakeProfitClientOrderID}); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	if err := oms.SyncActiveOrders(ctx); err != nil {
+		t.Fatalf("SyncActiveOrders failed: %v", err)
+	}
+	saved, _ := listRepo.GetOrderList(ctx, list.ListID)
+	if saved.Status != model.OrderListStatusDone || saved.Reason != "stop loss filled" {
+		t.Errorf("list = %s %q, want DONE by stop loss", saved.Status, saved.Reason)
+	}
+}
+
+func TestManager_PlaceBracket(t *testing.T) {
+	ctx := context.Background()
+	exchange := newTestExchange()
+	oms, orderRepo, listRepo := newOrderListTestManager(exchange, exchange)
+
+	bracketReq := func() *PlaceBracketRequest {
+		return &PlaceBracketRequest{
+			ExitPrices: ExitPrices{
+				TakeProfitPrice: model.MustMoney("52000"),
+				StopPrice:       model.MustMoney("48000"),
+			},
+			Symbol:       "BTCUSDT",
+			Side:         model.OrderSideBuy,
+			Type:         model.OrderTypeLimit,
+			Price:        model.MustMoney("49500"),
+			Quantity:     model.MustMoney("0.05"),
+			CurrentPrice: model.MustMoney("50000"),
+			AccountID:    "bracket-account",
+			StrategyID:   "grid",
+		}
+	}
+
+	list, err := oms.PlaceBracket(ctx, bracketReq())
+	if err != nil {
+		t.Fatalf("PlaceBracket failed: %v", err)
+	}
+	if list.Status != model.OrderListStatusPending || list.ExitSide != model.OrderSideSell || list.TakeProfitClientOrderID != "" {
+		t.Fatalf("list = %s exit %s tp %q, want PENDING", list.Status, list.ExitSide, list.TakeProfitClientOrderID)
+	}
+
+	// 入场单成交后挂出止盈止损
+	if err := exchange.FillOrder(list.EntryClientOrderID); err != nil {
+		t.Fatalf("FillOrder failed: %v", err)
+	}
+	if err := oms.SyncActiveOrders(ctx); err != nil {
+		t.Fatalf("SyncActiveOrders failed: %v", err)
+	}
+	armed, _ := listRepo.GetOrderList(ctx, list.ListID)
+	if armed.Status != model.OrderListStatusActive || !armed.Quantity.EQ(model.MustMoney("0.05")) {
+		t.Fatalf("armed list = %s qty %s, want ACTIVE 0.05", armed.Status, armed.Quantity)
+	}
+	tp, err := orderRepo.GetOrder(ctx, armed.TakeProfitClientOrderID)
+	if err != nil || tp.Side != model.OrderSideSell || !tp.Price.EQ(model.MustMoney("52000")) {
+		t.Fatalf("take profit leg = %+v (%v)", tp, err)
+	}
+
+	// 撤销止盈止损
+	if err := oms.CancelOrderList(ctx, list.ListID); err != nil {
+		t.Fatalf("CancelOrderList failed: %v", err)
+	}
+	if tp, _ = orderRepo.GetOrder(ctx, armed.TakeProfitClientOrderID); tp.Status != model.OrderStatusCancelled {
+		t.Errorf("take profit status = %s, want CANCELLED", tp.Status)
+	}
+	if err := oms.CancelOrderList(ctx, list.ListID); !errors.Is(err, ErrOrderListClosed) {
+		t.Errorf("expected ErrOrderListClosed, got %v", err)
+	}
+
+	// 入场单未成交即撤销：订单组撤销，不挂出止盈止损
+	pending, err := oms.PlaceBracket(ctx, bracketReq())
+	if err != nil {
+		t.Fatalf("PlaceBracket failed: %v", err)
+	}
+	if err := exchange.CancelOrder(ctx, &port.SpotCancelOrderRequest{ClientOrderID: pending.EntryClientOrderID}); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	if err := oms.SyncActiveOrders(ctx); err != nil {
+		t.Fatalf("SyncActiveOrders failed: %v", err)
+	}
+	saved, _ := listRepo.GetOrderList(ctx, pending.ListID)
+	if saved.Status != model.OrderListStatusCancelled || saved.TakeProfitClientOrderID != "" {
+		t.Errorf("list = %s tp %q, want CANCELLED without exits", saved.Status, saved.TakeProfitClientOrderID)
+	}
+}
+
+func TestManager_PlaceOCO_InvalidPrices(t *testing.T) {
+	exchange := newTestExchange()
+	oms, _, _ := newOrderListTestManager(exchange, exchange)
+
+	req := exitRequest()
+	req.TakeProfitPrice = model.MustMoney("48000") // 平多止盈价低于止损价
+	if _, err := oms.PlaceOCO(context.Background(), req); !errors.Is(err, ErrInvalidOrderList) {
+		t.Errorf("expected ErrInvalidOrderList, got %v", err)
+	}
+
+	disabled := NewManager(exchange, order.NewMemoryRepo(), risklogic.NewManager(risk.NewMemoryRiskRepo(), risklogic.RiskConfig{}), Config{})
+	if _, err := disabled.PlaceOCO(context.Background(), exitRequest()); !errors.Is(err, ErrOrderListsDisabled) {
+		t.Errorf("expected ErrOrderListsDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,688 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+// settlementTradeIDMaxLength settlements.trade_id 的长度上限
+const settlementTradeIDMaxLength = 64
+
+// TradeSource 成交来源（由现货网关实现）
+type TradeSource interface {
+	ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error)
+}
+
+// PnLRecorder 已平仓盈亏去向（由 risk.Manager 实现，更新各作用域的当日盈亏、连续亏损与回撤）
+type PnLRecorder interface {
+	RecordTrade(ctx context.Context, scope model.RiskScope, pnl model.Money) error
+}
+
+// ExposureBook 风控持仓（由 risk.Manager 实现，标的 -> 名义价值）
+type ExposureBook interface {
+	SetPositions(ctx context.Context, accountID string, positions map[string]model.Money) error
+}
+
+// FeeRateSource 手续费资产的计价资产价格（如 BNB 抵扣手续费按 BNBUSDT 价格折算）
+type FeeRateSource interface {
+	FeeRate(ctx context.Context, asset string) (model.Money, error)
+}
+
+// FillHandler 成交回调（成交记账后通知归属策略，重放历史成交时不回调）
+type FillHandler func(ctx context.Context, strategyID string, trade *model.Trade)
+
+// UnattributedHandler 无法归属策略的成交回调（客户端订单 ID 中的策略编码未注册，成交按无策略记账）
+type UnattributedHandler func(trade *model.Trade, strategyCode string)
+
+// UnpricedFeeHandler 手续费无法折算为计价资产的成交回调（手续费按资产单独记录，不计入 Fees 与盈亏）
+type UnpricedFeeHandler func(trade *model.Trade, err error)
+
+// LedgerConfig 持仓账本配置
+type LedgerConfig struct {
+	AccountID  string           // 持仓所属账户
+	Symbols    []string         // Sync 拉取成交的交易对
+	Method     model.CostMethod // 持仓成本计算方法（默认加权平均）
+	QuoteAsset string           // 计价资产（默认 USDT，手续费折算为计价资产记账）
+	Lookback   time.Duration    // 首次同步的成交回溯窗口（默认 24 小时）
+}
+
+// roundTrip 一次完整的开平仓（从空仓开仓到再次空仓），平仓后写入 settlements
+type roundTrip struct {
+	id         string
+	side       model.OrderSide // 持仓方向：Buy 为多头，Sell 为空头
+	quantity   model.Money     // 累计平仓数量
+	entryCost  model.Money     // 平仓部分的开仓成本
+	exitValue  model.Money     // 平仓部分的平仓金额
+	pnl        model.Money     // 累计已实现净盈亏
+	commission model.Money     // 累计手续费（开仓与平仓）
+	openedAt   time.Time
+}
+
+// book 单个策略、标的的持仓账
+type book struct {
+	position model.Position
+	lots     []model.PositionLot    // 未平仓批次（按开仓顺序，加权平均法下合并为一个批次）
+	openFees model.Money            // 未平仓部分尚未分摊的开仓手续费
+	unpriced map[string]model.Money // 无法折算为计价资产的手续费（资产 -> 累计数量）
+	trip     roundTrip
+}
+
+// Ledger 持仓账本
+// 职责：
+// 1. 按成交（executions）记账，维护每个策略、标的的净持仓、持仓成本（FIFO 或加权平均）与已实现净盈亏
+// 2. 按最新价格标记未实现盈亏
+// 3. 持仓归零时生成结算（round-trip）写入 settlements，并将盈亏计入风控状态
+// 4. 同步后以持仓名义价值更新风控持仓
+//
+// 账本状态不单独持久化：启动时按 executions 表重放（Load），运行中定时从交易所拉取新成交（Sync）
+type Ledger struct {
+	mu     sync.RWMutex
+	source TradeSource
+	config LedgerConfig
+
+	trades       port.TradeRepo          // 可选，nil 时不保存拉取的成交
+	orders       port.OrderRepo          // 可选，用于按订单归属策略
+	strategies   *model.StrategyRegistry // 可选，按客户端订单 ID 中的策略编码归属策略
+	settlements  port.SettlementRepo     // 可选，nil 时不写入结算
+	pnl          PnLRecorder             // 可选
+	exposure     ExposureBook            // 可选
+	fills        FillHandler             // 可选
+	unattributed UnattributedHandler     // 可选
+	feeRates     FeeRateSource           // 可选，未设置时按账本标记价格折算
+	unpricedFee  UnpricedFeeHandler      // 可选
+
+	books  map[string]*book       // key: StrategyID|Symbol
+	seen   map[string]struct{}    // key: Symbol|ExecID
+	synced map[string]time.Time   // key: Symbol，已同步的最近成交时间
+	marks  map[string]model.Money // key: Symbol，最新标记价格
+}
+
+// NewLedger 创建持仓账本
+// source 为 nil 时只能通过 Apply 记账（回测）
+func NewLedger(source TradeSource, config LedgerConfig) *Ledger {
+	if config.Method == 0 {
+		config.Method = model.CostMethodWeightedAverage
+	}
+	if config.QuoteAsset == "" {
+		config.QuoteAsset = "USDT"
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = 24 * time.Hour
+	}
+
+	return &Ledger{
+		source: source,
+		config: config,
+		books:  make(map[string]*book),
+		seen:   make(map[string]struct{}),
+		synced: make(map[string]time.Time),
+		marks:  make(map[string]model.Money),
+	}
+}
+
+// SetTradeRepo 设置成交仓储（Load 的重放来源，Sync 拉取的成交同时写入）
+func (l *Ledger) SetTradeRepo(repo port.TradeRepo) {
+	l.trades = repo
+}
+
+// SetOrderRepo 设置订单仓储（按成交关联的订单归属策略，本地无订单时按客户端订单 ID 中的策略编码归属）
+func (l *Ledger) SetOrderRepo(repo port.OrderRepo) {
+	l.orders = repo
+}
+
+// SetStrategyRegistry 设置策略注册表（按客户端订单 ID 中的策略编码还原策略，未设置时本地无订单的成交按无策略记账）
+func (l *Ledger) SetStrategyRegistry(registry *model.StrategyRegistry) {
+	l.strategies = registry
+}
+
+// SetSettlementRepo 设置结算仓储
+func (l *Ledger) SetSettlementRepo(repo port.SettlementRepo) {
+	l.settlements = repo
+}
+
+// SetPnLRecorder 设置已平仓盈亏去向
+func (l *Ledger) SetPnLRecorder(recorder PnLRecorder) {
+	l.pnl = recorder
+}
+
+// SetExposureBook 设置风控持仓
+func (l *Ledger) SetExposureBook(exposure ExposureBook) {
+	l.exposure = exposure
+}
+
+// SetFillHandler 设置成交回调
+func (l *Ledger) SetFillHandler(handler FillHandler) {
+	l.fills = handler
+}
+
+// OnUnattributed 设置无法归属策略的成交回调（用于记录日志）
+func (l *Ledger) OnUnattributed(fn UnattributedHandler) {
+	l.unattributed = fn
+}
+
+// SetFeeRateSource 设置手续费资产价格来源（用于折算以第三种资产收取的手续费）
+func (l *Ledger) SetFeeRateSource(source FeeRateSource) {
+	l.feeRates = source
+}
+
+// OnUnpricedFee 设置手续费无法折算的成交回调（用于告警）
+func (l *Ledger) OnUnpricedFee(fn UnpricedFeeHandler) {
+	l.unpricedFee = fn
+}
+
+// Method 持仓成本计算方法
+func (l *Ledger) Method() model.CostMethod {
+	return l.config.Method
+}
+
+// Load 按成交仓储重放全部历史成交重建账本（不重复写入结算与风控盈亏）
+func (l *Ledger) Load(ctx context.Context) error {
+	if l.trades == nil {
+		return nil
+	}
+
+	trades, err := l.trades.ListTrades(ctx, "", time.Time{})
+	if err != nil {
+		return fmt.Errorf("load trades failed: %w", err)
+	}
+	for _, trade := range trades {
+		if _, _, _, err := l.record(ctx, trade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync 从交易所拉取已同步时间之后的成交并记账，返回新记账的成交数量
+func (l *Ledger) Sync(ctx context.Context, now time.Time) (int, error) {
+	if l.source == nil {
+		return 0, nil
+	}
+
+	applied := 0
+	var errs []error
+	for _, symbol := range l.config.Symbols {
+		l.mu.RLock()
+		since, ok := l.synced[symbol]
+		l.mu.RUnlock()
+		if !ok {
+			since = now.Add(-l.config.Lookback)
+		}
+
+		trades, err := l.source.ListTrades(ctx, symbol, since)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list %s trades failed: %w", symbol, err))
+			continue
+		}
+		sort.SliceStable(trades, func(i, j int) bool {
+			return trades[i].TradedAt.Before(trades[j].TradedAt)
+		})
+
+		for _, trade := range trades {
+			if l.trades != nil {
+				if _, err := l.trades.SaveTrade(ctx, trade); err != nil {
+					errs = append(errs, fmt.Errorf("save trade %s failed: %w", trade.ExecID, err))
+					break // 未保存的成交不记账，下次同步重试
+				}
+			}
+			isNew, err := l.Apply(ctx, trade)
+			if isNew {
+				applied++
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if applied > 0 && l.exposure != nil {
+		if err := l.exposure.SetPositions(ctx, l.config.AccountID, l.Exposure()); err != nil {
+			errs = append(errs, fmt.Errorf("update risk positions failed: %w", err))
+		}
+	}
+	return applied, errors.Join(errs...)
+}
+
+// Start 定时同步成交（后台 goroutine），onError 为 nil 时忽略同步错误
+func (l *Ledger) Start(ctx context.Context, interval time.Duration, onError func(err error)) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				if _, err := l.Sync(ctx, now); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Apply 记账一笔成交（按 Symbol 与 ExecID 幂等），返回是否为新成交
+// 新成交记账后回调 FillHandler；持仓归零时写入结算并将盈亏计入风控；记账本身不会因写入失败回滚，失败以错误返回
+func (l *Ledger) Apply(ctx context.Context, trade *model.Trade) (bool, error) {
+	strategyID, settlement, isNew, err := l.record(ctx, trade)
+	if isNew && l.fills != nil {
+		l.fills(ctx, strategyID, trade)
+	}
+	if err != nil || settlement == nil {
+		return isNew, err
+	}
+
+	if l.settlements != nil {
+		if err := l.settlements.SaveSettlement(ctx, settlement); err != nil {
+			return true, fmt.Errorf("save settlement %s failed: %w", settlement.TradeID, err)
+		}
+	}
+	if l.pnl != nil {
+		scope := model.RiskScope{AccountID: l.config.AccountID, StrategyID: settlement.StrategyID, Symbol: settlement.Symbol}
+		if err := l.pnl.RecordTrade(ctx, scope, settlement.RealizedPnL); err != nil {
+			return true, fmt.Errorf("record trade pnl failed: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// record 记账，返回成交归属的策略与是否为新成交，持仓归零时返回结算
+func (l *Ledger) record(ctx context.Context, trade *model.Trade) (string, *model.Settlement, bool, error) {
+	key := trade.Symbol + "|" + trade.ExecID
+	l.mu.RLock()
+	_, dup := l.seen[key]
+	l.mu.RUnlock()
+	if dup || !trade.Quantity.IsPositive() {
+		return "", nil, false, nil
+	}
+
+	strategyID, err := l.strategyOf(ctx, trade)
+	if err != nil {
+		return "", nil, false, err
+	}
+	fee, feeErr := l.feeInQuote(ctx, trade)
+	if feeErr != nil && l.unpricedFee != nil {
+		l.unpricedFee(trade, feeErr)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, dup := l.seen[key]; dup {
+		return "", nil, false, nil
+	}
+	l.seen[key] = struct{}{}
+	if trade.TradedAt.After(l.synced[trade.Symbol]) {
+		l.synced[trade.Symbol] = trade.TradedAt
+	}
+
+	b := l.book(strategyID, trade.Symbol)
+	if feeErr != nil {
+		b.unpriced[trade.FeeAsset] = b.unpriced[trade.FeeAsset].Add(trade.Fee)
+	}
+	return strategyID, l.apply(b, trade, l.netQuantity(trade), fee), true, nil
+}
+
+// apply 按成交更新持仓账（调用方持有锁）
+// 反向成交先平仓，超出持仓的部分按成交方向开新仓；手续费按数量比例分摊到平仓与开仓部分
+func (l *Ledger) apply(b *book, trade *model.Trade, quantity, fee model.Money) *model.Settlement {
+	pos := &b.position
+	remainingFee := fee
+	long := trade.Side == model.OrderSideBuy
+
+	var settlement *model.Settlement
+	if !quantity.IsPositive() {
+		pos.Fees = pos.Fees.Add(fee)
+		pos.UpdatedAt = trade.TradedAt
+		return nil
+	}
+	if !pos.IsFlat() && pos.IsLong() != long {
+		closeQty := quantity.Min(pos.Quantity.Abs())
+		closeFee := fee.Mul(closeQty).Div(quantity)
+		l.close(b, closeQty, trade.Price, closeFee)
+		quantity = quantity.Sub(closeQty)
+		remainingFee = remainingFee.Sub(closeFee)
+		if pos.IsFlat() {
+			settlement = l.settle(b, trade.TradedAt)
+		}
+	}
+	if quantity.IsPositive() {
+		l.open(b, trade, quantity, remainingFee)
+	}
+
+	pos.Fees = pos.Fees.Add(fee)
+	pos.UpdatedAt = trade.TradedAt
+	return settlement
+}
+
+// open 开仓或加仓
+func (l *Ledger) open(b *book, trade *model.Trade, quantity, fee model.Money) {
+	pos := &b.position
+	if pos.IsFlat() {
+		b.trip = roundTrip{
+			id:         settlementTradeID(trade),
+			side:       trade.Side,
+			quantity:   model.Zero(),
+			entryCost:  model.Zero(),
+			exitValue:  model.Zero(),
+			pnl:        model.Zero(),
+			commission: model.Zero(),
+			openedAt:   trade.TradedAt,
+		}
+		pos.OpenedAt = trade.TradedAt
+	}
+
+	lot := model.PositionLot{Quantity: quantity, Price: trade.Price, OpenedAt: trade.TradedAt}
+	if l.config.Method == model.CostMethodWeightedAverage && len(b.lots) > 0 {
+		merged := &b.lots[0]
+		total := merged.Quantity.Add(quantity)
+		merged.Price = merged.Quantity.Mul(merged.Price).Add(quantity.Mul(trade.Price)).Div(total)
+		merged.Quantity = total
+	} else {
+		b.lots = append(b.lots, lot)
+	}
+
+	if trade.Side == model.OrderSideBuy {
+		pos.Quantity = pos.Quantity.Add(quantity)
+	} else {
+		pos.Quantity = pos.Quantity.Sub(quantity)
+	}
+	b.openFees = b.openFees.Add(fee)
+	pos.AvgCost = avgCost(b.lots)
+}
+
+// close 平仓（按批次顺序消耗，加权平均法下只有一个批次）
+func (l *Ledger) close(b *book, quantity, price, fee model.Money) {
+	pos := &b.position
+	held := pos.Quantity.Abs()
+	entryFee := b.openFees.Mul(quantity).Div(held)
+	b.openFees = b.openFees.Sub(entryFee)
+
+	gross, entryCost := model.Zero(), model.Zero()
+	remaining := quantity
+	for remaining.IsPositive() && len(b.lots) > 0 {
+		lot := &b.lots[0]
+		take := remaining.Min(lot.Quantity)
+		entryCost = entryCost.Add(take.Mul(lot.Price))
+		gross = gross.Add(price.Sub(lot.Price).Mul(take))
+		lot.Quantity = lot.Quantity.Sub(take)
+		remaining = remaining.Sub(take)
+		if lot.Quantity.IsZero() {
+			b.lots = b.lots[1:]
+		}
+	}
+	if !pos.IsLong() {
+		gross = gross.Neg()
+		pos.Quantity = pos.Quantity.Add(quantity)
+	} else {
+		pos.Quantity = pos.Quantity.Sub(quantity)
+	}
+
+	pnl := gross.Sub(entryFee).Sub(fee)
+	pos.RealizedPnL = pos.RealizedPnL.Add(pnl)
+	pos.AvgCost = avgCost(b.lots)
+
+	b.trip.quantity = b.trip.quantity.Add(quantity)
+	b.trip.entryCost = b.trip.entryCost.Add(entryCost)
+	b.trip.exitValue = b.trip.exitValue.Add(price.Mul(quantity))
+	b.trip.pnl = b.trip.pnl.Add(pnl)
+	b.trip.commission = b.trip.commission.Add(entryFee).Add(fee)
+}
+
+// settle 持仓归零，生成结算
+func (l *Ledger) settle(b *book, closedAt time.Time) *model.Settlement {
+	trip := b.trip
+	b.trip = roundTrip{}
+	b.lots = nil
+	b.openFees = model.Zero()
+	b.position.OpenedAt = time.Time{}
+
+	return &model.Settlement{
+		TradeID:     trip.id,
+		StrategyID:  b.position.StrategyID,
+		Symbol:      b.position.Symbol,
+		Side:        trip.side,
+		RealizedPnL: trip.pnl,
+		Commission:  trip.commission,
+		EntryPrice:  trip.entryCost.Div(trip.quantity),
+		ExitPrice:   trip.exitValue.Div(trip.quantity),
+		Quantity:    trip.quantity,
+		OpenedAt:    trip.openedAt,
+		ClosedAt:    closedAt,
+	}
+}
+
+// Mark 按最新价格标记标的的全部持仓
+func (l *Ledger) Mark(symbol string, price model.Money) {
+	if price.IsZero() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.marks[symbol] = price
+	for _, b := range l.books {
+		if b.position.Symbol == symbol {
+			b.position.MarkPrice = price
+		}
+	}
+}
+
+// MarkPrice 标的的最新标记价格（尚未标记时返回 false）
+func (l *Ledger) MarkPrice(symbol string) (model.Money, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	price, ok := l.marks[symbol]
+	return price, ok
+}
+
+// UnpricedFees 策略在标的上无法折算为计价资产的手续费（资产 -> 累计数量，未记账时返回空）
+func (l *Ledger) UnpricedFees(strategyID, symbol string) map[string]model.Money {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	fees := make(map[string]model.Money)
+	if b, ok := l.books[strategyID+"|"+symbol]; ok {
+		for asset, fee := range b.unpriced {
+			fees[asset] = fee
+		}
+	}
+	return fees
+}
+
+// Position 策略在标的上的持仓（未记账时返回空仓）
+func (l *Ledger) Position(strategyID, symbol string) model.Position {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if b, ok := l.books[strategyID+"|"+symbol]; ok {
+		return b.position
+	}
+	return emptyPosition(l.config.AccountID, strategyID, symbol)
+}
+
+// Positions 全部持仓（含已平仓但有已实现盈亏的记录，按标的、策略排序）
+func (l *Ledger) Positions() []model.Position {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	positions := make([]model.Position, 0, len(l.books))
+	for _, b := range l.books {
+		positions = append(positions, b.position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Symbol != positions[j].Symbol {
+			return positions[i].Symbol < positions[j].Symbol
+		}
+		return positions[i].StrategyID < positions[j].StrategyID
+	})
+	return positions
+}
+
+// Exposure 各标的持仓名义价值（汇总全部策略，空仓标的为零）
+func (l *Ledger) Exposure() map[string]model.Money {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	exposure := make(map[string]model.Money, len(l.config.Symbols))
+	for _, symbol := range l.config.Symbols {
+		exposure[symbol] = model.Zero()
+	}
+	for _, b := range l.books {
+		notional, ok := exposure[b.position.Symbol]
+		if !ok {
+			notional = model.Zero()
+		}
+		exposure[b.position.Symbol] = notional.Add(b.position.Notional())
+	}
+	return exposure
+}
+
+// book 获取或创建持仓账（调用方持有锁）
+func (l *Ledger) book(strategyID, symbol string) *book {
+	key := strategyID + "|" + symbol
+	if b, ok := l.books[key]; ok {
+		return b
+	}
+	b := &book{
+		position: emptyPosition(l.config.AccountID, strategyID, symbol),
+		openFees: model.Zero(),
+		unpriced: make(map[string]model.Money),
+	}
+	if price, ok := l.marks[symbol]; ok {
+		b.position.MarkPrice = price
+	}
+	l.books[key] = b
+	return b
+}
+
+// strategyOf 成交归属的策略：优先按本地订单，其次按客户端订单 ID 中的策略编码
+// 编码只接受策略注册表中的策略（见 SetStrategyRegistry），未注册时按无策略记账并回调 UnattributedHandler
+func (l *Ledger) strategyOf(ctx context.Context, trade *model.Trade) (string, error) {
+	if l.orders != nil {
+		var (
+			order *model.Order
+			err   error
+		)
+		switch {
+		case trade.ClientOrderID != "":
+			order, err = l.orders.GetOrder(ctx, trade.ClientOrderID)
+		case trade.ExchangeOrderID != "":
+			order, err = l.orders.GetOrderByExchangeID(ctx, trade.ExchangeOrderID)
+		default:
+			err = port.ErrOrderNotFound
+		}
+		switch {
+		case err == nil:
+			return order.StrategyID, nil
+		case !errors.Is(err, port.ErrOrderNotFound):
+			return "", fmt.Errorf("get order of trade %s failed: %w", trade.ExecID, err)
+		}
+	}
+
+	id, err := model.DecodeClientOrderID(trade.ClientOrderID)
+	if err != nil {
+		return "", nil
+	}
+	if l.strategies != nil {
+		id.StrategyID, _ = l.strategies.Lookup(id.StrategyCode)
+	}
+	if id.StrategyID == "" && id.StrategyCode != "" && id.StrategyCode != model.StrategyCode("") && l.unattributed != nil {
+		l.unattributed(trade, id.StrategyCode)
+	}
+	return id.StrategyID, nil
+}
+
+// feeInQuote 手续费折算为计价资产
+// 以基础资产收取时按成交价折算；其他资产（如 BNB）按 FeeRateSource 折算，未设置时按账本中该资产交易对的标记价格折算
+// 无法折算时返回零与错误，由调用方单独记录该资产的手续费
+func (l *Ledger) feeInQuote(ctx context.Context, trade *model.Trade) (model.Money, error) {
+	switch trade.FeeAsset {
+	case "", l.config.QuoteAsset:
+		return trade.Fee, nil
+	case l.baseAsset(trade):
+		return trade.Fee.Mul(trade.Price), nil
+	}
+	if trade.Fee.IsZero() {
+		return model.Zero(), nil
+	}
+
+	if l.feeRates != nil {
+		rate, err := l.feeRates.FeeRate(ctx, trade.FeeAsset)
+		if err != nil {
+			return model.Zero(), fmt.Errorf("get %s fee rate failed: %w", trade.FeeAsset, err)
+		}
+		if rate.IsPositive() {
+			return trade.Fee.Mul(rate), nil
+		}
+	} else if rate, ok := l.MarkPrice(trade.FeeAsset + l.config.QuoteAsset); ok {
+		return trade.Fee.Mul(rate), nil
+	}
+	return model.Zero(), fmt.Errorf("no %s%s rate to price fee of trade %s", trade.FeeAsset, l.config.QuoteAsset, trade.ExecID)
+}
+
+// netQuantity 成交对持仓的实际变动数量
+// 以基础资产收取的手续费从到账数量中扣除：买入到账 数量-手续费，卖出额外扣减 数量+手续费
+func (l *Ledger) netQuantity(trade *model.Trade) model.Money {
+	if trade.FeeAsset == "" || trade.FeeAsset != l.baseAsset(trade) {
+		return trade.Quantity
+	}
+	if trade.Side == model.OrderSideBuy {
+		return trade.Quantity.Sub(trade.Fee)
+	}
+	return trade.Quantity.Add(trade.Fee)
+}
+
+// baseAsset 成交标的的基础资产（如 BTCUSDT -> BTC）
+func (l *Ledger) baseAsset(trade *model.Trade) string {
+	return strings.TrimSuffix(trade.Symbol, l.config.QuoteAsset)
+}
+
+// avgCost 剩余批次的加权均价
+func avgCost(lots []model.PositionLot) model.Money {
+	quantity, cost := model.Zero(), model.Zero()
+	for _, lot := range lots {
+		quantity = quantity.Add(lot.Quantity)
+		cost = cost.Add(lot.Quantity.Mul(lot.Price))
+	}
+	if quantity.IsZero() {
+		return model.Zero()
+	}
+	return cost.Div(quantity)
+}
+
+// emptyPosition 空仓
+func emptyPosition(accountID, strategyID, symbol string) model.Position {
+	return model.Position{
+		AccountID:   accountID,
+		StrategyID:  strategyID,
+		Symbol:      symbol,
+		Quantity:    model.Zero(),
+		AvgCost:     model.Zero(),
+		RealizedPnL: model.Zero(),
+		Fees:        model.Zero(),
+		MarkPrice:   model.Zero(),
+	}
+}
+
+// settlementTradeID 结算的逻辑交易 ID（标的与开仓成交 ID）
+func settlementTradeID(trade *model.Trade) string {
+	id := trade.Symbol + "-" + trade.ExecID
+	if len(id) > settlementTradeIDMaxLength {
+		id = id[:settlementTradeIDMaxLength]
+	}
+	return id
+}
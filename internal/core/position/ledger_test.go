@@ -0,0 +1,430 @@
+package position
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
+	"github.com/iluyuns/alpha-trade/internal/infra/order"
+	"github.com/iluyuns/alpha-trade/internal/infra/settlement"
+	"github.com/iluyuns/alpha-trade/internal/infra/trade"
+)
+
+type mockPnLRecorder struct {
+	scopes []model.RiskScope
+	pnls   []model.Money
+}
+
+func (r *mockPnLRecorder) RecordTrade(ctx context.Context, scope model.RiskScope, pnl model.Money) error {
+	r.scopes = append(r.scopes, scope)
+	r.pnls = append(r.pnls, pnl)
+	return nil
+}
+
+type mockExposureBook struct {
+	positions map[string]model.Money
+}
+
+func (b *mockExposureBook) SetPositions(ctx context.Context, accountID string, positions map[string]model.Money) error {
+	b.positions = positions
+	return nil
+}
+
+var ledgerBase = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fill 构造成交（手续费以 USDT 计）
+func fill(id int, side model.OrderSide, qty, price, fee string) *model.Trade {
+	return &model.Trade{
+		ExecID:        fmt.Sprintf("E%d", id),
+		ClientOrderID: fmt.Sprintf("order-%d", id),
+		Symbol:        "BTCUSDT",
+		Side:          side,
+		Price:         model.MustMoney(price),
+		Quantity:      model.MustMoney(qty),
+		Fee:           model.MustMoney(fee),
+		FeeAsset:      "USDT",
+		TradedAt:      ledgerBase.Add(time.Duration(id) * time.Minute),
+	}
+}
+
+func applyAll(t *testing.T, ledger *Ledger, trades ...*model.Trade) {
+	t.Helper()
+	for _, tr := range trades {
+		if _, err := ledger.Apply(context.Background(), tr); err != nil {
+			t.Fatalf("Apply %s failed: %v", tr.ExecID, err)
+		}
+	}
+}
+
+func TestLedger_WeightedAverage(t *testing.T) {
+	ctx := context.Background()
+	settlements := settlement.NewMemoryRepo()
+	recorder := &mockPnLRecorder{}
+	ledger := NewLedger(nil, LedgerConfig{AccountID: "acc"})
+	ledger.SetSettlementRepo(settlements)
+	ledger.SetPnLRecorder(recorder)
+
+	applyAll(t, ledger,
+		fill(1, model.OrderSideBuy, "1", "100", "1"),
+		fill(2, model.OrderSideBuy, "1", "200", "1"),
+	)
+	pos := ledger.Position("", "BTCUSDT")
+	if !pos.Quantity.EQ(model.MustMoney("2")) || !pos.AvgCost.EQ(model.MustMoney("150")) {
+		t.Fatalf("position = %s @ %s, want 2 @ 150", pos.Quantity, pos.AvgCost)
+	}
+
+	ledger.Mark("BTCUSDT", model.MustMoney("180"))
+	if price, ok := ledger.MarkPrice("BTCUSDT"); !ok || !price.EQ(model.MustMoney("180")) {
+		t.Errorf("MarkPrice = %s, %v, want 180", price, ok)
+	}
+	if _, ok := ledger.MarkPrice("ETHUSDT"); ok {
+		t.Error("unmarked symbol should have no mark price")
+	}
+	if pnl := ledger.Position("", "BTCUSDT").UnrealizedPnL(); !pnl.EQ(model.MustMoney("60")) {
+		t.Errorf("unrealized = %s, want 60", pnl)
+	}
+
+	// 减仓一半：毛利 (250-150)*1 = 100，开仓手续费分摊 1，平仓手续费 1
+	applyAll(t, ledger, fill(3, model.OrderSideSell, "1", "250", "1"))
+	pos = ledger.Position("", "BTCUSDT")
+	if !pos.Quantity.EQ(model.MustMoney("1")) || !pos.AvgCost.EQ(model.MustMoney("150")) {
+		t.Fatalf("position = %s @ %s, want 1 @ 150", pos.Quantity, pos.AvgCost)
+	}
+	if !pos.RealizedPnL.EQ(model.MustMoney("98")) {
+		t.Errorf("realized = %s, want 98", pos.RealizedPnL)
+	}
+	if recent, _ := settlements.ListRecentSettlements(ctx, 10); len(recent) != 0 {
+		t.Fatalf("partial close must not settle, got %d", len(recent))
+	}
+
+	// 平仓：毛利 (100-150)*1 = -50，手续费 1 + 1
+	applyAll(t, ledger, fill(4, model.OrderSideSell, "1", "100", "1"))
+	pos = ledger.Position("", "BTCUSDT")
+	if !pos.IsFlat() || !pos.AvgCost.IsZero() || !pos.OpenedAt.IsZero() {
+		t.Fatalf("position should be flat, got %+v", pos)
+	}
+	if !pos.RealizedPnL.EQ(model.MustMoney("46")) || !pos.Fees.EQ(model.MustMoney("4")) {
+		t.Errorf("realized = %s fees = %s, want 46 and 4", pos.RealizedPnL, pos.Fees)
+	}
+
+	recent, err := settlements.ListRecentSettlements(ctx, 10)
+	if err != nil || len(recent) != 1 {
+		t.Fatalf("expected one settlement, got %d (%v)", len(recent), err)
+	}
+	s := recent[0]
+	if s.TradeID != "BTCUSDT-E1" || s.Side != model.OrderSideBuy || !s.Quantity.EQ(model.MustMoney("2")) {
+		t.Errorf("settlement = %+v", s)
+	}
+	if !s.RealizedPnL.EQ(model.MustMoney("46")) || !s.Commission.EQ(model.MustMoney("4")) {
+		t.Errorf("settlement pnl = %s commission = %s, want 46 and 4", s.RealizedPnL, s.Commission)
+	}
+	if !s.EntryPrice.EQ(model.MustMoney("150")) || !s.ExitPrice.EQ(model.MustMoney("175")) {
+		t.Errorf("settlement entry = %s exit = %s, want 150 and 175", s.EntryPrice, s.ExitPrice)
+	}
+	if !s.OpenedAt.Equal(ledgerBase.Add(time.Minute)) || !s.ClosedAt.Equal(ledgerBase.Add(4*time.Minute)) {
+		t.Errorf("settlement opened %v closed %v", s.OpenedAt, s.ClosedAt)
+	}
+
+	if len(recorder.pnls) != 1 || !recorder.pnls[0].EQ(model.MustMoney("46")) {
+		t.Fatalf("risk pnl = %v, want [46]", recorder.pnls)
+	}
+	if scope := recorder.scopes[0]; scope.AccountID != "acc" || scope.Symbol != "BTCUSDT" {
+		t.Errorf("risk scope = %+v", scope)
+	}
+}
+
+func TestLedger_FIFO(t *testing.T) {
+	ledger := NewLedger(nil, LedgerConfig{Method: model.CostMethodFIFO})
+
+	applyAll(t, ledger,
+		fill(1, model.OrderSideBuy, "1", "100", "0"),
+		fill(2, model.OrderSideBuy, "1", "200", "0"),
+		fill(3, model.OrderSideSell, "1", "250", "0"),
+	)
+
+	// 先平掉 100 的批次：已实现 150，剩余批次成本 200
+	pos := ledger.Position("", "BTCUSDT")
+	if !pos.RealizedPnL.EQ(model.MustMoney("150")) {
+		t.Errorf("realized = %s, want 150", pos.RealizedPnL)
+	}
+	if !pos.Quantity.EQ(model.MustMoney("1")) || !pos.AvgCost.EQ(model.MustMoney("200")) {
+		t.Errorf("position = %s @ %s, want 1 @ 200", pos.Quantity, pos.AvgCost)
+	}
+}
+
+func TestLedger_ShortAndFlip(t *testing.T) {
+	ctx := context.Background()
+	settlements := settlement.NewMemoryRepo()
+	ledger := NewLedger(nil, LedgerConfig{})
+	ledger.SetSettlementRepo(settlements)
+
+	// 开空 1 @ 200，反手买入 3 @ 150：平空 1（盈利 50），开多 2 @ 150
+	applyAll(t, ledger,
+		fill(1, model.OrderSideSell, "1", "200", "0"),
+		fill(2, model.OrderSideBuy, "3", "150", "3"),
+	)
+
+	pos := ledger.Position("", "BTCUSDT")
+	if !pos.Quantity.EQ(model.MustMoney("2")) || !pos.AvgCost.EQ(model.MustMoney("150")) {
+		t.Fatalf("position = %s @ %s, want 2 @ 150", pos.Quantity, pos.AvgCost)
+	}
+	// 手续费按数量分摊：平空部分 1，开多部分 2
+	if !pos.RealizedPnL.EQ(model.MustMoney("49")) {
+		t.Errorf("realized = %s, want 49", pos.RealizedPnL)
+	}
+	if !pos.OpenedAt.Equal(ledgerBase.Add(2 * time.Minute)) {
+		t.Errorf("flipped position opened at %v", pos.OpenedAt)
+	}
+
+	recent, _ := settlements.ListRecentSettlements(ctx, 10)
+	if len(recent) != 1 || recent[0].Side != model.OrderSideSell || !recent[0].RealizedPnL.EQ(model.MustMoney("49")) {
+		t.Fatalf("short settlement = %+v", recent)
+	}
+
+	// 平多：开仓手续费 2 在平仓时计入
+	applyAll(t, ledger, fill(3, model.OrderSideSell, "2", "160", "0"))
+	recent, _ = settlements.ListRecentSettlements(ctx, 10)
+	if len(recent) != 2 || !recent[1].RealizedPnL.EQ(model.MustMoney("18")) {
+		t.Fatalf("long settlement = %+v", recent)
+	}
+}
+
+type mockFeeRates map[string]model.Money
+
+func (r mockFeeRates) FeeRate(ctx context.Context, asset string) (model.Money, error) {
+	rate, ok := r[asset]
+	if !ok {
+		return model.Zero(), fmt.Errorf("no rate for %s", asset)
+	}
+	return rate, nil
+}
+
+func TestLedger_FeeInBaseAsset(t *testing.T) {
+	ledger := NewLedger(nil, LedgerConfig{})
+	buy := fill(1, model.OrderSideBuy, "1", "100", "0")
+	buy.Fee, buy.FeeAsset = model.MustMoney("0.001"), "BTC"
+	applyAll(t, ledger, buy)
+
+	// 以基础资产收取的手续费从到账数量中扣除，并按成交价折算计入手续费
+	pos := ledger.Position("", "BTCUSDT")
+	if !pos.Quantity.EQ(model.MustMoney("0.999")) || !pos.Fees.EQ(model.MustMoney("0.1")) {
+		t.Errorf("quantity = %s, fees = %s, want 0.999, 0.1", pos.Quantity, pos.Fees)
+	}
+
+	// 卖出时基础资产手续费额外扣减持仓，全部卖出后持仓归零
+	sell := fill(2, model.OrderSideSell, "0.998", "110", "0")
+	sell.Fee, sell.FeeAsset = model.MustMoney("0.001"), "BTC"
+	applyAll(t, ledger, sell)
+
+	pos = ledger.Position("", "BTCUSDT")
+	if !pos.IsFlat() {
+		t.Fatalf("quantity = %s, want flat", pos.Quantity)
+	}
+	// 买入 100 付出 1 BTC 的成本，卖出 0.998 BTC 到账 109.78：净盈亏 9.78
+	if !pos.RealizedPnL.EQ(model.MustMoney("9.78")) {
+		t.Errorf("realized = %s, want 9.78", pos.RealizedPnL)
+	}
+}
+
+func TestLedger_FeeInThirdAsset(t *testing.T) {
+	bnbFill := func(id int) *model.Trade {
+		tr := fill(id, model.OrderSideBuy, "1", "100", "0")
+		tr.Fee, tr.FeeAsset = model.MustMoney("0.01"), "BNB"
+		return tr
+	}
+
+	t.Run("按价格来源折算", func(t *testing.T) {
+		ledger := NewLedger(nil, LedgerConfig{})
+		ledger.SetFeeRateSource(mockFeeRates{"BNB": model.MustMoney("300")})
+		applyAll(t, ledger, bnbFill(1))
+
+		pos := ledger.Position("", "BTCUSDT")
+		if !pos.Quantity.EQ(model.MustMoney("1")) || !pos.Fees.EQ(model.MustMoney("3")) {
+			t.Errorf("quantity = %s, fees = %s, want 1, 3", pos.Quantity, pos.Fees)
+		}
+		if fees := ledger.UnpricedFees("", "BTCUSDT"); len(fees) != 0 {
+			t.Errorf("unpriced fees = %v, want none", fees)
+		}
+	})
+
+	t.Run("未设置价格来源时按账本标记价格折算", func(t *testing.T) {
+		ledger := NewLedger(nil, LedgerConfig{})
+		ledger.Mark("BNBUSDT", model.MustMoney("250"))
+		applyAll(t, ledger, bnbFill(1))
+
+		if fees := ledger.Position("", "BTCUSDT").Fees; !fees.EQ(model.MustMoney("2.5")) {
+			t.Errorf("fees = %s, want 2.5", fees)
+		}
+	})
+
+	t.Run("无法折算时单独记录并告警", func(t *testing.T) {
+		ledger := NewLedger(nil, LedgerConfig{})
+		var flagged []string
+		ledger.OnUnpricedFee(func(trade *model.Trade, err error) {
+			flagged = append(flagged, trade.ExecID)
+		})
+		applyAll(t, ledger, bnbFill(1), bnbFill(2))
+
+		if fees := ledger.Position("", "BTCUSDT").Fees; !fees.IsZero() {
+			t.Errorf("fees = %s, want 0", fees)
+		}
+		unpriced := ledger.UnpricedFees("", "BTCUSDT")
+		if !unpriced["BNB"].EQ(model.MustMoney("0.02")) {
+			t.Errorf("unpriced BNB = %s, want 0.02", unpriced["BNB"])
+		}
+		if strings.Join(flagged, ",") != "E1,E2" {
+			t.Errorf("flagged = %v, want [E1 E2]", flagged)
+		}
+	})
+}
+
+func TestLedger_StrategyAttribution(t *testing.T) {
+	ctx := context.Background()
+	orders := order.NewMemoryRepo()
+	_ = orders.SaveOrder(ctx, &model.Order{
+		ClientOrderID: "order-1",
+		ExchangeID:    "X1",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeMarket,
+		Quantity:      model.MustMoney("1"),
+		Filled:        model.MustMoney("1"),
+		Status:        model.OrderStatusFilled,
+		StrategyID:    "ma_cross",
+	})
+	registry := model.NewStrategyRegistry()
+	_, _ = registry.Register("breakout")
+	ledger := NewLedger(nil, LedgerConfig{})
+	ledger.SetOrderRepo(orders)
+	ledger.SetStrategyRegistry(registry)
+	var notified []string
+	ledger.SetFillHandler(func(ctx context.Context, strategyID string, trade *model.Trade) {
+		notified = append(notified, strategyID+":"+trade.ExecID)
+	})
+
+	// 交易所成交只带交易所订单 ID
+	byExchangeID := fill(1, model.OrderSideBuy, "1", "100", "0")
+	byExchangeID.ClientOrderID, byExchangeID.ExchangeOrderID = "", "X1"
+	// 本地无订单：按客户端订单 ID 中的策略编码归属
+	encoded, _ := model.NewClientOrderID(model.OrderIntentEntry, "breakout", "BTCUSDT")
+	byTag := fill(2, model.OrderSideBuy, "1", "100", "0")
+	byTag.ClientOrderID = encoded
+	applyAll(t, ledger, byExchangeID, byTag, fill(3, model.OrderSideBuy, "1", "100", "0"))
+	applyAll(t, ledger, byTag) // 重复成交不回调
+
+	positions := ledger.Positions()
+	if len(positions) != 3 {
+		t.Fatalf("expected 3 positions, got %d", len(positions))
+	}
+	for i, want := range []string{"", "breakout", "ma_cross"} {
+		if positions[i].StrategyID != want || !positions[i].Quantity.EQ(model.MustMoney("1")) {
+			t.Errorf("positions[%d] = %s %s, want %s 1", i, positions[i].StrategyID, positions[i].Quantity, want)
+		}
+	}
+	if got := strings.Join(notified, ","); got != "ma_cross:E1,breakout:E2,:E3" {
+		t.Errorf("fill notifications = %s", got)
+	}
+}
+
+func TestLedger_UnregisteredStrategyCode(t *testing.T) {
+	registry := model.NewStrategyRegistry()
+	_, _ = registry.Register("breakout")
+	ledger := NewLedger(nil, LedgerConfig{})
+	ledger.SetStrategyRegistry(registry)
+	var codes []string
+	ledger.OnUnattributed(func(trade *model.Trade, strategyCode string) {
+		codes = append(codes, trade.ExecID+":"+strategyCode)
+	})
+
+	// 本地无订单且策略编码未注册：按无策略记账，不创建不存在的策略持仓
+	encoded, err := model.ClientOrderID{Intent: model.OrderIntentEntry, StrategyID: "unregistered_strategy", Symbol: "BTCUSDT"}.Encode(0)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	unknown := fill(1, model.OrderSideBuy, "1", "100", "0")
+	unknown.ClientOrderID = encoded
+	manual, _ := model.NewClientOrderID(model.OrderIntentManual, "", "BTCUSDT")
+	byManual := fill(2, model.OrderSideBuy, "1", "100", "0")
+	byManual.ClientOrderID = manual
+	applyAll(t, ledger, unknown, byManual)
+
+	positions := ledger.Positions()
+	if len(positions) != 1 || positions[0].StrategyID != "" || !positions[0].Quantity.EQ(model.MustMoney("2")) {
+		t.Fatalf("expected a single unattributed position of 2, got %+v", positions)
+	}
+	if want := "E1:" + model.StrategyCode("unregistered_strategy"); strings.Join(codes, ",") != want {
+		t.Errorf("unattributed callbacks = %v, want %s", codes, want)
+	}
+}
+
+func TestLedger_SyncAndLoad(t *testing.T) {
+	ctx := context.Background()
+	exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("100000")})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	trades := trade.NewMemoryRepo()
+	settlements := settlement.NewMemoryRepo()
+	exposure := &mockExposureBook{}
+
+	ledger := NewLedger(exchange, LedgerConfig{AccountID: "acc", Symbols: []string{"BTCUSDT"}})
+	ledger.SetTradeRepo(trades)
+	ledger.SetSettlementRepo(settlements)
+	ledger.SetExposureBook(exposure)
+
+	place := func(id string, side model.OrderSide) {
+		t.Helper()
+		if _, err := exchange.PlaceOrder(ctx, &port.SpotPlaceOrderRequest{ClientOrderID: id, Symbol: "BTCUSDT",
+			Side: side, Type: model.OrderTypeMarket, Quantity: model.MustMoney("0.1")}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+	}
+
+	place("buy-1", model.OrderSideBuy)
+	n, err := ledger.Sync(ctx, time.Now())
+	if err != nil || n != 1 {
+		t.Fatalf("Sync = %d (%v), want 1", n, err)
+	}
+	if n, _ := ledger.Sync(ctx, time.Now()); n != 0 {
+		t.Fatalf("repeated Sync must be idempotent, got %d", n)
+	}
+	if notional := exposure.positions["BTCUSDT"]; !notional.IsPositive() {
+		t.Errorf("risk exposure not updated: %v", exposure.positions)
+	}
+
+	place("sell-1", model.OrderSideSell)
+	if n, err := ledger.Sync(ctx, time.Now()); err != nil || n != 1 {
+		t.Fatalf("Sync = %d (%v), want 1", n, err)
+	}
+	pos := ledger.Position("", "BTCUSDT")
+	if !pos.IsFlat() || !pos.RealizedPnL.IsNegative() {
+		t.Fatalf("round trip should be flat with slippage and fee loss, got %+v", pos)
+	}
+	if notional := exposure.positions["BTCUSDT"]; !notional.IsZero() {
+		t.Errorf("flat position should clear risk exposure, got %s", notional)
+	}
+	recent, _ := settlements.ListRecentSettlements(ctx, 10)
+	if len(recent) != 1 {
+		t.Fatalf("expected one settlement, got %d", len(recent))
+	}
+
+	// 重启：按成交仓储重放，结果一致且不重复写入结算
+	restored := NewLedger(exchange, LedgerConfig{AccountID: "acc", Symbols: []string{"BTCUSDT"}})
+	restored.SetTradeRepo(trades)
+	restored.SetSettlementRepo(settlements)
+	if err := restored.Load(ctx); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := restored.Position("", "BTCUSDT"); !got.RealizedPnL.EQ(pos.RealizedPnL) || !got.Fees.EQ(pos.Fees) {
+		t.Errorf("restored = %s / %s, want %s / %s", got.RealizedPnL, got.Fees, pos.RealizedPnL, pos.Fees)
+	}
+	if n, _ := restored.Sync(ctx, time.Now()); n != 0 {
+		t.Errorf("Sync after Load must skip replayed trades, got %d", n)
+	}
+	if recent, _ := settlements.ListRecentSettlements(ctx, 10); len(recent) != 1 {
+		t.Errorf("Load must not rewrite settlements, got %d", len(recent))
+	}
+}
@@ -0,0 +1,79 @@
+package position
+
+import (
+	"fmt"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// StopPolicy 止损移动策略（每个策略可独立选择，见 RISK_PROTOCOL 7.3 保本止损）
+type StopPolicy struct {
+	BreakevenR          float64 // 浮盈达到 N 倍 R 时止损移至开仓价（0 表示不启用）
+	TrailingATRMultiple float64 // 保本后按 ATR * N 追踪（0 表示不追踪）
+}
+
+// DefaultStopPolicy 默认策略：1.2R 保本，不追踪
+func DefaultStopPolicy() StopPolicy {
+	return StopPolicy{BreakevenR: 1.2}
+}
+
+// adjust 按策略计算新的止损位
+// 止损只能朝有利方向移动，返回 nil 表示无需调整
+func (p StopPolicy) adjust(level *model.StopLevel, mark, atr model.Money) *model.StopAdjustment {
+	if level.StopLoss.IsZero() {
+		return nil
+	}
+
+	// 1. 保本：浮盈 >= BreakevenR * R
+	if level.Stage == model.StopStageInitial {
+		r := level.RiskUnit()
+		if p.BreakevenR <= 0 || !r.IsPositive() {
+			return nil
+		}
+		threshold := r.Mul(model.NewMoneyFromFloat(p.BreakevenR))
+		if favorableMove(level, mark).LT(threshold) || !improves(level, level.EntryPrice) {
+			return nil
+		}
+		return &model.StopAdjustment{
+			OldStop: level.StopLoss,
+			NewStop: level.EntryPrice,
+			Stage:   model.StopStageBreakeven,
+			Reason:  fmt.Sprintf("breakeven at %.1fR", p.BreakevenR),
+		}
+	}
+
+	// 2. 追踪：保本之后按 ATR 倍数跟随
+	if p.TrailingATRMultiple <= 0 || !atr.IsPositive() {
+		return nil
+	}
+	distance := atr.Mul(model.NewMoneyFromFloat(p.TrailingATRMultiple))
+	candidate := mark.Sub(distance)
+	if !level.IsLong() {
+		candidate = mark.Add(distance)
+	}
+	if !improves(level, candidate) {
+		return nil
+	}
+	return &model.StopAdjustment{
+		OldStop: level.StopLoss,
+		NewStop: candidate,
+		Stage:   model.StopStageTrailing,
+		Reason:  fmt.Sprintf("trail %.1fxATR", p.TrailingATRMultiple),
+	}
+}
+
+// favorableMove 有利方向的价格移动（多头上涨、空头下跌为正）
+func favorableMove(level *model.StopLevel, mark model.Money) model.Money {
+	if level.IsLong() {
+		return mark.Sub(level.EntryPrice)
+	}
+	return level.EntryPrice.Sub(mark)
+}
+
+// improves 新止损是否比当前止损更有利（多头更高、空头更低）
+func improves(level *model.StopLevel, newStop model.Money) bool {
+	if level.IsLong() {
+		return newStop.GT(level.StopLoss)
+	}
+	return newStop.LT(level.StopLoss)
+}
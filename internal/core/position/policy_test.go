@@ -0,0 +1,113 @@
+package position
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/infra/stop"
+)
+
+func TestStopPolicy_Breakeven(t *testing.T) {
+	policy := StopPolicy{BreakevenR: 1.2}
+	level := newLongStop() // 开仓 50000，止损 49000 -> R = 1000
+	level.InitialStop = level.StopLoss
+
+	// 浮盈 1100 < 1.2R
+	if adj := policy.adjust(&level, model.MustMoney("51100"), model.Zero()); adj != nil {
+		t.Fatalf("breakeven triggered too early: %+v", adj)
+	}
+
+	// 浮盈 1200 = 1.2R -> 移至开仓价
+	adj := policy.adjust(&level, model.MustMoney("51200"), model.Zero())
+	if adj == nil {
+		t.Fatal("expected breakeven adjustment")
+	}
+	if !adj.NewStop.EQ(level.EntryPrice) || adj.Stage != model.StopStageBreakeven {
+		t.Errorf("adjustment = %s (%s), want %s (BREAKEVEN)", adj.NewStop, adj.Stage, level.EntryPrice)
+	}
+}
+
+func TestStopPolicy_Trailing(t *testing.T) {
+	policy := StopPolicy{BreakevenR: 1.2, TrailingATRMultiple: 2}
+	level := newLongStop()
+	level.InitialStop = level.StopLoss
+	level.StopLoss = level.EntryPrice
+	level.Stage = model.StopStageBreakeven
+
+	// 52500 - 500 * 2 = 51500 > 50000
+	adj := policy.adjust(&level, model.MustMoney("52500"), model.MustMoney("500"))
+	if adj == nil || !adj.NewStop.EQ(model.MustMoney("51500")) {
+		t.Fatalf("expected trail to 51500, got %+v", adj)
+	}
+	level.StopLoss = adj.NewStop
+
+	// 价格回落：止损不回退
+	if adj := policy.adjust(&level, model.MustMoney("52000"), model.MustMoney("500")); adj != nil {
+		t.Errorf("trailing stop must not loosen, got %s", adj.NewStop)
+	}
+
+	// 未启用追踪
+	noTrail := StopPolicy{BreakevenR: 1.2}
+	if adj := noTrail.adjust(&level, model.MustMoney("60000"), model.MustMoney("500")); adj != nil {
+		t.Errorf("trailing disabled, got %+v", adj)
+	}
+}
+
+func TestStopPolicy_Short(t *testing.T) {
+	policy := StopPolicy{BreakevenR: 1.2, TrailingATRMultiple: 1}
+	level := model.StopLevel{
+		Side:        model.OrderSideSell,
+		EntryPrice:  model.MustMoney("3000"),
+		StopLoss:    model.MustMoney("3100"),
+		InitialStop: model.MustMoney("3100"),
+	}
+
+	adj := policy.adjust(&level, model.MustMoney("2880"), model.Zero())
+	if adj == nil || !adj.NewStop.EQ(model.MustMoney("3000")) {
+		t.Fatalf("expected short breakeven at 3000, got %+v", adj)
+	}
+	level.StopLoss = adj.NewStop
+	level.Stage = adj.Stage
+
+	// 2800 + 50 = 2850 < 3000
+	adj = policy.adjust(&level, model.MustMoney("2800"), model.MustMoney("50"))
+	if adj == nil || !adj.NewStop.EQ(model.MustMoney("2850")) {
+		t.Fatalf("expected short trail to 2850, got %+v", adj)
+	}
+}
+
+func TestStopManager_AdjustmentPersistedAndRestored(t *testing.T) {
+	ctx := context.Background()
+	repo := stop.NewMemoryRepo()
+	mgr := NewStopManager(&mockExecutor{}, repo, StopConfig{DefaultPolicy: DefaultStopPolicy()})
+	if err := mgr.Track(ctx, newLongStop()); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+
+	if err := mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("51300"), time.Now()); err != nil {
+		t.Fatalf("OnMarkPrice failed: %v", err)
+	}
+
+	adjustments, _ := repo.ListAdjustments(ctx, "acc:test:BTCUSDT", 10)
+	if len(adjustments) != 1 || adjustments[0].Stage != model.StopStageBreakeven {
+		t.Fatalf("expected 1 breakeven adjustment, got %+v", adjustments)
+	}
+
+	// 重启：从仓储恢复，止损位为保本位而非初始止损
+	restored := NewStopManager(&mockExecutor{}, repo, StopConfig{})
+	if err := restored.Restore(ctx); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	level, err := restored.Get("acc:test:BTCUSDT")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !level.StopLoss.EQ(model.MustMoney("50000")) || level.Stage != model.StopStageBreakeven {
+		t.Errorf("restored stop = %s (%s), want 50000 (BREAKEVEN)", level.StopLoss, level.Stage)
+	}
+	if !level.InitialStop.EQ(model.MustMoney("49000")) {
+		t.Errorf("initial stop = %s, want 49000", level.InitialStop)
+	}
+}
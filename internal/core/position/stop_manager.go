@@ -0,0 +1,503 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/core/sizing"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+var (
+	// ErrInvalidStop 止损参数无效
+	ErrInvalidStop = errors.New("position: invalid stop level")
+	// ErrStopNotFound 止损记录不存在
+	ErrStopNotFound = errors.New("position: stop not found")
+)
+
+// ExitReason 平仓原因
+type ExitReason int
+
+const (
+	// ExitStopLoss 止损
+	ExitStopLoss ExitReason = iota + 1
+	// ExitTakeProfit 止盈
+	ExitTakeProfit
+	// ExitKillSwitch 人工紧急平仓（Kill Switch）
+	ExitKillSwitch
+)
+
+func (r ExitReason) String() string {
+	switch r {
+	case ExitStopLoss:
+		return "STOP_LOSS"
+	case ExitTakeProfit:
+		return "TAKE_PROFIT"
+	case ExitKillSwitch:
+		return "KILL_SWITCH"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// stopBreached 标记价格是否穿过止损位
+func stopBreached(level *model.StopLevel, mark model.Money) bool {
+	if level.StopLoss.IsZero() {
+		return false
+	}
+	if level.IsLong() {
+		return mark.LE(level.StopLoss)
+	}
+	return mark.GE(level.StopLoss)
+}
+
+// takeProfitReached 标记价格是否达到止盈位
+func takeProfitReached(level *model.StopLevel, mark model.Money) bool {
+	if level.TakeProfit.IsZero() {
+		return false
+	}
+	if level.IsLong() {
+		return mark.GE(level.TakeProfit)
+	}
+	return mark.LE(level.TakeProfit)
+}
+
+// ExitRequest 平仓请求
+type ExitRequest struct {
+	Stop        model.StopLevel
+	Reason      ExitReason
+	MarkPrice   model.Money // 触发时的标记价格
+	TriggeredAt time.Time
+}
+
+// ExitExecutor 平仓执行器（由 OMS 适配器实现，必须以只减仓、高优先级方式下单）
+type ExitExecutor interface {
+	ExecuteExit(ctx context.Context, req *ExitRequest) error
+}
+
+// StopConfig 止损管理配置（见 RISK_PROTOCOL 6. 防扫单与防插针优化）
+type StopConfig struct {
+	ConfirmWindow     time.Duration // 确认延迟窗口（默认 1.5s）
+	BypassMovePercent float64       // 瞬间波动超过该比例时绕过确认期（默认 3%）
+	ATRPeriod         int           // 追踪止损使用的 ATR 周期（默认 14）
+	DefaultPolicy     StopPolicy    // 未单独配置的策略使用的止损移动策略
+}
+
+// trackedStop 内部跟踪状态
+type trackedStop struct {
+	level       model.StopLevel
+	breachedAt  time.Time // 首次穿过止损位的时间（零值表示未穿过）
+	confirmedAt time.Time // 确认窗口内再次收到仍穿过止损位的标记价格的时间（零值表示尚未确认）
+	exiting     bool      // 平仓指令已发出，避免重复触发
+}
+
+// StopManager 服务端止损管理器
+// 职责：
+// 1. 按持仓维护止损/止盈价位
+// 2. 使用标记价格（Mark Price）判定触发，过滤 Last Price 插针
+// 3. 止损触发后等待确认窗口，瞬间大幅波动时立即平仓
+// 4. 按策略执行保本与 ATR 追踪止损，每次调整持久化
+// 5. 通过 ExitExecutor 将平仓交给 OMS 执行
+type StopManager struct {
+	mu       sync.Mutex
+	executor ExitExecutor
+	repo     port.StopRepo // 可选，nil 时不持久化
+	config   StopConfig
+
+	stops     map[string]*trackedStop // key: PositionID
+	lastMarks map[string]model.Money  // key: Symbol
+	atrs      map[string]*sizing.ATR  // key: Symbol
+	policies  map[string]StopPolicy   // key: StrategyID
+}
+
+// NewStopManager 创建止损管理器
+// repo 为 nil 时止损仅保存在内存中（回测）
+func NewStopManager(executor ExitExecutor, repo port.StopRepo, config StopConfig) *StopManager {
+	if config.ConfirmWindow == 0 {
+		config.ConfirmWindow = 1500 * time.Millisecond
+	}
+	if config.BypassMovePercent == 0 {
+		config.BypassMovePercent = 0.03
+	}
+
+	return &StopManager{
+		executor:  executor,
+		repo:      repo,
+		config:    config,
+		stops:     make(map[string]*trackedStop),
+		lastMarks: make(map[string]model.Money),
+		atrs:      make(map[string]*sizing.ATR),
+		policies:  make(map[string]StopPolicy),
+	}
+}
+
+// SetStrategyPolicy 为指定策略设置止损移动策略
+func (m *StopManager) SetStrategyPolicy(strategyID string, policy StopPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[strategyID] = policy
+}
+
+// PolicyFor 获取策略的止损移动策略
+func (m *StopManager) PolicyFor(strategyID string) StopPolicy {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.policyFor(strategyID)
+}
+
+func (m *StopManager) policyFor(strategyID string) StopPolicy {
+	if policy, ok := m.policies[strategyID]; ok {
+		return policy
+	}
+	return m.config.DefaultPolicy
+}
+
+// Restore 从持久化恢复止损（服务重启后调用）
+func (m *StopManager) Restore(ctx context.Context) error {
+	if m.repo == nil {
+		return nil
+	}
+
+	stops, err := m.repo.ListStops(ctx)
+	if err != nil {
+		return fmt.Errorf("restore stops failed: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range stops {
+		m.stops[s.PositionID] = &trackedStop{level: *s}
+	}
+	return nil
+}
+
+// Track 登记或替换持仓止损
+func (m *StopManager) Track(ctx context.Context, level model.StopLevel) error {
+	if level.PositionID == "" || level.Symbol == "" || !level.Quantity.IsPositive() {
+		return ErrInvalidStop
+	}
+	if level.StopLoss.IsZero() && level.TakeProfit.IsZero() {
+		return fmt.Errorf("%w: stop loss or take profit required", ErrInvalidStop)
+	}
+
+	if level.InitialStop.IsZero() {
+		level.InitialStop = level.StopLoss
+	}
+	level.UpdatedAt = time.Now()
+
+	m.mu.Lock()
+	m.stops[level.PositionID] = &trackedStop{level: level}
+	m.mu.Unlock()
+
+	if m.repo != nil {
+		return m.repo.SaveStop(ctx, &level)
+	}
+	return nil
+}
+
+// Resize 按最新净持仓调整止损的数量与开仓均价（加仓或部分平仓后调用），保留止损位与保本/追踪进度
+func (m *StopManager) Resize(ctx context.Context, positionID string, quantity, entryPrice model.Money) error {
+	if !quantity.IsPositive() {
+		return ErrInvalidStop
+	}
+
+	m.mu.Lock()
+	ts, ok := m.stops[positionID]
+	if !ok {
+		m.mu.Unlock()
+		return ErrStopNotFound
+	}
+	ts.level.Quantity = quantity
+	if entryPrice.IsPositive() {
+		ts.level.EntryPrice = entryPrice
+	}
+	ts.level.UpdatedAt = time.Now()
+	level := ts.level
+	m.mu.Unlock()
+
+	if m.repo != nil {
+		return m.repo.SaveStop(ctx, &level)
+	}
+	return nil
+}
+
+// Remove 移除持仓止损（持仓已平或人工撤销）
+func (m *StopManager) Remove(ctx context.Context, positionID string) error {
+	m.mu.Lock()
+	delete(m.stops, positionID)
+	m.mu.Unlock()
+
+	if m.repo != nil {
+		return m.repo.DeleteStop(ctx, positionID)
+	}
+	return nil
+}
+
+// Get 查询持仓止损
+func (m *StopManager) Get(positionID string) (model.StopLevel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ts, ok := m.stops[positionID]
+	if !ok {
+		return model.StopLevel{}, ErrStopNotFound
+	}
+	return ts.level, nil
+}
+
+// List 列出所有跟踪中的止损
+func (m *StopManager) List() []model.StopLevel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	levels := make([]model.StopLevel, 0, len(m.stops))
+	for _, ts := range m.stops {
+		levels = append(levels, ts.level)
+	}
+	return levels
+}
+
+// Adjustments 查询止损调整记录（未配置持久化时返回空）
+func (m *StopManager) Adjustments(ctx context.Context, positionID string, limit int) ([]*model.StopAdjustment, error) {
+	if m.repo == nil {
+		return nil, nil
+	}
+	return m.repo.ListAdjustments(ctx, positionID, limit)
+}
+
+// OnCandle K 线更新：刷新 ATR 并以收盘价作为标记价格（仅用于回测等没有标记价格推送的场景）
+func (m *StopManager) OnCandle(ctx context.Context, candle *model.Candle, now time.Time) error {
+	m.UpdateATR(candle)
+	return m.OnMarkPrice(ctx, candle.Symbol, candle.Close, now)
+}
+
+// UpdateATR K 线更新：仅刷新追踪止损使用的 ATR（标记价格由 OnMarkPrice 单独推送）
+func (m *StopManager) UpdateATR(candle *model.Candle) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	atr, ok := m.atrs[candle.Symbol]
+	if !ok {
+		atr = sizing.NewATR(m.config.ATRPeriod)
+		m.atrs[candle.Symbol] = atr
+	}
+	atr.Update(candle)
+}
+
+// OnMarkPrice 标记价格更新（行情驱动）
+func (m *StopManager) OnMarkPrice(ctx context.Context, symbol string, mark model.Money, now time.Time) error {
+	m.mu.Lock()
+	prevMark, hasPrev := m.lastMarks[symbol]
+	m.lastMarks[symbol] = mark
+
+	atr := model.Zero()
+	if a, ok := m.atrs[symbol]; ok && a.Ready() {
+		atr = a.Value()
+	}
+
+	var (
+		exits       []*ExitRequest
+		adjustments []*model.StopAdjustment
+		adjusted    []model.StopLevel
+	)
+	for _, ts := range m.stops {
+		if ts.level.Symbol != symbol || ts.exiting {
+			continue
+		}
+		if req := m.evaluate(ts, mark, prevMark, hasPrev, now); req != nil {
+			ts.exiting = true
+			exits = append(exits, req)
+			continue
+		}
+		if adj := m.policyFor(ts.level.StrategyID).adjust(&ts.level, mark, atr); adj != nil {
+			adj.PositionID = ts.level.PositionID
+			adj.Symbol = symbol
+			adj.MarkPrice = mark
+			adj.AdjustedAt = now
+
+			ts.level.StopLoss = adj.NewStop
+			ts.level.Stage = adj.Stage
+			ts.level.UpdatedAt = now
+			ts.breachedAt, ts.confirmedAt = time.Time{}, time.Time{}
+
+			adjustments = append(adjustments, adj)
+			adjusted = append(adjusted, ts.level)
+		}
+	}
+	m.mu.Unlock()
+
+	persistErr := m.persistAdjustments(ctx, adjusted, adjustments)
+	if err := m.execute(ctx, exits); err != nil {
+		return err
+	}
+	return persistErr
+}
+
+// CheckPending 检查确认窗口已到期的止损（定时驱动，窗口结束后没有新的标记价格推送时仍能及时平仓）
+// 仅当确认窗口内收到过新的标记价格且仍穿过止损位时触发：首次穿过后没有新价格确认的不视为有效突破
+func (m *StopManager) CheckPending(ctx context.Context, now time.Time) error {
+	m.mu.Lock()
+	var exits []*ExitRequest
+	for _, ts := range m.stops {
+		if ts.exiting || ts.breachedAt.IsZero() || ts.confirmedAt.IsZero() {
+			continue
+		}
+		mark, ok := m.lastMarks[ts.level.Symbol]
+		if !ok {
+			continue
+		}
+		if now.Sub(ts.breachedAt) >= m.config.ConfirmWindow && stopBreached(&ts.level, mark) {
+			ts.exiting = true
+			exits = append(exits, &ExitRequest{
+				Stop:        ts.level,
+				Reason:      ExitStopLoss,
+				MarkPrice:   mark,
+				TriggeredAt: now,
+			})
+		}
+	}
+	m.mu.Unlock()
+
+	return m.execute(ctx, exits)
+}
+
+// FlattenAll 市价平掉全部已登记止损的持仓，返回发出平仓指令的持仓数
+// 未登记止损的持仓不在此列：Kill Switch 按持仓账本平仓（见 oms.PositionFlattenAdapter）
+// 按最新标记价格计价（无行情时使用开仓均价），已在平仓中的持仓不重复下单；失败的持仓保留止损以便重试
+func (m *StopManager) FlattenAll(ctx context.Context, now time.Time) (int, error) {
+	m.mu.Lock()
+	var exits []*ExitRequest
+	for _, ts := range m.stops {
+		if ts.exiting {
+			continue
+		}
+		mark, ok := m.lastMarks[ts.level.Symbol]
+		if !ok {
+			mark = ts.level.EntryPrice
+		}
+		ts.exiting = true
+		exits = append(exits, &ExitRequest{
+			Stop:        ts.level,
+			Reason:      ExitKillSwitch,
+			MarkPrice:   mark,
+			TriggeredAt: now,
+		})
+	}
+	m.mu.Unlock()
+
+	return len(exits), m.execute(ctx, exits)
+}
+
+// Start 启动确认窗口巡检（后台 goroutine）
+func (m *StopManager) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				_ = m.CheckPending(ctx, now)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// evaluate 评估单个止损（调用方持有锁）
+func (m *StopManager) evaluate(ts *trackedStop, mark, prevMark model.Money, hasPrev bool, now time.Time) *ExitRequest {
+	level := &ts.level
+
+	// 1. 止盈：直接触发
+	if takeProfitReached(level, mark) {
+		return &ExitRequest{Stop: *level, Reason: ExitTakeProfit, MarkPrice: mark, TriggeredAt: now}
+	}
+
+	// 2. 未穿过止损位：清除确认状态（插针已回撤）
+	if !stopBreached(level, mark) {
+		ts.breachedAt, ts.confirmedAt = time.Time{}, time.Time{}
+		return nil
+	}
+
+	// 3. 瞬间大幅波动：绕过确认期
+	ref := level.StopLoss
+	if hasPrev && prevMark.IsPositive() {
+		ref = prevMark
+	}
+	move := mark.Sub(ref).Abs().Div(ref).Float64()
+	if move >= m.config.BypassMovePercent {
+		return &ExitRequest{Stop: *level, Reason: ExitStopLoss, MarkPrice: mark, TriggeredAt: now}
+	}
+
+	// 4. 首次穿过：开启确认窗口
+	if ts.breachedAt.IsZero() {
+		ts.breachedAt = now
+		return nil
+	}
+	if now.After(ts.breachedAt) {
+		ts.confirmedAt = now
+	}
+
+	// 5. 窗口期结束仍未回到止损位之上：触发
+	if now.Sub(ts.breachedAt) >= m.config.ConfirmWindow {
+		return &ExitRequest{Stop: *level, Reason: ExitStopLoss, MarkPrice: mark, TriggeredAt: now}
+	}
+
+	return nil
+}
+
+// persistAdjustments 持久化止损调整（先写记录再更新当前止损）
+func (m *StopManager) persistAdjustments(ctx context.Context, levels []model.StopLevel, adjustments []*model.StopAdjustment) error {
+	if m.repo == nil {
+		return nil
+	}
+
+	var lastErr error
+	for i, adj := range adjustments {
+		if err := m.repo.SaveAdjustment(ctx, adj); err != nil {
+			lastErr = err
+		}
+		if err := m.repo.SaveStop(ctx, &levels[i]); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// execute 执行平仓；成功后移除止损，失败则允许下次价格更新重试
+func (m *StopManager) execute(ctx context.Context, exits []*ExitRequest) error {
+	var lastErr error
+	for _, req := range exits {
+		err := m.executor.ExecuteExit(ctx, req)
+
+		m.mu.Lock()
+		if ts, ok := m.stops[req.Stop.PositionID]; ok {
+			if err != nil {
+				ts.exiting = false
+			} else {
+				delete(m.stops, req.Stop.PositionID)
+			}
+		}
+		m.mu.Unlock()
+
+		if err != nil {
+			lastErr = fmt.Errorf("exit %s (%s) failed: %w", req.Stop.PositionID, req.Reason, err)
+			continue
+		}
+		if m.repo != nil {
+			if err := m.repo.DeleteStop(ctx, req.Stop.PositionID); err != nil {
+				lastErr = err
+			}
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,285 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+type mockExecutor struct {
+	exits []*ExitRequest
+	err   error
+}
+
+func (e *mockExecutor) ExecuteExit(ctx context.Context, req *ExitRequest) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.exits = append(e.exits, req)
+	return nil
+}
+
+func newLongStop() model.StopLevel {
+	return model.StopLevel{
+		PositionID: "acc:test:BTCUSDT",
+		AccountID:  "acc",
+		Symbol:     "BTCUSDT",
+		Side:       model.OrderSideBuy,
+		Quantity:   model.MustMoney("0.1"),
+		EntryPrice: model.MustMoney("50000"),
+		StopLoss:   model.MustMoney("49000"),
+		TakeProfit: model.MustMoney("52000"),
+	}
+}
+
+func TestStopManager_ConfirmWindow(t *testing.T) {
+	ctx := context.Background()
+	exec := &mockExecutor{}
+	mgr := NewStopManager(exec, nil, StopConfig{})
+	if err := mgr.Track(ctx, newLongStop()); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+
+	base := time.Now()
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("49500"), base)
+
+	// 穿过止损位，进入确认窗口
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("48990"), base.Add(100*time.Millisecond))
+	if len(exec.exits) != 0 {
+		t.Fatalf("exit triggered before confirm window")
+	}
+
+	// 窗口内仍在止损位下方
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("48980"), base.Add(1*time.Second))
+	if len(exec.exits) != 0 {
+		t.Fatalf("exit triggered before confirm window elapsed")
+	}
+
+	// 窗口结束
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("48970"), base.Add(1700*time.Millisecond))
+	if len(exec.exits) != 1 {
+		t.Fatalf("expected 1 exit, got %d", len(exec.exits))
+	}
+
+	exit := exec.exits[0]
+	if exit.Reason != ExitStopLoss {
+		t.Errorf("reason = %s, want STOP_LOSS", exit.Reason)
+	}
+	if exit.Stop.ExitSide() != model.OrderSideSell {
+		t.Errorf("exit side = %s, want SELL", exit.Stop.ExitSide())
+	}
+	if _, err := mgr.Get("acc:test:BTCUSDT"); !errors.Is(err, ErrStopNotFound) {
+		t.Errorf("stop should be removed after exit, got %v", err)
+	}
+}
+
+func TestStopManager_WickFiltered(t *testing.T) {
+	ctx := context.Background()
+	exec := &mockExecutor{}
+	mgr := NewStopManager(exec, nil, StopConfig{})
+	_ = mgr.Track(ctx, newLongStop())
+
+	base := time.Now()
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("49100"), base)
+
+	// 插针穿过止损位后迅速收回
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("48950"), base.Add(200*time.Millisecond))
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("49200"), base.Add(800*time.Millisecond))
+
+	// 确认窗口到期时价格已回到止损位之上
+	if err := mgr.CheckPending(ctx, base.Add(2*time.Second)); err != nil {
+		t.Fatalf("CheckPending failed: %v", err)
+	}
+	if len(exec.exits) != 0 {
+		t.Fatalf("wick should not trigger exit, got %d exits", len(exec.exits))
+	}
+}
+
+func TestStopManager_PendingRequiresFreshMark(t *testing.T) {
+	ctx := context.Background()
+	exec := &mockExecutor{}
+	mgr := NewStopManager(exec, nil, StopConfig{})
+	_ = mgr.Track(ctx, newLongStop())
+
+	base := time.Now()
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("49100"), base)
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("48950"), base.Add(100*time.Millisecond))
+
+	// 窗口内没有新的标记价格：仅凭首次穿过的价格不触发
+	if err := mgr.CheckPending(ctx, base.Add(2*time.Second)); err != nil {
+		t.Fatalf("CheckPending failed: %v", err)
+	}
+	if len(exec.exits) != 0 {
+		t.Fatalf("stop should not fire without a confirming mark, got %d exits", len(exec.exits))
+	}
+
+	// 窗口内收到新的标记价格且仍在止损位下方：窗口到期后由巡检触发
+	confirmed := NewStopManager(exec, nil, StopConfig{})
+	_ = confirmed.Track(ctx, newLongStop())
+	_ = confirmed.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("48950"), base.Add(100*time.Millisecond))
+	_ = confirmed.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("48940"), base.Add(time.Second))
+	if err := confirmed.CheckPending(ctx, base.Add(2*time.Second)); err != nil {
+		t.Fatalf("CheckPending failed: %v", err)
+	}
+	if len(exec.exits) != 1 {
+		t.Fatalf("expected exit once a fresh mark confirmed the breach, got %d", len(exec.exits))
+	}
+}
+
+func TestStopManager_BypassOnLargeMove(t *testing.T) {
+	ctx := context.Background()
+	exec := &mockExecutor{}
+	mgr := NewStopManager(exec, nil, StopConfig{})
+	_ = mgr.Track(ctx, newLongStop())
+
+	base := time.Now()
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("49500"), base)
+
+	// 瞬间下跌 > 3%，立即平仓
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("47500"), base.Add(50*time.Millisecond))
+	if len(exec.exits) != 1 {
+		t.Fatalf("expected immediate exit, got %d", len(exec.exits))
+	}
+}
+
+func TestStopManager_TakeProfitAndShort(t *testing.T) {
+	ctx := context.Background()
+	exec := &mockExecutor{}
+	mgr := NewStopManager(exec, nil, StopConfig{})
+
+	_ = mgr.Track(ctx, newLongStop())
+	_ = mgr.Track(ctx, model.StopLevel{
+		PositionID: "acc:test:ETHUSDT",
+		Symbol:     "ETHUSDT",
+		Side:       model.OrderSideSell,
+		Quantity:   model.MustMoney("1"),
+		StopLoss:   model.MustMoney("3100"),
+	})
+
+	now := time.Now()
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("52000"), now)
+	if len(exec.exits) != 1 || exec.exits[0].Reason != ExitTakeProfit {
+		t.Fatalf("expected take profit exit, got %+v", exec.exits)
+	}
+
+	// 空头：价格上涨穿过止损位，窗口内新的标记价格确认
+	_ = mgr.OnMarkPrice(ctx, "ETHUSDT", model.MustMoney("3101"), now)
+	_ = mgr.OnMarkPrice(ctx, "ETHUSDT", model.MustMoney("3102"), now.Add(time.Second))
+	if err := mgr.CheckPending(ctx, now.Add(2*time.Second)); err != nil {
+		t.Fatalf("CheckPending failed: %v", err)
+	}
+	if len(exec.exits) != 2 {
+		t.Fatalf("expected short stop exit, got %d exits", len(exec.exits))
+	}
+	if side := exec.exits[1].Stop.ExitSide(); side != model.OrderSideBuy {
+		t.Errorf("short exit side = %s, want BUY", side)
+	}
+}
+
+func TestStopManager_RetryOnExecutorError(t *testing.T) {
+	ctx := context.Background()
+	exec := &mockExecutor{err: errors.New("gateway down")}
+	mgr := NewStopManager(exec, nil, StopConfig{})
+	_ = mgr.Track(ctx, newLongStop())
+
+	now := time.Now()
+	if err := mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("52500"), now); err == nil {
+		t.Fatal("expected executor error")
+	}
+	if _, err := mgr.Get("acc:test:BTCUSDT"); err != nil {
+		t.Fatalf("stop should be kept for retry: %v", err)
+	}
+
+	exec.err = nil
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("52500"), now.Add(time.Second))
+	if len(exec.exits) != 1 {
+		t.Fatalf("expected retry exit, got %d", len(exec.exits))
+	}
+}
+
+func TestStopManager_FlattenAll(t *testing.T) {
+	ctx := context.Background()
+	exec := &mockExecutor{}
+	mgr := NewStopManager(exec, nil, StopConfig{})
+
+	_ = mgr.Track(ctx, newLongStop())
+	_ = mgr.Track(ctx, model.StopLevel{
+		PositionID: "acc:test:ETHUSDT",
+		Symbol:     "ETHUSDT",
+		Side:       model.OrderSideSell,
+		Quantity:   model.MustMoney("1"),
+		EntryPrice: model.MustMoney("3000"),
+		StopLoss:   model.MustMoney("3100"),
+	})
+
+	now := time.Now()
+	_ = mgr.OnMarkPrice(ctx, "BTCUSDT", model.MustMoney("50500"), now)
+
+	count, err := mgr.FlattenAll(ctx, now)
+	if err != nil {
+		t.Fatalf("FlattenAll failed: %v", err)
+	}
+	if count != 2 || len(exec.exits) != 2 {
+		t.Fatalf("expected 2 exits, got count=%d exits=%d", count, len(exec.exits))
+	}
+	for _, exit := range exec.exits {
+		if exit.Reason != ExitKillSwitch {
+			t.Errorf("reason = %s, want KILL_SWITCH", exit.Reason)
+		}
+		// 有行情按标记价格，无行情按开仓均价
+		want := "50500"
+		if exit.Stop.Symbol == "ETHUSDT" {
+			want = "3000"
+		}
+		if exit.MarkPrice.String() != want {
+			t.Errorf("%s mark = %s, want %s", exit.Stop.Symbol, exit.MarkPrice, want)
+		}
+	}
+	if len(mgr.List()) != 0 {
+		t.Errorf("expected all stops removed, got %d", len(mgr.List()))
+	}
+}
+
+func TestStopManager_Resize(t *testing.T) {
+	ctx := context.Background()
+	m := NewStopManager(&mockExecutor{}, nil, StopConfig{})
+	if err := m.Track(ctx, newLongStop()); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	before, _ := m.Get("acc:test:BTCUSDT")
+
+	if err := m.Resize(ctx, "acc:test:BTCUSDT", model.MustMoney("0.25"), model.MustMoney("51000")); err != nil {
+		t.Fatalf("Resize failed: %v", err)
+	}
+	level, _ := m.Get("acc:test:BTCUSDT")
+	if !level.Quantity.EQ(model.MustMoney("0.25")) || !level.EntryPrice.EQ(model.MustMoney("51000")) {
+		t.Errorf("resized to %s @ %s, want 0.25 @ 51000", level.Quantity, level.EntryPrice)
+	}
+	if !level.StopLoss.EQ(before.StopLoss) || level.Stage != before.Stage {
+		t.Errorf("resize changed the stop: %s/%s, want %s/%s", level.StopLoss, level.Stage, before.StopLoss, before.Stage)
+	}
+
+	if err := m.Resize(ctx, "missing", model.MustMoney("1"), model.Zero()); !errors.Is(err, ErrStopNotFound) {
+		t.Errorf("resize missing stop: got %v, want ErrStopNotFound", err)
+	}
+}
+
+func TestStopManager_TrackValidation(t *testing.T) {
+	mgr := NewStopManager(&mockExecutor{}, nil, StopConfig{})
+
+	stop := newLongStop()
+	stop.StopLoss = model.Zero()
+	stop.TakeProfit = model.Zero()
+	if err := mgr.Track(context.Background(), stop); !errors.Is(err, ErrInvalidStop) {
+		t.Errorf("expected ErrInvalidStop, got %v", err)
+	}
+
+	stop = newLongStop()
+	stop.Quantity = model.Zero()
+	if err := mgr.Track(context.Background(), stop); !errors.Is(err, ErrInvalidStop) {
+		t.Errorf("expected ErrInvalidStop for zero quantity, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package reconcile
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/pkg/email"
+)
+
+// Alerter 对账告警渠道（对账失败或存在未修复差异时通知）
+type Alerter interface {
+	Alert(ctx context.Context, report *Report) error
+}
+
+// AlerterFunc 函数适配为告警渠道
+type AlerterFunc func(ctx context.Context, report *Report) error
+
+// Alert 实现 Alerter
+func (f AlerterFunc) Alert(ctx context.Context, report *Report) error {
+	return f(ctx, report)
+}
+
+// EmailAlerter 邮件告警渠道
+type EmailAlerter struct {
+	service    email.EmailService
+	sender     string
+	recipients []string
+}
+
+// NewEmailAlerter 创建邮件告警渠道
+func NewEmailAlerter(service email.EmailService, sender string, recipients []string) *EmailAlerter {
+	return &EmailAlerter{
+		service:    service,
+		sender:     sender,
+		recipients: recipients,
+	}
+}
+
+// Alert 实现 Alerter
+func (a *EmailAlerter) Alert(ctx context.Context, report *Report) error {
+	_, err := a.service.SendEmail(ctx, a.sender, a.recipients, report.Subject(), report.Body())
+	return err
+}
@@ -0,0 +1,386 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+// ErrInProgress 对账正在进行中
+var ErrInProgress = errors.New("reconcile: reconciliation already in progress")
+
+// TradingGate 下单开关（由 oms.Manager 实现，对账期间拒绝非只减仓订单）
+type TradingGate interface {
+	SetReconciling(reconciling bool)
+}
+
+// PositionBook 风控持仓（由 risk.Manager 实现，标的 -> 名义价值）
+type PositionBook interface {
+	Positions(ctx context.Context, accountID string) (map[string]model.Money, error)
+	SetPositions(ctx context.Context, accountID string, positions map[string]model.Money) error
+}
+
+// PriceSource 最新价格（用于将交易所余额折算为名义价值）
+type PriceSource interface {
+	GetLatestPrice(ctx context.Context, symbol string) (model.Money, error)
+}
+
+// Config 对账配置
+type Config struct {
+	AccountID         string        // 风控持仓所属账户
+	Symbols           []string      // 对账交易对（成交与持仓按交易对逐个查询）
+	QuoteAsset        string        // 计价资产（默认 USDT，交易对去掉计价资产后缀即为基础资产）
+	Lookback          time.Duration // 成交回溯窗口（默认 24 小时）
+	PositionTolerance float64       // 持仓名义价值允许的相对偏差（默认 1%）
+}
+
+// Reconciler 订单、成交与持仓对账
+// 以交易所为准，对比本地 OrderRepo、成交明细与风控持仓：
+// 可安全修复的差异（补录挂单与成交、推进订单状态、校正持仓）自动修复，其余差异告警；
+// 对账期间通过 TradingGate 暂停非只减仓订单，对账失败时保持暂停，需重新对账成功后恢复
+type Reconciler struct {
+	gateway port.SpotGateway
+	orders  port.OrderRepo
+	trades  port.TradeRepo
+	config  Config
+
+	gate      TradingGate
+	positions PositionBook
+	prices    PriceSource
+	alerters  []Alerter
+	onError   func(err error)
+
+	running atomic.Bool
+	mu      sync.RWMutex
+	last    *Report
+}
+
+// NewReconciler 创建对账器
+func NewReconciler(gateway port.SpotGateway, orders port.OrderRepo, trades port.TradeRepo, config Config) *Reconciler {
+	if config.QuoteAsset == "" {
+		config.QuoteAsset = "USDT"
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = 24 * time.Hour
+	}
+	if config.PositionTolerance <= 0 {
+		config.PositionTolerance = 0.01
+	}
+
+	return &Reconciler{
+		gateway: gateway,
+		orders:  orders,
+		trades:  trades,
+		config:  config,
+	}
+}
+
+// SetTradingGate 设置下单开关
+func (r *Reconciler) SetTradingGate(gate TradingGate) {
+	r.gate = gate
+}
+
+// SetPositionBook 设置风控持仓（未设置时跳过持仓对账）
+func (r *Reconciler) SetPositionBook(positions PositionBook) {
+	r.positions = positions
+}
+
+// SetPriceSource 设置价格来源（未设置时使用回溯窗口内最近一笔成交价）
+func (r *Reconciler) SetPriceSource(prices PriceSource) {
+	r.prices = prices
+}
+
+// AddAlerter 添加告警渠道
+func (r *Reconciler) AddAlerter(alerter Alerter) {
+	r.alerters = append(r.alerters, alerter)
+}
+
+// OnError 设置后台对账与告警失败的回调
+func (r *Reconciler) OnError(fn func(err error)) {
+	r.onError = fn
+}
+
+// Running 是否正在对账
+func (r *Reconciler) Running() bool {
+	return r.running.Load()
+}
+
+// Last 最近一次对账报告（尚未对账时返回 nil）
+func (r *Reconciler) Last() *Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.last
+}
+
+// Start 暂停下单并在后台执行启动对账
+func (r *Reconciler) Start(ctx context.Context) {
+	r.setReconciling(true)
+	go func() {
+		if _, err := r.Run(ctx, time.Now()); err != nil {
+			r.reportError(err)
+		}
+	}()
+}
+
+// Run 执行一次对账（对账期间暂停下单，成功后恢复）
+func (r *Reconciler) Run(ctx context.Context, now time.Time) (*Report, error) {
+	if !r.running.CompareAndSwap(false, true) {
+		return nil, ErrInProgress
+	}
+	defer r.running.Store(false)
+
+	r.setReconciling(true)
+	report := &Report{StartedAt: now}
+	err := r.reconcile(ctx, report, now)
+	report.FinishedAt = time.Now()
+	if err != nil {
+		report.Error = err.Error()
+	} else {
+		r.setReconciling(false)
+	}
+
+	r.mu.Lock()
+	r.last = report
+	r.mu.Unlock()
+
+	if err != nil || len(report.Unresolved()) > 0 {
+		r.alert(ctx, report)
+	}
+	return report, err
+}
+
+// reconcile 依次对账订单、成交与持仓（补录的挂单参与后续成交归属）
+func (r *Reconciler) reconcile(ctx context.Context, report *Report, now time.Time) error {
+	if err := r.reconcileOrders(ctx, report); err != nil {
+		return err
+	}
+
+	lastPrices, err := r.reconcileTrades(ctx, report, now)
+	if err != nil {
+		return err
+	}
+
+	if r.positions == nil {
+		return nil
+	}
+	return r.reconcilePositions(ctx, report, lastPrices)
+}
+
+// reconcileOrders 对账订单
+// 交易所挂单本地缺失时补录；本地活跃订单按交易所状态推进（停机期间成交或撤销）
+func (r *Reconciler) reconcileOrders(ctx context.Context, report *Report) error {
+	open, err := r.gateway.ListOpenOrders(ctx, "")
+	if err != nil {
+		return fmt.Errorf("list open orders failed: %w", err)
+	}
+
+	seen := make(map[string]bool, len(open))
+	for _, remote := range open {
+		seen[remote.ClientOrderID] = true
+
+		local, err := r.orders.GetOrder(ctx, remote.ClientOrderID)
+		if errors.Is(err, port.ErrOrderNotFound) {
+			diff := Diff{Kind: DiffOrphanOrder, Symbol: remote.Symbol, ClientOrderID: remote.ClientOrderID,
+				Detail: fmt.Sprintf("exchange open order %s missing locally", remote.ExchangeID)}
+			if err := r.orders.SaveOrder(ctx, remote); err != nil {
+				diff.Detail += ": " + err.Error()
+			} else {
+				diff.Repaired = true
+			}
+			report.add(diff)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("get order %s failed: %w", remote.ClientOrderID, err)
+		}
+		r.applyExchangeState(ctx, report, local, remote)
+	}
+
+	active, err := r.orders.ListActiveOrders(ctx)
+	if err != nil {
+		return fmt.Errorf("list active orders failed: %w", err)
+	}
+	for _, local := range active {
+		if seen[local.ClientOrderID] {
+			continue
+		}
+		remote, err := r.gateway.GetOrder(ctx, local.ClientOrderID)
+		if err != nil {
+			report.add(Diff{Kind: DiffOrderUnknown, Symbol: local.Symbol, ClientOrderID: local.ClientOrderID,
+				Detail: fmt.Sprintf("local %s order not found on exchange: %v", local.Status, err)})
+			continue
+		}
+		r.applyExchangeState(ctx, report, local, remote)
+	}
+	return nil
+}
+
+// applyExchangeState 按状态机将交易所订单状态应用到本地订单
+func (r *Reconciler) applyExchangeState(ctx context.Context, report *Report, local, remote *model.Order) {
+	if local.Status == remote.Status && local.Filled.EQ(remote.Filled) {
+		return
+	}
+
+	diff := Diff{Kind: DiffOrderStatus, Symbol: local.Symbol, ClientOrderID: local.ClientOrderID,
+		Detail: fmt.Sprintf("local %s filled %s, exchange %s filled %s", local.Status, local.Filled, remote.Status, remote.Filled)}
+	_, err := r.orders.ApplyUpdate(ctx, local.ClientOrderID, model.OrderUpdate{
+		Status:     remote.Status,
+		Filled:     remote.Filled,
+		Sequence:   remote.Sequence,
+		UpdateTime: remote.ExchangeUpdateTime,
+		Source:     model.OrderEventSourceReconcile,
+	})
+	switch {
+	case err == nil:
+		diff.Repaired = true
+	case errors.Is(err, model.ErrStaleOrderUpdate):
+		return // 本地已应用更新的状态
+	default:
+		diff.Detail += ": " + err.Error()
+	}
+	report.add(diff)
+}
+
+// reconcileTrades 补录回溯窗口内未入库的成交，返回各交易对最近成交价
+func (r *Reconciler) reconcileTrades(ctx context.Context, report *Report, now time.Time) (map[string]model.Money, error) {
+	since := now.Add(-r.config.Lookback)
+	lastPrices := make(map[string]model.Money, len(r.config.Symbols))
+
+	for _, symbol := range r.config.Symbols {
+		trades, err := r.gateway.ListTrades(ctx, symbol, since)
+		if err != nil {
+			return nil, fmt.Errorf("list %s trades failed: %w", symbol, err)
+		}
+
+		for _, t := range trades {
+			trade := *t
+			lastPrices[symbol] = trade.Price
+
+			known := true
+			if trade.ClientOrderID == "" && trade.ExchangeOrderID != "" {
+				order, err := r.orders.GetOrderByExchangeID(ctx, trade.ExchangeOrderID)
+				switch {
+				case err == nil:
+					trade.ClientOrderID = order.ClientOrderID
+				case errors.Is(err, port.ErrOrderNotFound):
+					known = false
+				default:
+					return nil, fmt.Errorf("get order %s failed: %w", trade.ExchangeOrderID, err)
+				}
+			}
+
+			inserted, err := r.trades.SaveTrade(ctx, &trade)
+			if err != nil {
+				return nil, fmt.Errorf("save trade %s failed: %w", trade.ExecID, err)
+			}
+			if !known {
+				report.add(Diff{Kind: DiffUnknownTrade, Symbol: symbol,
+					Detail: fmt.Sprintf("trade %s of exchange order %s %s %s@%s has no local order",
+						trade.ExecID, trade.ExchangeOrderID, trade.Side, trade.Quantity, trade.Price)})
+				continue
+			}
+			if inserted {
+				report.add(Diff{Kind: DiffMissingTrade, Symbol: symbol, ClientOrderID: trade.ClientOrderID, Repaired: true,
+					Detail: fmt.Sprintf("recorded trade %s %s %s@%s", trade.ExecID, trade.Side, trade.Quantity, trade.Price)})
+			}
+		}
+	}
+	return lastPrices, nil
+}
+
+// reconcilePositions 以交易所基础资产余额折算名义价值，校正风控持仓
+func (r *Reconciler) reconcilePositions(ctx context.Context, report *Report, lastPrices map[string]model.Money) error {
+	local, err := r.positions.Positions(ctx, r.config.AccountID)
+	if err != nil {
+		return fmt.Errorf("load positions failed: %w", err)
+	}
+
+	corrected := make(map[string]model.Money)
+	for _, symbol := range r.config.Symbols {
+		base := strings.TrimSuffix(symbol, r.config.QuoteAsset)
+		balance, err := r.gateway.GetBalance(ctx, base)
+		if err != nil {
+			return fmt.Errorf("get %s balance failed: %w", base, err)
+		}
+
+		notional := model.Zero()
+		if !balance.Total.IsZero() {
+			price, ok := r.price(ctx, symbol, lastPrices)
+			if !ok {
+				report.add(Diff{Kind: DiffPosition, Symbol: symbol,
+					Detail: fmt.Sprintf("no price to value %s %s balance", balance.Total, base)})
+				continue
+			}
+			notional = balance.Total.Mul(price)
+		}
+
+		if withinTolerance(local[symbol], notional, r.config.PositionTolerance) {
+			continue
+		}
+		corrected[symbol] = notional
+		report.add(Diff{Kind: DiffPosition, Symbol: symbol, Repaired: true,
+			Detail: fmt.Sprintf("position notional %s -> %s (%s %s)", local[symbol], notional, balance.Total, base)})
+	}
+
+	if len(corrected) == 0 {
+		return nil
+	}
+	if err := r.positions.SetPositions(ctx, r.config.AccountID, corrected); err != nil {
+		return fmt.Errorf("save positions failed: %w", err)
+	}
+	return nil
+}
+
+// price 标的价格（优先使用价格来源，失败时回退到最近成交价）
+func (r *Reconciler) price(ctx context.Context, symbol string, lastPrices map[string]model.Money) (model.Money, bool) {
+	if r.prices != nil {
+		if price, err := r.prices.GetLatestPrice(ctx, symbol); err == nil && price.IsPositive() {
+			return price, true
+		}
+	}
+	price, ok := lastPrices[symbol]
+	return price, ok && price.IsPositive()
+}
+
+// withinTolerance 两个名义价值的偏差是否在相对容差内
+func withinTolerance(a, b model.Money, tolerance float64) bool {
+	diff := a.Sub(b).Abs()
+	if diff.IsZero() {
+		return true
+	}
+	scale := a.Abs()
+	if b.Abs().GT(scale) {
+		scale = b.Abs()
+	}
+	return diff.LE(scale.Mul(model.NewMoneyFromFloat(tolerance)))
+}
+
+// setReconciling 设置下单开关
+func (r *Reconciler) setReconciling(reconciling bool) {
+	if r.gate != nil {
+		r.gate.SetReconciling(reconciling)
+	}
+}
+
+// alert 发送对账告警（单个渠道失败不影响其余渠道）
+func (r *Reconciler) alert(ctx context.Context, report *Report) {
+	for _, alerter := range r.alerters {
+		if err := alerter.Alert(ctx, report); err != nil {
+			r.reportError(fmt.Errorf("reconcile alert failed: %w", err))
+		}
+	}
+}
+
+// reportError 上报后台错误
+func (r *Reconciler) reportError(err error) {
+	if r.onError != nil {
+		r.onError(err)
+	}
+}
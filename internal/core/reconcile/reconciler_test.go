@@ -0,0 +1,205 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
+	"github.com/iluyuns/alpha-trade/internal/infra/order"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+	"github.com/iluyuns/alpha-trade/internal/infra/trade"
+)
+
+// fakeGateway 返回固定挂单、订单与成交的模拟交易所（余额由 mock.SpotExchange 提供）
+type fakeGateway struct {
+	*mock.SpotExchange
+	open    []*model.Order
+	orders  map[string]*model.Order
+	trades  []*model.Trade
+	openErr error
+}
+
+func (g *fakeGateway) ListOpenOrders(ctx context.Context, symbol string) ([]*model.Order, error) {
+	return g.open, g.openErr
+}
+
+func (g *fakeGateway) GetOrder(ctx context.Context, clientOrderID string) (*model.Order, error) {
+	if o, ok := g.orders[clientOrderID]; ok {
+		return o, nil
+	}
+	return nil, fmt.Errorf("order %s not found", clientOrderID)
+}
+
+func (g *fakeGateway) ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error) {
+	return g.trades, nil
+}
+
+// recordingGate 记录下单开关状态
+type recordingGate struct {
+	reconciling bool
+	calls       int
+}
+
+func (g *recordingGate) SetReconciling(reconciling bool) {
+	g.reconciling = reconciling
+	g.calls++
+}
+
+func newTestOrder(clientOrderID, exchangeID string, status model.OrderStatus, filled string) *model.Order {
+	return &model.Order{
+		ClientOrderID: clientOrderID,
+		ExchangeID:    exchangeID,
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeLimit,
+		Price:         model.MustMoney("50000"),
+		Quantity:      model.MustMoney("0.1"),
+		Filled:        model.MustMoney(filled),
+		Status:        status,
+	}
+}
+
+func TestReconciler_RepairsAndAlerts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	gateway := &fakeGateway{
+		SpotExchange: mock.NewSpotExchange(map[string]model.Money{"BTC": model.MustMoney("0.1")}),
+		open:         []*model.Order{newTestOrder("orphan-1", "EX-0", model.OrderStatusSubmitted, "0")},
+		orders: map[string]*model.Order{
+			"local-filled": newTestOrder("local-filled", "EX-1", model.OrderStatusFilled, "0.1"),
+		},
+		trades: []*model.Trade{
+			{ExecID: "T-1", ExchangeOrderID: "EX-1", Symbol: "BTCUSDT", Side: model.OrderSideBuy,
+				Price: model.MustMoney("50000"), Quantity: model.MustMoney("0.1"), TradedAt: now.Add(-time.Hour)},
+			{ExecID: "T-2", ExchangeOrderID: "EX-999", Symbol: "BTCUSDT", Side: model.OrderSideSell,
+				Price: model.MustMoney("51000"), Quantity: model.MustMoney("0.01"), TradedAt: now.Add(-time.Minute)},
+		},
+	}
+
+	orders := order.NewMemoryRepo()
+	for _, o := range []*model.Order{
+		newTestOrder("local-filled", "EX-1", model.OrderStatusSubmitted, "0"),
+		newTestOrder("local-gone", "EX-2", model.OrderStatusSubmitted, "0"),
+	} {
+		if err := orders.SaveOrder(ctx, o); err != nil {
+			t.Fatalf("SaveOrder failed: %v", err)
+		}
+	}
+	trades := trade.NewMemoryRepo()
+	riskMgr := risklogic.NewManager(riskrepo.NewMemoryRiskRepo(), risklogic.RiskConfig{})
+
+	gate := &recordingGate{}
+	var alerts []*Report
+	r := NewReconciler(gateway, orders, trades, Config{AccountID: "acc-1", Symbols: []string{"BTCUSDT"}})
+	r.SetTradingGate(gate)
+	r.SetPositionBook(riskMgr)
+	r.AddAlerter(AlerterFunc(func(_ context.Context, report *Report) error {
+		alerts = append(alerts, report)
+		return nil
+	}))
+
+	report, err := r.Run(ctx, now)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	kinds := make(map[DiffKind]bool)
+	for _, d := range report.Diffs {
+		kinds[d.Kind] = d.Repaired
+	}
+	want := map[DiffKind]bool{
+		DiffOrphanOrder:  true,
+		DiffOrderStatus:  true,
+		DiffOrderUnknown: false,
+		DiffMissingTrade: true,
+		DiffUnknownTrade: false,
+		DiffPosition:     true,
+	}
+	for kind, repaired := range want {
+		if got, ok := kinds[kind]; !ok || got != repaired {
+			t.Errorf("diff %s: present=%v repaired=%v, want repaired=%v", kind, ok, got, repaired)
+		}
+	}
+
+	// 补录挂单、推进订单状态并写入成交
+	if _, err := orders.GetOrder(ctx, "orphan-1"); err != nil {
+		t.Errorf("expected orphan order adopted: %v", err)
+	}
+	if o, _ := orders.GetOrder(ctx, "local-filled"); !o.IsFilled() {
+		t.Errorf("expected local order filled, got %s", o.Status)
+	}
+	saved, _ := trades.ListTrades(ctx, "BTCUSDT", now.Add(-2*time.Hour))
+	if len(saved) != 2 || saved[0].ClientOrderID != "local-filled" {
+		t.Errorf("unexpected saved trades: %+v", saved)
+	}
+
+	// 持仓按最近成交价折算：0.1 BTC x 51000
+	positions, _ := riskMgr.Positions(ctx, "acc-1")
+	if !positions["BTCUSDT"].EQ(model.MustMoney("5100")) {
+		t.Errorf("position = %s, want 5100", positions["BTCUSDT"])
+	}
+
+	// 对账完成后恢复下单；未修复差异告警
+	if gate.reconciling || gate.calls != 2 {
+		t.Errorf("expected gate released after reconcile, reconciling=%v calls=%d", gate.reconciling, gate.calls)
+	}
+	if len(alerts) != 1 || len(alerts[0].Unresolved()) != 2 {
+		t.Fatalf("expected one alert with 2 unresolved diffs, got %d", len(alerts))
+	}
+
+	// 再次对账：已修复的差异不再出现
+	report, err = r.Run(ctx, now)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, d := range report.Diffs {
+		if d.Repaired {
+			t.Errorf("unexpected repair on second run: %+v", d)
+		}
+	}
+}
+
+func TestReconciler_FailureKeepsTradingBlocked(t *testing.T) {
+	ctx := context.Background()
+
+	gateway := &fakeGateway{
+		SpotExchange: mock.NewSpotExchange(nil),
+		openErr:      errors.New("exchange unavailable"),
+	}
+	gate := &recordingGate{}
+	alerted := 0
+	r := NewReconciler(gateway, order.NewMemoryRepo(), trade.NewMemoryRepo(), Config{Symbols: []string{"BTCUSDT"}})
+	r.SetTradingGate(gate)
+	r.AddAlerter(AlerterFunc(func(context.Context, *Report) error {
+		alerted++
+		return nil
+	}))
+
+	if _, err := r.Run(ctx, time.Now()); err == nil {
+		t.Fatal("expected reconcile error")
+	}
+	if !gate.reconciling {
+		t.Error("expected trading to remain blocked after failed reconcile")
+	}
+	if last := r.Last(); last == nil || last.Error == "" {
+		t.Errorf("expected failed report, got %+v", last)
+	}
+	if alerted != 1 {
+		t.Errorf("expected 1 alert, got %d", alerted)
+	}
+
+	// 交易所恢复后重新对账，解除暂停
+	gateway.openErr = nil
+	if _, err := r.Run(ctx, time.Now()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if gate.reconciling {
+		t.Error("expected trading unblocked after successful reconcile")
+	}
+}
@@ -0,0 +1,90 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DiffKind 对账差异类型
+type DiffKind string
+
+const (
+	DiffOrphanOrder  DiffKind = "ORPHAN_ORDER"  // 交易所挂单本地缺失（下单成功但保存失败），补录
+	DiffOrderStatus  DiffKind = "ORDER_STATUS"  // 本地订单状态落后于交易所（停机期间成交或撤销），按状态机推进
+	DiffOrderUnknown DiffKind = "ORDER_UNKNOWN" // 本地活跃订单在交易所查询失败，需人工确认
+	DiffMissingTrade DiffKind = "MISSING_TRADE" // 成交未入库，补录
+	DiffUnknownTrade DiffKind = "UNKNOWN_TRADE" // 成交无对应本地订单（如交易所端人工下单），需人工确认
+	DiffPosition     DiffKind = "POSITION"      // 风控持仓与交易所余额不一致，以交易所为准校正
+)
+
+// Diff 对账差异
+type Diff struct {
+	Kind          DiffKind
+	Symbol        string
+	ClientOrderID string
+	Detail        string
+	Repaired      bool // 是否已自动修复（未修复的差异需告警）
+}
+
+// Report 对账报告
+type Report struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Diffs      []Diff
+	Error      string // 对账中断原因（非空时交易保持暂停）
+}
+
+// add 记录差异
+func (r *Report) add(diff Diff) {
+	r.Diffs = append(r.Diffs, diff)
+}
+
+// Repaired 已自动修复的差异数
+func (r *Report) Repaired() int {
+	n := 0
+	for _, d := range r.Diffs {
+		if d.Repaired {
+			n++
+		}
+	}
+	return n
+}
+
+// Unresolved 未修复的差异
+func (r *Report) Unresolved() []Diff {
+	var diffs []Diff
+	for _, d := range r.Diffs {
+		if !d.Repaired {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+// Subject 告警标题
+func (r *Report) Subject() string {
+	if r.Error != "" {
+		return fmt.Sprintf("[alpha-trade] Reconciliation failed, trading remains blocked (%s)", r.Error)
+	}
+	return fmt.Sprintf("[alpha-trade] Reconciliation found %d unresolved differences", len(r.Unresolved()))
+}
+
+// Body 告警正文
+func (r *Report) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "started: %s\n", r.StartedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "finished: %s\n", r.FinishedAt.UTC().Format(time.RFC3339))
+	if r.Error != "" {
+		fmt.Fprintf(&b, "error: %s\n", r.Error)
+	}
+	fmt.Fprintf(&b, "differences: %d (repaired %d)\n", len(r.Diffs), r.Repaired())
+	for _, d := range r.Diffs {
+		status := "UNRESOLVED"
+		if d.Repaired {
+			status = "REPAIRED"
+		}
+		fmt.Fprintf(&b, "%s %s %s %s: %s\n", status, d.Kind, d.Symbol, d.ClientOrderID, d.Detail)
+	}
+	return b.String()
+}
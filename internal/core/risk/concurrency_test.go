@@ -0,0 +1,71 @@
+package risk
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+// TestManager_ConcurrentRecordTrade 模拟多进程共享同一仓储并发记录交易，验证乐观锁不丢失更新
+// 建议以 go test -race 运行
+func TestManager_ConcurrentRecordTrade(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	ctx := context.Background()
+
+	// 两个 Manager 各自持有缓存，相当于两个进程
+	replicas := []*Manager{
+		NewManager(repo, RiskConfig{}),
+		NewManager(repo, RiskConfig{}),
+	}
+
+	const (
+		workers = 8
+		trades  = 50
+	)
+	scope := model.AccountScope("acc-1")
+
+	var (
+		wg        sync.WaitGroup
+		succeeded int64
+	)
+	for i := 0; i < workers; i++ {
+		mgr := replicas[i%len(replicas)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < trades; j++ {
+				if err := mgr.RecordTrade(ctx, scope, model.MustMoney("-1")); err == nil {
+					atomic.AddInt64(&succeeded, 1)
+				}
+				// 并发读取路径（缓存快照不可被写入方修改）
+				if _, err := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01")); err != nil {
+					t.Errorf("CheckPreTrade failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded == 0 {
+		t.Fatal("no trade recorded")
+	}
+
+	state, err := repo.LoadState(ctx, scope)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if int64(state.DailyTradeCount) != succeeded {
+		t.Errorf("DailyTradeCount = %d, want %d (lost updates)", state.DailyTradeCount, succeeded)
+	}
+	if int64(state.ConsecutiveLosses) != succeeded {
+		t.Errorf("ConsecutiveLosses = %d, want %d", state.ConsecutiveLosses, succeeded)
+	}
+	want := model.MustMoney("-1").Mul(model.NewMoneyFromInt(succeeded))
+	if !state.DailyPnL.EQ(want) {
+		t.Errorf("DailyPnL = %s, want %s", state.DailyPnL, want)
+	}
+}
@@ -3,6 +3,9 @@ package risk
 import "errors"
 
 var (
+	// ErrOrderRejected 订单未通过事前风控检查（OMS 以该错误包装拒绝原因）
+	ErrOrderRejected = errors.New("risk: order rejected")
+
 	// ErrRiskLimitExceeded 风控限额超限
 	ErrRiskLimitExceeded = errors.New("risk: limit exceeded")
 
@@ -26,4 +29,16 @@ var (
 
 	// ErrMaxDrawdownExceeded 最大回撤超限
 	ErrMaxDrawdownExceeded = errors.New("risk: max drawdown exceeded")
+
+	// ErrUnknownRule 未注册的风控规则
+	ErrUnknownRule = errors.New("risk: unknown rule")
+
+	// ErrUnknownRuleParam 未知的规则参数
+	ErrUnknownRuleParam = errors.New("risk: unknown rule parameter")
+
+	// ErrInvalidLimits 限额取值无效
+	ErrInvalidLimits = errors.New("risk: invalid limits")
+
+	// ErrLimitLooseningNotVerified 放宽限额需要提级认证
+	ErrLimitLooseningNotVerified = errors.New("risk: loosening limits requires step-up verification")
 )
@@ -0,0 +1,49 @@
+package risk
+
+import (
+	"fmt"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// haltRule 全局交易暂停拦截时的规则名称（写入决策记录）
+const haltRule = "trading_halt"
+
+// SetHalt 设置全局交易暂停（传入 nil 或已解除的记录时恢复交易）
+func (m *Manager) SetHalt(halt *model.TradingHalt) {
+	if halt != nil && !halt.IsActive() {
+		halt = nil
+	}
+	if halt != nil {
+		copied := *halt
+		halt = &copied
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.halt = halt
+}
+
+// Halt 当前生效的全局交易暂停（未暂停时返回 nil）
+func (m *Manager) Halt() *model.TradingHalt {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.halt == nil {
+		return nil
+	}
+	copied := *m.halt
+	return &copied
+}
+
+// checkHalt 全局交易暂停期间拦截非只减仓订单
+func (m *Manager) checkHalt(req *OrderContext) (DecisionDetail, bool) {
+	m.mu.RLock()
+	halt := m.halt
+	m.mu.RUnlock()
+
+	if halt == nil || req.ReduceOnly {
+		return DecisionDetail{}, false
+	}
+	return NewBlock(fmt.Sprintf("trading halted: %s", halt.Reason), haltRule), true
+}
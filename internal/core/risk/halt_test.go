@@ -0,0 +1,51 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestCheckPreTrade_HaltBlocksOpeningOrders(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{MaxSinglePositionPercent: 1, MaxTotalExposurePercent: 1})
+	ctx := context.Background()
+
+	mgr.SetHalt(&model.TradingHalt{
+		ID:       1,
+		Status:   model.TradingHaltStatusActive,
+		Reason:   "exchange incident",
+		HaltedAt: time.Now(),
+	})
+
+	decision, err := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01"))
+	if err != nil {
+		t.Fatalf("CheckPreTrade failed: %v", err)
+	}
+	if !decision.IsBlocked() || decision.TriggeredRule != haltRule {
+		t.Fatalf("expected blocked by trading halt, got %v (%s)", decision.Decision, decision.TriggeredRule)
+	}
+
+	// 平仓（只减仓）在暂停期间放行
+	order := newRuleTestOrder("0.01")
+	order.ReduceOnly = true
+	if decision, _ := mgr.CheckPreTrade(ctx, order); decision.IsBlocked() {
+		t.Errorf("expected reduce-only allowed during halt, got blocked: %s", decision.Reason)
+	}
+
+	// 等待第二位管理员确认时仍处于暂停
+	mgr.SetHalt(&model.TradingHalt{ID: 1, Status: model.TradingHaltStatusReleasePending})
+	if decision, _ := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01")); !decision.IsBlocked() {
+		t.Error("expected blocked while release is pending")
+	}
+
+	// 解除后恢复
+	mgr.SetHalt(&model.TradingHalt{ID: 1, Status: model.TradingHaltStatusReleased})
+	if mgr.Halt() != nil {
+		t.Fatal("expected no halt after release")
+	}
+	if decision, _ := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01")); decision.IsBlocked() {
+		t.Errorf("expected allowed after release, got blocked: %s", decision.Reason)
+	}
+}
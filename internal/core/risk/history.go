@@ -0,0 +1,232 @@
+package risk
+
+import (
+	"context"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	"github.com/iluyuns/alpha-trade/internal/pkg/metrics"
+)
+
+// HistoryConfig 风控状态历史配置
+type HistoryConfig struct {
+	BufferSize        int           // 异步缓冲容量（默认 1024，满时丢弃并计数）
+	BatchSize         int           // 单次批量写入条数（默认 100）
+	FlushInterval     time.Duration // 定时写入间隔（默认 1s）
+	PeriodicRetention time.Duration // 定时快照保留时长（默认 30 天）
+	EventRetention    time.Duration // 变更 / 滚动 / 熔断快照保留时长（默认 365 天）
+}
+
+// HistoryRecorder 风控状态历史异步记录器
+// 状态变更（交易、日切、熔断开关）与定时快照写入时间序列，用于回撤曲线与熔断复盘
+type HistoryRecorder struct {
+	repo   port.RiskHistoryRepo
+	config HistoryConfig
+	queue  chan *model.RiskStateSnapshot
+}
+
+// NewHistoryRecorder 创建风控状态历史记录器
+func NewHistoryRecorder(repo port.RiskHistoryRepo, config HistoryConfig) *HistoryRecorder {
+	if config.BufferSize == 0 {
+		config.BufferSize = 1024
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval == 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.PeriodicRetention == 0 {
+		config.PeriodicRetention = 30 * 24 * time.Hour
+	}
+	if config.EventRetention == 0 {
+		config.EventRetention = 365 * 24 * time.Hour
+	}
+
+	return &HistoryRecorder{
+		repo:   repo,
+		config: config,
+		queue:  make(chan *model.RiskStateSnapshot, config.BufferSize),
+	}
+}
+
+// Record 投递一条快照（缓冲已满时丢弃，返回 false）
+func (h *HistoryRecorder) Record(snapshot *model.RiskStateSnapshot) bool {
+	select {
+	case h.queue <- snapshot:
+		return true
+	default:
+		metrics.DefaultMetrics.RiskHistoryDropped.Inc()
+		return false
+	}
+}
+
+// Start 启动后台批量写入（ctx 结束时写入剩余快照后退出）
+func (h *HistoryRecorder) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(h.config.FlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]*model.RiskStateSnapshot, 0, h.config.BatchSize)
+		for {
+			select {
+			case snapshot := <-h.queue:
+				batch = append(batch, snapshot)
+				if len(batch) >= h.config.BatchSize {
+					h.write(ctx, batch)
+					batch = batch[:0]
+				}
+			case <-ticker.C:
+				h.write(ctx, batch)
+				batch = batch[:0]
+			case <-ctx.Done():
+				h.write(context.Background(), append(batch, h.drain()...))
+				return
+			}
+		}
+	}()
+}
+
+// Flush 同步写入缓冲中的全部快照
+func (h *HistoryRecorder) Flush(ctx context.Context) {
+	h.write(ctx, h.drain())
+}
+
+// Purge 按保留策略清理过期快照，返回删除条数
+func (h *HistoryRecorder) Purge(ctx context.Context, now time.Time) (int64, error) {
+	periodic, err := h.repo.PurgeSnapshots(ctx,
+		[]string{model.RiskHistoryKindPeriodic},
+		now.Add(-h.config.PeriodicRetention),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := h.repo.PurgeSnapshots(ctx,
+		[]string{
+			model.RiskHistoryKindChange,
+			model.RiskHistoryKindDailyRoll,
+			model.RiskHistoryKindCircuitOpen,
+			model.RiskHistoryKindCircuitClose,
+		},
+		now.Add(-h.config.EventRetention),
+	)
+	return periodic + events, err
+}
+
+// drain 取出缓冲中的全部快照
+func (h *HistoryRecorder) drain() []*model.RiskStateSnapshot {
+	var snapshots []*model.RiskStateSnapshot
+	for {
+		select {
+		case snapshot := <-h.queue:
+			snapshots = append(snapshots, snapshot)
+		default:
+			return snapshots
+		}
+	}
+}
+
+// write 批量写入（失败时丢弃并计数，历史快照不影响交易）
+func (h *HistoryRecorder) write(ctx context.Context, batch []*model.RiskStateSnapshot) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := h.repo.SaveSnapshots(ctx, batch); err != nil {
+		metrics.DefaultMetrics.RiskHistoryDropped.Add(float64(len(batch)))
+	}
+}
+
+// SetHistoryRecorder 设置风控状态历史记录器（状态变更时写入快照）
+func (m *Manager) SetHistoryRecorder(history *HistoryRecorder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history = history
+}
+
+// StartHistorySnapshots 定时为本进程已加载的全部作用域写入快照，并按保留策略清理过期快照
+func (m *Manager) StartHistorySnapshots(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastPurge := time.Now()
+		for {
+			select {
+			case now := <-ticker.C:
+				m.SnapshotHistory(ctx, now)
+				if now.Sub(lastPurge) >= time.Hour {
+					m.mu.RLock()
+					history := m.history
+					m.mu.RUnlock()
+					if history != nil {
+						_, _ = history.Purge(ctx, now)
+					}
+					lastPurge = now
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// SnapshotHistory 为本进程已加载的全部作用域写入定时快照（从持久化读取最新状态）
+func (m *Manager) SnapshotHistory(ctx context.Context, now time.Time) {
+	m.mu.RLock()
+	history := m.history
+	scopes := make([]model.RiskScope, 0, len(m.stateCache))
+	for _, cached := range m.stateCache {
+		scopes = append(scopes, cached.state.Scope())
+	}
+	m.mu.RUnlock()
+
+	if history == nil {
+		return
+	}
+	for _, scope := range scopes {
+		state, err := m.repo.LoadState(ctx, scope)
+		if err != nil {
+			continue
+		}
+		history.Record(model.NewRiskStateSnapshot(state, model.RiskHistoryKindPeriodic, "", now))
+	}
+}
+
+// recordChange 写入状态变更快照（未设置记录器或关键字段未变化时忽略）
+func (m *Manager) recordChange(prev, next *model.RiskState, reason string) {
+	m.mu.RLock()
+	history := m.history
+	m.mu.RUnlock()
+
+	if history == nil {
+		return
+	}
+	kind, changed := classifyChange(prev, next)
+	if !changed {
+		return
+	}
+	history.Record(model.NewRiskStateSnapshot(next, kind, reason, time.Now()))
+}
+
+// classifyChange 按状态变化判定快照类型（熔断开关优先于日切，其余为普通变更）
+func classifyChange(prev, next *model.RiskState) (string, bool) {
+	switch {
+	case !prev.CircuitBreakerOpen && next.CircuitBreakerOpen:
+		return model.RiskHistoryKindCircuitOpen, true
+	case prev.CircuitBreakerOpen && !next.CircuitBreakerOpen:
+		return model.RiskHistoryKindCircuitClose, true
+	case !prev.DailyResetTime.Equal(next.DailyResetTime):
+		return model.RiskHistoryKindDailyRoll, true
+	}
+
+	changed := !prev.CurrentEquity.EQ(next.CurrentEquity) ||
+		!prev.PeakEquity.EQ(next.PeakEquity) ||
+		!prev.DailyPnL.EQ(next.DailyPnL) ||
+		!prev.TotalExposure.EQ(next.TotalExposure) ||
+		prev.DailyTradeCount != next.DailyTradeCount ||
+		prev.ConsecutiveLosses != next.ConsecutiveLosses ||
+		prev.CircuitBreakerUntil != next.CircuitBreakerUntil
+	return model.RiskHistoryKindChange, changed
+}
@@ -0,0 +1,124 @@
+package risk
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+	historyrepo "github.com/iluyuns/alpha-trade/internal/infra/riskhistory"
+)
+
+func TestHistory_RecordsCircuitBreakerReplay(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	history := historyrepo.NewMemoryRepo()
+	recorder := NewHistoryRecorder(history, HistoryConfig{})
+	ctx := context.Background()
+
+	mgr := NewManager(repo, RiskConfig{MaxConsecutiveLosses: 2})
+	mgr.SetHistoryRecorder(recorder)
+
+	scope := model.AccountScope("acc-1")
+	for i := 0; i < 2; i++ {
+		if err := mgr.RecordTrade(ctx, scope, model.MustMoney("-100")); err != nil {
+			t.Fatalf("RecordTrade failed: %v", err)
+		}
+	}
+	decision, err := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01"))
+	if err != nil {
+		t.Fatalf("CheckPreTrade failed: %v", err)
+	}
+	if !decision.IsBlocked() {
+		t.Fatalf("expected block, got %+v", decision)
+	}
+	// 熔断期间的检查不产生新快照
+	if _, err := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01")); err != nil {
+		t.Fatalf("CheckPreTrade failed: %v", err)
+	}
+	recorder.Flush(ctx)
+
+	snapshots, _ := history.ListSnapshots(ctx, model.RiskStateHistoryFilter{Scope: scope})
+	var kinds []string
+	for _, s := range snapshots {
+		kinds = append(kinds, s.Kind)
+	}
+	want := []string{model.RiskHistoryKindChange, model.RiskHistoryKindChange, model.RiskHistoryKindCircuitOpen}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+
+	open := snapshots[2]
+	if !strings.Contains(open.Reason, "ConsecutiveLosses") || open.ConsecutiveLosses != 2 || !open.CircuitBreakerOpen {
+		t.Errorf("unexpected circuit open snapshot %+v", open)
+	}
+	if snapshots[1].StateVersion >= open.StateVersion {
+		t.Errorf("state versions not increasing: %d -> %d", snapshots[1].StateVersion, open.StateVersion)
+	}
+}
+
+func TestHistory_RecordsCircuitBreakerClose(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	history := historyrepo.NewMemoryRepo()
+	recorder := NewHistoryRecorder(history, HistoryConfig{})
+	ctx := context.Background()
+
+	state := model.NewRiskState("acc-1", model.MustMoney("10000"))
+	state.CircuitBreakerOpen = true
+	state.CircuitBreakerUntil = time.Now().Add(-time.Minute).Unix()
+	if err := repo.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	mgr := NewManager(repo, RiskConfig{})
+	mgr.SetHistoryRecorder(recorder)
+	if _, err := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01")); err != nil {
+		t.Fatalf("CheckPreTrade failed: %v", err)
+	}
+	recorder.Flush(ctx)
+
+	snapshots, _ := history.ListSnapshots(ctx, model.RiskStateHistoryFilter{
+		Scope: model.AccountScope("acc-1"),
+		Kinds: []string{model.RiskHistoryKindCircuitClose},
+	})
+	if len(snapshots) != 1 || snapshots[0].Reason != "circuit breaker expired" {
+		t.Fatalf("unexpected close snapshots %+v", snapshots)
+	}
+}
+
+func TestHistory_PeriodicSnapshotAndRetention(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	history := historyrepo.NewMemoryRepo()
+	recorder := NewHistoryRecorder(history, HistoryConfig{
+		PeriodicRetention: time.Hour,
+		EventRetention:    24 * time.Hour,
+	})
+	ctx := context.Background()
+
+	mgr := NewManager(repo, RiskConfig{})
+	mgr.SetHistoryRecorder(recorder)
+	if _, err := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01")); err != nil {
+		t.Fatalf("CheckPreTrade failed: %v", err)
+	}
+
+	now := time.Now()
+	mgr.SnapshotHistory(ctx, now.Add(-2*time.Hour))
+	mgr.SnapshotHistory(ctx, now)
+	recorder.Record(model.NewRiskStateSnapshot(model.NewRiskState("acc-1", model.Zero()), model.RiskHistoryKindCircuitOpen, "old", now.Add(-2*time.Hour)))
+	recorder.Flush(ctx)
+
+	purged, err := recorder.Purge(ctx, now)
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	// 账户级与标的级（熔断规则按作用域加载）各有一条过期的定时快照
+	if purged != 2 {
+		t.Errorf("purged = %d, want 2", purged)
+	}
+
+	left, _ := history.ListSnapshots(ctx, model.RiskStateHistoryFilter{Scope: model.AccountScope("acc-1")})
+	if len(left) != 2 {
+		t.Errorf("remaining = %d, want 2", len(left))
+	}
+}
@@ -0,0 +1,346 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+// limitKind 限额取值类型
+type limitKind int
+
+const (
+	limitFloat limitKind = iota // 比例或金额
+	limitInt                    // 整数（次数、倍数）
+	limitBool                   // 开关（0 或 1）
+)
+
+// limitField RiskConfig 单项限额（参数名、取值类型、读写与放宽方向）
+type limitField struct {
+	key    string
+	kind   limitKind
+	get    func(cfg *RiskConfig) float64
+	set    func(cfg *RiskConfig, v float64)
+	looser func(old, new float64) bool
+}
+
+// check 校验取值与字段类型一致（整数与开关不做截断，避免保存的值与放宽判定、审计记录不一致）
+func (f limitField) check(v float64) error {
+	switch f.kind {
+	case limitInt:
+		if math.IsInf(v, 0) || v != math.Trunc(v) {
+			return fmt.Errorf("%w: %s must be a whole number, got %v", ErrInvalidLimits, f.key, v)
+		}
+	case limitBool:
+		if v != 0 && v != 1 {
+			return fmt.Errorf("%w: %s must be 0 or 1, got %v", ErrInvalidLimits, f.key, v)
+		}
+	}
+	return nil
+}
+
+// higherLooser 上限类参数：调大或置零（关闭）为放宽，从关闭改为任意值为收紧
+func higherLooser(old, new float64) bool {
+	if new == 0 {
+		return old != 0
+	}
+	if old == 0 {
+		return false
+	}
+	return new > old
+}
+
+// lowerLooser 下限类参数：调小为放宽
+func lowerLooser(old, new float64) bool {
+	return new < old
+}
+
+// limitFields 可运行时调整的限额（key 同规则参数名）
+var limitFields = []limitField{
+	{
+		key:    "max_consecutive_losses",
+		kind:   limitInt,
+		get:    func(c *RiskConfig) float64 { return float64(c.MaxConsecutiveLosses) },
+		set:    func(c *RiskConfig, v float64) { c.MaxConsecutiveLosses = int(v) },
+		looser: higherLooser,
+	},
+	{
+		key:    "max_daily_drawdown",
+		get:    func(c *RiskConfig) float64 { return c.MaxDailyDrawdown },
+		set:    func(c *RiskConfig, v float64) { c.MaxDailyDrawdown = v },
+		looser: higherLooser,
+	},
+	{
+		key:    "max_total_mdd",
+		get:    func(c *RiskConfig) float64 { return c.MaxTotalMDD },
+		set:    func(c *RiskConfig, v float64) { c.MaxTotalMDD = v },
+		looser: higherLooser,
+	},
+	{
+		key:    "max_single_position_percent",
+		get:    func(c *RiskConfig) float64 { return c.MaxSinglePositionPercent },
+		set:    func(c *RiskConfig, v float64) { c.MaxSinglePositionPercent = v },
+		looser: higherLooser,
+	},
+	{
+		key:    "max_total_exposure_percent",
+		get:    func(c *RiskConfig) float64 { return c.MaxTotalExposurePercent },
+		set:    func(c *RiskConfig, v float64) { c.MaxTotalExposurePercent = v },
+		looser: higherLooser,
+	},
+	{
+		key:    "min_cash_reserve_percent",
+		get:    func(c *RiskConfig) float64 { return c.MinCashReservePercent },
+		set:    func(c *RiskConfig, v float64) { c.MinCashReservePercent = v },
+		looser: lowerLooser,
+	},
+	{
+		key:    "max_leverage",
+		kind:   limitInt,
+		get:    func(c *RiskConfig) float64 { return float64(c.MaxLeverage) },
+		set:    func(c *RiskConfig, v float64) { c.MaxLeverage = int(v) },
+		looser: higherLooser,
+	},
+	{
+		key:  "force_leverage_one",
+		kind: limitBool,
+		get: func(c *RiskConfig) float64 {
+			if c.ForceLeverageOne {
+				return 1
+			}
+			return 0
+		},
+		set:    func(c *RiskConfig, v float64) { c.ForceLeverageOne = v != 0 },
+		looser: lowerLooser,
+	},
+	{
+		key:    "large_order_threshold",
+		get:    func(c *RiskConfig) float64 { return c.LargeOrderThreshold },
+		set:    func(c *RiskConfig, v float64) { c.LargeOrderThreshold = v },
+		looser: higherLooser,
+	},
+	{
+		key:    "max_price_deviation",
+		get:    func(c *RiskConfig) float64 { return c.MaxPriceDeviation },
+		set:    func(c *RiskConfig, v float64) { c.MaxPriceDeviation = v },
+		looser: higherLooser,
+	},
+	{
+		key:    "max_order_notional",
+		get:    func(c *RiskConfig) float64 { return c.MaxOrderNotional },
+		set:    func(c *RiskConfig, v float64) { c.MaxOrderNotional = v },
+		looser: higherLooser,
+	},
+}
+
+// findLimitField 按参数名查找限额
+func findLimitField(key string) (limitField, bool) {
+	for _, f := range limitFields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return limitField{}, false
+}
+
+// applyRuleParams 在配置副本上应用参数覆盖（key 为 snake_case 字段名，取值与字段类型不符时返回 ErrInvalidLimits）
+func applyRuleParams(cfg RiskConfig, params map[string]float64) (RiskConfig, error) {
+	for key, v := range params {
+		f, ok := findLimitField(key)
+		if !ok {
+			return cfg, fmt.Errorf("%w: %s", ErrUnknownRuleParam, key)
+		}
+		if err := f.check(v); err != nil {
+			return cfg, err
+		}
+		f.set(&cfg, v)
+	}
+	return cfg, nil
+}
+
+// Params 以参数名导出全部限额
+func (c RiskConfig) Params() map[string]float64 {
+	params := make(map[string]float64, len(limitFields))
+	for _, f := range limitFields {
+		params[f.key] = f.get(&c)
+	}
+	return params
+}
+
+// Validate 校验限额取值（不允许负数，比例类下限不超过 100%）
+func (c RiskConfig) Validate() error {
+	for _, f := range limitFields {
+		if f.get(&c) < 0 {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidLimits, f.key)
+		}
+	}
+	if c.MinCashReservePercent > 1 {
+		return fmt.Errorf("%w: min_cash_reserve_percent must not exceed 1", ErrInvalidLimits)
+	}
+	return nil
+}
+
+// DiffLimits 比较两份限额，返回变更项（按 limitFields 顺序）
+func DiffLimits(old, new RiskConfig) []model.RiskLimitChange {
+	var changes []model.RiskLimitChange
+	for _, f := range limitFields {
+		o, n := f.get(&old), f.get(&new)
+		if o == n {
+			continue
+		}
+		changes = append(changes, model.RiskLimitChange{
+			Field:    f.key,
+			Old:      o,
+			New:      n,
+			Loosened: f.looser(o, n),
+		})
+	}
+	return changes
+}
+
+// LimitUpdate 运行时限额变更请求
+type LimitUpdate struct {
+	Params    map[string]float64 // 变更的限额（未列出的保持不变）
+	ChangedBy int64              // 操作人（用户 ID）
+	Verified  bool               // 是否已通过提级认证（放宽限额时必须）
+	Reason    string             // 变更原因
+}
+
+// SetLimitRepo 设置限额版本仓储（未设置时变更仅在内存生效）
+func (m *Manager) SetLimitRepo(repo port.RiskLimitRepo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limitRepo = repo
+}
+
+// Config 当前生效的限额快照
+func (m *Manager) Config() RiskConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config
+}
+
+// LimitVersion 当前生效的限额版本号（0 表示启动配置）
+func (m *Manager) LimitVersion() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.limitVersion
+}
+
+// UpdateLimits 运行时变更限额
+// 收紧立即生效；任一项放宽时要求已通过提级认证，否则返回 ErrLimitLooseningNotVerified
+// 新版本先持久化再原子替换配置并重建所有规则集；无变更时返回 nil
+func (m *Manager) UpdateLimits(ctx context.Context, update LimitUpdate) (*model.RiskLimitVersion, error) {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	m.mu.RLock()
+	current, version, repo := m.config, m.limitVersion, m.limitRepo
+	m.mu.RUnlock()
+
+	next, err := applyRuleParams(current, update.Params)
+	if err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+
+	changes := DiffLimits(current, next)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	record := &model.RiskLimitVersion{
+		Version:   version + 1,
+		Limits:    next.Params(),
+		Changes:   changes,
+		ChangedBy: update.ChangedBy,
+		Verified:  update.Verified,
+		Reason:    update.Reason,
+		CreatedAt: time.Now(),
+	}
+	if record.Loosened() && !update.Verified {
+		return nil, ErrLimitLooseningNotVerified
+	}
+
+	if repo != nil {
+		if err := repo.SaveVersion(ctx, record); err != nil {
+			return nil, fmt.Errorf("save limit version failed: %w", err)
+		}
+	}
+
+	if err := m.swapConfig(next, record.Version); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// RestoreLimits 启动时恢复最新的限额版本（覆盖配置文件中的限额）
+func (m *Manager) RestoreLimits(ctx context.Context) error {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	m.mu.RLock()
+	current, repo := m.config, m.limitRepo
+	m.mu.RUnlock()
+	if repo == nil {
+		return nil
+	}
+
+	latest, err := repo.LatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("load latest limit version failed: %w", err)
+	}
+	if latest == nil {
+		return nil
+	}
+
+	next, err := applyRuleParams(current, latest.Limits)
+	if err != nil {
+		return fmt.Errorf("limit version %d: %w", latest.Version, err)
+	}
+	return m.swapConfig(next, latest.Version)
+}
+
+// LimitHistory 按版本号倒序列出最近的限额版本
+func (m *Manager) LimitHistory(ctx context.Context, limit int) ([]*model.RiskLimitVersion, error) {
+	m.mu.RLock()
+	repo := m.limitRepo
+	m.mu.RUnlock()
+
+	if repo == nil {
+		return nil, nil
+	}
+	return repo.ListVersions(ctx, limit)
+}
+
+// swapConfig 基于新限额重建所有规则集并原子替换（调用方持有 updateMu）
+func (m *Manager) swapConfig(cfg RiskConfig, version int64) error {
+	m.mu.RLock()
+	specs := make(map[string][]*model.RiskRuleSpec, len(m.ruleSpecs))
+	for accountID, s := range m.ruleSpecs {
+		specs[accountID] = s
+	}
+	m.mu.RUnlock()
+
+	ruleSets := make(map[string][]Rule, len(specs))
+	for accountID, s := range specs {
+		rules, err := m.registry.Build(m, cfg, s)
+		if err != nil {
+			return fmt.Errorf("rebuild rule set %s: %w", accountID, err)
+		}
+		ruleSets[accountID] = rules
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = cfg
+	m.limitVersion = version
+	m.ruleSets = ruleSets
+	return nil
+}
@@ -0,0 +1,165 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/infra/risklimit"
+)
+
+func TestDiffLimits_Direction(t *testing.T) {
+	base := RiskConfig{
+		MaxLeverage:           2,
+		MaxDailyDrawdown:      0.05,
+		MinCashReservePercent: 0.3,
+		ForceLeverageOne:      true,
+	}
+
+	tests := []struct {
+		name         string
+		params       map[string]float64
+		wantLoosened bool
+	}{
+		{"raise max leverage", map[string]float64{"max_leverage": 3}, true},
+		{"lower max leverage", map[string]float64{"max_leverage": 1}, false},
+		{"disable daily drawdown", map[string]float64{"max_daily_drawdown": 0}, true},
+		{"enable disabled limit", map[string]float64{"max_total_mdd": 0.2}, false},
+		{"lower cash reserve", map[string]float64{"min_cash_reserve_percent": 0.2}, true},
+		{"raise cash reserve", map[string]float64{"min_cash_reserve_percent": 0.4}, false},
+		{"stop forcing 1x", map[string]float64{"force_leverage_one": 0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := applyRuleParams(base, tt.params)
+			if err != nil {
+				t.Fatalf("applyRuleParams failed: %v", err)
+			}
+			changes := DiffLimits(base, next)
+			if len(changes) != 1 {
+				t.Fatalf("expected 1 change, got %+v", changes)
+			}
+			if changes[0].Loosened != tt.wantLoosened {
+				t.Errorf("loosened: got %v, want %v (%+v)", changes[0].Loosened, tt.wantLoosened, changes[0])
+			}
+		})
+	}
+}
+
+func TestUpdateLimits_TightenAppliesImmediately(t *testing.T) {
+	repo := risklimit.NewMemoryRepo()
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{MaxSinglePositionPercent: 0.6})
+	mgr.SetLimitRepo(repo)
+	ctx := context.Background()
+
+	// 0.1 BTC * 50000 = 5000 USD（50%），收紧前放行
+	if decision, _ := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.1")); !decision.IsAllowed() {
+		t.Fatalf("expected allowed before update, got %v", decision.Decision)
+	}
+
+	version, err := mgr.UpdateLimits(ctx, LimitUpdate{
+		Params:    map[string]float64{"max_single_position_percent": 0.3},
+		ChangedBy: 1,
+		Reason:    "tighten",
+	})
+	if err != nil {
+		t.Fatalf("UpdateLimits failed: %v", err)
+	}
+	if version.Version != 1 || version.Loosened() || mgr.LimitVersion() != 1 {
+		t.Errorf("unexpected version: %+v", version)
+	}
+	if mgr.Config().MaxSinglePositionPercent != 0.3 {
+		t.Errorf("config not swapped: %+v", mgr.Config())
+	}
+
+	// 规则集按新限额重建
+	if decision, _ := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.1")); !decision.ShouldReduce() {
+		t.Errorf("expected reduce after tightening, got %v", decision.Decision)
+	}
+
+	history, _ := mgr.LimitHistory(ctx, 10)
+	if len(history) != 1 || history[0].Changes[0].Field != "max_single_position_percent" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestUpdateLimits_LooseningRequiresVerification(t *testing.T) {
+	repo := risklimit.NewMemoryRepo()
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{MaxLeverage: 2, MaxDailyDrawdown: 0.05})
+	mgr.SetLimitRepo(repo)
+	ctx := context.Background()
+
+	// 同时收紧与放宽：整体视为放宽
+	update := LimitUpdate{
+		Params: map[string]float64{"max_leverage": 3, "max_daily_drawdown": 0.03},
+	}
+	if _, err := mgr.UpdateLimits(ctx, update); !errors.Is(err, ErrLimitLooseningNotVerified) {
+		t.Fatalf("expected ErrLimitLooseningNotVerified, got %v", err)
+	}
+	if mgr.Config().MaxLeverage != 2 || mgr.LimitVersion() != 0 {
+		t.Errorf("config must not change without verification: %+v", mgr.Config())
+	}
+	if versions, _ := repo.ListVersions(ctx, 0); len(versions) != 0 {
+		t.Errorf("expected no persisted version, got %d", len(versions))
+	}
+
+	update.Verified = true
+	version, err := mgr.UpdateLimits(ctx, update)
+	if err != nil {
+		t.Fatalf("UpdateLimits failed: %v", err)
+	}
+	if !version.Loosened() || !version.Verified || len(version.Changes) != 2 {
+		t.Errorf("unexpected version: %+v", version)
+	}
+	if mgr.Config().MaxLeverage != 3 {
+		t.Errorf("expected max leverage 3, got %d", mgr.Config().MaxLeverage)
+	}
+}
+
+func TestUpdateLimits_InvalidAndNoop(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{MaxLeverage: 2})
+	ctx := context.Background()
+
+	if _, err := mgr.UpdateLimits(ctx, LimitUpdate{Params: map[string]float64{"max_leverage": -1}}); !errors.Is(err, ErrInvalidLimits) {
+		t.Errorf("expected ErrInvalidLimits, got %v", err)
+	}
+	if _, err := mgr.UpdateLimits(ctx, LimitUpdate{Params: map[string]float64{"unknown": 1}}); !errors.Is(err, ErrUnknownRuleParam) {
+		t.Errorf("expected ErrUnknownRuleParam, got %v", err)
+	}
+	// 整数与开关参数不截断：2.9 倍杠杆不能以 2 倍保存
+	for key, v := range map[string]float64{"max_leverage": 2.9, "max_consecutive_losses": 3.5, "force_leverage_one": 0.5} {
+		if _, err := mgr.UpdateLimits(ctx, LimitUpdate{Params: map[string]float64{key: v}, Verified: true}); !errors.Is(err, ErrInvalidLimits) {
+			t.Errorf("%s=%v: expected ErrInvalidLimits, got %v", key, v, err)
+		}
+	}
+	if mgr.Config().MaxLeverage != 2 || mgr.LimitVersion() != 0 {
+		t.Errorf("rejected update should not change limits: leverage=%d version=%d", mgr.Config().MaxLeverage, mgr.LimitVersion())
+	}
+
+	version, err := mgr.UpdateLimits(ctx, LimitUpdate{Params: map[string]float64{"max_leverage": 2}})
+	if err != nil || version != nil {
+		t.Errorf("expected no-op, got %+v (%v)", version, err)
+	}
+}
+
+func TestRestoreLimits(t *testing.T) {
+	repo := risklimit.NewMemoryRepo()
+	ctx := context.Background()
+
+	first := NewManager(&mockRiskRepo{}, RiskConfig{MaxLeverage: 2})
+	first.SetLimitRepo(repo)
+	if _, err := first.UpdateLimits(ctx, LimitUpdate{Params: map[string]float64{"max_leverage": 1}}); err != nil {
+		t.Fatalf("UpdateLimits failed: %v", err)
+	}
+
+	// 重启后以最新版本覆盖配置文件中的限额
+	restarted := NewManager(&mockRiskRepo{}, RiskConfig{MaxLeverage: 2})
+	restarted.SetLimitRepo(repo)
+	if err := restarted.RestoreLimits(ctx); err != nil {
+		t.Fatalf("RestoreLimits failed: %v", err)
+	}
+	if restarted.Config().MaxLeverage != 1 || restarted.LimitVersion() != 1 {
+		t.Errorf("unexpected restored config: %+v (version %d)", restarted.Config(), restarted.LimitVersion())
+	}
+}
@@ -2,6 +2,9 @@ package risk
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,9 +34,19 @@ type OrderContext struct {
 	ProtectPrice model.Money // 保护价
 	CurrentPrice model.Money // 当前市价（用于计算名义价值）
 	AccountID    string      // 账户ID
+	StrategyID   string      // 策略ID（绩效风控按策略降级，为空时不生效）
 }
 
-// RiskConfig 风控配置快照（不可变）
+// Scope 订单所属的最细风控作用域（账户 -> 策略 -> 标的）
+func (r *OrderContext) Scope() model.RiskScope {
+	return model.RiskScope{
+		AccountID:  r.AccountID,
+		StrategyID: r.StrategyID,
+		Symbol:     r.Symbol,
+	}
+}
+
+// RiskConfig 风控配置快照（不可变，运行时变更通过 Manager.UpdateLimits 整体替换）
 type RiskConfig struct {
 	// 熔断器配置
 	MaxConsecutiveLosses int     // 最大连续亏损次数
@@ -61,98 +74,407 @@ type Manager struct {
 	repo   port.RiskRepo
 	config RiskConfig
 
-	// 内存缓存（减少 IO）
-	stateCache map[string]*model.RiskState
+	// 限额运行时变更（updateMu 串行化配置与规则集的重建）
+	updateMu     sync.Mutex
+	limitRepo    port.RiskLimitRepo
+	limitVersion int64
+
+	// 内存缓存（减少 IO，写时复制：缓存快照只读，写入成功后整体替换）
+	stateCache map[string]*cachedState
+
+	// 策略绩效统计（可选）
+	performance *PerformanceTracker
+
+	// 风控决策记录与状态历史（可选）
+	recorder *Recorder
+	history  *HistoryRecorder
+
+	// 交易日定义（key: AccountID，DefaultRuleSet 为默认定义）与交易日开始净值快照（可选）
+	sessions  map[string]model.TradingSession
+	snapshots port.AssetSnapshotRepo
+
+	// 全局交易暂停（Kill Switch，非 nil 时拒绝全部非只减仓订单）
+	halt *model.TradingHalt
+
+	// 规则注册表与按账户装配的规则集（key: AccountID，DefaultRuleSet 为默认规则集）
+	registry  *Registry
+	ruleSets  map[string][]Rule
+	ruleSpecs map[string][]*model.RiskRuleSpec // 规则集声明（限额变更后据此重建）
 }
 
-// NewManager 创建风控管理器
+// DefaultRuleSet 默认规则集 key（未单独配置规则集的账户使用）
+const DefaultRuleSet = "default"
+
+// NewManager 创建风控管理器（默认规则集启用全部内置规则）
 func NewManager(repo port.RiskRepo, config RiskConfig) *Manager {
-	return &Manager{
+	m := &Manager{
 		repo:       repo,
 		config:     config,
-		stateCache: make(map[string]*model.RiskState),
+		stateCache: make(map[string]*cachedState),
+		registry:   NewRegistry(),
+		ruleSets:   make(map[string][]Rule),
+		ruleSpecs:  make(map[string][]*model.RiskRuleSpec),
+		sessions:   map[string]model.TradingSession{DefaultRuleSet: model.DefaultTradingSession()},
+	}
+
+	// 内置规则参数均来自 RiskConfig，不会装配失败
+	specs := m.registry.DefaultSpecs()
+	rules, _ := m.registry.Build(m, config, specs)
+	m.ruleSets[DefaultRuleSet] = rules
+	m.ruleSpecs[DefaultRuleSet] = specs
+	return m
+}
+
+// Registry 规则注册表（用于注册自定义规则，需在 SetRuleSet 之前完成）
+func (m *Manager) Registry() *Registry {
+	return m.registry
+}
+
+// SetRuleSet 设置账户规则集（accountID 为空或 DefaultRuleSet 时替换默认规则集）
+// specs 中的参数覆盖基于全局 RiskConfig 的副本，未列出的规则不参与评估
+func (m *Manager) SetRuleSet(accountID string, specs []*model.RiskRuleSpec) error {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	rules, err := m.registry.Build(m, m.Config(), specs)
+	if err != nil {
+		return err
+	}
+	if accountID == "" {
+		accountID = DefaultRuleSet
 	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ruleSets[accountID] = rules
+	m.ruleSpecs[accountID] = specs
+	return nil
+}
+
+// LoadRuleSets 从配置源加载所有账户规则集
+func (m *Manager) LoadRuleSets(ctx context.Context, repo port.RiskRuleRepo) error {
+	sets, err := repo.ListRuleSets(ctx)
+	if err != nil {
+		return fmt.Errorf("load rule sets failed: %w", err)
+	}
+	for accountID, specs := range sets {
+		if err := m.SetRuleSet(accountID, specs); err != nil {
+			return fmt.Errorf("account %s: %w", accountID, err)
+		}
+	}
+	return nil
+}
+
+// Rules 账户当前生效的规则集（按优先级升序）
+func (m *Manager) Rules(accountID string) []Rule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if rules, ok := m.ruleSets[accountID]; ok {
+		return rules
+	}
+	return m.ruleSets[DefaultRuleSet]
+}
+
+// SetPerformanceTracker 设置策略绩效统计（启用按策略的动态风险降级）
+func (m *Manager) SetPerformanceTracker(tracker *PerformanceTracker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.performance = tracker
+}
+
+// SetRecorder 设置风控决策记录器（Block / Reduce 全量记录，Allow 抽样）
+func (m *Manager) SetRecorder(recorder *Recorder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recorder = recorder
+}
+
+// record 投递风控决策记录（未设置记录器时忽略）
+func (m *Manager) record(req *OrderContext, state *model.RiskState, decision DecisionDetail) {
+	m.mu.RLock()
+	recorder := m.recorder
+	m.mu.RUnlock()
+
+	if recorder == nil || !recorder.shouldRecord(decision) {
+		return
+	}
+	recorder.Record(newRiskRecord(req, state, decision, time.Now()))
+}
+
+// StrategyPerformance 查询策略绩效快照（未启用绩效统计时返回 false）
+func (m *Manager) StrategyPerformance(strategyID string, now time.Time) (model.StrategyPerformance, bool) {
+	m.mu.RLock()
+	tracker := m.performance
+	m.mu.RUnlock()
+
+	if tracker == nil {
+		return model.StrategyPerformance{}, false
+	}
+	return tracker.Snapshot(strategyID, now), true
+}
+
+// SizeMultiplier 策略当前仓位系数（正常 1.0，减半模式 0.5，冷静期 0）
+func (m *Manager) SizeMultiplier(strategyID string) float64 {
+	if strategyID == "" {
+		return 1
+	}
+	perf, ok := m.StrategyPerformance(strategyID, time.Now())
+	if !ok {
+		return 1
+	}
+	return perf.SizeMultiplier
 }
 
 // CheckPreTrade 交易前风控检查（核心入口）
-// 按规则顺序短路评估：CircuitBreaker -> PositionLimit -> FatFinger
+// 按账户规则集的优先级依次评估（默认：CircuitBreaker -> StrategyPerformance -> PositionLimit）
+// Block 立即短路；Reduce 不中断，后续规则评估降档后的订单，最终返回累计的降档建议
 func (m *Manager) CheckPreTrade(ctx context.Context, req *OrderContext) (DecisionDetail, error) {
 	startTime := time.Now()
 	metrics.DefaultMetrics.RiskChecksTotal.Inc()
 
-	// 1. 加载风控状态
-	state, err := m.loadState(ctx, req.AccountID, "")
-	if err != nil {
+	// 0. 全局交易暂停：不属于规则集，无法通过规则配置关闭；只减仓订单放行以便平仓
+	if decision, halted := m.checkHalt(req); halted {
+		metrics.DefaultMetrics.RiskCheckLatency.Observe(time.Since(startTime).Seconds())
 		metrics.DefaultMetrics.RiskChecksBlocked.Inc()
-		return NewBlock("failed to load risk state", "internal"), err
+		m.record(req, nil, decision)
+		return decision, nil
 	}
 
-	// 2. 每日重置检查
-	if state.ShouldResetDaily(time.Now()) {
-		state.ResetDaily()
-		_ = m.repo.SaveState(ctx, state)
+	// 1. 加载账户级风控状态（策略 / 标的级状态由熔断规则按作用域加载）
+	state, err := m.loadScopeState(ctx, model.AccountScope(req.AccountID))
+	if err != nil {
+		metrics.DefaultMetrics.RiskChecksBlocked.Inc()
+		decision := NewBlock("failed to load risk state", "internal")
+		m.record(req, nil, decision)
+		return decision, err
 	}
 
-	// 3. 规则链检查（短路评估）
-	rules := []RuleFunc{
-		m.checkCircuitBreaker,
-		m.checkPositionLimit,
-		// m.checkFatFinger, // TODO: implement in rule_fat_finger.go
+	// 2. 规则链检查（Block 短路，Reduce 传递）
+	decision, _ := m.evaluateRules(ctx, req, state, nil)
+
+	// 记录延迟
+	metrics.DefaultMetrics.RiskCheckLatency.Observe(time.Since(startTime).Seconds())
+
+	switch {
+	case decision.IsBlocked():
+		metrics.DefaultMetrics.RiskChecksBlocked.Inc()
+		if decision.TriggeredRule == "circuit_breaker" {
+			metrics.DefaultMetrics.CircuitBreakerOpened.Inc()
+		}
+	case decision.ShouldReduce():
+		metrics.DefaultMetrics.RiskChecksBlocked.Inc()
+	default:
+		metrics.DefaultMetrics.RiskChecksAllowed.Inc()
 	}
+	m.record(req, state, decision)
+	return decision, nil
+}
 
-	for _, rule := range rules {
-		decision := rule(ctx, req, state)
-		if !decision.IsAllowed() {
-			// 记录延迟
-			metrics.DefaultMetrics.RiskCheckLatency.Observe(time.Since(startTime).Seconds())
-			metrics.DefaultMetrics.RiskChecksBlocked.Inc()
-			if decision.IsBlocked() && decision.TriggeredRule == "circuit_breaker" {
-				metrics.DefaultMetrics.CircuitBreakerOpened.Inc()
-			}
-			return decision, nil
+// ruleTrace 规则评估回调（rule 为规则，req 为该规则评估的订单，decision 为评估结果）
+type ruleTrace func(rule Rule, req *OrderContext, decision DecisionDetail)
+
+// evaluateRules 按账户规则集依次评估订单，返回最终决策与降档后的订单
+// Block 立即短路；Reduce 不中断，后续规则评估降档后的订单；trace 非 nil 时逐条回调评估结果
+func (m *Manager) evaluateRules(ctx context.Context, req *OrderContext, state *model.RiskState, trace ruleTrace) (DecisionDetail, *OrderContext) {
+	var (
+		current = req
+		reduced *DecisionDetail
+		reasons []string
+	)
+	for _, rule := range m.Rules(req.AccountID) {
+		if !ruleApplies(rule, current) {
+			continue
+		}
+
+		decision := rule.Evaluate(ctx, current, state)
+		if trace != nil {
+			trace(rule, current, decision)
+		}
+		switch {
+		case decision.IsBlocked():
+			return decision, current
+
+		case decision.ShouldReduce():
+			current = applyReduce(current, decision)
+			reasons = append(reasons, decision.Reason)
+			reduced = &decision
 		}
 	}
 
-	// 记录延迟
-	metrics.DefaultMetrics.RiskCheckLatency.Observe(time.Since(startTime).Seconds())
-	metrics.DefaultMetrics.RiskChecksAllowed.Inc()
+	if reduced != nil {
+		return NewReduce(
+			strings.Join(reasons, "; "),
+			reduced.TriggeredRule,
+			current.Quantity.String(),
+			current.Leverage,
+		), current
+	}
+	return NewAllow(), current
+}
 
-	return NewAllow(), nil
+// applyReduce 按降档建议生成新的订单上下文（不修改原订单）
+func applyReduce(req *OrderContext, decision DecisionDetail) *OrderContext {
+	reduced := *req
+	if qty, err := model.NewMoney(decision.SuggestedQuantity); err == nil {
+		reduced.Quantity = qty
+	}
+	if decision.SuggestedLeverage > 0 {
+		reduced.Leverage = decision.SuggestedLeverage
+	}
+	return &reduced
 }
 
 // RuleFunc 风控规则函数签名
 type RuleFunc func(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail
 
+// maxStateUpdateRetries 乐观锁冲突时的最大重试次数
+const maxStateUpdateRetries = 5
+
+// stateCacheTTL 状态缓存有效期（多进程部署时其他进程的写入最迟在该时间后可见）
+const stateCacheTTL = time.Second
+
+// cachedState 缓存的状态快照（只读）
+type cachedState struct {
+	state    *model.RiskState
+	loadedAt time.Time
+}
+
 // loadState 加载风控状态（带缓存）
-func (m *Manager) loadState(ctx context.Context, accountID, symbol string) (*model.RiskState, error) {
+// 返回快照的副本，调用方可自由修改；持久化必须经过 updateState
+func (m *Manager) loadState(ctx context.Context, scope model.RiskScope) (*model.RiskState, error) {
 	m.mu.RLock()
-	cacheKey := accountID + ":" + symbol
-	cached, exists := m.stateCache[cacheKey]
+	cached, exists := m.stateCache[scope.Key()]
 	m.mu.RUnlock()
 
-	if exists {
-		return cached, nil
+	if exists && time.Since(cached.loadedAt) < stateCacheTTL {
+		return cached.state.Clone(), nil
 	}
 
 	// 从持久化加载
-	state, err := m.repo.LoadState(ctx, accountID, symbol)
+	state, err := m.repo.LoadState(ctx, scope)
 	if err != nil {
 		return nil, err
 	}
 
 	// 写入缓存
+	m.cacheState(state)
+	return state, nil
+}
+
+// cacheState 以副本替换缓存快照（不会用旧版本覆盖新版本）
+func (m *Manager) cacheState(state *model.RiskState) {
 	m.mu.Lock()
-	m.stateCache[cacheKey] = state
-	m.mu.Unlock()
+	defer m.mu.Unlock()
 
+	key := state.Scope().Key()
+	if cached, ok := m.stateCache[key]; ok && cached.state.Version > state.Version {
+		return
+	}
+	m.stateCache[key] = &cachedState{
+		state:    state.Clone(),
+		loadedAt: time.Now(),
+	}
+}
+
+// updateState 以乐观锁更新风控状态
+// 在 state 的副本上应用 mutate 后 CAS 写入；版本冲突时重新加载最新状态并重新应用 mutate
+// 成功后 state 更新为写入后的状态并按 reason 写入状态历史，失败时 state 保持不变
+// 模拟检查（见 Simulate）只在 state 上应用 mutate，不写入持久化、缓存与历史
+func (m *Manager) updateState(ctx context.Context, state *model.RiskState, reason string, mutate func(s *model.RiskState)) error {
+	if isDryRun(ctx) {
+		mutate(state)
+		return nil
+	}
+
+	scope := state.Scope()
+	next := state.Clone()
+	for attempt := 0; ; attempt++ {
+		prev := next.Clone()
+		mutate(next)
+		err := m.repo.CompareAndSwapState(ctx, next)
+		if err == nil {
+			m.cacheState(next)
+			m.recordChange(prev, next, reason)
+			*state = *next
+			return nil
+		}
+
+		m.InvalidateCache(scope)
+		if !errors.Is(err, port.ErrRiskStateConflict) {
+			return err
+		}
+		metrics.DefaultMetrics.RiskStateConflicts.Inc()
+		if attempt >= maxStateUpdateRetries {
+			return err
+		}
+
+		latest, err := m.repo.LoadState(ctx, scope)
+		if err != nil {
+			return err
+		}
+		next = latest
+	}
+}
+
+// loadScopeState 加载作用域风控状态（到达交易日重置时刻时滚动当日统计）
+func (m *Manager) loadScopeState(ctx context.Context, scope model.RiskScope) (*model.RiskState, error) {
+	state, err := m.loadState(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if state.ShouldResetDaily(now) {
+		m.rollState(ctx, state, now)
+	}
 	return state, nil
 }
 
+// RecordTrade 记录已平仓交易盈亏（沿作用域链逐级更新：账户 -> 策略 -> 标的）
+// 账户级只更新当日盈亏与连续亏损（净值由 UpdateEquity 同步）；
+// 策略 / 标的级以账户当前净值为起点累计盈亏曲线，用于计算该作用域的回撤
+// 各级状态分别以乐观锁写入，并发记录不会丢失更新
+func (m *Manager) RecordTrade(ctx context.Context, scope model.RiskScope, pnl model.Money) error {
+	var accountEquity model.Money
+	for i, s := range scope.Chain() {
+		state, err := m.loadScopeState(ctx, s)
+		if err != nil {
+			return fmt.Errorf("load %s risk state failed: %w", s, err)
+		}
+
+		isAccount := i == 0
+		session := m.Session(s.AccountID)
+		err = m.updateState(ctx, state, fmt.Sprintf("trade pnl %s", pnl), func(st *model.RiskState) {
+			if now := time.Now(); st.ShouldResetDaily(now) {
+				st.RollDaily(session, now)
+			}
+			if !isAccount {
+				if !st.InitialEquity.IsPositive() {
+					st.InitialEquity = accountEquity
+					st.CurrentEquity = accountEquity
+					st.PeakEquity = accountEquity
+				}
+				st.UpdateEquity(st.CurrentEquity.Add(pnl))
+			}
+			st.RecordTrade(pnl)
+		})
+		if err != nil {
+			return fmt.Errorf("save %s risk state failed: %w", s, err)
+		}
+
+		if isAccount {
+			accountEquity = state.CurrentEquity
+		}
+	}
+	return nil
+}
+
 // InvalidateCache 清除缓存（状态变更后调用）
-func (m *Manager) InvalidateCache(accountID, symbol string) {
+func (m *Manager) InvalidateCache(scope model.RiskScope) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	delete(m.stateCache, accountID+":"+symbol)
+	delete(m.stateCache, scope.Key())
 }
 
 // checkFatFinger Fat Finger 检测（待实现）
@@ -0,0 +1,252 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+// PerformanceConfig 绩效风控配置（见 RISK_PROTOCOL 7.2 动态风险降级）
+type PerformanceConfig struct {
+	Window             int           // 滚动窗口交易笔数（默认 10）
+	MinWinRate         float64       // 胜率下限，低于则进入减半模式（默认 30%）
+	HalfSizeMultiplier float64       // 减半模式仓位系数（默认 0.5）
+	LossStreak         int           // 触发冷静期的连续亏损次数（默认 5）
+	CoolingOff         time.Duration // 冷静期时长（默认 24h）
+}
+
+// PerformanceTracker 策略滚动绩效统计
+// 职责：
+// 1. 按策略维护最近 N 笔已平仓交易（数据源为 settlements 表）
+// 2. 计算胜率、平均盈亏、期望值与 R 倍数
+// 3. 判定策略风险模式：正常 / 减半 / 冷静期
+type PerformanceTracker struct {
+	mu     sync.RWMutex
+	repo   port.SettlementRepo // 可选，nil 时仅统计 Record 的交易
+	config PerformanceConfig
+
+	trades map[string][]*model.Settlement // key: StrategyID，按平仓时间升序
+}
+
+// NewPerformanceTracker 创建绩效统计器
+func NewPerformanceTracker(repo port.SettlementRepo, config PerformanceConfig) *PerformanceTracker {
+	if config.Window == 0 {
+		config.Window = 10
+	}
+	if config.MinWinRate == 0 {
+		config.MinWinRate = 0.3
+	}
+	if config.HalfSizeMultiplier == 0 {
+		config.HalfSizeMultiplier = 0.5
+	}
+	if config.LossStreak == 0 {
+		config.LossStreak = 5
+	}
+	if config.CoolingOff == 0 {
+		config.CoolingOff = 24 * time.Hour
+	}
+
+	return &PerformanceTracker{
+		repo:   repo,
+		config: config,
+		trades: make(map[string][]*model.Settlement),
+	}
+}
+
+// history 每个策略保留的交易笔数（需同时覆盖统计窗口与连损判定）
+func (t *PerformanceTracker) history() int {
+	if t.config.LossStreak > t.config.Window {
+		return t.config.LossStreak
+	}
+	return t.config.Window
+}
+
+// Load 从结算表重新加载各策略最近的交易
+func (t *PerformanceTracker) Load(ctx context.Context) error {
+	if t.repo == nil {
+		return nil
+	}
+
+	settlements, err := t.repo.ListRecentSettlements(ctx, t.history())
+	if err != nil {
+		return fmt.Errorf("load settlements failed: %w", err)
+	}
+
+	trades := make(map[string][]*model.Settlement)
+	for _, s := range settlements {
+		trades[s.StrategyID] = append(trades[s.StrategyID], s)
+	}
+
+	t.mu.Lock()
+	t.trades = trades
+	t.mu.Unlock()
+	return nil
+}
+
+// Record 记录一笔已平仓交易（持久化后计入统计）
+func (t *PerformanceTracker) Record(ctx context.Context, settlement *model.Settlement) error {
+	if t.repo != nil {
+		if err := t.repo.SaveSettlement(ctx, settlement); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	copied := *settlement
+	trades := append(t.trades[settlement.StrategyID], &copied)
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].ClosedAt.Before(trades[j].ClosedAt)
+	})
+	if n := len(trades) - t.history(); n > 0 {
+		trades = trades[n:]
+	}
+	t.trades[settlement.StrategyID] = trades
+	return nil
+}
+
+// Snapshot 计算策略当前绩效与风险模式
+func (t *PerformanceTracker) Snapshot(strategyID string, now time.Time) model.StrategyPerformance {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.evaluate(strategyID, t.trades[strategyID], now)
+}
+
+// List 列出所有有交易记录的策略绩效（按策略 ID 排序）
+func (t *PerformanceTracker) List(now time.Time) []model.StrategyPerformance {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	list := make([]model.StrategyPerformance, 0, len(t.trades))
+	for strategyID, trades := range t.trades {
+		list = append(list, t.evaluate(strategyID, trades, now))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].StrategyID < list[j].StrategyID
+	})
+	return list
+}
+
+// Start 定时从结算表刷新（后台 goroutine）
+func (t *PerformanceTracker) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = t.Load(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// evaluate 基于最近交易计算绩效快照（调用方持有读锁）
+func (t *PerformanceTracker) evaluate(strategyID string, trades []*model.Settlement, now time.Time) model.StrategyPerformance {
+	perf := model.StrategyPerformance{
+		StrategyID:     strategyID,
+		AvgWin:         model.Zero(),
+		AvgLoss:        model.Zero(),
+		AvgCommission:  model.Zero(),
+		Expectancy:     model.Zero(),
+		Mode:           model.StrategyRiskModeNormal,
+		SizeMultiplier: 1,
+	}
+	if len(trades) == 0 {
+		return perf
+	}
+	perf.Symbol = trades[len(trades)-1].Symbol
+
+	// 最近连续亏损次数（基于保留的全部历史）
+	for i := len(trades) - 1; i >= 0 && !trades[i].IsWin(); i-- {
+		perf.ConsecutiveLosses++
+	}
+
+	// 统计窗口
+	window := trades
+	if len(window) > t.config.Window {
+		window = window[len(window)-t.config.Window:]
+	}
+	perf.Trades = len(window)
+
+	var (
+		wins, losses        int
+		totalWin, totalLoss = model.Zero(), model.Zero()
+		totalFee            = model.Zero()
+		totalR              float64
+		rCount              int
+	)
+	for _, s := range window {
+		if s.IsWin() {
+			wins++
+			totalWin = totalWin.Add(s.RealizedPnL)
+		} else {
+			losses++
+			totalLoss = totalLoss.Add(s.RealizedPnL.Abs())
+		}
+		totalFee = totalFee.Add(s.Commission)
+		if r, ok := s.RMultiple(); ok {
+			totalR += r
+			rCount++
+		}
+	}
+
+	count := model.NewMoneyFromInt(int64(perf.Trades))
+	perf.WinRate = float64(wins) / float64(perf.Trades)
+	perf.AvgCommission = totalFee.Div(count)
+	if wins > 0 {
+		perf.AvgWin = totalWin.Div(model.NewMoneyFromInt(int64(wins)))
+	}
+	if losses > 0 {
+		perf.AvgLoss = totalLoss.Div(model.NewMoneyFromInt(int64(losses)))
+	}
+	if perf.AvgLoss.IsPositive() {
+		perf.PayoffRatio = perf.AvgWin.Div(perf.AvgLoss).Float64()
+	}
+	if rCount > 0 {
+		perf.AvgRMultiple = totalR / float64(rCount)
+	}
+
+	// 期望值 = WinRate*AvgWin - LossRate*AvgLoss（净盈亏口径，即 (总盈利 - 总亏损) / 笔数）
+	perf.Expectancy = totalWin.Sub(totalLoss).Div(count)
+
+	// 冷静期：连续亏损达到阈值后暂停 CoolingOff，从最后一笔亏损平仓时间起算
+	if perf.ConsecutiveLosses >= t.config.LossStreak {
+		pausedUntil := trades[len(trades)-1].ClosedAt.Add(t.config.CoolingOff)
+		if now.Before(pausedUntil) {
+			perf.Mode = model.StrategyRiskModePaused
+			perf.SizeMultiplier = 0
+			perf.PausedUntil = pausedUntil
+			perf.Reason = fmt.Sprintf("%d consecutive losses, cooling off until %s",
+				perf.ConsecutiveLosses, pausedUntil.Format(time.RFC3339))
+			return perf
+		}
+	}
+
+	// 减半模式：样本满窗口后，胜率低于下限或净期望值为负（毛期望值不足以覆盖手续费）
+	if perf.Trades >= t.config.Window {
+		switch {
+		case perf.WinRate < t.config.MinWinRate:
+			perf.Reason = fmt.Sprintf("win rate %.0f%% below %.0f%% over last %d trades",
+				perf.WinRate*100, t.config.MinWinRate*100, perf.Trades)
+		case perf.Expectancy.IsNegative():
+			perf.Reason = fmt.Sprintf("expectancy %s below commission cost", perf.Expectancy)
+		}
+		if perf.Reason != "" {
+			perf.Mode = model.StrategyRiskModeHalfSize
+			perf.SizeMultiplier = t.config.HalfSizeMultiplier
+		}
+	}
+
+	return perf
+}
@@ -0,0 +1,140 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// recordTrades 按顺序记录交易盈亏（正数盈利，负数亏损），每笔间隔 1 分钟
+func recordTrades(t *testing.T, tracker *PerformanceTracker, strategyID string, start time.Time, pnls ...string) {
+	t.Helper()
+	for i, pnl := range pnls {
+		err := tracker.Record(context.Background(), &model.Settlement{
+			StrategyID:  strategyID,
+			Symbol:      "BTCUSDT",
+			RealizedPnL: model.MustMoney(pnl),
+			Commission:  model.MustMoney("1"),
+			RiskAmount:  model.MustMoney("100"),
+			ClosedAt:    start.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+}
+
+func TestPerformanceTracker_Metrics(t *testing.T) {
+	tracker := NewPerformanceTracker(nil, PerformanceConfig{Window: 4})
+	start := time.Now().Add(-time.Hour)
+
+	// 窗口外的交易不计入统计
+	recordTrades(t, tracker, "alpha", start, "-500", "200", "-100", "100", "-100")
+
+	perf := tracker.Snapshot("alpha", time.Now())
+	if perf.Trades != 4 {
+		t.Fatalf("trades: got %d, want 4", perf.Trades)
+	}
+	if perf.WinRate != 0.5 {
+		t.Errorf("win rate: got %.2f, want 0.5", perf.WinRate)
+	}
+	if !perf.AvgWin.EQ(model.MustMoney("150")) || !perf.AvgLoss.EQ(model.MustMoney("100")) {
+		t.Errorf("avg win/loss: got %s/%s, want 150/100", perf.AvgWin, perf.AvgLoss)
+	}
+	if !perf.Expectancy.EQ(model.MustMoney("25")) {
+		t.Errorf("expectancy: got %s, want 25", perf.Expectancy)
+	}
+	if perf.PayoffRatio != 1.5 || perf.AvgRMultiple != 0.25 {
+		t.Errorf("payoff/R: got %.2f/%.2f, want 1.5/0.25", perf.PayoffRatio, perf.AvgRMultiple)
+	}
+	if perf.Mode != model.StrategyRiskModeNormal || perf.SizeMultiplier != 1 {
+		t.Errorf("mode: got %s x%.2f, want NORMAL x1", perf.Mode, perf.SizeMultiplier)
+	}
+}
+
+func TestPerformanceTracker_HalfSize(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+
+	t.Run("低胜率", func(t *testing.T) {
+		tracker := NewPerformanceTracker(nil, PerformanceConfig{})
+		// 10 笔中仅 2 笔盈利（20% < 30%），且未达到连损阈值
+		recordTrades(t, tracker, "alpha", start,
+			"500", "-10", "-10", "-10", "-10", "500", "-10", "-10", "-10", "-10")
+
+		perf := tracker.Snapshot("alpha", time.Now())
+		if perf.Mode != model.StrategyRiskModeHalfSize || perf.SizeMultiplier != 0.5 {
+			t.Errorf("mode: got %s x%.2f, want HALF_SIZE x0.5", perf.Mode, perf.SizeMultiplier)
+		}
+	})
+
+	t.Run("期望值为负", func(t *testing.T) {
+		tracker := NewPerformanceTracker(nil, PerformanceConfig{})
+		recordTrades(t, tracker, "alpha", start,
+			"10", "-50", "10", "-50", "10", "-50", "10", "-50", "10", "10")
+
+		perf := tracker.Snapshot("alpha", time.Now())
+		if perf.Mode != model.StrategyRiskModeHalfSize {
+			t.Errorf("mode: got %s, want HALF_SIZE (expectancy %s)", perf.Mode, perf.Expectancy)
+		}
+	})
+
+	t.Run("样本不足", func(t *testing.T) {
+		tracker := NewPerformanceTracker(nil, PerformanceConfig{})
+		recordTrades(t, tracker, "alpha", start, "-10", "-10", "10")
+
+		if perf := tracker.Snapshot("alpha", time.Now()); perf.Mode != model.StrategyRiskModeNormal {
+			t.Errorf("mode: got %s, want NORMAL before window is full", perf.Mode)
+		}
+	})
+}
+
+func TestPerformanceTracker_CoolingOff(t *testing.T) {
+	tracker := NewPerformanceTracker(nil, PerformanceConfig{})
+	start := time.Now().Add(-time.Hour)
+	recordTrades(t, tracker, "alpha", start, "100", "-10", "-10", "-10", "-10", "-10")
+
+	lastLoss := start.Add(5 * time.Minute)
+	perf := tracker.Snapshot("alpha", time.Now())
+	if perf.Mode != model.StrategyRiskModePaused || perf.SizeMultiplier != 0 {
+		t.Fatalf("mode: got %s x%.2f, want PAUSED x0", perf.Mode, perf.SizeMultiplier)
+	}
+	if !perf.PausedUntil.Equal(lastLoss.Add(24 * time.Hour)) {
+		t.Errorf("paused until: got %s, want %s", perf.PausedUntil, lastLoss.Add(24*time.Hour))
+	}
+
+	// 冷静期结束后恢复（样本不足窗口，不进入减半模式）
+	if perf := tracker.Snapshot("alpha", lastLoss.Add(25*time.Hour)); perf.Mode != model.StrategyRiskModeNormal {
+		t.Errorf("mode after cooling off: got %s, want NORMAL", perf.Mode)
+	}
+}
+
+func TestStrategyPerformance_Rule(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{})
+	state := model.NewRiskState("test", model.MustMoney("10000"))
+	req := &OrderContext{Symbol: "BTCUSDT", Quantity: model.MustMoney("0.1"), StrategyID: "alpha"}
+
+	// 未启用绩效统计时不生效
+	if decision := mgr.CheckStrategyPerformance(context.Background(), req, state); !decision.IsAllowed() {
+		t.Fatalf("expected allowed without tracker, got %s", decision.Reason)
+	}
+
+	tracker := NewPerformanceTracker(nil, PerformanceConfig{})
+	recordTrades(t, tracker, "alpha", time.Now().Add(-time.Hour), "-10", "-10", "-10", "-10", "-10")
+	mgr.SetPerformanceTracker(tracker)
+
+	decision := mgr.CheckStrategyPerformance(context.Background(), req, state)
+	if !decision.IsBlocked() {
+		t.Errorf("expected paused strategy to be blocked, got %v", decision.Decision)
+	}
+	if mgr.SizeMultiplier("alpha") != 0 || mgr.SizeMultiplier("beta") != 1 {
+		t.Errorf("unexpected size multipliers: alpha=%.2f beta=%.2f", mgr.SizeMultiplier("alpha"), mgr.SizeMultiplier("beta"))
+	}
+
+	// 冷静期内仍允许止损平仓
+	req.ReduceOnly = true
+	if decision := mgr.CheckStrategyPerformance(context.Background(), req, state); !decision.IsAllowed() {
+		t.Errorf("reduce-only order should be allowed during cooling off, got %s", decision.Reason)
+	}
+}
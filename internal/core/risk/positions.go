@@ -0,0 +1,47 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// Positions 账户持仓（标的 -> 名义价值，返回副本）
+func (m *Manager) Positions(ctx context.Context, accountID string) (map[string]model.Money, error) {
+	state, err := m.loadState(ctx, model.AccountScope(accountID))
+	if err != nil {
+		return nil, fmt.Errorf("load account risk state failed: %w", err)
+	}
+
+	positions := make(map[string]model.Money, len(state.PositionMap))
+	for symbol, notional := range state.PositionMap {
+		positions[symbol] = notional
+	}
+	return positions, nil
+}
+
+// SetPositions 以交易所对账结果覆盖账户持仓并重新计算总敞口
+// 仅覆盖 positions 中的标的（名义价值为零时移除），其余标的保持不变
+func (m *Manager) SetPositions(ctx context.Context, accountID string, positions map[string]model.Money) error {
+	state, err := m.loadScopeState(ctx, model.AccountScope(accountID))
+	if err != nil {
+		return fmt.Errorf("load account risk state failed: %w", err)
+	}
+
+	return m.updateState(ctx, state, "reconcile positions", func(s *model.RiskState) {
+		for symbol, notional := range positions {
+			if notional.IsZero() {
+				delete(s.PositionMap, symbol)
+				continue
+			}
+			s.PositionMap[symbol] = notional
+		}
+
+		total := model.Zero()
+		for _, notional := range s.PositionMap {
+			total = total.Add(notional)
+		}
+		s.TotalExposure = total
+	})
+}
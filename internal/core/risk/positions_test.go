@@ -0,0 +1,44 @@
+package risk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+func TestManager_SetPositions(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	ctx := context.Background()
+
+	state := model.NewRiskState("acc-1", model.MustMoney("10000"))
+	state.PositionMap["BTCUSDT"] = model.MustMoney("2000")
+	state.PositionMap["ETHUSDT"] = model.MustMoney("500")
+	state.TotalExposure = model.MustMoney("2500")
+	if err := repo.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	mgr := NewManager(repo, RiskConfig{})
+	err := mgr.SetPositions(ctx, "acc-1", map[string]model.Money{
+		"BTCUSDT": model.MustMoney("3000"),
+		"ETHUSDT": model.Zero(),
+	})
+	if err != nil {
+		t.Fatalf("SetPositions failed: %v", err)
+	}
+
+	positions, err := mgr.Positions(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("Positions failed: %v", err)
+	}
+	if len(positions) != 1 || !positions["BTCUSDT"].EQ(model.MustMoney("3000")) {
+		t.Errorf("unexpected positions: %v", positions)
+	}
+
+	saved, _ := repo.LoadState(ctx, model.AccountScope("acc-1"))
+	if !saved.TotalExposure.EQ(model.MustMoney("3000")) || saved.Version != state.Version+1 {
+		t.Errorf("total exposure = %s version = %d, want 3000 / %d", saved.TotalExposure, saved.Version, state.Version+1)
+	}
+}
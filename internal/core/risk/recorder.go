@@ -0,0 +1,182 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	"github.com/iluyuns/alpha-trade/internal/pkg/metrics"
+)
+
+// RecorderConfig 风控决策记录配置
+type RecorderConfig struct {
+	BufferSize      int           // 异步缓冲容量（默认 1024，满时丢弃并计数）
+	BatchSize       int           // 单次批量写入条数（默认 100）
+	FlushInterval   time.Duration // 定时写入间隔（默认 1s）
+	AllowSampleRate float64       // 放行决策抽样比例（0 不记录，1 全部记录）
+}
+
+// Recorder 风控决策异步记录器
+// Block / Reduce 决策全部记录，Allow 按比例抽样；写入在后台批量完成，不阻塞下单路径
+type Recorder struct {
+	repo   port.RiskRecordRepo
+	config RecorderConfig
+	queue  chan *model.RiskRecord
+	sample func() float64 // 抽样随机源（测试可替换）
+}
+
+// NewRecorder 创建风控决策记录器
+func NewRecorder(repo port.RiskRecordRepo, config RecorderConfig) *Recorder {
+	if config.BufferSize == 0 {
+		config.BufferSize = 1024
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval == 0 {
+		config.FlushInterval = time.Second
+	}
+
+	return &Recorder{
+		repo:   repo,
+		config: config,
+		queue:  make(chan *model.RiskRecord, config.BufferSize),
+		sample: rand.Float64,
+	}
+}
+
+// Record 投递一条记录（缓冲已满时丢弃，返回 false）
+func (r *Recorder) Record(record *model.RiskRecord) bool {
+	select {
+	case r.queue <- record:
+		return true
+	default:
+		metrics.DefaultMetrics.RiskRecordsDropped.Inc()
+		return false
+	}
+}
+
+// Start 启动后台批量写入（ctx 结束时写入剩余记录后退出）
+func (r *Recorder) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.config.FlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]*model.RiskRecord, 0, r.config.BatchSize)
+		for {
+			select {
+			case record := <-r.queue:
+				batch = append(batch, record)
+				if len(batch) >= r.config.BatchSize {
+					r.write(ctx, batch)
+					batch = batch[:0]
+				}
+			case <-ticker.C:
+				r.write(ctx, batch)
+				batch = batch[:0]
+			case <-ctx.Done():
+				r.write(context.Background(), append(batch, r.drain()...))
+				return
+			}
+		}
+	}()
+}
+
+// Flush 同步写入缓冲中的全部记录
+func (r *Recorder) Flush(ctx context.Context) {
+	r.write(ctx, r.drain())
+}
+
+// drain 取出缓冲中的全部记录
+func (r *Recorder) drain() []*model.RiskRecord {
+	var records []*model.RiskRecord
+	for {
+		select {
+		case record := <-r.queue:
+			records = append(records, record)
+		default:
+			return records
+		}
+	}
+}
+
+// write 批量写入（失败时丢弃并计数，风控记录不影响交易）
+func (r *Recorder) write(ctx context.Context, batch []*model.RiskRecord) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := r.repo.SaveRecords(ctx, batch); err != nil {
+		metrics.DefaultMetrics.RiskRecordsDropped.Add(float64(len(batch)))
+	}
+}
+
+// shouldRecord 判断决策是否需要记录
+func (r *Recorder) shouldRecord(decision DecisionDetail) bool {
+	if !decision.IsAllowed() {
+		return true
+	}
+	return r.config.AllowSampleRate > 0 && r.sample() < r.config.AllowSampleRate
+}
+
+// orderSnapshot 订单上下文快照（枚举序列化为字符串，便于审计查询）
+type orderSnapshot struct {
+	ClientOrderID string      `json:"client_order_id,omitempty"`
+	AccountID     string      `json:"account_id"`
+	StrategyID    string      `json:"strategy_id,omitempty"`
+	Symbol        string      `json:"symbol"`
+	MarketType    string      `json:"market_type"`
+	Side          string      `json:"side"`
+	Type          string      `json:"type"`
+	Price         model.Money `json:"price"`
+	Quantity      model.Money `json:"quantity"`
+	Leverage      int         `json:"leverage,omitempty"`
+	ReduceOnly    bool        `json:"reduce_only"`
+	ProtectPrice  model.Money `json:"protect_price"`
+	CurrentPrice  model.Money `json:"current_price"`
+}
+
+// newRiskRecord 基于决策构造风控记录（立即序列化订单与状态，避免后续变更影响快照）
+func newRiskRecord(req *OrderContext, state *model.RiskState, decision DecisionDetail, now time.Time) *model.RiskRecord {
+	record := &model.RiskRecord{
+		EventType:         decision.TriggeredRule,
+		Decision:          decision.Decision.String(),
+		Reason:            decision.Reason,
+		SuggestedQuantity: decision.SuggestedQuantity,
+		SuggestedLeverage: decision.SuggestedLeverage,
+		AccountID:         req.AccountID,
+		Symbol:            req.Symbol,
+		StrategyID:        req.StrategyID,
+		TriggeredAt:       now,
+	}
+
+	switch decision.Decision {
+	case Block:
+		record.Level = model.RiskRecordLevelBlock
+	case Reduce:
+		record.Level = model.RiskRecordLevelWarning
+	default:
+		record.Level = model.RiskRecordLevelInfo
+		record.EventType = Allow.String()
+	}
+
+	record.Order, _ = json.Marshal(orderSnapshot{
+		ClientOrderID: req.ClientOrderID,
+		AccountID:     req.AccountID,
+		StrategyID:    req.StrategyID,
+		Symbol:        req.Symbol,
+		MarketType:    req.MarketType.String(),
+		Side:          req.Side.String(),
+		Type:          req.Type.String(),
+		Price:         req.Price,
+		Quantity:      req.Quantity,
+		Leverage:      req.Leverage,
+		ReduceOnly:    req.ReduceOnly,
+		ProtectPrice:  req.ProtectPrice,
+		CurrentPrice:  req.CurrentPrice,
+	})
+	record.State, _ = json.Marshal(state)
+	return record
+}
@@ -0,0 +1,73 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/infra/riskrecord"
+)
+
+func TestRecorder_RecordsReduceAndSamplesAllow(t *testing.T) {
+	repo := riskrecord.NewMemoryRepo()
+	recorder := NewRecorder(repo, RecorderConfig{AllowSampleRate: 0.5})
+	samples := []float64{0.9, 0.1}
+	recorder.sample = func() float64 {
+		v := samples[0]
+		samples = samples[1:]
+		return v
+	}
+
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{MaxSinglePositionPercent: 0.3})
+	mgr.SetRecorder(recorder)
+	ctx := context.Background()
+
+	// 0.1 BTC * 50000 = 5000 USD（50%）超过单标的 30% 上限：降档
+	if decision, _ := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.1")); !decision.ShouldReduce() {
+		t.Fatalf("expected reduce, got %v", decision.Decision)
+	}
+	// 两次放行：第一次未抽中，第二次抽中
+	_, _ = mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01"))
+	_, _ = mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01"))
+
+	recorder.Flush(ctx)
+
+	records, total, err := repo.ListRecords(ctx, model.RiskRecordFilter{})
+	if err != nil {
+		t.Fatalf("ListRecords failed: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 records, got %d", total)
+	}
+
+	allow, reduce := records[0], records[1]
+	if allow.Decision != "ALLOW" || allow.EventType != "ALLOW" || allow.Level != model.RiskRecordLevelInfo {
+		t.Errorf("unexpected allow record: %+v", allow)
+	}
+	if reduce.Decision != "REDUCE" || reduce.Level != model.RiskRecordLevelWarning || reduce.SuggestedQuantity == "" {
+		t.Errorf("unexpected reduce record: %+v", reduce)
+	}
+
+	var order map[string]any
+	if err := json.Unmarshal(reduce.Order, &order); err != nil {
+		t.Fatalf("order snapshot is not valid JSON: %v", err)
+	}
+	if order["quantity"] != "0.1" || order["side"] == "" {
+		t.Errorf("order snapshot must keep the original request: %v", order)
+	}
+	if len(reduce.State) == 0 {
+		t.Error("expected risk state snapshot")
+	}
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	recorder := NewRecorder(riskrecord.NewMemoryRepo(), RecorderConfig{BufferSize: 1})
+
+	if !recorder.Record(&model.RiskRecord{}) {
+		t.Fatal("expected first record to be queued")
+	}
+	if recorder.Record(&model.RiskRecord{}) {
+		t.Error("expected record to be dropped when buffer is full")
+	}
+}
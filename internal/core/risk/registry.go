@@ -0,0 +1,203 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// 内置规则名称（注册表 key，同时用于 YAML / strategy_configs 配置）
+const (
+	RuleCircuitBreaker      = "circuit_breaker"
+	RuleStrategyPerformance = "strategy_performance"
+	RulePositionLimit       = "position_limit"
+)
+
+// Rule 风控规则（由注册表按 RiskRuleSpec 装配）
+type Rule interface {
+	// Name 规则名称
+	Name() string
+
+	// Priority 优先级（越小越先评估）
+	Priority() int
+
+	// Scope 作用域
+	Scope() model.RiskRuleScope
+
+	// Target 作用对象（空表示不限定）
+	Target() string
+
+	// Enabled 是否启用
+	Enabled() bool
+
+	// Evaluate 评估订单
+	Evaluate(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail
+}
+
+// RuleFactory 规则工厂：基于规则集覆盖后的配置快照构造规则函数
+type RuleFactory func(m *Manager, cfg RiskConfig) RuleFunc
+
+// RuleDefinition 规则注册信息
+type RuleDefinition struct {
+	Priority int                 // 默认优先级
+	Scope    model.RiskRuleScope // 默认作用域
+	Factory  RuleFactory
+}
+
+// Registry 风控规则注册表
+type Registry struct {
+	mu    sync.RWMutex
+	rules map[string]RuleDefinition
+}
+
+// NewRegistry 创建规则注册表（已注册内置规则）
+func NewRegistry() *Registry {
+	r := &Registry{
+		rules: make(map[string]RuleDefinition),
+	}
+
+	r.Register(RuleCircuitBreaker, RuleDefinition{
+		Priority: 10,
+		Scope:    model.RiskRuleScopeSystem,
+		Factory: func(m *Manager, cfg RiskConfig) RuleFunc {
+			return m.circuitBreakerRule(cfg)
+		},
+	})
+	r.Register(RuleStrategyPerformance, RuleDefinition{
+		Priority: 20,
+		Scope:    model.RiskRuleScopeStrategy,
+		Factory: func(m *Manager, cfg RiskConfig) RuleFunc {
+			return m.CheckStrategyPerformance
+		},
+	})
+	r.Register(RulePositionLimit, RuleDefinition{
+		Priority: 30,
+		Scope:    model.RiskRuleScopeAccount,
+		Factory: func(m *Manager, cfg RiskConfig) RuleFunc {
+			return func(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+				return checkPositionLimit(cfg, req, state)
+			}
+		},
+	})
+
+	return r
+}
+
+// Register 注册规则（同名覆盖）
+func (r *Registry) Register(name string, def RuleDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[name] = def
+}
+
+// Names 已注册的规则名称（按默认优先级排序）
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.rules))
+	for name := range r.rules {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := r.rules[names[i]].Priority, r.rules[names[j]].Priority
+		if pi != pj {
+			return pi < pj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// DefaultSpecs 所有已注册规则的默认配置（全部启用）
+func (r *Registry) DefaultSpecs() []*model.RiskRuleSpec {
+	names := r.Names()
+	specs := make([]*model.RiskRuleSpec, 0, len(names))
+	for _, name := range names {
+		specs = append(specs, &model.RiskRuleSpec{Name: name, Enabled: true})
+	}
+	return specs
+}
+
+// Build 按规则集配置装配规则（按优先级升序，同优先级保持配置顺序）
+func (r *Registry) Build(m *Manager, base RiskConfig, specs []*model.RiskRuleSpec) ([]Rule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]Rule, 0, len(specs))
+	for _, spec := range specs {
+		def, ok := r.rules[spec.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRule, spec.Name)
+		}
+
+		cfg, err := applyRuleParams(base, spec.Params)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", spec.Name, err)
+		}
+
+		rule := &configuredRule{
+			name:     spec.Name,
+			priority: spec.Priority,
+			scope:    spec.Scope,
+			target:   spec.Target,
+			enabled:  spec.Enabled,
+			eval:     def.Factory(m, cfg),
+		}
+		if rule.priority == 0 {
+			rule.priority = def.Priority
+		}
+		if rule.scope == 0 {
+			rule.scope = def.Scope
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority() < rules[j].Priority()
+	})
+	return rules, nil
+}
+
+// configuredRule 按配置装配的规则实例
+type configuredRule struct {
+	name     string
+	priority int
+	scope    model.RiskRuleScope
+	target   string
+	enabled  bool
+	eval     RuleFunc
+}
+
+func (r *configuredRule) Name() string               { return r.name }
+func (r *configuredRule) Priority() int              { return r.priority }
+func (r *configuredRule) Scope() model.RiskRuleScope { return r.scope }
+func (r *configuredRule) Target() string             { return r.target }
+func (r *configuredRule) Enabled() bool              { return r.enabled }
+
+// Evaluate 实现 Rule
+func (r *configuredRule) Evaluate(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+	return r.eval(ctx, req, state)
+}
+
+// ruleApplies 判断规则是否作用于该订单
+func ruleApplies(rule Rule, req *OrderContext) bool {
+	if !rule.Enabled() {
+		return false
+	}
+
+	target := rule.Target()
+	switch rule.Scope() {
+	case model.RiskRuleScopeAccount:
+		return target == "" || target == req.AccountID
+	case model.RiskRuleScopeStrategy:
+		return req.StrategyID != "" && (target == "" || target == req.StrategyID)
+	case model.RiskRuleScopeSymbol:
+		return target == "" || target == req.Symbol
+	default:
+		return true
+	}
+}
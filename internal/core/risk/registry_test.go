@@ -0,0 +1,236 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// halveRule 测试规则：数量减半并记录评估顺序
+func halveRule(calls *[]string) RuleDefinition {
+	return RuleDefinition{
+		Priority: 5,
+		Scope:    model.RiskRuleScopeSystem,
+		Factory: func(m *Manager, cfg RiskConfig) RuleFunc {
+			return func(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+				*calls = append(*calls, "halve:"+req.Quantity.String())
+				half := req.Quantity.Div(model.NewMoneyFromInt(2))
+				return NewReduce("halve", "Halve", half.String(), 0)
+			}
+		},
+	}
+}
+
+// blockRule 测试规则：直接拒绝
+func blockRule(calls *[]string) RuleDefinition {
+	return RuleDefinition{
+		Priority: 1,
+		Scope:    model.RiskRuleScopeSymbol,
+		Factory: func(m *Manager, cfg RiskConfig) RuleFunc {
+			return func(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+				*calls = append(*calls, "block")
+				return NewBlock("blocked", "Block")
+			}
+		},
+	}
+}
+
+func newRuleTestOrder(qty string) *OrderContext {
+	return &OrderContext{
+		AccountID:    "acc-1",
+		Symbol:       "BTCUSDT",
+		MarketType:   model.MarketTypeSpot,
+		Quantity:     model.MustMoney(qty),
+		Price:        model.MustMoney("50000"),
+		CurrentPrice: model.MustMoney("50000"),
+	}
+}
+
+func TestRegistry_DefaultRuleSet(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{})
+
+	var names []string
+	for _, rule := range mgr.Rules("any-account") {
+		names = append(names, rule.Name())
+		if !rule.Enabled() {
+			t.Errorf("expected %s enabled", rule.Name())
+		}
+	}
+	want := []string{RuleCircuitBreaker, RuleStrategyPerformance, RulePositionLimit}
+	if len(names) != len(want) {
+		t.Fatalf("got rules %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("rule %d: got %s, want %s", i, names[i], want[i])
+		}
+	}
+}
+
+func TestRegistry_InvalidSpec(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{})
+
+	err := mgr.SetRuleSet("acc-1", []*model.RiskRuleSpec{{Name: "unknown", Enabled: true}})
+	if !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("expected ErrUnknownRule, got %v", err)
+	}
+
+	err = mgr.SetRuleSet("acc-1", []*model.RiskRuleSpec{
+		{Name: RulePositionLimit, Enabled: true, Params: map[string]float64{"max_position": 1}},
+	})
+	if !errors.Is(err, ErrUnknownRuleParam) {
+		t.Errorf("expected ErrUnknownRuleParam, got %v", err)
+	}
+}
+
+func TestCheckPreTrade_ReduceCarryForward(t *testing.T) {
+	tests := []struct {
+		name         string
+		qty          string
+		wantDecision Decision
+		wantQty      string
+		wantCalls    int
+	}{
+		{
+			// 0.1 -> 0.05（2500 USD，25%），仓位限制放行
+			name:         "reduced order passes later rules",
+			qty:          "0.1",
+			wantDecision: Reduce,
+			wantQty:      "0.05",
+		},
+		{
+			// 0.2 -> 0.1（5000 USD，50%），仓位限制继续降至 30%
+			name:         "later rule reduces further",
+			qty:          "0.2",
+			wantDecision: Reduce,
+			wantQty:      "0.06",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			mgr := NewManager(&mockRiskRepo{}, RiskConfig{MaxSinglePositionPercent: 0.3})
+			mgr.Registry().Register("halve", halveRule(&calls))
+			err := mgr.SetRuleSet("acc-1", []*model.RiskRuleSpec{
+				{Name: RulePositionLimit, Enabled: true},
+				{Name: "halve", Enabled: true},
+			})
+			if err != nil {
+				t.Fatalf("SetRuleSet failed: %v", err)
+			}
+
+			decision, err := mgr.CheckPreTrade(context.Background(), newRuleTestOrder(tt.qty))
+			if err != nil {
+				t.Fatalf("CheckPreTrade failed: %v", err)
+			}
+			if decision.Decision != tt.wantDecision {
+				t.Fatalf("got %v, want %v (reason: %s)", decision.Decision, tt.wantDecision, decision.Reason)
+			}
+			if !model.MustMoney(decision.SuggestedQuantity).EQ(model.MustMoney(tt.wantQty)) {
+				t.Errorf("suggested quantity: got %s, want %s", decision.SuggestedQuantity, tt.wantQty)
+			}
+			// 优先级 5 的 halve 先于优先级 30 的 position_limit 评估原始订单
+			if len(calls) != 1 || calls[0] != "halve:"+tt.qty {
+				t.Errorf("unexpected evaluation: %v", calls)
+			}
+		})
+	}
+}
+
+func TestCheckPreTrade_BlockShortCircuits(t *testing.T) {
+	var calls []string
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{})
+	mgr.Registry().Register("halve", halveRule(&calls))
+	mgr.Registry().Register("block", blockRule(&calls))
+	err := mgr.SetRuleSet("acc-1", []*model.RiskRuleSpec{
+		{Name: "halve", Enabled: true},
+		{Name: "block", Enabled: true, Target: "BTCUSDT"},
+	})
+	if err != nil {
+		t.Fatalf("SetRuleSet failed: %v", err)
+	}
+
+	decision, _ := mgr.CheckPreTrade(context.Background(), newRuleTestOrder("0.1"))
+	if !decision.IsBlocked() {
+		t.Fatalf("expected blocked, got %v", decision.Decision)
+	}
+	if len(calls) != 1 || calls[0] != "block" {
+		t.Errorf("expected only block rule evaluated, got %v", calls)
+	}
+}
+
+func TestCheckPreTrade_ScopeAndEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *model.RiskRuleSpec
+		req  func(*OrderContext)
+	}{
+		{
+			name: "disabled",
+			spec: &model.RiskRuleSpec{Name: "block", Enabled: false},
+		},
+		{
+			name: "other symbol",
+			spec: &model.RiskRuleSpec{Name: "block", Enabled: true, Target: "ETHUSDT"},
+		},
+		{
+			name: "other account",
+			spec: &model.RiskRuleSpec{Name: "block", Enabled: true, Scope: model.RiskRuleScopeAccount, Target: "acc-2"},
+		},
+		{
+			name: "untagged order for strategy rule",
+			spec: &model.RiskRuleSpec{Name: "block", Enabled: true, Scope: model.RiskRuleScopeStrategy},
+		},
+		{
+			name: "other strategy",
+			spec: &model.RiskRuleSpec{Name: "block", Enabled: true, Scope: model.RiskRuleScopeStrategy, Target: "grid"},
+			req:  func(req *OrderContext) { req.StrategyID = "trend" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			mgr := NewManager(&mockRiskRepo{}, RiskConfig{})
+			mgr.Registry().Register("block", blockRule(&calls))
+			if err := mgr.SetRuleSet("acc-1", []*model.RiskRuleSpec{tt.spec}); err != nil {
+				t.Fatalf("SetRuleSet failed: %v", err)
+			}
+
+			req := newRuleTestOrder("0.1")
+			if tt.req != nil {
+				tt.req(req)
+			}
+			decision, _ := mgr.CheckPreTrade(context.Background(), req)
+			if !decision.IsAllowed() || len(calls) != 0 {
+				t.Errorf("expected rule skipped, got %v (calls %v)", decision.Decision, calls)
+			}
+		})
+	}
+}
+
+func TestCheckPreTrade_PerAccountParams(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{MaxSinglePositionPercent: 0.3})
+	err := mgr.SetRuleSet("acc-1", []*model.RiskRuleSpec{
+		{Name: RulePositionLimit, Enabled: true, Params: map[string]float64{"max_single_position_percent": 0.6}},
+	})
+	if err != nil {
+		t.Fatalf("SetRuleSet failed: %v", err)
+	}
+
+	// 0.1 BTC * 50000 = 5000 USD（50%）
+	decision, _ := mgr.CheckPreTrade(context.Background(), newRuleTestOrder("0.1"))
+	if !decision.IsAllowed() {
+		t.Errorf("acc-1: expected allowed, got %v (%s)", decision.Decision, decision.Reason)
+	}
+
+	req := newRuleTestOrder("0.1")
+	req.AccountID = "acc-2"
+	decision, _ = mgr.CheckPreTrade(context.Background(), req)
+	if !decision.ShouldReduce() {
+		t.Errorf("acc-2: expected reduce under default rule set, got %v", decision.Decision)
+	}
+}
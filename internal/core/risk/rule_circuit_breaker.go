@@ -14,7 +14,45 @@ import (
 // 2. 当日MDD >= MaxDailyDrawdown
 // 3. 总MDD >= MaxTotalMDD
 // 4. 熔断器已打开且未过期
+// 依次评估账户、策略、标的作用域（见 model.RiskScope），只拦截触发熔断的作用域内的订单
+// 熔断期间仍允许只减仓订单（止损平仓）通过
 func (m *Manager) CheckCircuitBreaker(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+	return m.circuitBreakerRule(m.Config())(ctx, req, state)
+}
+
+// circuitBreakerRule 按给定配置构造熔断器规则
+func (m *Manager) circuitBreakerRule(cfg RiskConfig) RuleFunc {
+	return func(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+		decision := m.evaluateCircuitBreaker(ctx, cfg, state)
+		if !decision.IsBlocked() {
+			decision = m.evaluateScopedCircuitBreakers(ctx, cfg, req)
+		}
+		if decision.IsBlocked() && req.ReduceOnly {
+			return NewAllow()
+		}
+		return decision
+	}
+}
+
+// evaluateScopedCircuitBreakers 评估策略 / 标的作用域的熔断条件（账户级由调用方传入的状态评估）
+func (m *Manager) evaluateScopedCircuitBreakers(ctx context.Context, cfg RiskConfig, req *OrderContext) DecisionDetail {
+	for _, scope := range req.Scope().Chain()[1:] {
+		state, err := m.loadScopeState(ctx, scope)
+		if err != nil {
+			return NewBlock(fmt.Sprintf("failed to load %s risk state", scope), "internal")
+		}
+
+		decision := m.evaluateCircuitBreaker(ctx, cfg, state)
+		if decision.IsBlocked() {
+			decision.Reason = fmt.Sprintf("%s: %s", scope, decision.Reason)
+			return decision
+		}
+	}
+	return NewAllow()
+}
+
+// evaluateCircuitBreaker 评估熔断条件（含熔断器开关状态变更，触发原因写入状态历史）
+func (m *Manager) evaluateCircuitBreaker(ctx context.Context, cfg RiskConfig, state *model.RiskState) DecisionDetail {
 	// 1. 检查熔断器是否已打开
 	if state.CircuitBreakerOpen {
 		if time.Now().Unix() < state.CircuitBreakerUntil {
@@ -24,58 +62,61 @@ func (m *Manager) CheckCircuitBreaker(ctx context.Context, req *OrderContext, st
 				"CircuitBreaker",
 			)
 		}
-		// 熔断器已过期，自动关闭
-		state.CloseCircuitBreaker()
-		_ = m.repo.SaveState(ctx, state)
+		// 熔断器已过期，自动关闭（其他进程可能已重新打开，按最新状态判断）
+		_ = m.updateState(ctx, state, "circuit breaker expired", func(s *model.RiskState) {
+			if s.CircuitBreakerOpen && time.Now().Unix() >= s.CircuitBreakerUntil {
+				s.CloseCircuitBreaker()
+			}
+		})
 	}
 
 	// 2. 检查连续亏损次数
-	if m.config.MaxConsecutiveLosses > 0 && state.ConsecutiveLosses >= m.config.MaxConsecutiveLosses {
-		// 打开熔断器（1小时冷却）
-		state.OpenCircuitBreaker(1 * time.Hour)
-		_ = m.repo.SaveState(ctx, state)
-
-		return NewBlock(
+	if cfg.MaxConsecutiveLosses > 0 && state.ConsecutiveLosses >= cfg.MaxConsecutiveLosses {
+		decision := NewBlock(
 			fmt.Sprintf("consecutive losses (%d) >= max allowed (%d)",
-				state.ConsecutiveLosses, m.config.MaxConsecutiveLosses),
+				state.ConsecutiveLosses, cfg.MaxConsecutiveLosses),
 			"CircuitBreaker:ConsecutiveLosses",
 		)
+		// 打开熔断器（1小时冷却）
+		m.openCircuitBreaker(ctx, state, decision, 1*time.Hour)
+		return decision
 	}
 
 	// 3. 检查当日回撤
-	if m.config.MaxDailyDrawdown > 0 && state.CurrentEquity.IsPositive() {
+	if cfg.MaxDailyDrawdown > 0 && state.CurrentEquity.IsPositive() {
 		dailyPnLPercent := state.DailyPnL.Div(state.CurrentEquity).Float64()
-		if dailyPnLPercent < -m.config.MaxDailyDrawdown {
-			state.OpenCircuitBreaker(24 * time.Hour) // 次日重置
-			_ = m.repo.SaveState(ctx, state)
-
-			return NewBlock(
+		if dailyPnLPercent < -cfg.MaxDailyDrawdown {
+			decision := NewBlock(
 				fmt.Sprintf("daily drawdown (%.2f%%) >= max allowed (%.2f%%)",
-					dailyPnLPercent*100, m.config.MaxDailyDrawdown*100),
+					dailyPnLPercent*100, cfg.MaxDailyDrawdown*100),
 				"CircuitBreaker:DailyDrawdown",
 			)
+			m.openCircuitBreaker(ctx, state, decision, 24*time.Hour) // 次日重置
+			return decision
 		}
 	}
 
 	// 4. 检查总回撤
-	if m.config.MaxTotalMDD > 0 {
+	if cfg.MaxTotalMDD > 0 {
 		totalMDDPercent := state.MDDPercent.Float64()
-		if totalMDDPercent >= m.config.MaxTotalMDD {
-			state.OpenCircuitBreaker(7 * 24 * time.Hour) // 7天冷却
-			_ = m.repo.SaveState(ctx, state)
-
-			return NewBlock(
+		if totalMDDPercent >= cfg.MaxTotalMDD {
+			decision := NewBlock(
 				fmt.Sprintf("total MDD (%.2f%%) >= max allowed (%.2f%%)",
-					totalMDDPercent*100, m.config.MaxTotalMDD*100),
+					totalMDDPercent*100, cfg.MaxTotalMDD*100),
 				"CircuitBreaker:TotalMDD",
 			)
+			m.openCircuitBreaker(ctx, state, decision, 7*24*time.Hour) // 7天冷却
+			return decision
 		}
 	}
 
 	return NewAllow()
 }
 
-// checkCircuitBreaker 内部调用（manager.go 中的短路链）
-func (m *Manager) checkCircuitBreaker(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
-	return m.CheckCircuitBreaker(ctx, req, state)
+// openCircuitBreaker 打开熔断器，以拦截规则与原因作为状态历史的触发原因
+func (m *Manager) openCircuitBreaker(ctx context.Context, state *model.RiskState, decision DecisionDetail, duration time.Duration) {
+	reason := decision.TriggeredRule + ": " + decision.Reason
+	_ = m.updateState(ctx, state, reason, func(s *model.RiskState) {
+		s.OpenCircuitBreaker(duration)
+	})
 }
@@ -110,14 +110,19 @@ func TestCircuitBreaker_AlreadyOpen(t *testing.T) {
 // Mock RiskRepo for testing
 type mockRiskRepo struct{}
 
-func (m *mockRiskRepo) LoadState(ctx context.Context, accountID, symbol string) (*model.RiskState, error) {
-	return model.NewRiskState(accountID, model.MustMoney("10000")), nil
+func (m *mockRiskRepo) LoadState(ctx context.Context, scope model.RiskScope) (*model.RiskState, error) {
+	return model.NewScopedRiskState(scope, model.MustMoney("10000")), nil
 }
 
 func (m *mockRiskRepo) SaveState(ctx context.Context, state *model.RiskState) error {
 	return nil
 }
 
+func (m *mockRiskRepo) CompareAndSwapState(ctx context.Context, state *model.RiskState) error {
+	state.Version++
+	return nil
+}
+
 func (m *mockRiskRepo) UpdateEquity(ctx context.Context, accountID string, newEquity model.Money) error {
 	return nil
 }
@@ -137,3 +142,25 @@ func (m *mockRiskRepo) CloseCircuitBreaker(ctx context.Context, accountID string
 func (m *mockRiskRepo) IsCircuitBreakerOpen(ctx context.Context, accountID string) (bool, error) {
 	return false, nil
 }
+
+func TestCircuitBreaker_ReduceOnlyAllowed(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{})
+
+	state := model.NewRiskState("test", model.MustMoney("10000"))
+	state.OpenCircuitBreaker(1 * time.Hour)
+
+	req := &OrderContext{
+		Symbol:   "BTCUSDT",
+		Side:     model.OrderSideSell,
+		Quantity: model.MustMoney("0.1"),
+	}
+	if decision := mgr.CheckCircuitBreaker(context.Background(), req, state); !decision.IsBlocked() {
+		t.Fatalf("expected opening order blocked, got %v", decision.Decision)
+	}
+
+	// 止损平仓（只减仓）在熔断期间放行
+	req.ReduceOnly = true
+	if decision := mgr.CheckCircuitBreaker(context.Background(), req, state); !decision.IsAllowed() {
+		t.Errorf("expected reduce-only allowed, got blocked: %s", decision.Reason)
+	}
+}
@@ -14,25 +14,35 @@ import (
 // 3. 现金储备 >= MinCashReservePercent
 // 4. 合约杠杆 <= MaxLeverage
 // 5. 大额单强制降档（ProtectPrice 机制）
+// 只减仓订单降低敞口，不受仓位限制
 func (m *Manager) CheckPositionLimit(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+	return checkPositionLimit(m.Config(), req, state)
+}
+
+// checkPositionLimit 按给定配置评估仓位限制
+func checkPositionLimit(cfg RiskConfig, req *OrderContext, state *model.RiskState) DecisionDetail {
+	if req.ReduceOnly {
+		return NewAllow()
+	}
+
 	// 计算订单名义价值
 	orderNotional := calculateNotional(req)
 
 	// 1. 单标的仓位限制
-	if m.config.MaxSinglePositionPercent > 0 && state.CurrentEquity.IsPositive() {
+	if cfg.MaxSinglePositionPercent > 0 && state.CurrentEquity.IsPositive() {
 		existingPosition := state.PositionMap[req.Symbol]
 		newPosition := existingPosition.Add(orderNotional)
 		positionPercent := newPosition.Div(state.CurrentEquity).Float64()
 
-		if positionPercent > m.config.MaxSinglePositionPercent {
+		if positionPercent > cfg.MaxSinglePositionPercent {
 			// 计算建议数量
-			maxAllowed := state.CurrentEquity.Mul(model.NewMoneyFromFloat(m.config.MaxSinglePositionPercent))
+			maxAllowed := state.CurrentEquity.Mul(model.NewMoneyFromFloat(cfg.MaxSinglePositionPercent))
 			availableNotional := maxAllowed.Sub(existingPosition)
 
 			if availableNotional.LE(model.Zero()) {
 				return NewBlock(
 					fmt.Sprintf("single position limit exceeded for %s: %.2f%% > %.2f%%",
-						req.Symbol, positionPercent*100, m.config.MaxSinglePositionPercent*100),
+						req.Symbol, positionPercent*100, cfg.MaxSinglePositionPercent*100),
 					"PositionLimit:SinglePosition",
 				)
 			}
@@ -44,7 +54,7 @@ func (m *Manager) CheckPositionLimit(ctx context.Context, req *OrderContext, sta
 			}
 			suggestedQty := availableNotional.Div(price)
 			return NewReduce(
-				fmt.Sprintf("single position limit: reduce to %.2f%%", m.config.MaxSinglePositionPercent*100),
+				fmt.Sprintf("single position limit: reduce to %.2f%%", cfg.MaxSinglePositionPercent*100),
 				"PositionLimit:SinglePosition",
 				suggestedQty.String(),
 				1, // 强制1x杠杆
@@ -53,28 +63,28 @@ func (m *Manager) CheckPositionLimit(ctx context.Context, req *OrderContext, sta
 	}
 
 	// 2. 总敞口限制
-	if m.config.MaxTotalExposurePercent > 0 && state.CurrentEquity.IsPositive() {
+	if cfg.MaxTotalExposurePercent > 0 && state.CurrentEquity.IsPositive() {
 		newTotalExposure := state.TotalExposure.Add(orderNotional)
 		exposurePercent := newTotalExposure.Div(state.CurrentEquity).Float64()
 
-		if exposurePercent > m.config.MaxTotalExposurePercent {
+		if exposurePercent > cfg.MaxTotalExposurePercent {
 			return NewBlock(
 				fmt.Sprintf("total exposure limit exceeded: %.2f%% > %.2f%%",
-					exposurePercent*100, m.config.MaxTotalExposurePercent*100),
+					exposurePercent*100, cfg.MaxTotalExposurePercent*100),
 				"PositionLimit:TotalExposure",
 			)
 		}
 	}
 
 	// 3. 现金储备检查
-	if m.config.MinCashReservePercent > 0 && state.CurrentEquity.IsPositive() {
-		requiredCash := state.CurrentEquity.Mul(model.NewMoneyFromFloat(m.config.MinCashReservePercent))
+	if cfg.MinCashReservePercent > 0 && state.CurrentEquity.IsPositive() {
+		requiredCash := state.CurrentEquity.Mul(model.NewMoneyFromFloat(cfg.MinCashReservePercent))
 		availableCash := state.CurrentEquity.Sub(state.TotalExposure).Sub(orderNotional)
 
 		if availableCash.LT(requiredCash) {
 			return NewBlock(
 				fmt.Sprintf("insufficient cash reserve: required %.2f%%, available %.2f%%",
-					m.config.MinCashReservePercent*100,
+					cfg.MinCashReservePercent*100,
 					availableCash.Div(state.CurrentEquity).Float64()*100),
 				"PositionLimit:CashReserve",
 			)
@@ -83,19 +93,19 @@ func (m *Manager) CheckPositionLimit(ctx context.Context, req *OrderContext, sta
 
 	// 4. 合约杠杆限制
 	if req.MarketType == model.MarketTypeFuture {
-		if m.config.MaxLeverage > 0 && req.Leverage > m.config.MaxLeverage {
+		if cfg.MaxLeverage > 0 && req.Leverage > cfg.MaxLeverage {
 			return NewReduce(
-				fmt.Sprintf("leverage %dx exceeds max %dx", req.Leverage, m.config.MaxLeverage),
+				fmt.Sprintf("leverage %dx exceeds max %dx", req.Leverage, cfg.MaxLeverage),
 				"PositionLimit:Leverage",
 				req.Quantity.String(),
-				m.config.MaxLeverage,
+				cfg.MaxLeverage,
 			)
 		}
 
 		// 5. 大额单强制1x
-		if m.config.ForceLeverageOne && state.CurrentEquity.IsPositive() {
+		if cfg.ForceLeverageOne && state.CurrentEquity.IsPositive() {
 			orderSizePercent := orderNotional.Div(state.CurrentEquity).Float64()
-			if orderSizePercent > m.config.LargeOrderThreshold && req.Leverage > 1 {
+			if orderSizePercent > cfg.LargeOrderThreshold && req.Leverage > 1 {
 				return NewReduce(
 					fmt.Sprintf("large order (%.2f%% of equity) requires 1x leverage", orderSizePercent*100),
 					"PositionLimit:LargeOrder",
@@ -109,11 +119,6 @@ func (m *Manager) CheckPositionLimit(ctx context.Context, req *OrderContext, sta
 	return NewAllow()
 }
 
-// checkPositionLimit 内部调用（manager.go 中的短路链）
-func (m *Manager) checkPositionLimit(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
-	return m.CheckPositionLimit(ctx, req, state)
-}
-
 // calculateNotional 计算订单名义价值
 func calculateNotional(req *OrderContext) model.Money {
 	price := req.Price
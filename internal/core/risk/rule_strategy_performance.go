@@ -0,0 +1,28 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// CheckStrategyPerformance 策略绩效规则（见 RISK_PROTOCOL 7.2 动态风险降级）
+// 冷静期内拒绝该策略的开仓订单；减半模式由 SizeMultiplier 在下单前压缩数量
+// 只减仓订单与未标记策略的订单不受影响
+func (m *Manager) CheckStrategyPerformance(ctx context.Context, req *OrderContext, state *model.RiskState) DecisionDetail {
+	if req.ReduceOnly || req.StrategyID == "" {
+		return NewAllow()
+	}
+
+	perf, ok := m.StrategyPerformance(req.StrategyID, time.Now())
+	if !ok || perf.Mode != model.StrategyRiskModePaused {
+		return NewAllow()
+	}
+
+	return NewBlock(
+		fmt.Sprintf("strategy %s paused: %s", req.StrategyID, perf.Reason),
+		"StrategyPerformance:CoolingOff",
+	)
+}
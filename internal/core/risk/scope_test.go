@@ -0,0 +1,101 @@
+package risk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+func newScopeTestOrder(strategyID, symbol string) *OrderContext {
+	req := newRuleTestOrder("0.01")
+	req.StrategyID = strategyID
+	req.Symbol = symbol
+	return req
+}
+
+func TestRecordTrade_UpdatesScopeChain(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	mgr := NewManager(repo, RiskConfig{})
+	ctx := context.Background()
+
+	scope := model.RiskScope{AccountID: "acc-1", StrategyID: "alpha", Symbol: "BTCUSDT"}
+	if err := mgr.RecordTrade(ctx, scope, model.MustMoney("-100")); err != nil {
+		t.Fatalf("RecordTrade failed: %v", err)
+	}
+
+	for _, s := range scope.Chain() {
+		state, _ := repo.LoadState(ctx, s)
+		if state.ConsecutiveLosses != 1 || state.DailyPnL.String() != "-100" {
+			t.Errorf("%s: unexpected state %+v", s, state)
+		}
+	}
+
+	// 策略级按自身盈亏曲线计算回撤
+	strategy, _ := repo.LoadState(ctx, model.RiskScope{AccountID: "acc-1", StrategyID: "alpha"})
+	if strategy.CurrentEquity.String() != "9900" || strategy.MDD.String() != "100" {
+		t.Errorf("unexpected strategy equity: current %s, mdd %s", strategy.CurrentEquity, strategy.MDD)
+	}
+
+	// 其他策略不受影响
+	other, _ := repo.LoadState(ctx, model.RiskScope{AccountID: "acc-1", StrategyID: "beta"})
+	if other.ConsecutiveLosses != 0 {
+		t.Errorf("expected beta untouched, got %+v", other)
+	}
+}
+
+func TestCheckPreTrade_StrategyBreachBlocksOnlyStrategy(t *testing.T) {
+	mgr := NewManager(riskrepo.NewMemoryRiskRepo(), RiskConfig{MaxConsecutiveLosses: 2})
+	ctx := context.Background()
+
+	// alpha 连亏两次，中间 beta 盈利一次：账户连续亏损被重置，alpha 策略级触发熔断
+	alpha := model.RiskScope{AccountID: "acc-1", StrategyID: "alpha", Symbol: "BTCUSDT"}
+	beta := model.RiskScope{AccountID: "acc-1", StrategyID: "beta", Symbol: "ETHUSDT"}
+	_ = mgr.RecordTrade(ctx, alpha, model.MustMoney("-100"))
+	_ = mgr.RecordTrade(ctx, beta, model.MustMoney("50"))
+	_ = mgr.RecordTrade(ctx, alpha, model.MustMoney("-100"))
+
+	decision, _ := mgr.CheckPreTrade(ctx, newScopeTestOrder("alpha", "ETHUSDT"))
+	if !decision.IsBlocked() || !strings.HasPrefix(decision.Reason, "STRATEGY acc-1/alpha") {
+		t.Fatalf("expected alpha blocked at strategy scope, got %v (%s)", decision.Decision, decision.Reason)
+	}
+
+	if decision, _ := mgr.CheckPreTrade(ctx, newScopeTestOrder("beta", "BTCUSDT")); !decision.IsAllowed() {
+		t.Errorf("expected beta allowed, got %v (%s)", decision.Decision, decision.Reason)
+	}
+
+	// 熔断期间只减仓订单仍放行
+	reduceOnly := newScopeTestOrder("alpha", "BTCUSDT")
+	reduceOnly.ReduceOnly = true
+	if decision, _ := mgr.CheckPreTrade(ctx, reduceOnly); decision.IsBlocked() {
+		t.Errorf("expected reduce-only order allowed, got %s", decision.Reason)
+	}
+}
+
+func TestCheckPreTrade_SymbolBreachBlocksOnlySymbol(t *testing.T) {
+	mgr := NewManager(riskrepo.NewMemoryRiskRepo(), RiskConfig{MaxConsecutiveLosses: 2})
+	ctx := context.Background()
+
+	// alpha 在 BTCUSDT 连亏两次，中间在 ETHUSDT 盈利：仅 alpha/BTCUSDT 标的级触发熔断
+	btc := model.RiskScope{AccountID: "acc-1", StrategyID: "alpha", Symbol: "BTCUSDT"}
+	eth := model.RiskScope{AccountID: "acc-1", StrategyID: "alpha", Symbol: "ETHUSDT"}
+	_ = mgr.RecordTrade(ctx, btc, model.MustMoney("-100"))
+	_ = mgr.RecordTrade(ctx, eth, model.MustMoney("50"))
+	_ = mgr.RecordTrade(ctx, btc, model.MustMoney("-100"))
+
+	decision, _ := mgr.CheckPreTrade(ctx, newScopeTestOrder("alpha", "BTCUSDT"))
+	if !decision.IsBlocked() || !strings.HasPrefix(decision.Reason, "SYMBOL acc-1/alpha:BTCUSDT") {
+		t.Fatalf("expected alpha/BTCUSDT blocked at symbol scope, got %v (%s)", decision.Decision, decision.Reason)
+	}
+
+	for _, req := range []*OrderContext{
+		newScopeTestOrder("alpha", "ETHUSDT"),
+		newScopeTestOrder("beta", "BTCUSDT"),
+	} {
+		if decision, _ := mgr.CheckPreTrade(ctx, req); !decision.IsAllowed() {
+			t.Errorf("%s/%s: expected allowed, got %v (%s)", req.StrategyID, req.Symbol, decision.Decision, decision.Reason)
+		}
+	}
+}
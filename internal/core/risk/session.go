@@ -0,0 +1,182 @@
+package risk
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	"github.com/iluyuns/alpha-trade/internal/pkg/metrics"
+)
+
+// SetSession 设置账户交易日定义（accountID 为空或 DefaultRuleSet 时替换默认定义）
+// 已持久化状态的下次重置时刻不变，滚动后按新定义计算
+func (m *Manager) SetSession(accountID string, session model.TradingSession) error {
+	if err := session.Validate(); err != nil {
+		return err
+	}
+	if accountID == "" {
+		accountID = DefaultRuleSet
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[accountID] = session
+	return nil
+}
+
+// Session 账户当前生效的交易日定义
+func (m *Manager) Session(accountID string) model.TradingSession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if session, ok := m.sessions[accountID]; ok {
+		return session
+	}
+	return m.sessions[DefaultRuleSet]
+}
+
+// SessionAccounts 单独配置了交易日定义的账户（按 ID 排序）
+func (m *Manager) SessionAccounts() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	accounts := make([]string, 0, len(m.sessions))
+	for accountID := range m.sessions {
+		if accountID != DefaultRuleSet {
+			accounts = append(accounts, accountID)
+		}
+	}
+	sort.Strings(accounts)
+	return accounts
+}
+
+// SetSnapshotRepo 设置资产快照仓储（账户级每日滚动时记录交易日开始净值）
+func (m *Manager) SetSnapshotRepo(repo port.AssetSnapshotRepo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots = repo
+}
+
+// RollDaily 到达重置时刻时滚动账户级当日统计，返回本次调用是否执行了滚动
+// 由 SessionScheduler 定时调用，无订单时当日统计也能按时重置
+func (m *Manager) RollDaily(ctx context.Context, accountID string, now time.Time) (bool, error) {
+	state, err := m.loadState(ctx, model.AccountScope(accountID))
+	if err != nil {
+		return false, err
+	}
+	if !state.ShouldResetDaily(now) {
+		return false, nil
+	}
+	return m.rollState(ctx, state, now)
+}
+
+// rollState 以乐观锁滚动当日统计（多进程同时滚动时只有一个进程生效）
+// 持久化失败时 state 仍按滚动后的状态返回，保证本次检查按新交易日评估
+func (m *Manager) rollState(ctx context.Context, state *model.RiskState, now time.Time) (bool, error) {
+	session := m.Session(state.AccountID)
+
+	var rolled bool
+	err := m.updateState(ctx, state, "daily rollover", func(s *model.RiskState) {
+		rolled = s.ShouldResetDaily(now)
+		if rolled {
+			s.RollDaily(session, now)
+		}
+	})
+	if err != nil {
+		state.RollDaily(session, now)
+		return false, err
+	}
+
+	if rolled && state.Scope().Level() == model.RiskRuleScopeAccount && !isDryRun(ctx) {
+		metrics.DefaultMetrics.RiskDailyRollovers.Inc()
+		m.snapshotDailyStart(ctx, state, now)
+	}
+	return rolled, nil
+}
+
+// snapshotDailyStart 记录交易日开始净值（未设置快照仓储时忽略）
+func (m *Manager) snapshotDailyStart(ctx context.Context, state *model.RiskState, now time.Time) {
+	m.mu.RLock()
+	repo := m.snapshots
+	m.mu.RUnlock()
+
+	if repo == nil {
+		return
+	}
+	err := repo.SaveSnapshot(ctx, &model.AssetSnapshot{
+		AccountID:        state.AccountID,
+		Kind:             model.AssetSnapshotKindDailyStart,
+		TradingDay:       state.LastResetDate,
+		TotalBalance:     state.CurrentEquity,
+		AvailableBalance: model.Zero(),
+		FrozenBalance:    model.Zero(),
+		SnapshotTime:     now,
+	})
+	if err != nil {
+		metrics.DefaultMetrics.RiskSnapshotFailures.Inc()
+	}
+}
+
+// SessionScheduler 交易日滚动调度器
+// 定时检查已配置交易日定义的账户，到达重置时刻时主动滚动当日统计（不依赖下单触发）
+type SessionScheduler struct {
+	manager  *Manager
+	interval time.Duration
+	accounts func() []string                   // 需要滚动的账户（Manager.SessionAccounts）
+	onError  func(accountID string, err error) // 滚动失败回调（可选）
+}
+
+// NewSessionScheduler 创建交易日滚动调度器（interval 默认 1 分钟）
+func NewSessionScheduler(manager *Manager, interval time.Duration) *SessionScheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &SessionScheduler{
+		manager:  manager,
+		interval: interval,
+		accounts: manager.SessionAccounts,
+	}
+}
+
+// OnError 设置滚动失败回调（用于日志告警）
+func (s *SessionScheduler) OnError(fn func(accountID string, err error)) {
+	s.onError = fn
+}
+
+// Start 启动后台定时滚动（ctx 结束时退出）
+func (s *SessionScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.Tick(ctx, time.Now())
+		for {
+			select {
+			case now := <-ticker.C:
+				s.Tick(ctx, now)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Tick 检查并滚动一次，返回执行了滚动的账户
+func (s *SessionScheduler) Tick(ctx context.Context, now time.Time) []string {
+	var rolled []string
+	for _, accountID := range s.accounts() {
+		ok, err := s.manager.RollDaily(ctx, accountID, now)
+		if err != nil {
+			if s.onError != nil {
+				s.onError(accountID, err)
+			}
+			continue
+		}
+		if ok {
+			rolled = append(rolled, accountID)
+		}
+	}
+	return rolled
+}
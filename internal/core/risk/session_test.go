@@ -0,0 +1,114 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	snapshotrepo "github.com/iluyuns/alpha-trade/internal/infra/assetsnapshot"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+// seedDueState 写入一个已到达重置时刻的账户状态
+func seedDueState(t *testing.T, repo port.RiskRepo, accountID string) {
+	t.Helper()
+	state := model.NewRiskState(accountID, model.MustMoney("10000"))
+	state.CurrentEquity = model.MustMoney("10500")
+	state.RecordTrade(model.MustMoney("-200"))
+	state.DailyResetTime = time.Now().Add(-time.Minute)
+	if err := repo.SaveState(context.Background(), state); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+}
+
+func TestManager_SetSession(t *testing.T) {
+	mgr := NewManager(&mockRiskRepo{}, RiskConfig{})
+
+	if got := mgr.Session("acc-1"); got != model.DefaultTradingSession() {
+		t.Errorf("default session = %+v", got)
+	}
+
+	shanghai := model.TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8}
+	if err := mgr.SetSession("acc-1", shanghai); err != nil {
+		t.Fatalf("SetSession failed: %v", err)
+	}
+	if got := mgr.Session("acc-1"); got != shanghai {
+		t.Errorf("session = %+v, want %+v", got, shanghai)
+	}
+	if got := mgr.SessionAccounts(); len(got) != 1 || got[0] != "acc-1" {
+		t.Errorf("SessionAccounts = %v, want [acc-1]", got)
+	}
+
+	if err := mgr.SetSession("acc-2", model.TradingSession{Timezone: "UTC", ResetHour: 25}); err == nil {
+		t.Error("invalid session should be rejected")
+	}
+}
+
+func TestSessionScheduler_RollsWithoutOrders(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	snapshots := snapshotrepo.NewMemoryRepo()
+	ctx := context.Background()
+	seedDueState(t, repo, "acc-1")
+
+	// 两个进程共享同一仓储，只应滚动一次、生成一条快照
+	var replicas []*Manager
+	for i := 0; i < 2; i++ {
+		mgr := NewManager(repo, RiskConfig{})
+		mgr.SetSnapshotRepo(snapshots)
+		if err := mgr.SetSession("acc-1", model.TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8}); err != nil {
+			t.Fatalf("SetSession failed: %v", err)
+		}
+		replicas = append(replicas, mgr)
+	}
+
+	now := time.Now()
+	first := NewSessionScheduler(replicas[0], time.Minute).Tick(ctx, now)
+	second := NewSessionScheduler(replicas[1], time.Minute).Tick(ctx, now)
+	if len(first) != 1 || len(second) != 0 {
+		t.Errorf("rolled = %v / %v, want exactly one rollover", first, second)
+	}
+
+	state, _ := repo.LoadState(ctx, model.AccountScope("acc-1"))
+	if !state.DailyPnL.IsZero() || state.DailyTradeCount != 0 {
+		t.Errorf("daily stats not reset: pnl=%s count=%d", state.DailyPnL, state.DailyTradeCount)
+	}
+	session := replicas[0].Session("acc-1")
+	if want := session.NextDailyReset(now); !state.DailyResetTime.Equal(want) {
+		t.Errorf("DailyResetTime = %s, want %s", state.DailyResetTime, want)
+	}
+
+	list, _ := snapshots.ListSnapshots(ctx, "acc-1", model.AssetSnapshotKindDailyStart, 0)
+	if len(list) != 1 {
+		t.Fatalf("snapshots = %d, want 1", len(list))
+	}
+	if list[0].TradingDay != session.TradingDay(now) || !list[0].TotalBalance.EQ(model.MustMoney("10500")) {
+		t.Errorf("unexpected snapshot %+v", list[0])
+	}
+}
+
+func TestCheckPreTrade_RollsWithAccountSession(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	snapshots := snapshotrepo.NewMemoryRepo()
+	ctx := context.Background()
+	seedDueState(t, repo, "acc-1")
+
+	mgr := NewManager(repo, RiskConfig{})
+	mgr.SetSnapshotRepo(snapshots)
+	if err := mgr.SetSession("acc-1", model.TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8}); err != nil {
+		t.Fatalf("SetSession failed: %v", err)
+	}
+
+	if _, err := mgr.CheckPreTrade(ctx, newRuleTestOrder("0.01")); err != nil {
+		t.Fatalf("CheckPreTrade failed: %v", err)
+	}
+
+	state, _ := repo.LoadState(ctx, model.AccountScope("acc-1"))
+	if want := mgr.Session("acc-1").TradingDay(time.Now()); state.LastResetDate != want {
+		t.Errorf("LastResetDate = %s, want %s", state.LastResetDate, want)
+	}
+	if list, _ := snapshots.ListSnapshots(ctx, "acc-1", "", 0); len(list) != 1 {
+		t.Errorf("snapshots = %d, want 1", len(list))
+	}
+}
@@ -0,0 +1,130 @@
+package risk
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// dryRunKey 模拟检查的 context 标记
+type dryRunKey struct{}
+
+// withDryRun 标记为模拟检查：规则对状态的变更（熔断开关、交易日滚动）只作用于内存副本
+func withDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// isDryRun 是否为模拟检查
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// RuleResult 单条规则的评估结果
+type RuleResult struct {
+	Rule     string
+	Priority int
+	Decision DecisionDetail
+	Quantity model.Money // 该规则评估时的订单数量（前序规则降档后）
+	Leverage int         // 该规则评估时的杠杆
+}
+
+// Exposure 账户敞口
+type Exposure struct {
+	Equity         model.Money // 账户净值
+	OrderNotional  model.Money // 订单名义价值（只减仓订单为负）
+	SymbolExposure model.Money // 标的敞口
+	SymbolPercent  float64     // 标的敞口占净值比例
+	TotalExposure  model.Money // 总敞口
+	TotalPercent   float64     // 总敞口占净值比例
+}
+
+// Simulation 模拟风控检查结果
+type Simulation struct {
+	Decision          DecisionDetail // 最终决策（与 CheckPreTrade 一致）
+	Rules             []RuleResult   // 按评估顺序的规则结果（Block 短路后的规则不出现）
+	SuggestedQuantity model.Money    // 最终放行数量（拦截时为 0）
+	SuggestedLeverage int            // 最终放行杠杆
+	Before            Exposure       // 当前敞口
+	After             Exposure       // 按最终放行数量成交后的预计敞口
+	State             *model.RiskState
+}
+
+// Simulate 模拟交易前风控检查（What-if）
+// 基于当前风控状态的副本执行与 CheckPreTrade 相同的规则链，返回逐条规则的评估结果与成交后的预计敞口；
+// 不写入风控状态、状态历史与决策记录，也不计入风控指标
+func (m *Manager) Simulate(ctx context.Context, req *OrderContext) (*Simulation, error) {
+	ctx = withDryRun(ctx)
+
+	state, err := m.loadScopeState(ctx, model.AccountScope(req.AccountID))
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &Simulation{
+		Before: exposureOf(state, req.Symbol, model.Zero()),
+	}
+
+	// 全局交易暂停先于规则链
+	decision, halted := m.checkHalt(req)
+	current := req
+	if halted {
+		sim.Rules = append(sim.Rules, RuleResult{
+			Rule:     haltRule,
+			Decision: decision,
+			Quantity: req.Quantity,
+			Leverage: req.Leverage,
+		})
+	} else {
+		decision, current = m.evaluateRules(ctx, req, state, func(rule Rule, req *OrderContext, decision DecisionDetail) {
+			sim.Rules = append(sim.Rules, RuleResult{
+				Rule:     rule.Name(),
+				Priority: rule.Priority(),
+				Decision: decision,
+				Quantity: req.Quantity,
+				Leverage: req.Leverage,
+			})
+		})
+	}
+
+	sim.Decision = decision
+	sim.State = state
+	if decision.IsBlocked() {
+		sim.SuggestedQuantity = model.Zero()
+		sim.After = sim.Before
+		return sim, nil
+	}
+
+	sim.SuggestedQuantity = current.Quantity
+	sim.SuggestedLeverage = current.Leverage
+	notional := calculateNotional(current)
+	if current.ReduceOnly {
+		notional = notional.Min(state.PositionMap[req.Symbol]).Neg()
+	}
+	sim.After = exposureOf(state, req.Symbol, notional)
+	return sim, nil
+}
+
+// exposureOf 计算在当前敞口上叠加 notional 后的账户敞口（敞口不低于 0）
+func exposureOf(state *model.RiskState, symbol string, notional model.Money) Exposure {
+	symbolExposure := state.PositionMap[symbol].Add(notional)
+	if symbolExposure.IsNegative() {
+		symbolExposure = model.Zero()
+	}
+	totalExposure := state.TotalExposure.Add(notional)
+	if totalExposure.IsNegative() {
+		totalExposure = model.Zero()
+	}
+
+	exposure := Exposure{
+		Equity:         state.CurrentEquity,
+		OrderNotional:  notional,
+		SymbolExposure: symbolExposure,
+		TotalExposure:  totalExposure,
+	}
+	if state.CurrentEquity.IsPositive() {
+		exposure.SymbolPercent = symbolExposure.Div(state.CurrentEquity).Float64()
+		exposure.TotalPercent = totalExposure.Div(state.CurrentEquity).Float64()
+	}
+	return exposure
+}
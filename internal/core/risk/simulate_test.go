@@ -0,0 +1,106 @@
+package risk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+	historyrepo "github.com/iluyuns/alpha-trade/internal/infra/riskhistory"
+	"github.com/iluyuns/alpha-trade/internal/infra/riskrecord"
+)
+
+func TestSimulate_ReduceWithProjectedExposure(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	ctx := context.Background()
+
+	state := model.NewRiskState("acc-1", model.MustMoney("10000"))
+	state.PositionMap["BTCUSDT"] = model.MustMoney("2000")
+	state.TotalExposure = model.MustMoney("2000")
+	if err := repo.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	mgr := NewManager(repo, RiskConfig{MaxSinglePositionPercent: 0.3, MaxTotalExposurePercent: 1})
+	sim, err := mgr.Simulate(ctx, newRuleTestOrder("0.1"))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	if !sim.Decision.ShouldReduce() {
+		t.Fatalf("expected reduce, got %v (%s)", sim.Decision.Decision, sim.Decision.Reason)
+	}
+	if !sim.SuggestedQuantity.EQ(model.MustMoney("0.02")) || sim.SuggestedLeverage != 1 {
+		t.Errorf("suggested = %s x%d, want 0.02 x1", sim.SuggestedQuantity, sim.SuggestedLeverage)
+	}
+	// 熔断与仓位限制逐条记录；策略绩效规则仅作用于标记策略的订单
+	if len(sim.Rules) != 2 || !sim.Rules[1].Decision.ShouldReduce() || !sim.Rules[1].Quantity.EQ(model.MustMoney("0.1")) {
+		t.Errorf("unexpected rule results: %+v", sim.Rules)
+	}
+
+	if !sim.Before.SymbolExposure.EQ(model.MustMoney("2000")) {
+		t.Errorf("before symbol exposure = %s, want 2000", sim.Before.SymbolExposure)
+	}
+	if !sim.After.SymbolExposure.EQ(model.MustMoney("3000")) || !sim.After.TotalExposure.EQ(model.MustMoney("3000")) {
+		t.Errorf("after exposure = %s / %s, want 3000 / 3000", sim.After.SymbolExposure, sim.After.TotalExposure)
+	}
+	if sim.After.SymbolPercent != 0.3 {
+		t.Errorf("after symbol percent = %v, want 0.3", sim.After.SymbolPercent)
+	}
+}
+
+func TestSimulate_DoesNotPersist(t *testing.T) {
+	repo := riskrepo.NewMemoryRiskRepo()
+	history := historyrepo.NewMemoryRepo()
+	recorder := NewHistoryRecorder(history, HistoryConfig{})
+	records := riskrecord.NewMemoryRepo()
+	ctx := context.Background()
+
+	mgr := NewManager(repo, RiskConfig{MaxConsecutiveLosses: 2})
+	mgr.SetHistoryRecorder(recorder)
+	decisions := NewRecorder(records, RecorderConfig{AllowSampleRate: 1})
+	mgr.SetRecorder(decisions)
+
+	scope := model.AccountScope("acc-1")
+	for i := 0; i < 2; i++ {
+		if err := mgr.RecordTrade(ctx, scope, model.MustMoney("-100")); err != nil {
+			t.Fatalf("RecordTrade failed: %v", err)
+		}
+	}
+	before, _ := repo.LoadState(ctx, scope)
+
+	sim, err := mgr.Simulate(ctx, newRuleTestOrder("0.01"))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if !sim.Decision.IsBlocked() || len(sim.Rules) != 1 {
+		t.Fatalf("expected blocked by first rule, got %+v", sim)
+	}
+	if !sim.State.CircuitBreakerOpen {
+		t.Error("expected simulated state to open circuit breaker")
+	}
+	if !sim.SuggestedQuantity.IsZero() || !sim.After.TotalExposure.EQ(sim.Before.TotalExposure) {
+		t.Errorf("expected no projected fill when blocked, got %s", sim.SuggestedQuantity)
+	}
+
+	// 熔断未写入持久化、缓存、状态历史与决策记录
+	after, _ := repo.LoadState(ctx, scope)
+	if after.CircuitBreakerOpen || after.Version != before.Version {
+		t.Errorf("expected state untouched, got open=%v version %d -> %d",
+			after.CircuitBreakerOpen, before.Version, after.Version)
+	}
+	if cached, _ := mgr.loadState(ctx, scope); cached.CircuitBreakerOpen {
+		t.Error("expected cached state untouched")
+	}
+	recorder.Flush(ctx)
+	snapshots, _ := history.ListSnapshots(ctx, model.RiskStateHistoryFilter{Scope: scope})
+	for _, s := range snapshots {
+		if s.Kind == model.RiskHistoryKindCircuitOpen {
+			t.Error("expected no circuit open snapshot")
+		}
+	}
+	decisions.Flush(ctx)
+	if _, total, _ := records.ListRecords(ctx, model.RiskRecordFilter{}); total != 0 {
+		t.Errorf("expected no decision records, got %d", total)
+	}
+}
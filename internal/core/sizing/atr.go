@@ -0,0 +1,74 @@
+package sizing
+
+import (
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// ATR 平均真实波幅（Average True Range，Wilder 平滑）
+// 用于波动率头寸缩放与 ATR 止损
+type ATR struct {
+	period    int
+	count     int
+	prevClose model.Money
+	sumTR     model.Money // 预热阶段 TR 累加
+	value     model.Money
+}
+
+// NewATR 创建 ATR 指标（period <= 0 时默认 14）
+func NewATR(period int) *ATR {
+	if period <= 0 {
+		period = 14
+	}
+	return &ATR{
+		period:    period,
+		prevClose: model.Zero(),
+		sumTR:     model.Zero(),
+		value:     model.Zero(),
+	}
+}
+
+// Update 输入一根 K 线，返回最新 ATR 值（预热期间返回零）
+func (a *ATR) Update(c *model.Candle) model.Money {
+	tr := trueRange(c, a.prevClose)
+	a.prevClose = c.Close
+	a.count++
+
+	if a.count <= a.period {
+		a.sumTR = a.sumTR.Add(tr)
+		if a.count == a.period {
+			a.value = a.sumTR.Div(model.NewMoneyFromInt(int64(a.period)))
+		}
+		return a.value
+	}
+
+	// Wilder 平滑：ATR = (ATR_prev * (n-1) + TR) / n
+	n := model.NewMoneyFromInt(int64(a.period))
+	a.value = a.value.Mul(n.Sub(model.NewMoneyFromInt(1))).Add(tr).Div(n)
+	return a.value
+}
+
+// Value 当前 ATR 值
+func (a *ATR) Value() model.Money {
+	return a.value
+}
+
+// Ready 是否已完成预热
+func (a *ATR) Ready() bool {
+	return a.count >= a.period
+}
+
+// trueRange 真实波幅：max(H-L, |H-prevClose|, |L-prevClose|)
+func trueRange(c *model.Candle, prevClose model.Money) model.Money {
+	tr := c.Range()
+	if prevClose.IsZero() {
+		return tr
+	}
+
+	if hc := c.High.Sub(prevClose).Abs(); hc.GT(tr) {
+		tr = hc
+	}
+	if lc := c.Low.Sub(prevClose).Abs(); lc.GT(tr) {
+		tr = lc
+	}
+	return tr
+}
@@ -0,0 +1,206 @@
+package sizing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+var (
+	// ErrInvalidPrice 价格无效
+	ErrInvalidPrice = errors.New("sizing: price must be positive")
+	// ErrATRNotReady ATR 未就绪（波动率模式需要 ATR）
+	ErrATRNotReady = errors.New("sizing: atr not ready")
+	// ErrNoEquity 账户净值为零
+	ErrNoEquity = errors.New("sizing: account equity is not positive")
+)
+
+// Mode 仓位计算模式
+type Mode int
+
+const (
+	// ModeVolatility 波动率目标：Quantity = AccountRiskAmount / (ATR * N)
+	ModeVolatility Mode = iota + 1
+	// ModeFixedFractional 固定比例：Quantity = Equity * Fraction / Price
+	ModeFixedFractional
+	// ModeKelly Kelly 公式（带上限）：Quantity = Equity * min(f*, KellyCap) / Price
+	ModeKelly
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeVolatility:
+		return "VOLATILITY"
+	case ModeFixedFractional:
+		return "FIXED_FRACTIONAL"
+	case ModeKelly:
+		return "KELLY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseMode 解析配置中的模式名称（大小写不敏感，空字符串默认波动率模式）
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(s) {
+	case "", "volatility", "atr":
+		return ModeVolatility, nil
+	case "fixed_fractional", "fixed":
+		return ModeFixedFractional, nil
+	case "kelly":
+		return ModeKelly, nil
+	default:
+		return 0, fmt.Errorf("sizing: unknown mode %q", s)
+	}
+}
+
+// Config 仓位计算配置（每个策略可独立选择）
+type Config struct {
+	Mode Mode
+
+	// 波动率模式
+	RiskPerTrade float64 // 每笔交易风险占净值比例（e.g. 0.01 = 1%）
+	ATRMultiple  float64 // 止损距离 = ATR * N（默认 2）
+
+	// 固定比例模式
+	Fraction float64 // 单笔名义价值占净值比例
+
+	// Kelly 模式
+	WinRate     float64 // 胜率
+	PayoffRatio float64 // 盈亏比（AvgWin / AvgLoss）
+	KellyCap    float64 // Kelly 比例上限（e.g. 0.25 表示最多使用 1/4 净值）
+
+	// 交易所精度约束
+	LotSize model.Money // 数量步长（向下取整）
+	MinQty  model.Money // 最小下单数量（低于此值返回零）
+	MaxQty  model.Money // 最大下单数量（零表示不限制）
+}
+
+// Request 仓位计算请求
+type Request struct {
+	AccountID string
+	Symbol    string
+	Price     model.Money // 参考价格（固定比例/Kelly 模式用于名义价值换算）
+	ATR       model.Money // 当前 ATR（波动率模式必填）
+}
+
+// Sizer 仓位计算器
+// 账户风险额取自 RiskState.CurrentEquity，策略与 OMS 均可调用
+type Sizer struct {
+	mu       sync.RWMutex
+	repo     port.RiskRepo
+	fallback Config
+	configs  map[string]Config // key: 策略名称
+}
+
+// NewSizer 创建仓位计算器
+// fallback: 未单独配置的策略使用的默认配置
+func NewSizer(repo port.RiskRepo, fallback Config) *Sizer {
+	return &Sizer{
+		repo:     repo,
+		fallback: fallback,
+		configs:  make(map[string]Config),
+	}
+}
+
+// SetStrategyConfig 为指定策略设置仓位计算配置
+func (s *Sizer) SetStrategyConfig(strategy string, cfg Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[strategy] = cfg
+}
+
+// ConfigFor 获取策略的仓位计算配置
+func (s *Sizer) ConfigFor(strategy string) Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if cfg, ok := s.configs[strategy]; ok {
+		return cfg
+	}
+	return s.fallback
+}
+
+// Size 计算下单数量
+// 返回值已按 LotSize 向下取整，低于 MinQty 时返回零
+func (s *Sizer) Size(ctx context.Context, strategy string, req *Request) (model.Money, error) {
+	state, err := s.repo.LoadState(ctx, model.AccountScope(req.AccountID))
+	if err != nil {
+		return model.Zero(), fmt.Errorf("sizing: load risk state failed: %w", err)
+	}
+	if !state.CurrentEquity.IsPositive() {
+		return model.Zero(), ErrNoEquity
+	}
+
+	return Calculate(s.ConfigFor(strategy), state.CurrentEquity, req)
+}
+
+// Calculate 按配置计算下单数量（纯函数，便于回测与测试）
+func Calculate(cfg Config, equity model.Money, req *Request) (model.Money, error) {
+	var qty model.Money
+
+	switch cfg.Mode {
+	case ModeVolatility, 0:
+		if !req.ATR.IsPositive() {
+			return model.Zero(), ErrATRNotReady
+		}
+		multiple := cfg.ATRMultiple
+		if multiple <= 0 {
+			multiple = 2
+		}
+		riskAmount := equity.Mul(model.NewMoneyFromFloat(cfg.RiskPerTrade))
+		stopDistance := req.ATR.Mul(model.NewMoneyFromFloat(multiple))
+		qty = riskAmount.Div(stopDistance)
+
+	case ModeFixedFractional:
+		if !req.Price.IsPositive() {
+			return model.Zero(), ErrInvalidPrice
+		}
+		notional := equity.Mul(model.NewMoneyFromFloat(cfg.Fraction))
+		qty = notional.Div(req.Price)
+
+	case ModeKelly:
+		if !req.Price.IsPositive() {
+			return model.Zero(), ErrInvalidPrice
+		}
+		fraction := kellyFraction(cfg.WinRate, cfg.PayoffRatio)
+		if cfg.KellyCap > 0 && fraction > cfg.KellyCap {
+			fraction = cfg.KellyCap
+		}
+		if fraction <= 0 {
+			// 期望为负，不开仓
+			return model.Zero(), nil
+		}
+		notional := equity.Mul(model.NewMoneyFromFloat(fraction))
+		qty = notional.Div(req.Price)
+
+	default:
+		return model.Zero(), fmt.Errorf("sizing: unsupported mode %s", cfg.Mode)
+	}
+
+	return applyLimits(cfg, qty), nil
+}
+
+// kellyFraction Kelly 最优比例：f* = W - (1 - W) / R
+func kellyFraction(winRate, payoff float64) float64 {
+	if payoff <= 0 {
+		return 0
+	}
+	return winRate - (1-winRate)/payoff
+}
+
+// applyLimits 应用数量上限、步长与最小数量
+func applyLimits(cfg Config, qty model.Money) model.Money {
+	if cfg.MaxQty.IsPositive() {
+		qty = qty.Min(cfg.MaxQty)
+	}
+	qty = qty.FloorToStep(cfg.LotSize)
+	if qty.IsNegative() || (cfg.MinQty.IsPositive() && qty.LT(cfg.MinQty)) {
+		return model.Zero()
+	}
+	return qty
+}
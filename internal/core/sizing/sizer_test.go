@@ -0,0 +1,195 @@
+package sizing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/infra/risk"
+)
+
+func TestCalculate_Volatility(t *testing.T) {
+	tests := []struct {
+		name    string
+		equity  string
+		atr     string
+		cfg     Config
+		want    string
+		wantErr error
+	}{
+		{
+			// 10000 * 1% = 100 USDT 风险；止损距离 = 500 * 2 = 1000 -> 0.1
+			name:   "basic",
+			equity: "10000",
+			atr:    "500",
+			cfg:    Config{Mode: ModeVolatility, RiskPerTrade: 0.01, ATRMultiple: 2},
+			want:   "0.1",
+		},
+		{
+			// 高波动 -> 更小仓位
+			name:   "high volatility",
+			equity: "10000",
+			atr:    "1000",
+			cfg:    Config{Mode: ModeVolatility, RiskPerTrade: 0.01, ATRMultiple: 2},
+			want:   "0.05",
+		},
+		{
+			// 100 / (300 * 2) = 0.16666 -> lot 0.001 -> 0.166
+			name:   "lot size rounding",
+			equity: "10000",
+			atr:    "300",
+			cfg:    Config{Mode: ModeVolatility, RiskPerTrade: 0.01, ATRMultiple: 2, LotSize: model.MustMoney("0.001")},
+			want:   "0.166",
+		},
+		{
+			name:   "below min qty",
+			equity: "100",
+			atr:    "500",
+			cfg:    Config{Mode: ModeVolatility, RiskPerTrade: 0.01, ATRMultiple: 2, LotSize: model.MustMoney("0.001"), MinQty: model.MustMoney("0.01")},
+			want:   "0",
+		},
+		{
+			name:    "atr not ready",
+			equity:  "10000",
+			atr:     "0",
+			cfg:     Config{Mode: ModeVolatility, RiskPerTrade: 0.01},
+			wantErr: ErrATRNotReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Calculate(tt.cfg, model.MustMoney(tt.equity), &Request{
+				Symbol: "BTCUSDT",
+				Price:  model.MustMoney("50000"),
+				ATR:    model.MustMoney(tt.atr),
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Calculate failed: %v", err)
+			}
+			if !got.EQ(model.MustMoney(tt.want)) {
+				t.Errorf("qty = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculate_FixedFractional(t *testing.T) {
+	cfg := Config{Mode: ModeFixedFractional, Fraction: 0.2, LotSize: model.MustMoney("0.0001")}
+
+	// 10000 * 20% = 2000 USDT / 50000 = 0.04
+	got, err := Calculate(cfg, model.MustMoney("10000"), &Request{Price: model.MustMoney("50000")})
+	if err != nil {
+		t.Fatalf("Calculate failed: %v", err)
+	}
+	if !got.EQ(model.MustMoney("0.04")) {
+		t.Errorf("qty = %s, want 0.04", got)
+	}
+
+	if _, err := Calculate(cfg, model.MustMoney("10000"), &Request{}); !errors.Is(err, ErrInvalidPrice) {
+		t.Errorf("expected ErrInvalidPrice, got %v", err)
+	}
+}
+
+func TestCalculate_Kelly(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			// f* = 0.6 - 0.4/2 = 0.4, 上限 0.25 -> 2500 / 50000 = 0.05
+			name: "capped",
+			cfg:  Config{Mode: ModeKelly, WinRate: 0.6, PayoffRatio: 2, KellyCap: 0.25},
+			want: "0.05",
+		},
+		{
+			// f* = 0.5 - 0.5/1.5 = 0.1666.. -> 1666.67 / 50000 = 0.0333 (lot 0.0001)
+			name: "under cap",
+			cfg:  Config{Mode: ModeKelly, WinRate: 0.5, PayoffRatio: 1.5, KellyCap: 0.25, LotSize: model.MustMoney("0.0001")},
+			want: "0.0333",
+		},
+		{
+			// 负期望 -> 不开仓
+			name: "negative edge",
+			cfg:  Config{Mode: ModeKelly, WinRate: 0.3, PayoffRatio: 1, KellyCap: 0.25},
+			want: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Calculate(tt.cfg, model.MustMoney("10000"), &Request{Price: model.MustMoney("50000")})
+			if err != nil {
+				t.Fatalf("Calculate failed: %v", err)
+			}
+			if !got.EQ(model.MustMoney(tt.want)) {
+				t.Errorf("qty = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSizer_PerStrategyConfig(t *testing.T) {
+	ctx := context.Background()
+	repo := risk.NewMemoryRiskRepo()
+	_ = repo.SaveState(ctx, model.NewRiskState("acc", model.MustMoney("20000")))
+
+	sizer := NewSizer(repo, Config{Mode: ModeVolatility, RiskPerTrade: 0.01, ATRMultiple: 2})
+	sizer.SetStrategyConfig("fixed", Config{Mode: ModeFixedFractional, Fraction: 0.1})
+
+	req := &Request{AccountID: "acc", Symbol: "BTCUSDT", Price: model.MustMoney("50000"), ATR: model.MustMoney("500")}
+
+	// 默认配置：20000 * 1% / (500 * 2) = 0.2
+	got, err := sizer.Size(ctx, "SimpleVolatility", req)
+	if err != nil {
+		t.Fatalf("Size failed: %v", err)
+	}
+	if !got.EQ(model.MustMoney("0.2")) {
+		t.Errorf("default qty = %s, want 0.2", got)
+	}
+
+	// 策略配置：20000 * 10% / 50000 = 0.04
+	got, err = sizer.Size(ctx, "fixed", req)
+	if err != nil {
+		t.Fatalf("Size failed: %v", err)
+	}
+	if !got.EQ(model.MustMoney("0.04")) {
+		t.Errorf("fixed qty = %s, want 0.04", got)
+	}
+}
+
+func TestATR(t *testing.T) {
+	atr := NewATR(3)
+	candles := []*model.Candle{
+		{High: model.MustMoney("110"), Low: model.MustMoney("100"), Close: model.MustMoney("105")}, // TR 10
+		{High: model.MustMoney("112"), Low: model.MustMoney("104"), Close: model.MustMoney("110")}, // TR 8
+		{High: model.MustMoney("111"), Low: model.MustMoney("99"), Close: model.MustMoney("100")},  // TR 12
+		{High: model.MustMoney("103"), Low: model.MustMoney("97"), Close: model.MustMoney("101")},  // TR 6
+	}
+
+	for i, c := range candles[:2] {
+		atr.Update(c)
+		if atr.Ready() {
+			t.Fatalf("ATR should not be ready after %d candles", i+1)
+		}
+	}
+
+	// 预热完成：(10 + 8 + 12) / 3 = 10
+	if got := atr.Update(candles[2]); !got.EQ(model.MustMoney("10")) {
+		t.Errorf("ATR after warmup = %s, want 10", got)
+	}
+
+	// Wilder 平滑：(10 * 2 + 6) / 3 = 8.666...
+	got := atr.Update(candles[3])
+	if got.Float64() < 8.66 || got.Float64() > 8.67 {
+		t.Errorf("smoothed ATR = %s, want ~8.667", got)
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// 资产快照类型（对应 asset_snapshots.kind）
+const (
+	AssetSnapshotKindDailyStart = "DAILY_START" // 交易日开始净值（风控每日重置时生成）
+)
+
+// AssetSnapshot 资产快照（对应 asset_snapshots 表）
+type AssetSnapshot struct {
+	ID         int64
+	AccountID  string
+	Exchange   string
+	Kind       string // 快照类型（见 AssetSnapshotKind*）
+	TradingDay string // 所属交易日（格式：2006-01-02）
+
+	TotalBalance     Money // 总权益
+	AvailableBalance Money // 可用余额
+	FrozenBalance    Money // 冻结金额
+
+	SnapshotTime time.Time
+}
@@ -0,0 +1,324 @@
+package model
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrInvalidClientOrderID 无法按编码格式解析的客户端订单 ID
+	ErrInvalidClientOrderID = errors.New("invalid client order id")
+	// ErrStrategyCodeConflict 不同策略 ID 的策略编码冲突（需更换策略 ID）
+	ErrStrategyCodeConflict = errors.New("strategy code conflict")
+)
+
+// 各交易所客户端订单 ID 长度限制
+const (
+	BinanceClientOrderIDMaxLength = 36 // newClientOrderId: ^[.A-Z:/a-z0-9_-]{1,36}$
+	OKXClientOrderIDMaxLength     = 32 // clOrdId: 字母开头，仅字母与数字，1-32 位
+
+	// DefaultClientOrderIDMaxLength 默认长度上限（取各交易所的最小值，同一 ID 可在任一交易所使用）
+	DefaultClientOrderIDMaxLength = OKXClientOrderIDMaxLength
+)
+
+// OrderIntent 下单意图（编码进客户端订单 ID，用于归因）
+type OrderIntent byte
+
+const (
+	OrderIntentEntry     OrderIntent = 'E' // 策略开仓
+	OrderIntentExit      OrderIntent = 'X' // 策略平仓
+	OrderIntentStop      OrderIntent = 'S' // 止损 / 止盈触发平仓
+	OrderIntentTWAPChild OrderIntent = 'T' // 执行算法子单（如 TWAP）
+	OrderIntentManual    OrderIntent = 'M' // 人工或系统下单（无策略信号）
+)
+
+func (i OrderIntent) String() string {
+	switch i {
+	case OrderIntentEntry:
+		return "ENTRY"
+	case OrderIntentExit:
+		return "EXIT"
+	case OrderIntentStop:
+		return "STOP"
+	case OrderIntentTWAPChild:
+		return "TWAP_CHILD"
+	case OrderIntentManual:
+		return "MANUAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// valid 是否为已定义的意图
+func (i OrderIntent) valid() bool {
+	return i.String() != "UNKNOWN"
+}
+
+// 编码格式（仅字母与数字，满足各交易所字符集）：
+//
+//	B  E  0KQ5Z3XC  001F  7Q  3F0K  BTCUSDT
+//	|  |  |         |     |   |     └ 交易对（剩余部分，完整保留，最长 ClientOrderIDMaxSymbolLength 位）
+//	|  |  |         |     |   └ 策略编码（4 位 36 进制，见 StrategyCode，无策略为 0000）
+//	|  |  |         |     └ 随机数（2 位 36 进制，区分同毫秒同序号的不同进程）
+//	|  |  |         └ 序号（4 位 36 进制，进程内递增、循环使用）
+//	|  |  └ 毫秒时间戳（8 位 36 进制）
+//	|  └ 下单意图
+//	└ 格式版本（字母开头）
+const (
+	clientOrderIDVersion   = 'B'
+	clientOrderIDTimeLen   = 8
+	clientOrderIDSeqLen    = 4
+	clientOrderIDRandLen   = 2
+	clientOrderIDCodeLen   = 4
+	clientOrderIDHeaderLen = 2 + clientOrderIDTimeLen + clientOrderIDSeqLen + clientOrderIDRandLen + clientOrderIDCodeLen
+
+	// ClientOrderIDMaxSymbolLength 默认长度上限下交易对的最大长度（OKX 32 位 - 20 位固定头 = 12 位）
+	// 交易对不截断（网关查单依赖交易对），更长的交易对（如 1000000MOGUSDT）生成 ID 时返回错误
+	ClientOrderIDMaxSymbolLength = DefaultClientOrderIDMaxLength - clientOrderIDHeaderLen
+)
+
+var (
+	clientOrderIDSeqMod  = pow36(clientOrderIDSeqLen)
+	clientOrderIDRandMod = pow36(clientOrderIDRandLen)
+	clientOrderIDCodeMod = pow36(clientOrderIDCodeLen)
+
+	// noStrategyCode 无策略（人工或系统下单）的策略编码
+	noStrategyCode = strings.Repeat("0", clientOrderIDCodeLen)
+)
+
+// ClientOrderID 客户端订单 ID 的结构化内容
+type ClientOrderID struct {
+	Intent       OrderIntent
+	StrategyID   string    // 下单策略（编码为 StrategyCode，仅 StrategyRegistry.Decode 按注册表还原，未注册时为空）
+	StrategyCode string    // 策略编码（解析结果，编码时由 StrategyID 计算）
+	Symbol       string    // 交易对
+	Sequence     int64     // 进程内序号（按 4 位 36 进制取模）
+	Time         time.Time // 生成时间（毫秒精度）
+	Nonce        int64     // 随机数
+}
+
+// Encode 编码为客户端订单 ID（maxLen <= 0 时使用默认上限）
+// 交易对必须完整保留（网关查单依赖交易对），超出上限时返回错误（默认上限下最长 ClientOrderIDMaxSymbolLength 位）
+// 策略以定长编码写入，解析时需按策略注册表还原（见 StrategyRegistry.Decode）
+func (c ClientOrderID) Encode(maxLen int) (string, error) {
+	if maxLen <= 0 {
+		maxLen = DefaultClientOrderIDMaxLength
+	}
+	if !c.Intent.valid() {
+		return "", fmt.Errorf("%w: unknown intent %q", ErrInvalidClientOrderID, byte(c.Intent))
+	}
+	if c.Symbol == "" || !isAlphanumeric(c.Symbol) {
+		return "", fmt.Errorf("%w: symbol %q must be alphanumeric", ErrInvalidClientOrderID, c.Symbol)
+	}
+	if clientOrderIDHeaderLen+len(c.Symbol) > maxLen {
+		return "", fmt.Errorf("%w: symbol %q exceeds %d characters (max length %d)", ErrInvalidClientOrderID, c.Symbol, maxLen-clientOrderIDHeaderLen, maxLen)
+	}
+
+	var b strings.Builder
+	b.Grow(clientOrderIDHeaderLen + len(c.Symbol))
+	b.WriteByte(clientOrderIDVersion)
+	b.WriteByte(byte(c.Intent))
+	b.WriteString(base36(c.Time.UnixMilli(), clientOrderIDTimeLen))
+	b.WriteString(base36(c.Sequence%clientOrderIDSeqMod, clientOrderIDSeqLen))
+	b.WriteString(base36(c.Nonce%clientOrderIDRandMod, clientOrderIDRandLen))
+	b.WriteString(StrategyCode(c.StrategyID))
+	b.WriteString(c.Symbol)
+	return b.String(), nil
+}
+
+// DecodeClientOrderID 解析客户端订单 ID（Encode 的逆过程）
+// 只解析策略编码，不还原策略 ID（需要归属时使用 StrategyRegistry.Decode）
+func DecodeClientOrderID(id string) (ClientOrderID, error) {
+	if len(id) <= clientOrderIDHeaderLen || id[0] != clientOrderIDVersion || !isAlphanumeric(id) {
+		return ClientOrderID{}, fmt.Errorf("%w: %s", ErrInvalidClientOrderID, id)
+	}
+
+	c := ClientOrderID{Intent: OrderIntent(id[1])}
+	if !c.Intent.valid() {
+		return ClientOrderID{}, fmt.Errorf("%w: %s", ErrInvalidClientOrderID, id)
+	}
+
+	pos := 2
+	fields := []struct {
+		width int
+		dst   *int64
+	}{
+		{clientOrderIDTimeLen, new(int64)},
+		{clientOrderIDSeqLen, &c.Sequence},
+		{clientOrderIDRandLen, &c.Nonce},
+	}
+	for _, f := range fields {
+		v, err := strconv.ParseInt(id[pos:pos+f.width], 36, 64)
+		if err != nil {
+			return ClientOrderID{}, fmt.Errorf("%w: %s", ErrInvalidClientOrderID, id)
+		}
+		*f.dst = v
+		pos += f.width
+	}
+	c.Time = time.UnixMilli(*fields[0].dst)
+
+	c.StrategyCode = id[pos : pos+clientOrderIDCodeLen]
+	if _, err := strconv.ParseInt(c.StrategyCode, 36, 64); err != nil {
+		return ClientOrderID{}, fmt.Errorf("%w: %s", ErrInvalidClientOrderID, id)
+	}
+	c.Symbol = id[pos+clientOrderIDCodeLen:]
+	return c, nil
+}
+
+// SymbolFromClientOrderID 从客户端订单 ID 提取交易对
+// 兼容旧格式 <uuid>-<symbol>（取最后一个 "-" 之后的部分），无法解析时返回空字符串
+func SymbolFromClientOrderID(id string) string {
+	if c, err := DecodeClientOrderID(id); err == nil {
+		return c.Symbol
+	}
+	if i := strings.LastIndexByte(id, '-'); i >= 0 && i < len(id)-1 {
+		return id[i+1:]
+	}
+	return ""
+}
+
+// StrategyCode 策略 ID 的定长编码（FNV-1a 哈希取 4 位 36 进制，与进程无关；空策略为 0000）
+func StrategyCode(strategyID string) string {
+	if strategyID == "" {
+		return noStrategyCode
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strategyID))
+	return base36(int64(h.Sum32())%clientOrderIDCodeMod, clientOrderIDCodeLen)
+}
+
+// StrategyRegistry 策略注册表（策略编码 -> 策略 ID，用于从客户端订单 ID 还原下单策略，并发安全）
+// 服务启动时创建并注册全部已配置的策略，注入需要归属成交的组件（如 position.Ledger）
+type StrategyRegistry struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewStrategyRegistry 创建策略注册表
+func NewStrategyRegistry() *StrategyRegistry {
+	return &StrategyRegistry{ids: make(map[string]string)}
+}
+
+// Register 注册策略并返回其编码（重复注册幂等；编码与其他策略冲突时返回 ErrStrategyCodeConflict）
+func (r *StrategyRegistry) Register(strategyID string) (string, error) {
+	code := StrategyCode(strategyID)
+	if strategyID == "" {
+		return code, nil
+	}
+	if code == noStrategyCode {
+		return "", fmt.Errorf("%w: %s collides with the empty strategy", ErrStrategyCodeConflict, strategyID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.ids[code]; ok && existing != strategyID {
+		return "", fmt.Errorf("%w: %s and %s share code %s", ErrStrategyCodeConflict, existing, strategyID, code)
+	}
+	r.ids[code] = strategyID
+	return code, nil
+}
+
+// Lookup 按编码查找策略 ID
+func (r *StrategyRegistry) Lookup(code string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	strategyID, ok := r.ids[code]
+	return strategyID, ok
+}
+
+// Decode 解析客户端订单 ID 并按注册表还原策略 ID（编码未注册时 StrategyID 为空）
+func (r *StrategyRegistry) Decode(id string) (ClientOrderID, error) {
+	c, err := DecodeClientOrderID(id)
+	if err != nil {
+		return ClientOrderID{}, err
+	}
+	c.StrategyID, _ = r.Lookup(c.StrategyCode)
+	return c, nil
+}
+
+// ClientOrderIDGenerator 客户端订单 ID 生成器（进程内序号递增，并发安全）
+type ClientOrderIDGenerator struct {
+	maxLen     int
+	seq        atomic.Int64
+	strategies *StrategyRegistry // 已生成过 ID 的策略（检测编码冲突）
+}
+
+// NewClientOrderIDGenerator 创建生成器（maxLen <= 0 时使用默认上限）
+func NewClientOrderIDGenerator(maxLen int) *ClientOrderIDGenerator {
+	if maxLen <= 0 {
+		maxLen = DefaultClientOrderIDMaxLength
+	}
+	return &ClientOrderIDGenerator{maxLen: maxLen, strategies: NewStrategyRegistry()}
+}
+
+// Next 生成客户端订单 ID（与已生成过 ID 的其他策略编码冲突时返回错误）
+func (g *ClientOrderIDGenerator) Next(intent OrderIntent, strategyID, symbol string) (string, error) {
+	if _, err := g.strategies.Register(strategyID); err != nil {
+		return "", err
+	}
+	return ClientOrderID{
+		Intent:     intent,
+		StrategyID: strategyID,
+		Symbol:     symbol,
+		Sequence:   g.seq.Add(1),
+		Time:       time.Now(),
+		Nonce:      randomNonce(),
+	}.Encode(g.maxLen)
+}
+
+// defaultClientOrderIDs 进程内共享的生成器（策略引擎、OMS、执行算法共用序号）
+var defaultClientOrderIDs = NewClientOrderIDGenerator(DefaultClientOrderIDMaxLength)
+
+// NewClientOrderID 使用默认生成器生成客户端订单 ID
+func NewClientOrderID(intent OrderIntent, strategyID, symbol string) (string, error) {
+	return defaultClientOrderIDs.Next(intent, strategyID, symbol)
+}
+
+// randomNonce 随机数（随机源不可用时返回 0，仍由时间戳与序号区分）
+func randomNonce() int64 {
+	var b [2]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0
+	}
+	return (int64(b[0])<<8 | int64(b[1])) % clientOrderIDRandMod
+}
+
+// base36 按固定宽度编码（大写，左侧补 0，超出宽度时保留低位）
+func base36(v int64, width int) string {
+	if v < 0 {
+		v = -v
+	}
+	s := strings.ToUpper(strconv.FormatInt(v%pow36(width), 36))
+	if len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+	return s
+}
+
+func pow36(n int) int64 {
+	v := int64(1)
+	for i := 0; i < n; i++ {
+		v *= 36
+	}
+	return v
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isAlphanumericByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumericByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
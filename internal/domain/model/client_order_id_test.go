@@ -0,0 +1,179 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClientOrderID_RoundTrip(t *testing.T) {
+	now := time.UnixMilli(1760000000123)
+	registry := NewStrategyRegistry()
+	for _, strategyID := range []string{"SimpleVolatility", "ma_cross-v2", "breakout_momentum"} {
+		if _, err := registry.Register(strategyID); err != nil {
+			t.Fatalf("Register(%s) failed: %v", strategyID, err)
+		}
+	}
+	tests := []struct {
+		name   string
+		id     ClientOrderID
+		maxLen int
+	}{
+		{"entry", ClientOrderID{Intent: OrderIntentEntry, StrategyID: "SimpleVolatility", Symbol: "BTCUSDT", Sequence: 42, Time: now, Nonce: 7}, 0},
+		{"non-alphanumeric strategy", ClientOrderID{Intent: OrderIntentStop, StrategyID: "ma_cross-v2", Symbol: "ETHUSDT", Sequence: 1, Time: now}, 0},
+		{"long symbol", ClientOrderID{Intent: OrderIntentTWAPChild, StrategyID: "breakout_momentum", Symbol: "1000SHIBUSDT", Sequence: 3, Time: now}, 0},
+		{"binance limit", ClientOrderID{Intent: OrderIntentExit, StrategyID: "breakout_momentum", Symbol: "1000SHIBUSDT", Sequence: 3, Time: now}, BinanceClientOrderIDMaxLength},
+		{"no strategy", ClientOrderID{Intent: OrderIntentManual, Symbol: "BTCUSDT", Time: now}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.id.Encode(tt.maxLen)
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			limit := tt.maxLen
+			if limit == 0 {
+				limit = DefaultClientOrderIDMaxLength
+			}
+			if len(encoded) > limit || !isAlphanumeric(encoded) {
+				t.Fatalf("encoded id %q violates venue limits", encoded)
+			}
+
+			decoded, err := registry.Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if decoded.Intent != tt.id.Intent || decoded.Symbol != tt.id.Symbol || decoded.StrategyID != tt.id.StrategyID {
+				t.Errorf("decoded = %+v", decoded)
+			}
+			if decoded.StrategyCode != StrategyCode(tt.id.StrategyID) {
+				t.Errorf("decoded strategy code = %s, want %s", decoded.StrategyCode, StrategyCode(tt.id.StrategyID))
+			}
+			if decoded.Sequence != tt.id.Sequence || decoded.Nonce != tt.id.Nonce || !decoded.Time.Equal(now) {
+				t.Errorf("decoded seq/nonce/time = %d/%d/%s", decoded.Sequence, decoded.Nonce, decoded.Time)
+			}
+		})
+	}
+}
+
+func TestClientOrderID_UnregisteredStrategy(t *testing.T) {
+	encoded, err := ClientOrderID{Intent: OrderIntentEntry, StrategyID: "never_registered", Symbol: "BTCUSDT"}.Encode(0)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	// 生成 ID 不影响其他注册表：未注册的策略无法还原，只保留编码
+	if _, err := NewClientOrderID(OrderIntentEntry, "never_registered", "BTCUSDT"); err != nil {
+		t.Fatalf("NewClientOrderID failed: %v", err)
+	}
+	decoded, err := NewStrategyRegistry().Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.StrategyID != "" || decoded.StrategyCode != StrategyCode("never_registered") {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	// 不带注册表的解析只返回编码
+	registry := NewStrategyRegistry()
+	_, _ = registry.Register("never_registered")
+	if decoded, _ := DecodeClientOrderID(encoded); decoded.StrategyID != "" {
+		t.Errorf("DecodeClientOrderID should not resolve strategies, got %s", decoded.StrategyID)
+	}
+	if decoded, _ := registry.Decode(encoded); decoded.StrategyID != "never_registered" {
+		t.Errorf("registry.Decode = %+v", decoded)
+	}
+}
+
+func TestClientOrderID_SymbolLength(t *testing.T) {
+	// 默认上限（OKX 32 位）扣除 20 位固定头后，交易对最长 12 位
+	if ClientOrderIDMaxSymbolLength != 12 {
+		t.Fatalf("ClientOrderIDMaxSymbolLength = %d, want 12", ClientOrderIDMaxSymbolLength)
+	}
+	encoded, err := ClientOrderID{Intent: OrderIntentEntry, StrategyID: "ma_cross", Symbol: "1000SHIBUSDT"}.Encode(0)
+	if err != nil || len(encoded) != DefaultClientOrderIDMaxLength {
+		t.Fatalf("12-character symbol should fill the id exactly: %q, %v", encoded, err)
+	}
+
+	// 更长的交易对返回错误，不截断
+	for _, maxLen := range []int{0, OKXClientOrderIDMaxLength} {
+		if _, err := (ClientOrderID{Intent: OrderIntentEntry, Symbol: "1000000MOGUSDT"}).Encode(maxLen); !errors.Is(err, ErrInvalidClientOrderID) {
+			t.Errorf("Encode(%d) of a 14-character symbol expected error, got %v", maxLen, err)
+		}
+	}
+	if _, err := NewClientOrderID(OrderIntentEntry, "ma_cross", "1000000MOGUSDT"); !errors.Is(err, ErrInvalidClientOrderID) {
+		t.Errorf("NewClientOrderID of a 14-character symbol expected error, got %v", err)
+	}
+
+	// Binance 36 位上限下可容纳 16 位交易对
+	if _, err := (ClientOrderID{Intent: OrderIntentEntry, Symbol: "1000000MOGUSDT"}).Encode(BinanceClientOrderIDMaxLength); err != nil {
+		t.Errorf("Encode with binance limit failed: %v", err)
+	}
+}
+
+func TestStrategyRegistry_Conflict(t *testing.T) {
+	registry := NewStrategyRegistry()
+	if _, err := registry.Register("SimpleVolatility"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, err := registry.Register("SimpleVolatility"); err != nil {
+		t.Errorf("re-registering the same strategy should be idempotent: %v", err)
+	}
+
+	// 找到编码冲突的两个策略 ID
+	seen := make(map[string]string)
+	for i := 0; ; i++ {
+		strategyID := fmt.Sprintf("strategy_%d", i)
+		code := StrategyCode(strategyID)
+		existing, ok := seen[code]
+		if !ok {
+			seen[code] = strategyID
+			continue
+		}
+		if _, err := registry.Register(existing); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		if _, err := registry.Register(strategyID); !errors.Is(err, ErrStrategyCodeConflict) {
+			t.Errorf("expected conflict between %s and %s, got %v", existing, strategyID, err)
+		}
+		if got, _ := registry.Lookup(code); got != existing {
+			t.Errorf("conflicting registration should not replace %s, got %s", existing, got)
+		}
+		return
+	}
+}
+
+func TestClientOrderID_Invalid(t *testing.T) {
+	if _, err := (ClientOrderID{Intent: OrderIntentEntry, Symbol: "BTC-USDT"}).Encode(0); !errors.Is(err, ErrInvalidClientOrderID) {
+		t.Errorf("expected error for non-alphanumeric symbol, got %v", err)
+	}
+	if _, err := (ClientOrderID{Intent: OrderIntentEntry, Symbol: "VERYLONGSYMBOLNAMEUSDT"}).Encode(0); !errors.Is(err, ErrInvalidClientOrderID) {
+		t.Errorf("expected error for symbol exceeding max length, got %v", err)
+	}
+	if _, err := (ClientOrderID{Intent: 'Z', Symbol: "BTCUSDT"}).Encode(0); !errors.Is(err, ErrInvalidClientOrderID) {
+		t.Errorf("expected error for unknown intent, got %v", err)
+	}
+
+	for _, id := range []string{"", "0190f1c2-7a6b-7c3d-8e9f-0a1b2c3d4e5f-BTCUSDT", "AE" + "00000000000000" + "0000BTC", "BZ" + "00000000000000" + "0000BTC", "BE" + "00000000000000" + "0000", "BE" + "00000000000000" + "00-0BTC"} {
+		if _, err := DecodeClientOrderID(id); !errors.Is(err, ErrInvalidClientOrderID) {
+			t.Errorf("DecodeClientOrderID(%q) expected error, got %v", id, err)
+		}
+	}
+}
+
+func TestClientOrderIDGenerator_Unique(t *testing.T) {
+	gen := NewClientOrderIDGenerator(0)
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id, err := gen.Next(OrderIntentEntry, "ma_cross", "BTCUSDT")
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate client order id %s", id)
+		}
+		seen[id] = true
+	}
+}
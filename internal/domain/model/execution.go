@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// ParentStatus 母单状态
+type ParentStatus int
+
+const (
+	ParentStatusActive ParentStatus = iota + 1
+	ParentStatusCompleted
+	ParentStatusCancelled
+	ParentStatusFailed
+)
+
+func (s ParentStatus) String() string {
+	switch s {
+	case ParentStatusActive:
+		return "ACTIVE"
+	case ParentStatusCompleted:
+		return "COMPLETED"
+	case ParentStatusCancelled:
+		return "CANCELLED"
+	case ParentStatusFailed:
+		return "FAILED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseParentStatus 解析母单状态
+func ParseParentStatus(s string) ParentStatus {
+	switch s {
+	case "ACTIVE":
+		return ParentStatusActive
+	case "COMPLETED":
+		return ParentStatusCompleted
+	case "CANCELLED":
+		return ParentStatusCancelled
+	case "FAILED":
+		return ParentStatusFailed
+	default:
+		return 0
+	}
+}
+
+// ParentOrder 算法母单（由执行算法拆分为多笔子单）
+type ParentOrder struct {
+	ID         string
+	AccountID  string
+	StrategyID string
+	Symbol     string
+	Side       OrderSide
+	Algorithm  string // e.g. "TWAP"
+
+	// 数量
+	Quantity  Money // 母单总数量
+	Submitted Money // 已下发子单数量
+	Filled    Money // 已成交数量
+
+	// 调度参数
+	StartTime time.Time
+	EndTime   time.Time
+	Slices    int // 计划子单数量
+
+	// 子单属性
+	ReduceOnly bool
+
+	Status     ParentStatus
+	ChildCount int    // 已下发子单数量
+	LastError  string // 最近一次子单失败原因
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Remaining 剩余未下发数量
+func (p *ParentOrder) Remaining() Money {
+	return p.Quantity.Sub(p.Submitted)
+}
+
+// IsActive 是否仍在执行
+func (p *ParentOrder) IsActive() bool {
+	return p.Status == ParentStatusActive
+}
+
+// ChildOrder 母单与子单的关联记录
+type ChildOrder struct {
+	ParentID      string
+	ClientOrderID string
+	Seq           int // 子单序号（从 1 开始）
+	Quantity      Money
+	Filled        Money
+	Status        OrderStatus
+	CreatedAt     time.Time
+}
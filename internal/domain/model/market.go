@@ -11,6 +11,14 @@ type Tick struct {
 	RecvTime  time.Time // 系统接收时间
 }
 
+// MarkPrice 标记价格（止损判定使用，过滤最新成交价的插针）
+type MarkPrice struct {
+	Symbol    string
+	Price     Money
+	EventTime time.Time // 交易所推送时间（行情未携带时为接收时间）
+	RecvTime  time.Time // 系统接收时间
+}
+
 // Candle K线数据
 type Candle struct {
 	Symbol   string
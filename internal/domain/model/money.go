@@ -114,6 +114,23 @@ func (m Money) Neg() Money {
 	return Money{v: m.v.Neg()}
 }
 
+// FloorToStep 按步长向下取整（用于数量对齐交易所 LotSize）
+// step 非正数时原样返回
+func (m Money) FloorToStep(step Money) Money {
+	if !step.v.IsPositive() {
+		return m
+	}
+	return Money{v: m.v.Div(step.v).Floor().Mul(step.v)}
+}
+
+// Min 取较小值
+func (m Money) Min(other Money) Money {
+	if m.LE(other) {
+		return m
+	}
+	return other
+}
+
 // String 格式化为字符串
 func (m Money) String() string {
 	return m.v.String()
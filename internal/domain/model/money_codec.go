@@ -0,0 +1,93 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Money 序列化支持：
+// - JSON：字符串形式（"123.45"），避免 float64 精度损失；反序列化同时兼容数字与 null
+// - Text：用于 map key、YAML、URL 参数等
+// - SQL：以字符串写入 DECIMAL 列，读取兼容 string/[]byte/float64/int64
+// - Binary：紧凑二进制编码（热路径缓存使用）
+
+// MarshalJSON 实现 json.Marshaler
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.v.String() + `"`), nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*m = Zero()
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	return m.UnmarshalText(data)
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.v.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler（空字符串视为零）
+func (m *Money) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = Zero()
+		return nil
+	}
+	d, err := decimal.NewFromString(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid money value %q: %w", text, err)
+	}
+	m.v = d
+	return nil
+}
+
+// Value 实现 driver.Valuer（以字符串写入，由数据库转换为 DECIMAL）
+func (m Money) Value() (driver.Value, error) {
+	return m.v.String(), nil
+}
+
+// Scan 实现 sql.Scanner（NULL 视为零）
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Zero()
+		return nil
+	case string:
+		return m.UnmarshalText([]byte(v))
+	case []byte:
+		return m.UnmarshalText(v)
+	case float64:
+		m.v = decimal.NewFromFloat(v)
+		return nil
+	case int64:
+		m.v = decimal.NewFromInt(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler
+func (m Money) MarshalBinary() ([]byte, error) {
+	return m.v.MarshalBinary()
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler
+func (m *Money) UnmarshalBinary(data []byte) error {
+	var d decimal.Decimal
+	if err := d.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("invalid money binary: %w", err)
+	}
+	m.v = d
+	return nil
+}
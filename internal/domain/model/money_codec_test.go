@@ -0,0 +1,125 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoney_JSON(t *testing.T) {
+	type payload struct {
+		Price Money            `json:"price"`
+		Book  map[string]Money `json:"book"`
+	}
+
+	in := payload{
+		Price: MustMoney("50123.123456789012345678"),
+		Book:  map[string]Money{"BTCUSDT": MustMoney("-0.00000001")},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"price":"50123.123456789012345678","book":{"BTCUSDT":"-0.00000001"}}` {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+
+	var out payload
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !out.Price.EQ(in.Price) || !out.Book["BTCUSDT"].EQ(in.Book["BTCUSDT"]) {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+
+	// 兼容数字与 null
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"price":12.5}`, "12.5"},
+		{`{"price":null}`, "0"},
+		{`{"price":""}`, "0"},
+	}
+	for _, tt := range tests {
+		var p payload
+		if err := json.Unmarshal([]byte(tt.input), &p); err != nil {
+			t.Errorf("Unmarshal(%s) failed: %v", tt.input, err)
+			continue
+		}
+		if p.Price.String() != tt.want {
+			t.Errorf("Unmarshal(%s): got %s, want %s", tt.input, p.Price, tt.want)
+		}
+	}
+
+	var p payload
+	if err := json.Unmarshal([]byte(`{"price":"abc"}`), &p); err == nil {
+		t.Error("expected error for invalid money string")
+	}
+}
+
+func TestMoney_Text(t *testing.T) {
+	m := MustMoney("1.2300")
+	text, err := m.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText failed: %v", err)
+	}
+
+	var out Money
+	if err := out.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	if !out.EQ(m) {
+		t.Errorf("got %s, want %s", out, m)
+	}
+}
+
+func TestMoney_SQL(t *testing.T) {
+	v, err := MustMoney("0.1").Value()
+	if err != nil || v != "0.1" {
+		t.Fatalf("Value: got %v (%v), want \"0.1\"", v, err)
+	}
+
+	tests := []struct {
+		name string
+		src  any
+		want string
+	}{
+		{"string", "123.456", "123.456"},
+		{"bytes", []byte("-7.5"), "-7.5"},
+		{"float64", 0.25, "0.25"},
+		{"int64", int64(42), "42"},
+		{"nil", nil, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			if err := m.Scan(tt.src); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if m.String() != tt.want {
+				t.Errorf("got %s, want %s", m, tt.want)
+			}
+		})
+	}
+
+	var m Money
+	if err := m.Scan(true); err == nil {
+		t.Error("expected error scanning bool")
+	}
+}
+
+func TestMoney_Binary(t *testing.T) {
+	m := MustMoney("-98765.000000000000000001")
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	var out Money
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if !out.EQ(m) {
+		t.Errorf("got %s, want %s", out, m)
+	}
+}
@@ -123,3 +123,27 @@ func TestNewMoney_InvalidInput(t *testing.T) {
 		t.Error("expected error for empty string")
 	}
 }
+
+func TestMoney_FloorToStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		step  string
+		want  string
+	}{
+		{"exact multiple", "0.05", "0.001", "0.05"},
+		{"round down", "0.05678", "0.001", "0.056"},
+		{"below step", "0.0004", "0.001", "0"},
+		{"integer step", "12.9", "1", "12"},
+		{"zero step (noop)", "0.05678", "0", "0.05678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustMoney(tt.value).FloorToStep(MustMoney(tt.step))
+			if !got.EQ(MustMoney(tt.want)) {
+				t.Errorf("FloorToStep(%s, %s) = %s, want %s", tt.value, tt.step, got, tt.want)
+			}
+		})
+	}
+}
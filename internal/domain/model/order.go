@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // OrderSide 订单方向
 type OrderSide int
@@ -21,14 +24,30 @@ func (s OrderSide) String() string {
 	}
 }
 
+// ParseOrderSide 解析订单方向（不区分大小写，未知返回 0）
+func ParseOrderSide(s string) OrderSide {
+	switch strings.ToUpper(s) {
+	case "BUY":
+		return OrderSideBuy
+	case "SELL":
+		return OrderSideSell
+	default:
+		return 0
+	}
+}
+
 // OrderType 订单类型
 type OrderType int
 
 const (
 	OrderTypeLimit OrderType = iota + 1
 	OrderTypeMarket
-	OrderTypeIOC // Immediate-Or-Cancel
-	OrderTypeFOK // Fill-Or-Kill
+	OrderTypeIOC             // Immediate-Or-Cancel
+	OrderTypeFOK             // Fill-Or-Kill
+	OrderTypeStopMarket      // 止损市价单：价格触及触发价后按市价成交
+	OrderTypeStopLimit       // 止损限价单：价格触及触发价后按限价挂单
+	OrderTypeTakeProfit      // 止盈市价单：价格触及触发价后按市价成交
+	OrderTypeTakeProfitLimit // 止盈限价单：价格触及触发价后按限价挂单
 )
 
 func (t OrderType) String() string {
@@ -41,11 +60,102 @@ func (t OrderType) String() string {
 		return "IOC"
 	case OrderTypeFOK:
 		return "FOK"
+	case OrderTypeStopMarket:
+		return "STOP_LOSS"
+	case OrderTypeStopLimit:
+		return "STOP_LOSS_LIMIT"
+	case OrderTypeTakeProfit:
+		return "TAKE_PROFIT"
+	case OrderTypeTakeProfitLimit:
+		return "TAKE_PROFIT_LIMIT"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// ParseOrderType 解析订单类型（不区分大小写，未知返回 0）
+// 条件单按 orders.type 的枚举值（与 Binance 一致）解析，LIMIT_MAKER 按限价单处理
+func ParseOrderType(s string) OrderType {
+	switch strings.ToUpper(s) {
+	case "LIMIT", "LIMIT_MAKER":
+		return OrderTypeLimit
+	case "MARKET":
+		return OrderTypeMarket
+	case "IOC":
+		return OrderTypeIOC
+	case "FOK":
+		return OrderTypeFOK
+	case "STOP_LOSS", "STOP_MARKET":
+		return OrderTypeStopMarket
+	case "STOP_LOSS_LIMIT", "STOP_LIMIT":
+		return OrderTypeStopLimit
+	case "TAKE_PROFIT":
+		return OrderTypeTakeProfit
+	case "TAKE_PROFIT_LIMIT":
+		return OrderTypeTakeProfitLimit
+	default:
+		return 0
+	}
+}
+
+// IsConditional 是否为条件单（价格触及触发价后才生效）
+func (t OrderType) IsConditional() bool {
+	switch t {
+	case OrderTypeStopMarket, OrderTypeStopLimit, OrderTypeTakeProfit, OrderTypeTakeProfitLimit:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasLimitPrice 是否需要限价（成交价不劣于 Price）
+func (t OrderType) HasLimitPrice() bool {
+	switch t {
+	case OrderTypeLimit, OrderTypeIOC, OrderTypeFOK, OrderTypeStopLimit, OrderTypeTakeProfitLimit:
+		return true
+	default:
+		return false
+	}
+}
+
+// TriggersAbove 条件单是否在价格涨至触发价时触发（否则在跌至触发价时触发）
+// 止损买入与止盈卖出向上触发，止损卖出与止盈买入向下触发
+func TriggersAbove(t OrderType, side OrderSide) bool {
+	stop := t == OrderTypeStopMarket || t == OrderTypeStopLimit
+	return stop == (side == OrderSideBuy)
+}
+
+// ExpirePolicy 挂单超过最长挂单时长后的处理方式
+type ExpirePolicy int
+
+const (
+	ExpirePolicyCancel  ExpirePolicy = iota + 1 // 撤单
+	ExpirePolicyReprice                         // 按最新价改单重新挂出（仅限价单，达到改价次数上限后撤单）
+)
+
+func (p ExpirePolicy) String() string {
+	switch p {
+	case ExpirePolicyCancel:
+		return "CANCEL"
+	case ExpirePolicyReprice:
+		return "REPRICE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseExpirePolicy 解析挂单超时处理方式（不区分大小写，未知返回 0）
+func ParseExpirePolicy(s string) ExpirePolicy {
+	switch strings.ToUpper(s) {
+	case "CANCEL":
+		return ExpirePolicyCancel
+	case "REPRICE":
+		return ExpirePolicyReprice
+	default:
+		return 0
+	}
+}
+
 // OrderStatus 订单状态
 type OrderStatus int
 
@@ -56,6 +166,7 @@ const (
 	OrderStatusFilled
 	OrderStatusCancelled
 	OrderStatusRejected
+	OrderStatusUnknown // 下单结果未知（网关超时），待向交易所查询确认
 )
 
 func (s OrderStatus) String() string {
@@ -72,6 +183,8 @@ func (s OrderStatus) String() string {
 		return "CANCELLED"
 	case OrderStatusRejected:
 		return "REJECTED"
+	case OrderStatusUnknown:
+		return "UNKNOWN"
 	default:
 		return "UNKNOWN"
 	}
@@ -96,6 +209,18 @@ func (m MarketType) String() string {
 	}
 }
 
+// ParseMarketType 解析市场类型（不区分大小写，未知返回 0）
+func ParseMarketType(s string) MarketType {
+	switch strings.ToUpper(s) {
+	case "SPOT":
+		return MarketTypeSpot
+	case "FUTURE":
+		return MarketTypeFuture
+	default:
+		return 0
+	}
+}
+
 // Order 订单领域模型
 type Order struct {
 	// 唯一标识
@@ -109,12 +234,15 @@ type Order struct {
 	Type       OrderType
 
 	// 价格与数量
-	Price    Money // 限价单价格（市价单为零）
-	Quantity Money // 下单数量
-	Filled   Money // 已成交数量
+	Price     Money // 限价单价格（市价单为零）
+	StopPrice Money // 条件单触发价（非条件单为零）
+	Quantity  Money // 下单数量
+	Filled    Money // 已成交数量
 
-	// 状态
-	Status OrderStatus
+	// 状态（经 ApplyUpdate 按状态机转换）
+	Status             OrderStatus
+	Sequence           int64     // 最近应用的交易所更新序号（用于丢弃乱序回报）
+	ExchangeUpdateTime time.Time // 最近应用的交易所更新时间
 
 	// 时间
 	CreatedAt  time.Time
@@ -128,6 +256,15 @@ type Order struct {
 
 	// 保护价（风控用）
 	ProtectPrice Money // 最差成交价格
+
+	// 挂单时效（交易所侧为 GTC，由 OMS 同步时执行）
+	ExpireAt     time.Time     // 截止时间（good-till-time，到期撤单，零值表示不限）
+	MaxAge       time.Duration // 最长挂单时长（自创建起算，零值表示不限）
+	ExpirePolicy ExpirePolicy  // 超过最长挂单时长后的处理方式（零值按撤单处理）
+	Reprices     int           // 已按 ExpirePolicyReprice 改价的次数（改单后的新订单继承）
+
+	// 归属
+	StrategyID string // 下单策略（持久化到 orders.strategy_id，手动下单为空）
 }
 
 // IsFilled 是否完全成交
@@ -139,7 +276,8 @@ func (o *Order) IsFilled() bool {
 func (o *Order) IsActive() bool {
 	return o.Status == OrderStatusPending ||
 		o.Status == OrderStatusSubmitted ||
-		o.Status == OrderStatusPartialFilled
+		o.Status == OrderStatusPartialFilled ||
+		o.Status == OrderStatusUnknown
 }
 
 // IsClosed 是否已关闭
@@ -161,3 +299,24 @@ func (o *Order) FilledPercent() Money {
 func (o *Order) RemainingQty() Money {
 	return o.Quantity.Sub(o.Filled)
 }
+
+// PastDeadline 是否已过截止时间（未设置截止时间时恒为 false）
+func (o *Order) PastDeadline(now time.Time) bool {
+	return !o.ExpireAt.IsZero() && !now.Before(o.ExpireAt)
+}
+
+// Stale 挂单时长是否已超过最长挂单时长（未设置时恒为 false）
+func (o *Order) Stale(now time.Time) bool {
+	return o.MaxAge > 0 && now.Sub(o.CreatedAt) >= o.MaxAge
+}
+
+// Triggered 条件单在给定价格下是否触发（非条件单恒为 false）
+func (o *Order) Triggered(price Money) bool {
+	if !o.Type.IsConditional() || o.StopPrice.IsZero() {
+		return false
+	}
+	if TriggersAbove(o.Type, o.Side) {
+		return price.GE(o.StopPrice)
+	}
+	return price.LE(o.StopPrice)
+}
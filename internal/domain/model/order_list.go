@@ -0,0 +1,128 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// OrderListType 订单组类型
+type OrderListType int
+
+const (
+	OrderListTypeOCO     OrderListType = iota + 1 // 止盈与止损二选一：一方成交后撤销另一方
+	OrderListTypeBracket                          // 括号单：入场单成交后挂出止盈止损 OCO
+)
+
+func (t OrderListType) String() string {
+	switch t {
+	case OrderListTypeOCO:
+		return "OCO"
+	case OrderListTypeBracket:
+		return "BRACKET"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseOrderListType 解析订单组类型（不区分大小写，未知返回 0）
+func ParseOrderListType(s string) OrderListType {
+	switch strings.ToUpper(s) {
+	case "OCO":
+		return OrderListTypeOCO
+	case "BRACKET":
+		return OrderListTypeBracket
+	default:
+		return 0
+	}
+}
+
+// OrderListStatus 订单组状态
+type OrderListStatus int
+
+const (
+	OrderListStatusPending   OrderListStatus = iota + 1 // 括号单等待入场单成交
+	OrderListStatusActive                               // 止盈止损已挂出
+	OrderListStatusDone                                 // 止盈或止损已成交（另一方已撤销）
+	OrderListStatusCancelled                            // 已撤销（入场单未成交、挂出失败或手动撤销）
+)
+
+func (s OrderListStatus) String() string {
+	switch s {
+	case OrderListStatusPending:
+		return "PENDING"
+	case OrderListStatusActive:
+		return "ACTIVE"
+	case OrderListStatusDone:
+		return "DONE"
+	case OrderListStatusCancelled:
+		return "CANCELLED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseOrderListStatus 解析订单组状态（不区分大小写，未知返回 0）
+func ParseOrderListStatus(s string) OrderListStatus {
+	switch strings.ToUpper(s) {
+	case "PENDING":
+		return OrderListStatusPending
+	case "ACTIVE":
+		return OrderListStatusActive
+	case "DONE":
+		return OrderListStatusDone
+	case "CANCELLED":
+		return OrderListStatusCancelled
+	default:
+		return 0
+	}
+}
+
+// OrderList 订单组（OCO / 括号单）
+// 父子关系：括号单的入场单与止盈、止损腿均按 ClientOrderID 关联到 orders 表
+// 交易所支持原生 OCO 时两条腿由交易所挂出并互相撤销（Native），否则由 OMS 本地模拟：
+// 止盈腿为挂出的限价单，止损腿在价格触及触发价时才下单，并先撤销止盈腿
+type OrderList struct {
+	ListID     string // 订单组客户端 ID（原生 OCO 时即交易所 listClientOrderId）
+	Type       OrderListType
+	Status     OrderListStatus
+	Symbol     string
+	AccountID  string
+	StrategyID string
+
+	// 括号单入场单（OCO 为空）
+	EntryClientOrderID string
+
+	// 止盈止损（方向与入场方向相反）
+	ExitSide        OrderSide
+	Quantity        Money // 止盈止损数量（括号单为入场单成交数量）
+	TakeProfitPrice Money // 止盈限价
+	StopPrice       Money // 止损触发价
+	StopLimitPrice  Money // 止损触发后的限价（零值表示市价止损）
+
+	// 子订单
+	TakeProfitClientOrderID string
+	StopLossClientOrderID   string // 本地模拟时为预生成的 ID，触发后才下单
+
+	ExchangeListID string // 交易所订单组 ID（原生 OCO）
+	Native         bool   // 是否由交易所原生执行
+	Reason         string // 完结原因
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsActive 订单组是否未完结
+func (l *OrderList) IsActive() bool {
+	return l.Status == OrderListStatusPending || l.Status == OrderListStatusActive
+}
+
+// StopTriggered 价格是否触及止损触发价（平多为卖出，价格跌破触发价；平空为买入，价格涨破触发价）
+func (l *OrderList) StopTriggered(price Money) bool {
+	if l.StopPrice.IsZero() || price.IsZero() {
+		return false
+	}
+	if l.ExitSide == OrderSideSell {
+		return price.LE(l.StopPrice)
+	}
+	return price.GE(l.StopPrice)
+}
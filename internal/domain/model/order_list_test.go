@@ -0,0 +1,43 @@
+package model
+
+import "testing"
+
+func TestOrderList_StopTriggered(t *testing.T) {
+	tests := []struct {
+		name  string
+		side  OrderSide
+		price string
+		want  bool
+	}{
+		{"long above stop", OrderSideSell, "49500", false},
+		{"long at stop", OrderSideSell, "49000", true},
+		{"long gap below stop", OrderSideSell, "48000", true},
+		{"short below stop", OrderSideBuy, "50500", false},
+		{"short gap above stop", OrderSideBuy, "52000", true},
+		{"no price", OrderSideSell, "0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop := "49000"
+			if tt.side == OrderSideBuy {
+				stop = "51000"
+			}
+			l := &OrderList{ExitSide: tt.side, StopPrice: MustMoney(stop)}
+			if got := l.StopTriggered(MustMoney(tt.price)); got != tt.want {
+				t.Errorf("StopTriggered(%s) = %v, want %v", tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOrderListStatus(t *testing.T) {
+	for _, s := range []OrderListStatus{OrderListStatusPending, OrderListStatusActive, OrderListStatusDone, OrderListStatusCancelled} {
+		if got := ParseOrderListStatus(s.String()); got != s {
+			t.Errorf("ParseOrderListStatus(%s) = %v", s, got)
+		}
+	}
+	if got := ParseOrderListType("bracket"); got != OrderListTypeBracket {
+		t.Errorf("ParseOrderListType(bracket) = %v", got)
+	}
+}
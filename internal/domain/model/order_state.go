@@ -0,0 +1,196 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidOrderTransition 非法的订单状态转换（如已成交订单回退为已提交）
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+
+	// ErrStaleOrderUpdate 过期的订单更新（序号或更新时间早于已应用的更新，乱序到达时忽略）
+	ErrStaleOrderUpdate = errors.New("stale order update")
+)
+
+// 订单事件来源
+const (
+	OrderEventSourceCreate    = "CREATE"    // 订单创建（首次保存，OMS 下单时为调用网关前写入的下单意图）
+	OrderEventSourceSubmit    = "SUBMIT"    // 网关下单返回（确认、拒绝或超时未知）
+	OrderEventSourceCancel    = "CANCEL"    // 本地撤单
+	OrderEventSourceAmend     = "AMEND"     // 改单（原订单撤销并由新订单替换）
+	OrderEventSourceSync      = "SYNC"      // 轮询同步交易所状态
+	OrderEventSourceExchange  = "EXCHANGE"  // 交易所推送
+	OrderEventSourceReconcile = "RECONCILE" // 启动或手动对账
+)
+
+// orderTransitions 合法的状态转换（终态不可再转换）
+// PENDING -> SUBMITTED -> PARTIAL_FILLED -> FILLED
+// PENDING -> UNKNOWN（网关超时）-> 按交易所查询结果转换（查无此单视为拒绝）
+// 未完结的订单均可被撤销；未成交的订单可被拒绝
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending: {
+		OrderStatusSubmitted, OrderStatusPartialFilled, OrderStatusFilled,
+		OrderStatusCancelled, OrderStatusRejected, OrderStatusUnknown,
+	},
+	OrderStatusUnknown: {
+		OrderStatusSubmitted, OrderStatusPartialFilled, OrderStatusFilled,
+		OrderStatusCancelled, OrderStatusRejected,
+	},
+	OrderStatusSubmitted: {
+		OrderStatusPartialFilled, OrderStatusFilled,
+		OrderStatusCancelled, OrderStatusRejected,
+	},
+	OrderStatusPartialFilled: {
+		OrderStatusPartialFilled, OrderStatusFilled, OrderStatusCancelled,
+	},
+}
+
+// CanTransitionTo 是否允许从当前状态转换到 next
+// PARTIAL_FILLED -> PARTIAL_FILLED 表示继续部分成交
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal 是否为终态
+func (s OrderStatus) IsTerminal() bool {
+	return s == OrderStatusFilled || s == OrderStatusCancelled || s == OrderStatusRejected
+}
+
+// IsUnconfirmed 是否为交易所尚未确认的下单意图（已写入本地但网关未返回或超时）
+func (s OrderStatus) IsUnconfirmed() bool {
+	return s == OrderStatusPending || s == OrderStatusUnknown
+}
+
+// ParseOrderStatus 解析订单状态（未知返回 0）
+func ParseOrderStatus(s string) OrderStatus {
+	switch s {
+	case "PENDING":
+		return OrderStatusPending
+	case "SUBMITTED":
+		return OrderStatusSubmitted
+	case "PARTIAL_FILLED":
+		return OrderStatusPartialFilled
+	case "FILLED":
+		return OrderStatusFilled
+	case "CANCELLED":
+		return OrderStatusCancelled
+	case "REJECTED":
+		return OrderStatusRejected
+	case "UNKNOWN":
+		return OrderStatusUnknown
+	default:
+		return 0
+	}
+}
+
+// OrderUpdate 订单状态更新（本地操作或交易所回报）
+type OrderUpdate struct {
+	Status     OrderStatus
+	ExchangeID string    // 交易所订单 ID（为空表示不变，下单确认时写入）
+	Filled     Money     // 累计成交数量（零值表示不变）
+	Sequence   int64     // 交易所更新序号（0 表示无序号，按 UpdateTime 判断先后）
+	UpdateTime time.Time // 交易所更新时间（零值表示未知，不做乱序判断）
+	Source     string    // 事件来源（OrderEventSource*）
+	Reason     string    // 说明（如撤单原因、拒单原因）
+}
+
+// OrderEvent 订单状态转换事件（追加写入 order_events，按 ID 顺序可重放订单完整历史）
+type OrderEvent struct {
+	ID            int64
+	ClientOrderID string
+	FromStatus    OrderStatus // 0 表示订单创建
+	ToStatus      OrderStatus
+	Filled        Money // 转换后的累计成交数量
+	Sequence      int64
+	ExchangeTime  time.Time
+	Source        string
+	Reason        string
+	CreatedAt     time.Time
+}
+
+// NewOrderCreatedEvent 订单创建事件
+func NewOrderCreatedEvent(order *Order, source string, now time.Time) *OrderEvent {
+	return &OrderEvent{
+		ClientOrderID: order.ClientOrderID,
+		ToStatus:      order.Status,
+		Filled:        order.Filled,
+		Sequence:      order.Sequence,
+		ExchangeTime:  order.ExchangeUpdateTime,
+		Source:        source,
+		CreatedAt:     now,
+	}
+}
+
+// ApplyUpdate 按状态机应用更新，返回状态转换事件
+// 序号或更新时间早于已应用的更新时返回 ErrStaleOrderUpdate；非法转换返回 ErrInvalidOrderTransition；
+// 状态与成交数量均未变化时返回 nil 事件（仅推进序号与更新时间）
+func (o *Order) ApplyUpdate(update OrderUpdate, now time.Time) (*OrderEvent, error) {
+	if o.isStale(update) {
+		return nil, fmt.Errorf("%w: %s seq=%d time=%s", ErrStaleOrderUpdate,
+			o.ClientOrderID, update.Sequence, update.UpdateTime.Format(time.RFC3339Nano))
+	}
+
+	filled := o.Filled
+	if !update.Filled.IsZero() {
+		filled = update.Filled
+	}
+	if filled.LT(o.Filled) {
+		return nil, fmt.Errorf("%w: %s filled %s < %s", ErrStaleOrderUpdate, o.ClientOrderID, filled, o.Filled)
+	}
+
+	changed := update.Status != o.Status || !filled.EQ(o.Filled)
+	if changed && !o.Status.CanTransitionTo(update.Status) {
+		return nil, fmt.Errorf("%w: %s %s -> %s", ErrInvalidOrderTransition, o.ClientOrderID, o.Status, update.Status)
+	}
+
+	if update.Sequence > o.Sequence {
+		o.Sequence = update.Sequence
+	}
+	if update.UpdateTime.After(o.ExchangeUpdateTime) {
+		o.ExchangeUpdateTime = update.UpdateTime
+	}
+	if update.ExchangeID != "" {
+		o.ExchangeID = update.ExchangeID
+	}
+	if !changed {
+		return nil, nil
+	}
+
+	event := &OrderEvent{
+		ClientOrderID: o.ClientOrderID,
+		FromStatus:    o.Status,
+		ToStatus:      update.Status,
+		Filled:        filled,
+		Sequence:      update.Sequence,
+		ExchangeTime:  update.UpdateTime,
+		Source:        update.Source,
+		Reason:        update.Reason,
+		CreatedAt:     now,
+	}
+
+	o.Status = update.Status
+	o.Filled = filled
+	o.UpdatedAt = now
+	if o.Status == OrderStatusFilled && o.FillTime.IsZero() {
+		o.FillTime = now
+	}
+	return event, nil
+}
+
+// isStale 更新是否早于已应用的更新（优先比较序号，均无序号时比较更新时间）
+func (o *Order) isStale(update OrderUpdate) bool {
+	if update.Sequence > 0 && o.Sequence > 0 {
+		return update.Sequence < o.Sequence
+	}
+	if !update.UpdateTime.IsZero() && !o.ExchangeUpdateTime.IsZero() {
+		return update.UpdateTime.Before(o.ExchangeUpdateTime)
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+		want     bool
+	}{
+		{OrderStatusPending, OrderStatusSubmitted, true},
+		{OrderStatusSubmitted, OrderStatusPartialFilled, true},
+		{OrderStatusPartialFilled, OrderStatusPartialFilled, true},
+		{OrderStatusPartialFilled, OrderStatusFilled, true},
+		{OrderStatusSubmitted, OrderStatusCancelled, true},
+		{OrderStatusPartialFilled, OrderStatusRejected, false},
+		{OrderStatusSubmitted, OrderStatusPending, false},
+		{OrderStatusFilled, OrderStatusSubmitted, false},
+		{OrderStatusFilled, OrderStatusCancelled, false},
+		{OrderStatusCancelled, OrderStatusFilled, false},
+		{OrderStatusPending, OrderStatusUnknown, true},
+		{OrderStatusUnknown, OrderStatusFilled, true},
+		{OrderStatusUnknown, OrderStatusRejected, true},
+		{OrderStatusSubmitted, OrderStatusUnknown, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from.String()+"->"+tt.to.String(), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("CanTransitionTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrder_ApplyUpdate(t *testing.T) {
+	now := time.Now()
+	order := &Order{
+		ClientOrderID: "order-1",
+		Quantity:      MustMoney("1"),
+		Filled:        Zero(),
+		Status:        OrderStatusSubmitted,
+	}
+
+	// 部分成交
+	event, err := order.ApplyUpdate(OrderUpdate{
+		Status:     OrderStatusPartialFilled,
+		Filled:     MustMoney("0.4"),
+		Sequence:   2,
+		UpdateTime: now,
+		Source:     OrderEventSourceExchange,
+	}, now)
+	if err != nil {
+		t.Fatalf("ApplyUpdate failed: %v", err)
+	}
+	if event.FromStatus != OrderStatusSubmitted || event.ToStatus != OrderStatusPartialFilled || !event.Filled.EQ(MustMoney("0.4")) {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	// 序号更早的回报乱序到达：忽略
+	_, err = order.ApplyUpdate(OrderUpdate{Status: OrderStatusSubmitted, Sequence: 1}, now)
+	if !errors.Is(err, ErrStaleOrderUpdate) {
+		t.Fatalf("expected ErrStaleOrderUpdate, got %v", err)
+	}
+
+	// 重复回报：无事件
+	if event, err := order.ApplyUpdate(OrderUpdate{Status: OrderStatusPartialFilled, Sequence: 2}, now); err != nil || event != nil {
+		t.Fatalf("expected no-op for duplicate update, got %+v, %v", event, err)
+	}
+
+	// 完全成交
+	if _, err := order.ApplyUpdate(OrderUpdate{Status: OrderStatusFilled, Filled: MustMoney("1"), Sequence: 3}, now); err != nil {
+		t.Fatalf("ApplyUpdate failed: %v", err)
+	}
+	if order.FillTime.IsZero() || order.Sequence != 3 {
+		t.Errorf("expected fill time and sequence set, got %+v", order)
+	}
+
+	// 终态不可回退（无序号与时间的本地更新）
+	_, err = order.ApplyUpdate(OrderUpdate{Status: OrderStatusCancelled}, now)
+	if !errors.Is(err, ErrInvalidOrderTransition) {
+		t.Fatalf("expected ErrInvalidOrderTransition, got %v", err)
+	}
+	if order.Status != OrderStatusFilled {
+		t.Errorf("status changed to %s after rejected update", order.Status)
+	}
+}
+
+func TestOrder_ApplyUpdateByTime(t *testing.T) {
+	now := time.Now()
+	order := &Order{
+		ClientOrderID:      "order-2",
+		Filled:             MustMoney("1"),
+		Status:             OrderStatusFilled,
+		ExchangeUpdateTime: now,
+	}
+
+	// 无序号时按更新时间判断：更早的轮询结果视为过期
+	_, err := order.ApplyUpdate(OrderUpdate{Status: OrderStatusSubmitted, UpdateTime: now.Add(-time.Second)}, now)
+	if !errors.Is(err, ErrStaleOrderUpdate) {
+		t.Fatalf("expected ErrStaleOrderUpdate, got %v", err)
+	}
+
+	// 成交数量不回退
+	_, err = order.ApplyUpdate(OrderUpdate{Status: OrderStatusFilled, Filled: MustMoney("0.5")}, now)
+	if !errors.Is(err, ErrStaleOrderUpdate) {
+		t.Fatalf("expected ErrStaleOrderUpdate for decreasing fill, got %v", err)
+	}
+}
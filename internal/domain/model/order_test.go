@@ -160,6 +160,10 @@ func TestOrderType_String(t *testing.T) {
 		{OrderTypeMarket, "MARKET"},
 		{OrderTypeIOC, "IOC"},
 		{OrderTypeFOK, "FOK"},
+		{OrderTypeStopMarket, "STOP_LOSS"},
+		{OrderTypeStopLimit, "STOP_LOSS_LIMIT"},
+		{OrderTypeTakeProfit, "TAKE_PROFIT"},
+		{OrderTypeTakeProfitLimit, "TAKE_PROFIT_LIMIT"},
 		{OrderType(99), "UNKNOWN"},
 	}
 
@@ -172,6 +176,54 @@ func TestOrderType_String(t *testing.T) {
 	}
 }
 
+func TestOrder_Triggered(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   OrderType
+		side  OrderSide
+		price string
+		want  bool
+	}{
+		{"sell stop above trigger", OrderTypeStopMarket, OrderSideSell, "49500", false},
+		{"sell stop at trigger", OrderTypeStopMarket, OrderSideSell, "49000", true},
+		{"buy stop below trigger", OrderTypeStopLimit, OrderSideBuy, "48000", false},
+		{"buy stop above trigger", OrderTypeStopLimit, OrderSideBuy, "49100", true},
+		{"sell take profit above trigger", OrderTypeTakeProfit, OrderSideSell, "49100", true},
+		{"sell take profit below trigger", OrderTypeTakeProfitLimit, OrderSideSell, "48900", false},
+		{"buy take profit below trigger", OrderTypeTakeProfit, OrderSideBuy, "48900", true},
+		{"limit never triggers", OrderTypeLimit, OrderSideSell, "1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{Type: tt.typ, Side: tt.side, StopPrice: MustMoney("49000")}
+			if got := o.Triggered(MustMoney(tt.price)); got != tt.want {
+				t.Errorf("Triggered(%s) = %v, want %v", tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrder_Expiry(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := &Order{CreatedAt: created}
+	if o.PastDeadline(created.Add(time.Hour)) || o.Stale(created.Add(time.Hour)) {
+		t.Fatal("order without expiry settings should never expire")
+	}
+
+	o.ExpireAt = created.Add(10 * time.Minute)
+	o.MaxAge = time.Minute
+	if o.PastDeadline(created.Add(9*time.Minute)) || o.Stale(created.Add(59*time.Second)) {
+		t.Error("order should not expire early")
+	}
+	if !o.PastDeadline(o.ExpireAt) {
+		t.Error("order should be past deadline at ExpireAt")
+	}
+	if !o.Stale(created.Add(time.Minute)) {
+		t.Error("order should be stale after MaxAge")
+	}
+}
+
 func TestOrderStatus_String(t *testing.T) {
 	tests := []struct {
 		status OrderStatus
@@ -214,6 +266,31 @@ func TestMarketType_String(t *testing.T) {
 	}
 }
 
+func TestParseOrderEnums(t *testing.T) {
+	if got := ParseOrderSide("sell"); got != OrderSideSell {
+		t.Errorf("ParseOrderSide(sell) = %v", got)
+	}
+	if got := ParseOrderType("MARKET"); got != OrderTypeMarket {
+		t.Errorf("ParseOrderType(MARKET) = %v", got)
+	}
+	for _, typ := range []OrderType{OrderTypeStopMarket, OrderTypeStopLimit, OrderTypeTakeProfit, OrderTypeTakeProfitLimit} {
+		if got := ParseOrderType(typ.String()); got != typ {
+			t.Errorf("ParseOrderType(%s) = %v", typ, got)
+		}
+	}
+	for _, policy := range []ExpirePolicy{ExpirePolicyCancel, ExpirePolicyReprice} {
+		if got := ParseExpirePolicy(policy.String()); got != policy {
+			t.Errorf("ParseExpirePolicy(%s) = %v", policy, got)
+		}
+	}
+	if got := ParseMarketType("future"); got != MarketTypeFuture {
+		t.Errorf("ParseMarketType(future) = %v", got)
+	}
+	if ParseOrderSide("HOLD") != 0 || ParseOrderType("") != 0 || ParseMarketType("OPTION") != 0 || ParseExpirePolicy("KEEP") != 0 {
+		t.Error("expected 0 for unknown values")
+	}
+}
+
 func TestOrder_CompleteFlow(t *testing.T) {
 	// 模拟完整订单流程
 	order := &Order{
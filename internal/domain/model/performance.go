@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// Settlement 已平仓交易结算（对应 settlements 表）
+type Settlement struct {
+	TradeID    string
+	StrategyID string // 存储于 metadata.strategy_id
+	Symbol     string
+	Side       OrderSide // 持仓方向：Buy 为多头，Sell 为空头
+
+	RealizedPnL Money // 已实现净盈亏（扣费后）
+	Commission  Money // 手续费
+	EntryPrice  Money
+	ExitPrice   Money
+	Quantity    Money
+	RiskAmount  Money // 开仓时的初始风险金额（1R，存储于 metadata.risk_amount，零表示未知）
+
+	OpenedAt time.Time
+	ClosedAt time.Time
+}
+
+// IsWin 是否盈利
+func (s *Settlement) IsWin() bool {
+	return s.RealizedPnL.IsPositive()
+}
+
+// RMultiple 以初始风险计量的盈亏倍数（初始风险未知时返回 false）
+func (s *Settlement) RMultiple() (float64, bool) {
+	if !s.RiskAmount.IsPositive() {
+		return 0, false
+	}
+	return s.RealizedPnL.Div(s.RiskAmount).Float64(), true
+}
+
+// StrategyRiskMode 策略风险模式（见 RISK_PROTOCOL 7.2 动态风险降级）
+type StrategyRiskMode int
+
+const (
+	// StrategyRiskModeNormal 正常仓位
+	StrategyRiskModeNormal StrategyRiskMode = iota
+	// StrategyRiskModeHalfSize 减半模式
+	StrategyRiskModeHalfSize
+	// StrategyRiskModePaused 冷静期（暂停开仓）
+	StrategyRiskModePaused
+)
+
+func (m StrategyRiskMode) String() string {
+	switch m {
+	case StrategyRiskModeNormal:
+		return "NORMAL"
+	case StrategyRiskModeHalfSize:
+		return "HALF_SIZE"
+	case StrategyRiskModePaused:
+		return "PAUSED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// StrategyPerformance 策略滚动绩效快照
+type StrategyPerformance struct {
+	StrategyID string
+	Symbol     string // 最近一笔交易的标的
+
+	Trades            int     // 统计窗口内的交易笔数
+	WinRate           float64 // 胜率
+	AvgWin            Money   // 平均盈利
+	AvgLoss           Money   // 平均亏损（正数）
+	AvgCommission     Money   // 平均手续费
+	Expectancy        Money   // 期望值：WinRate*AvgWin - LossRate*AvgLoss（净盈亏口径）
+	PayoffRatio       float64 // 盈亏比：AvgWin / AvgLoss
+	AvgRMultiple      float64 // 平均 R 倍数（仅统计已知初始风险的交易）
+	ConsecutiveLosses int     // 最近连续亏损次数
+
+	Mode           StrategyRiskMode
+	SizeMultiplier float64   // 仓位系数（正常 1.0，减半 0.5，暂停 0）
+	PausedUntil    time.Time // 冷静期结束时间
+	Reason         string    // 降级原因
+}
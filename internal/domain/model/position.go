@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// CostMethod 持仓成本计算方法
+type CostMethod int
+
+const (
+	CostMethodWeightedAverage CostMethod = iota + 1 // 加权平均：加仓时按数量加权合并成本
+	CostMethodFIFO                                  // 先进先出：减仓时按开仓顺序消耗批次
+)
+
+func (m CostMethod) String() string {
+	switch m {
+	case CostMethodWeightedAverage:
+		return "WEIGHTED_AVERAGE"
+	case CostMethodFIFO:
+		return "FIFO"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseCostMethod 解析持仓成本计算方法（不区分大小写，未知返回 0）
+func ParseCostMethod(s string) CostMethod {
+	switch strings.ToUpper(s) {
+	case "WEIGHTED_AVERAGE", "AVERAGE":
+		return CostMethodWeightedAverage
+	case "FIFO":
+		return CostMethodFIFO
+	default:
+		return 0
+	}
+}
+
+// PositionLot 持仓批次（一笔开仓成交的剩余部分）
+type PositionLot struct {
+	Quantity Money // 剩余数量（正数）
+	Price    Money // 开仓价
+	OpenedAt time.Time
+}
+
+// Position 持仓（按策略与标的记账，由成交驱动）
+type Position struct {
+	AccountID  string
+	StrategyID string // 为空表示人工或无法归属策略的成交
+	Symbol     string
+
+	Quantity    Money // 净持仓（正数为多头，负数为空头）
+	AvgCost     Money // 持仓均价（FIFO 为剩余批次的加权均价，空仓为零）
+	RealizedPnL Money // 累计已实现净盈亏（扣除开仓与平仓手续费）
+	Fees        Money // 累计手续费（折算为计价资产）
+	MarkPrice   Money // 最新标记价格（用于未实现盈亏，零表示尚未标记）
+
+	OpenedAt  time.Time // 当前持仓的开仓时间（空仓为零值）
+	UpdatedAt time.Time
+}
+
+// IsFlat 是否空仓
+func (p Position) IsFlat() bool {
+	return p.Quantity.IsZero()
+}
+
+// IsLong 是否为多头（空仓返回 false）
+func (p Position) IsLong() bool {
+	return p.Quantity.IsPositive()
+}
+
+// UnrealizedPnL 未实现盈亏（按标记价格计算，未标记或空仓时为零，不含平仓手续费）
+func (p Position) UnrealizedPnL() Money {
+	if p.IsFlat() || p.MarkPrice.IsZero() {
+		return Zero()
+	}
+	return p.MarkPrice.Sub(p.AvgCost).Mul(p.Quantity)
+}
+
+// Notional 持仓名义价值（按标记价格，未标记时按持仓均价）
+func (p Position) Notional() Money {
+	price := p.MarkPrice
+	if price.IsZero() {
+		price = p.AvgCost
+	}
+	return p.Quantity.Abs().Mul(price)
+}
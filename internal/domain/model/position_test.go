@@ -0,0 +1,58 @@
+package model
+
+import "testing"
+
+func TestParseCostMethod(t *testing.T) {
+	for _, m := range []CostMethod{CostMethodWeightedAverage, CostMethodFIFO} {
+		if got := ParseCostMethod(m.String()); got != m {
+			t.Errorf("ParseCostMethod(%s) = %v", m, got)
+		}
+	}
+	if got := ParseCostMethod("fifo"); got != CostMethodFIFO {
+		t.Errorf("ParseCostMethod is case insensitive, got %v", got)
+	}
+	if got := ParseCostMethod("LIFO"); got != 0 {
+		t.Errorf("unknown method should parse to 0, got %v", got)
+	}
+}
+
+func TestPosition_UnrealizedPnL(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity string
+		avgCost  string
+		mark     string
+		want     string
+	}{
+		{"多头盈利", "0.5", "50000", "52000", "1000"},
+		{"多头亏损", "0.5", "50000", "49000", "-500"},
+		{"空头盈利", "-0.5", "50000", "49000", "500"},
+		{"空头亏损", "-0.5", "50000", "52000", "-1000"},
+		{"未标记", "0.5", "50000", "0", "0"},
+		{"空仓", "0", "0", "52000", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Position{
+				Quantity:  MustMoney(tt.quantity),
+				AvgCost:   MustMoney(tt.avgCost),
+				MarkPrice: MustMoney(tt.mark),
+			}
+			if got := p.UnrealizedPnL(); !got.EQ(MustMoney(tt.want)) {
+				t.Errorf("UnrealizedPnL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosition_Notional(t *testing.T) {
+	p := &Position{Quantity: MustMoney("-0.5"), AvgCost: MustMoney("50000")}
+	if got := p.Notional(); !got.EQ(MustMoney("25000")) {
+		t.Errorf("Notional() without mark = %s, want 25000", got)
+	}
+	p.MarkPrice = MustMoney("52000")
+	if got := p.Notional(); !got.EQ(MustMoney("26000")) {
+		t.Errorf("Notional() = %s, want 26000", got)
+	}
+}
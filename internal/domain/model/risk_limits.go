@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// RiskLimitChange 单项风控限额变更
+type RiskLimitChange struct {
+	Field    string  `json:"field"`    // 参数名（snake_case，如 max_leverage）
+	Old      float64 `json:"old"`      // 变更前
+	New      float64 `json:"new"`      // 变更后
+	Loosened bool    `json:"loosened"` // 是否放宽
+}
+
+// RiskLimitVersion 风控限额版本（每次运行时变更生成一个新版本）
+type RiskLimitVersion struct {
+	Version   int64
+	Limits    map[string]float64 // 变更后的完整限额（key 同规则参数名）
+	Changes   []RiskLimitChange  // 相对上一版本的变更
+	ChangedBy int64              // 操作人（用户 ID）
+	Verified  bool               // 是否通过提级认证（放宽限额时必须）
+	Reason    string             // 变更原因
+	CreatedAt time.Time
+}
+
+// Loosened 本次变更是否包含放宽
+func (v *RiskLimitVersion) Loosened() bool {
+	for _, c := range v.Changes {
+		if c.Loosened {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 风控记录级别（对应 risk_records.level）
+const (
+	RiskRecordLevelInfo    = "INFO"    // 抽样记录的放行决策
+	RiskRecordLevelWarning = "WARNING" // 降档
+	RiskRecordLevelBlock   = "BLOCK"   // 拦截
+)
+
+// RiskRecord 风控决策记录（对应 risk_records 表）
+type RiskRecord struct {
+	ID        int64
+	EventType string // 触发规则（如 PositionLimit:SinglePosition，放行时为 ALLOW）
+	Level     string // INFO / WARNING / BLOCK
+
+	Decision          string // ALLOW / BLOCK / REDUCE
+	Reason            string
+	SuggestedQuantity string // 降档建议数量
+	SuggestedLeverage int    // 降档建议杠杆
+
+	AccountID  string
+	Symbol     string
+	StrategyID string
+
+	Order json.RawMessage // 风控检查时的完整订单上下文
+	State json.RawMessage // 风控检查时的 RiskState 快照
+
+	TriggeredAt time.Time
+}
+
+// RiskRecordFilter 风控记录查询条件（零值字段不参与过滤）
+type RiskRecordFilter struct {
+	Rule       string // 规则名称，匹配完整名称或前缀（如 PositionLimit 匹配 PositionLimit:*）
+	Decision   string
+	Symbol     string
+	StrategyID string
+	From       time.Time
+	To         time.Time
+
+	Offset int
+	Limit  int
+}
@@ -0,0 +1,59 @@
+package model
+
+import "strings"
+
+// RiskRuleScope 风控规则作用域
+type RiskRuleScope int
+
+const (
+	// RiskRuleScopeSystem 系统级（对所有订单生效）
+	RiskRuleScopeSystem RiskRuleScope = iota + 1
+	// RiskRuleScopeAccount 账户级（Target 为空时对规则集所属账户生效）
+	RiskRuleScopeAccount
+	// RiskRuleScopeStrategy 策略级（仅对标记策略的订单生效，Target 限定策略 ID）
+	RiskRuleScopeStrategy
+	// RiskRuleScopeSymbol 标的级（Target 限定交易对）
+	RiskRuleScopeSymbol
+)
+
+func (s RiskRuleScope) String() string {
+	switch s {
+	case RiskRuleScopeSystem:
+		return "SYSTEM"
+	case RiskRuleScopeAccount:
+		return "ACCOUNT"
+	case RiskRuleScopeStrategy:
+		return "STRATEGY"
+	case RiskRuleScopeSymbol:
+		return "SYMBOL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseRiskRuleScope 解析规则作用域（大小写不敏感，未知值返回 0）
+func ParseRiskRuleScope(s string) RiskRuleScope {
+	switch strings.ToUpper(s) {
+	case "SYSTEM":
+		return RiskRuleScopeSystem
+	case "ACCOUNT":
+		return RiskRuleScopeAccount
+	case "STRATEGY":
+		return RiskRuleScopeStrategy
+	case "SYMBOL":
+		return RiskRuleScopeSymbol
+	default:
+		return 0
+	}
+}
+
+// RiskRuleSpec 声明式风控规则配置（来自 YAML 或 strategy_configs 表）
+// Priority / Scope 为零值时使用规则注册时的默认值
+type RiskRuleSpec struct {
+	Name     string             // 注册表中的规则名称（如 position_limit）
+	Priority int                // 优先级（越小越先评估）
+	Scope    RiskRuleScope      // 作用域
+	Target   string             // 作用对象（账户 / 策略 ID / 交易对，空表示不限定）
+	Enabled  bool               // 是否启用
+	Params   map[string]float64 // 覆盖的风控参数（snake_case，如 max_leverage）
+}
@@ -0,0 +1,62 @@
+package model
+
+// RiskScope 风控状态作用域（账户 -> 策略 -> 标的，逐级细化）
+// 每个作用域独立统计盈亏、连续亏损、回撤与熔断，触发熔断时只拦截该作用域内的订单
+//   - 账户：StrategyID、Symbol 均为空
+//   - 策略：StrategyID 非空，Symbol 为空
+//   - 标的：Symbol 非空（StrategyID 为空时为账户下的手动交易）
+type RiskScope struct {
+	AccountID  string
+	StrategyID string
+	Symbol     string
+}
+
+// AccountScope 账户级作用域
+func AccountScope(accountID string) RiskScope {
+	return RiskScope{AccountID: accountID}
+}
+
+// Level 作用域级别
+func (s RiskScope) Level() RiskRuleScope {
+	switch {
+	case s.Symbol != "":
+		return RiskRuleScopeSymbol
+	case s.StrategyID != "":
+		return RiskRuleScopeStrategy
+	default:
+		return RiskRuleScopeAccount
+	}
+}
+
+// Chain 从账户级到当前作用域的全部作用域（由粗到细）
+func (s RiskScope) Chain() []RiskScope {
+	chain := []RiskScope{AccountScope(s.AccountID)}
+	if s.StrategyID != "" {
+		chain = append(chain, RiskScope{AccountID: s.AccountID, StrategyID: s.StrategyID})
+	}
+	if s.Symbol != "" {
+		chain = append(chain, s)
+	}
+	return chain
+}
+
+// Key 存储键（账户 / 账户:标的 的格式与旧版本兼容）
+//   - account
+//   - account:symbol
+//   - account/strategy
+//   - account/strategy:symbol
+func (s RiskScope) Key() string {
+	key := s.AccountID
+	if s.StrategyID != "" {
+		key += "/" + s.StrategyID
+	}
+	if s.Symbol != "" {
+		key += ":" + s.Symbol
+	}
+	return key
+}
+
+// String 实现 fmt.Stringer
+func (s RiskScope) String() string {
+	return s.Level().String() + " " + s.Key()
+}
@@ -0,0 +1,36 @@
+package model
+
+import "testing"
+
+func TestRiskScope_KeyAndChain(t *testing.T) {
+	tests := []struct {
+		scope     RiskScope
+		wantKey   string
+		wantLevel RiskRuleScope
+		wantChain int
+	}{
+		{RiskScope{AccountID: "acc"}, "acc", RiskRuleScopeAccount, 1},
+		{RiskScope{AccountID: "acc", Symbol: "BTCUSDT"}, "acc:BTCUSDT", RiskRuleScopeSymbol, 2},
+		{RiskScope{AccountID: "acc", StrategyID: "alpha"}, "acc/alpha", RiskRuleScopeStrategy, 2},
+		{RiskScope{AccountID: "acc", StrategyID: "alpha", Symbol: "BTCUSDT"}, "acc/alpha:BTCUSDT", RiskRuleScopeSymbol, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantKey, func(t *testing.T) {
+			if got := tt.scope.Key(); got != tt.wantKey {
+				t.Errorf("Key() = %q, want %q", got, tt.wantKey)
+			}
+			if got := tt.scope.Level(); got != tt.wantLevel {
+				t.Errorf("Level() = %v, want %v", got, tt.wantLevel)
+			}
+
+			chain := tt.scope.Chain()
+			if len(chain) != tt.wantChain {
+				t.Fatalf("Chain() = %v, want %d scopes", chain, tt.wantChain)
+			}
+			if chain[0] != AccountScope("acc") || chain[len(chain)-1] != tt.scope {
+				t.Errorf("Chain() must run from account to scope, got %v", chain)
+			}
+		})
+	}
+}
@@ -4,9 +4,10 @@ import "time"
 
 // RiskState 风控状态（持久化到 Redis/DB）
 type RiskState struct {
-	// 账户级别
-	AccountID string
-	Symbol    string // 标的（空字符串表示账户全局状态）
+	// 作用域（见 RiskScope）
+	AccountID  string
+	StrategyID string // 策略（空字符串表示不区分策略）
+	Symbol     string // 标的（空字符串表示不区分标的）
 
 	// 初始/当前净值
 	InitialEquity Money // 初始净值（用于计算总回报）
@@ -16,8 +17,13 @@ type RiskState struct {
 	// 当日盈亏统计
 	DailyPnL        Money     // 当日累计盈亏
 	DailyTradeCount int       // 当日交易次数
-	DailyResetTime  time.Time // 每日重置时间（UTC 0点）
-	LastResetDate   string    // 最后重置日期（格式：2006-01-02）
+	DailyResetTime  time.Time // 下次每日重置时间（见 TradingSession）
+	LastResetDate   string    // 当前交易日（格式：2006-01-02）
+
+	// 当周盈亏统计（仅在启用每周重置的交易日定义下按周清零）
+	WeeklyPnL        Money     // 当周累计盈亏
+	WeeklyTradeCount int       // 当周交易次数
+	WeeklyResetTime  time.Time // 下次每周重置时间（零值表示未启用）
 
 	// 连续亏损跟踪
 	ConsecutiveLosses int       // 连续亏损次数
@@ -37,11 +43,15 @@ type RiskState struct {
 
 	// 快照时间
 	UpdatedAt time.Time
+
+	// 乐观锁版本（每次持久化写入加一，0 表示尚未持久化）
+	Version int64
 }
 
 // NewRiskState 创建初始风控状态
 func NewRiskState(accountID string, initialEquity Money) *RiskState {
 	now := time.Now()
+	session := DefaultTradingSession()
 	return &RiskState{
 		AccountID:      accountID,
 		Symbol:         "", // 默认为账户全局状态
@@ -49,13 +59,41 @@ func NewRiskState(accountID string, initialEquity Money) *RiskState {
 		CurrentEquity:  initialEquity,
 		PeakEquity:     initialEquity,
 		DailyPnL:       Zero(),
+		WeeklyPnL:      Zero(),
 		MDD:            Zero(),
 		MDDPercent:     Zero(),
 		PositionMap:    make(map[string]Money),
 		TotalExposure:  Zero(),
 		UpdatedAt:      now,
-		DailyResetTime: getNextDayUTC(),
-		LastResetDate:  now.Format("2006-01-02"),
+		DailyResetTime: session.NextDailyReset(now),
+		LastResetDate:  session.TradingDay(now),
+	}
+}
+
+// NewScopedRiskState 创建指定作用域的初始风控状态
+func NewScopedRiskState(scope RiskScope, initialEquity Money) *RiskState {
+	state := NewRiskState(scope.AccountID, initialEquity)
+	state.StrategyID = scope.StrategyID
+	state.Symbol = scope.Symbol
+	return state
+}
+
+// Clone 深拷贝（状态在多个协程间共享时先拷贝再修改）
+func (rs *RiskState) Clone() *RiskState {
+	copied := *rs
+	copied.PositionMap = make(map[string]Money, len(rs.PositionMap))
+	for k, v := range rs.PositionMap {
+		copied.PositionMap[k] = v
+	}
+	return &copied
+}
+
+// Scope 状态所属作用域
+func (rs *RiskState) Scope() RiskScope {
+	return RiskScope{
+		AccountID:  rs.AccountID,
+		StrategyID: rs.StrategyID,
+		Symbol:     rs.Symbol,
 	}
 }
 
@@ -78,6 +116,21 @@ func (rs *RiskState) UpdateEquity(newEquity Money) {
 	rs.UpdatedAt = time.Now()
 }
 
+// RecordTrade 记录一笔已平仓交易（当日盈亏、交易次数与连续亏损）
+func (rs *RiskState) RecordTrade(pnl Money) {
+	rs.DailyPnL = rs.DailyPnL.Add(pnl)
+	rs.DailyTradeCount++
+	rs.WeeklyPnL = rs.WeeklyPnL.Add(pnl)
+	rs.WeeklyTradeCount++
+
+	if pnl.IsNegative() {
+		rs.RecordLoss()
+	} else if pnl.IsPositive() {
+		rs.ResetConsecutiveLosses()
+	}
+	rs.UpdatedAt = time.Now()
+}
+
 // RecordLoss 记录亏损（连续亏损计数）
 func (rs *RiskState) RecordLoss() {
 	rs.ConsecutiveLosses++
@@ -110,19 +163,35 @@ func (rs *RiskState) ShouldResetDaily(now time.Time) bool {
 	return now.After(rs.DailyResetTime)
 }
 
-// ResetDaily 每日重置
+// ResetDaily 按默认交易日（UTC 0 点）每日重置
 func (rs *RiskState) ResetDaily() {
-	now := time.Now()
+	rs.RollDaily(DefaultTradingSession(), time.Now())
+}
+
+// RollDaily 按交易日定义滚动当日统计（到达每周重置时刻时同时清零周统计）
+// 返回是否发生了每周重置
+func (rs *RiskState) RollDaily(session TradingSession, now time.Time) bool {
 	rs.DailyPnL = Zero()
 	rs.DailyTradeCount = 0
-	rs.DailyResetTime = getNextDayUTC()
-	rs.LastResetDate = now.Format("2006-01-02")
+	rs.DailyResetTime = session.NextDailyReset(now)
+	rs.LastResetDate = session.TradingDay(now)
 	rs.UpdatedAt = now
-}
 
-// getNextDayUTC 获取下一个UTC午夜时间
-func getNextDayUTC() time.Time {
-	now := time.Now().UTC()
-	tomorrow := now.Add(24 * time.Hour)
-	return time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	weekly := false
+	switch {
+	case !session.WeeklyReset:
+		// 未启用每周重置：周统计随每日重置清零
+		rs.WeeklyPnL = Zero()
+		rs.WeeklyTradeCount = 0
+		rs.WeeklyResetTime = time.Time{}
+	case rs.WeeklyResetTime.IsZero():
+		// 首次启用：从当前交易日开始累计
+		rs.WeeklyResetTime = session.NextWeeklyReset(now)
+	case !now.Before(rs.WeeklyResetTime):
+		rs.WeeklyPnL = Zero()
+		rs.WeeklyTradeCount = 0
+		rs.WeeklyResetTime = session.NextWeeklyReset(now)
+		weekly = true
+	}
+	return weekly
 }
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// RiskStateVersion 当前 RiskState 编码版本
+// 版本历史：
+//   - 1：裸 RiskState JSON（Money 未实现序列化，所有金额字段被写成 {}）
+//   - 2：带版本信封，Money 以字符串编码
+const RiskStateVersion = 2
+
+// riskStateEnvelope 版本信封
+type riskStateEnvelope struct {
+	Version int             `json:"version"`
+	State   json.RawMessage `json:"state"`
+}
+
+// RiskStateMigration 将 from 版本的状态字段迁移到 from+1 版本（就地修改）
+type RiskStateMigration func(fields map[string]json.RawMessage) error
+
+var (
+	migrationsMu        sync.RWMutex
+	riskStateMigrations = map[int]RiskStateMigration{
+		1: migrateRiskStateV1,
+	}
+)
+
+// RegisterRiskStateMigration 注册 from -> from+1 的迁移函数（同版本覆盖）
+// 新增 RiskState 版本时，提升 RiskStateVersion 并注册上一版本的迁移
+func RegisterRiskStateMigration(from int, fn RiskStateMigration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	riskStateMigrations[from] = fn
+}
+
+// EncodeRiskState 以当前版本编码 RiskState
+func EncodeRiskState(state *RiskState) ([]byte, error) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshal risk state failed: %w", err)
+	}
+	return json.Marshal(riskStateEnvelope{
+		Version: RiskStateVersion,
+		State:   raw,
+	})
+}
+
+// DecodeRiskState 解码 RiskState，旧版本数据依次执行迁移后再解析
+// 返回数据的原始版本，调用方可据此用其他来源补齐旧版本丢失的字段
+func DecodeRiskState(data []byte) (*RiskState, int, error) {
+	version, raw, err := unwrapRiskState(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > RiskStateVersion {
+		return nil, version, fmt.Errorf("unsupported risk state version %d (current %d)", version, RiskStateVersion)
+	}
+
+	if version < RiskStateVersion {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, version, fmt.Errorf("unmarshal risk state fields failed: %w", err)
+		}
+
+		migrationsMu.RLock()
+		for v := version; v < RiskStateVersion; v++ {
+			migrate, ok := riskStateMigrations[v]
+			if !ok {
+				migrationsMu.RUnlock()
+				return nil, version, fmt.Errorf("no risk state migration from version %d", v)
+			}
+			if err := migrate(fields); err != nil {
+				migrationsMu.RUnlock()
+				return nil, version, fmt.Errorf("migrate risk state from version %d failed: %w", v, err)
+			}
+		}
+		migrationsMu.RUnlock()
+
+		if raw, err = json.Marshal(fields); err != nil {
+			return nil, version, fmt.Errorf("marshal migrated risk state failed: %w", err)
+		}
+	}
+
+	var state RiskState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, version, fmt.Errorf("unmarshal risk state failed: %w", err)
+	}
+	if state.PositionMap == nil {
+		state.PositionMap = make(map[string]Money)
+	}
+	return &state, version, nil
+}
+
+// unwrapRiskState 拆出版本信封（无信封视为版本 1）
+func unwrapRiskState(data []byte) (int, json.RawMessage, error) {
+	var envelope riskStateEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return 0, nil, fmt.Errorf("unmarshal risk state envelope failed: %w", err)
+	}
+	if envelope.Version == 0 || len(envelope.State) == 0 {
+		return 1, data, nil
+	}
+	return envelope.Version, envelope.State, nil
+}
+
+// migrateRiskStateV1 版本 1 的金额字段均为 {}，无法恢复，清除后按零值解析
+func migrateRiskStateV1(fields map[string]json.RawMessage) error {
+	for key, value := range fields {
+		if bytes.Equal(bytes.TrimSpace(value), []byte("{}")) {
+			delete(fields, key)
+		}
+	}
+
+	// PositionMap 的值同样为 {}，保留标的、金额清零
+	if raw, ok := fields["PositionMap"]; ok && !bytes.Equal(raw, []byte("null")) {
+		var positions map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &positions); err != nil {
+			return err
+		}
+		for symbol, value := range positions {
+			if bytes.Equal(bytes.TrimSpace(value), []byte("{}")) {
+				positions[symbol] = json.RawMessage(`"0"`)
+			}
+		}
+		encoded, err := json.Marshal(positions)
+		if err != nil {
+			return err
+		}
+		fields["PositionMap"] = encoded
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRiskStateCodec_RoundTrip(t *testing.T) {
+	state := NewRiskState("acc", MustMoney("10000.5"))
+	state.Symbol = "BTCUSDT"
+	state.CurrentEquity = MustMoney("9500.25")
+	state.PeakEquity = MustMoney("11000")
+	state.DailyPnL = MustMoney("-500.25")
+	state.DailyTradeCount = 7
+	state.ConsecutiveLosses = 2
+	state.MDD = MustMoney("1499.75")
+	state.MDDPercent = MustMoney("0.136340909090909091")
+	state.TotalExposure = MustMoney("3000")
+	state.PositionMap["BTCUSDT"] = MustMoney("2000.123456789")
+	state.PositionMap["ETHUSDT"] = MustMoney("999.876543211")
+	state.OpenCircuitBreaker(time.Hour)
+
+	data, err := EncodeRiskState(state)
+	if err != nil {
+		t.Fatalf("EncodeRiskState failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"version":2`) {
+		t.Errorf("encoded state missing version: %s", data)
+	}
+
+	decoded, version, err := DecodeRiskState(data)
+	if err != nil {
+		t.Fatalf("DecodeRiskState failed: %v", err)
+	}
+	if version != RiskStateVersion {
+		t.Errorf("version: got %d, want %d", version, RiskStateVersion)
+	}
+
+	checks := map[string][2]Money{
+		"InitialEquity": {decoded.InitialEquity, state.InitialEquity},
+		"CurrentEquity": {decoded.CurrentEquity, state.CurrentEquity},
+		"PeakEquity":    {decoded.PeakEquity, state.PeakEquity},
+		"DailyPnL":      {decoded.DailyPnL, state.DailyPnL},
+		"MDD":           {decoded.MDD, state.MDD},
+		"MDDPercent":    {decoded.MDDPercent, state.MDDPercent},
+		"TotalExposure": {decoded.TotalExposure, state.TotalExposure},
+		"BTCUSDT":       {decoded.PositionMap["BTCUSDT"], state.PositionMap["BTCUSDT"]},
+		"ETHUSDT":       {decoded.PositionMap["ETHUSDT"], state.PositionMap["ETHUSDT"]},
+	}
+	for name, pair := range checks {
+		if !pair[0].EQ(pair[1]) {
+			t.Errorf("%s: got %s, want %s", name, pair[0], pair[1])
+		}
+	}
+	if decoded.AccountID != "acc" || decoded.Symbol != "BTCUSDT" || decoded.DailyTradeCount != 7 ||
+		decoded.ConsecutiveLosses != 2 || !decoded.CircuitBreakerOpen || decoded.CircuitBreakerUntil != state.CircuitBreakerUntil {
+		t.Errorf("scalar fields mismatch: %+v", decoded)
+	}
+}
+
+func TestRiskStateCodec_LegacyV1(t *testing.T) {
+	// 版本 1：裸 JSON，金额字段被序列化为 {}
+	legacy := `{"AccountID":"acc","Symbol":"","InitialEquity":{},"CurrentEquity":{},"PeakEquity":{},` +
+		`"DailyPnL":{},"DailyTradeCount":3,"ConsecutiveLosses":4,"MDD":{},"MDDPercent":{},` +
+		`"TotalExposure":{},"PositionMap":{"BTCUSDT":{}},"CircuitBreakerOpen":true,"CircuitBreakerUntil":1700000000}`
+
+	state, version, err := DecodeRiskState([]byte(legacy))
+	if err != nil {
+		t.Fatalf("DecodeRiskState failed: %v", err)
+	}
+	if version != 1 {
+		t.Errorf("version: got %d, want 1", version)
+	}
+	if state.AccountID != "acc" || state.ConsecutiveLosses != 4 || !state.CircuitBreakerOpen {
+		t.Errorf("scalar fields lost in migration: %+v", state)
+	}
+	if !state.CurrentEquity.IsZero() {
+		t.Errorf("unrecoverable money should decode as zero, got %s", state.CurrentEquity)
+	}
+	if _, ok := state.PositionMap["BTCUSDT"]; !ok {
+		t.Error("position symbols should be kept")
+	}
+}
+
+func TestRiskStateCodec_Migrations(t *testing.T) {
+	t.Run("未来版本", func(t *testing.T) {
+		_, _, err := DecodeRiskState([]byte(`{"version":99,"state":{}}`))
+		if err == nil {
+			t.Error("expected error for unsupported version")
+		}
+	})
+
+	t.Run("自定义迁移", func(t *testing.T) {
+		original := riskStateMigrations[1]
+		defer RegisterRiskStateMigration(1, original)
+
+		// 旧字段改名：Losses -> ConsecutiveLosses
+		RegisterRiskStateMigration(1, func(fields map[string]json.RawMessage) error {
+			if err := original(fields); err != nil {
+				return err
+			}
+			fields["ConsecutiveLosses"] = fields["Losses"]
+			delete(fields, "Losses")
+			return nil
+		})
+
+		state, _, err := DecodeRiskState([]byte(`{"AccountID":"acc","Losses":6}`))
+		if err != nil {
+			t.Fatalf("DecodeRiskState failed: %v", err)
+		}
+		if state.ConsecutiveLosses != 6 {
+			t.Errorf("migration not applied: got %d, want 6", state.ConsecutiveLosses)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// 风控状态快照类型（对应 risk_state_history.kind）
+const (
+	RiskHistoryKindPeriodic     = "PERIODIC"      // 定时快照
+	RiskHistoryKindChange       = "CHANGE"        // 状态变更（交易记录等）
+	RiskHistoryKindDailyRoll    = "DAILY_ROLL"    // 交易日滚动
+	RiskHistoryKindCircuitOpen  = "CIRCUIT_OPEN"  // 熔断器打开
+	RiskHistoryKindCircuitClose = "CIRCUIT_CLOSE" // 熔断器关闭
+)
+
+// RiskStateSnapshot 风控状态历史快照（对应 risk_state_history 表）
+type RiskStateSnapshot struct {
+	ID         int64
+	AccountID  string
+	StrategyID string
+	Symbol     string
+
+	Kind   string // 快照类型（见 RiskHistoryKind*）
+	Reason string // 触发原因（熔断时为拦截原因）
+
+	CurrentEquity Money
+	PeakEquity    Money
+	MDD           Money
+	MDDPercent    Money
+	DailyPnL      Money
+	TotalExposure Money
+
+	ConsecutiveLosses   int
+	CircuitBreakerOpen  bool
+	CircuitBreakerUntil int64
+	StateVersion        int64 // 快照对应的 RiskState 版本
+
+	RecordedAt time.Time
+}
+
+// NewRiskStateSnapshot 由当前状态生成快照
+func NewRiskStateSnapshot(state *RiskState, kind, reason string, at time.Time) *RiskStateSnapshot {
+	return &RiskStateSnapshot{
+		AccountID:           state.AccountID,
+		StrategyID:          state.StrategyID,
+		Symbol:              state.Symbol,
+		Kind:                kind,
+		Reason:              reason,
+		CurrentEquity:       state.CurrentEquity,
+		PeakEquity:          state.PeakEquity,
+		MDD:                 state.MDD,
+		MDDPercent:          state.MDDPercent,
+		DailyPnL:            state.DailyPnL,
+		TotalExposure:       state.TotalExposure,
+		ConsecutiveLosses:   state.ConsecutiveLosses,
+		CircuitBreakerOpen:  state.CircuitBreakerOpen,
+		CircuitBreakerUntil: state.CircuitBreakerUntil,
+		StateVersion:        state.Version,
+		RecordedAt:          at,
+	}
+}
+
+// Scope 快照所属作用域
+func (s *RiskStateSnapshot) Scope() RiskScope {
+	return RiskScope{
+		AccountID:  s.AccountID,
+		StrategyID: s.StrategyID,
+		Symbol:     s.Symbol,
+	}
+}
+
+// RiskStateHistoryFilter 风控状态历史查询条件（零值字段不参与过滤）
+type RiskStateHistoryFilter struct {
+	Scope RiskScope // 作用域（精确匹配）
+	Kinds []string  // 快照类型
+	From  time.Time
+	To    time.Time
+	Limit int
+}
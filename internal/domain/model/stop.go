@@ -0,0 +1,92 @@
+package model
+
+import "time"
+
+// StopStage 止损阶段
+type StopStage int
+
+const (
+	// StopStageInitial 初始止损
+	StopStageInitial StopStage = iota
+	// StopStageBreakeven 已移至保本位（开仓价）
+	StopStageBreakeven
+	// StopStageTrailing ATR 追踪止损
+	StopStageTrailing
+)
+
+func (s StopStage) String() string {
+	switch s {
+	case StopStageInitial:
+		return "INITIAL"
+	case StopStageBreakeven:
+		return "BREAKEVEN"
+	case StopStageTrailing:
+		return "TRAILING"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseStopStage 解析止损阶段
+func ParseStopStage(s string) StopStage {
+	switch s {
+	case "BREAKEVEN":
+		return StopStageBreakeven
+	case "TRAILING":
+		return StopStageTrailing
+	default:
+		return StopStageInitial
+	}
+}
+
+// StopLevel 持仓的止损/止盈设置
+type StopLevel struct {
+	PositionID string // 持仓标识（同一持仓只保留一组止损）
+	AccountID  string
+	StrategyID string
+	Symbol     string
+
+	Side       OrderSide // 持仓方向：Buy 为多头，Sell 为空头
+	Quantity   Money     // 持仓数量（平仓数量）
+	EntryPrice Money     // 开仓均价
+
+	StopLoss    Money     // 当前止损价（零表示不设）
+	TakeProfit  Money     // 止盈价（零表示不设）
+	InitialStop Money     // 初始止损价（1R = |EntryPrice - InitialStop|）
+	Stage       StopStage // 止损阶段
+
+	UpdatedAt time.Time
+}
+
+// ExitSide 平仓方向
+func (s *StopLevel) ExitSide() OrderSide {
+	if s.Side == OrderSideBuy {
+		return OrderSideSell
+	}
+	return OrderSideBuy
+}
+
+// IsLong 是否多头
+func (s *StopLevel) IsLong() bool {
+	return s.Side == OrderSideBuy
+}
+
+// RiskUnit 单位风险 R（开仓价与初始止损的距离）
+func (s *StopLevel) RiskUnit() Money {
+	if s.InitialStop.IsZero() {
+		return Zero()
+	}
+	return s.EntryPrice.Sub(s.InitialStop).Abs()
+}
+
+// StopAdjustment 止损调整记录（审计与看板展示）
+type StopAdjustment struct {
+	PositionID string
+	Symbol     string
+	OldStop    Money
+	NewStop    Money
+	Stage      StopStage
+	MarkPrice  Money  // 调整时的标记价格
+	Reason     string // e.g. "breakeven at 1.2R", "trail 2.0xATR"
+	AdjustedAt time.Time
+}
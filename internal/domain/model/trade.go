@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Trade 成交明细（交易所每一笔撮合成交，对应 executions 表）
+type Trade struct {
+	ExecID          string // 交易所成交 ID（与 Symbol 组合唯一）
+	ClientOrderID   string // 关联的客户端订单 ID（交易所未返回时按 ExchangeOrderID 查找本地订单）
+	ExchangeOrderID string // 交易所订单 ID
+	Symbol          string
+	Side            OrderSide
+	Price           Money
+	Quantity        Money
+	QuoteQty        Money // 成交金额（计价资产）
+	Fee             Money
+	FeeAsset        string
+	TradedAt        time.Time // 交易所撮合时间
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// 全局交易暂停状态
+const (
+	TradingHaltStatusActive         = "ACTIVE"          // 暂停中
+	TradingHaltStatusReleasePending = "RELEASE_PENDING" // 已申请解除，等待第二位管理员确认
+	TradingHaltStatusReleased       = "RELEASED"        // 已解除
+)
+
+// TradingHalt 全局交易暂停（Kill Switch 触发后持久化，重启后仍生效）
+// 暂停期间风控拒绝全部非只减仓订单；解除需提级认证，可配置为双人确认
+type TradingHalt struct {
+	ID     int64
+	Status string
+	Reason string // 触发原因
+
+	HaltedBy int64 // 触发人（用户 ID）
+	HaltedAt time.Time
+
+	// 触发时的处置结果
+	CancelledOrders    int  // 撤销的活跃订单数
+	Flattened          bool // 是否请求市价平掉全部持仓
+	FlattenedPositions int  // 发出平仓指令的持仓数
+
+	// 解除（双人确认时 ReleaseRequestedBy 为发起人，ReleasedBy 为确认人）
+	ReleaseRequestedBy int64
+	ReleaseRequestedAt time.Time
+	ReleasedBy         int64
+	ReleasedAt         time.Time
+}
+
+// IsActive 暂停是否仍生效（等待确认解除时仍处于暂停）
+func (h *TradingHalt) IsActive() bool {
+	return h.Status != TradingHaltStatusReleased
+}
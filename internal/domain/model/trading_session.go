@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// TradingSession 交易日定义（风控当日统计按该时段滚动）
+// 交易日从时区 Timezone 的 ResetHour 点开始，例如 Asia/Shanghai 8 点重置时，
+// 北京时间 10-20 07:00 仍属于 10-19 交易日
+type TradingSession struct {
+	Timezone  string // IANA 时区（空表示 UTC）
+	ResetHour int    // 每日重置时刻（0-23，时区内的小时）
+
+	// 每周重置（可选）：在 WeeklyResetDay 的每日重置时刻同时清零周统计
+	WeeklyReset    bool
+	WeeklyResetDay time.Weekday
+}
+
+// DefaultTradingSession 默认交易日（UTC 0 点重置，不启用每周重置）
+func DefaultTradingSession() TradingSession {
+	return TradingSession{Timezone: "UTC"}
+}
+
+// Validate 校验时区与重置时刻
+func (s TradingSession) Validate() error {
+	if s.ResetHour < 0 || s.ResetHour > 23 {
+		return fmt.Errorf("invalid reset hour %d", s.ResetHour)
+	}
+	if s.WeeklyResetDay < time.Sunday || s.WeeklyResetDay > time.Saturday {
+		return fmt.Errorf("invalid weekly reset day %d", s.WeeklyResetDay)
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	return nil
+}
+
+// Location 交易日时区（无效时区回退 UTC，配置加载时应先 Validate）
+func (s TradingSession) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// TradingDay now 所属交易日（格式：2006-01-02）
+func (s TradingSession) TradingDay(now time.Time) string {
+	return s.sessionStart(now).Format("2006-01-02")
+}
+
+// NextDailyReset now 之后的下一个每日重置时刻
+func (s TradingSession) NextDailyReset(now time.Time) time.Time {
+	start := s.sessionStart(now)
+	return s.resetAt(start.Year(), start.Month(), start.Day()+1)
+}
+
+// NextWeeklyReset now 之后的下一个每周重置时刻（未启用每周重置时返回零值）
+func (s TradingSession) NextWeeklyReset(now time.Time) time.Time {
+	if !s.WeeklyReset {
+		return time.Time{}
+	}
+	next := s.NextDailyReset(now)
+	for next.Weekday() != s.WeeklyResetDay {
+		next = s.resetAt(next.Year(), next.Month(), next.Day()+1)
+	}
+	return next
+}
+
+// sessionStart now 所属交易日的开始时刻（时区内时间）
+func (s TradingSession) sessionStart(now time.Time) time.Time {
+	local := now.In(s.Location())
+	start := s.resetAt(local.Year(), local.Month(), local.Day())
+	if local.Before(start) {
+		start = s.resetAt(local.Year(), local.Month(), local.Day()-1)
+	}
+	return start
+}
+
+// resetAt 指定日期的重置时刻（按日历构造，跨夏令时不漂移）
+func (s TradingSession) resetAt(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, s.ResetHour, 0, 0, 0, s.Location())
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestTradingSession_TradingDay(t *testing.T) {
+	shanghai := mustLocation(t, "Asia/Shanghai")
+	session := TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"重置前属于前一交易日", time.Date(2024, 3, 5, 7, 59, 0, 0, shanghai), "2024-03-04"},
+		{"重置时刻开始新交易日", time.Date(2024, 3, 5, 8, 0, 0, 0, shanghai), "2024-03-05"},
+		{"UTC 时间按时区换算", time.Date(2024, 3, 4, 23, 30, 0, 0, time.UTC), "2024-03-04"},
+		{"跨月", time.Date(2024, 3, 1, 3, 0, 0, 0, shanghai), "2024-02-29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := session.TradingDay(tt.now); got != tt.want {
+				t.Errorf("TradingDay = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTradingSession_NextDailyReset(t *testing.T) {
+	shanghai := mustLocation(t, "Asia/Shanghai")
+	session := TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8}
+
+	next := session.NextDailyReset(time.Date(2024, 3, 5, 7, 0, 0, 0, shanghai))
+	if want := time.Date(2024, 3, 5, 8, 0, 0, 0, shanghai); !next.Equal(want) {
+		t.Errorf("before reset: next = %s, want %s", next, want)
+	}
+
+	next = session.NextDailyReset(time.Date(2024, 3, 5, 8, 0, 0, 0, shanghai))
+	if want := time.Date(2024, 3, 6, 8, 0, 0, 0, shanghai); !next.Equal(want) {
+		t.Errorf("at reset: next = %s, want %s", next, want)
+	}
+
+	// 默认定义与旧版本一致：下一个 UTC 0 点
+	next = DefaultTradingSession().NextDailyReset(time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("default: next = %s, want %s", next, want)
+	}
+}
+
+func TestTradingSession_DST(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	session := TradingSession{Timezone: "America/New_York", ResetHour: 17}
+
+	// 2024-03-10 夏令时开始，当日只有 23 小时
+	next := session.NextDailyReset(time.Date(2024, 3, 9, 18, 0, 0, 0, newYork))
+	if want := time.Date(2024, 3, 10, 17, 0, 0, 0, newYork); !next.Equal(want) {
+		t.Errorf("next = %s, want %s", next, want)
+	}
+}
+
+func TestTradingSession_NextWeeklyReset(t *testing.T) {
+	shanghai := mustLocation(t, "Asia/Shanghai")
+	session := TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8, WeeklyReset: true, WeeklyResetDay: time.Monday}
+
+	// 2024-03-05 为周二
+	next := session.NextWeeklyReset(time.Date(2024, 3, 5, 9, 0, 0, 0, shanghai))
+	if want := time.Date(2024, 3, 11, 8, 0, 0, 0, shanghai); !next.Equal(want) {
+		t.Errorf("next = %s, want %s", next, want)
+	}
+
+	if !DefaultTradingSession().NextWeeklyReset(time.Now()).IsZero() {
+		t.Error("weekly reset disabled should return zero time")
+	}
+}
+
+func TestTradingSession_Validate(t *testing.T) {
+	if err := (TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8}).Validate(); err != nil {
+		t.Errorf("valid session: %v", err)
+	}
+	if err := (TradingSession{Timezone: "UTC", ResetHour: 24}).Validate(); err == nil {
+		t.Error("reset hour 24 should be invalid")
+	}
+	if err := (TradingSession{Timezone: "Mars/Olympus"}).Validate(); err == nil {
+		t.Error("unknown timezone should be invalid")
+	}
+}
+
+func TestRiskState_RollDaily(t *testing.T) {
+	shanghai := mustLocation(t, "Asia/Shanghai")
+	session := TradingSession{Timezone: "Asia/Shanghai", ResetHour: 8, WeeklyReset: true, WeeklyResetDay: time.Monday}
+
+	state := NewRiskState("acc-1", MustMoney("10000"))
+	state.RecordTrade(MustMoney("-100"))
+
+	// 首次启用每周重置：只设置下次重置时间，不清零周统计
+	tuesday := time.Date(2024, 3, 5, 8, 0, 0, 0, shanghai)
+	if state.RollDaily(session, tuesday) {
+		t.Error("first roll should not be weekly")
+	}
+	if !state.DailyPnL.IsZero() || state.DailyTradeCount != 0 {
+		t.Errorf("daily stats not reset: pnl=%s count=%d", state.DailyPnL, state.DailyTradeCount)
+	}
+	if state.LastResetDate != "2024-03-05" {
+		t.Errorf("LastResetDate = %s, want 2024-03-05", state.LastResetDate)
+	}
+	if !state.WeeklyPnL.EQ(MustMoney("-100")) {
+		t.Errorf("WeeklyPnL = %s, want -100", state.WeeklyPnL)
+	}
+
+	state.RecordTrade(MustMoney("50"))
+	monday := time.Date(2024, 3, 11, 8, 0, 0, 0, shanghai)
+	if !state.RollDaily(session, monday) {
+		t.Error("monday roll should be weekly")
+	}
+	if !state.WeeklyPnL.IsZero() || state.WeeklyTradeCount != 0 {
+		t.Errorf("weekly stats not reset: pnl=%s count=%d", state.WeeklyPnL, state.WeeklyTradeCount)
+	}
+	if want := time.Date(2024, 3, 18, 8, 0, 0, 0, shanghai); !state.WeeklyResetTime.Equal(want) {
+		t.Errorf("WeeklyResetTime = %s, want %s", state.WeeklyResetTime, want)
+	}
+}
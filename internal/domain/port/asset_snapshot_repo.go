@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// AssetSnapshotRepo 资产快照持久化接口
+type AssetSnapshotRepo interface {
+	// SaveSnapshot 保存快照（同账户、同类型、同交易日只保留首条，重复写入忽略）
+	SaveSnapshot(ctx context.Context, snapshot *model.AssetSnapshot) error
+
+	// ListSnapshots 查询账户快照（按快照时间倒序，kind 为空时不过滤，limit 为 0 时不限制）
+	ListSnapshots(ctx context.Context, accountID, kind string, limit int) ([]*model.AssetSnapshot, error)
+}
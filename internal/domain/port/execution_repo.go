@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// ExecutionRepo 算法母单持久化接口
+// 实现要求：
+// 1. 母单按 ID 幂等更新
+// 2. 子单关联只追加，状态可更新（用于重启后恢复执行进度）
+type ExecutionRepo interface {
+	// SaveParent 保存母单（幂等）
+	SaveParent(ctx context.Context, parent *model.ParentOrder) error
+
+	// GetParent 获取母单
+	GetParent(ctx context.Context, parentID string) (*model.ParentOrder, error)
+
+	// ListActiveParents 列出执行中的母单
+	ListActiveParents(ctx context.Context) ([]*model.ParentOrder, error)
+
+	// SaveChild 保存子单关联（按 ClientOrderID 幂等）
+	SaveChild(ctx context.Context, child *model.ChildOrder) error
+
+	// ListChildren 列出母单的所有子单（按序号升序）
+	ListChildren(ctx context.Context, parentID string) ([]*model.ChildOrder, error)
+}
@@ -13,6 +13,7 @@ type FuturePlaceOrderRequest struct {
 	Side          model.OrderSide // 买卖方向
 	Type          model.OrderType // 订单类型
 	Price         model.Money     // 限价单价格
+	StopPrice     model.Money     // 条件单触发价（止损、止盈单）
 	Quantity      model.Money     // 数量（张数）
 	Leverage      int             // 杠杆倍数
 	ReduceOnly    bool            // 只减仓
@@ -26,6 +27,19 @@ type FutureCancelOrderRequest struct {
 	Symbol        string // 合约交易对
 }
 
+// FutureAmendOrderRequest 改单请求（撤销原订单并以新价格、数量提交新订单）
+type FutureAmendOrderRequest struct {
+	ClientOrderID    string          // 原订单客户端ID
+	NewClientOrderID string          // 新订单客户端ID
+	Symbol           string          // 合约交易对
+	Side             model.OrderSide // 买卖方向（与原订单一致）
+	Type             model.OrderType // 订单类型
+	Price            model.Money     // 新订单价格
+	StopPrice        model.Money     // 新订单触发价（条件单）
+	Quantity         model.Money     // 新订单数量（张数）
+	ReduceOnly       bool            // 只减仓
+}
+
 // FuturePosition 合约持仓
 type FuturePosition struct {
 	Symbol           string
@@ -58,6 +72,9 @@ type FutureGateway interface {
 	// CancelOrder 撤单
 	CancelOrder(ctx context.Context, req *FutureCancelOrderRequest) error
 
+	// AmendOrder 改单（语义同 SpotGateway.AmendOrder，错误包装 ErrAmendCancelFailed / ErrAmendNewOrderRejected）
+	AmendOrder(ctx context.Context, req *FutureAmendOrderRequest) (*AmendOrderResult, error)
+
 	// GetOrder 查询订单
 	GetOrder(ctx context.Context, clientOrderID string) (*model.Order, error)
 
@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// ErrOrderListNotFound 订单组不存在
+var ErrOrderListNotFound = errors.New("order list not found")
+
+// OrderListRepo 订单组（OCO / 括号单）持久化接口
+// 实现要求：
+// 1. 按 ListID 幂等写入，每次状态变化都必须落盘（重启后恢复本地模拟的止损触发）
+// 2. 子订单只记录 ClientOrderID，订单本身仍由 OrderRepo 持久化
+type OrderListRepo interface {
+	// SaveOrderList 保存订单组（按 ListID 幂等更新）
+	SaveOrderList(ctx context.Context, list *model.OrderList) error
+
+	// GetOrderList 根据 ListID 获取订单组（不存在时返回 ErrOrderListNotFound）
+	GetOrderList(ctx context.Context, listID string) (*model.OrderList, error)
+
+	// ListActiveOrderLists 列出所有未完结的订单组（PENDING / ACTIVE，按创建时间升序）
+	ListActiveOrderLists(ctx context.Context) ([]*model.OrderList, error)
+}
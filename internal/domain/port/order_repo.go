@@ -2,10 +2,14 @@ package port
 
 import (
 	"context"
+	"errors"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
 )
 
+// ErrOrderNotFound 订单不存在
+var ErrOrderNotFound = errors.New("order not found")
+
 // OrderRepo 订单持久化接口
 // 实现要求：
 // 1. 幂等写入（相同 ClientOrderID 多次写入结果一致）
@@ -13,18 +17,26 @@ import (
 // 3. 支持回测（内存实现）与实盘（DB 实现）
 type OrderRepo interface {
 	// SaveOrder 保存订单（幂等）
-	// 如果 ClientOrderID 已存在则更新，否则插入
+	// 如果 ClientOrderID 已存在则更新，否则插入并追加订单创建事件
 	SaveOrder(ctx context.Context, order *model.Order) error
 
-	// GetOrder 根据 ClientOrderID 获取订单
+	// GetOrder 根据 ClientOrderID 获取订单（不存在时返回 ErrOrderNotFound）
 	GetOrder(ctx context.Context, clientOrderID string) (*model.Order, error)
 
-	// GetOrderByExchangeID 根据交易所订单ID获取订单
+	// GetOrderByExchangeID 根据交易所订单ID获取订单（不存在时返回 ErrOrderNotFound）
 	GetOrderByExchangeID(ctx context.Context, exchangeID string) (*model.Order, error)
 
-	// UpdateOrderStatus 原子更新订单状态
+	// UpdateOrderStatus 原子更新订单状态（等同于只含状态的 ApplyUpdate）
 	UpdateOrderStatus(ctx context.Context, clientOrderID string, status model.OrderStatus) error
 
+	// ApplyUpdate 按订单状态机原子应用更新，并追加状态转换事件
+	// 非法转换返回 model.ErrInvalidOrderTransition，乱序到达的过期更新返回 model.ErrStaleOrderUpdate；
+	// 状态与成交数量均未变化时返回 nil 事件
+	ApplyUpdate(ctx context.Context, clientOrderID string, update model.OrderUpdate) (*model.OrderEvent, error)
+
+	// ListOrderEvents 列出订单的状态转换事件（按发生顺序，首条为订单创建）
+	ListOrderEvents(ctx context.Context, clientOrderID string) ([]*model.OrderEvent, error)
+
 	// UpdateFilled 更新成交数量
 	UpdateFilled(ctx context.Context, clientOrderID string, filled model.Money) error
 
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// RiskHistoryRepo 风控状态历史持久化接口
+type RiskHistoryRepo interface {
+	// SaveSnapshots 批量保存风控状态快照
+	SaveSnapshots(ctx context.Context, snapshots []*model.RiskStateSnapshot) error
+
+	// ListSnapshots 查询作用域的风控状态快照（按记录时间升序）
+	ListSnapshots(ctx context.Context, filter model.RiskStateHistoryFilter) ([]*model.RiskStateSnapshot, error)
+
+	// PurgeSnapshots 删除 before 之前的指定类型快照，返回删除条数
+	PurgeSnapshots(ctx context.Context, kinds []string, before time.Time) (int64, error)
+}
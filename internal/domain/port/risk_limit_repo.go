@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// RiskLimitRepo 风控限额版本持久化接口
+type RiskLimitRepo interface {
+	// SaveVersion 保存新版本（版本号已存在时返回错误）
+	SaveVersion(ctx context.Context, version *model.RiskLimitVersion) error
+
+	// LatestVersion 获取最新版本（无版本时返回 nil, nil）
+	LatestVersion(ctx context.Context) (*model.RiskLimitVersion, error)
+
+	// ListVersions 按版本号倒序列出最近的版本
+	ListVersions(ctx context.Context, limit int) ([]*model.RiskLimitVersion, error)
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// RiskRecordRepo 风控决策记录持久化接口
+type RiskRecordRepo interface {
+	// SaveRecords 批量保存风控记录
+	SaveRecords(ctx context.Context, records []*model.RiskRecord) error
+
+	// ListRecords 分页查询风控记录（按触发时间倒序），返回记录与满足条件的总数
+	ListRecords(ctx context.Context, filter model.RiskRecordFilter) ([]*model.RiskRecord, int64, error)
+}
@@ -2,25 +2,35 @@ package port
 
 import (
 	"context"
+	"errors"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
 )
 
+// ErrRiskStateConflict 乐观锁冲突（存储中的状态已被其他进程或协程修改）
+var ErrRiskStateConflict = errors.New("risk state version conflict")
+
 // RiskRepo 风控状态持久化接口
 // 实现要求：
 // 1. 幂等写入（相同状态多次写入结果一致）
-// 2. 原子更新（状态变更全部成功或全部失败）
+// 2. 原子更新（状态变更全部成功或全部失败），每次写入 RiskState.Version 加一
 // 3. 支持回测（内存实现）与实盘（Redis/DB实现）
 type RiskRepo interface {
 	// LoadState 加载风控状态
-	// accountID: 账户ID（支持多账户）
-	// symbol: 标的（空字符串表示全局状态）
-	LoadState(ctx context.Context, accountID, symbol string) (*model.RiskState, error)
+	// scope: 作用域（账户 / 策略 / 标的），不存在时返回该作用域的初始状态
+	LoadState(ctx context.Context, scope model.RiskScope) (*model.RiskState, error)
 
 	// SaveState 保存风控状态（幂等）
-	// 覆盖写入，确保状态一致性
+	// 覆盖写入（不校验版本，用于初始化与回测），按 state.Scope() 区分作用域，写入后更新 state.Version
 	SaveState(ctx context.Context, state *model.RiskState) error
 
+	// CompareAndSwapState 乐观锁写入
+	// 仅当存储中的版本等于 state.Version 时写入（Version 为 0 表示仅在不存在时创建），成功后 state.Version 加一
+	// 版本不一致时返回 ErrRiskStateConflict，调用方应重新加载后重试
+	CompareAndSwapState(ctx context.Context, state *model.RiskState) error
+
+	// 以下原子操作仅作用于账户级状态
+
 	// UpdateEquity 原子更新净值
 	// 同时重新计算 MDD
 	UpdateEquity(ctx context.Context, accountID string, newEquity model.Money) error
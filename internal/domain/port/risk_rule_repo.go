@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// RiskRuleRepo 风控规则集配置源接口
+type RiskRuleRepo interface {
+	// ListRuleSets 查询所有账户的规则集（key: AccountID，"default" 表示默认规则集）
+	ListRuleSets(ctx context.Context) (map[string][]*model.RiskRuleSpec, error)
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// SettlementRepo 交易结算持久化接口（绩效统计数据源）
+type SettlementRepo interface {
+	// SaveSettlement 保存已平仓交易结算
+	SaveSettlement(ctx context.Context, settlement *model.Settlement) error
+
+	// ListRecentSettlements 按策略查询最近的结算记录（每个策略最多 perStrategy 笔，按平仓时间升序）
+	ListRecentSettlements(ctx context.Context, perStrategy int) ([]*model.Settlement, error)
+}
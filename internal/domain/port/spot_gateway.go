@@ -2,10 +2,27 @@ package port
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
 )
 
+var (
+	// ErrOrderOutcomeUnknown 下单结果未知（请求超时或交易所返回执行状态未知），订单可能已在交易所生效
+	// 调用方应按 ClientOrderID 查询确认，不可视为下单失败
+	ErrOrderOutcomeUnknown = errors.New("order outcome unknown")
+
+	// ErrExchangeOrderNotFound 交易所不存在该订单（GetOrder 查询不到）
+	ErrExchangeOrderNotFound = errors.New("exchange order not found")
+
+	// ErrAmendCancelFailed 改单时原订单撤销失败（原订单保持不变，新订单未提交）
+	ErrAmendCancelFailed = errors.New("amend: cancel of original order failed")
+
+	// ErrAmendNewOrderRejected 改单时原订单已撤销但新订单被拒绝（账簿中不再有该订单）
+	ErrAmendNewOrderRejected = errors.New("amend: original order cancelled but new order rejected")
+)
+
 // SpotPlaceOrderRequest 现货下单请求
 type SpotPlaceOrderRequest struct {
 	ClientOrderID string          // 客户端订单ID（幂等）
@@ -13,6 +30,7 @@ type SpotPlaceOrderRequest struct {
 	Side          model.OrderSide // 买卖方向
 	Type          model.OrderType // 订单类型
 	Price         model.Money     // 限价单价格
+	StopPrice     model.Money     // 条件单触发价（止损、止盈单）
 	Quantity      model.Money     // 数量
 	ProtectPrice  model.Money     // 保护价（最差成交价）
 }
@@ -24,6 +42,44 @@ type SpotCancelOrderRequest struct {
 	Symbol        string // 交易对
 }
 
+// SpotAmendOrderRequest 改单请求（撤销原订单并以新价格、数量提交新订单）
+type SpotAmendOrderRequest struct {
+	ClientOrderID    string          // 原订单客户端ID
+	NewClientOrderID string          // 新订单客户端ID
+	Symbol           string          // 交易对
+	Side             model.OrderSide // 买卖方向（与原订单一致）
+	Type             model.OrderType // 订单类型
+	Price            model.Money     // 新订单价格
+	StopPrice        model.Money     // 新订单触发价（条件单）
+	Quantity         model.Money     // 新订单数量
+}
+
+// AmendOrderResult 改单结果（现货与合约共用）
+type AmendOrderResult struct {
+	Cancelled *model.Order // 撤销后的原订单（含撤销前的最终成交数量，撤销失败时为 nil）
+	Order     *model.Order // 新订单（新订单被拒绝时为 nil）
+}
+
+// SpotOCORequest 现货 OCO 下单请求（止盈限价单与止损单，一方成交后交易所撤销另一方）
+type SpotOCORequest struct {
+	ListClientOrderID       string          // 订单组客户端ID
+	Symbol                  string          // 交易对
+	Side                    model.OrderSide // 两条腿的方向（平多为卖出）
+	Quantity                model.Money     // 数量
+	TakeProfitClientOrderID string          // 止盈腿客户端ID
+	TakeProfitPrice         model.Money     // 止盈限价
+	StopLossClientOrderID   string          // 止损腿客户端ID
+	StopPrice               model.Money     // 止损触发价
+	StopLimitPrice          model.Money     // 止损触发后的限价（零值表示市价止损）
+}
+
+// SpotOCOResult OCO 下单结果
+type SpotOCOResult struct {
+	ExchangeListID string       // 交易所订单组ID
+	TakeProfit     *model.Order // 止盈腿
+	StopLoss       *model.Order // 止损腿
+}
+
 // SpotBalance 现货余额
 type SpotBalance struct {
 	Asset     string
@@ -35,13 +91,17 @@ type SpotBalance struct {
 
 // SpotGateway 现货交易接口
 type SpotGateway interface {
-	// PlaceOrder 下单
+	// PlaceOrder 下单（结果未知时返回包装 ErrOrderOutcomeUnknown 的错误）
 	PlaceOrder(ctx context.Context, req *SpotPlaceOrderRequest) (*model.Order, error)
 
 	// CancelOrder 撤单
 	CancelOrder(ctx context.Context, req *SpotCancelOrderRequest) error
 
-	// GetOrder 查询订单
+	// AmendOrder 改单（原子撤单并下单：原订单撤销失败时不提交新订单，返回包装 ErrAmendCancelFailed 的错误；
+	// 原订单已撤销但新订单被拒绝时返回包装 ErrAmendNewOrderRejected 的错误，结果中的 Cancelled 仍有效）
+	AmendOrder(ctx context.Context, req *SpotAmendOrderRequest) (*AmendOrderResult, error)
+
+	// GetOrder 查询订单（交易所不存在该订单时返回包装 ErrExchangeOrderNotFound 的错误）
 	GetOrder(ctx context.Context, clientOrderID string) (*model.Order, error)
 
 	// GetBalance 查询余额
@@ -49,4 +109,19 @@ type SpotGateway interface {
 
 	// GetAllBalances 查询所有余额
 	GetAllBalances(ctx context.Context) ([]*SpotBalance, error)
+
+	// ListOpenOrders 查询未完结订单（symbol 为空时查询全部交易对）
+	ListOpenOrders(ctx context.Context, symbol string) ([]*model.Order, error)
+
+	// ListTrades 查询 since 之后的成交明细（按成交时间升序）
+	ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error)
+}
+
+// SpotOCOGateway 现货原生 OCO 能力（可选，网关未实现时由 OMS 本地模拟）
+type SpotOCOGateway interface {
+	// PlaceOCO 提交 OCO 订单组（结果未知时返回包装 ErrOrderOutcomeUnknown 的错误，可按两条腿的 ClientOrderID 查询确认）
+	PlaceOCO(ctx context.Context, req *SpotOCORequest) (*SpotOCOResult, error)
+
+	// CancelOCO 撤销 OCO 订单组（两条腿一并撤销）
+	CancelOCO(ctx context.Context, symbol, listClientOrderID string) error
 }
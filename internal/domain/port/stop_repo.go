@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// StopRepo 持仓止损持久化接口
+// 实现要求：
+// 1. 每次止损调整都必须落盘，重启后恢复到最新止损位而非初始止损
+// 2. 调整记录只追加，供看板与审计查询
+type StopRepo interface {
+	// SaveStop 保存止损（按 PositionID 幂等更新）
+	SaveStop(ctx context.Context, stop *model.StopLevel) error
+
+	// DeleteStop 删除止损（持仓已平）
+	DeleteStop(ctx context.Context, positionID string) error
+
+	// ListStops 列出所有生效中的止损（用于重启恢复）
+	ListStops(ctx context.Context) ([]*model.StopLevel, error)
+
+	// SaveAdjustment 追加止损调整记录
+	SaveAdjustment(ctx context.Context, adj *model.StopAdjustment) error
+
+	// ListAdjustments 查询止损调整记录（positionID 为空时返回全部，按时间倒序）
+	ListAdjustments(ctx context.Context, positionID string, limit int) ([]*model.StopAdjustment, error)
+}
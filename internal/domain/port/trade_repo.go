@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// TradeRepo 成交明细持久化接口（executions 表）
+// 实现要求：
+// 1. 按 (ExecID, Symbol) 幂等写入，重复推送或对账补录不产生重复记录
+// 2. 只追加，不修改已写入的成交
+type TradeRepo interface {
+	// SaveTrade 保存成交（已存在时忽略），返回是否为新写入
+	SaveTrade(ctx context.Context, trade *model.Trade) (bool, error)
+
+	// ListTrades 查询 since 之后的成交（symbol 为空时返回全部，按成交时间升序）
+	ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error)
+}
@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// TradingHaltRepo 全局交易暂停持久化接口
+type TradingHaltRepo interface {
+	// SaveHalt 保存新的暂停记录（写入后回填 ID）
+	SaveHalt(ctx context.Context, halt *model.TradingHalt) error
+
+	// UpdateHalt 更新暂停记录的处置结果与解除状态
+	UpdateHalt(ctx context.Context, halt *model.TradingHalt) error
+
+	// ActiveHalt 获取仍生效的暂停记录（无暂停时返回 nil, nil）
+	ActiveHalt(ctx context.Context) (*model.TradingHalt, error)
+}
@@ -0,0 +1,58 @@
+package binance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// FeeRates 手续费资产价格（按 <资产><计价资产> 交易对的最新成交价折算，如 BNB -> BNBUSDT）
+// 价格按资产缓存 ttl，避免账本重放历史成交时逐笔请求行情
+type FeeRates struct {
+	ws         *WSClient
+	quoteAsset string
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	rates map[string]feeRate
+}
+
+type feeRate struct {
+	price     model.Money
+	fetchedAt time.Time
+}
+
+// NewFeeRates 创建手续费资产价格来源（ttl <= 0 时缓存 1 分钟）
+func NewFeeRates(ws *WSClient, quoteAsset string, ttl time.Duration) *FeeRates {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &FeeRates{
+		ws:         ws,
+		quoteAsset: quoteAsset,
+		ttl:        ttl,
+		rates:      make(map[string]feeRate),
+	}
+}
+
+// FeeRate 资产的计价资产价格（实现 position.FeeRateSource）
+func (r *FeeRates) FeeRate(ctx context.Context, asset string) (model.Money, error) {
+	r.mu.Lock()
+	cached, ok := r.rates[asset]
+	r.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < r.ttl {
+		return cached.price, nil
+	}
+
+	price, err := r.ws.GetLatestPrice(ctx, asset+r.quoteAsset)
+	if err != nil {
+		return model.Zero(), err
+	}
+
+	r.mu.Lock()
+	r.rates[asset] = feeRate{price: price, fetchedAt: time.Now()}
+	r.mu.Unlock()
+	return price, nil
+}
@@ -2,15 +2,24 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
 	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/binance/binance-connector-go/handlers"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
 	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
+// Binance 错误码（https://developers.binance.com/docs/binance-spot-api-docs/errors）
+const (
+	errCodeUnexpectedResp = -1006 // 后端返回异常，执行状态未知
+	errCodeTimeout        = -1007 // 等待后端响应超时，执行状态未知
+	errCodeNoSuchOrder    = -2013 // 订单不存在
+)
+
 // SpotClient Binance 现货客户端（实现 port.SpotGateway 接口）
 type SpotClient struct {
 	client    *binance_connector.Client
@@ -55,22 +64,32 @@ func (c *SpotClient) PlaceOrder(ctx context.Context, req *port.SpotPlaceOrderReq
 		Symbol(req.Symbol).
 		Side(side).
 		Type(orderType).
-		NewClientOrderId(req.ClientOrderID)
+		NewClientOrderId(req.ClientOrderID).
+		NewOrderRespType("RESULT") // 未指定时条件单只返回 ACK（不含状态、方向与数量）
 
 	// 数量（转换为 float64）
 	quantity, _ := strconv.ParseFloat(req.Quantity.String(), 64)
 	builder = builder.Quantity(quantity)
 
-	// 限价单需要价格
-	if req.Type == model.OrderTypeLimit {
+	// 限价单（含 IOC / FOK 与触发后挂限价的条件单）需要价格与有效方式
+	if req.Type.HasLimitPrice() {
 		price, _ := strconv.ParseFloat(req.Price.String(), 64)
 		builder = builder.Price(price).
-			TimeInForce("GTC") // Good Till Cancel
+			TimeInForce(c.convertTimeInForce(req.Type))
+	}
+
+	// 条件单需要触发价
+	if req.Type.IsConditional() {
+		stopPrice, _ := strconv.ParseFloat(req.StopPrice.String(), 64)
+		builder = builder.StopPrice(stopPrice)
 	}
 
 	// 执行下单
 	resp, err := builder.Do(ctx)
 	if err != nil {
+		if isOutcomeUnknown(err) {
+			return nil, fmt.Errorf("binance place order failed: %w: %v", port.ErrOrderOutcomeUnknown, err)
+		}
 		return nil, fmt.Errorf("binance place order failed: %w", err)
 	}
 
@@ -100,12 +119,198 @@ func (c *SpotClient) CancelOrder(ctx context.Context, req *port.SpotCancelOrderR
 	return nil
 }
 
+// AmendOrder 改单（cancelReplace，STOP_ON_FAILURE 模式：原订单撤销失败时不提交新订单）
+func (c *SpotClient) AmendOrder(ctx context.Context, req *port.SpotAmendOrderRequest) (*port.AmendOrderResult, error) {
+	quantity, _ := strconv.ParseFloat(req.Quantity.String(), 64)
+	builder := c.client.NewCancelReplaceService().
+		Symbol(req.Symbol).
+		Side(c.convertSide(req.Side)).
+		OrderType(c.convertOrderType(req.Type)).
+		CancelReplaceMode("STOP_ON_FAILURE").
+		CancelOrigClientOrderId(req.ClientOrderID).
+		NewClientOrderId(req.NewClientOrderID).
+		Quantity(quantity)
+	if req.Type.HasLimitPrice() {
+		price, _ := strconv.ParseFloat(req.Price.String(), 64)
+		builder = builder.Price(price).
+			TimeInForce(c.convertTimeInForce(req.Type))
+	}
+	if req.Type.IsConditional() {
+		stopPrice, _ := strconv.ParseFloat(req.StopPrice.String(), 64)
+		builder = builder.StopPrice(stopPrice)
+	}
+
+	resp, err := builder.Do(ctx)
+	if err != nil {
+		// 响应无法解析（网络错误或超时），撤单与下单结果均未知
+		return nil, fmt.Errorf("binance cancel replace failed: %w: %v", port.ErrOrderOutcomeUnknown, err)
+	}
+	return c.convertCancelReplaceResponse(resp)
+}
+
+// convertCancelReplaceResponse 转换改单响应（部分失败时响应位于 data 字段）
+func (c *SpotClient) convertCancelReplaceResponse(resp *binance_connector.CancelReplaceResponse) (*port.AmendOrderResult, error) {
+	if resp.Code == errCodeUnexpectedResp || resp.Code == errCodeTimeout {
+		return nil, fmt.Errorf("binance cancel replace failed: %w: code=%d, msg=%s", port.ErrOrderOutcomeUnknown, resp.Code, resp.Msg)
+	}
+
+	result := &port.AmendOrderResult{}
+	cancelResult, newOrderResult := resp.CancelResult, resp.NewOrderResult
+	if resp.Data != nil {
+		cancelResult, newOrderResult = resp.Data.CancelResult, resp.Data.NewOrderResult
+	}
+
+	// 撤销成功时记录原订单的最终状态（两种响应结构的字段相同，类型定义不同）
+	if cancelResult == "SUCCESS" {
+		if resp.Data != nil && resp.Data.CancelResponse != nil {
+			cr := resp.Data.CancelResponse
+			result.Cancelled = c.convertCancelledOrder(cr.OrigClientOrderId, cr.OrderId, cr.Symbol, cr.Side,
+				cr.Price, cr.OrigQty, cr.ExecutedQty, cr.Status)
+		} else if resp.CancelResponse != nil {
+			cr := resp.CancelResponse
+			result.Cancelled = c.convertCancelledOrder(cr.OrigClientOrderId, cr.OrderId, cr.Symbol, cr.Side,
+				cr.Price, cr.OrigQty, cr.ExecutedQty, cr.Status)
+		}
+	}
+
+	switch {
+	case cancelResult != "SUCCESS":
+		return nil, fmt.Errorf("%w: code=%d, msg=%s", port.ErrAmendCancelFailed, resp.Code, resp.Msg)
+	case newOrderResult != "SUCCESS" || resp.NewOrderResponse == nil:
+		return result, fmt.Errorf("%w: code=%d, msg=%s", port.ErrAmendNewOrderRejected, resp.Code, resp.Msg)
+	}
+
+	o := resp.NewOrderResponse
+	result.Order = &model.Order{
+		ClientOrderID: o.ClientOrderId,
+		ExchangeID:    strconv.FormatInt(o.OrderId, 10),
+		Symbol:        o.Symbol,
+		MarketType:    model.MarketTypeSpot,
+		Side:          model.ParseOrderSide(o.Side),
+		Type:          model.ParseOrderType(o.Type),
+		Price:         model.MustMoney(o.Price),
+		Quantity:      model.MustMoney(o.OrigQty),
+		Filled:        model.MustMoney(o.ExecutedQty),
+		Status:        c.convertOrderStatus(o.Status),
+		SubmitTime:    time.UnixMilli(int64(o.TransactTime)),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if result.Order.Type == 0 {
+		result.Order.Type = model.OrderTypeLimit
+	}
+	return result, nil
+}
+
+// convertCancelledOrder 转换改单响应中被撤销的原订单
+func (c *SpotClient) convertCancelledOrder(clientOrderID string, orderID int64, symbol, side, price, origQty, executedQty, status string) *model.Order {
+	return &model.Order{
+		ClientOrderID: clientOrderID,
+		ExchangeID:    strconv.FormatInt(orderID, 10),
+		Symbol:        symbol,
+		MarketType:    model.MarketTypeSpot,
+		Side:          model.ParseOrderSide(side),
+		Price:         model.MustMoney(price),
+		Quantity:      model.MustMoney(origQty),
+		Filled:        model.MustMoney(executedQty),
+		Status:        c.convertOrderStatus(status),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+}
+
+// PlaceOCO 提交原生 OCO 订单组（实现 port.SpotOCOGateway）
+// 止盈腿为 LIMIT_MAKER，止损腿为 STOP_LOSS（未设置止损限价）或 STOP_LOSS_LIMIT
+func (c *SpotClient) PlaceOCO(ctx context.Context, req *port.SpotOCORequest) (*port.SpotOCOResult, error) {
+	quantity, _ := strconv.ParseFloat(req.Quantity.String(), 64)
+	price, _ := strconv.ParseFloat(req.TakeProfitPrice.String(), 64)
+	stopPrice, _ := strconv.ParseFloat(req.StopPrice.String(), 64)
+	builder := c.client.NewNewOCOService().
+		Symbol(req.Symbol).
+		Side(c.convertSide(req.Side)).
+		Quantity(quantity).
+		ListClientOrderId(req.ListClientOrderID).
+		LimitClientOrderId(req.TakeProfitClientOrderID).
+		Price(price).
+		StopClientOrderId(req.StopLossClientOrderID).
+		StopPrice(stopPrice)
+	if !req.StopLimitPrice.IsZero() {
+		stopLimitPrice, _ := strconv.ParseFloat(req.StopLimitPrice.String(), 64)
+		builder = builder.StopLimitPrice(stopLimitPrice).
+			StopLimitTimeInForce("GTC")
+	}
+
+	resp, err := builder.Do(ctx)
+	if err != nil {
+		// 响应解析失败（连接器按数值解析 orderReports 中的字符串字段）同样视为结果未知，按两条腿的 ClientOrderID 查询确认
+		if isOutcomeUnknown(err) {
+			return nil, fmt.Errorf("binance place oco failed: %w: %v", port.ErrOrderOutcomeUnknown, err)
+		}
+		return nil, fmt.Errorf("binance place oco failed: %w", err)
+	}
+	return c.convertOCOResponse(resp, req), nil
+}
+
+// CancelOCO 撤销 OCO 订单组
+func (c *SpotClient) CancelOCO(ctx context.Context, symbol, listClientOrderID string) error {
+	_, err := c.client.NewCancelOCOService().
+		Symbol(symbol).
+		ListClientOrderId(listClientOrderID).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("binance cancel oco failed: %w", err)
+	}
+	return nil
+}
+
+// convertOCOResponse 转换 OCO 下单响应（按请求中的 ClientOrderID 区分止盈腿与止损腿）
+func (c *SpotClient) convertOCOResponse(resp *binance_connector.OrderOCOResponse, req *port.SpotOCORequest) *port.SpotOCOResult {
+	leg := func(clientOrderID string, price model.Money, orderType model.OrderType) *model.Order {
+		order := &model.Order{
+			ClientOrderID: clientOrderID,
+			Symbol:        req.Symbol,
+			MarketType:    model.MarketTypeSpot,
+			Side:          req.Side,
+			Type:          orderType,
+			Price:         price,
+			Quantity:      req.Quantity,
+			Filled:        model.Zero(),
+			Status:        model.OrderStatusSubmitted,
+			SubmitTime:    time.UnixMilli(int64(resp.TransactionTime)),
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+		for _, o := range resp.Orders {
+			if o.ClientOrderId == clientOrderID {
+				order.ExchangeID = strconv.FormatInt(o.OrderId, 10)
+			}
+		}
+		for _, r := range resp.OrderReports {
+			if r.ClientOrderId == clientOrderID {
+				order.Status = c.convertOrderStatus(r.Status)
+				order.Filled = model.NewMoneyFromFloat(r.ExecutedQty)
+			}
+		}
+		return order
+	}
+
+	stopType := model.OrderTypeStopMarket
+	if !req.StopLimitPrice.IsZero() {
+		stopType = model.OrderTypeStopLimit
+	}
+	stopLoss := leg(req.StopLossClientOrderID, req.StopLimitPrice, stopType)
+	stopLoss.StopPrice = req.StopPrice
+	return &port.SpotOCOResult{
+		ExchangeListID: strconv.FormatInt(resp.OrderListId, 10),
+		TakeProfit:     leg(req.TakeProfitClientOrderID, req.TakeProfitPrice, model.OrderTypeLimit),
+		StopLoss:       stopLoss,
+	}
+}
+
 // GetOrder 查询订单
 func (c *SpotClient) GetOrder(ctx context.Context, clientOrderID string) (*model.Order, error) {
-	// Binance 需要 symbol，但我们的接口设计只传 clientOrderID
-	// 这是一个设计缺陷，需要改进接口或在订单ID中编码symbol
-	// 临时方案：从 clientOrderID 解析 symbol（假设格式: SYMBOL-UUID）
-	symbol := c.extractSymbolFromOrderID(clientOrderID)
+	// Binance 查单需要 symbol，从客户端订单 ID 解码（见 model.ClientOrderID）
+	symbol := model.SymbolFromClientOrderID(clientOrderID)
 	if symbol == "" {
 		return nil, fmt.Errorf("cannot extract symbol from clientOrderID: %s", clientOrderID)
 	}
@@ -115,6 +320,9 @@ func (c *SpotClient) GetOrder(ctx context.Context, clientOrderID string) (*model
 		OrigClientOrderId(clientOrderID).
 		Do(ctx)
 	if err != nil {
+		if apiErrorCode(err) == errCodeNoSuchOrder {
+			return nil, fmt.Errorf("binance get order failed: %w: %v", port.ErrExchangeOrderNotFound, err)
+		}
 		return nil, fmt.Errorf("binance get order failed: %w", err)
 	}
 
@@ -183,6 +391,121 @@ func (c *SpotClient) GetAllBalances(ctx context.Context) ([]*port.SpotBalance, e
 	return balances, nil
 }
 
+// ListOpenOrders 查询未完结订单（symbol 为空时查询全部交易对）
+func (c *SpotClient) ListOpenOrders(ctx context.Context, symbol string) ([]*model.Order, error) {
+	service := c.client.NewGetOpenOrdersService()
+	if symbol != "" {
+		service = service.Symbol(symbol)
+	}
+	resp, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("binance get open orders failed: %w", err)
+	}
+
+	orders := make([]*model.Order, 0, len(resp))
+	for _, o := range resp {
+		orders = append(orders, c.convertOpenOrder(o))
+	}
+	return orders, nil
+}
+
+// ListTrades 查询 since 之后的成交明细（myTrades 不返回客户端订单 ID，仅填充交易所订单 ID）
+func (c *SpotClient) ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error) {
+	resp, err := c.client.NewGetMyTradesService().
+		Symbol(symbol).
+		StartTime(uint64(since.UnixMilli())).
+		Limit(1000).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("binance get my trades failed: %w", err)
+	}
+
+	trades := make([]*model.Trade, 0, len(resp))
+	for _, t := range resp {
+		side := model.OrderSideSell
+		if t.IsBuyer {
+			side = model.OrderSideBuy
+		}
+		trades = append(trades, &model.Trade{
+			ExecID:          strconv.FormatInt(t.Id, 10),
+			ExchangeOrderID: strconv.FormatInt(t.OrderId, 10),
+			Symbol:          t.Symbol,
+			Side:            side,
+			Price:           model.MustMoney(t.Price),
+			Quantity:        model.MustMoney(t.Quantity),
+			QuoteQty:        model.MustMoney(t.QuoteQuantity),
+			Fee:             model.MustMoney(t.Commission),
+			FeeAsset:        t.CommissionAsset,
+			TradedAt:        time.UnixMilli(int64(t.Time)),
+		})
+	}
+	return trades, nil
+}
+
+// Depth 1% 价格范围内的盘口深度（实现 execution.DepthSource）
+// 卖出按买盘、买入按卖盘累计最优价 1% 范围内的挂单数量
+func (c *SpotClient) Depth(ctx context.Context, symbol string, side model.OrderSide) (model.Money, error) {
+	resp, err := c.client.NewOrderBookService().
+		Symbol(symbol).
+		Limit(500).
+		Do(ctx)
+	if err != nil {
+		return model.Zero(), fmt.Errorf("get order book failed: %w", err)
+	}
+
+	levels, bound := resp.Asks, model.MustMoney("1.01")
+	if side == model.OrderSideSell {
+		levels, bound = resp.Bids, model.MustMoney("0.99")
+	}
+
+	depth, limit := model.Zero(), model.Zero()
+	for _, level := range levels {
+		if len(level) < 2 || level[0] == nil || level[1] == nil {
+			continue
+		}
+		price, err := model.NewMoney(level[0].Text('f', -1))
+		if err != nil {
+			return model.Zero(), fmt.Errorf("parse order book price failed: %w", err)
+		}
+		qty, err := model.NewMoney(level[1].Text('f', -1))
+		if err != nil {
+			return model.Zero(), fmt.Errorf("parse order book quantity failed: %w", err)
+		}
+		if limit.IsZero() {
+			limit = price.Mul(bound)
+		}
+		if (side == model.OrderSideSell && price.LT(limit)) || (side != model.OrderSideSell && price.GT(limit)) {
+			break
+		}
+		depth = depth.Add(qty)
+	}
+	return depth, nil
+}
+
+// isOutcomeUnknown 下单结果是否未知
+// 网络错误与超时（请求可能已送达）、-1006/-1007 以及无法解析错误码的 5xx 响应均视为未知；其余 API 错误为明确拒绝
+func isOutcomeUnknown(err error) bool {
+	var apiErr *handlers.APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	switch apiErr.Code {
+	case 0, errCodeUnexpectedResp, errCodeTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// apiErrorCode 提取 Binance API 错误码（非 API 错误返回 0）
+func apiErrorCode(err error) int64 {
+	var apiErr *handlers.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
 // convertOrderType 转换订单类型
 func (c *SpotClient) convertOrderType(t model.OrderType) string {
 	switch t {
@@ -194,11 +517,26 @@ func (c *SpotClient) convertOrderType(t model.OrderType) string {
 		return "LIMIT" // IOC 通过 TimeInForce 实现
 	case model.OrderTypeFOK:
 		return "LIMIT" // FOK 通过 TimeInForce 实现
+	case model.OrderTypeStopMarket, model.OrderTypeStopLimit, model.OrderTypeTakeProfit, model.OrderTypeTakeProfitLimit:
+		return t.String() // STOP_LOSS、STOP_LOSS_LIMIT、TAKE_PROFIT、TAKE_PROFIT_LIMIT
 	default:
 		return "LIMIT"
 	}
 }
 
+// convertTimeInForce 限价类订单的有效方式
+// 现货不支持 GTD，挂单截止时间与最长挂单时长由 OMS 同步时撤单执行，交易所侧按 GTC 挂出
+func (c *SpotClient) convertTimeInForce(t model.OrderType) string {
+	switch t {
+	case model.OrderTypeIOC:
+		return "IOC"
+	case model.OrderTypeFOK:
+		return "FOK"
+	default:
+		return "GTC"
+	}
+}
+
 // convertSide 转换买卖方向
 func (c *SpotClient) convertSide(side model.OrderSide) string {
 	if side == model.OrderSideBuy {
@@ -207,58 +545,145 @@ func (c *SpotClient) convertSide(side model.OrderSide) string {
 	return "SELL"
 }
 
+// orderResponse 下单与查单响应的公共字段
+type orderResponse struct {
+	OrderID       int64
+	ClientOrderID string
+	Symbol        string
+	Status        string
+	Side          string
+	Type          string
+	Price         string
+	StopPrice     string
+	OrigQty       string
+	ExecutedQty   string
+	UpdateTime    uint64 // 交易所最后更新时间（毫秒，用于丢弃乱序回报；下单响应为撮合时间）
+}
+
 // convertOrderResponse 转换订单响应
+// 连接器按请求返回不同的结构体指针：下单为 CreateOrderResponseACK / RESULT / FULL，查单为 GetOrderResponse。
+// ACK 只含订单标识，其余字段为空（状态按 PENDING 处理，由后续同步补全）
 func (c *SpotClient) convertOrderResponse(data interface{}) *model.Order {
-	// binance_connector 返回的是结构化数据
-	// 使用类型断言获取字段
-	var orderID int64
-	var clientOrderID, symbol, status, side, price, origQty, executedQty string
-
-	// 使用反射或 map 方式解析（这里简化为 map）
-	if orderMap, ok := data.(map[string]interface{}); ok {
-		if oid, ok := orderMap["orderId"].(float64); ok {
-			orderID = int64(oid)
+	var resp orderResponse
+	switch r := data.(type) {
+	case *binance_connector.CreateOrderResponseACK:
+		resp = orderResponse{
+			OrderID:       r.OrderId,
+			ClientOrderID: r.ClientOrderId,
+			Symbol:        r.Symbol,
+			UpdateTime:    r.TransactTime,
+		}
+	case *binance_connector.CreateOrderResponseRESULT:
+		resp = orderResponse{
+			OrderID:       r.OrderId,
+			ClientOrderID: r.ClientOrderId,
+			Symbol:        r.Symbol,
+			Status:        r.Status,
+			Side:          r.Side,
+			Type:          r.Type,
+			Price:         r.Price,
+			StopPrice:     r.StopPrice,
+			OrigQty:       r.OrigQty,
+			ExecutedQty:   r.ExecutedQty,
+			UpdateTime:    r.TransactTime,
+		}
+	case *binance_connector.CreateOrderResponseFULL:
+		resp = orderResponse{
+			OrderID:       r.OrderId,
+			ClientOrderID: r.ClientOrderId,
+			Symbol:        r.Symbol,
+			Status:        r.Status,
+			Side:          r.Side,
+			Type:          r.Type,
+			Price:         r.Price,
+			StopPrice:     r.StopPrice,
+			OrigQty:       r.OrigQty,
+			ExecutedQty:   r.ExecutedQty,
+			UpdateTime:    r.TransactTime,
+		}
+	case *binance_connector.GetOrderResponse:
+		resp = orderResponse{
+			OrderID:       r.OrderId,
+			ClientOrderID: r.ClientOrderId,
+			Symbol:        r.Symbol,
+			Status:        r.Status,
+			Side:          r.Side,
+			Type:          r.Type,
+			Price:         r.Price,
+			StopPrice:     r.StopPrice,
+			OrigQty:       r.OrigQty,
+			ExecutedQty:   r.ExecutedQty,
+			UpdateTime:    r.UpdateTime,
 		}
-		clientOrderID, _ = orderMap["clientOrderId"].(string)
-		symbol, _ = orderMap["symbol"].(string)
-		status, _ = orderMap["status"].(string)
-		side, _ = orderMap["side"].(string)
-		price, _ = orderMap["price"].(string)
-		origQty, _ = orderMap["origQty"].(string)
-		executedQty, _ = orderMap["executedQty"].(string)
 	}
 
 	order := &model.Order{
-		ClientOrderID: clientOrderID,
-		ExchangeID:    strconv.FormatInt(orderID, 10),
-		Symbol:        symbol,
+		ClientOrderID: resp.ClientOrderID,
+		ExchangeID:    strconv.FormatInt(resp.OrderID, 10),
+		Symbol:        resp.Symbol,
 		MarketType:    model.MarketTypeSpot,
-		Status:        c.convertOrderStatus(status),
+		Type:          model.ParseOrderType(resp.Type),
+		Status:        c.convertOrderStatus(resp.Status),
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	// 解析买卖方向
-	if side == "BUY" {
+	if resp.UpdateTime > 0 {
+		order.ExchangeUpdateTime = time.UnixMilli(int64(resp.UpdateTime))
+	}
+
+	// 解析买卖方向（ACK 不含方向）
+	switch resp.Side {
+	case "BUY":
 		order.Side = model.OrderSideBuy
-	} else {
+	case "SELL":
 		order.Side = model.OrderSideSell
 	}
 
 	// 解析数量和价格
-	if price != "" {
-		order.Price = model.MustMoney(price)
+	if resp.Price != "" {
+		order.Price = model.MustMoney(resp.Price)
+	}
+	if resp.StopPrice != "" {
+		order.StopPrice = model.MustMoney(resp.StopPrice)
 	}
-	if origQty != "" {
-		order.Quantity = model.MustMoney(origQty)
+	if resp.OrigQty != "" {
+		order.Quantity = model.MustMoney(resp.OrigQty)
 	}
-	if executedQty != "" {
-		order.Filled = model.MustMoney(executedQty)
+	if resp.ExecutedQty != "" {
+		order.Filled = model.MustMoney(resp.ExecutedQty)
 	}
 
 	return order
 }
 
+// convertOpenOrder 转换未完结订单
+func (c *SpotClient) convertOpenOrder(o *binance_connector.NewOpenOrdersResponse) *model.Order {
+	order := &model.Order{
+		ClientOrderID:      o.ClientOrderId,
+		ExchangeID:         strconv.FormatInt(o.OrderId, 10),
+		Symbol:             o.Symbol,
+		MarketType:         model.MarketTypeSpot,
+		Side:               model.ParseOrderSide(o.Side),
+		Type:               model.ParseOrderType(o.Type),
+		Price:              model.MustMoney(o.Price),
+		Quantity:           model.MustMoney(o.OrigQty),
+		Filled:             model.MustMoney(o.ExecutedQty),
+		Status:             c.convertOrderStatus(o.Status),
+		SubmitTime:         time.UnixMilli(int64(o.Time)),
+		ExchangeUpdateTime: time.UnixMilli(int64(o.UpdateTime)),
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+	if order.Type == 0 {
+		order.Type = model.OrderTypeLimit // 未识别的类型按限价单处理
+	}
+	if o.StopPrice != "" {
+		order.StopPrice = model.MustMoney(o.StopPrice)
+	}
+	return order
+}
+
 // convertOrderStatus 转换订单状态
 func (c *SpotClient) convertOrderStatus(status string) model.OrderStatus {
 	switch status {
@@ -279,19 +704,8 @@ func (c *SpotClient) convertOrderStatus(status string) model.OrderStatus {
 	}
 }
 
-// extractSymbolFromOrderID 从订单ID提取交易对
-// 假设订单ID格式: BTCUSDT-uuid 或 uuid-BTCUSDT
-func (c *SpotClient) extractSymbolFromOrderID(orderID string) string {
-	// 简化实现：假设前缀是 symbol
-	// 实际应该有更健壮的解析逻辑
-	if len(orderID) > 7 && orderID[0:3] != "" {
-		// 尝试提取前缀
-		for i := 0; i < len(orderID); i++ {
-			if orderID[i] == '-' {
-				return orderID[0:i]
-			}
-		}
-	}
-	return ""
-}
-
+// 确保 SpotClient 实现了 SpotGateway 与原生 OCO 接口
+var (
+	_ port.SpotGateway    = (*SpotClient)(nil)
+	_ port.SpotOCOGateway = (*SpotClient)(nil)
+)
@@ -2,10 +2,16 @@ package binance
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"testing"
 
+	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/binance/binance-connector-go/handlers"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
 // TestBinanceSpotClient_Integration 集成测试（需要真实 API Key）
@@ -60,6 +66,10 @@ func TestConvertOrderType(t *testing.T) {
 		{model.OrderTypeLimit, "LIMIT"},
 		{model.OrderTypeIOC, "LIMIT"},
 		{model.OrderTypeFOK, "LIMIT"},
+		{model.OrderTypeStopMarket, "STOP_LOSS"},
+		{model.OrderTypeStopLimit, "STOP_LOSS_LIMIT"},
+		{model.OrderTypeTakeProfit, "TAKE_PROFIT"},
+		{model.OrderTypeTakeProfitLimit, "TAKE_PROFIT_LIMIT"},
 	}
 
 	for _, tt := range tests {
@@ -72,6 +82,29 @@ func TestConvertOrderType(t *testing.T) {
 	}
 }
 
+func TestConvertTimeInForce(t *testing.T) {
+	client := &SpotClient{}
+
+	tests := []struct {
+		input model.OrderType
+		want  string
+	}{
+		{model.OrderTypeLimit, "GTC"},
+		{model.OrderTypeIOC, "IOC"},
+		{model.OrderTypeFOK, "FOK"},
+		{model.OrderTypeStopLimit, "GTC"},
+		{model.OrderTypeTakeProfitLimit, "GTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input.String(), func(t *testing.T) {
+			if got := client.convertTimeInForce(tt.input); got != tt.want {
+				t.Errorf("convertTimeInForce() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConvertSide(t *testing.T) {
 	client := &SpotClient{}
 
@@ -119,25 +152,210 @@ func TestConvertOrderStatus(t *testing.T) {
 	}
 }
 
-func TestExtractSymbolFromOrderID(t *testing.T) {
+func TestIsOutcomeUnknown(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", context.DeadlineExceeded, true},
+		{"backend timeout", &handlers.APIError{Code: errCodeTimeout}, true},
+		{"unexpected response", &handlers.APIError{Code: errCodeUnexpectedResp}, true},
+		{"unparsed 5xx", &handlers.APIError{}, true},
+		{"insufficient balance", &handlers.APIError{Code: -2010, Message: "Account has insufficient balance"}, false},
+		{"wrapped rejection", fmt.Errorf("wrap: %w", &handlers.APIError{Code: -1013}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOutcomeUnknown(tt.err); got != tt.want {
+				t.Errorf("isOutcomeUnknown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertCancelReplaceResponse(t *testing.T) {
+	client := &SpotClient{}
+
+	decode := func(t *testing.T, body string) *binance_connector.CancelReplaceResponse {
+		t.Helper()
+		resp := new(binance_connector.CancelReplaceResponse)
+		if err := json.Unmarshal([]byte(body), resp); err != nil {
+			t.Fatalf("unmarshal failed: %v", err)
+		}
+		return resp
+	}
+
+	t.Run("success", func(t *testing.T) {
+		resp := decode(t, `{"cancelResult":"SUCCESS","newOrderResult":"SUCCESS",
+			"cancelResponse":{"symbol":"BTCUSDT","origClientOrderId":"old","orderId":1,"price":"50000","origQty":"0.02","executedQty":"0.005","status":"CANCELED","side":"BUY"},
+			"newOrderResponse":{"symbol":"BTCUSDT","orderId":2,"clientOrderId":"new","transactTime":1700000000000,"price":"49900","origQty":"0.015","executedQty":"0","status":"NEW","type":"LIMIT","side":"BUY"}}`)
+		result, err := client.convertCancelReplaceResponse(resp)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Cancelled.Status != model.OrderStatusCancelled || result.Cancelled.Filled.String() != "0.005" {
+			t.Errorf("cancelled = %s filled %s", result.Cancelled.Status, result.Cancelled.Filled)
+		}
+		if result.Order.ClientOrderID != "new" || result.Order.ExchangeID != "2" || result.Order.Status != model.OrderStatusSubmitted {
+			t.Errorf("new order = %+v", result.Order)
+		}
+	})
+
+	t.Run("cancel failed", func(t *testing.T) {
+		resp := decode(t, `{"code":-2022,"msg":"Order cancel-replace failed.",
+			"data":{"cancelResult":"FAILURE","newOrderResult":"NOT_ATTEMPTED",
+			"cancelResponse":{"code":-2011,"msg":"Unknown order sent."},"newOrderResponse":null}}`)
+		result, err := client.convertCancelReplaceResponse(resp)
+		if !errors.Is(err, port.ErrAmendCancelFailed) || result != nil {
+			t.Errorf("expected ErrAmendCancelFailed, got %v", err)
+		}
+	})
+
+	t.Run("new order rejected", func(t *testing.T) {
+		resp := decode(t, `{"code":-2021,"msg":"Order cancel-replace partially failed.",
+			"data":{"cancelResult":"SUCCESS","newOrderResult":"FAILURE",
+			"cancelResponse":{"symbol":"BTCUSDT","origClientOrderId":"old","orderId":1,"price":"50000","origQty":"0.02","executedQty":"0","status":"CANCELED","side":"BUY"},
+			"newOrderResponse":{"code":-2010,"msg":"Account has insufficient balance for requested action."}}}`)
+		result, err := client.convertCancelReplaceResponse(resp)
+		if !errors.Is(err, port.ErrAmendNewOrderRejected) {
+			t.Fatalf("expected ErrAmendNewOrderRejected, got %v", err)
+		}
+		if result.Cancelled == nil || result.Cancelled.ClientOrderID != "old" || result.Order != nil {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+}
+
+func TestConvertOCOResponse(t *testing.T) {
+	client := &SpotClient{}
+	req := &port.SpotOCORequest{
+		ListClientOrderID:       "list",
+		Symbol:                  "BTCUSDT",
+		Side:                    model.OrderSideSell,
+		Quantity:                model.MustMoney("0.1"),
+		TakeProfitClientOrderID: "tp",
+		TakeProfitPrice:         model.MustMoney("52000"),
+		StopLossClientOrderID:   "sl",
+		StopPrice:               model.MustMoney("49000"),
+	}
+
+	resp := new(binance_connector.OrderOCOResponse)
+	body := `{"orderListId":7,"contingencyType":"OCO","listStatusType":"EXEC_STARTED","listOrderStatus":"EXECUTING",
+		"listClientOrderId":"list","transactionTime":1700000000000,"symbol":"BTCUSDT",
+		"orders":[{"symbol":"BTCUSDT","orderId":11,"clientOrderId":"sl"},{"symbol":"BTCUSDT","orderId":12,"clientOrderId":"tp"}]}`
+	if err := json.Unmarshal([]byte(body), resp); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	result := client.convertOCOResponse(resp, req)
+	if result.ExchangeListID != "7" {
+		t.Errorf("ExchangeListID = %s, want 7", result.ExchangeListID)
+	}
+	if result.TakeProfit.ExchangeID != "12" || result.TakeProfit.Type != model.OrderTypeLimit || !result.TakeProfit.Price.EQ(req.TakeProfitPrice) {
+		t.Errorf("take profit leg = %+v", result.TakeProfit)
+	}
+	// 未设置止损限价：止损腿按市价止损单记录
+	if result.StopLoss.ExchangeID != "11" || result.StopLoss.Type != model.OrderTypeStopMarket || result.StopLoss.Status != model.OrderStatusSubmitted {
+		t.Errorf("stop loss leg = %+v", result.StopLoss)
+	}
+	if !result.StopLoss.StopPrice.EQ(req.StopPrice) {
+		t.Errorf("stop loss leg = %+v", result.StopLoss)
+	}
+}
+
+func TestConvertOrderResponse(t *testing.T) {
+	client := &SpotClient{}
+
+	resp := new(binance_connector.GetOrderResponse)
+	body := `{"symbol":"BTCUSDT","orderId":21,"clientOrderId":"sl","price":"48900.00","origQty":"0.10","executedQty":"0.00",
+		"status":"NEW","timeInForce":"GTC","type":"STOP_LOSS_LIMIT","side":"SELL","stopPrice":"49000.00","updateTime":1700000000000}`
+	if err := json.Unmarshal([]byte(body), resp); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	order := client.convertOrderResponse(resp)
+	if order.ClientOrderID != "sl" || order.ExchangeID != "21" || order.Symbol != "BTCUSDT" {
+		t.Errorf("unexpected identity: %+v", order)
+	}
+	if order.Type != model.OrderTypeStopLimit || order.Side != model.OrderSideSell || order.Status != model.OrderStatusSubmitted {
+		t.Errorf("unexpected type/side/status: %+v", order)
+	}
+	if !order.Price.EQ(model.MustMoney("48900")) || !order.StopPrice.EQ(model.MustMoney("49000")) || !order.Quantity.EQ(model.MustMoney("0.1")) {
+		t.Errorf("unexpected prices: price=%s stop=%s qty=%s", order.Price, order.StopPrice, order.Quantity)
+	}
+	if order.ExchangeUpdateTime.UnixMilli() != 1700000000000 {
+		t.Errorf("ExchangeUpdateTime = %v", order.ExchangeUpdateTime)
+	}
+}
+
+// TestConvertOrderResponse_CreateOrder 下单响应为连接器的结构体指针（曾按 map 断言解析，交易所订单 ID 与状态全部丢失）
+func TestConvertOrderResponse_CreateOrder(t *testing.T) {
 	client := &SpotClient{}
+	body := `{"symbol":"BTCUSDT","orderId":28,"orderListId":-1,"clientOrderId":"BE0KQ5Z3XC001F7Q3F0KBTCUSDT","transactTime":1700000000123,
+		"price":"0.00000000","origQty":"0.01000000","executedQty":"0.01000000","cummulativeQuoteQty":"500.00000000",
+		"status":"FILLED","timeInForce":"GTC","type":"MARKET","side":"BUY","workingTime":1700000000123,"selfTradePreventionMode":"NONE",
+		"fills":[{"price":"50000.00","qty":"0.01","commission":"0.00001","commissionAsset":"BTC","tradeId":7}]}`
+
+	tests := []struct {
+		name       string
+		resp       interface{}
+		wantStatus model.OrderStatus
+		wantSide   model.OrderSide
+	}{
+		{"full", new(binance_connector.CreateOrderResponseFULL), model.OrderStatusFilled, model.OrderSideBuy},
+		{"result", new(binance_connector.CreateOrderResponseRESULT), model.OrderStatusFilled, model.OrderSideBuy},
+		{"ack", new(binance_connector.CreateOrderResponseACK), model.OrderStatusPending, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(body), tt.resp); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+
+			order := client.convertOrderResponse(tt.resp)
+			if order.ExchangeID != "28" || order.ClientOrderID != "BE0KQ5Z3XC001F7Q3F0KBTCUSDT" || order.Symbol != "BTCUSDT" {
+				t.Errorf("unexpected identity: %+v", order)
+			}
+			if order.Status != tt.wantStatus || order.Side != tt.wantSide {
+				t.Errorf("status/side = %s/%s, want %s/%s", order.Status, order.Side, tt.wantStatus, tt.wantSide)
+			}
+			if order.ExchangeUpdateTime.UnixMilli() != 1700000000123 {
+				t.Errorf("ExchangeUpdateTime = %v", order.ExchangeUpdateTime)
+			}
+			if tt.wantStatus == model.OrderStatusFilled && (!order.Quantity.EQ(model.MustMoney("0.01")) || !order.Filled.EQ(model.MustMoney("0.01"))) {
+				t.Errorf("quantity/filled = %s/%s", order.Quantity, order.Filled)
+			}
+		})
+	}
+}
+
+func TestSymbolFromClientOrderID(t *testing.T) {
+	encoded, err := model.NewClientOrderID(model.OrderIntentEntry, "ma_cross", "BTCUSDT")
+	if err != nil {
+		t.Fatalf("NewClientOrderID failed: %v", err)
+	}
+	if len(encoded) > model.BinanceClientOrderIDMaxLength {
+		t.Fatalf("client order id %s exceeds binance limit", encoded)
+	}
 
 	tests := []struct {
 		name    string
 		orderID string
 		want    string
 	}{
-		{"with symbol prefix", "BTCUSDT-uuid-123", "BTCUSDT"},
-		{"short symbol", "BTC-uuid-456", "BTC"},
+		{"encoded", encoded, "BTCUSDT"},
+		{"legacy uuid suffix", "0190f1c2-7a6b-7c3d-8e9f-0a1b2c3d4e5f-ETHUSDT", "ETHUSDT"},
 		{"no separator", "BTCUSDT123", ""},
 		{"empty", "", ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := client.extractSymbolFromOrderID(tt.orderID)
+			got := model.SymbolFromClientOrderID(tt.orderID)
 			if got != tt.want {
-				t.Errorf("extractSymbolFromOrderID() = %v, want %v", got, tt.want)
+				t.Errorf("SymbolFromClientOrderID() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -13,12 +13,21 @@ import (
 	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
+// MarkPriceSource 止损使用的标记价格来源
+type MarkPriceSource string
+
+const (
+	MarkPriceBookTicker MarkPriceSource = "book_ticker"  // 现货最优买卖价中间价（bookTicker，实时推送）
+	MarkPriceFutures    MarkPriceSource = "futures_mark" // U 本位合约标记价格（markPrice@1s）
+)
+
 // WSClient Binance WebSocket 客户端 (实现 port.MarketDataRepo)
 type WSClient struct {
-	baseURL string // wss://stream.binance.com:9443
-	client  *SpotClient
-	mu      sync.RWMutex
-	conns   map[string]*wsConn // stream -> connection
+	baseURL        string // wss://stream.binance.com:9443
+	futuresBaseURL string // wss://fstream.binance.com（标记价格）
+	client         *SpotClient
+	mu             sync.RWMutex
+	conns          map[string]*wsConn // stream -> connection
 }
 
 // wsConn WebSocket 连接封装
@@ -33,14 +42,17 @@ type wsConn struct {
 // NewWSClient 创建 WebSocket 客户端
 func NewWSClient(cfg Config) *WSClient {
 	baseURL := "wss://stream.binance.com:9443"
+	futuresBaseURL := "wss://fstream.binance.com"
 	if cfg.Testnet {
 		baseURL = "wss://testnet.binance.vision"
+		futuresBaseURL = "wss://stream.binancefuture.com"
 	}
 
 	return &WSClient{
-		baseURL: baseURL,
-		client:  NewSpotClient(cfg),
-		conns:   make(map[string]*wsConn),
+		baseURL:        baseURL,
+		futuresBaseURL: futuresBaseURL,
+		client:         NewSpotClient(cfg),
+		conns:          make(map[string]*wsConn),
 	}
 }
 
@@ -78,6 +90,36 @@ func (c *WSClient) SubscribeKLines(ctx context.Context, symbols []string, interv
 	return ch, nil
 }
 
+// SubscribeMarkPrices 订阅标记价格（止损判定使用）
+// 现货按 bookTicker 中间价推送；合约标记价格每秒推送一次
+func (c *WSClient) SubscribeMarkPrices(ctx context.Context, symbols []string, source MarkPriceSource) (<-chan *model.MarkPrice, error) {
+	var (
+		baseURL string
+		suffix  string
+		parse   func(data []byte) *model.MarkPrice
+	)
+	switch source {
+	case MarkPriceBookTicker:
+		baseURL, suffix, parse = c.baseURL, "@bookTicker", c.parseBookTickerMessage
+	case MarkPriceFutures:
+		baseURL, suffix, parse = c.futuresBaseURL, "@markPrice@1s", c.parseMarkPriceMessage
+	default:
+		return nil, fmt.Errorf("unsupported mark price source: %s", source)
+	}
+
+	ch := make(chan *model.MarkPrice, 100)
+	conn, err := c.dialURL(ctx, baseURL, c.buildStream(symbols, suffix))
+	if err != nil {
+		close(ch)
+		return nil, fmt.Errorf("dial websocket failed: %w", err)
+	}
+
+	// 启动消息读取协程
+	go c.readMarkPriceMessages(conn, ch, parse)
+
+	return ch, nil
+}
+
 // GetHistoricalKLines 拉取历史 K线
 func (c *WSClient) GetHistoricalKLines(ctx context.Context, symbol string, interval string, startTime, endTime int64) ([]*model.Candle, error) {
 	// 使用 binance-connector-go 的 KLines API (时间戳转 uint64)
@@ -131,8 +173,13 @@ func (c *WSClient) GetLatestPrice(ctx context.Context, symbol string) (model.Mon
 	return model.Zero(), fmt.Errorf("empty ticker price response")
 }
 
-// dial 建立 WebSocket 连接
+// dial 建立现货 WebSocket 连接
 func (c *WSClient) dial(ctx context.Context, stream string) (*wsConn, error) {
+	return c.dialURL(ctx, c.baseURL, stream)
+}
+
+// dialURL 建立 WebSocket 连接
+func (c *WSClient) dialURL(ctx context.Context, baseURL, stream string) (*wsConn, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -142,7 +189,7 @@ func (c *WSClient) dial(ctx context.Context, stream string) (*wsConn, error) {
 	}
 
 	// 建立新连接
-	url := fmt.Sprintf("%s/ws/%s", c.baseURL, stream)
+	url := fmt.Sprintf("%s/ws/%s", baseURL, stream)
 	dialer := websocket.DefaultDialer
 	dialer.HandshakeTimeout = 10 * time.Second
 
@@ -249,6 +296,33 @@ func (c *WSClient) readKlineMessages(conn *wsConn, ch chan<- *model.Candle) {
 	}
 }
 
+// readMarkPriceMessages 读取标记价格消息
+func (c *WSClient) readMarkPriceMessages(conn *wsConn, ch chan<- *model.MarkPrice, parse func(data []byte) *model.MarkPrice) {
+	defer close(ch)
+	defer conn.conn.Close()
+
+	for {
+		select {
+		case <-conn.ctx.Done():
+			return
+		default:
+			_, message, err := conn.conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			mark := parse(message)
+			if mark != nil {
+				select {
+				case ch <- mark:
+				case <-conn.ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
 // parseTickMessage 解析 Tick 消息
 func (c *WSClient) parseTickMessage(data []byte) *model.Tick {
 	var msg struct {
@@ -323,6 +397,81 @@ func (c *WSClient) parseKlineMessage(data []byte) *model.Candle {
 	}
 }
 
+// parseBookTickerMessage 解析现货 bookTicker 消息（按最优买卖价中间价计算标记价格）
+func (c *WSClient) parseBookTickerMessage(data []byte) *model.MarkPrice {
+	var msg struct {
+		UpdateID int64  `json:"u"` // Order book updateId
+		Symbol   string `json:"s"` // BTCUSDT
+		BidPrice string `json:"b"` // Best bid price
+		BidQty   string `json:"B"` // Best bid qty（需显式声明，否则大小写不敏感匹配会覆盖 b）
+		AskPrice string `json:"a"` // Best ask price
+		AskQty   string `json:"A"` // Best ask qty
+	}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil
+	}
+	if msg.Symbol == "" || msg.BidPrice == "" || msg.AskPrice == "" {
+		return nil
+	}
+
+	bid, err := model.NewMoney(msg.BidPrice)
+	if err != nil {
+		return nil
+	}
+	ask, err := model.NewMoney(msg.AskPrice)
+	if err != nil || !bid.IsPositive() || !ask.IsPositive() {
+		return nil
+	}
+
+	now := time.Now()
+	return &model.MarkPrice{
+		Symbol:    msg.Symbol,
+		Price:     bid.Add(ask).Div(model.MustMoney("2")),
+		EventTime: now, // 现货 bookTicker 不携带事件时间
+		RecvTime:  now,
+	}
+}
+
+// parseMarkPriceMessage 解析合约 markPriceUpdate 消息
+func (c *WSClient) parseMarkPriceMessage(data []byte) *model.MarkPrice {
+	var msg struct {
+		EventType   string `json:"e"` // "markPriceUpdate"
+		EventTime   int64  `json:"E"` // Event time
+		Symbol      string `json:"s"` // BTCUSDT
+		MarkPrice   string `json:"p"` // Mark price
+		SettlePrice string `json:"P"` // Estimated settle price（需显式声明，避免覆盖 p）
+	}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil
+	}
+	if msg.EventType != "markPriceUpdate" || msg.MarkPrice == "" {
+		return nil
+	}
+
+	price, err := model.NewMoney(msg.MarkPrice)
+	if err != nil || !price.IsPositive() {
+		return nil
+	}
+
+	return &model.MarkPrice{
+		Symbol:    msg.Symbol,
+		Price:     price,
+		EventTime: time.UnixMilli(msg.EventTime),
+		RecvTime:  time.Now(),
+	}
+}
+
+// buildStream 构建多个交易对的流名称（如 btcusdt@bookTicker/ethusdt@bookTicker）
+func (c *WSClient) buildStream(symbols []string, suffix string) string {
+	streams := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		streams[i] = strings.ToLower(symbol) + suffix
+	}
+	return strings.Join(streams, "/")
+}
+
 // buildTickStream 构建 Tick 流名称
 // 单个: btcusdt@trade
 // 多个: btcusdt@trade/ethusdt@trade/bnbusdt@trade
@@ -254,6 +254,56 @@ func TestParseKlineMessage(t *testing.T) {
 	}
 }
 
+// TestParseMarkPriceMessages 测试解析标记价格消息
+func TestParseMarkPriceMessages(t *testing.T) {
+	client := NewWSClient(Config{})
+
+	// 现货 bookTicker：取买一卖一中间价
+	mark := client.parseBookTickerMessage([]byte(`{
+		"u": 400900217,
+		"s": "BTCUSDT",
+		"b": "16500.00",
+		"B": "1.5",
+		"a": "16501.00",
+		"A": "2.0"
+	}`))
+	if mark == nil {
+		t.Fatal("parseBookTickerMessage returned nil")
+	}
+	if mark.Symbol != "BTCUSDT" || mark.Price.String() != "16500.5" {
+		t.Errorf("bookTicker mark = %s @ %s, want BTCUSDT @ 16500.5", mark.Symbol, mark.Price)
+	}
+
+	// 合约 markPriceUpdate
+	mark = client.parseMarkPriceMessage([]byte(`{
+		"e": "markPriceUpdate",
+		"E": 1672531200000,
+		"s": "BTCUSDT",
+		"p": "16499.80",
+		"i": "16500.10",
+		"P": "16480.00",
+		"r": "0.00010000",
+		"T": 1672560000000
+	}`))
+	if mark == nil {
+		t.Fatal("parseMarkPriceMessage returned nil")
+	}
+	if mark.Price.String() != "16499.8" {
+		t.Errorf("mark price = %s, want 16499.8", mark.Price)
+	}
+	if !mark.EventTime.Equal(time.UnixMilli(1672531200000)) {
+		t.Errorf("EventTime = %v", mark.EventTime)
+	}
+
+	// 非标记价格事件与缺失报价的消息被忽略
+	if client.parseMarkPriceMessage([]byte(`{"e":"trade","s":"BTCUSDT","p":"1"}`)) != nil {
+		t.Error("parseMarkPriceMessage should ignore non markPriceUpdate events")
+	}
+	if client.parseBookTickerMessage([]byte(`{"s":"BTCUSDT","b":"16500.00"}`)) != nil {
+		t.Error("parseBookTickerMessage should ignore messages without ask price")
+	}
+}
+
 // TestWSClient_GetHistoricalKLines 测试拉取历史 K线
 func TestWSClient_GetHistoricalKLines(t *testing.T) {
 	apiKey := os.Getenv("BINANCE_API_KEY")
@@ -124,7 +124,7 @@ func TestIntegration_RiskManager_MockExchange(t *testing.T) {
 		_ = riskRepo.RecordTrade(ctx, "test-account", model.MustMoney("-200"))
 
 		// 清除缓存（重要：让风控管理器重新加载状态）
-		riskMgr.InvalidateCache("test-account", "")
+		riskMgr.InvalidateCache(model.AccountScope("test-account"))
 
 		// 下一笔订单应该被熔断器阻止
 		orderReq := &risklogic.OrderContext{
@@ -3,6 +3,7 @@ package mock
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,6 +18,12 @@ type SpotExchange struct {
 	// 订单簿
 	orders map[string]*model.Order // key: ClientOrderID
 
+	// 已触发但尚未成交的止损/止盈限价单
+	triggered map[string]bool // key: ClientOrderID
+
+	// 成交明细（按成交顺序）
+	trades []*model.Trade
+
 	// 账户余额
 	balances map[string]*port.SpotBalance // key: Asset
 
@@ -50,6 +57,7 @@ func NewSpotExchange(initialBalance map[string]model.Money) *SpotExchange {
 
 	return &SpotExchange{
 		orders:        make(map[string]*model.Order),
+		triggered:     make(map[string]bool),
 		balances:      balances,
 		currentPrices: make(map[string]model.Money),
 		config: SpotExchangeConfig{
@@ -66,6 +74,11 @@ func (e *SpotExchange) PlaceOrder(ctx context.Context, req *port.SpotPlaceOrderR
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	return e.placeOrder(req)
+}
+
+// placeOrder 下单（调用方持有锁）
+func (e *SpotExchange) placeOrder(req *port.SpotPlaceOrderRequest) (*model.Order, error) {
 	// 检查订单是否已存在（幂等）
 	if existing, exists := e.orders[req.ClientOrderID]; exists {
 		return existing, nil
@@ -80,6 +93,7 @@ func (e *SpotExchange) PlaceOrder(ctx context.Context, req *port.SpotPlaceOrderR
 		Side:          req.Side,
 		Type:          req.Type,
 		Price:         req.Price,
+		StopPrice:     req.StopPrice,
 		Quantity:      req.Quantity,
 		Filled:        model.Zero(),
 		Status:        model.OrderStatusPending,
@@ -87,37 +101,65 @@ func (e *SpotExchange) PlaceOrder(ctx context.Context, req *port.SpotPlaceOrderR
 		UpdatedAt:     time.Now(),
 	}
 
-	// 模拟立即成交（回测模式）
+	// 条件单挂单等待触发（与交易所一致：按当前价会立即触发的条件单直接拒绝）
+	if order.Type.IsConditional() {
+		if order.StopPrice.IsZero() {
+			order.Status = model.OrderStatusRejected
+			e.orders[req.ClientOrderID] = order
+			return order, fmt.Errorf("%s order requires a stop price", order.Type)
+		}
+		if price, exists := e.currentPrices[order.Symbol]; exists && order.Triggered(price) {
+			order.Status = model.OrderStatusRejected
+			e.orders[req.ClientOrderID] = order
+			return order, fmt.Errorf("order would trigger immediately: stop %s, price %s", order.StopPrice, price)
+		}
+		order.Status = model.OrderStatusSubmitted
+		order.SubmitTime = time.Now()
+		e.orders[req.ClientOrderID] = order
+		return order, nil
+	}
+
+	// 模拟立即成交（回测模式），否则订单挂单等待
 	if e.config.InstantFill {
 		if err := e.fillOrder(order); err != nil {
 			order.Status = model.OrderStatusRejected
 			e.orders[req.ClientOrderID] = order
 			return order, err
 		}
+	} else {
+		order.Status = model.OrderStatusSubmitted
+		order.SubmitTime = time.Now()
 	}
 
 	e.orders[req.ClientOrderID] = order
 	return order, nil
 }
 
-// fillOrder 模拟订单成交
+// fillOrder 模拟订单成交（限价类订单按挂单价成交，其余按当前价加滑点成交）
 func (e *SpotExchange) fillOrder(order *model.Order) error {
 	// 获取成交价格
 	fillPrice := order.Price
-	if order.Type == model.OrderTypeMarket {
+	if !order.Type.HasLimitPrice() {
 		price, exists := e.currentPrices[order.Symbol]
 		if !exists {
 			return fmt.Errorf("no market price for %s", order.Symbol)
 		}
-		// 模拟滑点
-		slippage := price.Mul(e.config.Slippage)
-		if order.Side == model.OrderSideBuy {
-			fillPrice = price.Add(slippage)
-		} else {
-			fillPrice = price.Sub(slippage)
-		}
+		fillPrice = e.withSlippage(price, order.Side)
 	}
+	return e.fillOrderAt(order, fillPrice)
+}
 
+// withSlippage 按买卖方向施加不利滑点
+func (e *SpotExchange) withSlippage(price model.Money, side model.OrderSide) model.Money {
+	slippage := price.Mul(e.config.Slippage)
+	if side == model.OrderSideBuy {
+		return price.Add(slippage)
+	}
+	return price.Sub(slippage)
+}
+
+// fillOrderAt 按指定价格成交订单
+func (e *SpotExchange) fillOrderAt(order *model.Order, fillPrice model.Money) error {
 	// 解析交易对（简化：假设 BTCUSDT 格式）
 	baseAsset, quoteAsset := parseSymbol(order.Symbol)
 
@@ -158,12 +200,31 @@ func (e *SpotExchange) fillOrder(order *model.Order) error {
 		quoteBal.Total = quoteBal.Free.Add(quoteBal.Locked)
 	}
 
+	// 记录成交明细
+	quoteQty := fillPrice.Mul(order.Quantity)
+	e.trades = append(e.trades, &model.Trade{
+		ExecID:          fmt.Sprintf("MOCK-TRADE-%d", len(e.trades)+1),
+		ClientOrderID:   order.ClientOrderID,
+		ExchangeOrderID: order.ExchangeID,
+		Symbol:          order.Symbol,
+		Side:            order.Side,
+		Price:           fillPrice,
+		Quantity:        order.Quantity,
+		QuoteQty:        quoteQty,
+		Fee:             quoteQty.Mul(e.config.TakerFee),
+		FeeAsset:        quoteAsset,
+		TradedAt:        time.Now(),
+	})
+
 	// 更新订单状态
 	order.Filled = order.Quantity
 	order.Status = model.OrderStatusFilled
-	order.SubmitTime = time.Now()
+	if order.SubmitTime.IsZero() {
+		order.SubmitTime = time.Now()
+	}
 	order.FillTime = time.Now()
 	order.UpdatedAt = time.Now()
+	delete(e.triggered, order.ClientOrderID)
 
 	return nil
 }
@@ -184,9 +245,45 @@ func (e *SpotExchange) CancelOrder(ctx context.Context, req *port.SpotCancelOrde
 
 	order.Status = model.OrderStatusCancelled
 	order.UpdatedAt = time.Now()
+	delete(e.triggered, order.ClientOrderID)
 	return nil
 }
 
+// AmendOrder 改单（撤销原订单并提交新订单，原订单不存在或已完结时不提交新订单）
+func (e *SpotExchange) AmendOrder(ctx context.Context, req *port.SpotAmendOrderRequest) (*port.AmendOrderResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	original, exists := e.orders[req.ClientOrderID]
+	if !exists || original.IsClosed() {
+		return nil, fmt.Errorf("%w: order %s is not open", port.ErrAmendCancelFailed, req.ClientOrderID)
+	}
+	if _, exists := e.orders[req.NewClientOrderID]; exists {
+		return nil, fmt.Errorf("%w: duplicate client order id %s", port.ErrAmendCancelFailed, req.NewClientOrderID)
+	}
+
+	original.Status = model.OrderStatusCancelled
+	original.UpdatedAt = time.Now()
+	delete(e.triggered, original.ClientOrderID)
+	cancelled := *original
+	result := &port.AmendOrderResult{Cancelled: &cancelled}
+
+	order, err := e.placeOrder(&port.SpotPlaceOrderRequest{
+		ClientOrderID: req.NewClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Price:         req.Price,
+		StopPrice:     req.StopPrice,
+		Quantity:      req.Quantity,
+	})
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", port.ErrAmendNewOrderRejected, err)
+	}
+	result.Order = order
+	return result, nil
+}
+
 // GetOrder 查询订单
 func (e *SpotExchange) GetOrder(ctx context.Context, clientOrderID string) (*model.Order, error) {
 	e.mu.RLock()
@@ -194,7 +291,7 @@ func (e *SpotExchange) GetOrder(ctx context.Context, clientOrderID string) (*mod
 
 	order, exists := e.orders[clientOrderID]
 	if !exists {
-		return nil, fmt.Errorf("order %s not found", clientOrderID)
+		return nil, fmt.Errorf("%w: %s", port.ErrExchangeOrderNotFound, clientOrderID)
 	}
 
 	return order, nil
@@ -232,6 +329,57 @@ func (e *SpotExchange) GetAllBalances(ctx context.Context) ([]*port.SpotBalance,
 	return balances, nil
 }
 
+// ListOpenOrders 查询未完结订单（symbol 为空时查询全部交易对）
+func (e *SpotExchange) ListOpenOrders(ctx context.Context, symbol string) ([]*model.Order, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	orders := make([]*model.Order, 0)
+	for _, order := range e.orders {
+		if order.IsClosed() || (symbol != "" && order.Symbol != symbol) {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// ListTrades 查询 since 之后的成交明细
+func (e *SpotExchange) ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	trades := make([]*model.Trade, 0)
+	for _, trade := range e.trades {
+		if trade.Symbol == symbol && !trade.TradedAt.Before(since) {
+			trades = append(trades, trade)
+		}
+	}
+	return trades, nil
+}
+
+// SetInstantFill 设置是否立即成交（关闭后订单保持挂单状态，用于撤单、改单测试）
+func (e *SpotExchange) SetInstantFill(instant bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config.InstantFill = instant
+}
+
+// FillOrder 撮合挂单（模拟挂单按挂单价全部成交，用于止盈、止损等挂单测试）
+func (e *SpotExchange) FillOrder(clientOrderID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, exists := e.orders[clientOrderID]
+	if !exists {
+		return fmt.Errorf("%w: %s", port.ErrExchangeOrderNotFound, clientOrderID)
+	}
+	if order.IsClosed() {
+		return fmt.Errorf("order already closed")
+	}
+	return e.fillOrder(order)
+}
+
 // SetPrice 设置当前价格（回测用）
 func (e *SpotExchange) SetPrice(symbol string, price model.Money) {
 	e.mu.Lock()
@@ -239,6 +387,86 @@ func (e *SpotExchange) SetPrice(symbol string, price model.Money) {
 	e.currentPrices[symbol] = price
 }
 
+// ProcessCandle 按 K 线撮合挂单（回测用），返回本根 K 线内成交的订单
+// 当前价更新为收盘价，挂单按下单顺序撮合：
+//   - 条件单：最高价/最低价触及触发价时触发；开盘价已越过触发价（跳空）时按开盘价触发
+//   - 止损/止盈市价单：触发后按触发价（跳空时为开盘价）加滑点成交
+//   - 限价单（含已触发的止损/止盈限价单）：自生效价格（开盘价或触发价）起，价格范围触及限价时成交，
+//     生效价格已优于限价时按生效价格成交
+//   - 挂单的市价单：按开盘价加滑点成交
+//
+// 成交时余额不足的订单记为拒绝
+func (e *SpotExchange) ProcessCandle(candle *model.Candle) []*model.Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	defer func() { e.currentPrices[candle.Symbol] = candle.Close }()
+
+	pending := make([]*model.Order, 0)
+	for _, order := range e.orders {
+		if order.Symbol == candle.Symbol && !order.IsClosed() {
+			pending = append(pending, order)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].CreatedAt.Equal(pending[j].CreatedAt) {
+			return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+		}
+		return pending[i].ClientOrderID < pending[j].ClientOrderID
+	})
+
+	filled := make([]*model.Order, 0)
+	for _, order := range pending {
+		fillPrice, ok := e.matchCandle(order, candle)
+		if !ok {
+			continue
+		}
+		if err := e.fillOrderAt(order, fillPrice); err != nil {
+			order.Status = model.OrderStatusRejected
+			order.UpdatedAt = time.Now()
+			delete(e.triggered, order.ClientOrderID)
+			continue
+		}
+		filled = append(filled, order)
+	}
+	return filled
+}
+
+// matchCandle 计算挂单在 K 线内的成交价（未成交返回 false，止损/止盈限价单触发后记入 triggered）
+func (e *SpotExchange) matchCandle(order *model.Order, candle *model.Candle) (model.Money, bool) {
+	// 生效价格：订单开始参与撮合时的价格
+	ref := candle.Open
+	if order.Type.IsConditional() && !e.triggered[order.ClientOrderID] {
+		above := model.TriggersAbove(order.Type, order.Side)
+		switch {
+		case order.Triggered(candle.Open):
+			// 跳空：开盘即越过触发价
+		case above && candle.High.GE(order.StopPrice), !above && candle.Low.LE(order.StopPrice):
+			ref = order.StopPrice
+		default:
+			return model.Zero(), false
+		}
+		if !order.Type.HasLimitPrice() {
+			return e.withSlippage(ref, order.Side), true
+		}
+		e.triggered[order.ClientOrderID] = true
+	}
+
+	if !order.Type.HasLimitPrice() {
+		return e.withSlippage(ref, order.Side), true
+	}
+	if order.Side == model.OrderSideBuy {
+		if ref.LE(order.Price) {
+			return ref, true
+		}
+		return order.Price, candle.Low.LE(order.Price)
+	}
+	if ref.GE(order.Price) {
+		return ref, true
+	}
+	return order.Price, candle.High.GE(order.Price)
+}
+
 // getOrCreateBalance 获取或创建余额
 func (e *SpotExchange) getOrCreateBalance(asset string) *port.SpotBalance {
 	if bal, exists := e.balances[asset]; exists {
@@ -0,0 +1,196 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+func newStopTestExchange(t *testing.T) *SpotExchange {
+	t.Helper()
+	exchange := NewSpotExchange(map[string]model.Money{
+		"USDT": model.MustMoney("100000"),
+		"BTC":  model.MustMoney("1"),
+	})
+	exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+	return exchange
+}
+
+func candle(open, high, low, close string) *model.Candle {
+	return &model.Candle{
+		Symbol: "BTCUSDT",
+		Open:   model.MustMoney(open),
+		High:   model.MustMoney(high),
+		Low:    model.MustMoney(low),
+		Close:  model.MustMoney(close),
+	}
+}
+
+func placeStop(t *testing.T, exchange *SpotExchange, id string, side model.OrderSide, orderType model.OrderType, stop, limit string) *model.Order {
+	t.Helper()
+	req := &port.SpotPlaceOrderRequest{
+		ClientOrderID: id,
+		Symbol:        "BTCUSDT",
+		Side:          side,
+		Type:          orderType,
+		StopPrice:     model.MustMoney(stop),
+		Quantity:      model.MustMoney("0.1"),
+	}
+	if limit != "" {
+		req.Price = model.MustMoney(limit)
+	}
+	order, err := exchange.PlaceOrder(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if order.Status != model.OrderStatusSubmitted {
+		t.Fatalf("conditional order should rest, got %s", order.Status)
+	}
+	return order
+}
+
+func lastTradePrice(t *testing.T, exchange *SpotExchange) model.Money {
+	t.Helper()
+	if len(exchange.trades) == 0 {
+		t.Fatal("expected a trade")
+	}
+	return exchange.trades[len(exchange.trades)-1].Price
+}
+
+func TestSpotExchange_StopMarket(t *testing.T) {
+	t.Run("盘中触发按触发价加滑点成交", func(t *testing.T) {
+		exchange := newStopTestExchange(t)
+		placeStop(t, exchange, "sl", model.OrderSideSell, model.OrderTypeStopMarket, "49000", "")
+
+		if filled := exchange.ProcessCandle(candle("50000", "50500", "49500", "49800")); len(filled) != 0 {
+			t.Fatalf("stop should not trigger above 49000, filled %d", len(filled))
+		}
+		filled := exchange.ProcessCandle(candle("49800", "49900", "48500", "48800"))
+		if len(filled) != 1 || filled[0].ClientOrderID != "sl" || filled[0].Status != model.OrderStatusFilled {
+			t.Fatalf("expected stop to fill, got %+v", filled)
+		}
+		// 49000 * (1 - 0.0005)
+		if price := lastTradePrice(t, exchange); !price.EQ(model.MustMoney("48975.5")) {
+			t.Errorf("fill price = %s, want 48975.5", price)
+		}
+	})
+
+	t.Run("跳空按开盘价成交", func(t *testing.T) {
+		exchange := newStopTestExchange(t)
+		placeStop(t, exchange, "sl", model.OrderSideSell, model.OrderTypeStopMarket, "49000", "")
+
+		filled := exchange.ProcessCandle(candle("48000", "48200", "47500", "47800"))
+		if len(filled) != 1 {
+			t.Fatalf("expected stop to fill on gap, got %d", len(filled))
+		}
+		// 48000 * (1 - 0.0005)
+		if price := lastTradePrice(t, exchange); !price.EQ(model.MustMoney("47976")) {
+			t.Errorf("fill price = %s, want 47976", price)
+		}
+	})
+
+	t.Run("止损买入向上触发", func(t *testing.T) {
+		exchange := newStopTestExchange(t)
+		placeStop(t, exchange, "sb", model.OrderSideBuy, model.OrderTypeStopMarket, "51000", "")
+
+		if filled := exchange.ProcessCandle(candle("50000", "50900", "49000", "49500")); len(filled) != 0 {
+			t.Fatalf("buy stop should not trigger below 51000")
+		}
+		if filled := exchange.ProcessCandle(candle("49500", "51200", "49400", "51100")); len(filled) != 1 {
+			t.Fatalf("buy stop should trigger at 51000")
+		}
+	})
+
+	t.Run("会立即触发的条件单被拒绝", func(t *testing.T) {
+		exchange := newStopTestExchange(t)
+		order, err := exchange.PlaceOrder(context.Background(), &port.SpotPlaceOrderRequest{
+			ClientOrderID: "bad",
+			Symbol:        "BTCUSDT",
+			Side:          model.OrderSideSell,
+			Type:          model.OrderTypeStopMarket,
+			StopPrice:     model.MustMoney("51000"),
+			Quantity:      model.MustMoney("0.1"),
+		})
+		if err == nil || order.Status != model.OrderStatusRejected {
+			t.Fatalf("expected rejection, got status %v err %v", order.Status, err)
+		}
+	})
+}
+
+func TestSpotExchange_StopLimit(t *testing.T) {
+	t.Run("触发后价格越过限价时挂单等待", func(t *testing.T) {
+		exchange := newStopTestExchange(t)
+		placeStop(t, exchange, "sl", model.OrderSideSell, model.OrderTypeStopLimit, "49000", "48900")
+
+		// 跳空低开至限价以下且未回升：已触发但不成交
+		if filled := exchange.ProcessCandle(candle("48000", "48500", "47500", "48200")); len(filled) != 0 {
+			t.Fatalf("stop limit should not fill below limit, got %d", len(filled))
+		}
+		if !exchange.triggered["sl"] {
+			t.Fatal("stop limit should be marked triggered")
+		}
+
+		// 回升至限价：按限价成交
+		filled := exchange.ProcessCandle(candle("48200", "49100", "48100", "49000"))
+		if len(filled) != 1 {
+			t.Fatalf("triggered stop limit should fill at limit, got %d", len(filled))
+		}
+		if price := lastTradePrice(t, exchange); !price.EQ(model.MustMoney("48900")) {
+			t.Errorf("fill price = %s, want 48900", price)
+		}
+		if exchange.triggered["sl"] {
+			t.Error("filled order should leave triggered set")
+		}
+	})
+
+	t.Run("盘中触发按触发价成交", func(t *testing.T) {
+		exchange := newStopTestExchange(t)
+		placeStop(t, exchange, "sl", model.OrderSideSell, model.OrderTypeStopLimit, "49000", "48900")
+
+		if filled := exchange.ProcessCandle(candle("49500", "49600", "48800", "48900")); len(filled) != 1 {
+			t.Fatalf("stop limit should fill when triggered, got %d", len(filled))
+		}
+		if price := lastTradePrice(t, exchange); !price.EQ(model.MustMoney("49000")) {
+			t.Errorf("fill price = %s, want 49000", price)
+		}
+	})
+}
+
+func TestSpotExchange_TakeProfitAndLimit(t *testing.T) {
+	exchange := newStopTestExchange(t)
+	exchange.SetInstantFill(false)
+	placeStop(t, exchange, "tp", model.OrderSideSell, model.OrderTypeTakeProfitLimit, "52000", "52000")
+	if _, err := exchange.PlaceOrder(context.Background(), &port.SpotPlaceOrderRequest{
+		ClientOrderID: "bid",
+		Symbol:        "BTCUSDT",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeLimit,
+		Price:         model.MustMoney("49000"),
+		Quantity:      model.MustMoney("0.1"),
+	}); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	filled := exchange.ProcessCandle(candle("50000", "50100", "48800", "49500"))
+	if len(filled) != 1 || filled[0].ClientOrderID != "bid" {
+		t.Fatalf("expected resting limit to fill, got %+v", filled)
+	}
+	if price := lastTradePrice(t, exchange); !price.EQ(model.MustMoney("49000")) {
+		t.Errorf("limit fill price = %s, want 49000", price)
+	}
+
+	filled = exchange.ProcessCandle(candle("49500", "52500", "49400", "52300"))
+	if len(filled) != 1 || filled[0].ClientOrderID != "tp" {
+		t.Fatalf("expected take profit to fill, got %+v", filled)
+	}
+	if price := lastTradePrice(t, exchange); !price.EQ(model.MustMoney("52000")) {
+		t.Errorf("take profit fill price = %s, want 52000", price)
+	}
+
+	price := exchange.currentPrices["BTCUSDT"]
+	if !price.EQ(model.MustMoney("52300")) {
+		t.Errorf("current price = %s, want close 52300", price)
+	}
+}
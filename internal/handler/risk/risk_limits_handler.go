@@ -0,0 +1,29 @@
+package risk
+
+import (
+	"net/http"
+
+	"github.com/iluyuns/alpha-trade/internal/logic/risk"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func RiskLimitsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.RiskLimitsReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		l := risk.NewRiskLimitsLogic(r.Context(), svcCtx)
+		resp, err := l.RiskLimits(&req)
+		if err != nil {
+			httpx.Error(w, err)
+		} else {
+			httpx.OkJson(w, resp)
+		}
+	}
+}
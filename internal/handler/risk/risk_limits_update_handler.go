@@ -0,0 +1,43 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/logic/risk"
+	"github.com/iluyuns/alpha-trade/internal/pkg/ctxval"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func RiskLimitsUpdateHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.RiskLimitsUpdateReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		// 注入 IP 到 Context（审计日志）
+		ctx := context.WithValue(r.Context(), ctxval.IPKey, httpx.GetRemoteAddr(r))
+
+		l := risk.NewRiskLimitsUpdateLogic(ctx, svcCtx)
+		resp, err := l.RiskLimitsUpdate(&req)
+		switch {
+		case errors.Is(err, risklogic.ErrLimitLooseningNotVerified):
+			// 放宽限额未通过提级认证，与 MFAStepUp 中间件保持一致返回 403
+			httpx.WriteJson(w, http.StatusForbidden, map[string]string{
+				"error": "sudo_required",
+				"msg":   err.Error(),
+			})
+		case err != nil:
+			httpx.Error(w, err)
+		default:
+			httpx.OkJson(w, resp)
+		}
+	}
+}
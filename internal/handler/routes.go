@@ -10,6 +10,7 @@ import (
 	authpasskey "github.com/iluyuns/alpha-trade/internal/handler/auth/passkey"
 	dashboard "github.com/iluyuns/alpha-trade/internal/handler/dashboard"
 	notifications "github.com/iluyuns/alpha-trade/internal/handler/notifications"
+	risk "github.com/iluyuns/alpha-trade/internal/handler/risk"
 	system "github.com/iluyuns/alpha-trade/internal/handler/system"
 	trading "github.com/iluyuns/alpha-trade/internal/handler/trading"
 	"github.com/iluyuns/alpha-trade/internal/svc"
@@ -135,6 +136,51 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/notifications"),
 	)
 
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth, serverCtx.MFA},
+			[]rest.Route{
+				{
+					// 查询当前风控限额
+					Method:  http.MethodGet,
+					Path:    "/limits",
+					Handler: risk.RiskLimitsHandler(serverCtx),
+				},
+				{
+					// 调整风控限额
+					Method:  http.MethodPut,
+					Path:    "/limits",
+					Handler: risk.RiskLimitsUpdateHandler(serverCtx),
+				},
+				{
+					// 查询风控限额变更历史
+					Method:  http.MethodGet,
+					Path:    "/limits/history",
+					Handler: risk.RiskLimitHistoryHandler(serverCtx),
+				},
+				{
+					// 查询风控决策记录
+					Method:  http.MethodGet,
+					Path:    "/records",
+					Handler: risk.RiskRecordsHandler(serverCtx),
+				},
+				{
+					// 查询风控状态历史
+					Method:  http.MethodGet,
+					Path:    "/history",
+					Handler: risk.RiskHistoryHandler(serverCtx),
+				},
+				{
+					// 模拟下单前风控检查
+					Method:  http.MethodPost,
+					Path:    "/simulate",
+					Handler: risk.RiskSimulateHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/v1/risk"),
+	)
+
 	server.AddRoutes(
 		rest.WithMiddlewares(
 			[]rest.Middleware{serverCtx.Auth, serverCtx.MFA},
@@ -154,6 +200,36 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithMiddlewares(
 			[]rest.Middleware{serverCtx.Auth, serverCtx.MFA},
 			[]rest.Route{
+				{
+					// 查询全局交易暂停状态
+					Method:  http.MethodGet,
+					Path:    "/halt",
+					Handler: trading.TradingHaltHandler(serverCtx),
+				},
+				{
+					// 紧急停止（Kill Switch）
+					Method:  http.MethodPost,
+					Path:    "/kill-switch",
+					Handler: trading.KillSwitchHandler(serverCtx),
+				},
+				{
+					// 查询订单状态转换历史
+					Method:  http.MethodGet,
+					Path:    "/orders/events",
+					Handler: trading.OrderEventsHandler(serverCtx),
+				},
+				{
+					// 查询对账状态
+					Method:  http.MethodGet,
+					Path:    "/reconcile",
+					Handler: trading.ReconcileStatusHandler(serverCtx),
+				},
+				{
+					// 执行对账
+					Method:  http.MethodPost,
+					Path:    "/reconcile",
+					Handler: trading.ReconcileRunHandler(serverCtx),
+				},
 				{
 					// 启动交易循环
 					Method:  http.MethodPost,
@@ -176,4 +252,19 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		),
 		rest.WithPrefix("/api/v1/trading"),
 	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth, serverCtx.MFA, serverCtx.MFAStepUp},
+			[]rest.Route{
+				{
+					// 解除全局交易暂停
+					Method:  http.MethodPost,
+					Path:    "/halt/release",
+					Handler: trading.HaltReleaseHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/v1/trading"),
+	)
 }
@@ -0,0 +1,41 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/iluyuns/alpha-trade/internal/core/killswitch"
+	"github.com/iluyuns/alpha-trade/internal/logic/trading"
+	"github.com/iluyuns/alpha-trade/internal/pkg/ctxval"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func HaltReleaseHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 注入 IP 到 Context（审计日志）
+		ctx := context.WithValue(r.Context(), ctxval.IPKey, httpx.GetRemoteAddr(r))
+
+		l := trading.NewHaltReleaseLogic(ctx, svcCtx)
+		resp, err := l.HaltRelease()
+		switch {
+		case errors.Is(err, killswitch.ErrSameApprover):
+			// 双人确认：发起人不能自行确认
+			httpx.WriteJson(w, http.StatusForbidden, map[string]string{
+				"error": "second_approval_required",
+				"msg":   err.Error(),
+			})
+		case errors.Is(err, killswitch.ErrNotHalted):
+			httpx.WriteJson(w, http.StatusConflict, map[string]string{
+				"error": "not_halted",
+				"msg":   err.Error(),
+			})
+		case err != nil:
+			httpx.Error(w, err)
+		default:
+			httpx.OkJson(w, resp)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package trading
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/iluyuns/alpha-trade/internal/logic/trading"
+	"github.com/iluyuns/alpha-trade/internal/pkg/ctxval"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func KillSwitchHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.KillSwitchRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		// 注入 IP 到 Context（审计日志）
+		ctx := context.WithValue(r.Context(), ctxval.IPKey, httpx.GetRemoteAddr(r))
+
+		l := trading.NewKillSwitchLogic(ctx, svcCtx)
+		resp, err := l.KillSwitch(&req)
+		if err != nil {
+			httpx.Error(w, err)
+		} else {
+			httpx.OkJson(w, resp)
+		}
+	}
+}
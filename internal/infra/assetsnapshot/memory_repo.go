@@ -0,0 +1,65 @@
+package assetsnapshot
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存资产快照仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu        sync.RWMutex
+	snapshots []*model.AssetSnapshot
+	nextID    int64
+}
+
+// NewMemoryRepo 创建内存资产快照仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+// SaveSnapshot 保存快照（同账户、同类型、同交易日重复写入忽略）
+func (r *MemoryRepo) SaveSnapshot(ctx context.Context, snapshot *model.AssetSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.snapshots {
+		if s.AccountID == snapshot.AccountID && s.Kind == snapshot.Kind && s.TradingDay == snapshot.TradingDay {
+			return nil
+		}
+	}
+
+	r.nextID++
+	copied := *snapshot
+	copied.ID = r.nextID
+	r.snapshots = append(r.snapshots, &copied)
+	return nil
+}
+
+// ListSnapshots 查询账户快照（按快照时间倒序）
+func (r *MemoryRepo) ListSnapshots(ctx context.Context, accountID, kind string, limit int) ([]*model.AssetSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []*model.AssetSnapshot
+	for _, s := range r.snapshots {
+		if s.AccountID != accountID || (kind != "" && s.Kind != kind) {
+			continue
+		}
+		copied := *s
+		matched = append(matched, &copied)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].SnapshotTime.Equal(matched[j].SnapshotTime) {
+			return matched[i].SnapshotTime.After(matched[j].SnapshotTime)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
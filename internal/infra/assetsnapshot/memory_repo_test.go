@@ -0,0 +1,56 @@
+package assetsnapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func newDailySnapshot(accountID, day string, equity string, at time.Time) *model.AssetSnapshot {
+	return &model.AssetSnapshot{
+		AccountID:        accountID,
+		Kind:             model.AssetSnapshotKindDailyStart,
+		TradingDay:       day,
+		TotalBalance:     model.MustMoney(equity),
+		AvailableBalance: model.Zero(),
+		FrozenBalance:    model.Zero(),
+		SnapshotTime:     at,
+	}
+}
+
+func TestMemoryRepo_SaveAndList(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	base := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	snapshots := []*model.AssetSnapshot{
+		newDailySnapshot("acc-1", "2024-03-05", "10000", base),
+		newDailySnapshot("acc-1", "2024-03-06", "10200", base.Add(24*time.Hour)),
+		// 同交易日重复写入被忽略
+		newDailySnapshot("acc-1", "2024-03-06", "99999", base.Add(25*time.Hour)),
+		newDailySnapshot("acc-2", "2024-03-06", "5000", base.Add(24*time.Hour)),
+	}
+	for _, s := range snapshots {
+		if err := repo.SaveSnapshot(ctx, s); err != nil {
+			t.Fatalf("SaveSnapshot failed: %v", err)
+		}
+	}
+
+	list, err := repo.ListSnapshots(ctx, "acc-1", model.AssetSnapshotKindDailyStart, 0)
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("len = %d, want 2", len(list))
+	}
+	if list[0].TradingDay != "2024-03-06" || !list[0].TotalBalance.EQ(model.MustMoney("10200")) {
+		t.Errorf("unexpected latest snapshot %+v", list[0])
+	}
+
+	list, _ = repo.ListSnapshots(ctx, "acc-1", "", 1)
+	if len(list) != 1 {
+		t.Errorf("limit: len = %d, want 1", len(list))
+	}
+}
@@ -0,0 +1,82 @@
+package assetsnapshot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 资产快照仓储（实现 port.AssetSnapshotRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 资产快照仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+// SaveSnapshot 保存快照（依赖 (account_id, kind, trading_day) 唯一约束去重）
+func (r *PostgresRepo) SaveSnapshot(ctx context.Context, snapshot *model.AssetSnapshot) error {
+	query := `
+		INSERT INTO asset_snapshots (
+			account_id, exchange, kind, trading_day,
+			total_balance, available_balance, frozen_balance, snapshot_time
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (account_id, kind, trading_day) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		snapshot.AccountID,
+		snapshot.Exchange,
+		snapshot.Kind,
+		snapshot.TradingDay,
+		snapshot.TotalBalance.String(),
+		snapshot.AvailableBalance.String(),
+		snapshot.FrozenBalance.String(),
+		snapshot.SnapshotTime,
+	)
+	if err != nil {
+		return fmt.Errorf("save asset snapshot failed: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshots 查询账户快照（按快照时间倒序）
+func (r *PostgresRepo) ListSnapshots(ctx context.Context, accountID, kind string, limit int) ([]*model.AssetSnapshot, error) {
+	query := `
+		SELECT id, account_id, exchange, kind, COALESCE(trading_day::TEXT, ''),
+			total_balance, available_balance, frozen_balance, snapshot_time
+		FROM asset_snapshots
+		WHERE account_id = $1 AND ($2 = '' OR kind = $2)
+		ORDER BY snapshot_time DESC, id DESC
+		LIMIT NULLIF($3, 0)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, accountID, kind, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list asset snapshots failed: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*model.AssetSnapshot
+	for rows.Next() {
+		var (
+			s                        model.AssetSnapshot
+			total, available, frozen string
+		)
+		if err := rows.Scan(&s.ID, &s.AccountID, &s.Exchange, &s.Kind, &s.TradingDay,
+			&total, &available, &frozen, &s.SnapshotTime); err != nil {
+			return nil, fmt.Errorf("scan asset snapshot failed: %w", err)
+		}
+		s.TotalBalance = model.MustMoney(total)
+		s.AvailableBalance = model.MustMoney(available)
+		s.FrozenBalance = model.MustMoney(frozen)
+		snapshots = append(snapshots, &s)
+	}
+	return snapshots, rows.Err()
+}
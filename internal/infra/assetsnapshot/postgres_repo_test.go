@@ -0,0 +1,66 @@
+package assetsnapshot
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	accountID := "test-account-" + time.Now().Format("20060102150405")
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM asset_snapshots WHERE account_id = $1", accountID)
+	}()
+
+	t.Run("SaveAndList", func(t *testing.T) {
+		now := time.Now()
+		if err := repo.SaveSnapshot(ctx, newDailySnapshot(accountID, "2024-03-05", "10000", now)); err != nil {
+			t.Fatalf("SaveSnapshot failed: %v", err)
+		}
+		// 同交易日重复写入被忽略
+		if err := repo.SaveSnapshot(ctx, newDailySnapshot(accountID, "2024-03-05", "20000", now)); err != nil {
+			t.Fatalf("SaveSnapshot (duplicate) failed: %v", err)
+		}
+
+		list, err := repo.ListSnapshots(ctx, accountID, model.AssetSnapshotKindDailyStart, 0)
+		if err != nil {
+			t.Fatalf("ListSnapshots failed: %v", err)
+		}
+		if len(list) != 1 {
+			t.Fatalf("len = %d, want 1", len(list))
+		}
+		if list[0].TradingDay != "2024-03-05" || !list[0].TotalBalance.EQ(model.MustMoney("10000")) {
+			t.Errorf("unexpected snapshot %+v", list[0])
+		}
+	})
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
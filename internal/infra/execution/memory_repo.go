@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存母单仓储（用于回测）
+type MemoryRepo struct {
+	mu       sync.RWMutex
+	parents  map[string]*model.ParentOrder // key: ParentID
+	children map[string]*model.ChildOrder  // key: ClientOrderID
+}
+
+// NewMemoryRepo 创建内存母单仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		parents:  make(map[string]*model.ParentOrder),
+		children: make(map[string]*model.ChildOrder),
+	}
+}
+
+// SaveParent 保存母单（幂等）
+func (r *MemoryRepo) SaveParent(ctx context.Context, parent *model.ParentOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *parent
+	r.parents[parent.ID] = &copied
+	return nil
+}
+
+// GetParent 获取母单
+func (r *MemoryRepo) GetParent(ctx context.Context, parentID string) (*model.ParentOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	parent, exists := r.parents[parentID]
+	if !exists {
+		return nil, fmt.Errorf("parent order not found: %s", parentID)
+	}
+
+	copied := *parent
+	return &copied, nil
+}
+
+// ListActiveParents 列出执行中的母单
+func (r *MemoryRepo) ListActiveParents(ctx context.Context) ([]*model.ParentOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var parents []*model.ParentOrder
+	for _, parent := range r.parents {
+		if parent.IsActive() {
+			copied := *parent
+			parents = append(parents, &copied)
+		}
+	}
+	return parents, nil
+}
+
+// SaveChild 保存子单关联（幂等）
+func (r *MemoryRepo) SaveChild(ctx context.Context, child *model.ChildOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *child
+	r.children[child.ClientOrderID] = &copied
+	return nil
+}
+
+// ListChildren 列出母单的所有子单（按序号升序）
+func (r *MemoryRepo) ListChildren(ctx context.Context, parentID string) ([]*model.ChildOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var children []*model.ChildOrder
+	for _, child := range r.children {
+		if child.ParentID == parentID {
+			copied := *child
+			children = append(children, &copied)
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Seq < children[j].Seq
+	})
+	return children, nil
+}
@@ -0,0 +1,67 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_Parents(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+
+	parent := &model.ParentOrder{
+		ID:       "parent-1",
+		Symbol:   "BTCUSDT",
+		Quantity: model.MustMoney("1"),
+		Status:   model.ParentStatusActive,
+	}
+	if err := repo.SaveParent(ctx, parent); err != nil {
+		t.Fatalf("SaveParent failed: %v", err)
+	}
+	_ = repo.SaveParent(ctx, &model.ParentOrder{ID: "parent-2", Status: model.ParentStatusCompleted})
+
+	// 修改原对象不影响已保存数据
+	parent.Quantity = model.MustMoney("5")
+
+	got, err := repo.GetParent(ctx, "parent-1")
+	if err != nil {
+		t.Fatalf("GetParent failed: %v", err)
+	}
+	if !got.Quantity.EQ(model.MustMoney("1")) {
+		t.Errorf("unexpected quantity: %s", got.Quantity)
+	}
+
+	active, _ := repo.ListActiveParents(ctx)
+	if len(active) != 1 || active[0].ID != "parent-1" {
+		t.Errorf("unexpected active parents: %+v", active)
+	}
+
+	if _, err := repo.GetParent(ctx, "missing"); err == nil {
+		t.Error("expected error for missing parent")
+	}
+}
+
+func TestMemoryRepo_Children(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+
+	_ = repo.SaveChild(ctx, &model.ChildOrder{ParentID: "p1", ClientOrderID: "c2", Seq: 2})
+	_ = repo.SaveChild(ctx, &model.ChildOrder{ParentID: "p1", ClientOrderID: "c1", Seq: 1})
+	_ = repo.SaveChild(ctx, &model.ChildOrder{ParentID: "p2", ClientOrderID: "c3", Seq: 1})
+
+	// 按 ClientOrderID 幂等更新
+	_ = repo.SaveChild(ctx, &model.ChildOrder{ParentID: "p1", ClientOrderID: "c1", Seq: 1, Status: model.OrderStatusFilled})
+
+	children, err := repo.ListChildren(ctx, "p1")
+	if err != nil {
+		t.Fatalf("ListChildren failed: %v", err)
+	}
+	if len(children) != 2 || children[0].ClientOrderID != "c1" || children[1].ClientOrderID != "c2" {
+		t.Fatalf("expected children ordered by seq, got %+v", children)
+	}
+	if children[0].Status != model.OrderStatusFilled {
+		t.Errorf("child status not updated: %s", children[0].Status)
+	}
+}
@@ -0,0 +1,257 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 母单仓储（实现 port.ExecutionRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 母单仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+const parentColumns = `
+	parent_id, account_id, strategy_id, symbol, side, algorithm,
+	quantity, submitted_qty, filled_qty,
+	start_time, end_time, slices, reduce_only,
+	status, child_count, last_error, created_at, updated_at
+`
+
+// SaveParent 保存母单（幂等）
+func (r *PostgresRepo) SaveParent(ctx context.Context, parent *model.ParentOrder) error {
+	query := `
+		INSERT INTO execution_parents (` + parentColumns + `) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+		)
+		ON CONFLICT (parent_id) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			submitted_qty = EXCLUDED.submitted_qty,
+			filled_qty = EXCLUDED.filled_qty,
+			end_time = EXCLUDED.end_time,
+			slices = EXCLUDED.slices,
+			status = EXCLUDED.status,
+			child_count = EXCLUDED.child_count,
+			last_error = EXCLUDED.last_error,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		parent.ID,
+		parent.AccountID,
+		parent.StrategyID,
+		parent.Symbol,
+		parent.Side.String(),
+		parent.Algorithm,
+		parent.Quantity.String(),
+		parent.Submitted.String(),
+		parent.Filled.String(),
+		parent.StartTime,
+		parent.EndTime,
+		parent.Slices,
+		parent.ReduceOnly,
+		parent.Status.String(),
+		parent.ChildCount,
+		parent.LastError,
+		parent.CreatedAt,
+		parent.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save parent order failed: %w", err)
+	}
+	return nil
+}
+
+// GetParent 获取母单
+func (r *PostgresRepo) GetParent(ctx context.Context, parentID string) (*model.ParentOrder, error) {
+	query := `SELECT ` + parentColumns + ` FROM execution_parents WHERE parent_id = $1`
+
+	parent, err := scanParent(r.db.QueryRowContext(ctx, query, parentID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("parent order not found: %s", parentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get parent order failed: %w", err)
+	}
+	return parent, nil
+}
+
+// ListActiveParents 列出执行中的母单
+func (r *PostgresRepo) ListActiveParents(ctx context.Context) ([]*model.ParentOrder, error) {
+	query := `SELECT ` + parentColumns + ` FROM execution_parents WHERE status = 'ACTIVE' ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list active parents failed: %w", err)
+	}
+	defer rows.Close()
+
+	var parents []*model.ParentOrder
+	for rows.Next() {
+		parent, err := scanParent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan parent order failed: %w", err)
+		}
+		parents = append(parents, parent)
+	}
+
+	return parents, rows.Err()
+}
+
+// SaveChild 保存子单关联（幂等）
+func (r *PostgresRepo) SaveChild(ctx context.Context, child *model.ChildOrder) error {
+	query := `
+		INSERT INTO execution_children (
+			client_oid, parent_id, seq, quantity, filled_qty, status, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		ON CONFLICT (client_oid) DO UPDATE SET
+			filled_qty = EXCLUDED.filled_qty,
+			status = EXCLUDED.status
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		child.ClientOrderID,
+		child.ParentID,
+		child.Seq,
+		child.Quantity.String(),
+		child.Filled.String(),
+		child.Status.String(),
+		child.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save child order failed: %w", err)
+	}
+	return nil
+}
+
+// ListChildren 列出母单的所有子单（按序号升序）
+func (r *PostgresRepo) ListChildren(ctx context.Context, parentID string) ([]*model.ChildOrder, error) {
+	query := `
+		SELECT client_oid, parent_id, seq, quantity, filled_qty, status, created_at
+		FROM execution_children
+		WHERE parent_id = $1
+		ORDER BY seq
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("list child orders failed: %w", err)
+	}
+	defer rows.Close()
+
+	var children []*model.ChildOrder
+	for rows.Next() {
+		var (
+			clientOid, parID, quantity, filled, status string
+			seq                                        int
+			createdAt                                  time.Time
+		)
+
+		if err := rows.Scan(&clientOid, &parID, &seq, &quantity, &filled, &status, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan child order failed: %w", err)
+		}
+
+		children = append(children, &model.ChildOrder{
+			ParentID:      parID,
+			ClientOrderID: clientOid,
+			Seq:           seq,
+			Quantity:      model.MustMoney(quantity),
+			Filled:        model.MustMoney(filled),
+			Status:        parseOrderStatus(status),
+			CreatedAt:     createdAt,
+		})
+	}
+
+	return children, rows.Err()
+}
+
+// rowScanner 兼容 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanParent 扫描母单记录
+func scanParent(row rowScanner) (*model.ParentOrder, error) {
+	var (
+		id, accountID, strategyID, symbol, side, algorithm string
+		quantity, submitted, filled, status, lastError     string
+		startTime, endTime, createdAt, updatedAt           time.Time
+		slices, childCount                                 int
+		reduceOnly                                         bool
+	)
+
+	err := row.Scan(
+		&id, &accountID, &strategyID, &symbol, &side, &algorithm,
+		&quantity, &submitted, &filled,
+		&startTime, &endTime, &slices, &reduceOnly,
+		&status, &childCount, &lastError, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ParentOrder{
+		ID:         id,
+		AccountID:  accountID,
+		StrategyID: strategyID,
+		Symbol:     symbol,
+		Side:       parseOrderSide(side),
+		Algorithm:  algorithm,
+		Quantity:   model.MustMoney(quantity),
+		Submitted:  model.MustMoney(submitted),
+		Filled:     model.MustMoney(filled),
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Slices:     slices,
+		ReduceOnly: reduceOnly,
+		Status:     model.ParseParentStatus(status),
+		ChildCount: childCount,
+		LastError:  lastError,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}, nil
+}
+
+// 辅助函数：解析订单方向
+func parseOrderSide(s string) model.OrderSide {
+	switch s {
+	case "BUY":
+		return model.OrderSideBuy
+	case "SELL":
+		return model.OrderSideSell
+	default:
+		return 0
+	}
+}
+
+// 辅助函数：解析订单状态
+func parseOrderStatus(s string) model.OrderStatus {
+	switch s {
+	case "PENDING":
+		return model.OrderStatusPending
+	case "SUBMITTED":
+		return model.OrderStatusSubmitted
+	case "PARTIAL_FILLED":
+		return model.OrderStatusPartialFilled
+	case "FILLED":
+		return model.OrderStatusFilled
+	case "CANCELLED":
+		return model.OrderStatusCancelled
+	case "REJECTED":
+		return model.OrderStatusRejected
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,100 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	parentID := "test-parent-" + time.Now().Format("20060102150405")
+	now := time.Now()
+
+	t.Run("SaveAndGetParent", func(t *testing.T) {
+		parent := &model.ParentOrder{
+			ID:         parentID,
+			AccountID:  "test-account",
+			Symbol:     "BTCUSDT",
+			Side:       model.OrderSideSell,
+			Algorithm:  "TWAP",
+			Quantity:   model.MustMoney("1"),
+			Submitted:  model.MustMoney("0.25"),
+			Filled:     model.MustMoney("0.25"),
+			StartTime:  now,
+			EndTime:    now.Add(time.Minute),
+			Slices:     4,
+			ReduceOnly: true,
+			Status:     model.ParentStatusActive,
+			ChildCount: 1,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := repo.SaveParent(ctx, parent); err != nil {
+			t.Fatalf("SaveParent failed: %v", err)
+		}
+
+		got, err := repo.GetParent(ctx, parentID)
+		if err != nil {
+			t.Fatalf("GetParent failed: %v", err)
+		}
+		if !got.Submitted.EQ(model.MustMoney("0.25")) || !got.ReduceOnly || got.Status != model.ParentStatusActive {
+			t.Errorf("unexpected parent: %+v", got)
+		}
+	})
+
+	t.Run("SaveAndListChildren", func(t *testing.T) {
+		err := repo.SaveChild(ctx, &model.ChildOrder{
+			ParentID:      parentID,
+			ClientOrderID: parentID + "-child-1",
+			Seq:           1,
+			Quantity:      model.MustMoney("0.25"),
+			Filled:        model.MustMoney("0.25"),
+			Status:        model.OrderStatusFilled,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			t.Fatalf("SaveChild failed: %v", err)
+		}
+
+		children, err := repo.ListChildren(ctx, parentID)
+		if err != nil {
+			t.Fatalf("ListChildren failed: %v", err)
+		}
+		if len(children) != 1 || children[0].Status != model.OrderStatusFilled {
+			t.Errorf("unexpected children: %+v", children)
+		}
+	})
+
+	// 清理测试数据（子单随母单级联删除）
+	_, _ = db.ExecContext(ctx, "DELETE FROM execution_parents WHERE parent_id = $1", parentID)
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
 // MemoryRepo 内存订单仓储（用于回测）
 type MemoryRepo struct {
 	mu     sync.RWMutex
-	orders map[string]*model.Order // key: ClientOrderID
-	byExID map[string]string       // key: ExchangeID -> value: ClientOrderID
+	orders map[string]*model.Order        // key: ClientOrderID
+	byExID map[string]string              // key: ExchangeID -> value: ClientOrderID
+	events map[string][]*model.OrderEvent // key: ClientOrderID
+	nextID int64
 }
 
 // NewMemoryRepo 创建内存订单仓储
@@ -20,6 +24,7 @@ func NewMemoryRepo() *MemoryRepo {
 	return &MemoryRepo{
 		orders: make(map[string]*model.Order),
 		byExID: make(map[string]string),
+		events: make(map[string][]*model.OrderEvent),
 	}
 }
 
@@ -30,6 +35,9 @@ func (r *MemoryRepo) SaveOrder(ctx context.Context, order *model.Order) error {
 
 	// 深拷贝避免外部修改
 	copied := copyOrder(order)
+	if _, exists := r.orders[order.ClientOrderID]; !exists {
+		r.appendEvent(model.NewOrderCreatedEvent(order, model.OrderEventSourceCreate, time.Now()))
+	}
 	r.orders[order.ClientOrderID] = copied
 
 	// 建立 ExchangeID 索引
@@ -47,7 +55,7 @@ func (r *MemoryRepo) GetOrder(ctx context.Context, clientOrderID string) (*model
 
 	order, exists := r.orders[clientOrderID]
 	if !exists {
-		return nil, fmt.Errorf("order not found: %s", clientOrderID)
+		return nil, fmt.Errorf("%w: %s", port.ErrOrderNotFound, clientOrderID)
 	}
 
 	return copyOrder(order), nil
@@ -60,29 +68,67 @@ func (r *MemoryRepo) GetOrderByExchangeID(ctx context.Context, exchangeID string
 
 	clientOrderID, exists := r.byExID[exchangeID]
 	if !exists {
-		return nil, fmt.Errorf("order not found by exchange id: %s", exchangeID)
+		return nil, fmt.Errorf("%w: %s", port.ErrOrderNotFound, exchangeID)
 	}
 
 	order, exists := r.orders[clientOrderID]
 	if !exists {
-		return nil, fmt.Errorf("order not found: %s", clientOrderID)
+		return nil, fmt.Errorf("%w: %s", port.ErrOrderNotFound, clientOrderID)
 	}
 
 	return copyOrder(order), nil
 }
 
-// UpdateOrderStatus 原子更新订单状态
+// UpdateOrderStatus 原子更新订单状态（按状态机校验）
 func (r *MemoryRepo) UpdateOrderStatus(ctx context.Context, clientOrderID string, status model.OrderStatus) error {
+	_, err := r.ApplyUpdate(ctx, clientOrderID, model.OrderUpdate{Status: status})
+	return err
+}
+
+// ApplyUpdate 按订单状态机应用更新并追加状态转换事件
+func (r *MemoryRepo) ApplyUpdate(ctx context.Context, clientOrderID string, update model.OrderUpdate) (*model.OrderEvent, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	order, exists := r.orders[clientOrderID]
 	if !exists {
-		return fmt.Errorf("order not found: %s", clientOrderID)
+		return nil, fmt.Errorf("%w: %s", port.ErrOrderNotFound, clientOrderID)
 	}
 
-	order.Status = status
-	return nil
+	// 在副本上应用，失败时订单保持不变
+	next := copyOrder(order)
+	event, err := next.ApplyUpdate(update, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	r.orders[clientOrderID] = next
+	if next.ExchangeID != "" {
+		r.byExID[next.ExchangeID] = clientOrderID
+	}
+	if event != nil {
+		r.appendEvent(event)
+	}
+	return event, nil
+}
+
+// ListOrderEvents 列出订单的状态转换事件
+func (r *MemoryRepo) ListOrderEvents(ctx context.Context, clientOrderID string) ([]*model.OrderEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]*model.OrderEvent, 0, len(r.events[clientOrderID]))
+	for _, e := range r.events[clientOrderID] {
+		copied := *e
+		events = append(events, &copied)
+	}
+	return events, nil
+}
+
+// appendEvent 追加事件（调用方持有写锁）
+func (r *MemoryRepo) appendEvent(event *model.OrderEvent) {
+	r.nextID++
+	event.ID = r.nextID
+	r.events[event.ClientOrderID] = append(r.events[event.ClientOrderID], event)
 }
 
 // UpdateFilled 更新成交数量
@@ -92,7 +138,7 @@ func (r *MemoryRepo) UpdateFilled(ctx context.Context, clientOrderID string, fil
 
 	order, exists := r.orders[clientOrderID]
 	if !exists {
-		return fmt.Errorf("order not found: %s", clientOrderID)
+		return fmt.Errorf("%w: %s", port.ErrOrderNotFound, clientOrderID)
 	}
 
 	order.Filled = filled
@@ -135,22 +181,30 @@ func (r *MemoryRepo) ListOrdersBySymbol(ctx context.Context, symbol string, limi
 // copyOrder 深拷贝订单（避免并发修改）
 func copyOrder(order *model.Order) *model.Order {
 	return &model.Order{
-		ClientOrderID: order.ClientOrderID,
-		ExchangeID:    order.ExchangeID,
-		Symbol:        order.Symbol,
-		MarketType:    order.MarketType,
-		Side:          order.Side,
-		Type:          order.Type,
-		Price:         order.Price,
-		Quantity:      order.Quantity,
-		Filled:        order.Filled,
-		Status:        order.Status,
-		CreatedAt:     order.CreatedAt,
-		UpdatedAt:     order.UpdatedAt,
-		SubmitTime:    order.SubmitTime,
-		FillTime:      order.FillTime,
-		Leverage:      order.Leverage,
-		ReduceOnly:    order.ReduceOnly,
-		ProtectPrice:  order.ProtectPrice,
+		ClientOrderID:      order.ClientOrderID,
+		ExchangeID:         order.ExchangeID,
+		Symbol:             order.Symbol,
+		MarketType:         order.MarketType,
+		Side:               order.Side,
+		Type:               order.Type,
+		Price:              order.Price,
+		StopPrice:          order.StopPrice,
+		Quantity:           order.Quantity,
+		Filled:             order.Filled,
+		Status:             order.Status,
+		Sequence:           order.Sequence,
+		ExchangeUpdateTime: order.ExchangeUpdateTime,
+		CreatedAt:          order.CreatedAt,
+		UpdatedAt:          order.UpdatedAt,
+		SubmitTime:         order.SubmitTime,
+		FillTime:           order.FillTime,
+		Leverage:           order.Leverage,
+		ReduceOnly:         order.ReduceOnly,
+		ProtectPrice:       order.ProtectPrice,
+		ExpireAt:           order.ExpireAt,
+		MaxAge:             order.MaxAge,
+		ExpirePolicy:       order.ExpirePolicy,
+		Reprices:           order.Reprices,
+		StrategyID:         order.StrategyID,
 	}
 }
@@ -2,6 +2,7 @@ package order
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -114,6 +115,62 @@ func TestMemoryRepo_UpdateFilled(t *testing.T) {
 	}
 }
 
+func TestMemoryRepo_ApplyUpdate(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+
+	_ = repo.SaveOrder(ctx, &model.Order{
+		ClientOrderID: "test-order-events",
+		Symbol:        "BTCUSDT",
+		Quantity:      model.MustMoney("1"),
+		Filled:        model.MustMoney("0"),
+		Status:        model.OrderStatusSubmitted,
+	})
+
+	event, err := repo.ApplyUpdate(ctx, "test-order-events", model.OrderUpdate{
+		Status:   model.OrderStatusFilled,
+		Filled:   model.MustMoney("1"),
+		Sequence: 5,
+		Source:   model.OrderEventSourceExchange,
+	})
+	if err != nil {
+		t.Fatalf("ApplyUpdate failed: %v", err)
+	}
+	if event == nil || event.ID == 0 {
+		t.Fatalf("expected persisted event, got %+v", event)
+	}
+
+	// 已成交订单不能被迟到的轮询结果回退
+	err = repo.UpdateOrderStatus(ctx, "test-order-events", model.OrderStatusSubmitted)
+	if !errors.Is(err, model.ErrInvalidOrderTransition) {
+		t.Fatalf("expected ErrInvalidOrderTransition, got %v", err)
+	}
+	_, err = repo.ApplyUpdate(ctx, "test-order-events", model.OrderUpdate{Status: model.OrderStatusSubmitted, Sequence: 4})
+	if !errors.Is(err, model.ErrStaleOrderUpdate) {
+		t.Fatalf("expected ErrStaleOrderUpdate, got %v", err)
+	}
+
+	loaded, _ := repo.GetOrder(ctx, "test-order-events")
+	if loaded.Status != model.OrderStatusFilled || loaded.Sequence != 5 {
+		t.Errorf("unexpected order after rejected updates: status=%s seq=%d", loaded.Status, loaded.Sequence)
+	}
+
+	// 事件日志：创建 -> 成交
+	events, err := repo.ListOrderEvents(ctx, "test-order-events")
+	if err != nil {
+		t.Fatalf("ListOrderEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].FromStatus != 0 || events[0].ToStatus != model.OrderStatusSubmitted || events[0].Source != model.OrderEventSourceCreate {
+		t.Errorf("unexpected created event: %+v", events[0])
+	}
+	if events[1].FromStatus != model.OrderStatusSubmitted || events[1].ToStatus != model.OrderStatusFilled {
+		t.Errorf("unexpected fill event: %+v", events[1])
+	}
+}
+
 func TestMemoryRepo_ListActiveOrders(t *testing.T) {
 	repo := NewMemoryRepo()
 	ctx := context.Background()
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
 // PostgresRepo PostgreSQL 订单仓储（实现 port.OrderRepo 接口）
@@ -21,24 +22,35 @@ func NewPostgresRepo(db *sql.DB) *PostgresRepo {
 	}
 }
 
-// SaveOrder 保存订单（幂等）
+// SaveOrder 保存订单（幂等，首次插入时追加订单创建事件）
 func (r *PostgresRepo) SaveOrder(ctx context.Context, order *model.Order) error {
 	query := `
 		INSERT INTO orders (
 			client_oid, order_id, exchange, symbol, side, type,
 			price, quantity, filled_qty, status, 
-			created_at, updated_at
+			created_at, updated_at, strategy_id,
+			last_sequence, exchange_updated_at, stop_price,
+			expire_at, max_age_ms, expire_policy, reprice_count
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14, $15, $16,
+			$17, $18, $19, $20
 		)
 		ON CONFLICT (client_oid) DO UPDATE SET
 			order_id = EXCLUDED.order_id,
 			status = EXCLUDED.status,
 			filled_qty = EXCLUDED.filled_qty,
 			updated_at = EXCLUDED.updated_at
+		RETURNING (xmax = 0) AS inserted
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var inserted bool
+	err = tx.QueryRowContext(ctx, query,
 		order.ClientOrderID,
 		order.ExchangeID,
 		getExchangeName(order.Symbol), // 简化处理，从 Symbol 推断
@@ -51,9 +63,26 @@ func (r *PostgresRepo) SaveOrder(ctx context.Context, order *model.Order) error
 		order.Status.String(),
 		order.CreatedAt,
 		order.UpdatedAt,
-	)
+		order.StrategyID,
+		order.Sequence,
+		nullTime(order.ExchangeUpdateTime),
+		order.StopPrice.String(),
+		nullTime(order.ExpireAt),
+		order.MaxAge.Milliseconds(),
+		expirePolicyName(order.ExpirePolicy),
+		order.Reprices,
+	).Scan(&inserted)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if inserted {
+		event := model.NewOrderCreatedEvent(order, model.OrderEventSourceCreate, time.Now())
+		if err := insertEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // GetOrder 根据 ClientOrderID 获取订单
@@ -62,42 +91,61 @@ func (r *PostgresRepo) GetOrder(ctx context.Context, clientOrderID string) (*mod
 		SELECT 
 			client_oid, order_id, symbol, side, type,
 			price, quantity, filled_qty, status,
-			created_at, updated_at
+			created_at, updated_at, COALESCE(strategy_id, ''),
+			last_sequence, exchange_updated_at, stop_price,
+			expire_at, max_age_ms, expire_policy, reprice_count
 		FROM orders
 		WHERE client_oid = $1
 	`
 
 	var (
 		clientOid, exchangeID, symbol, side, orderType, status string
-		price, quantity, filled                                 string
-		createdAt, updatedAt                                    time.Time
+		price, quantity, filled, stopPrice                     string
+		createdAt, updatedAt                                   time.Time
+		strategyID                                             string
+		sequence                                               int64
+		exchangeUpdatedAt                                      sql.NullTime
+		expireAt                                               sql.NullTime
+		maxAgeMs                                               int64
+		expirePolicy                                           string
+		reprices                                               int
 	)
 
 	err := r.db.QueryRowContext(ctx, query, clientOrderID).Scan(
 		&clientOid, &exchangeID, &symbol, &side, &orderType,
 		&price, &quantity, &filled, &status,
-		&createdAt, &updatedAt,
+		&createdAt, &updatedAt, &strategyID,
+		&sequence, &exchangeUpdatedAt, &stopPrice,
+		&expireAt, &maxAgeMs, &expirePolicy, &reprices,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("order not found: %s", clientOrderID)
+		return nil, fmt.Errorf("%w: %s", port.ErrOrderNotFound, clientOrderID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get order failed: %w", err)
 	}
 
 	return &model.Order{
-		ClientOrderID: clientOid,
-		ExchangeID:    exchangeID,
-		Symbol:        symbol,
-		Side:          parseOrderSide(side),
-		Type:          parseOrderType(orderType),
-		Price:         model.MustMoney(price),
-		Quantity:      model.MustMoney(quantity),
-		Filled:        model.MustMoney(filled),
-		Status:        parseOrderStatus(status),
-		CreatedAt:     createdAt,
-		UpdatedAt:     updatedAt,
+		ClientOrderID:      clientOid,
+		ExchangeID:         exchangeID,
+		Symbol:             symbol,
+		Side:               parseOrderSide(side),
+		Type:               parseOrderType(orderType),
+		Price:              model.MustMoney(price),
+		StopPrice:          model.MustMoney(stopPrice),
+		Quantity:           model.MustMoney(quantity),
+		Filled:             model.MustMoney(filled),
+		Status:             model.ParseOrderStatus(status),
+		CreatedAt:          createdAt,
+		UpdatedAt:          updatedAt,
+		StrategyID:         strategyID,
+		Sequence:           sequence,
+		ExchangeUpdateTime: exchangeUpdatedAt.Time,
+		ExpireAt:           expireAt.Time,
+		MaxAge:             time.Duration(maxAgeMs) * time.Millisecond,
+		ExpirePolicy:       model.ParseExpirePolicy(expirePolicy),
+		Reprices:           reprices,
 	}, nil
 }
 
@@ -107,67 +155,188 @@ func (r *PostgresRepo) GetOrderByExchangeID(ctx context.Context, exchangeID stri
 		SELECT 
 			client_oid, order_id, symbol, side, type,
 			price, quantity, filled_qty, status,
-			created_at, updated_at
+			created_at, updated_at, COALESCE(strategy_id, ''),
+			last_sequence, exchange_updated_at, stop_price,
+			expire_at, max_age_ms, expire_policy, reprice_count
 		FROM orders
 		WHERE order_id = $1
 	`
 
 	var (
 		clientOid, exchID, symbol, side, orderType, status string
-		price, quantity, filled                            string
+		price, quantity, filled, stopPrice                 string
 		createdAt, updatedAt                               time.Time
+		strategyID                                         string
+		sequence                                           int64
+		exchangeUpdatedAt                                  sql.NullTime
+		expireAt                                           sql.NullTime
+		maxAgeMs                                           int64
+		expirePolicy                                       string
+		reprices                                           int
 	)
 
 	err := r.db.QueryRowContext(ctx, query, exchangeID).Scan(
 		&clientOid, &exchID, &symbol, &side, &orderType,
 		&price, &quantity, &filled, &status,
-		&createdAt, &updatedAt,
+		&createdAt, &updatedAt, &strategyID,
+		&sequence, &exchangeUpdatedAt, &stopPrice,
+		&expireAt, &maxAgeMs, &expirePolicy, &reprices,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("order not found: %s", exchangeID)
+		return nil, fmt.Errorf("%w: %s", port.ErrOrderNotFound, exchangeID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get order by exchange id failed: %w", err)
 	}
 
 	return &model.Order{
-		ClientOrderID: clientOid,
-		ExchangeID:    exchID,
-		Symbol:        symbol,
-		Side:          parseOrderSide(side),
-		Type:          parseOrderType(orderType),
-		Price:         model.MustMoney(price),
-		Quantity:      model.MustMoney(quantity),
-		Filled:        model.MustMoney(filled),
-		Status:        parseOrderStatus(status),
-		CreatedAt:     createdAt,
-		UpdatedAt:     updatedAt,
+		ClientOrderID:      clientOid,
+		ExchangeID:         exchID,
+		Symbol:             symbol,
+		Side:               parseOrderSide(side),
+		Type:               parseOrderType(orderType),
+		Price:              model.MustMoney(price),
+		StopPrice:          model.MustMoney(stopPrice),
+		Quantity:           model.MustMoney(quantity),
+		Filled:             model.MustMoney(filled),
+		Status:             model.ParseOrderStatus(status),
+		CreatedAt:          createdAt,
+		UpdatedAt:          updatedAt,
+		StrategyID:         strategyID,
+		Sequence:           sequence,
+		ExchangeUpdateTime: exchangeUpdatedAt.Time,
+		ExpireAt:           expireAt.Time,
+		MaxAge:             time.Duration(maxAgeMs) * time.Millisecond,
+		ExpirePolicy:       model.ParseExpirePolicy(expirePolicy),
+		Reprices:           reprices,
 	}, nil
 }
 
-// UpdateOrderStatus 原子更新订单状态
+// UpdateOrderStatus 原子更新订单状态（按状态机校验）
 func (r *PostgresRepo) UpdateOrderStatus(ctx context.Context, clientOrderID string, status model.OrderStatus) error {
-	query := `
+	_, err := r.ApplyUpdate(ctx, clientOrderID, model.OrderUpdate{Status: status})
+	return err
+}
+
+// ApplyUpdate 按订单状态机应用更新并追加状态转换事件
+// 在事务内锁定订单行，并发更新按到达顺序串行校验
+func (r *PostgresRepo) ApplyUpdate(ctx context.Context, clientOrderID string, update model.OrderUpdate) (*model.OrderEvent, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		status, filled    string
+		exchangeUpdatedAt sql.NullTime
+	)
+	order := &model.Order{ClientOrderID: clientOrderID}
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, filled_qty, last_sequence, exchange_updated_at
+		FROM orders
+		WHERE client_oid = $1
+		FOR UPDATE
+	`, clientOrderID).Scan(&status, &filled, &order.Sequence, &exchangeUpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", port.ErrOrderNotFound, clientOrderID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock order failed: %w", err)
+	}
+	order.Status = model.ParseOrderStatus(status)
+	order.Filled = model.MustMoney(filled)
+	order.ExchangeUpdateTime = exchangeUpdatedAt.Time
+
+	now := time.Now()
+	event, err := order.ApplyUpdate(update, now)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE orders
-		SET status = $2, updated_at = $3
+		SET status = $2, filled_qty = $3, last_sequence = $4, exchange_updated_at = $5, updated_at = $6,
+			order_id = COALESCE(NULLIF($7, ''), order_id)
 		WHERE client_oid = $1
+	`, clientOrderID, order.Status.String(), order.Filled.String(), order.Sequence,
+		nullTime(order.ExchangeUpdateTime), now, update.ExchangeID)
+	if err != nil {
+		return nil, fmt.Errorf("update order failed: %w", err)
+	}
+
+	if event != nil {
+		if err := insertEvent(ctx, tx, event); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ListOrderEvents 列出订单的状态转换事件
+func (r *PostgresRepo) ListOrderEvents(ctx context.Context, clientOrderID string) ([]*model.OrderEvent, error) {
+	query := `
+		SELECT id, client_oid, from_status, to_status, filled_qty, sequence,
+			exchange_time, source, reason, created_at
+		FROM order_events
+		WHERE client_oid = $1
+		ORDER BY id ASC
 	`
 
-	result, err := r.db.ExecContext(ctx, query, clientOrderID, status.String(), time.Now())
+	rows, err := r.db.QueryContext(ctx, query, clientOrderID)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("list order events failed: %w", err)
 	}
+	defer rows.Close()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
+	events := []*model.OrderEvent{}
+	for rows.Next() {
+		var (
+			e                  model.OrderEvent
+			fromStatus, filled string
+			toStatus           string
+			exchangeTime       sql.NullTime
+		)
+		err := rows.Scan(
+			&e.ID, &e.ClientOrderID, &fromStatus, &toStatus, &filled, &e.Sequence,
+			&exchangeTime, &e.Source, &e.Reason, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan order event failed: %w", err)
+		}
+		e.FromStatus = model.ParseOrderStatus(fromStatus)
+		e.ToStatus = model.ParseOrderStatus(toStatus)
+		e.Filled = model.MustMoney(filled)
+		e.ExchangeTime = exchangeTime.Time
+		events = append(events, &e)
 	}
+	return events, rows.Err()
+}
 
-	if rows == 0 {
-		return fmt.Errorf("order not found: %s", clientOrderID)
+// insertEvent 在事务内追加订单事件（订单创建事件的 from_status 为空字符串）
+func insertEvent(ctx context.Context, tx *sql.Tx, event *model.OrderEvent) error {
+	var fromStatus string
+	if event.FromStatus != 0 {
+		fromStatus = event.FromStatus.String()
 	}
 
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO order_events (
+			client_oid, from_status, to_status, filled_qty, sequence,
+			exchange_time, source, reason, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`,
+		event.ClientOrderID, fromStatus, event.ToStatus.String(), event.Filled.String(), event.Sequence,
+		nullTime(event.ExchangeTime), event.Source, event.Reason, event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("insert order event failed: %w", err)
+	}
 	return nil
 }
 
@@ -190,7 +359,7 @@ func (r *PostgresRepo) UpdateFilled(ctx context.Context, clientOrderID string, f
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("order not found: %s", clientOrderID)
+		return fmt.Errorf("%w: %s", port.ErrOrderNotFound, clientOrderID)
 	}
 
 	return nil
@@ -202,9 +371,11 @@ func (r *PostgresRepo) ListActiveOrders(ctx context.Context) ([]*model.Order, er
 		SELECT 
 			client_oid, order_id, symbol, side, type,
 			price, quantity, filled_qty, status,
-			created_at, updated_at
+			created_at, updated_at, COALESCE(strategy_id, ''),
+			last_sequence, exchange_updated_at, stop_price,
+			expire_at, max_age_ms, expire_policy, reprice_count
 		FROM orders
-		WHERE status IN ('PENDING', 'SUBMITTED', 'PARTIAL_FILLED')
+		WHERE status IN ('PENDING', 'SUBMITTED', 'PARTIAL_FILLED', 'UNKNOWN')
 		ORDER BY created_at DESC
 	`
 
@@ -218,31 +389,48 @@ func (r *PostgresRepo) ListActiveOrders(ctx context.Context) ([]*model.Order, er
 	for rows.Next() {
 		var (
 			clientOid, exchangeID, symbol, side, orderType, status string
-			price, quantity, filled                                 string
-			createdAt, updatedAt                                    time.Time
+			price, quantity, filled, stopPrice                     string
+			createdAt, updatedAt                                   time.Time
+			strategyID                                             string
+			sequence                                               int64
+			exchangeUpdatedAt                                      sql.NullTime
+			expireAt                                               sql.NullTime
+			maxAgeMs                                               int64
+			expirePolicy                                           string
+			reprices                                               int
 		)
 
 		err := rows.Scan(
 			&clientOid, &exchangeID, &symbol, &side, &orderType,
 			&price, &quantity, &filled, &status,
-			&createdAt, &updatedAt,
+			&createdAt, &updatedAt, &strategyID,
+			&sequence, &exchangeUpdatedAt, &stopPrice,
+			&expireAt, &maxAgeMs, &expirePolicy, &reprices,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan order failed: %w", err)
 		}
 
 		orders = append(orders, &model.Order{
-			ClientOrderID: clientOid,
-			ExchangeID:    exchangeID,
-			Symbol:        symbol,
-			Side:          parseOrderSide(side),
-			Type:          parseOrderType(orderType),
-			Price:         model.MustMoney(price),
-			Quantity:      model.MustMoney(quantity),
-			Filled:        model.MustMoney(filled),
-			Status:        parseOrderStatus(status),
-			CreatedAt:     createdAt,
-			UpdatedAt:     updatedAt,
+			ClientOrderID:      clientOid,
+			ExchangeID:         exchangeID,
+			Symbol:             symbol,
+			Side:               parseOrderSide(side),
+			Type:               parseOrderType(orderType),
+			Price:              model.MustMoney(price),
+			StopPrice:          model.MustMoney(stopPrice),
+			Quantity:           model.MustMoney(quantity),
+			Filled:             model.MustMoney(filled),
+			Status:             model.ParseOrderStatus(status),
+			CreatedAt:          createdAt,
+			UpdatedAt:          updatedAt,
+			StrategyID:         strategyID,
+			Sequence:           sequence,
+			ExchangeUpdateTime: exchangeUpdatedAt.Time,
+			ExpireAt:           expireAt.Time,
+			MaxAge:             time.Duration(maxAgeMs) * time.Millisecond,
+			ExpirePolicy:       model.ParseExpirePolicy(expirePolicy),
+			Reprices:           reprices,
 		})
 	}
 
@@ -255,7 +443,9 @@ func (r *PostgresRepo) ListOrdersBySymbol(ctx context.Context, symbol string, li
 		SELECT 
 			client_oid, order_id, symbol, side, type,
 			price, quantity, filled_qty, status,
-			created_at, updated_at
+			created_at, updated_at, COALESCE(strategy_id, ''),
+			last_sequence, exchange_updated_at, stop_price,
+			expire_at, max_age_ms, expire_policy, reprice_count
 		FROM orders
 		WHERE symbol = $1
 		ORDER BY created_at DESC
@@ -272,31 +462,48 @@ func (r *PostgresRepo) ListOrdersBySymbol(ctx context.Context, symbol string, li
 	for rows.Next() {
 		var (
 			clientOid, exchangeID, sym, side, orderType, status string
-			price, quantity, filled                             string
+			price, quantity, filled, stopPrice                  string
 			createdAt, updatedAt                                time.Time
+			strategyID                                          string
+			sequence                                            int64
+			exchangeUpdatedAt                                   sql.NullTime
+			expireAt                                            sql.NullTime
+			maxAgeMs                                            int64
+			expirePolicy                                        string
+			reprices                                            int
 		)
 
 		err := rows.Scan(
 			&clientOid, &exchangeID, &sym, &side, &orderType,
 			&price, &quantity, &filled, &status,
-			&createdAt, &updatedAt,
+			&createdAt, &updatedAt, &strategyID,
+			&sequence, &exchangeUpdatedAt, &stopPrice,
+			&expireAt, &maxAgeMs, &expirePolicy, &reprices,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan order failed: %w", err)
 		}
 
 		orders = append(orders, &model.Order{
-			ClientOrderID: clientOid,
-			ExchangeID:    exchangeID,
-			Symbol:        sym,
-			Side:          parseOrderSide(side),
-			Type:          parseOrderType(orderType),
-			Price:         model.MustMoney(price),
-			Quantity:      model.MustMoney(quantity),
-			Filled:        model.MustMoney(filled),
-			Status:        parseOrderStatus(status),
-			CreatedAt:     createdAt,
-			UpdatedAt:     updatedAt,
+			ClientOrderID:      clientOid,
+			ExchangeID:         exchangeID,
+			Symbol:             sym,
+			Side:               parseOrderSide(side),
+			Type:               parseOrderType(orderType),
+			Price:              model.MustMoney(price),
+			StopPrice:          model.MustMoney(stopPrice),
+			Quantity:           model.MustMoney(quantity),
+			Filled:             model.MustMoney(filled),
+			Status:             model.ParseOrderStatus(status),
+			CreatedAt:          createdAt,
+			UpdatedAt:          updatedAt,
+			StrategyID:         strategyID,
+			Sequence:           sequence,
+			ExchangeUpdateTime: exchangeUpdatedAt.Time,
+			ExpireAt:           expireAt.Time,
+			MaxAge:             time.Duration(maxAgeMs) * time.Millisecond,
+			ExpirePolicy:       model.ParseExpirePolicy(expirePolicy),
+			Reprices:           reprices,
 		})
 	}
 
@@ -315,40 +522,25 @@ func parseOrderSide(s string) model.OrderSide {
 	}
 }
 
-// 辅助函数：解析订单类型
+// 辅助函数：解析订单类型（含条件单）
 func parseOrderType(t string) model.OrderType {
-	switch t {
-	case "LIMIT":
-		return model.OrderTypeLimit
-	case "MARKET":
-		return model.OrderTypeMarket
-	case "IOC":
-		return model.OrderTypeIOC
-	case "FOK":
-		return model.OrderTypeFOK
-	default:
-		return 0
+	return model.ParseOrderType(t)
+}
+
+// nullTime 零值时间转为 NULL
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
 	}
+	return t
 }
 
-// 辅助函数：解析订单状态
-func parseOrderStatus(s string) model.OrderStatus {
-	switch s {
-	case "PENDING":
-		return model.OrderStatusPending
-	case "SUBMITTED":
-		return model.OrderStatusSubmitted
-	case "PARTIAL_FILLED":
-		return model.OrderStatusPartialFilled
-	case "FILLED":
-		return model.OrderStatusFilled
-	case "CANCELLED":
-		return model.OrderStatusCancelled
-	case "REJECTED":
-		return model.OrderStatusRejected
-	default:
-		return 0
+// expirePolicyName 挂单超时处理方式的存储值（未设置时为空）
+func expirePolicyName(policy model.ExpirePolicy) string {
+	if policy == 0 {
+		return ""
 	}
+	return policy.String()
 }
 
 // 辅助函数：从 Symbol 推断交易所名称（简化实现）
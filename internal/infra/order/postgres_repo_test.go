@@ -3,6 +3,7 @@ package order
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -139,7 +140,27 @@ func TestPostgresRepo_Integration(t *testing.T) {
 		}
 	})
 
+	t.Run("ListOrderEvents", func(t *testing.T) {
+		// 已成交订单不能回退
+		err := repo.UpdateOrderStatus(ctx, clientOrderID, model.OrderStatusSubmitted)
+		if !errors.Is(err, model.ErrInvalidOrderTransition) {
+			t.Errorf("expected ErrInvalidOrderTransition, got %v", err)
+		}
+
+		events, err := repo.ListOrderEvents(ctx, clientOrderID)
+		if err != nil {
+			t.Fatalf("ListOrderEvents failed: %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+		if events[0].ToStatus != model.OrderStatusPending || events[1].ToStatus != model.OrderStatusFilled {
+			t.Errorf("unexpected events: %v -> %v", events[0].ToStatus, events[1].ToStatus)
+		}
+	})
+
 	// 清理测试数据
+	_, _ = db.ExecContext(ctx, "DELETE FROM order_events WHERE client_oid LIKE 'test-order-%' OR client_oid LIKE 'active-%'")
 	_, _ = db.ExecContext(ctx, "DELETE FROM orders WHERE client_oid LIKE 'test-order-%' OR client_oid LIKE 'active-%'")
 }
 
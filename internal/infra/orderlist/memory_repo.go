@@ -0,0 +1,65 @@
+package orderlist
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+// MemoryRepo 内存订单组仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu    sync.RWMutex
+	lists map[string]*model.OrderList // key: ListID
+}
+
+// NewMemoryRepo 创建内存订单组仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		lists: make(map[string]*model.OrderList),
+	}
+}
+
+// SaveOrderList 保存订单组（幂等）
+func (r *MemoryRepo) SaveOrderList(ctx context.Context, list *model.OrderList) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *list
+	r.lists[list.ListID] = &copied
+	return nil
+}
+
+// GetOrderList 根据 ListID 获取订单组
+func (r *MemoryRepo) GetOrderList(ctx context.Context, listID string) (*model.OrderList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list, ok := r.lists[listID]
+	if !ok {
+		return nil, port.ErrOrderListNotFound
+	}
+	copied := *list
+	return &copied, nil
+}
+
+// ListActiveOrderLists 列出所有未完结的订单组（按创建时间升序）
+func (r *MemoryRepo) ListActiveOrderLists(ctx context.Context) ([]*model.OrderList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*model.OrderList
+	for _, list := range r.lists {
+		if list.IsActive() {
+			copied := *list
+			result = append(result, &copied)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package orderlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+func TestMemoryRepo_OrderLists(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+
+	if _, err := repo.GetOrderList(ctx, "missing"); !errors.Is(err, port.ErrOrderListNotFound) {
+		t.Fatalf("expected ErrOrderListNotFound, got %v", err)
+	}
+
+	now := time.Now()
+	bracket := &model.OrderList{
+		ListID:             "bracket-1",
+		Type:               model.OrderListTypeBracket,
+		Status:             model.OrderListStatusPending,
+		Symbol:             "BTCUSDT",
+		EntryClientOrderID: "entry-1",
+		ExitSide:           model.OrderSideSell,
+		TakeProfitPrice:    model.MustMoney("52000"),
+		StopPrice:          model.MustMoney("49000"),
+		CreatedAt:          now,
+	}
+	oco := &model.OrderList{
+		ListID:    "oco-1",
+		Type:      model.OrderListTypeOCO,
+		Status:    model.OrderListStatusActive,
+		Symbol:    "BTCUSDT",
+		ExitSide:  model.OrderSideSell,
+		CreatedAt: now.Add(-time.Minute),
+	}
+	for _, l := range []*model.OrderList{bracket, oco} {
+		if err := repo.SaveOrderList(ctx, l); err != nil {
+			t.Fatalf("SaveOrderList failed: %v", err)
+		}
+	}
+
+	// 修改入参不影响仓储
+	bracket.StopPrice = model.MustMoney("1")
+	got, err := repo.GetOrderList(ctx, "bracket-1")
+	if err != nil || !got.StopPrice.EQ(model.MustMoney("49000")) {
+		t.Fatalf("unexpected order list: %+v (%v)", got, err)
+	}
+
+	active, _ := repo.ListActiveOrderLists(ctx)
+	if len(active) != 2 || active[0].ListID != "oco-1" {
+		t.Fatalf("expected 2 active lists ordered by creation, got %d", len(active))
+	}
+
+	// 完结后不再列出
+	oco.Status = model.OrderListStatusDone
+	if err := repo.SaveOrderList(ctx, oco); err != nil {
+		t.Fatalf("SaveOrderList failed: %v", err)
+	}
+	active, _ = repo.ListActiveOrderLists(ctx)
+	if len(active) != 1 || active[0].ListID != "bracket-1" {
+		t.Errorf("expected only bracket-1 active, got %d", len(active))
+	}
+}
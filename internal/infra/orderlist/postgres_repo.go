@@ -0,0 +1,141 @@
+package orderlist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+)
+
+// PostgresRepo PostgreSQL 订单组仓储（实现 port.OrderListRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 订单组仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+const orderListColumns = `list_id, list_type, status, symbol, account_id, strategy_id,
+	entry_client_oid, exit_side, quantity, take_profit_price, stop_price, stop_limit_price,
+	take_profit_client_oid, stop_loss_client_oid, exchange_list_id, native, reason,
+	created_at, updated_at`
+
+// SaveOrderList 保存订单组（幂等，创建后类型、交易对与归属不变）
+func (r *PostgresRepo) SaveOrderList(ctx context.Context, list *model.OrderList) error {
+	query := `
+		INSERT INTO order_lists (` + orderListColumns + `) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15, $16, $17, $18, $19
+		)
+		ON CONFLICT (list_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			quantity = EXCLUDED.quantity,
+			take_profit_client_oid = EXCLUDED.take_profit_client_oid,
+			stop_loss_client_oid = EXCLUDED.stop_loss_client_oid,
+			exchange_list_id = EXCLUDED.exchange_list_id,
+			native = EXCLUDED.native,
+			reason = EXCLUDED.reason,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		list.ListID,
+		list.Type.String(),
+		list.Status.String(),
+		list.Symbol,
+		list.AccountID,
+		list.StrategyID,
+		list.EntryClientOrderID,
+		list.ExitSide.String(),
+		list.Quantity.String(),
+		list.TakeProfitPrice.String(),
+		list.StopPrice.String(),
+		list.StopLimitPrice.String(),
+		list.TakeProfitClientOrderID,
+		list.StopLossClientOrderID,
+		list.ExchangeListID,
+		list.Native,
+		list.Reason,
+		list.CreatedAt,
+		list.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save order list failed: %w", err)
+	}
+	return nil
+}
+
+// GetOrderList 根据 ListID 获取订单组
+func (r *PostgresRepo) GetOrderList(ctx context.Context, listID string) (*model.OrderList, error) {
+	query := `SELECT ` + orderListColumns + ` FROM order_lists WHERE list_id = $1`
+
+	list, err := scanOrderList(r.db.QueryRowContext(ctx, query, listID))
+	if err == sql.ErrNoRows {
+		return nil, port.ErrOrderListNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get order list failed: %w", err)
+	}
+	return list, nil
+}
+
+// ListActiveOrderLists 列出所有未完结的订单组（按创建时间升序）
+func (r *PostgresRepo) ListActiveOrderLists(ctx context.Context) ([]*model.OrderList, error) {
+	query := `SELECT ` + orderListColumns + ` FROM order_lists
+		WHERE status IN ('PENDING', 'ACTIVE')
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list active order lists failed: %w", err)
+	}
+	defer rows.Close()
+
+	var lists []*model.OrderList
+	for rows.Next() {
+		list, err := scanOrderList(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan order list failed: %w", err)
+		}
+		lists = append(lists, list)
+	}
+	return lists, rows.Err()
+}
+
+// rowScanner 兼容 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanOrderList 扫描一行订单组
+func scanOrderList(row rowScanner) (*model.OrderList, error) {
+	var (
+		l                                          model.OrderList
+		listType, status, exitSide                 string
+		quantity, takeProfit, stopPrice, stopLimit string
+	)
+	err := row.Scan(
+		&l.ListID, &listType, &status, &l.Symbol, &l.AccountID, &l.StrategyID,
+		&l.EntryClientOrderID, &exitSide, &quantity, &takeProfit, &stopPrice, &stopLimit,
+		&l.TakeProfitClientOrderID, &l.StopLossClientOrderID, &l.ExchangeListID, &l.Native, &l.Reason,
+		&l.CreatedAt, &l.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Type = model.ParseOrderListType(listType)
+	l.Status = model.ParseOrderListStatus(status)
+	l.ExitSide = model.ParseOrderSide(exitSide)
+	l.Quantity = model.MustMoney(quantity)
+	l.TakeProfitPrice = model.MustMoney(takeProfit)
+	l.StopPrice = model.MustMoney(stopPrice)
+	l.StopLimitPrice = model.MustMoney(stopLimit)
+	return &l, nil
+}
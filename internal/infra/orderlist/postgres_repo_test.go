@@ -0,0 +1,119 @@
+package orderlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	listID := "test-list-" + time.Now().Format("20060102150405")
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		list := &model.OrderList{
+			ListID:             listID,
+			Type:               model.OrderListTypeBracket,
+			Status:             model.OrderListStatusPending,
+			Symbol:             "BTCUSDT",
+			AccountID:          "test-account",
+			StrategyID:         "SimpleVolatility",
+			EntryClientOrderID: listID + "-entry",
+			ExitSide:           model.OrderSideSell,
+			Quantity:           model.Zero(),
+			TakeProfitPrice:    model.MustMoney("52000"),
+			StopPrice:          model.MustMoney("49000"),
+			StopLimitPrice:     model.Zero(),
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		}
+		if err := repo.SaveOrderList(ctx, list); err != nil {
+			t.Fatalf("SaveOrderList failed: %v", err)
+		}
+
+		got, err := repo.GetOrderList(ctx, listID)
+		if err != nil {
+			t.Fatalf("GetOrderList failed: %v", err)
+		}
+		if got.Type != model.OrderListTypeBracket || got.Status != model.OrderListStatusPending ||
+			got.ExitSide != model.OrderSideSell || !got.StopPrice.EQ(model.MustMoney("49000")) {
+			t.Errorf("unexpected order list: %+v", got)
+		}
+	})
+
+	t.Run("ArmAndComplete", func(t *testing.T) {
+		list, _ := repo.GetOrderList(ctx, listID)
+		list.Status = model.OrderListStatusActive
+		list.Quantity = model.MustMoney("0.1")
+		list.TakeProfitClientOrderID = listID + "-tp"
+		list.StopLossClientOrderID = listID + "-sl"
+		list.UpdatedAt = time.Now()
+		if err := repo.SaveOrderList(ctx, list); err != nil {
+			t.Fatalf("SaveOrderList failed: %v", err)
+		}
+
+		active, err := repo.ListActiveOrderLists(ctx)
+		if err != nil {
+			t.Fatalf("ListActiveOrderLists failed: %v", err)
+		}
+		var found *model.OrderList
+		for _, l := range active {
+			if l.ListID == listID {
+				found = l
+			}
+		}
+		if found == nil || found.TakeProfitClientOrderID != listID+"-tp" || !found.Quantity.EQ(model.MustMoney("0.1")) {
+			t.Fatalf("armed list not listed as active: %+v", found)
+		}
+
+		list.Status = model.OrderListStatusDone
+		list.Reason = "take profit filled"
+		if err := repo.SaveOrderList(ctx, list); err != nil {
+			t.Fatalf("SaveOrderList failed: %v", err)
+		}
+		active, _ = repo.ListActiveOrderLists(ctx)
+		for _, l := range active {
+			if l.ListID == listID {
+				t.Error("completed list should not be active")
+			}
+		}
+	})
+
+	if _, err := repo.GetOrderList(ctx, listID+"-missing"); !errors.Is(err, port.ErrOrderListNotFound) {
+		t.Errorf("expected ErrOrderListNotFound, got %v", err)
+	}
+
+	// 清理测试数据
+	_, _ = db.ExecContext(ctx, "DELETE FROM order_lists WHERE list_id = $1", listID)
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
@@ -13,7 +13,7 @@ import (
 // MemoryRiskRepo 内存风控状态仓储（回测/测试用）
 type MemoryRiskRepo struct {
 	mu     sync.RWMutex
-	states map[string]*model.RiskState // key: accountID:symbol
+	states map[string]*model.RiskState // key: RiskScope.Key()
 }
 
 // NewMemoryRiskRepo 创建内存仓储
@@ -24,19 +24,18 @@ func NewMemoryRiskRepo() port.RiskRepo {
 }
 
 // LoadState 加载风控状态
-func (r *MemoryRiskRepo) LoadState(ctx context.Context, accountID, symbol string) (*model.RiskState, error) {
+func (r *MemoryRiskRepo) LoadState(ctx context.Context, scope model.RiskScope) (*model.RiskState, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	key := makeKey(accountID, symbol)
-	state, exists := r.states[key]
+	state, exists := r.states[scope.Key()]
 	if !exists {
 		// 不存在则返回默认状态
-		return model.NewRiskState(accountID, model.MustMoney("10000")), nil
+		return model.NewScopedRiskState(scope, model.MustMoney("10000")), nil
 	}
 
 	// 返回副本，避免外部修改
-	return copyState(state), nil
+	return state.Clone(), nil
 }
 
 // SaveState 保存风控状态（幂等）
@@ -44,8 +43,31 @@ func (r *MemoryRiskRepo) SaveState(ctx context.Context, state *model.RiskState)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := makeKey(state.AccountID, "")
-	r.states[key] = copyState(state)
+	key := state.Scope().Key()
+	state.Version = 1
+	if stored, exists := r.states[key]; exists {
+		state.Version = stored.Version + 1
+	}
+	r.states[key] = state.Clone()
+	return nil
+}
+
+// CompareAndSwapState 乐观锁写入
+func (r *MemoryRiskRepo) CompareAndSwapState(ctx context.Context, state *model.RiskState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := state.Scope().Key()
+	var current int64
+	if stored, exists := r.states[key]; exists {
+		current = stored.Version
+	}
+	if current != state.Version {
+		return port.ErrRiskStateConflict
+	}
+
+	state.Version++
+	r.states[key] = state.Clone()
 	return nil
 }
 
@@ -54,13 +76,14 @@ func (r *MemoryRiskRepo) UpdateEquity(ctx context.Context, accountID string, new
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := makeKey(accountID, "")
+	key := model.AccountScope(accountID).Key()
 	state, exists := r.states[key]
 	if !exists {
 		state = model.NewRiskState(accountID, newEquity)
 	}
 
 	state.UpdateEquity(newEquity)
+	state.Version++
 	r.states[key] = state
 	return nil
 }
@@ -70,25 +93,15 @@ func (r *MemoryRiskRepo) RecordTrade(ctx context.Context, accountID string, pnl
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := makeKey(accountID, "")
+	key := model.AccountScope(accountID).Key()
 	state, exists := r.states[key]
 	if !exists {
 		return fmt.Errorf("account %s not found", accountID)
 	}
 
-	// 更新当日盈亏
-	state.DailyPnL = state.DailyPnL.Add(pnl)
-	state.DailyTradeCount++
-
-	// 更新连续亏损
-	if pnl.IsNegative() {
-		state.RecordLoss()
-	} else if pnl.IsPositive() {
-		state.ResetConsecutiveLosses()
-	}
-
-	state.UpdatedAt = time.Now()
-	r.states[key] = state
+	// 更新当日盈亏与连续亏损
+	state.RecordTrade(pnl)
+	state.Version++
 	return nil
 }
 
@@ -97,14 +110,14 @@ func (r *MemoryRiskRepo) OpenCircuitBreaker(ctx context.Context, accountID strin
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := makeKey(accountID, "")
+	key := model.AccountScope(accountID).Key()
 	state, exists := r.states[key]
 	if !exists {
 		return fmt.Errorf("account %s not found", accountID)
 	}
 
 	state.OpenCircuitBreaker(time.Duration(durationSec) * time.Second)
-	r.states[key] = state
+	state.Version++
 	return nil
 }
 
@@ -113,14 +126,14 @@ func (r *MemoryRiskRepo) CloseCircuitBreaker(ctx context.Context, accountID stri
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := makeKey(accountID, "")
+	key := model.AccountScope(accountID).Key()
 	state, exists := r.states[key]
 	if !exists {
 		return fmt.Errorf("account %s not found", accountID)
 	}
 
 	state.CloseCircuitBreaker()
-	r.states[key] = state
+	state.Version++
 	return nil
 }
 
@@ -129,7 +142,7 @@ func (r *MemoryRiskRepo) IsCircuitBreakerOpen(ctx context.Context, accountID str
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	key := makeKey(accountID, "")
+	key := model.AccountScope(accountID).Key()
 	state, exists := r.states[key]
 	if !exists {
 		return false, nil
@@ -141,42 +154,3 @@ func (r *MemoryRiskRepo) IsCircuitBreakerOpen(ctx context.Context, accountID str
 
 	return false, nil
 }
-
-// makeKey 生成存储键
-func makeKey(accountID, symbol string) string {
-	if symbol == "" {
-		return accountID
-	}
-	return accountID + ":" + symbol
-}
-
-// copyState 深拷贝状态（避免并发修改）
-func copyState(state *model.RiskState) *model.RiskState {
-	copied := &model.RiskState{
-		AccountID:           state.AccountID,
-		Symbol:              state.Symbol,
-		InitialEquity:       state.InitialEquity,
-		CurrentEquity:       state.CurrentEquity,
-		PeakEquity:          state.PeakEquity,
-		DailyPnL:            state.DailyPnL,
-		DailyTradeCount:     state.DailyTradeCount,
-		DailyResetTime:      state.DailyResetTime,
-		LastResetDate:       state.LastResetDate,
-		ConsecutiveLosses:   state.ConsecutiveLosses,
-		LastLossTime:        state.LastLossTime,
-		MDD:                 state.MDD,
-		MDDPercent:          state.MDDPercent,
-		TotalExposure:       state.TotalExposure,
-		CircuitBreakerOpen:  state.CircuitBreakerOpen,
-		CircuitBreakerUntil: state.CircuitBreakerUntil,
-		UpdatedAt:           state.UpdatedAt,
-		PositionMap:         make(map[string]model.Money),
-	}
-
-	// 拷贝 map
-	for k, v := range state.PositionMap {
-		copied.PositionMap[k] = v
-	}
-
-	return copied
-}
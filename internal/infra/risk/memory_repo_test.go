@@ -2,9 +2,11 @@ package risk
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
 func TestMemoryRiskRepo_SaveAndLoad(t *testing.T) {
@@ -22,7 +24,7 @@ func TestMemoryRiskRepo_SaveAndLoad(t *testing.T) {
 	}
 
 	// 加载状态
-	loaded, err := repo.LoadState(ctx, "test-account", "")
+	loaded, err := repo.LoadState(ctx, model.AccountScope("test-account"))
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
@@ -50,7 +52,7 @@ func TestMemoryRiskRepo_UpdateEquity(t *testing.T) {
 	}
 
 	// 验证
-	loaded, _ := repo.LoadState(ctx, "test", "")
+	loaded, _ := repo.LoadState(ctx, model.AccountScope("test"))
 	if loaded.CurrentEquity.String() != "12000" {
 		t.Errorf("CurrentEquity = %s, want 12000", loaded.CurrentEquity.String())
 	}
@@ -70,7 +72,7 @@ func TestMemoryRiskRepo_RecordTrade(t *testing.T) {
 	_ = repo.RecordTrade(ctx, "test", model.MustMoney("-100"))
 	_ = repo.RecordTrade(ctx, "test", model.MustMoney("-200"))
 
-	loaded, _ := repo.LoadState(ctx, "test", "")
+	loaded, _ := repo.LoadState(ctx, model.AccountScope("test"))
 	if loaded.DailyPnL.String() != "-300" {
 		t.Errorf("DailyPnL = %s, want -300", loaded.DailyPnL.String())
 	}
@@ -84,7 +86,7 @@ func TestMemoryRiskRepo_RecordTrade(t *testing.T) {
 	// 记录盈利（重置连续亏损）
 	_ = repo.RecordTrade(ctx, "test", model.MustMoney("150"))
 
-	loaded, _ = repo.LoadState(ctx, "test", "")
+	loaded, _ = repo.LoadState(ctx, model.AccountScope("test"))
 	if loaded.ConsecutiveLosses != 0 {
 		t.Errorf("ConsecutiveLosses = %d, want 0 after profit", loaded.ConsecutiveLosses)
 	}
@@ -123,3 +125,144 @@ func TestMemoryRiskRepo_CircuitBreaker(t *testing.T) {
 		t.Error("Expected circuit breaker to be closed")
 	}
 }
+
+// newRoundTripState 覆盖全部金额字段的风控状态（用于各仓储的往返测试）
+func newRoundTripState(accountID string) *model.RiskState {
+	state := model.NewRiskState(accountID, model.MustMoney("10000.123456789012345678"))
+	state.CurrentEquity = model.MustMoney("9500.5")
+	state.PeakEquity = model.MustMoney("11000.000000000000000001")
+	state.DailyPnL = model.MustMoney("-499.623456789012345678")
+	state.DailyTradeCount = 5
+	state.ConsecutiveLosses = 2
+	state.MDD = model.MustMoney("1499.5")
+	state.MDDPercent = model.MustMoney("0.136318181818181818")
+	state.TotalExposure = model.MustMoney("3000.25")
+	state.PositionMap["BTCUSDT"] = model.MustMoney("2000.125")
+	state.PositionMap["ETHUSDT"] = model.MustMoney("1000.125")
+	return state
+}
+
+// assertRoundTrip 校验金额字段无损往返
+func assertRoundTrip(t *testing.T, got, want *model.RiskState) {
+	t.Helper()
+
+	checks := []struct {
+		name      string
+		got, want model.Money
+	}{
+		{"InitialEquity", got.InitialEquity, want.InitialEquity},
+		{"CurrentEquity", got.CurrentEquity, want.CurrentEquity},
+		{"PeakEquity", got.PeakEquity, want.PeakEquity},
+		{"DailyPnL", got.DailyPnL, want.DailyPnL},
+		{"MDD", got.MDD, want.MDD},
+		{"MDDPercent", got.MDDPercent, want.MDDPercent},
+		{"TotalExposure", got.TotalExposure, want.TotalExposure},
+	}
+	for _, c := range checks {
+		if !c.got.EQ(c.want) {
+			t.Errorf("%s = %s, want %s", c.name, c.got, c.want)
+		}
+	}
+
+	if len(got.PositionMap) != len(want.PositionMap) {
+		t.Fatalf("PositionMap size = %d, want %d", len(got.PositionMap), len(want.PositionMap))
+	}
+	for symbol, notional := range want.PositionMap {
+		if !got.PositionMap[symbol].EQ(notional) {
+			t.Errorf("PositionMap[%s] = %s, want %s", symbol, got.PositionMap[symbol], notional)
+		}
+	}
+	if got.DailyTradeCount != want.DailyTradeCount || got.ConsecutiveLosses != want.ConsecutiveLosses {
+		t.Errorf("counters = %d/%d, want %d/%d",
+			got.DailyTradeCount, got.ConsecutiveLosses, want.DailyTradeCount, want.ConsecutiveLosses)
+	}
+}
+
+func TestMemoryRiskRepo_RoundTrip(t *testing.T) {
+	repo := NewMemoryRiskRepo()
+	ctx := context.Background()
+	state := newRoundTripState("roundtrip-account")
+
+	if err := repo.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	// 修改原对象不影响已保存数据
+	state.PositionMap["BTCUSDT"] = model.Zero()
+	expected := newRoundTripState("roundtrip-account")
+
+	loaded, err := repo.LoadState(ctx, model.AccountScope("roundtrip-account"))
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	assertRoundTrip(t, loaded, expected)
+}
+
+func TestMemoryRiskRepo_ScopedStates(t *testing.T) {
+	repo := NewMemoryRiskRepo()
+	ctx := context.Background()
+
+	strategy := model.RiskScope{AccountID: "acc", StrategyID: "alpha"}
+	state := model.NewScopedRiskState(strategy, model.MustMoney("10000"))
+	state.ConsecutiveLosses = 3
+	if err := repo.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	loaded, _ := repo.LoadState(ctx, strategy)
+	if loaded.ConsecutiveLosses != 3 || loaded.Scope() != strategy {
+		t.Errorf("unexpected strategy state: %+v", loaded)
+	}
+
+	// 其他作用域互不影响
+	for _, scope := range []model.RiskScope{
+		model.AccountScope("acc"),
+		{AccountID: "acc", StrategyID: "beta"},
+		{AccountID: "acc", StrategyID: "alpha", Symbol: "BTCUSDT"},
+	} {
+		other, _ := repo.LoadState(ctx, scope)
+		if other.ConsecutiveLosses != 0 || other.Scope() != scope {
+			t.Errorf("scope %s: unexpected state %+v", scope, other)
+		}
+	}
+}
+
+func TestMemoryRiskRepo_CompareAndSwap(t *testing.T) {
+	repo := NewMemoryRiskRepo()
+	ctx := context.Background()
+
+	// 首次写入：版本 0 仅在不存在时创建
+	first := model.NewRiskState("cas", model.MustMoney("10000"))
+	if err := repo.CompareAndSwapState(ctx, first); err != nil {
+		t.Fatalf("CompareAndSwapState failed: %v", err)
+	}
+	if first.Version != 1 {
+		t.Errorf("expected version 1, got %d", first.Version)
+	}
+	if err := repo.CompareAndSwapState(ctx, model.NewRiskState("cas", model.MustMoney("10000"))); !errors.Is(err, port.ErrRiskStateConflict) {
+		t.Errorf("expected conflict on duplicate create, got %v", err)
+	}
+
+	// 两个副本基于同一版本修改：后写入者冲突
+	a, _ := repo.LoadState(ctx, model.AccountScope("cas"))
+	b, _ := repo.LoadState(ctx, model.AccountScope("cas"))
+	a.ConsecutiveLosses = 1
+	if err := repo.CompareAndSwapState(ctx, a); err != nil {
+		t.Fatalf("CompareAndSwapState failed: %v", err)
+	}
+	b.ConsecutiveLosses = 5
+	if err := repo.CompareAndSwapState(ctx, b); !errors.Is(err, port.ErrRiskStateConflict) {
+		t.Errorf("expected conflict on stale version, got %v", err)
+	}
+
+	// 原子操作同样递增版本，使持有旧版本的写入失败
+	_ = repo.RecordTrade(ctx, "cas", model.MustMoney("-10"))
+	if err := repo.CompareAndSwapState(ctx, a); !errors.Is(err, port.ErrRiskStateConflict) {
+		t.Errorf("expected conflict after RecordTrade, got %v", err)
+	}
+
+	loaded, _ := repo.LoadState(ctx, model.AccountScope("cas"))
+	if loaded.ConsecutiveLosses != 2 || loaded.Version != 3 {
+		t.Errorf("unexpected state: losses %d, version %d", loaded.ConsecutiveLosses, loaded.Version)
+	}
+}
@@ -3,14 +3,15 @@ package risk
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
 // PostgresRepo PostgreSQL 风控仓储（实现 port.RiskRepo 接口）
+// version 列为乐观锁版本：CompareAndSwapState 按行版本比较写入，原子 SQL 更新同时递增版本
 type PostgresRepo struct {
 	db *sql.DB
 }
@@ -23,14 +24,14 @@ func NewPostgresRepo(db *sql.DB) *PostgresRepo {
 }
 
 // LoadState 加载风控状态
-func (r *PostgresRepo) LoadState(ctx context.Context, accountID, symbol string) (*model.RiskState, error) {
+func (r *PostgresRepo) LoadState(ctx context.Context, scope model.RiskScope) (*model.RiskState, error) {
 	query := `
 		SELECT 
 			initial_equity, current_equity, peak_equity, daily_pnl,
 			consecutive_losses, circuit_breaker_open, circuit_breaker_until,
-			last_reset_date, state_data
+			last_reset_date, state_data, version
 		FROM risk_states
-		WHERE account_id = $1 AND symbol = $2
+		WHERE account_id = $1 AND strategy_id = $2 AND symbol = $3
 	`
 
 	var (
@@ -40,34 +41,44 @@ func (r *PostgresRepo) LoadState(ctx context.Context, accountID, symbol string)
 		circuitBreakerUntil                                sql.NullTime
 		lastResetDate                                      sql.NullTime
 		stateData                                          sql.NullString
+		version                                            int64
 	)
 
-	err := r.db.QueryRowContext(ctx, query, accountID, symbol).Scan(
+	err := r.db.QueryRowContext(ctx, query, scope.AccountID, scope.StrategyID, scope.Symbol).Scan(
 		&initialEquity, &currentEquity, &peakEquity, &dailyPnl,
 		&consecutiveLosses, &circuitBreakerOpen, &circuitBreakerUntil,
-		&lastResetDate, &stateData,
+		&lastResetDate, &stateData, &version,
 	)
 
 	if err == sql.ErrNoRows {
 		// 记录不存在，返回新状态
-		return model.NewRiskState(accountID, model.Zero()), nil
+		return model.NewScopedRiskState(scope, model.Zero()), nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("load risk state failed: %w", err)
 	}
 
-	// 构建状态对象
-	state := &model.RiskState{
-		AccountID:          accountID,
-		Symbol:             symbol,
-		InitialEquity:      model.MustMoney(initialEquity),
-		CurrentEquity:      model.MustMoney(currentEquity),
-		PeakEquity:         model.MustMoney(peakEquity),
-		DailyPnL:           model.MustMoney(dailyPnl),
-		ConsecutiveLosses:  consecutiveLosses,
-		CircuitBreakerOpen: circuitBreakerOpen,
+	// 完整状态来自 state_data（旧版本数据自动迁移），解析失败时仅使用列数据
+	state := model.NewScopedRiskState(scope, model.Zero())
+	if stateData.Valid && stateData.String != "" {
+		if decoded, _, err := model.DecodeRiskState([]byte(stateData.String)); err == nil {
+			state = decoded
+		}
 	}
 
+	// 独立列由 UpdateEquity / RecordTrade 等原子 SQL 更新，优先于 state_data 快照
+	state.AccountID = scope.AccountID
+	state.StrategyID = scope.StrategyID
+	state.Symbol = scope.Symbol
+	state.InitialEquity = model.MustMoney(initialEquity)
+	state.CurrentEquity = model.MustMoney(currentEquity)
+	state.PeakEquity = model.MustMoney(peakEquity)
+	state.DailyPnL = model.MustMoney(dailyPnl)
+	state.ConsecutiveLosses = consecutiveLosses
+	state.CircuitBreakerOpen = circuitBreakerOpen
+	state.CircuitBreakerUntil = 0
+	state.Version = version
+
 	if circuitBreakerUntil.Valid {
 		state.CircuitBreakerUntil = circuitBreakerUntil.Time.Unix()
 	}
@@ -75,36 +86,26 @@ func (r *PostgresRepo) LoadState(ctx context.Context, accountID, symbol string)
 		state.LastResetDate = lastResetDate.Time.Format("2006-01-02")
 	}
 
-	// 如果有完整的 JSON 数据，覆盖解析
-	if stateData.Valid && stateData.String != "" {
-		var fullState model.RiskState
-		if err := json.Unmarshal([]byte(stateData.String), &fullState); err == nil {
-			// JSON 数据优先
-			return &fullState, nil
-		}
-	}
-
 	return state, nil
 }
 
-// SaveState 保存风控状态
+// SaveState 保存风控状态（覆盖写入，版本加一）
 func (r *PostgresRepo) SaveState(ctx context.Context, state *model.RiskState) error {
-	// 序列化状态为 JSON
-	stateData, err := json.Marshal(state)
+	args, err := stateArgs(state)
 	if err != nil {
-		return fmt.Errorf("marshal state data failed: %w", err)
+		return err
 	}
 
 	// 使用 UPSERT (ON CONFLICT)
 	query := `
 		INSERT INTO risk_states (
-			account_id, symbol, initial_equity, current_equity, peak_equity,
+			account_id, strategy_id, symbol, initial_equity, current_equity, peak_equity,
 			daily_pnl, consecutive_losses, circuit_breaker_open,
-			circuit_breaker_until, last_reset_date, state_data, updated_at
+			circuit_breaker_until, last_reset_date, state_data, updated_at, version
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, 1
 		)
-		ON CONFLICT (account_id, symbol) DO UPDATE SET
+		ON CONFLICT (account_id, strategy_id, symbol) DO UPDATE SET
 			initial_equity = EXCLUDED.initial_equity,
 			current_equity = EXCLUDED.current_equity,
 			peak_equity = EXCLUDED.peak_equity,
@@ -114,9 +115,78 @@ func (r *PostgresRepo) SaveState(ctx context.Context, state *model.RiskState) er
 			circuit_breaker_until = EXCLUDED.circuit_breaker_until,
 			last_reset_date = EXCLUDED.last_reset_date,
 			state_data = EXCLUDED.state_data,
-			updated_at = EXCLUDED.updated_at
+			updated_at = EXCLUDED.updated_at,
+			version = risk_states.version + 1
+		RETURNING version
 	`
 
+	return r.db.QueryRowContext(ctx, query, args...).Scan(&state.Version)
+}
+
+// CompareAndSwapState 乐观锁写入（行版本比较）
+func (r *PostgresRepo) CompareAndSwapState(ctx context.Context, state *model.RiskState) error {
+	args, err := stateArgs(state)
+	if err != nil {
+		return err
+	}
+
+	var query string
+	if state.Version == 0 {
+		// 首次写入：仅在不存在时创建
+		query = `
+			INSERT INTO risk_states (
+				account_id, strategy_id, symbol, initial_equity, current_equity, peak_equity,
+				daily_pnl, consecutive_losses, circuit_breaker_open,
+				circuit_breaker_until, last_reset_date, state_data, updated_at, version
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, 1
+			)
+			ON CONFLICT (account_id, strategy_id, symbol) DO NOTHING
+		`
+	} else {
+		query = `
+			UPDATE risk_states
+			SET
+				initial_equity = $4,
+				current_equity = $5,
+				peak_equity = $6,
+				daily_pnl = $7,
+				consecutive_losses = $8,
+				circuit_breaker_open = $9,
+				circuit_breaker_until = $10,
+				last_reset_date = $11,
+				state_data = $12,
+				updated_at = $13,
+				version = version + 1
+			WHERE account_id = $1 AND strategy_id = $2 AND symbol = $3 AND version = $14
+		`
+		args = append(args, state.Version)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("compare and swap risk state failed: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return port.ErrRiskStateConflict
+	}
+
+	state.Version++
+	return nil
+}
+
+// stateArgs 风控状态写入参数（$1 - $13）
+func stateArgs(state *model.RiskState) ([]interface{}, error) {
+	// 序列化状态为版本化 JSON
+	stateData, err := model.EncodeRiskState(state)
+	if err != nil {
+		return nil, err
+	}
+
 	var circuitBreakerUntil interface{}
 	if state.CircuitBreakerUntil > 0 {
 		circuitBreakerUntil = time.Unix(state.CircuitBreakerUntil, 0)
@@ -128,8 +198,9 @@ func (r *PostgresRepo) SaveState(ctx context.Context, state *model.RiskState) er
 		lastResetDate = t
 	}
 
-	_, err = r.db.ExecContext(ctx, query,
+	return []interface{}{
 		state.AccountID,
+		state.StrategyID,
 		state.Symbol,
 		state.InitialEquity.String(),
 		state.CurrentEquity.String(),
@@ -141,9 +212,7 @@ func (r *PostgresRepo) SaveState(ctx context.Context, state *model.RiskState) er
 		lastResetDate,
 		stateData,
 		time.Now(),
-	)
-
-	return err
+	}, nil
 }
 
 // UpdateEquity 原子更新净值
@@ -156,8 +225,9 @@ func (r *PostgresRepo) UpdateEquity(ctx context.Context, accountID string, newEq
 				WHEN $2::DECIMAL > peak_equity THEN $2::DECIMAL 
 				ELSE peak_equity 
 			END,
-			updated_at = $3
-		WHERE account_id = $1 AND symbol = ''
+			updated_at = $3,
+			version = version + 1
+		WHERE account_id = $1 AND strategy_id = '' AND symbol = ''
 	`
 
 	_, err := r.db.ExecContext(ctx, query, accountID, newEquity.String(), time.Now())
@@ -175,8 +245,9 @@ func (r *PostgresRepo) RecordTrade(ctx context.Context, accountID string, pnl mo
 				WHEN $2::DECIMAL > 0 THEN 0
 				ELSE consecutive_losses
 			END,
-			updated_at = $3
-		WHERE account_id = $1 AND symbol = ''
+			updated_at = $3,
+			version = version + 1
+		WHERE account_id = $1 AND strategy_id = '' AND symbol = ''
 	`
 
 	_, err := r.db.ExecContext(ctx, query, accountID, pnl.String(), time.Now())
@@ -191,8 +262,9 @@ func (r *PostgresRepo) OpenCircuitBreaker(ctx context.Context, accountID string,
 		SET 
 			circuit_breaker_open = true,
 			circuit_breaker_until = $2,
-			updated_at = $3
-		WHERE account_id = $1 AND symbol = ''
+			updated_at = $3,
+			version = version + 1
+		WHERE account_id = $1 AND strategy_id = '' AND symbol = ''
 	`
 
 	_, err := r.db.ExecContext(ctx, query, accountID, until, time.Now())
@@ -206,8 +278,9 @@ func (r *PostgresRepo) CloseCircuitBreaker(ctx context.Context, accountID string
 		SET 
 			circuit_breaker_open = false,
 			circuit_breaker_until = NULL,
-			updated_at = $2
-		WHERE account_id = $1 AND symbol = ''
+			updated_at = $2,
+			version = version + 1
+		WHERE account_id = $1 AND strategy_id = '' AND symbol = ''
 	`
 
 	_, err := r.db.ExecContext(ctx, query, accountID, time.Now())
@@ -219,7 +292,7 @@ func (r *PostgresRepo) IsCircuitBreakerOpen(ctx context.Context, accountID strin
 	query := `
 		SELECT circuit_breaker_open, circuit_breaker_until
 		FROM risk_states
-		WHERE account_id = $1 AND symbol = ''
+		WHERE account_id = $1 AND strategy_id = '' AND symbol = ''
 	`
 
 	var (
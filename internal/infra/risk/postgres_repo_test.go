@@ -3,12 +3,14 @@ package risk
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"os"
 	"testing"
 	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
 // TestPostgresRepo_Integration 集成测试（需要真实数据库）
@@ -45,7 +47,7 @@ func TestPostgresRepo_Integration(t *testing.T) {
 		}
 
 		// 加载
-		loaded, err := repo.LoadState(ctx, accountID, "")
+		loaded, err := repo.LoadState(ctx, model.AccountScope(accountID))
 		if err != nil {
 			t.Fatalf("LoadState failed: %v", err)
 		}
@@ -62,6 +64,24 @@ func TestPostgresRepo_Integration(t *testing.T) {
 		}
 	})
 
+	t.Run("RoundTrip", func(t *testing.T) {
+		roundTripID := accountID + "-roundtrip"
+		defer func() {
+			_, _ = db.ExecContext(ctx, "DELETE FROM risk_states WHERE account_id = $1", roundTripID)
+		}()
+
+		state := newRoundTripState(roundTripID)
+		if err := repo.SaveState(ctx, state); err != nil {
+			t.Fatalf("SaveState failed: %v", err)
+		}
+
+		loaded, err := repo.LoadState(ctx, model.AccountScope(roundTripID))
+		if err != nil {
+			t.Fatalf("LoadState failed: %v", err)
+		}
+		assertRoundTrip(t, loaded, state)
+	})
+
 	t.Run("UpdateEquity", func(t *testing.T) {
 		newEquity := model.MustMoney("12000")
 		err := repo.UpdateEquity(ctx, accountID, newEquity)
@@ -70,7 +90,7 @@ func TestPostgresRepo_Integration(t *testing.T) {
 		}
 
 		// 验证更新
-		loaded, err := repo.LoadState(ctx, accountID, "")
+		loaded, err := repo.LoadState(ctx, model.AccountScope(accountID))
 		if err != nil {
 			t.Fatalf("LoadState failed: %v", err)
 		}
@@ -98,7 +118,7 @@ func TestPostgresRepo_Integration(t *testing.T) {
 		}
 
 		// 验证
-		loaded, _ := repo.LoadState(ctx, accountID, "")
+		loaded, _ := repo.LoadState(ctx, model.AccountScope(accountID))
 		if !loaded.DailyPnL.EQ(model.MustMoney("550")) { // 500 + 100 - 50
 			t.Errorf("DailyPnL = %v, want 550", loaded.DailyPnL)
 		}
@@ -136,6 +156,41 @@ func TestPostgresRepo_Integration(t *testing.T) {
 		}
 	})
 
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		casID := accountID + "-cas"
+		defer func() {
+			_, _ = db.ExecContext(ctx, "DELETE FROM risk_states WHERE account_id = $1", casID)
+		}()
+
+		state := model.NewRiskState(casID, model.MustMoney("10000"))
+		if err := repo.CompareAndSwapState(ctx, state); err != nil {
+			t.Fatalf("CompareAndSwapState (create) failed: %v", err)
+		}
+
+		// 重复创建应冲突
+		dup := model.NewRiskState(casID, model.MustMoney("10000"))
+		if err := repo.CompareAndSwapState(ctx, dup); !errors.Is(err, port.ErrRiskStateConflict) {
+			t.Errorf("duplicate create: err = %v, want ErrRiskStateConflict", err)
+		}
+
+		// 原子方法递增版本，持有旧版本的写入应冲突
+		stale := state.Clone()
+		if err := repo.RecordTrade(ctx, casID, model.MustMoney("-10")); err != nil {
+			t.Fatalf("RecordTrade failed: %v", err)
+		}
+		if err := repo.CompareAndSwapState(ctx, stale); !errors.Is(err, port.ErrRiskStateConflict) {
+			t.Errorf("stale write: err = %v, want ErrRiskStateConflict", err)
+		}
+
+		loaded, err := repo.LoadState(ctx, model.AccountScope(casID))
+		if err != nil {
+			t.Fatalf("LoadState failed: %v", err)
+		}
+		if loaded.Version != state.Version+1 {
+			t.Errorf("Version = %d, want %d", loaded.Version, state.Version+1)
+		}
+	})
+
 	// 清理测试数据
 	_, _ = db.ExecContext(ctx, "DELETE FROM risk_states WHERE account_id = $1", accountID)
 }
@@ -2,16 +2,20 @@ package risk
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
+// maxWatchRetries 原子更新在 WATCH 冲突时的最大重试次数
+const maxWatchRetries = 10
+
 // RedisRepo Redis 风控仓储（实现 port.RiskRepo 接口）
-// 使用 JSON 序列化存储 RiskState，支持 TTL 过期
+// 使用版本化 JSON 编码存储 RiskState（model.EncodeRiskState），支持 TTL 过期
+// 所有写入均在 WATCH/MULTI 事务中完成，RiskState.Version 作为乐观锁版本
 type RedisRepo struct {
 	client *redis.Client
 	ttl    time.Duration // 状态过期时间（默认 24 小时）
@@ -33,126 +37,160 @@ func NewRedisRepoWithTTL(client *redis.Client, ttl time.Duration) *RedisRepo {
 	}
 }
 
-// makeKey 生成 Redis 键
-func (r *RedisRepo) makeKey(accountID, symbol string) string {
-	if symbol == "" {
-		return fmt.Sprintf("risk:state:%s", accountID)
-	}
-	return fmt.Sprintf("risk:state:%s:%s", accountID, symbol)
+// makeKey 生成 Redis 键（risk:state:{RiskScope.Key()}）
+func (r *RedisRepo) makeKey(scope model.RiskScope) string {
+	return fmt.Sprintf("risk:state:%s", scope.Key())
 }
 
 // LoadState 加载风控状态
-func (r *RedisRepo) LoadState(ctx context.Context, accountID, symbol string) (*model.RiskState, error) {
-	key := r.makeKey(accountID, symbol)
+func (r *RedisRepo) LoadState(ctx context.Context, scope model.RiskScope) (*model.RiskState, error) {
+	return r.get(ctx, r.client, scope)
+}
 
+// get 读取风控状态（不存在时返回该作用域的初始状态）
+func (r *RedisRepo) get(ctx context.Context, c redis.Cmdable, scope model.RiskScope) (*model.RiskState, error) {
 	// 从 Redis 读取 JSON
-	data, err := r.client.Get(ctx, key).Result()
+	data, err := c.Get(ctx, r.makeKey(scope)).Result()
 	if err == redis.Nil {
 		// 记录不存在，返回新状态
-		return model.NewRiskState(accountID, model.Zero()), nil
+		return model.NewScopedRiskState(scope, model.Zero()), nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("load risk state from redis failed: %w", err)
 	}
 
-	// 反序列化
-	var state model.RiskState
-	if err := json.Unmarshal([]byte(data), &state); err != nil {
-		return nil, fmt.Errorf("unmarshal risk state failed: %w", err)
+	// 反序列化（旧版本数据自动迁移）
+	state, _, err := model.DecodeRiskState([]byte(data))
+	if err != nil {
+		return nil, err
 	}
 
-	return &state, nil
+	return state, nil
 }
 
-// SaveState 保存风控状态（幂等）
-func (r *RedisRepo) SaveState(ctx context.Context, state *model.RiskState) error {
-	key := r.makeKey(state.AccountID, state.Symbol)
+// watch 在 WATCH 事务中读取当前状态，由 fn 返回待写入的状态（版本由 fn 设置）
+// 键在读取后被其他客户端修改时返回 port.ErrRiskStateConflict
+func (r *RedisRepo) watch(ctx context.Context, scope model.RiskScope, fn func(stored *model.RiskState) (*model.RiskState, error)) error {
+	key := r.makeKey(scope)
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := r.get(ctx, tx, scope)
+		if err != nil {
+			return err
+		}
 
-	// 序列化为 JSON
-	data, err := json.Marshal(state)
-	if err != nil {
-		return fmt.Errorf("marshal risk state failed: %w", err)
-	}
+		next, err := fn(stored)
+		if err != nil {
+			return err
+		}
 
-	// 写入 Redis（带 TTL）
-	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+		// 序列化为版本化 JSON
+		data, err := model.EncodeRiskState(next)
+		if err != nil {
+			return err
+		}
+
+		// 写入 Redis（带 TTL）
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, r.ttl)
+			return nil
+		})
+		return err
+	}, key)
+
+	if err == redis.TxFailedErr {
+		return port.ErrRiskStateConflict
+	}
+	if err != nil && err != port.ErrRiskStateConflict {
 		return fmt.Errorf("save risk state to redis failed: %w", err)
 	}
-
-	return nil
+	return err
 }
 
-// UpdateEquity 原子更新净值
-func (r *RedisRepo) UpdateEquity(ctx context.Context, accountID string, newEquity model.Money) error {
-	// 先加载状态
-	state, err := r.LoadState(ctx, accountID, "")
-	if err != nil {
-		return err
+// update 原子读取-修改-写入（WATCH 冲突时重试）
+func (r *RedisRepo) update(ctx context.Context, scope model.RiskScope, mutate func(state *model.RiskState)) error {
+	for i := 0; i < maxWatchRetries; i++ {
+		err := r.watch(ctx, scope, func(stored *model.RiskState) (*model.RiskState, error) {
+			mutate(stored)
+			stored.Version++
+			return stored, nil
+		})
+		if err != port.ErrRiskStateConflict {
+			return err
+		}
 	}
+	return port.ErrRiskStateConflict
+}
 
-	// 更新净值
-	state.UpdateEquity(newEquity)
-
-	// 保存
-	return r.SaveState(ctx, state)
+// SaveState 保存风控状态（幂等）
+func (r *RedisRepo) SaveState(ctx context.Context, state *model.RiskState) error {
+	for i := 0; i < maxWatchRetries; i++ {
+		var version int64
+		err := r.watch(ctx, state.Scope(), func(stored *model.RiskState) (*model.RiskState, error) {
+			next := state.Clone()
+			next.Version = stored.Version + 1
+			version = next.Version
+			return next, nil
+		})
+		if err == nil {
+			state.Version = version
+			return nil
+		}
+		if err != port.ErrRiskStateConflict {
+			return err
+		}
+	}
+	return port.ErrRiskStateConflict
 }
 
-// RecordTrade 记录交易（更新当日统计）
-func (r *RedisRepo) RecordTrade(ctx context.Context, accountID string, pnl model.Money) error {
-	// 先加载状态
-	state, err := r.LoadState(ctx, accountID, "")
+// CompareAndSwapState 乐观锁写入
+func (r *RedisRepo) CompareAndSwapState(ctx context.Context, state *model.RiskState) error {
+	err := r.watch(ctx, state.Scope(), func(stored *model.RiskState) (*model.RiskState, error) {
+		if stored.Version != state.Version {
+			return nil, port.ErrRiskStateConflict
+		}
+		next := state.Clone()
+		next.Version++
+		return next, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	// 更新状态
-	state.DailyPnL = state.DailyPnL.Add(pnl)
-	state.DailyTradeCount++
+	state.Version++
+	return nil
+}
 
-	// 更新连续亏损
-	if pnl.IsNegative() {
-		state.RecordLoss()
-	} else if pnl.IsPositive() {
-		state.ResetConsecutiveLosses()
-	}
+// UpdateEquity 原子更新净值
+func (r *RedisRepo) UpdateEquity(ctx context.Context, accountID string, newEquity model.Money) error {
+	return r.update(ctx, model.AccountScope(accountID), func(state *model.RiskState) {
+		state.UpdateEquity(newEquity)
+	})
+}
 
-	// 保存
-	return r.SaveState(ctx, state)
+// RecordTrade 记录交易（更新当日统计）
+func (r *RedisRepo) RecordTrade(ctx context.Context, accountID string, pnl model.Money) error {
+	return r.update(ctx, model.AccountScope(accountID), func(state *model.RiskState) {
+		state.RecordTrade(pnl)
+	})
 }
 
 // OpenCircuitBreaker 打开熔断器
 func (r *RedisRepo) OpenCircuitBreaker(ctx context.Context, accountID string, duration int64) error {
-	// 先加载状态
-	state, err := r.LoadState(ctx, accountID, "")
-	if err != nil {
-		return err
-	}
-
-	// 打开熔断器
-	state.OpenCircuitBreaker(time.Duration(duration) * time.Second)
-
-	// 保存
-	return r.SaveState(ctx, state)
+	return r.update(ctx, model.AccountScope(accountID), func(state *model.RiskState) {
+		state.OpenCircuitBreaker(time.Duration(duration) * time.Second)
+	})
 }
 
 // CloseCircuitBreaker 关闭熔断器
 func (r *RedisRepo) CloseCircuitBreaker(ctx context.Context, accountID string) error {
-	// 先加载状态
-	state, err := r.LoadState(ctx, accountID, "")
-	if err != nil {
-		return err
-	}
-
-	// 关闭熔断器
-	state.CloseCircuitBreaker()
-
-	// 保存
-	return r.SaveState(ctx, state)
+	return r.update(ctx, model.AccountScope(accountID), func(state *model.RiskState) {
+		state.CloseCircuitBreaker()
+	})
 }
 
 // IsCircuitBreakerOpen 检查熔断器状态
 func (r *RedisRepo) IsCircuitBreakerOpen(ctx context.Context, accountID string) (bool, error) {
-	state, err := r.LoadState(ctx, accountID, "")
+	state, err := r.LoadState(ctx, model.AccountScope(accountID))
 	if err != nil {
 		return false, err
 	}
@@ -2,11 +2,14 @@ package risk
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
 
 // setupRedisTestClient 创建测试用的 Redis 客户端
@@ -38,7 +41,7 @@ func TestRedisRepo_LoadState(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("加载不存在的状态", func(t *testing.T) {
-		state, err := repo.LoadState(ctx, "test-account", "")
+		state, err := repo.LoadState(ctx, model.AccountScope("test-account"))
 		if err != nil {
 			t.Fatalf("LoadState failed: %v", err)
 		}
@@ -56,7 +59,7 @@ func TestRedisRepo_LoadState(t *testing.T) {
 		}
 
 		// 加载状态
-		loadedState, err := repo.LoadState(ctx, "test-account-2", "")
+		loadedState, err := repo.LoadState(ctx, model.AccountScope("test-account-2"))
 		if err != nil {
 			t.Fatalf("LoadState failed: %v", err)
 		}
@@ -83,7 +86,7 @@ func TestRedisRepo_SaveState(t *testing.T) {
 		}
 
 		// 验证已保存
-		loadedState, err := repo.LoadState(ctx, "save-test", "")
+		loadedState, err := repo.LoadState(ctx, model.AccountScope("save-test"))
 		if err != nil {
 			t.Fatalf("LoadState failed: %v", err)
 		}
@@ -106,7 +109,7 @@ func TestRedisRepo_SaveState(t *testing.T) {
 		}
 
 		// 验证状态一致
-		loadedState, err := repo.LoadState(ctx, "idempotent-test", "")
+		loadedState, err := repo.LoadState(ctx, model.AccountScope("idempotent-test"))
 		if err != nil {
 			t.Fatalf("LoadState failed: %v", err)
 		}
@@ -136,7 +139,7 @@ func TestRedisRepo_UpdateEquity(t *testing.T) {
 	}
 
 	// 验证更新
-	loadedState, err := repo.LoadState(ctx, "equity-test", "")
+	loadedState, err := repo.LoadState(ctx, model.AccountScope("equity-test"))
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
@@ -168,7 +171,7 @@ func TestRedisRepo_RecordTrade(t *testing.T) {
 		t.Fatalf("RecordTrade failed: %v", err)
 	}
 
-	loadedState, err := repo.LoadState(ctx, "trade-test", "")
+	loadedState, err := repo.LoadState(ctx, model.AccountScope("trade-test"))
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
@@ -186,7 +189,7 @@ func TestRedisRepo_RecordTrade(t *testing.T) {
 		t.Fatalf("RecordTrade failed: %v", err)
 	}
 
-	loadedState2, err := repo.LoadState(ctx, "trade-test", "")
+	loadedState2, err := repo.LoadState(ctx, model.AccountScope("trade-test"))
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
@@ -260,7 +263,7 @@ func TestRedisRepo_TTL(t *testing.T) {
 	}
 
 	// 立即加载应该存在
-	_, err := repo.LoadState(ctx, "ttl-test", "")
+	_, err := repo.LoadState(ctx, model.AccountScope("ttl-test"))
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
@@ -269,7 +272,7 @@ func TestRedisRepo_TTL(t *testing.T) {
 	time.Sleep(3 * time.Second)
 
 	// 再次加载应该返回新状态（因为已过期）
-	loadedState, err := repo.LoadState(ctx, "ttl-test", "")
+	loadedState, err := repo.LoadState(ctx, model.AccountScope("ttl-test"))
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
@@ -279,3 +282,108 @@ func TestRedisRepo_TTL(t *testing.T) {
 		t.Logf("Note: State may still exist if TTL hasn't expired yet")
 	}
 }
+
+func TestRedisRepo_RoundTrip(t *testing.T) {
+	client := setupRedisTestClient(t)
+	defer client.Close()
+
+	repo := NewRedisRepo(client)
+	ctx := context.Background()
+
+	t.Run("全部金额字段", func(t *testing.T) {
+		state := newRoundTripState("roundtrip-test")
+		if err := repo.SaveState(ctx, state); err != nil {
+			t.Fatalf("SaveState failed: %v", err)
+		}
+
+		loaded, err := repo.LoadState(ctx, model.AccountScope("roundtrip-test"))
+		if err != nil {
+			t.Fatalf("LoadState failed: %v", err)
+		}
+		assertRoundTrip(t, loaded, state)
+	})
+
+	t.Run("旧版本数据迁移", func(t *testing.T) {
+		legacy := `{"AccountID":"legacy-test","CurrentEquity":{},"ConsecutiveLosses":3,"PositionMap":{}}`
+		if err := client.Set(ctx, repo.makeKey(model.AccountScope("legacy-test")), legacy, time.Minute).Err(); err != nil {
+			t.Fatalf("Set legacy data failed: %v", err)
+		}
+
+		loaded, err := repo.LoadState(ctx, model.AccountScope("legacy-test"))
+		if err != nil {
+			t.Fatalf("LoadState failed: %v", err)
+		}
+		if loaded.ConsecutiveLosses != 3 || !loaded.CurrentEquity.IsZero() {
+			t.Errorf("unexpected migrated state: losses=%d equity=%s", loaded.ConsecutiveLosses, loaded.CurrentEquity)
+		}
+	})
+}
+
+func TestRedisRepo_CompareAndSwap(t *testing.T) {
+	client := setupRedisTestClient(t)
+	defer client.Close()
+
+	repo := NewRedisRepo(client)
+	ctx := context.Background()
+
+	state := model.NewRiskState("cas-test", model.MustMoney("10000"))
+	if err := repo.CompareAndSwapState(ctx, state); err != nil {
+		t.Fatalf("CompareAndSwapState (create) failed: %v", err)
+	}
+	if state.Version != 1 {
+		t.Errorf("Version = %d, want 1", state.Version)
+	}
+
+	// 版本过期的写入应被拒绝
+	stale := state.Clone()
+	stale.Version = 0
+	if err := repo.CompareAndSwapState(ctx, stale); !errors.Is(err, port.ErrRiskStateConflict) {
+		t.Errorf("stale write: err = %v, want ErrRiskStateConflict", err)
+	}
+
+	state.DailyPnL = model.MustMoney("-50")
+	if err := repo.CompareAndSwapState(ctx, state); err != nil {
+		t.Fatalf("CompareAndSwapState (update) failed: %v", err)
+	}
+
+	loaded, err := repo.LoadState(ctx, model.AccountScope("cas-test"))
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if loaded.Version != 2 || !loaded.DailyPnL.EQ(model.MustMoney("-50")) {
+		t.Errorf("unexpected state: version=%d pnl=%s", loaded.Version, loaded.DailyPnL)
+	}
+}
+
+func TestRedisRepo_ConcurrentRecordTrade(t *testing.T) {
+	client := setupRedisTestClient(t)
+	defer client.Close()
+
+	repo := NewRedisRepo(client)
+	ctx := context.Background()
+
+	if err := repo.SaveState(ctx, model.NewRiskState("concurrent-test", model.MustMoney("10000"))); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	const workers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := repo.RecordTrade(ctx, "concurrent-test", model.MustMoney("-10")); err != nil {
+				t.Errorf("RecordTrade failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := repo.LoadState(ctx, model.AccountScope("concurrent-test"))
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if loaded.DailyTradeCount != workers || !loaded.DailyPnL.EQ(model.MustMoney("-100")) {
+		t.Errorf("lost updates: trades=%d pnl=%s", loaded.DailyTradeCount, loaded.DailyPnL)
+	}
+}
@@ -0,0 +1,108 @@
+package riskhistory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存风控状态历史仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu        sync.RWMutex
+	snapshots []*model.RiskStateSnapshot
+	nextID    int64
+}
+
+// NewMemoryRepo 创建内存风控状态历史仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+// SaveSnapshots 批量保存风控状态快照
+func (r *MemoryRepo) SaveSnapshots(ctx context.Context, snapshots []*model.RiskStateSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range snapshots {
+		r.nextID++
+		copied := *s
+		copied.ID = r.nextID
+		r.snapshots = append(r.snapshots, &copied)
+	}
+	return nil
+}
+
+// ListSnapshots 查询作用域的风控状态快照（按记录时间升序）
+func (r *MemoryRepo) ListSnapshots(ctx context.Context, filter model.RiskStateHistoryFilter) ([]*model.RiskStateSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []*model.RiskStateSnapshot
+	for _, s := range r.snapshots {
+		if matchFilter(s, filter) {
+			copied := *s
+			matched = append(matched, &copied)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].RecordedAt.Equal(matched[j].RecordedAt) {
+			return matched[i].RecordedAt.Before(matched[j].RecordedAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, nil
+}
+
+// PurgeSnapshots 删除 before 之前的指定类型快照
+func (r *MemoryRepo) PurgeSnapshots(ctx context.Context, kinds []string, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		kept   = r.snapshots[:0]
+		purged int64
+	)
+	for _, s := range r.snapshots {
+		if containsKind(kinds, s.Kind) && s.RecordedAt.Before(before) {
+			purged++
+			continue
+		}
+		kept = append(kept, s)
+	}
+	r.snapshots = kept
+	return purged, nil
+}
+
+// matchFilter 判断快照是否满足查询条件
+func matchFilter(s *model.RiskStateSnapshot, filter model.RiskStateHistoryFilter) bool {
+	if s.Scope() != filter.Scope {
+		return false
+	}
+	if len(filter.Kinds) > 0 && !containsKind(filter.Kinds, s.Kind) {
+		return false
+	}
+	if !filter.From.IsZero() && s.RecordedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && !s.RecordedAt.Before(filter.To) {
+		return false
+	}
+	return true
+}
+
+// containsKind kinds 是否包含 kind
+func containsKind(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package riskhistory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func newSnapshot(scope model.RiskScope, kind string, at time.Time) *model.RiskStateSnapshot {
+	state := model.NewScopedRiskState(scope, model.MustMoney("10000"))
+	return model.NewRiskStateSnapshot(state, kind, "", at)
+}
+
+func TestMemoryRepo_ListAndPurge(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	base := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	account := model.AccountScope("acc-1")
+	strategy := model.RiskScope{AccountID: "acc-1", StrategyID: "alpha"}
+
+	err := repo.SaveSnapshots(ctx, []*model.RiskStateSnapshot{
+		newSnapshot(account, model.RiskHistoryKindPeriodic, base.Add(2*time.Hour)),
+		newSnapshot(account, model.RiskHistoryKindCircuitOpen, base.Add(time.Hour)),
+		newSnapshot(account, model.RiskHistoryKindPeriodic, base),
+		newSnapshot(strategy, model.RiskHistoryKindPeriodic, base),
+	})
+	if err != nil {
+		t.Fatalf("SaveSnapshots failed: %v", err)
+	}
+
+	t.Run("按作用域与时间范围查询", func(t *testing.T) {
+		list, _ := repo.ListSnapshots(ctx, model.RiskStateHistoryFilter{
+			Scope: account,
+			From:  base.Add(time.Hour),
+		})
+		if len(list) != 2 {
+			t.Fatalf("len = %d, want 2", len(list))
+		}
+		if !list[0].RecordedAt.Before(list[1].RecordedAt) {
+			t.Error("snapshots should be ascending by time")
+		}
+	})
+
+	t.Run("按类型查询", func(t *testing.T) {
+		list, _ := repo.ListSnapshots(ctx, model.RiskStateHistoryFilter{
+			Scope: account,
+			Kinds: []string{model.RiskHistoryKindCircuitOpen},
+		})
+		if len(list) != 1 {
+			t.Fatalf("len = %d, want 1", len(list))
+		}
+	})
+
+	t.Run("按类型清理", func(t *testing.T) {
+		purged, _ := repo.PurgeSnapshots(ctx, []string{model.RiskHistoryKindPeriodic}, base.Add(time.Hour))
+		if purged != 2 {
+			t.Errorf("purged = %d, want 2", purged)
+		}
+		list, _ := repo.ListSnapshots(ctx, model.RiskStateHistoryFilter{Scope: account})
+		if len(list) != 2 {
+			t.Errorf("remaining = %d, want 2", len(list))
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package riskhistory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/lib/pq"
+)
+
+// PostgresRepo PostgreSQL 风控状态历史仓储（实现 port.RiskHistoryRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 风控状态历史仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+// snapshotColumns 快照字段（不含 id）
+const snapshotColumns = `account_id, strategy_id, symbol, kind, reason,
+	current_equity, peak_equity, mdd, mdd_percent, daily_pnl, total_exposure,
+	consecutive_losses, circuit_breaker_open, circuit_breaker_until, state_version, recorded_at`
+
+// SaveSnapshots 批量保存风控状态快照（单条 INSERT 多行）
+func (r *PostgresRepo) SaveSnapshots(ctx context.Context, snapshots []*model.RiskStateSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	const columns = 16
+	var (
+		values []string
+		args   []any
+	)
+	for i, s := range snapshots {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+
+		var until any
+		if s.CircuitBreakerUntil > 0 {
+			until = time.Unix(s.CircuitBreakerUntil, 0)
+		}
+		args = append(args,
+			s.AccountID, s.StrategyID, s.Symbol, s.Kind, s.Reason,
+			s.CurrentEquity.String(), s.PeakEquity.String(), s.MDD.String(), s.MDDPercent.String(),
+			s.DailyPnL.String(), s.TotalExposure.String(),
+			s.ConsecutiveLosses, s.CircuitBreakerOpen, until, s.StateVersion, s.RecordedAt,
+		)
+	}
+
+	query := `INSERT INTO risk_state_history (` + snapshotColumns + `) VALUES ` + strings.Join(values, ", ")
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("save risk state history failed: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshots 查询作用域的风控状态快照（按记录时间升序）
+func (r *PostgresRepo) ListSnapshots(ctx context.Context, filter model.RiskStateHistoryFilter) ([]*model.RiskStateSnapshot, error) {
+	query := `
+		SELECT id, ` + snapshotColumns + `
+		FROM risk_state_history
+		WHERE account_id = $1 AND strategy_id = $2 AND symbol = $3
+			AND (cardinality($4::TEXT[]) = 0 OR kind = ANY($4))
+			AND ($5::TIMESTAMPTZ IS NULL OR recorded_at >= $5)
+			AND ($6::TIMESTAMPTZ IS NULL OR recorded_at < $6)
+		ORDER BY recorded_at, id
+		LIMIT NULLIF($7, 0)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		filter.Scope.AccountID, filter.Scope.StrategyID, filter.Scope.Symbol,
+		pq.Array(filter.Kinds), nullTime(filter.From), nullTime(filter.To), filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list risk state history failed: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*model.RiskStateSnapshot
+	for rows.Next() {
+		var (
+			s                                            model.RiskStateSnapshot
+			equity, peak, mdd, mddPercent, pnl, exposure string
+			until                                        sql.NullTime
+		)
+		if err := rows.Scan(
+			&s.ID, &s.AccountID, &s.StrategyID, &s.Symbol, &s.Kind, &s.Reason,
+			&equity, &peak, &mdd, &mddPercent, &pnl, &exposure,
+			&s.ConsecutiveLosses, &s.CircuitBreakerOpen, &until, &s.StateVersion, &s.RecordedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan risk state history failed: %w", err)
+		}
+
+		s.CurrentEquity = model.MustMoney(equity)
+		s.PeakEquity = model.MustMoney(peak)
+		s.MDD = model.MustMoney(mdd)
+		s.MDDPercent = model.MustMoney(mddPercent)
+		s.DailyPnL = model.MustMoney(pnl)
+		s.TotalExposure = model.MustMoney(exposure)
+		if until.Valid {
+			s.CircuitBreakerUntil = until.Time.Unix()
+		}
+		snapshots = append(snapshots, &s)
+	}
+	return snapshots, rows.Err()
+}
+
+// PurgeSnapshots 删除 before 之前的指定类型快照
+func (r *PostgresRepo) PurgeSnapshots(ctx context.Context, kinds []string, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM risk_state_history WHERE kind = ANY($1) AND recorded_at < $2`,
+		pq.Array(kinds), before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purge risk state history failed: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// nullTime 零值时间转为 NULL
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
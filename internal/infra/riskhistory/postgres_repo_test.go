@@ -0,0 +1,93 @@
+package riskhistory
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	scope := model.AccountScope("test-account-" + time.Now().Format("20060102150405"))
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM risk_state_history WHERE account_id = $1", scope.AccountID)
+	}()
+
+	now := time.Now().Truncate(time.Second)
+	open := newSnapshot(scope, model.RiskHistoryKindCircuitOpen, now)
+	open.Reason = "CircuitBreaker:ConsecutiveLosses: consecutive losses (3) >= max allowed (3)"
+	open.CircuitBreakerOpen = true
+	open.CircuitBreakerUntil = now.Add(time.Hour).Unix()
+
+	t.Run("SaveAndList", func(t *testing.T) {
+		err := repo.SaveSnapshots(ctx, []*model.RiskStateSnapshot{
+			newSnapshot(scope, model.RiskHistoryKindPeriodic, now.Add(-time.Hour)),
+			open,
+		})
+		if err != nil {
+			t.Fatalf("SaveSnapshots failed: %v", err)
+		}
+
+		list, err := repo.ListSnapshots(ctx, model.RiskStateHistoryFilter{Scope: scope})
+		if err != nil {
+			t.Fatalf("ListSnapshots failed: %v", err)
+		}
+		if len(list) != 2 {
+			t.Fatalf("len = %d, want 2", len(list))
+		}
+		got := list[1]
+		if got.Kind != model.RiskHistoryKindCircuitOpen || got.Reason != open.Reason || got.CircuitBreakerUntil != open.CircuitBreakerUntil {
+			t.Errorf("unexpected snapshot %+v", got)
+		}
+		if !got.CurrentEquity.EQ(model.MustMoney("10000")) {
+			t.Errorf("CurrentEquity = %s, want 10000", got.CurrentEquity)
+		}
+
+		list, _ = repo.ListSnapshots(ctx, model.RiskStateHistoryFilter{
+			Scope: scope,
+			Kinds: []string{model.RiskHistoryKindCircuitOpen},
+		})
+		if len(list) != 1 {
+			t.Errorf("kind filter: len = %d, want 1", len(list))
+		}
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		if _, err := repo.PurgeSnapshots(ctx, []string{model.RiskHistoryKindPeriodic}, now); err != nil {
+			t.Fatalf("PurgeSnapshots failed: %v", err)
+		}
+		list, _ := repo.ListSnapshots(ctx, model.RiskStateHistoryFilter{Scope: scope})
+		if len(list) != 1 {
+			t.Errorf("remaining = %d, want 1", len(list))
+		}
+	})
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
@@ -0,0 +1,73 @@
+package risklimit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存限额版本仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu       sync.RWMutex
+	versions map[int64]*model.RiskLimitVersion // key: Version
+}
+
+// NewMemoryRepo 创建内存限额版本仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		versions: make(map[int64]*model.RiskLimitVersion),
+	}
+}
+
+// SaveVersion 保存新版本
+func (r *MemoryRepo) SaveVersion(ctx context.Context, version *model.RiskLimitVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.versions[version.Version]; exists {
+		return fmt.Errorf("limit version already exists: %d", version.Version)
+	}
+	r.versions[version.Version] = copyVersion(version)
+	return nil
+}
+
+// LatestVersion 获取最新版本
+func (r *MemoryRepo) LatestVersion(ctx context.Context) (*model.RiskLimitVersion, error) {
+	versions, _ := r.ListVersions(ctx, 1)
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return versions[0], nil
+}
+
+// ListVersions 按版本号倒序列出最近的版本
+func (r *MemoryRepo) ListVersions(ctx context.Context, limit int) ([]*model.RiskLimitVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]*model.RiskLimitVersion, 0, len(r.versions))
+	for _, v := range r.versions {
+		versions = append(versions, copyVersion(v))
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	if limit > 0 && len(versions) > limit {
+		versions = versions[:limit]
+	}
+	return versions, nil
+}
+
+// copyVersion 深拷贝版本记录
+func copyVersion(v *model.RiskLimitVersion) *model.RiskLimitVersion {
+	copied := *v
+	copied.Limits = make(map[string]float64, len(v.Limits))
+	for k, val := range v.Limits {
+		copied.Limits[k] = val
+	}
+	copied.Changes = append([]model.RiskLimitChange(nil), v.Changes...)
+	return &copied
+}
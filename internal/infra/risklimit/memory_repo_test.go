@@ -0,0 +1,50 @@
+package risklimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_Versions(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+
+	latest, err := repo.LatestVersion(ctx)
+	if err != nil || latest != nil {
+		t.Fatalf("expected no version, got %+v (%v)", latest, err)
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		err := repo.SaveVersion(ctx, &model.RiskLimitVersion{
+			Version:   i,
+			Limits:    map[string]float64{"max_leverage": float64(i)},
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("SaveVersion %d failed: %v", i, err)
+		}
+	}
+	if err := repo.SaveVersion(ctx, &model.RiskLimitVersion{Version: 2}); err == nil {
+		t.Error("expected duplicate version rejected")
+	}
+
+	latest, _ = repo.LatestVersion(ctx)
+	if latest == nil || latest.Version != 3 || latest.Limits["max_leverage"] != 3 {
+		t.Fatalf("unexpected latest version: %+v", latest)
+	}
+
+	versions, _ := repo.ListVersions(ctx, 2)
+	if len(versions) != 2 || versions[0].Version != 3 || versions[1].Version != 2 {
+		t.Errorf("unexpected versions: %+v", versions)
+	}
+
+	// 修改返回值不影响仓储
+	latest.Limits["max_leverage"] = 100
+	latest, _ = repo.LatestVersion(ctx)
+	if latest.Limits["max_leverage"] != 3 {
+		t.Error("expected stored limits unchanged")
+	}
+}
@@ -0,0 +1,130 @@
+package risklimit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 限额版本仓储（实现 port.RiskLimitRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 限额版本仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+const versionColumns = `version, limits, changes, COALESCE(changed_by, 0), verified, reason, created_at`
+
+// SaveVersion 保存新版本（版本号冲突时由主键约束拒绝）
+func (r *PostgresRepo) SaveVersion(ctx context.Context, version *model.RiskLimitVersion) error {
+	limitsJSON, err := json.Marshal(version.Limits)
+	if err != nil {
+		return fmt.Errorf("marshal limits failed: %w", err)
+	}
+	changesJSON, err := json.Marshal(version.Changes)
+	if err != nil {
+		return fmt.Errorf("marshal limit changes failed: %w", err)
+	}
+
+	query := `
+		INSERT INTO risk_limit_versions (
+			version, limits, changes, changed_by, verified, reason, created_at
+		) VALUES (
+			$1, $2, $3, NULLIF($4, 0), $5, $6, $7
+		)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		version.Version,
+		limitsJSON,
+		changesJSON,
+		version.ChangedBy,
+		version.Verified,
+		version.Reason,
+		version.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save limit version failed: %w", err)
+	}
+	return nil
+}
+
+// LatestVersion 获取最新版本
+func (r *PostgresRepo) LatestVersion(ctx context.Context) (*model.RiskLimitVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM risk_limit_versions ORDER BY version DESC LIMIT 1`
+
+	version, err := scanVersion(r.db.QueryRowContext(ctx, query))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest limit version failed: %w", err)
+	}
+	return version, nil
+}
+
+// ListVersions 按版本号倒序列出最近的版本
+func (r *PostgresRepo) ListVersions(ctx context.Context, limit int) ([]*model.RiskLimitVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM risk_limit_versions ORDER BY version DESC LIMIT NULLIF($1, 0)`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list limit versions failed: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []*model.RiskLimitVersion
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan limit version failed: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// rowScanner 兼容 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanVersion 扫描版本记录
+func scanVersion(row rowScanner) (*model.RiskLimitVersion, error) {
+	var (
+		version, changedBy      int64
+		limitsJSON, changesJSON []byte
+		verified                bool
+		reason                  string
+		createdAt               time.Time
+	)
+
+	if err := row.Scan(&version, &limitsJSON, &changesJSON, &changedBy, &verified, &reason, &createdAt); err != nil {
+		return nil, err
+	}
+
+	v := &model.RiskLimitVersion{
+		Version:   version,
+		ChangedBy: changedBy,
+		Verified:  verified,
+		Reason:    reason,
+		CreatedAt: createdAt,
+	}
+	if err := json.Unmarshal(limitsJSON, &v.Limits); err != nil {
+		return nil, fmt.Errorf("unmarshal limits failed: %w", err)
+	}
+	if err := json.Unmarshal(changesJSON, &v.Changes); err != nil {
+		return nil, fmt.Errorf("unmarshal limit changes failed: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,74 @@
+package risklimit
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+
+	// 使用远大于正常版本号的测试版本，避免与真实数据冲突
+	testVersion := time.Now().UnixNano()
+	err = repo.SaveVersion(ctx, &model.RiskLimitVersion{
+		Version: testVersion,
+		Limits:  map[string]float64{"max_leverage": 3, "max_daily_drawdown": 0.05},
+		Changes: []model.RiskLimitChange{
+			{Field: "max_leverage", Old: 2, New: 3, Loosened: true},
+		},
+		Verified:  true,
+		Reason:    "integration test",
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("SaveVersion failed: %v", err)
+	}
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM risk_limit_versions WHERE version = $1", testVersion)
+	}()
+
+	latest, err := repo.LatestVersion(ctx)
+	if err != nil {
+		t.Fatalf("LatestVersion failed: %v", err)
+	}
+	if latest == nil || latest.Version != testVersion {
+		t.Fatalf("unexpected latest version: %+v", latest)
+	}
+	if latest.Limits["max_leverage"] != 3 || len(latest.Changes) != 1 || !latest.Loosened() || latest.ChangedBy != 0 {
+		t.Errorf("unexpected version content: %+v", latest)
+	}
+
+	if err := repo.SaveVersion(ctx, latest); err == nil {
+		t.Error("expected duplicate version rejected")
+	}
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
@@ -0,0 +1,89 @@
+package riskrecord
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存风控记录仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu      sync.RWMutex
+	records []*model.RiskRecord
+	nextID  int64
+}
+
+// NewMemoryRepo 创建内存风控记录仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+// SaveRecords 批量保存风控记录
+func (r *MemoryRepo) SaveRecords(ctx context.Context, records []*model.RiskRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rec := range records {
+		r.nextID++
+		copied := *rec
+		copied.ID = r.nextID
+		r.records = append(r.records, &copied)
+	}
+	return nil
+}
+
+// ListRecords 分页查询风控记录（按触发时间倒序）
+func (r *MemoryRepo) ListRecords(ctx context.Context, filter model.RiskRecordFilter) ([]*model.RiskRecord, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []*model.RiskRecord
+	for _, rec := range r.records {
+		if matchFilter(rec, filter) {
+			copied := *rec
+			matched = append(matched, &copied)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].TriggeredAt.Equal(matched[j].TriggeredAt) {
+			return matched[i].TriggeredAt.After(matched[j].TriggeredAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	total := int64(len(matched))
+	if filter.Offset >= len(matched) {
+		return nil, total, nil
+	}
+	matched = matched[filter.Offset:]
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, total, nil
+}
+
+// matchFilter 判断记录是否满足查询条件
+func matchFilter(rec *model.RiskRecord, filter model.RiskRecordFilter) bool {
+	if filter.Rule != "" && rec.EventType != filter.Rule && !strings.HasPrefix(rec.EventType, filter.Rule+":") {
+		return false
+	}
+	if filter.Decision != "" && rec.Decision != filter.Decision {
+		return false
+	}
+	if filter.Symbol != "" && rec.Symbol != filter.Symbol {
+		return false
+	}
+	if filter.StrategyID != "" && rec.StrategyID != filter.StrategyID {
+		return false
+	}
+	if !filter.From.IsZero() && rec.TriggeredAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && !rec.TriggeredAt.Before(filter.To) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package riskrecord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_ListRecords(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_ = repo.SaveRecords(ctx, []*model.RiskRecord{
+		{EventType: "PositionLimit:SinglePosition", Decision: "REDUCE", Symbol: "BTCUSDT", StrategyID: "alpha", TriggeredAt: base},
+		{EventType: "PositionLimit:TotalExposure", Decision: "BLOCK", Symbol: "ETHUSDT", StrategyID: "alpha", TriggeredAt: base.Add(time.Minute)},
+		{EventType: "CircuitBreaker:DailyDrawdown", Decision: "BLOCK", Symbol: "BTCUSDT", StrategyID: "beta", TriggeredAt: base.Add(2 * time.Minute)},
+	})
+
+	tests := []struct {
+		name    string
+		filter  model.RiskRecordFilter
+		wantIDs []int64
+	}{
+		{"all newest first", model.RiskRecordFilter{}, []int64{3, 2, 1}},
+		{"rule prefix", model.RiskRecordFilter{Rule: "PositionLimit"}, []int64{2, 1}},
+		{"exact rule", model.RiskRecordFilter{Rule: "PositionLimit:TotalExposure"}, []int64{2}},
+		{"symbol", model.RiskRecordFilter{Symbol: "BTCUSDT"}, []int64{3, 1}},
+		{"strategy and decision", model.RiskRecordFilter{StrategyID: "alpha", Decision: "BLOCK"}, []int64{2}},
+		{"time range", model.RiskRecordFilter{From: base.Add(time.Minute), To: base.Add(2 * time.Minute)}, []int64{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, total, err := repo.ListRecords(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListRecords failed: %v", err)
+			}
+			if total != int64(len(tt.wantIDs)) || len(records) != len(tt.wantIDs) {
+				t.Fatalf("got %d records (total %d), want %v", len(records), total, tt.wantIDs)
+			}
+			for i, rec := range records {
+				if rec.ID != tt.wantIDs[i] {
+					t.Errorf("record %d: got id %d, want %d", i, rec.ID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+
+	// 分页：total 为满足条件的总数
+	records, total, _ := repo.ListRecords(ctx, model.RiskRecordFilter{Offset: 1, Limit: 1})
+	if total != 3 || len(records) != 1 || records[0].ID != 2 {
+		t.Errorf("unexpected page: total %d, records %+v", total, records)
+	}
+}
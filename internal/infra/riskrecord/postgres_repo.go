@@ -0,0 +1,165 @@
+package riskrecord
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 风控记录仓储（实现 port.RiskRecordRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 风控记录仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+// recordDetails risk_records.details 中的决策上下文
+type recordDetails struct {
+	Decision          string          `json:"decision"`
+	Reason            string          `json:"reason"`
+	SuggestedQuantity string          `json:"suggested_quantity,omitempty"`
+	SuggestedLeverage int             `json:"suggested_leverage,omitempty"`
+	AccountID         string          `json:"account_id"`
+	Order             json.RawMessage `json:"order,omitempty"`
+	State             json.RawMessage `json:"state,omitempty"`
+}
+
+// SaveRecords 批量保存风控记录（单条 INSERT 多行）
+func (r *PostgresRepo) SaveRecords(ctx context.Context, records []*model.RiskRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var (
+		values []string
+		args   []any
+	)
+	for i, rec := range records {
+		details, err := json.Marshal(recordDetails{
+			Decision:          rec.Decision,
+			Reason:            rec.Reason,
+			SuggestedQuantity: rec.SuggestedQuantity,
+			SuggestedLeverage: rec.SuggestedLeverage,
+			AccountID:         rec.AccountID,
+			Order:             rec.Order,
+			State:             rec.State,
+		})
+		if err != nil {
+			return fmt.Errorf("marshal risk record details failed: %w", err)
+		}
+
+		n := i * 6
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6))
+		args = append(args, rec.EventType, rec.Level, rec.Symbol, rec.StrategyID, details, rec.TriggeredAt)
+	}
+
+	query := `
+		INSERT INTO risk_records (
+			event_type, level, symbol, strategy_id, details, triggered_at
+		) VALUES ` + strings.Join(values, ", ")
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("save risk records failed: %w", err)
+	}
+	return nil
+}
+
+// ListRecords 分页查询风控记录（按触发时间倒序）
+func (r *PostgresRepo) ListRecords(ctx context.Context, filter model.RiskRecordFilter) ([]*model.RiskRecord, int64, error) {
+	where, args := buildFilter(filter)
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM risk_records` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count risk records failed: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, event_type, level, COALESCE(symbol, ''), COALESCE(strategy_id, ''),
+			COALESCE(details, '{}'), triggered_at
+		FROM risk_records%s
+		ORDER BY triggered_at DESC, id DESC
+		LIMIT NULLIF($%d, 0) OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list risk records failed: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*model.RiskRecord
+	for rows.Next() {
+		var (
+			rec         model.RiskRecord
+			detailsJSON []byte
+			triggeredAt time.Time
+		)
+		if err := rows.Scan(&rec.ID, &rec.EventType, &rec.Level, &rec.Symbol, &rec.StrategyID, &detailsJSON, &triggeredAt); err != nil {
+			return nil, 0, fmt.Errorf("scan risk record failed: %w", err)
+		}
+
+		var details recordDetails
+		if err := json.Unmarshal(detailsJSON, &details); err != nil {
+			return nil, 0, fmt.Errorf("unmarshal risk record details failed: %w", err)
+		}
+		rec.Decision = details.Decision
+		rec.Reason = details.Reason
+		rec.SuggestedQuantity = details.SuggestedQuantity
+		rec.SuggestedLeverage = details.SuggestedLeverage
+		rec.AccountID = details.AccountID
+		rec.Order = details.Order
+		rec.State = details.State
+		rec.TriggeredAt = triggeredAt
+		records = append(records, &rec)
+	}
+
+	return records, total, rows.Err()
+}
+
+// buildFilter 构造查询条件
+func buildFilter(filter model.RiskRecordFilter) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, strings.ReplaceAll(cond, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.Rule != "" {
+		add("(event_type = ? OR event_type LIKE ? || ':%')", filter.Rule)
+	}
+	if filter.Decision != "" {
+		add("details->>'decision' = ?", filter.Decision)
+	}
+	if filter.Symbol != "" {
+		add("symbol = ?", filter.Symbol)
+	}
+	if filter.StrategyID != "" {
+		add("strategy_id = ?", filter.StrategyID)
+	}
+	if !filter.From.IsZero() {
+		add("triggered_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("triggered_at < ?", filter.To)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
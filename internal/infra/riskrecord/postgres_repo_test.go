@@ -0,0 +1,95 @@
+package riskrecord
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	strategyID := "test-" + time.Now().Format("150405.000")
+	now := time.Now()
+
+	err = repo.SaveRecords(ctx, []*model.RiskRecord{
+		{
+			EventType:         "PositionLimit:SinglePosition",
+			Level:             model.RiskRecordLevelWarning,
+			Decision:          "REDUCE",
+			Reason:            "single position limit",
+			SuggestedQuantity: "0.06",
+			AccountID:         "acc-1",
+			Symbol:            "BTCUSDT",
+			StrategyID:        strategyID,
+			Order:             json.RawMessage(`{"symbol":"BTCUSDT","quantity":"0.1"}`),
+			State:             json.RawMessage(`{"AccountID":"acc-1"}`),
+			TriggeredAt:       now,
+		},
+		{
+			EventType:   "CircuitBreaker:DailyDrawdown",
+			Level:       model.RiskRecordLevelBlock,
+			Decision:    "BLOCK",
+			Symbol:      "ETHUSDT",
+			StrategyID:  strategyID,
+			TriggeredAt: now.Add(time.Second),
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveRecords failed: %v", err)
+	}
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM risk_records WHERE strategy_id = $1", strategyID)
+	}()
+
+	records, total, err := repo.ListRecords(ctx, model.RiskRecordFilter{
+		Rule:       "PositionLimit",
+		StrategyID: strategyID,
+		Limit:      10,
+	})
+	if err != nil {
+		t.Fatalf("ListRecords failed: %v", err)
+	}
+	if total != 1 || len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d (total %d)", len(records), total)
+	}
+	rec := records[0]
+	if rec.Decision != "REDUCE" || rec.SuggestedQuantity != "0.06" || rec.AccountID != "acc-1" || len(rec.Order) == 0 {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+
+	where, args := buildFilter(model.RiskRecordFilter{Rule: "PositionLimit", Symbol: "BTCUSDT"})
+	want := " WHERE (event_type = $1 OR event_type LIKE $1 || ':%') AND symbol = $2"
+	if where != want || len(args) != 2 {
+		t.Errorf("unexpected filter: %q %v", where, args)
+	}
+}
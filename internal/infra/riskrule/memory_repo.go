@@ -0,0 +1,56 @@
+package riskrule
+
+import (
+	"context"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存规则集配置源（用于回测与测试）
+type MemoryRepo struct {
+	mu   sync.RWMutex
+	sets map[string][]*model.RiskRuleSpec // key: AccountID
+}
+
+// NewMemoryRepo 创建内存规则集配置源
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		sets: make(map[string][]*model.RiskRuleSpec),
+	}
+}
+
+// SetRuleSet 设置账户规则集
+func (r *MemoryRepo) SetRuleSet(accountID string, specs []*model.RiskRuleSpec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sets[accountID] = copySpecs(specs)
+}
+
+// ListRuleSets 查询所有账户的规则集
+func (r *MemoryRepo) ListRuleSets(ctx context.Context) (map[string][]*model.RiskRuleSpec, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sets := make(map[string][]*model.RiskRuleSpec, len(r.sets))
+	for accountID, specs := range r.sets {
+		sets[accountID] = copySpecs(specs)
+	}
+	return sets, nil
+}
+
+// copySpecs 深拷贝规则配置
+func copySpecs(specs []*model.RiskRuleSpec) []*model.RiskRuleSpec {
+	copied := make([]*model.RiskRuleSpec, 0, len(specs))
+	for _, spec := range specs {
+		c := *spec
+		if spec.Params != nil {
+			c.Params = make(map[string]float64, len(spec.Params))
+			for k, v := range spec.Params {
+				c.Params[k] = v
+			}
+		}
+		copied = append(copied, &c)
+	}
+	return copied
+}
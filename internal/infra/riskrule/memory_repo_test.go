@@ -0,0 +1,35 @@
+package riskrule
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_ListRuleSets(t *testing.T) {
+	repo := NewMemoryRepo()
+	specs := []*model.RiskRuleSpec{
+		{Name: "position_limit", Enabled: true, Params: map[string]float64{"max_leverage": 3}},
+	}
+	repo.SetRuleSet("acc-1", specs)
+
+	// 修改入参不影响已保存的配置
+	specs[0].Params["max_leverage"] = 10
+
+	sets, err := repo.ListRuleSets(context.Background())
+	if err != nil {
+		t.Fatalf("ListRuleSets failed: %v", err)
+	}
+	got := sets["acc-1"]
+	if len(got) != 1 || got[0].Params["max_leverage"] != 3 {
+		t.Fatalf("unexpected rule set: %+v", got)
+	}
+
+	// 修改返回值不影响仓储
+	got[0].Enabled = false
+	sets, _ = repo.ListRuleSets(context.Background())
+	if !sets["acc-1"][0].Enabled {
+		t.Error("expected stored rule to stay enabled")
+	}
+}
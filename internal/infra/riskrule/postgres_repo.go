@@ -0,0 +1,139 @@
+package riskrule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// KeyPrefix strategy_configs 中风控规则配置的键名前缀
+// 键名格式：risk.rule.<account>.<rule>.<field>
+//   - enabled（value_num，0 关闭，缺省为启用）
+//   - priority（value_num）
+//   - scope（value_str：system / account / strategy / symbol）
+//   - target（value_str）
+//   - param.<key>（value_num，覆盖风控参数，如 param.max_leverage）
+const KeyPrefix = "risk.rule."
+
+// PostgresRepo 基于 strategy_configs 表的规则集配置源（实现 port.RiskRuleRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 规则集配置源
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+// ListRuleSets 查询所有账户的规则集
+func (r *PostgresRepo) ListRuleSets(ctx context.Context) (map[string][]*model.RiskRuleSpec, error) {
+	query := `
+		SELECT key_name, value_str, value_num
+		FROM strategy_configs
+		WHERE key_name LIKE $1
+		ORDER BY key_name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, KeyPrefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("list rule configs failed: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []ConfigEntry
+	for rows.Next() {
+		var (
+			key      string
+			valueStr sql.NullString
+			valueNum sql.NullString
+		)
+		if err := rows.Scan(&key, &valueStr, &valueNum); err != nil {
+			return nil, fmt.Errorf("scan rule config failed: %w", err)
+		}
+
+		entry := ConfigEntry{Key: key, Str: valueStr.String}
+		if valueNum.Valid {
+			num, err := strconv.ParseFloat(valueNum.String, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value_num for %s: %w", key, err)
+			}
+			entry.Num = num
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ParseRuleSets(entries)
+}
+
+// ConfigEntry strategy_configs 单行配置
+type ConfigEntry struct {
+	Key string
+	Str string
+	Num float64
+}
+
+// ParseRuleSets 将键值配置解析为按账户分组的规则集（同账户内按规则名称排序）
+func ParseRuleSets(entries []ConfigEntry) (map[string][]*model.RiskRuleSpec, error) {
+	specs := make(map[string]map[string]*model.RiskRuleSpec)
+
+	for _, e := range entries {
+		parts := strings.SplitN(strings.TrimPrefix(e.Key, KeyPrefix), ".", 3)
+		if !strings.HasPrefix(e.Key, KeyPrefix) || len(parts) != 3 {
+			return nil, fmt.Errorf("invalid rule config key: %s", e.Key)
+		}
+		account, name, field := parts[0], parts[1], parts[2]
+
+		if specs[account] == nil {
+			specs[account] = make(map[string]*model.RiskRuleSpec)
+		}
+		spec, ok := specs[account][name]
+		if !ok {
+			spec = &model.RiskRuleSpec{Name: name, Enabled: true}
+			specs[account][name] = spec
+		}
+
+		switch {
+		case field == "enabled":
+			spec.Enabled = e.Num != 0
+		case field == "priority":
+			spec.Priority = int(e.Num)
+		case field == "scope":
+			spec.Scope = model.ParseRiskRuleScope(e.Str)
+			if spec.Scope == 0 {
+				return nil, fmt.Errorf("invalid rule scope for %s: %s", e.Key, e.Str)
+			}
+		case field == "target":
+			spec.Target = e.Str
+		case strings.HasPrefix(field, "param."):
+			if spec.Params == nil {
+				spec.Params = make(map[string]float64)
+			}
+			spec.Params[strings.TrimPrefix(field, "param.")] = e.Num
+		default:
+			return nil, fmt.Errorf("unknown rule config field: %s", e.Key)
+		}
+	}
+
+	sets := make(map[string][]*model.RiskRuleSpec, len(specs))
+	for account, byName := range specs {
+		list := make([]*model.RiskRuleSpec, 0, len(byName))
+		for _, spec := range byName {
+			list = append(list, spec)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+		sets[account] = list
+	}
+	return sets, nil
+}
@@ -0,0 +1,110 @@
+package riskrule
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	account := "test-" + time.Now().Format("20060102150405")
+	prefix := KeyPrefix + account + ".position_limit."
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO strategy_configs (key_name, value_str, value_num) VALUES
+			($1, NULL, 15),
+			($2, 'symbol', NULL),
+			($3, 'BTCUSDT', NULL),
+			($4, NULL, 0.2)
+	`, prefix+"priority", prefix+"scope", prefix+"target", prefix+"param.max_single_position_percent")
+	if err != nil {
+		t.Fatalf("insert rule configs failed: %v", err)
+	}
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM strategy_configs WHERE key_name LIKE $1", prefix+"%")
+	}()
+
+	sets, err := repo.ListRuleSets(ctx)
+	if err != nil {
+		t.Fatalf("ListRuleSets failed: %v", err)
+	}
+	specs := sets[account]
+	if len(specs) != 1 {
+		t.Fatalf("expected 1 rule, got %d", len(specs))
+	}
+	spec := specs[0]
+	if spec.Priority != 15 || spec.Scope != model.RiskRuleScopeSymbol || spec.Target != "BTCUSDT" ||
+		!spec.Enabled || spec.Params["max_single_position_percent"] != 0.2 {
+		t.Errorf("unexpected rule spec: %+v", spec)
+	}
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
+
+func TestParseRuleSets(t *testing.T) {
+	sets, err := ParseRuleSets([]ConfigEntry{
+		{Key: "risk.rule.default.position_limit.param.max_leverage", Num: 3},
+		{Key: "risk.rule.default.circuit_breaker.enabled", Num: 0},
+		{Key: "risk.rule.acc-1.position_limit.priority", Num: 5},
+		{Key: "risk.rule.acc-1.position_limit.scope", Str: "strategy"},
+		{Key: "risk.rule.acc-1.position_limit.target", Str: "grid"},
+	})
+	if err != nil {
+		t.Fatalf("ParseRuleSets failed: %v", err)
+	}
+
+	def := sets["default"]
+	if len(def) != 2 || def[0].Name != "circuit_breaker" || def[1].Name != "position_limit" {
+		t.Fatalf("unexpected default rule set: %+v", def)
+	}
+	if def[0].Enabled {
+		t.Error("expected circuit_breaker disabled")
+	}
+	if !def[1].Enabled || def[1].Params["max_leverage"] != 3 {
+		t.Errorf("unexpected position_limit spec: %+v", def[1])
+	}
+
+	acc := sets["acc-1"]
+	if len(acc) != 1 || acc[0].Priority != 5 || acc[0].Scope != model.RiskRuleScopeStrategy || acc[0].Target != "grid" {
+		t.Errorf("unexpected acc-1 rule set: %+v", acc)
+	}
+
+	invalid := []ConfigEntry{
+		{Key: "risk.rule.default.position_limit"},
+		{Key: "risk.rule.default.position_limit.unknown"},
+		{Key: "risk.rule.default.position_limit.scope", Str: "global"},
+	}
+	for _, e := range invalid {
+		if _, err := ParseRuleSets([]ConfigEntry{e}); err == nil {
+			t.Errorf("expected error for %s", e.Key)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package settlement
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存结算仓储（用于回测）
+type MemoryRepo struct {
+	mu          sync.RWMutex
+	settlements []*model.Settlement
+}
+
+// NewMemoryRepo 创建内存结算仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+// SaveSettlement 保存已平仓交易结算
+func (r *MemoryRepo) SaveSettlement(ctx context.Context, settlement *model.Settlement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *settlement
+	r.settlements = append(r.settlements, &copied)
+	return nil
+}
+
+// ListRecentSettlements 按策略查询最近的结算记录（按平仓时间升序）
+func (r *MemoryRepo) ListRecentSettlements(ctx context.Context, perStrategy int) ([]*model.Settlement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sorted := make([]*model.Settlement, len(r.settlements))
+	copy(sorted, r.settlements)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ClosedAt.After(sorted[j].ClosedAt)
+	})
+
+	// 倒序取每个策略最近 perStrategy 笔
+	counts := make(map[string]int)
+	var recent []*model.Settlement
+	for _, s := range sorted {
+		if counts[s.StrategyID] >= perStrategy {
+			continue
+		}
+		counts[s.StrategyID]++
+		copied := *s
+		recent = append(recent, &copied)
+	}
+
+	// 恢复为升序
+	for i, j := 0, len(recent)-1; i < j; i, j = i+1, j-1 {
+		recent[i], recent[j] = recent[j], recent[i]
+	}
+	return recent, nil
+}
@@ -0,0 +1,44 @@
+package settlement
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_ListRecentSettlements(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	base := time.Now()
+
+	for i := 0; i < 5; i++ {
+		_ = repo.SaveSettlement(ctx, &model.Settlement{
+			TradeID:     "a",
+			StrategyID:  "alpha",
+			RealizedPnL: model.NewMoneyFromInt(int64(i)),
+			ClosedAt:    base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	_ = repo.SaveSettlement(ctx, &model.Settlement{TradeID: "b", StrategyID: "beta", ClosedAt: base})
+
+	recent, err := repo.ListRecentSettlements(ctx, 3)
+	if err != nil {
+		t.Fatalf("ListRecentSettlements failed: %v", err)
+	}
+	if len(recent) != 4 {
+		t.Fatalf("expected 3 alpha + 1 beta settlements, got %d", len(recent))
+	}
+
+	// 按平仓时间升序，alpha 只保留最近 3 笔
+	var alpha []string
+	for _, s := range recent {
+		if s.StrategyID == "alpha" {
+			alpha = append(alpha, s.RealizedPnL.String())
+		}
+	}
+	if len(alpha) != 3 || alpha[0] != "2" || alpha[2] != "4" {
+		t.Errorf("unexpected alpha window: %v", alpha)
+	}
+}
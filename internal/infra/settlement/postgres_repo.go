@@ -0,0 +1,154 @@
+package settlement
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 结算仓储（实现 port.SettlementRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 结算仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+// settlementMetadata settlements.metadata 中与绩效统计相关的字段
+type settlementMetadata struct {
+	StrategyID string `json:"strategy_id"`
+	RiskAmount string `json:"risk_amount,omitempty"`
+}
+
+// SaveSettlement 保存已平仓交易结算
+func (r *PostgresRepo) SaveSettlement(ctx context.Context, s *model.Settlement) error {
+	meta := settlementMetadata{StrategyID: s.StrategyID}
+	if !s.RiskAmount.IsZero() {
+		meta.RiskAmount = s.RiskAmount.String()
+	}
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshal settlement metadata failed: %w", err)
+	}
+
+	query := `
+		INSERT INTO settlements (
+			trade_id, symbol, market_type, side,
+			realized_pnl, commission, entry_price, exit_price, quantity,
+			opened_at, closed_at, duration_seconds, metadata
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		s.TradeID,
+		s.Symbol,
+		model.MarketTypeSpot.String(),
+		formatDirection(s.Side),
+		s.RealizedPnL.String(),
+		s.Commission.String(),
+		s.EntryPrice.String(),
+		s.ExitPrice.String(),
+		s.Quantity.String(),
+		s.OpenedAt,
+		s.ClosedAt,
+		int64(s.ClosedAt.Sub(s.OpenedAt).Seconds()),
+		metaJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("save settlement failed: %w", err)
+	}
+	return nil
+}
+
+// ListRecentSettlements 按策略查询最近的结算记录（按平仓时间升序）
+func (r *PostgresRepo) ListRecentSettlements(ctx context.Context, perStrategy int) ([]*model.Settlement, error) {
+	query := `
+		SELECT
+			trade_id, strategy_id, symbol, side,
+			realized_pnl, commission, entry_price, exit_price, quantity,
+			risk_amount, opened_at, closed_at
+		FROM (
+			SELECT
+				trade_id,
+				COALESCE(metadata->>'strategy_id', '') AS strategy_id,
+				symbol, side,
+				realized_pnl, commission, entry_price, exit_price, quantity,
+				COALESCE(metadata->>'risk_amount', '0') AS risk_amount,
+				opened_at, closed_at,
+				ROW_NUMBER() OVER (
+					PARTITION BY COALESCE(metadata->>'strategy_id', '')
+					ORDER BY closed_at DESC
+				) AS rn
+			FROM settlements
+		) recent
+		WHERE rn <= $1
+		ORDER BY closed_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, perStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("list recent settlements failed: %w", err)
+	}
+	defer rows.Close()
+
+	var settlements []*model.Settlement
+	for rows.Next() {
+		var (
+			tradeID, strategyID, symbol, side                      string
+			pnl, commission, entryPrice, exitPrice, quantity, risk string
+			openedAt, closedAt                                     time.Time
+		)
+
+		err := rows.Scan(
+			&tradeID, &strategyID, &symbol, &side,
+			&pnl, &commission, &entryPrice, &exitPrice, &quantity,
+			&risk, &openedAt, &closedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan settlement failed: %w", err)
+		}
+
+		settlements = append(settlements, &model.Settlement{
+			TradeID:     tradeID,
+			StrategyID:  strategyID,
+			Symbol:      symbol,
+			Side:        parseDirection(side),
+			RealizedPnL: model.MustMoney(pnl),
+			Commission:  model.MustMoney(commission),
+			EntryPrice:  model.MustMoney(entryPrice),
+			ExitPrice:   model.MustMoney(exitPrice),
+			Quantity:    model.MustMoney(quantity),
+			RiskAmount:  model.MustMoney(risk),
+			OpenedAt:    openedAt,
+			ClosedAt:    closedAt,
+		})
+	}
+
+	return settlements, rows.Err()
+}
+
+// 辅助函数：持仓方向转换为 settlements.side [ENUM: LONG, SHORT]
+func formatDirection(side model.OrderSide) string {
+	if side == model.OrderSideSell {
+		return "SHORT"
+	}
+	return "LONG"
+}
+
+// 辅助函数：解析 settlements.side
+func parseDirection(s string) model.OrderSide {
+	if s == "SHORT" {
+		return model.OrderSideSell
+	}
+	return model.OrderSideBuy
+}
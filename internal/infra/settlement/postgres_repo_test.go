@@ -0,0 +1,82 @@
+package settlement
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	tradeID := "test-settlement-" + time.Now().Format("20060102150405")
+	strategyID := "test-strategy-" + time.Now().Format("20060102150405")
+	now := time.Now()
+
+	err = repo.SaveSettlement(ctx, &model.Settlement{
+		TradeID:     tradeID,
+		StrategyID:  strategyID,
+		Symbol:      "BTCUSDT",
+		Side:        model.OrderSideBuy,
+		RealizedPnL: model.MustMoney("120"),
+		Commission:  model.MustMoney("5"),
+		EntryPrice:  model.MustMoney("50000"),
+		ExitPrice:   model.MustMoney("51250"),
+		Quantity:    model.MustMoney("0.1"),
+		RiskAmount:  model.MustMoney("100"),
+		OpenedAt:    now.Add(-time.Hour),
+		ClosedAt:    now,
+	})
+	if err != nil {
+		t.Fatalf("SaveSettlement failed: %v", err)
+	}
+
+	recent, err := repo.ListRecentSettlements(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListRecentSettlements failed: %v", err)
+	}
+
+	var found *model.Settlement
+	for _, s := range recent {
+		if s.TradeID == tradeID {
+			found = s
+		}
+	}
+	if found == nil {
+		t.Fatal("saved settlement not found")
+	}
+	if found.StrategyID != strategyID || !found.RiskAmount.EQ(model.MustMoney("100")) {
+		t.Errorf("unexpected settlement: %+v", found)
+	}
+
+	// 清理测试数据
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements WHERE trade_id = $1", tradeID)
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
@@ -0,0 +1,87 @@
+package stop
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存止损仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu          sync.RWMutex
+	stops       map[string]*model.StopLevel // key: PositionID
+	adjustments []*model.StopAdjustment
+}
+
+// NewMemoryRepo 创建内存止损仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		stops: make(map[string]*model.StopLevel),
+	}
+}
+
+// SaveStop 保存止损（幂等）
+func (r *MemoryRepo) SaveStop(ctx context.Context, stop *model.StopLevel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *stop
+	r.stops[stop.PositionID] = &copied
+	return nil
+}
+
+// DeleteStop 删除止损
+func (r *MemoryRepo) DeleteStop(ctx context.Context, positionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.stops, positionID)
+	return nil
+}
+
+// ListStops 列出所有生效中的止损
+func (r *MemoryRepo) ListStops(ctx context.Context) ([]*model.StopLevel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stops := make([]*model.StopLevel, 0, len(r.stops))
+	for _, s := range r.stops {
+		copied := *s
+		stops = append(stops, &copied)
+	}
+	return stops, nil
+}
+
+// SaveAdjustment 追加止损调整记录
+func (r *MemoryRepo) SaveAdjustment(ctx context.Context, adj *model.StopAdjustment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *adj
+	r.adjustments = append(r.adjustments, &copied)
+	return nil
+}
+
+// ListAdjustments 查询止损调整记录（按时间倒序）
+func (r *MemoryRepo) ListAdjustments(ctx context.Context, positionID string, limit int) ([]*model.StopAdjustment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*model.StopAdjustment
+	for _, adj := range r.adjustments {
+		if positionID == "" || adj.PositionID == positionID {
+			copied := *adj
+			result = append(result, &copied)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].AdjustedAt.After(result[j].AdjustedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package stop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_Stops(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+
+	stop := &model.StopLevel{
+		PositionID: "acc:test:BTCUSDT",
+		Symbol:     "BTCUSDT",
+		Side:       model.OrderSideBuy,
+		Quantity:   model.MustMoney("0.1"),
+		EntryPrice: model.MustMoney("50000"),
+		StopLoss:   model.MustMoney("49000"),
+	}
+	if err := repo.SaveStop(ctx, stop); err != nil {
+		t.Fatalf("SaveStop failed: %v", err)
+	}
+
+	// 修改原对象不影响已保存数据
+	stop.StopLoss = model.MustMoney("1")
+
+	stops, err := repo.ListStops(ctx)
+	if err != nil {
+		t.Fatalf("ListStops failed: %v", err)
+	}
+	if len(stops) != 1 || !stops[0].StopLoss.EQ(model.MustMoney("49000")) {
+		t.Fatalf("unexpected stops: %+v", stops)
+	}
+
+	if err := repo.DeleteStop(ctx, "acc:test:BTCUSDT"); err != nil {
+		t.Fatalf("DeleteStop failed: %v", err)
+	}
+	stops, _ = repo.ListStops(ctx)
+	if len(stops) != 0 {
+		t.Errorf("expected no stops after delete, got %d", len(stops))
+	}
+}
+
+func TestMemoryRepo_Adjustments(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	base := time.Now()
+
+	_ = repo.SaveAdjustment(ctx, &model.StopAdjustment{PositionID: "p1", Reason: "first", AdjustedAt: base})
+	_ = repo.SaveAdjustment(ctx, &model.StopAdjustment{PositionID: "p2", Reason: "other", AdjustedAt: base.Add(time.Second)})
+	_ = repo.SaveAdjustment(ctx, &model.StopAdjustment{PositionID: "p1", Reason: "second", AdjustedAt: base.Add(2 * time.Second)})
+
+	adjustments, err := repo.ListAdjustments(ctx, "p1", 10)
+	if err != nil {
+		t.Fatalf("ListAdjustments failed: %v", err)
+	}
+	if len(adjustments) != 2 || adjustments[0].Reason != "second" {
+		t.Fatalf("expected newest first for p1, got %+v", adjustments)
+	}
+
+	all, _ := repo.ListAdjustments(ctx, "", 2)
+	if len(all) != 2 || all[1].Reason != "other" {
+		t.Errorf("unexpected limited list: %+v", all)
+	}
+}
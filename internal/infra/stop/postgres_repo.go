@@ -0,0 +1,207 @@
+package stop
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 止损仓储（实现 port.StopRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 止损仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+// SaveStop 保存止损（幂等）
+func (r *PostgresRepo) SaveStop(ctx context.Context, stop *model.StopLevel) error {
+	query := `
+		INSERT INTO position_stops (
+			position_id, account_id, strategy_id, symbol, side,
+			quantity, entry_price, stop_loss, take_profit, initial_stop,
+			stage, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)
+		ON CONFLICT (position_id) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			entry_price = EXCLUDED.entry_price,
+			stop_loss = EXCLUDED.stop_loss,
+			take_profit = EXCLUDED.take_profit,
+			initial_stop = EXCLUDED.initial_stop,
+			stage = EXCLUDED.stage,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		stop.PositionID,
+		stop.AccountID,
+		stop.StrategyID,
+		stop.Symbol,
+		stop.Side.String(),
+		stop.Quantity.String(),
+		stop.EntryPrice.String(),
+		stop.StopLoss.String(),
+		stop.TakeProfit.String(),
+		stop.InitialStop.String(),
+		stop.Stage.String(),
+		stop.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save stop failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteStop 删除止损
+func (r *PostgresRepo) DeleteStop(ctx context.Context, positionID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM position_stops WHERE position_id = $1`, positionID)
+	if err != nil {
+		return fmt.Errorf("delete stop failed: %w", err)
+	}
+	return nil
+}
+
+// ListStops 列出所有生效中的止损
+func (r *PostgresRepo) ListStops(ctx context.Context) ([]*model.StopLevel, error) {
+	query := `
+		SELECT
+			position_id, account_id, strategy_id, symbol, side,
+			quantity, entry_price, stop_loss, take_profit, initial_stop,
+			stage, updated_at
+		FROM position_stops
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list stops failed: %w", err)
+	}
+	defer rows.Close()
+
+	var stops []*model.StopLevel
+	for rows.Next() {
+		var (
+			positionID, accountID, strategyID, symbol, side, stage  string
+			quantity, entryPrice, stopLoss, takeProfit, initialStop string
+			updatedAt                                               time.Time
+		)
+
+		err := rows.Scan(
+			&positionID, &accountID, &strategyID, &symbol, &side,
+			&quantity, &entryPrice, &stopLoss, &takeProfit, &initialStop,
+			&stage, &updatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan stop failed: %w", err)
+		}
+
+		stops = append(stops, &model.StopLevel{
+			PositionID:  positionID,
+			AccountID:   accountID,
+			StrategyID:  strategyID,
+			Symbol:      symbol,
+			Side:        parseOrderSide(side),
+			Quantity:    model.MustMoney(quantity),
+			EntryPrice:  model.MustMoney(entryPrice),
+			StopLoss:    model.MustMoney(stopLoss),
+			TakeProfit:  model.MustMoney(takeProfit),
+			InitialStop: model.MustMoney(initialStop),
+			Stage:       model.ParseStopStage(stage),
+			UpdatedAt:   updatedAt,
+		})
+	}
+
+	return stops, rows.Err()
+}
+
+// SaveAdjustment 追加止损调整记录
+func (r *PostgresRepo) SaveAdjustment(ctx context.Context, adj *model.StopAdjustment) error {
+	query := `
+		INSERT INTO stop_adjustments (
+			position_id, symbol, old_stop, new_stop, stage,
+			mark_price, reason, adjusted_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		adj.PositionID,
+		adj.Symbol,
+		adj.OldStop.String(),
+		adj.NewStop.String(),
+		adj.Stage.String(),
+		adj.MarkPrice.String(),
+		adj.Reason,
+		adj.AdjustedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save stop adjustment failed: %w", err)
+	}
+	return nil
+}
+
+// ListAdjustments 查询止损调整记录（按时间倒序）
+func (r *PostgresRepo) ListAdjustments(ctx context.Context, positionID string, limit int) ([]*model.StopAdjustment, error) {
+	query := `
+		SELECT
+			position_id, symbol, old_stop, new_stop, stage,
+			mark_price, reason, adjusted_at
+		FROM stop_adjustments
+		WHERE ($1 = '' OR position_id = $1)
+		ORDER BY adjusted_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, positionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list stop adjustments failed: %w", err)
+	}
+	defer rows.Close()
+
+	var adjustments []*model.StopAdjustment
+	for rows.Next() {
+		var (
+			posID, symbol, oldStop, newStop, stage, markPrice, reason string
+			adjustedAt                                                time.Time
+		)
+
+		err := rows.Scan(
+			&posID, &symbol, &oldStop, &newStop, &stage,
+			&markPrice, &reason, &adjustedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan stop adjustment failed: %w", err)
+		}
+
+		adjustments = append(adjustments, &model.StopAdjustment{
+			PositionID: posID,
+			Symbol:     symbol,
+			OldStop:    model.MustMoney(oldStop),
+			NewStop:    model.MustMoney(newStop),
+			Stage:      model.ParseStopStage(stage),
+			MarkPrice:  model.MustMoney(markPrice),
+			Reason:     reason,
+			AdjustedAt: adjustedAt,
+		})
+	}
+
+	return adjustments, rows.Err()
+}
+
+// 辅助函数：解析持仓方向
+func parseOrderSide(s string) model.OrderSide {
+	if s == "SELL" {
+		return model.OrderSideSell
+	}
+	return model.OrderSideBuy
+}
@@ -0,0 +1,106 @@
+package stop
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	positionID := "test-stop-" + time.Now().Format("20060102150405")
+
+	t.Run("SaveAndListStops", func(t *testing.T) {
+		stop := &model.StopLevel{
+			PositionID:  positionID,
+			AccountID:   "test-account",
+			StrategyID:  "SimpleVolatility",
+			Symbol:      "BTCUSDT",
+			Side:        model.OrderSideBuy,
+			Quantity:    model.MustMoney("0.1"),
+			EntryPrice:  model.MustMoney("50000"),
+			StopLoss:    model.MustMoney("50000"),
+			InitialStop: model.MustMoney("49000"),
+			Stage:       model.StopStageBreakeven,
+			UpdatedAt:   time.Now(),
+		}
+		if err := repo.SaveStop(ctx, stop); err != nil {
+			t.Fatalf("SaveStop failed: %v", err)
+		}
+
+		stops, err := repo.ListStops(ctx)
+		if err != nil {
+			t.Fatalf("ListStops failed: %v", err)
+		}
+
+		var found *model.StopLevel
+		for _, s := range stops {
+			if s.PositionID == positionID {
+				found = s
+			}
+		}
+		if found == nil {
+			t.Fatal("saved stop not found")
+		}
+		if found.Stage != model.StopStageBreakeven || !found.InitialStop.EQ(model.MustMoney("49000")) {
+			t.Errorf("unexpected stop: stage=%s initial=%s", found.Stage, found.InitialStop)
+		}
+	})
+
+	t.Run("SaveAndListAdjustments", func(t *testing.T) {
+		err := repo.SaveAdjustment(ctx, &model.StopAdjustment{
+			PositionID: positionID,
+			Symbol:     "BTCUSDT",
+			OldStop:    model.MustMoney("49000"),
+			NewStop:    model.MustMoney("50000"),
+			Stage:      model.StopStageBreakeven,
+			MarkPrice:  model.MustMoney("51200"),
+			Reason:     "breakeven at 1.2R",
+			AdjustedAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("SaveAdjustment failed: %v", err)
+		}
+
+		adjustments, err := repo.ListAdjustments(ctx, positionID, 10)
+		if err != nil {
+			t.Fatalf("ListAdjustments failed: %v", err)
+		}
+		if len(adjustments) != 1 {
+			t.Fatalf("expected 1 adjustment, got %d", len(adjustments))
+		}
+	})
+
+	// 清理测试数据
+	_ = repo.DeleteStop(ctx, positionID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM stop_adjustments WHERE position_id = $1", positionID)
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
@@ -0,0 +1,55 @@
+package trade
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存成交仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu     sync.RWMutex
+	trades map[string]*model.Trade // key: Symbol + ":" + ExecID
+}
+
+// NewMemoryRepo 创建内存成交仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		trades: make(map[string]*model.Trade),
+	}
+}
+
+// SaveTrade 保存成交（已存在时忽略）
+func (r *MemoryRepo) SaveTrade(ctx context.Context, trade *model.Trade) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := trade.Symbol + ":" + trade.ExecID
+	if _, exists := r.trades[key]; exists {
+		return false, nil
+	}
+	copied := *trade
+	r.trades[key] = &copied
+	return true, nil
+}
+
+// ListTrades 查询 since 之后的成交（按成交时间升序）
+func (r *MemoryRepo) ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trades := make([]*model.Trade, 0)
+	for _, t := range r.trades {
+		if (symbol == "" || t.Symbol == symbol) && !t.TradedAt.Before(since) {
+			copied := *t
+			trades = append(trades, &copied)
+		}
+	}
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].TradedAt.Before(trades[j].TradedAt)
+	})
+	return trades, nil
+}
@@ -0,0 +1,45 @@
+package trade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_SaveTrade(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	now := time.Now()
+
+	trades := []*model.Trade{
+		{ExecID: "2", Symbol: "BTCUSDT", Quantity: model.MustMoney("0.2"), TradedAt: now},
+		{ExecID: "1", Symbol: "BTCUSDT", Quantity: model.MustMoney("0.1"), TradedAt: now.Add(-time.Minute)},
+		{ExecID: "1", Symbol: "ETHUSDT", Quantity: model.MustMoney("1"), TradedAt: now.Add(-time.Hour)},
+	}
+	for _, tr := range trades {
+		inserted, err := repo.SaveTrade(ctx, tr)
+		if err != nil || !inserted {
+			t.Fatalf("SaveTrade(%s %s) = %v, %v", tr.Symbol, tr.ExecID, inserted, err)
+		}
+	}
+
+	// 重复写入忽略
+	if inserted, _ := repo.SaveTrade(ctx, trades[0]); inserted {
+		t.Error("expected duplicate trade to be ignored")
+	}
+
+	listed, err := repo.ListTrades(ctx, "BTCUSDT", now.Add(-2*time.Minute))
+	if err != nil {
+		t.Fatalf("ListTrades failed: %v", err)
+	}
+	if len(listed) != 2 || listed[0].ExecID != "1" || listed[1].ExecID != "2" {
+		t.Errorf("unexpected trades: %+v", listed)
+	}
+
+	all, _ := repo.ListTrades(ctx, "", now.Add(-2*time.Hour))
+	if len(all) != 3 {
+		t.Errorf("expected 3 trades, got %d", len(all))
+	}
+}
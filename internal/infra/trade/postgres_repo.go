@@ -0,0 +1,107 @@
+package trade
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 成交仓储（实现 port.TradeRepo 接口，写入 executions 表）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 成交仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+// SaveTrade 保存成交（按 exec_id + symbol 幂等，已存在时忽略）
+func (r *PostgresRepo) SaveTrade(ctx context.Context, trade *model.Trade) (bool, error) {
+	query := `
+		INSERT INTO executions (
+			client_oid, exec_id, symbol, side, price,
+			quantity, quote_qty, fee, fee_asset, traded_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+		ON CONFLICT (exec_id, symbol) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		trade.ClientOrderID,
+		trade.ExecID,
+		trade.Symbol,
+		trade.Side.String(),
+		trade.Price.String(),
+		trade.Quantity.String(),
+		trade.QuoteQty.String(),
+		trade.Fee.String(),
+		trade.FeeAsset,
+		trade.TradedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("save trade failed: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("save trade failed: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ListTrades 查询 since 之后的成交（按成交时间升序）
+func (r *PostgresRepo) ListTrades(ctx context.Context, symbol string, since time.Time) ([]*model.Trade, error) {
+	query := `
+		SELECT
+			client_oid, exec_id, symbol, side, price,
+			quantity, quote_qty, fee, COALESCE(fee_asset, ''), traded_at
+		FROM executions
+		WHERE ($1 = '' OR symbol = $1) AND traded_at >= $2
+		ORDER BY traded_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, symbol, since)
+	if err != nil {
+		return nil, fmt.Errorf("list trades failed: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []*model.Trade
+	for rows.Next() {
+		var (
+			clientOID, execID, sym, side, feeAsset string
+			price, quantity, quoteQty, fee         string
+			tradedAt                               time.Time
+		)
+
+		err := rows.Scan(
+			&clientOID, &execID, &sym, &side, &price,
+			&quantity, &quoteQty, &fee, &feeAsset, &tradedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan trade failed: %w", err)
+		}
+
+		trades = append(trades, &model.Trade{
+			ExecID:        execID,
+			ClientOrderID: clientOID,
+			Symbol:        sym,
+			Side:          model.ParseOrderSide(side),
+			Price:         model.MustMoney(price),
+			Quantity:      model.MustMoney(quantity),
+			QuoteQty:      model.MustMoney(quoteQty),
+			Fee:           model.MustMoney(fee),
+			FeeAsset:      feeAsset,
+			TradedAt:      tradedAt,
+		})
+	}
+
+	return trades, rows.Err()
+}
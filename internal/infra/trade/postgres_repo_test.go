@@ -0,0 +1,85 @@
+package trade
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+	execID := "test-exec-" + time.Now().Format("20060102150405")
+	tradedAt := time.Now().Truncate(time.Millisecond)
+
+	t.Run("SaveAndListTrades", func(t *testing.T) {
+		trade := &model.Trade{
+			ExecID:        execID,
+			ClientOrderID: "test-order-" + execID,
+			Symbol:        "BTCUSDT",
+			Side:          model.OrderSideBuy,
+			Price:         model.MustMoney("50000"),
+			Quantity:      model.MustMoney("0.1"),
+			QuoteQty:      model.MustMoney("5000"),
+			Fee:           model.MustMoney("5"),
+			FeeAsset:      "USDT",
+			TradedAt:      tradedAt,
+		}
+
+		inserted, err := repo.SaveTrade(ctx, trade)
+		if err != nil || !inserted {
+			t.Fatalf("SaveTrade = %v, %v", inserted, err)
+		}
+		if inserted, _ := repo.SaveTrade(ctx, trade); inserted {
+			t.Error("expected duplicate trade to be ignored")
+		}
+
+		trades, err := repo.ListTrades(ctx, "BTCUSDT", tradedAt)
+		if err != nil {
+			t.Fatalf("ListTrades failed: %v", err)
+		}
+		found := false
+		for _, tr := range trades {
+			if tr.ExecID == execID {
+				found = true
+				if !tr.QuoteQty.EQ(model.MustMoney("5000")) || tr.Side != model.OrderSideBuy {
+					t.Errorf("unexpected trade: %+v", tr)
+				}
+			}
+		}
+		if !found {
+			t.Error("expected saved trade to be listed")
+		}
+	})
+
+	// 清理测试数据
+	_, _ = db.ExecContext(ctx, "DELETE FROM executions WHERE exec_id = $1", execID)
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
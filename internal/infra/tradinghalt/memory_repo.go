@@ -0,0 +1,62 @@
+package tradinghalt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// MemoryRepo 内存交易暂停仓储（用于回测与测试）
+type MemoryRepo struct {
+	mu     sync.RWMutex
+	halts  []*model.TradingHalt
+	nextID int64
+}
+
+// NewMemoryRepo 创建内存交易暂停仓储
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+// SaveHalt 保存新的暂停记录
+func (r *MemoryRepo) SaveHalt(ctx context.Context, halt *model.TradingHalt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	halt.ID = r.nextID
+	copied := *halt
+	r.halts = append(r.halts, &copied)
+	return nil
+}
+
+// UpdateHalt 更新暂停记录
+func (r *MemoryRepo) UpdateHalt(ctx context.Context, halt *model.TradingHalt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, h := range r.halts {
+		if h.ID == halt.ID {
+			copied := *halt
+			r.halts[i] = &copied
+			return nil
+		}
+	}
+	return fmt.Errorf("trading halt not found: %d", halt.ID)
+}
+
+// ActiveHalt 获取最近一条仍生效的暂停记录
+func (r *MemoryRepo) ActiveHalt(ctx context.Context) (*model.TradingHalt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.halts) - 1; i >= 0; i-- {
+		if r.halts[i].IsActive() {
+			copied := *r.halts[i]
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
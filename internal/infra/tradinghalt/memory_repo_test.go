@@ -0,0 +1,65 @@
+package tradinghalt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+func TestMemoryRepo_Halts(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+
+	active, err := repo.ActiveHalt(ctx)
+	if err != nil || active != nil {
+		t.Fatalf("expected no active halt, got %+v (%v)", active, err)
+	}
+
+	halt := &model.TradingHalt{
+		Status:   model.TradingHaltStatusActive,
+		Reason:   "exchange incident",
+		HaltedBy: 1,
+		HaltedAt: time.Now(),
+	}
+	if err := repo.SaveHalt(ctx, halt); err != nil {
+		t.Fatalf("SaveHalt failed: %v", err)
+	}
+	if halt.ID == 0 {
+		t.Fatal("expected halt ID assigned")
+	}
+
+	// 修改入参不影响仓储
+	halt.Reason = "changed"
+	active, _ = repo.ActiveHalt(ctx)
+	if active == nil || active.ID != halt.ID || active.Reason != "exchange incident" {
+		t.Fatalf("unexpected active halt: %+v", active)
+	}
+
+	// 等待确认解除时仍生效
+	active.Status = model.TradingHaltStatusReleasePending
+	active.ReleaseRequestedBy = 1
+	active.ReleaseRequestedAt = time.Now()
+	if err := repo.UpdateHalt(ctx, active); err != nil {
+		t.Fatalf("UpdateHalt failed: %v", err)
+	}
+	active, _ = repo.ActiveHalt(ctx)
+	if active == nil || active.Status != model.TradingHaltStatusReleasePending {
+		t.Fatalf("expected pending halt still active, got %+v", active)
+	}
+
+	active.Status = model.TradingHaltStatusReleased
+	active.ReleasedBy = 2
+	active.ReleasedAt = time.Now()
+	if err := repo.UpdateHalt(ctx, active); err != nil {
+		t.Fatalf("UpdateHalt failed: %v", err)
+	}
+	if active, _ = repo.ActiveHalt(ctx); active != nil {
+		t.Errorf("expected no active halt after release, got %+v", active)
+	}
+
+	if err := repo.UpdateHalt(ctx, &model.TradingHalt{ID: 99}); err == nil {
+		t.Error("expected unknown halt rejected")
+	}
+}
@@ -0,0 +1,123 @@
+package tradinghalt
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+)
+
+// PostgresRepo PostgreSQL 交易暂停仓储（实现 port.TradingHaltRepo 接口）
+type PostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresRepo 创建 PostgreSQL 交易暂停仓储
+func NewPostgresRepo(db *sql.DB) *PostgresRepo {
+	return &PostgresRepo{
+		db: db,
+	}
+}
+
+const haltColumns = `id, status, reason, COALESCE(halted_by, 0), halted_at,
+	cancelled_orders, flattened, flattened_positions,
+	COALESCE(release_requested_by, 0), release_requested_at,
+	COALESCE(released_by, 0), released_at`
+
+// SaveHalt 保存新的暂停记录（写入后回填 ID）
+func (r *PostgresRepo) SaveHalt(ctx context.Context, halt *model.TradingHalt) error {
+	query := `
+		INSERT INTO trading_halts (
+			status, reason, halted_by, halted_at,
+			cancelled_orders, flattened, flattened_positions
+		) VALUES (
+			$1, $2, NULLIF($3, 0), $4, $5, $6, $7
+		)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		halt.Status,
+		halt.Reason,
+		halt.HaltedBy,
+		halt.HaltedAt,
+		halt.CancelledOrders,
+		halt.Flattened,
+		halt.FlattenedPositions,
+	).Scan(&halt.ID)
+	if err != nil {
+		return fmt.Errorf("save trading halt failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateHalt 更新暂停记录的处置结果与解除状态
+func (r *PostgresRepo) UpdateHalt(ctx context.Context, halt *model.TradingHalt) error {
+	query := `
+		UPDATE trading_halts SET
+			status = $2,
+			cancelled_orders = $3,
+			flattened = $4,
+			flattened_positions = $5,
+			release_requested_by = NULLIF($6, 0),
+			release_requested_at = $7,
+			released_by = NULLIF($8, 0),
+			released_at = $9,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		halt.ID,
+		halt.Status,
+		halt.CancelledOrders,
+		halt.Flattened,
+		halt.FlattenedPositions,
+		halt.ReleaseRequestedBy,
+		nullTime(halt.ReleaseRequestedAt),
+		halt.ReleasedBy,
+		nullTime(halt.ReleasedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("update trading halt failed: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("trading halt not found: %d", halt.ID)
+	}
+	return nil
+}
+
+// ActiveHalt 获取最近一条仍生效的暂停记录
+func (r *PostgresRepo) ActiveHalt(ctx context.Context) (*model.TradingHalt, error) {
+	query := `SELECT ` + haltColumns + ` FROM trading_halts WHERE status <> $1 ORDER BY id DESC LIMIT 1`
+
+	var (
+		h                       model.TradingHalt
+		requestedAt, releasedAt sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, query, model.TradingHaltStatusReleased).Scan(
+		&h.ID, &h.Status, &h.Reason, &h.HaltedBy, &h.HaltedAt,
+		&h.CancelledOrders, &h.Flattened, &h.FlattenedPositions,
+		&h.ReleaseRequestedBy, &requestedAt,
+		&h.ReleasedBy, &releasedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get active trading halt failed: %w", err)
+	}
+	h.ReleaseRequestedAt = requestedAt.Time
+	h.ReleasedAt = releasedAt.Time
+	return &h, nil
+}
+
+// nullTime 零值时间转为 NULL
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
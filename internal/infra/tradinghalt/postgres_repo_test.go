@@ -0,0 +1,82 @@
+package tradinghalt
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepo_Integration 集成测试（需要真实数据库）
+func TestPostgresRepo_Integration(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresRepo(db)
+	ctx := context.Background()
+
+	if active, err := repo.ActiveHalt(ctx); err != nil {
+		t.Fatalf("ActiveHalt failed: %v", err)
+	} else if active != nil {
+		t.Skipf("Skipping integration test: trading halt %d is active", active.ID)
+	}
+
+	halt := &model.TradingHalt{
+		Status:          model.TradingHaltStatusActive,
+		Reason:          "integration test",
+		HaltedAt:        time.Now(),
+		CancelledOrders: 3,
+		Flattened:       true,
+	}
+	if err := repo.SaveHalt(ctx, halt); err != nil {
+		t.Fatalf("SaveHalt failed: %v", err)
+	}
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM trading_halts WHERE id = $1", halt.ID)
+	}()
+
+	// 已有生效暂停时由唯一索引拒绝
+	if err := repo.SaveHalt(ctx, &model.TradingHalt{Status: model.TradingHaltStatusActive, HaltedAt: time.Now()}); err == nil {
+		t.Error("expected second active halt rejected")
+	}
+
+	active, err := repo.ActiveHalt(ctx)
+	if err != nil {
+		t.Fatalf("ActiveHalt failed: %v", err)
+	}
+	if active == nil || active.ID != halt.ID || active.CancelledOrders != 3 || !active.Flattened || active.HaltedBy != 0 {
+		t.Fatalf("unexpected active halt: %+v", active)
+	}
+
+	active.Status = model.TradingHaltStatusReleased
+	active.ReleasedAt = time.Now()
+	if err := repo.UpdateHalt(ctx, active); err != nil {
+		t.Fatalf("UpdateHalt failed: %v", err)
+	}
+	if active, err = repo.ActiveHalt(ctx); err != nil || active != nil {
+		t.Errorf("expected no active halt after release, got %+v (%v)", active, err)
+	}
+}
+
+// TestPostgresRepo_Unit 单元测试（不需要数据库）
+func TestPostgresRepo_Unit(t *testing.T) {
+	repo := NewPostgresRepo(nil)
+	if repo == nil {
+		t.Fatal("NewPostgresRepo returned nil")
+	}
+	if repo.db != nil {
+		t.Error("Expected db to be nil")
+	}
+}
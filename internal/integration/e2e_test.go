@@ -142,7 +142,7 @@ func TestE2E_TradingFlow(t *testing.T) {
 		_ = setup.riskRepo.RecordTrade(ctx, accountID, model.MustMoney("-200"))
 
 		// 清除缓存
-		setup.riskMgr.InvalidateCache(accountID, "")
+		setup.riskMgr.InvalidateCache(model.AccountScope(accountID))
 
 		// 尝试下单应被熔断
 		orderCtx := &risklogic.OrderContext{
@@ -411,7 +411,7 @@ func TestE2E_StatePersistence(t *testing.T) {
 	}
 
 	// 模拟系统重启：重新加载状态
-	reloadedState, err := riskRepo.LoadState(ctx, accountID, "")
+	reloadedState, err := riskRepo.LoadState(ctx, model.AccountScope(accountID))
 	if err != nil {
 		t.Fatalf("重新加载风控状态失败: %v", err)
 	}
@@ -48,6 +48,14 @@ func (l *DashboardLogic) Dashboard(req *types.DashboardReq) (resp *types.Dashboa
 		l.Errorf("Failed to fetch strategies: %v", err)
 	}
 
+	// 5. 获取持仓止损
+	if err := l.fetchStops(resp); err != nil {
+		l.Errorf("Failed to fetch stops: %v", err)
+	}
+
+	// 6. 获取持仓账本
+	l.fetchPositions(resp)
+
 	return resp, nil
 }
 
@@ -82,7 +90,7 @@ func (l *DashboardLogic) fetchRiskStatus(resp *types.DashboardResp) error {
 	}
 
 	accountID := "default-account"
-	state, err := l.svcCtx.RiskRepo.LoadState(l.ctx, accountID, "")
+	state, err := l.svcCtx.RiskRepo.LoadState(l.ctx, model.AccountScope(accountID))
 	if err != nil {
 		// 如果状态不存在，返回默认值
 		resp.RiskStatus = types.RiskStatus{
@@ -116,11 +124,13 @@ func (l *DashboardLogic) fetchRiskStatus(resp *types.DashboardResp) error {
 		resp.PnLPercent = pnlPercent.String()
 	}
 
-	// 构建风控状态
+	// 构建风控状态（限额取当前生效版本，支持运行时调整）
 	maxConsecutiveLosses := 5
+	maxLeverage := "2.0"
 	if l.svcCtx.RiskManager != nil {
-		// 从配置获取，这里简化处理
-		maxConsecutiveLosses = 5
+		limits := l.svcCtx.RiskManager.Config()
+		maxConsecutiveLosses = limits.MaxConsecutiveLosses
+		maxLeverage = fmt.Sprintf("%d.0", limits.MaxLeverage)
 	}
 
 	leverageStatus := "relaxed"
@@ -133,7 +143,7 @@ func (l *DashboardLogic) fetchRiskStatus(resp *types.DashboardResp) error {
 		MaxConsecutiveLosses: int64(maxConsecutiveLosses),
 		MacroCoolingMode:     "inactive", // TODO: 从 EventGateway 获取
 		LeverageStatus:       leverageStatus,
-		MaxLeverage:          "2.0",
+		MaxLeverage:          maxLeverage,
 		CurrentLeverage:      "1.0", // TODO: 从实际持仓计算
 	}
 
@@ -190,29 +200,144 @@ func (l *DashboardLogic) fetchSystemHealth(resp *types.DashboardResp) {
 func (l *DashboardLogic) fetchStrategies(resp *types.DashboardResp) error {
 	strategies := []types.StrategyOverview{}
 
+	// 启用绩效统计时按策略展示滚动绩效与风险模式
+	if l.svcCtx.Performance != nil {
+		for _, perf := range l.svcCtx.Performance.List(time.Now()) {
+			strategies = append(strategies, toStrategyOverview(perf))
+		}
+		resp.Strategies = strategies
+		return nil
+	}
+
 	// 从数据库查询策略配置
 	if l.svcCtx.DB != nil {
 		// TODO: 查询策略配置表
 		// 这里简化处理，返回示例数据
 		strategies = append(strategies, types.StrategyOverview{
-			ID:        "ST-101",
-			Name:      "MACD_Breakout",
-			Symbol:    "BTCUSDT",
-			Direction: "Long",
-			Status:    "running",
-			WinRate:   "65",
+			ID:             "ST-101",
+			Name:           "MACD_Breakout",
+			Symbol:         "BTCUSDT",
+			Direction:      "Long",
+			Status:         "running",
+			WinRate:        "65",
+			RiskMode:       model.StrategyRiskModeNormal.String(),
+			SizeMultiplier: "1",
 		})
 
 		strategies = append(strategies, types.StrategyOverview{
-			ID:        "ST-102",
-			Name:      "Grid_Maker",
-			Symbol:    "ETHUSDT",
-			Direction: "N/A",
-			Status:    "stopped",
-			WinRate:   "40",
+			ID:             "ST-102",
+			Name:           "Grid_Maker",
+			Symbol:         "ETHUSDT",
+			Direction:      "N/A",
+			Status:         "stopped",
+			WinRate:        "40",
+			RiskMode:       model.StrategyRiskModeNormal.String(),
+			SizeMultiplier: "1",
 		})
 	}
 
 	resp.Strategies = strategies
 	return nil
 }
+
+// toStrategyOverview 将策略绩效快照转换为概览（冷静期显示为 cooling）
+func toStrategyOverview(perf model.StrategyPerformance) types.StrategyOverview {
+	item := types.StrategyOverview{
+		ID:             perf.StrategyID,
+		Name:           perf.StrategyID,
+		Symbol:         perf.Symbol,
+		Direction:      "N/A",
+		Status:         "running",
+		WinRate:        fmt.Sprintf("%.0f", perf.WinRate*100),
+		Reason:         perf.Reason,
+		RiskMode:       perf.Mode.String(),
+		SizeMultiplier: fmt.Sprintf("%.2f", perf.SizeMultiplier),
+		Expectancy:     perf.Expectancy.String(),
+	}
+	if perf.Mode == model.StrategyRiskModePaused {
+		item.Status = "cooling"
+		item.PausedUntil = perf.PausedUntil.Format(time.RFC3339)
+	}
+	return item
+}
+
+func (l *DashboardLogic) fetchStops(resp *types.DashboardResp) error {
+	resp.Stops = []types.PositionStop{}
+	if l.svcCtx.StopManager == nil {
+		return nil
+	}
+
+	// 最近调整记录（按持仓取最新一条）
+	lastReasons := make(map[string]string)
+	adjustments, err := l.svcCtx.StopManager.Adjustments(l.ctx, "", 100)
+	if err != nil {
+		l.Errorf("Failed to fetch stop adjustments: %v", err)
+	}
+	for _, adj := range adjustments {
+		if _, ok := lastReasons[adj.PositionID]; !ok {
+			lastReasons[adj.PositionID] = adj.Reason
+		}
+	}
+
+	for _, s := range l.svcCtx.StopManager.List() {
+		direction := "Long"
+		if !s.IsLong() {
+			direction = "Short"
+		}
+
+		item := types.PositionStop{
+			PositionID:     s.PositionID,
+			StrategyID:     s.StrategyID,
+			Symbol:         s.Symbol,
+			Direction:      direction,
+			EntryPrice:     s.EntryPrice.String(),
+			StopLoss:       s.StopLoss.String(),
+			InitialStop:    s.InitialStop.String(),
+			Stage:          s.Stage.String(),
+			LastAdjustment: lastReasons[s.PositionID],
+			UpdatedAt:      s.UpdatedAt.Format(time.RFC3339),
+		}
+		if !s.TakeProfit.IsZero() {
+			item.TakeProfit = s.TakeProfit.String()
+		}
+		resp.Stops = append(resp.Stops, item)
+	}
+
+	return nil
+}
+
+func (l *DashboardLogic) fetchPositions(resp *types.DashboardResp) {
+	resp.Positions = []types.PositionItem{}
+	if l.svcCtx.Ledger == nil {
+		return
+	}
+
+	for _, p := range l.svcCtx.Ledger.Positions() {
+		direction := "Flat"
+		switch {
+		case p.IsLong():
+			direction = "Long"
+		case !p.IsFlat():
+			direction = "Short"
+		}
+
+		item := types.PositionItem{
+			StrategyID:    p.StrategyID,
+			Symbol:        p.Symbol,
+			Direction:     direction,
+			Quantity:      p.Quantity.String(),
+			AvgCost:       p.AvgCost.String(),
+			UnrealizedPnL: p.UnrealizedPnL().String(),
+			RealizedPnL:   p.RealizedPnL.String(),
+			Fees:          p.Fees.String(),
+			UpdatedAt:     p.UpdatedAt.Format(time.RFC3339),
+		}
+		if !p.MarkPrice.IsZero() {
+			item.MarkPrice = p.MarkPrice.String()
+		}
+		if !p.OpenedAt.IsZero() {
+			item.OpenedAt = p.OpenedAt.Format(time.RFC3339)
+		}
+		resp.Positions = append(resp.Positions, item)
+	}
+}
@@ -0,0 +1,99 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// maxRiskHistoryPoints 单次查询最大点数
+const maxRiskHistoryPoints = 10000
+
+var errRiskHistoryUnavailable = errors.New("risk history not available")
+
+type RiskHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRiskHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RiskHistoryLogic {
+	return &RiskHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RiskHistoryLogic) RiskHistory(req *types.RiskHistoryReq) (resp *types.RiskHistoryResp, err error) {
+	repo := l.svcCtx.RiskHistory
+	if repo == nil {
+		return nil, errRiskHistoryUnavailable
+	}
+
+	limit := req.Limit
+	if limit < 1 || limit > maxRiskHistoryPoints {
+		limit = maxRiskHistoryPoints
+	}
+
+	filter := model.RiskStateHistoryFilter{
+		Scope: model.RiskScope{
+			AccountID:  req.AccountID,
+			StrategyID: req.StrategyID,
+			Symbol:     req.Symbol,
+		},
+		Limit: int(limit),
+	}
+	if req.From > 0 {
+		filter.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		filter.To = time.Unix(req.To, 0)
+	}
+
+	snapshots, err := repo.ListSnapshots(l.ctx, filter)
+	if err != nil {
+		l.Errorf("Failed to list risk state history: %v", err)
+		return nil, err
+	}
+
+	resp = &types.RiskHistoryResp{
+		Points: make([]types.RiskHistoryPoint, 0, len(snapshots)),
+		Events: []types.RiskHistoryEvent{},
+	}
+	for _, s := range snapshots {
+		at := s.RecordedAt.Format(time.RFC3339)
+		resp.Points = append(resp.Points, types.RiskHistoryPoint{
+			Time:              at,
+			Kind:              s.Kind,
+			Equity:            s.CurrentEquity.String(),
+			PeakEquity:        s.PeakEquity.String(),
+			Drawdown:          s.MDD.String(),
+			DrawdownPercent:   s.MDDPercent.String(),
+			DailyPnL:          s.DailyPnL.String(),
+			Exposure:          s.TotalExposure.String(),
+			ConsecutiveLosses: int64(s.ConsecutiveLosses),
+			CircuitBreakerOn:  s.CircuitBreakerOpen,
+		})
+
+		switch s.Kind {
+		case model.RiskHistoryKindDailyRoll, model.RiskHistoryKindCircuitOpen, model.RiskHistoryKindCircuitClose:
+			event := types.RiskHistoryEvent{
+				Time:   at,
+				Kind:   s.Kind,
+				Reason: s.Reason,
+			}
+			if s.Kind == model.RiskHistoryKindCircuitOpen && s.CircuitBreakerUntil > 0 {
+				event.CircuitBreakerUntil = time.Unix(s.CircuitBreakerUntil, 0).Format(time.RFC3339)
+			}
+			resp.Events = append(resp.Events, event)
+		}
+	}
+	return resp, nil
+}
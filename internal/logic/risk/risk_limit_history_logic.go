@@ -0,0 +1,55 @@
+package risk
+
+import (
+	"context"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RiskLimitHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRiskLimitHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RiskLimitHistoryLogic {
+	return &RiskLimitHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RiskLimitHistoryLogic) RiskLimitHistory(req *types.RiskLimitHistoryReq) (resp *types.RiskLimitHistoryResp, err error) {
+	manager := l.svcCtx.RiskManager
+	if manager == nil {
+		return nil, errRiskManagerUnavailable
+	}
+
+	versions, err := manager.LimitHistory(l.ctx, int(req.Limit))
+	if err != nil {
+		l.Errorf("Failed to list risk limit versions: %v", err)
+		return nil, err
+	}
+
+	items := make([]types.RiskLimitVersionItem, 0, len(versions))
+	for _, v := range versions {
+		items = append(items, types.RiskLimitVersionItem{
+			Version:   v.Version,
+			Limits:    v.Limits,
+			Changes:   toRiskLimitChanges(v.Changes),
+			ChangedBy: v.ChangedBy,
+			Verified:  v.Verified,
+			Reason:    v.Reason,
+			CreatedAt: v.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &types.RiskLimitHistoryResp{
+		Items: items,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package risk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// errRiskManagerUnavailable 交易组件未初始化
+var errRiskManagerUnavailable = errors.New("risk manager is not initialized, please check trading configuration")
+
+type RiskLimitsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRiskLimitsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RiskLimitsLogic {
+	return &RiskLimitsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RiskLimitsLogic) RiskLimits(req *types.RiskLimitsReq) (resp *types.RiskLimitsResp, err error) {
+	manager := l.svcCtx.RiskManager
+	if manager == nil {
+		return nil, errRiskManagerUnavailable
+	}
+
+	return &types.RiskLimitsResp{
+		Version: manager.LimitVersion(),
+		Limits:  manager.Config().Params(),
+	}, nil
+}
+
+// toRiskLimitChanges 转换限额变更明细
+func toRiskLimitChanges(changes []model.RiskLimitChange) []types.RiskLimitChange {
+	items := make([]types.RiskLimitChange, 0, len(changes))
+	for _, c := range changes {
+		items = append(items, types.RiskLimitChange{
+			Field:    c.Field,
+			Old:      c.Old,
+			New:      c.New,
+			Loosened: c.Loosened,
+		})
+	}
+	return items
+}
@@ -0,0 +1,95 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// maxRiskRecordsPageSize 单页最大条数
+const maxRiskRecordsPageSize = 200
+
+var errRiskRecordsUnavailable = errors.New("risk records not available")
+
+type RiskRecordsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRiskRecordsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RiskRecordsLogic {
+	return &RiskRecordsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RiskRecordsLogic) RiskRecords(req *types.RiskRecordsReq) (resp *types.RiskRecordsResp, err error) {
+	repo := l.svcCtx.RiskRecords
+	if repo == nil {
+		return nil, errRiskRecordsUnavailable
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > maxRiskRecordsPageSize {
+		pageSize = maxRiskRecordsPageSize
+	}
+
+	filter := model.RiskRecordFilter{
+		Rule:       req.Rule,
+		Decision:   req.Decision,
+		Symbol:     req.Symbol,
+		StrategyID: req.StrategyID,
+		Offset:     int((page - 1) * pageSize),
+		Limit:      int(pageSize),
+	}
+	if req.From > 0 {
+		filter.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		filter.To = time.Unix(req.To, 0)
+	}
+
+	records, total, err := repo.ListRecords(l.ctx, filter)
+	if err != nil {
+		l.Errorf("Failed to list risk records: %v", err)
+		return nil, err
+	}
+
+	items := make([]types.RiskRecordItem, 0, len(records))
+	for _, rec := range records {
+		items = append(items, types.RiskRecordItem{
+			ID:                rec.ID,
+			Rule:              rec.EventType,
+			Level:             rec.Level,
+			Decision:          rec.Decision,
+			Reason:            rec.Reason,
+			SuggestedQuantity: rec.SuggestedQuantity,
+			SuggestedLeverage: int64(rec.SuggestedLeverage),
+			AccountID:         rec.AccountID,
+			Symbol:            rec.Symbol,
+			StrategyID:        rec.StrategyID,
+			Order:             string(rec.Order),
+			State:             string(rec.State),
+			TriggeredAt:       rec.TriggeredAt.Format(time.RFC3339),
+		})
+	}
+
+	return &types.RiskRecordsResp{
+		Total: total,
+		Items: items,
+	}, nil
+}
@@ -0,0 +1,143 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+
+	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RiskSimulateLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRiskSimulateLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RiskSimulateLogic {
+	return &RiskSimulateLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RiskSimulate 模拟下单前风控检查（不写入风控状态、状态历史与决策记录）
+func (l *RiskSimulateLogic) RiskSimulate(req *types.RiskSimulateReq) (resp *types.RiskSimulateResp, err error) {
+	manager := l.svcCtx.RiskManager
+	if manager == nil {
+		return nil, errRiskManagerUnavailable
+	}
+
+	order, err := toSimulateOrder(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := manager.Simulate(l.ctx, order)
+	if err != nil {
+		l.Errorf("Failed to simulate risk check: %v", err)
+		return nil, err
+	}
+	return toRiskSimulateResp(sim), nil
+}
+
+// toSimulateOrder 校验请求并构造订单上下文
+func toSimulateOrder(req *types.RiskSimulateReq) (*risklogic.OrderContext, error) {
+	if req.AccountID == "" || req.Symbol == "" {
+		return nil, fmt.Errorf("account_id and symbol are required")
+	}
+
+	order := &risklogic.OrderContext{
+		AccountID:  req.AccountID,
+		StrategyID: req.StrategyID,
+		Symbol:     req.Symbol,
+		MarketType: model.ParseMarketType(req.MarketType),
+		Side:       model.ParseOrderSide(req.Side),
+		Type:       model.ParseOrderType(req.Type),
+		Leverage:   int(req.Leverage),
+		ReduceOnly: req.ReduceOnly,
+	}
+	switch {
+	case order.MarketType == 0:
+		return nil, fmt.Errorf("invalid market_type: %s", req.MarketType)
+	case order.Side == 0:
+		return nil, fmt.Errorf("invalid side: %s", req.Side)
+	case order.Type == 0:
+		return nil, fmt.Errorf("invalid type: %s", req.Type)
+	}
+
+	fields := []struct {
+		name     string
+		value    string
+		required bool
+		dst      *model.Money
+	}{
+		{"quantity", req.Quantity, true, &order.Quantity},
+		{"current_price", req.CurrentPrice, true, &order.CurrentPrice},
+		{"price", req.Price, false, &order.Price},
+		{"protect_price", req.ProtectPrice, false, &order.ProtectPrice},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			if f.required {
+				return nil, fmt.Errorf("%s is required", f.name)
+			}
+			*f.dst = model.Zero()
+			continue
+		}
+		v, err := model.NewMoney(f.value)
+		if err != nil || v.IsNegative() {
+			return nil, fmt.Errorf("invalid %s: %s", f.name, f.value)
+		}
+		*f.dst = v
+	}
+	if !order.Quantity.IsPositive() {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+	return order, nil
+}
+
+// toRiskSimulateResp 转换模拟结果
+func toRiskSimulateResp(sim *risklogic.Simulation) *types.RiskSimulateResp {
+	resp := &types.RiskSimulateResp{
+		Decision:          sim.Decision.Decision.String(),
+		Reason:            sim.Decision.Reason,
+		TriggeredRule:     sim.Decision.TriggeredRule,
+		SuggestedQuantity: sim.SuggestedQuantity.String(),
+		SuggestedLeverage: int64(sim.SuggestedLeverage),
+		Rules:             make([]types.RiskSimulateRule, 0, len(sim.Rules)),
+		Before:            toRiskExposure(sim.Before),
+		After:             toRiskExposure(sim.After),
+	}
+	for _, r := range sim.Rules {
+		resp.Rules = append(resp.Rules, types.RiskSimulateRule{
+			Rule:              r.Rule,
+			Priority:          int64(r.Priority),
+			Decision:          r.Decision.Decision.String(),
+			Reason:            r.Decision.Reason,
+			TriggeredRule:     r.Decision.TriggeredRule,
+			Quantity:          r.Quantity.String(),
+			Leverage:          int64(r.Leverage),
+			SuggestedQuantity: r.Decision.SuggestedQuantity,
+			SuggestedLeverage: int64(r.Decision.SuggestedLeverage),
+		})
+	}
+	return resp
+}
+
+// toRiskExposure 转换账户敞口
+func toRiskExposure(e risklogic.Exposure) types.RiskExposure {
+	return types.RiskExposure{
+		Equity:         e.Equity.String(),
+		OrderNotional:  e.OrderNotional.String(),
+		SymbolExposure: e.SymbolExposure.String(),
+		SymbolPercent:  e.SymbolPercent,
+		TotalExposure:  e.TotalExposure.String(),
+		TotalPercent:   e.TotalPercent,
+	}
+}
@@ -0,0 +1,71 @@
+package trading
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/core/killswitch"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/pkg/ctxval"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type HaltReleaseLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewHaltReleaseLogic(ctx context.Context, svcCtx *svc.ServiceContext) *HaltReleaseLogic {
+	return &HaltReleaseLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// HaltRelease 解除全局交易暂停
+// 路由已挂载 MFAStepUp 中间件，到达此处即已通过提级认证
+func (l *HaltReleaseLogic) HaltRelease() (resp *types.HaltReleaseResponse, err error) {
+	ks := l.svcCtx.KillSwitch
+	if ks == nil {
+		return nil, errKillSwitchUnavailable
+	}
+	uid, _ := l.ctx.Value("uid").(int64)
+
+	halt, err := ks.Release(l.ctx, killswitch.ReleaseRequest{
+		UserID:   uid,
+		Verified: true,
+	}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	released := halt.Status == model.TradingHaltStatusReleased
+	action := "REQUEST_HALT_RELEASE"
+	if released {
+		action = "RELEASE_HALT"
+	}
+	if err := l.svcCtx.AuditLogs.RecordAction(
+		l.ctx,
+		uid,
+		ctxval.GetIP(l.ctx),
+		action,
+		"TRADING_HALT",
+		strconv.FormatInt(halt.ID, 10),
+		halt.Reason,
+		true,
+	); err != nil {
+		l.Errorf("Failed to record halt release audit log: %v", err)
+	}
+
+	l.Infof("Trading halt %d %s by user %d", halt.ID, halt.Status, uid)
+	return &types.HaltReleaseResponse{
+		Released: released,
+		Halt:     toTradingHaltInfo(halt),
+	}, nil
+}
@@ -0,0 +1,79 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/core/killswitch"
+	"github.com/iluyuns/alpha-trade/internal/pkg/ctxval"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var errKillSwitchReasonRequired = errors.New("kill switch reason is required")
+
+type KillSwitchLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewKillSwitchLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KillSwitchLogic {
+	return &KillSwitchLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// KillSwitch 紧急停止：撤销全部订单，可选平仓，设置持久化的全局交易暂停
+// 触发无需提级认证（紧急场景优先止损），解除需提级认证
+func (l *KillSwitchLogic) KillSwitch(req *types.KillSwitchRequest) (resp *types.KillSwitchResponse, err error) {
+	ks := l.svcCtx.KillSwitch
+	if ks == nil {
+		return nil, errKillSwitchUnavailable
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errKillSwitchReasonRequired
+	}
+	uid, _ := l.ctx.Value("uid").(int64)
+
+	result := ks.Trigger(l.ctx, killswitch.TriggerRequest{
+		UserID:  uid,
+		Reason:  reason,
+		Flatten: req.Flatten,
+	}, time.Now())
+
+	if err := l.svcCtx.AuditLogs.RecordAction(
+		l.ctx,
+		uid,
+		ctxval.GetIP(l.ctx),
+		"KILL_SWITCH",
+		"TRADING_HALT",
+		strconv.FormatInt(result.Halt.ID, 10),
+		reason,
+		false,
+	); err != nil {
+		l.Errorf("Failed to record kill switch audit log: %v", err)
+	}
+
+	l.Infof("Kill switch triggered via API by user %d (halt %d, flatten=%v)", uid, result.Halt.ID, req.Flatten)
+
+	errs := result.Errors
+	if errs == nil {
+		errs = []string{}
+	}
+	return &types.KillSwitchResponse{
+		Halt:               toTradingHaltInfo(result.Halt),
+		CancelledOrders:    result.CancelledOrders,
+		CancelledAlgos:     result.CancelledAlgos,
+		FlattenedPositions: result.FlattenedPositions,
+		Errors:             errs,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var errOrderRepoUnavailable = errors.New("order repository is not initialized, please check trading configuration")
+
+type OrderEventsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewOrderEventsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *OrderEventsLogic {
+	return &OrderEventsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// OrderEvents 查询订单状态转换历史（按发生顺序，可用于重放订单生命周期）
+func (l *OrderEventsLogic) OrderEvents(req *types.OrderEventsReq) (resp *types.OrderEventsResp, err error) {
+	repo := l.svcCtx.OrderRepo
+	if repo == nil {
+		return nil, errOrderRepoUnavailable
+	}
+
+	order, err := repo.GetOrder(l.ctx, req.ClientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := repo.ListOrderEvents(l.ctx, req.ClientOrderID)
+	if err != nil {
+		l.Errorf("Failed to list order events: %v", err)
+		return nil, err
+	}
+
+	resp = &types.OrderEventsResp{
+		ClientOrderID: order.ClientOrderID,
+		Status:        order.Status.String(),
+		Events:        make([]types.OrderEventItem, 0, len(events)),
+	}
+	for _, e := range events {
+		item := types.OrderEventItem{
+			ID:        e.ID,
+			ToStatus:  e.ToStatus.String(),
+			Filled:    e.Filled.String(),
+			Sequence:  e.Sequence,
+			Source:    e.Source,
+			Reason:    e.Reason,
+			CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		}
+		if e.FromStatus != 0 {
+			item.FromStatus = e.FromStatus.String()
+		}
+		if !e.ExchangeTime.IsZero() {
+			item.ExchangeTime = e.ExchangeTime.Format(time.RFC3339)
+		}
+		resp.Events = append(resp.Events, item)
+	}
+	return resp, nil
+}
@@ -0,0 +1,44 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/core/reconcile"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ReconcileRunLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReconcileRunLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReconcileRunLogic {
+	return &ReconcileRunLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ReconcileRun 执行一次对账（对账失败时仍返回报告，交易保持暂停）
+func (l *ReconcileRunLogic) ReconcileRun() (resp *types.ReconcileResponse, err error) {
+	r := l.svcCtx.Reconciler
+	if r == nil {
+		return nil, errReconcilerUnavailable
+	}
+
+	report, err := r.Run(l.ctx, time.Now())
+	if errors.Is(err, reconcile.ErrInProgress) {
+		return nil, err
+	}
+	if err != nil {
+		l.Errorf("Reconcile failed: %v", err)
+	}
+	return toReconcileResponse(l.svcCtx, report), nil
+}
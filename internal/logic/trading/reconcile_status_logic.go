@@ -0,0 +1,68 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/core/reconcile"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var errReconcilerUnavailable = errors.New("reconciler is not initialized, please check trading configuration")
+
+type ReconcileStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReconcileStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReconcileStatusLogic {
+	return &ReconcileStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ReconcileStatusLogic) ReconcileStatus() (resp *types.ReconcileResponse, err error) {
+	r := l.svcCtx.Reconciler
+	if r == nil {
+		return nil, errReconcilerUnavailable
+	}
+	return toReconcileResponse(l.svcCtx, r.Last()), nil
+}
+
+// toReconcileResponse 转换对账状态（尚未对账时报告为空）
+func toReconcileResponse(svcCtx *svc.ServiceContext, report *reconcile.Report) *types.ReconcileResponse {
+	resp := &types.ReconcileResponse{
+		Running: svcCtx.Reconciler.Running(),
+		Blocked: svcCtx.OMSManager != nil && svcCtx.OMSManager.Reconciling(),
+	}
+	if report == nil {
+		return resp
+	}
+
+	info := &types.ReconcileReport{
+		StartedAt:  report.StartedAt.Format(time.RFC3339),
+		FinishedAt: report.FinishedAt.Format(time.RFC3339),
+		Repaired:   report.Repaired(),
+		Unresolved: len(report.Unresolved()),
+		Diffs:      make([]types.ReconcileDiffItem, 0, len(report.Diffs)),
+		Error:      report.Error,
+	}
+	for _, d := range report.Diffs {
+		info.Diffs = append(info.Diffs, types.ReconcileDiffItem{
+			Kind:          string(d.Kind),
+			Symbol:        d.Symbol,
+			ClientOrderID: d.ClientOrderID,
+			Detail:        d.Detail,
+			Repaired:      d.Repaired,
+		})
+	}
+	resp.Report = info
+	return resp
+}
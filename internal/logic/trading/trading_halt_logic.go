@@ -0,0 +1,74 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/svc"
+	"github.com/iluyuns/alpha-trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var errKillSwitchUnavailable = errors.New("kill switch is not initialized, please check trading configuration")
+
+type TradingHaltLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTradingHaltLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TradingHaltLogic {
+	return &TradingHaltLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *TradingHaltLogic) TradingHalt() (resp *types.TradingHaltResponse, err error) {
+	ks := l.svcCtx.KillSwitch
+	if ks == nil {
+		return nil, errKillSwitchUnavailable
+	}
+
+	halt, err := ks.Status(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &types.TradingHaltResponse{
+		Halted:                halt != nil,
+		RequireSecondApproval: l.svcCtx.Config.KillSwitch.RequireSecondApproval,
+	}
+	if halt != nil {
+		info := toTradingHaltInfo(halt)
+		resp.Halt = &info
+	}
+	return resp, nil
+}
+
+// toTradingHaltInfo 转换暂停记录（未发生的时间字段为空）
+func toTradingHaltInfo(h *model.TradingHalt) types.TradingHaltInfo {
+	info := types.TradingHaltInfo{
+		ID:                 h.ID,
+		Status:             h.Status,
+		Reason:             h.Reason,
+		HaltedBy:           h.HaltedBy,
+		HaltedAt:           h.HaltedAt.Format(time.RFC3339),
+		CancelledOrders:    h.CancelledOrders,
+		Flattened:          h.Flattened,
+		FlattenedPositions: h.FlattenedPositions,
+		ReleaseRequestedBy: h.ReleaseRequestedBy,
+		ReleasedBy:         h.ReleasedBy,
+	}
+	if !h.ReleaseRequestedAt.IsZero() {
+		info.ReleaseRequestedAt = h.ReleaseRequestedAt.Format(time.RFC3339)
+	}
+	if !h.ReleasedAt.IsZero() {
+		info.ReleasedAt = h.ReleasedAt.Format(time.RFC3339)
+	}
+	return info
+}
@@ -51,6 +51,24 @@ func (l *TradingStartLogic) TradingStart() (resp *types.TradingStartResponse, er
 		}, nil
 	}
 
+	// 全局交易暂停（Kill Switch）期间禁止启动，需先解除
+	if l.svcCtx.KillSwitch != nil {
+		halt, err := l.svcCtx.KillSwitch.Status(l.ctx)
+		if err != nil {
+			l.Errorf("Failed to load trading halt: %v", err)
+			return &types.TradingStartResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to check trading halt: %v", err),
+			}, nil
+		}
+		if halt != nil {
+			return &types.TradingStartResponse{
+				Success: false,
+				Message: fmt.Sprintf("Trading is halted by kill switch (%s). Release the halt first.", halt.Reason),
+			}, nil
+		}
+	}
+
 	// 检查是否已经启动
 	if l.svcCtx.TradingLoop.IsStarted() {
 		return &types.TradingStartResponse{
@@ -42,6 +42,11 @@ func (l *TradingStatusLogic) TradingStatus() (resp *types.TradingStatusResponse,
 		} else {
 			resp.Message = "Trading loop is stopped"
 		}
+		// 全局交易暂停（Kill Switch）
+		if l.svcCtx.RiskManager != nil && l.svcCtx.RiskManager.Halt() != nil {
+			resp.Halted = true
+			resp.Message = "Trading is halted by kill switch"
+		}
 	} else {
 		resp.Started = false
 		if !config.Trading.Enabled {
@@ -1,12 +1,25 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/iluyuns/alpha-trade/internal/pkg/jwt"
 	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
+var (
+	// ErrSudoTokenMissing 缺少提级认证令牌
+	ErrSudoTokenMissing = errors.New("sudo_token_missing")
+
+	// ErrSudoInvalid 提级认证令牌无效或已过期
+	ErrSudoInvalid = errors.New("sudo_invalid")
+
+	// ErrSudoMismatch 提级认证令牌不属于当前登录用户
+	ErrSudoMismatch = errors.New("sudo_mismatch")
+)
+
 type MFAStepUpMiddleware struct {
 	secret string
 }
@@ -17,33 +30,46 @@ func NewMFAStepUpMiddleware(secret string) *MFAStepUpMiddleware {
 	}
 }
 
+// VerifySudoToken 校验提级认证令牌（供按条件要求提级认证的接口在 logic 层调用）
+func VerifySudoToken(ctx context.Context, secret, sudoToken string) error {
+	if sudoToken == "" {
+		return ErrSudoTokenMissing
+	}
+
+	claims, err := jwt.ParseToken(secret, sudoToken)
+	if err != nil || claims.Scope != jwt.ScopeSudoAuth {
+		return ErrSudoInvalid
+	}
+
+	// 确保 Sudo Token 属于当前登录用户
+	if uid, ok := ctx.Value("uid").(int64); ok {
+		if uid != claims.UserId {
+			return ErrSudoMismatch
+		}
+	}
+	return nil
+}
+
 func (m *MFAStepUpMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// 提级认证检查专有的 Sudo Header: X-Sudo-Token
-		sudoToken := r.Header.Get("X-Sudo-Token")
-		if sudoToken == "" {
+		err := VerifySudoToken(r.Context(), m.secret, r.Header.Get("X-Sudo-Token"))
+		switch {
+		case errors.Is(err, ErrSudoTokenMissing):
 			httpx.WriteJson(w, http.StatusForbidden, map[string]string{
 				"error": "sudo_token_missing",
 				"msg":   "High-risk operation requires sudo token",
 			})
 			return
-		}
-
-		claims, err := jwt.ParseToken(m.secret, sudoToken)
-		if err != nil || claims.Scope != jwt.ScopeSudoAuth {
+		case errors.Is(err, ErrSudoInvalid):
 			httpx.WriteJson(w, http.StatusForbidden, map[string]string{
 				"error": "sudo_invalid",
 				"msg":   "Invalid or expired sudo token",
 			})
 			return
-		}
-
-		// 校验通过，确保 Sudo Token 属于当前登录用户
-		if uid, ok := r.Context().Value("uid").(int64); ok {
-			if uid != claims.UserId {
-				httpx.WriteJson(w, http.StatusForbidden, map[string]string{"error": "sudo_mismatch"})
-				return
-			}
+		case errors.Is(err, ErrSudoMismatch):
+			httpx.WriteJson(w, http.StatusForbidden, map[string]string{"error": "sudo_mismatch"})
+			return
 		}
 
 		next(w, r)
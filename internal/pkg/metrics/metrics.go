@@ -18,6 +18,11 @@ type TradingMetrics struct {
 	RiskChecksBlocked    prometheus.Counter
 	RiskChecksAllowed    prometheus.Counter
 	CircuitBreakerOpened prometheus.Counter
+	RiskRecordsDropped   prometheus.Counter
+	RiskStateConflicts   prometheus.Counter
+	RiskDailyRollovers   prometheus.Counter
+	RiskSnapshotFailures prometheus.Counter
+	RiskHistoryDropped   prometheus.Counter
 
 	// 盈亏指标
 	PnLTotal   prometheus.Gauge
@@ -82,6 +87,26 @@ func NewTradingMetrics() *TradingMetrics {
 			Name: "alpha_trade_circuit_breaker_opened_total",
 			Help: "Total number of times circuit breaker was opened",
 		}),
+		RiskRecordsDropped: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "alpha_trade_risk_records_dropped_total",
+			Help: "Total number of risk decision records dropped (buffer full or write failed)",
+		}),
+		RiskStateConflicts: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "alpha_trade_risk_state_conflicts_total",
+			Help: "Total number of optimistic lock conflicts when updating risk state",
+		}),
+		RiskDailyRollovers: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "alpha_trade_risk_daily_rollovers_total",
+			Help: "Total number of account daily risk statistics rollovers",
+		}),
+		RiskSnapshotFailures: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "alpha_trade_risk_snapshot_failures_total",
+			Help: "Total number of failed daily start equity snapshots",
+		}),
+		RiskHistoryDropped: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "alpha_trade_risk_history_dropped_total",
+			Help: "Total number of risk state history snapshots dropped (buffer full or write failed)",
+		}),
 
 		// 盈亏指标
 		PnLTotal: promauto.NewGauge(prometheus.GaugeOpts{
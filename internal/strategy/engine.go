@@ -2,9 +2,13 @@ package strategy
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/iluyuns/alpha-trade/internal/core/position"
+	riskmgr "github.com/iluyuns/alpha-trade/internal/core/risk"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
 	"github.com/iluyuns/alpha-trade/internal/domain/port"
 )
@@ -39,6 +43,17 @@ type TradeSignal struct {
 	Price    model.Money
 	Quantity model.Money
 	Reason   string
+
+	// 开仓信号可携带止损/止盈价，成交后登记到 StopManager（零表示不设）
+	StopLoss   model.Money
+	TakeProfit model.Money
+
+	// 订单类型（零值为市价单，限价单按 Price 挂出）
+	// 限价单可设置挂单时效，超时由 OMS 撤单或改价，并通过 OrderExpiryHandler 通知策略
+	OrderType    model.OrderType
+	ExpireAt     time.Time          // 截止时间（零值表示不限）
+	MaxAge       time.Duration      // 最长挂单时长（零值表示不限）
+	ExpirePolicy model.ExpirePolicy // 超过最长挂单时长后撤单或改价（零值为撤单）
 }
 
 // Strategy 策略接口
@@ -53,13 +68,58 @@ type Strategy interface {
 	OnTick(ctx context.Context, tick *model.Tick) (*TradeSignal, error)
 }
 
+// OrderUpdateHandler 订单状态变化通知（策略可选实现：提交确认、部分成交、完全成交、撤单与拒单）
+type OrderUpdateHandler interface {
+	OnOrderUpdate(ctx context.Context, order *model.Order)
+}
+
+// FillHandler 成交通知（策略可选实现，按成交明细逐笔通知，用于维护策略内部持仓）
+type FillHandler interface {
+	OnFill(ctx context.Context, trade *model.Trade)
+}
+
+// RiskRejectHandler 风控拒单通知（策略可选实现）
+type RiskRejectHandler interface {
+	OnRiskReject(ctx context.Context, reject *RiskReject)
+}
+
+// RiskReject 未通过事前风控的下单
+type RiskReject struct {
+	Signal        *TradeSignal // 触发下单的信号
+	ClientOrderID string
+	Reason        string
+}
+
+// OrderExpiryHandler 挂单超时通知（策略可选实现）
+type OrderExpiryHandler interface {
+	// OnOrderExpired 挂单超时后被撤销（replacement 为 nil）或按最新价改单（replacement 为新订单）
+	OnOrderExpired(ctx context.Context, expired, replacement *model.Order)
+}
+
+// PositionReader 持仓查询（由 position.Ledger 实现）
+type PositionReader interface {
+	Position(strategyID, symbol string) model.Position
+}
+
 // Engine 策略引擎
 type Engine struct {
 	strategy      Strategy
 	spotGateway   port.SpotGateway
 	futureGateway port.FutureGateway
 	accountID     string
-	oms           OMSInterface // OMS 接口（可选，如果提供则通过 OMS 下单）
+	oms           OMSInterface          // OMS 接口（可选，如果提供则通过 OMS 下单）
+	stopManager   *position.StopManager // 止损管理器（可选）
+	positions     PositionReader        // 持仓查询（可选，止损按净持仓登记；未设置时按收到的成交累计）
+
+	// 止损按成交登记：开仓信号的止损/止盈价在成交后按净持仓登记，加仓与部分平仓后调整数量
+	stopsMu    sync.Mutex
+	stopPrices map[string]stopPrices         // key: Symbol，最近一次开仓信号的止损/止盈价
+	fills      map[string]*model.PositionLot // key: Symbol，未设置 PositionReader 时按成交累计的净持仓
+
+	// 策略回调串行化：K 线与订单生命周期事件不并发进入策略，生命周期事件按到达顺序投递
+	mu      sync.Mutex // 持有期间独占策略
+	queueMu sync.Mutex
+	queue   []func() // 待投递的生命周期事件
 }
 
 // OMSInterface OMS 接口（避免循环依赖）
@@ -77,7 +137,11 @@ type PlaceOrderRequest struct {
 	Quantity      model.Money
 	CurrentPrice  model.Money
 	AccountID     string
+	StrategyID    string
 	ProtectPrice  model.Money
+	ExpireAt      time.Time
+	MaxAge        time.Duration
+	ExpirePolicy  model.ExpirePolicy
 }
 
 // NewEngine 创建策略引擎（直接调用 Gateway，不经过 OMS）
@@ -98,8 +162,34 @@ func NewEngineWithOMS(strategy Strategy, oms OMSInterface, accountID string) *En
 	}
 }
 
+// stopPrices 开仓信号携带的止损/止盈价
+type stopPrices struct {
+	stopLoss   model.Money
+	takeProfit model.Money
+}
+
+// SetStopManager 设置止损管理器（仅 OMS 模式生效，止损随 HandleFill 收到的成交登记与调整）
+func (e *Engine) SetStopManager(stopManager *position.StopManager) {
+	e.stopManager = stopManager
+}
+
+// SetPositionReader 设置持仓查询（止损按持仓账本的净持仓与均价登记）
+// 持仓账本须在回调 HandleFill 前完成记账（position.Ledger 满足）
+func (e *Engine) SetPositionReader(positions PositionReader) {
+	e.positions = positions
+}
+
 // ProcessCandle 处理K线
+// 处理期间到达的生命周期事件（如下单确认、风控拒单）在返回前按顺序投递
 func (e *Engine) ProcessCandle(ctx context.Context, candle *model.Candle) error {
+	e.mu.Lock()
+	err := e.processCandle(ctx, candle)
+	e.unlock()
+	return err
+}
+
+// processCandle 处理K线并执行信号（调用方持有 mu）
+func (e *Engine) processCandle(ctx context.Context, candle *model.Candle) error {
 	signal, err := e.strategy.OnCandle(ctx, candle)
 	if err != nil {
 		return err
@@ -119,32 +209,52 @@ func (e *Engine) executeSignal(ctx context.Context, signal *TradeSignal) error {
 		return nil
 	}
 
+	orderType := signal.OrderType
+	if orderType == 0 {
+		orderType = model.OrderTypeMarket
+	}
+
 	var side model.OrderSide
+	intent := model.OrderIntentEntry
 	if signal.Signal == SignalBuy {
 		side = model.OrderSideBuy
 	} else {
 		side = model.OrderSideSell
+		intent = model.OrderIntentExit
+	}
+
+	clientOrderID, err := model.NewClientOrderID(intent, e.strategy.Name(), signal.Symbol)
+	if err != nil {
+		return fmt.Errorf("generate client order id failed: %w", err)
 	}
 
 	// 如果配置了 OMS，通过 OMS 下单（集成风控）
 	if e.oms != nil {
-		order, err := e.oms.PlaceOrder(ctx, &PlaceOrderRequest{
-			ClientOrderID: generateOrderID(signal.Symbol),
+		// 成交可能在下单返回前到达：先记录止损价，成交后按净持仓登记
+		if signal.Signal == SignalBuy {
+			e.rememberStops(signal)
+		}
+		_, err := e.oms.PlaceOrder(ctx, &PlaceOrderRequest{
+			ClientOrderID: clientOrderID,
 			Symbol:        signal.Symbol,
 			Side:          side,
-			Type:          model.OrderTypeMarket,
+			Type:          orderType,
 			Price:         signal.Price,
 			Quantity:      signal.Quantity,
 			CurrentPrice:  signal.Price, // 使用信号价格作为当前价格
 			AccountID:     e.accountID,
+			StrategyID:    e.strategy.Name(),
+			ExpireAt:      signal.ExpireAt,
+			MaxAge:        signal.MaxAge,
+			ExpirePolicy:  signal.ExpirePolicy,
 		})
 		if err != nil {
-			// 记录错误（可能是风控拒绝或 Gateway 错误）
-			// 错误已在 OMS 和 RiskManager 中记录，这里只返回
+			// 错误已在 OMS 和 RiskManager 中记录，风控拒绝时通知策略
+			if errors.Is(err, riskmgr.ErrOrderRejected) {
+				e.notifyRiskReject(ctx, &RiskReject{Signal: signal, ClientOrderID: clientOrderID, Reason: err.Error()})
+			}
 			return fmt.Errorf("place order via OMS failed: %w", err)
 		}
-		// 订单成功，记录日志（可选，由调用方决定是否记录）
-		_ = order // 避免未使用变量警告
 		return nil
 	}
 
@@ -153,19 +263,194 @@ func (e *Engine) executeSignal(ctx context.Context, signal *TradeSignal) error {
 		return nil // 如果没有 Gateway 也没有 OMS，跳过
 	}
 
-	_, err := e.spotGateway.PlaceOrder(ctx, &port.SpotPlaceOrderRequest{
-		ClientOrderID: generateOrderID(signal.Symbol),
+	order, err := e.spotGateway.PlaceOrder(ctx, &port.SpotPlaceOrderRequest{
+		ClientOrderID: clientOrderID,
 		Symbol:        signal.Symbol,
 		Side:          side,
-		Type:          model.OrderTypeMarket,
+		Type:          orderType,
 		Price:         signal.Price,
 		Quantity:      signal.Quantity,
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	// 不经过 OMS 时没有订单状态回调，按网关返回通知策略（网关返回的订单不带策略归属）
+	update := *order
+	update.StrategyID = e.strategy.Name()
+	e.HandleOrderUpdate(ctx, &update)
+	return nil
+}
+
+// HandleOrderUpdate 转发订单状态变化给下单策略（其他策略的订单忽略）
+func (e *Engine) HandleOrderUpdate(ctx context.Context, order *model.Order) {
+	handler, ok := e.strategy.(OrderUpdateHandler)
+	if !ok || order.StrategyID != e.strategy.Name() {
+		return
+	}
+	e.dispatch(func() { handler.OnOrderUpdate(ctx, order) })
+}
+
+// HandleFill 按成交登记或调整止损，并转发给归属策略（其他策略的成交忽略）
+func (e *Engine) HandleFill(ctx context.Context, strategyID string, trade *model.Trade) {
+	if strategyID != e.strategy.Name() {
+		return
+	}
+	e.syncStops(ctx, trade)
+
+	if handler, ok := e.strategy.(FillHandler); ok {
+		e.dispatch(func() { handler.OnFill(ctx, trade) })
+	}
+}
+
+// notifyRiskReject 通知策略下单被风控拒绝
+func (e *Engine) notifyRiskReject(ctx context.Context, reject *RiskReject) {
+	if handler, ok := e.strategy.(RiskRejectHandler); ok {
+		e.dispatch(func() { handler.OnRiskReject(ctx, reject) })
+	}
+}
+
+// HandleOrderExpired 将挂单超时通知转发给下单策略（其他策略的订单忽略）
+func (e *Engine) HandleOrderExpired(ctx context.Context, expired, replacement *model.Order) {
+	if expired.StrategyID != e.strategy.Name() {
+		return
+	}
+	if handler, ok := e.strategy.(OrderExpiryHandler); ok {
+		e.dispatch(func() { handler.OnOrderExpired(ctx, expired, replacement) })
+	}
+}
+
+// dispatch 生命周期事件入队并尝试投递
+// 事件来自 OMS 同步、持仓账本与 K 线处理等不同 goroutine，入队顺序即投递顺序
+func (e *Engine) dispatch(event func()) {
+	e.queueMu.Lock()
+	e.queue = append(e.queue, event)
+	e.queueMu.Unlock()
+	e.drain()
+}
+
+// drain 策略空闲时投递排队事件；策略被占用时直接返回，由占用者释放前投递
+// 释放后重新检查队列，避免在占用者最后一次投递之后入队的事件滞留
+func (e *Engine) drain() {
+	for e.pending() && e.mu.TryLock() {
+		e.deliver()
+		e.mu.Unlock()
+	}
+}
+
+// unlock 投递占用期间到达的事件后释放策略
+func (e *Engine) unlock() {
+	e.deliver()
+	e.mu.Unlock()
+	e.drain()
+}
+
+// deliver 按入队顺序投递排队事件（调用方持有 mu，投递中入队的事件同样在此投递）
+func (e *Engine) deliver() {
+	for {
+		e.queueMu.Lock()
+		if len(e.queue) == 0 {
+			e.queueMu.Unlock()
+			return
+		}
+		event := e.queue[0]
+		e.queue = e.queue[1:]
+		e.queueMu.Unlock()
+		event()
+	}
+}
+
+// pending 是否有待投递的事件
+func (e *Engine) pending() bool {
+	e.queueMu.Lock()
+	defer e.queueMu.Unlock()
+	return len(e.queue) > 0
+}
+
+// rememberStops 记录开仓信号的止损/止盈价（未设置时保留上一次的价格，用于加仓）
+func (e *Engine) rememberStops(signal *TradeSignal) {
+	if e.stopManager == nil || (signal.StopLoss.IsZero() && signal.TakeProfit.IsZero()) {
+		return
+	}
+
+	e.stopsMu.Lock()
+	defer e.stopsMu.Unlock()
+	if e.stopPrices == nil {
+		e.stopPrices = make(map[string]stopPrices)
+	}
+	e.stopPrices[signal.Symbol] = stopPrices{stopLoss: signal.StopLoss, takeProfit: signal.TakeProfit}
+}
+
+// syncStops 按成交后的净持仓登记、调整或移除止损
+// 已有止损时只调整数量与均价（保留保本/追踪进度）；尚无止损时按开仓信号的止损价登记；持仓归零时移除
+func (e *Engine) syncStops(ctx context.Context, trade *model.Trade) {
+	if e.stopManager == nil {
+		return
+	}
+
+	e.stopsMu.Lock()
+	defer e.stopsMu.Unlock()
+
+	quantity, entryPrice := e.netPosition(trade)
+	positionID := e.positionID(trade.Symbol)
+	if !quantity.IsPositive() {
+		_ = e.stopManager.Remove(ctx, positionID)
+		delete(e.stopPrices, trade.Symbol)
+		return
+	}
+
+	if _, err := e.stopManager.Get(positionID); err == nil {
+		_ = e.stopManager.Resize(ctx, positionID, quantity, entryPrice)
+		return
+	}
+	prices, ok := e.stopPrices[trade.Symbol]
+	if !ok {
+		return
+	}
+	_ = e.stopManager.Track(ctx, model.StopLevel{
+		PositionID: positionID,
+		AccountID:  e.accountID,
+		StrategyID: e.strategy.Name(),
+		Symbol:     trade.Symbol,
+		Side:       model.OrderSideBuy,
+		Quantity:   quantity,
+		EntryPrice: entryPrice,
+		StopLoss:   prices.stopLoss,
+		TakeProfit: prices.takeProfit,
+	})
+}
+
+// netPosition 成交后的净持仓与均价（调用方持有 stopsMu）
+// 优先查询持仓账本；未设置时按收到的成交累计（买入加权平均，卖出只减数量）
+func (e *Engine) netPosition(trade *model.Trade) (model.Money, model.Money) {
+	if e.positions != nil {
+		pos := e.positions.Position(e.strategy.Name(), trade.Symbol)
+		return pos.Quantity, pos.AvgCost
+	}
+
+	if e.fills == nil {
+		e.fills = make(map[string]*model.PositionLot)
+	}
+	lot, ok := e.fills[trade.Symbol]
+	if !ok {
+		lot = &model.PositionLot{Quantity: model.Zero(), Price: model.Zero()}
+		e.fills[trade.Symbol] = lot
+	}
+	if trade.Side == model.OrderSideBuy {
+		total := lot.Quantity.Add(trade.Quantity)
+		lot.Price = lot.Quantity.Mul(lot.Price).Add(trade.Quantity.Mul(trade.Price)).Div(total)
+		lot.Quantity = total
+	} else {
+		lot.Quantity = lot.Quantity.Sub(trade.Quantity)
+	}
+	if !lot.Quantity.IsPositive() {
+		delete(e.fills, trade.Symbol)
+		return model.Zero(), model.Zero()
+	}
+	return lot.Quantity, lot.Price
 }
 
-// generateOrderID 生成订单ID
-func generateOrderID(symbol string) string {
-	return uuid.Must(uuid.NewV7()).String() + "-" + symbol
+// positionID 持仓标识（账户 + 策略 + 交易对）
+func (e *Engine) positionID(symbol string) string {
+	return e.accountID + ":" + e.strategy.Name() + ":" + symbol
 }
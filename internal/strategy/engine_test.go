@@ -0,0 +1,222 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iluyuns/alpha-trade/internal/core/position"
+	riskmgr "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/gateway/mock"
+)
+
+// recordingStrategy 每根 K 线产生一个买入信号并记录收到的回调
+type recordingStrategy struct {
+	events []string
+}
+
+func (s *recordingStrategy) Name() string { return "recorder" }
+
+func (s *recordingStrategy) OnCandle(ctx context.Context, candle *model.Candle) (*TradeSignal, error) {
+	s.events = append(s.events, "candle")
+	return &TradeSignal{Signal: SignalBuy, Symbol: candle.Symbol, Price: candle.Close, Quantity: model.MustMoney("0.01")}, nil
+}
+
+func (s *recordingStrategy) OnTick(ctx context.Context, tick *model.Tick) (*TradeSignal, error) {
+	return nil, nil
+}
+
+func (s *recordingStrategy) OnOrderUpdate(ctx context.Context, order *model.Order) {
+	s.events = append(s.events, "order:"+order.Status.String())
+}
+
+func (s *recordingStrategy) OnFill(ctx context.Context, trade *model.Trade) {
+	s.events = append(s.events, "fill:"+trade.Quantity.String())
+}
+
+func (s *recordingStrategy) OnRiskReject(ctx context.Context, reject *RiskReject) {
+	s.events = append(s.events, "reject:"+reject.Signal.Symbol)
+}
+
+// callbackOMS 模拟 OMS：下单过程中同步回调订单状态（与 OMS 的 OrderUpdateHandler 一致）
+type callbackOMS struct {
+	engine *Engine
+	reject bool
+}
+
+func (o *callbackOMS) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*model.Order, error) {
+	if o.reject {
+		return nil, fmt.Errorf("%w by risk manager: daily drawdown", riskmgr.ErrOrderRejected)
+	}
+	order := &model.Order{
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Quantity:      req.Quantity,
+		StrategyID:    req.StrategyID,
+		Status:        model.OrderStatusSubmitted,
+	}
+	o.engine.HandleOrderUpdate(ctx, order)
+	filled := *order
+	filled.Status, filled.Filled = model.OrderStatusFilled, req.Quantity
+	o.engine.HandleOrderUpdate(ctx, &filled)
+	return &filled, nil
+}
+
+func TestEngine_LifecycleHooks(t *testing.T) {
+	ctx := context.Background()
+	candle := &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney("50000")}
+
+	t.Run("下单回报在 K 线处理后按顺序投递", func(t *testing.T) {
+		strategy := &recordingStrategy{}
+		oms := &callbackOMS{}
+		engine := NewEngineWithOMS(strategy, oms, "acc")
+		oms.engine = engine
+
+		if err := engine.ProcessCandle(ctx, candle); err != nil {
+			t.Fatalf("ProcessCandle failed: %v", err)
+		}
+		engine.HandleFill(ctx, "recorder", &model.Trade{Symbol: "BTCUSDT", Quantity: model.MustMoney("0.01")})
+		engine.HandleFill(ctx, "other", &model.Trade{Symbol: "BTCUSDT", Quantity: model.MustMoney("1")})
+		engine.HandleOrderUpdate(ctx, &model.Order{StrategyID: "other", Status: model.OrderStatusCancelled})
+
+		want := "candle,order:SUBMITTED,order:FILLED,fill:0.01"
+		if got := strings.Join(strategy.events, ","); got != want {
+			t.Errorf("events = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("风控拒单通知策略", func(t *testing.T) {
+		strategy := &recordingStrategy{}
+		engine := NewEngineWithOMS(strategy, &callbackOMS{reject: true}, "acc")
+
+		if err := engine.ProcessCandle(ctx, candle); err == nil {
+			t.Fatal("expected risk rejection error")
+		}
+		if got := strings.Join(strategy.events, ","); got != "candle,reject:BTCUSDT" {
+			t.Errorf("events = %s", got)
+		}
+	})
+
+	t.Run("直连网关按返回订单通知策略", func(t *testing.T) {
+		exchange := mock.NewSpotExchange(map[string]model.Money{"USDT": model.MustMoney("10000")})
+		exchange.SetPrice("BTCUSDT", model.MustMoney("50000"))
+		strategy := &recordingStrategy{}
+		engine := NewEngine(strategy, exchange, "acc")
+
+		if err := engine.ProcessCandle(ctx, candle); err != nil {
+			t.Fatalf("ProcessCandle failed: %v", err)
+		}
+		if got := strings.Join(strategy.events, ","); got != "candle,order:FILLED" {
+			t.Errorf("events = %s", got)
+		}
+	})
+}
+
+// stopStrategy 每根 K 线产生一个携带止损价的买入信号
+type stopStrategy struct{}
+
+func (s *stopStrategy) Name() string { return "stopper" }
+
+func (s *stopStrategy) OnCandle(ctx context.Context, candle *model.Candle) (*TradeSignal, error) {
+	return &TradeSignal{
+		Signal:     SignalBuy,
+		Symbol:     candle.Symbol,
+		Price:      candle.Close,
+		Quantity:   model.MustMoney("0.01"),
+		StopLoss:   model.MustMoney("49000"),
+		TakeProfit: model.MustMoney("52000"),
+	}, nil
+}
+
+func (s *stopStrategy) OnTick(ctx context.Context, tick *model.Tick) (*TradeSignal, error) {
+	return nil, nil
+}
+
+// acceptingOMS 模拟 OMS：下单后仅确认提交，成交由测试经 HandleFill 推送
+type acceptingOMS struct{}
+
+func (o *acceptingOMS) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*model.Order, error) {
+	return &model.Order{ClientOrderID: req.ClientOrderID, Symbol: req.Symbol, Side: req.Side, Quantity: req.Quantity, Status: model.OrderStatusSubmitted}, nil
+}
+
+// ledgerPositions 持仓账本桩（按交易对返回持仓）
+type ledgerPositions map[string]model.Position
+
+func (p ledgerPositions) Position(strategyID, symbol string) model.Position {
+	return p[symbol]
+}
+
+func TestEngine_StopsFollowFills(t *testing.T) {
+	ctx := context.Background()
+	candle := &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney("50000")}
+	positionID := "acc:stopper:BTCUSDT"
+	trade := func(side model.OrderSide, qty, price string) *model.Trade {
+		return &model.Trade{Symbol: "BTCUSDT", Side: side, Quantity: model.MustMoney(qty), Price: model.MustMoney(price)}
+	}
+
+	t.Run("按成交累计的净持仓登记与调整", func(t *testing.T) {
+		stops := position.NewStopManager(nil, nil, position.StopConfig{})
+		engine := NewEngineWithOMS(&stopStrategy{}, &acceptingOMS{}, "acc")
+		engine.SetStopManager(stops)
+
+		if err := engine.ProcessCandle(ctx, candle); err != nil {
+			t.Fatalf("ProcessCandle failed: %v", err)
+		}
+		if _, err := stops.Get(positionID); err == nil {
+			t.Fatal("stop registered before any fill")
+		}
+
+		// 部分成交按成交数量登记，加仓按净持仓调整
+		engine.HandleFill(ctx, "stopper", trade(model.OrderSideBuy, "0.004", "50000"))
+		level, err := stops.Get(positionID)
+		if err != nil || !level.Quantity.EQ(model.MustMoney("0.004")) || !level.StopLoss.EQ(model.MustMoney("49000")) {
+			t.Fatalf("stop after partial fill = %+v, %v", level, err)
+		}
+		engine.HandleFill(ctx, "stopper", trade(model.OrderSideBuy, "0.006", "51000"))
+		level, _ = stops.Get(positionID)
+		if !level.Quantity.EQ(model.MustMoney("0.01")) || !level.EntryPrice.EQ(model.MustMoney("50600")) {
+			t.Errorf("stop after scale-in = %s @ %s, want 0.01 @ 50600", level.Quantity, level.EntryPrice)
+		}
+
+		// 部分平仓缩减数量，全部平仓后移除
+		engine.HandleFill(ctx, "stopper", trade(model.OrderSideSell, "0.004", "50500"))
+		level, _ = stops.Get(positionID)
+		if !level.Quantity.EQ(model.MustMoney("0.006")) {
+			t.Errorf("stop after partial exit = %s, want 0.006", level.Quantity)
+		}
+		engine.HandleFill(ctx, "stopper", trade(model.OrderSideSell, "0.006", "50500"))
+		if _, err := stops.Get(positionID); !errors.Is(err, position.ErrStopNotFound) {
+			t.Errorf("stop should be removed when flat, got %v", err)
+		}
+	})
+
+	t.Run("按持仓账本的净持仓登记", func(t *testing.T) {
+		stops := position.NewStopManager(nil, nil, position.StopConfig{})
+		positions := ledgerPositions{}
+		engine := NewEngineWithOMS(&stopStrategy{}, &acceptingOMS{}, "acc")
+		engine.SetStopManager(stops)
+		engine.SetPositionReader(positions)
+
+		if err := engine.ProcessCandle(ctx, candle); err != nil {
+			t.Fatalf("ProcessCandle failed: %v", err)
+		}
+		// 账本扣除基础资产手续费后的净持仓
+		positions["BTCUSDT"] = model.Position{Quantity: model.MustMoney("0.00999"), AvgCost: model.MustMoney("50050")}
+		engine.HandleFill(ctx, "stopper", trade(model.OrderSideBuy, "0.01", "50000"))
+
+		level, err := stops.Get(positionID)
+		if err != nil || !level.Quantity.EQ(model.MustMoney("0.00999")) || !level.EntryPrice.EQ(model.MustMoney("50050")) {
+			t.Errorf("stop = %+v, %v; want ledger position 0.00999 @ 50050", level, err)
+		}
+
+		// 其他策略的成交不影响止损
+		engine.HandleFill(ctx, "other", trade(model.OrderSideSell, "0.01", "50000"))
+		if _, err := stops.Get(positionID); err != nil {
+			t.Errorf("other strategy fill removed the stop: %v", err)
+		}
+	})
+}
@@ -4,16 +4,30 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/iluyuns/alpha-trade/internal/core/sizing"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
 )
 
+// defaultQuantity 未配置仓位计算器时的固定下单数量
+var defaultQuantity = model.MustMoney("0.01")
+
 // SimpleVolatility 简单波动策略
 // 逻辑：价格波动超过阈值时触发交易
+// 持仓按成交回报（OnFill）累计，有在途订单时不产生新信号
 type SimpleVolatility struct {
 	symbol           string
 	threshold        model.Money // 波动阈值（百分比）
 	lastPrice        model.Money
-	positionQuantity model.Money
+	positionQuantity model.Money         // 按成交累计的持仓
+	working          map[string]struct{} // 在途订单（key: ClientOrderID）
+
+	// 持仓来源（可选，设置后以持仓账本的成交持仓为准，替代本地计数）
+	positions PositionReader
+
+	// 仓位计算（可选）
+	sizer     *sizing.Sizer
+	accountID string
+	atr       *sizing.ATR
 }
 
 // NewSimpleVolatility 创建简单波动策略
@@ -23,6 +37,52 @@ func NewSimpleVolatility(symbol string, threshold model.Money) *SimpleVolatility
 		threshold:        threshold,
 		lastPrice:        model.Zero(),
 		positionQuantity: model.Zero(),
+		working:          make(map[string]struct{}),
+	}
+}
+
+// SetSizer 启用波动率仓位计算（替代固定数量）
+// atrPeriod: ATR 周期（<= 0 时默认 14）
+func (s *SimpleVolatility) SetSizer(sizer *sizing.Sizer, accountID string, atrPeriod int) {
+	s.sizer = sizer
+	s.accountID = accountID
+	s.atr = sizing.NewATR(atrPeriod)
+}
+
+// SetPositionReader 从持仓账本读取本策略的持仓（按成交记账，下单未成交时不计入）
+func (s *SimpleVolatility) SetPositionReader(positions PositionReader) {
+	s.positions = positions
+}
+
+// position 当前持仓数量
+func (s *SimpleVolatility) position() model.Money {
+	if s.positions != nil {
+		return s.positions.Position(s.Name(), s.symbol).Quantity
+	}
+	return s.positionQuantity
+}
+
+// OnOrderUpdate 跟踪在途订单（实现 OrderUpdateHandler）
+func (s *SimpleVolatility) OnOrderUpdate(ctx context.Context, order *model.Order) {
+	if order.Symbol != s.symbol {
+		return
+	}
+	if order.IsActive() {
+		s.working[order.ClientOrderID] = struct{}{}
+	} else {
+		delete(s.working, order.ClientOrderID)
+	}
+}
+
+// OnFill 按成交更新持仓（实现 FillHandler）
+func (s *SimpleVolatility) OnFill(ctx context.Context, trade *model.Trade) {
+	if trade.Symbol != s.symbol {
+		return
+	}
+	if trade.Side == model.OrderSideBuy {
+		s.positionQuantity = s.positionQuantity.Add(trade.Quantity)
+	} else {
+		s.positionQuantity = s.positionQuantity.Sub(trade.Quantity)
 	}
 }
 
@@ -40,6 +100,11 @@ func (s *SimpleVolatility) OnCandle(ctx context.Context, candle *model.Candle) (
 
 	currentPrice := candle.Close
 
+	// 更新 ATR（仅在启用仓位计算时）
+	if s.atr != nil {
+		s.atr.Update(candle)
+	}
+
 	// 初始化：记录首个价格
 	if s.lastPrice.IsZero() {
 		s.lastPrice = currentPrice
@@ -53,18 +118,19 @@ func (s *SimpleVolatility) OnCandle(ctx context.Context, candle *model.Candle) (
 	// 更新最后价格
 	defer func() { s.lastPrice = currentPrice }()
 
-	// 检查是否超过阈值
-	if changePercent.LT(s.threshold) {
+	// 检查是否超过阈值（有在途订单时等待其完结）
+	if changePercent.LT(s.threshold) || len(s.working) > 0 {
 		return nil, nil
 	}
 
 	// 生成信号
 	var signal Signal
 	var reason string
+	position := s.position()
 
 	if priceDiff.IsPositive() {
 		// 价格上涨超过阈值 -> 买入
-		if s.positionQuantity.IsZero() {
+		if position.IsZero() {
 			signal = SignalBuy
 			reason = fmt.Sprintf("Price up %.2f%% (threshold: %.2f%%)",
 				changePercent.Float64()*100, s.threshold.Float64()*100)
@@ -73,7 +139,7 @@ func (s *SimpleVolatility) OnCandle(ctx context.Context, candle *model.Candle) (
 		}
 	} else {
 		// 价格下跌超过阈值 -> 卖出
-		if s.positionQuantity.IsPositive() {
+		if position.IsPositive() {
 			signal = SignalSell
 			reason = fmt.Sprintf("Price down %.2f%% (threshold: %.2f%%)",
 				changePercent.Float64()*100, s.threshold.Float64()*100)
@@ -82,12 +148,17 @@ func (s *SimpleVolatility) OnCandle(ctx context.Context, candle *model.Candle) (
 		}
 	}
 
-	// 更新仓位（简化：固定数量）
-	quantity := model.MustMoney("0.01")
+	// 计算下单数量：买入按仓位计算器，卖出平掉全部持仓
+	quantity := position
 	if signal == SignalBuy {
-		s.positionQuantity = s.positionQuantity.Add(quantity)
-	} else if signal == SignalSell {
-		s.positionQuantity = s.positionQuantity.Sub(quantity)
+		qty, err := s.entryQuantity(ctx, currentPrice)
+		if err != nil {
+			return nil, err
+		}
+		if !qty.IsPositive() {
+			return nil, nil // 风险预算不足一个最小下单单位
+		}
+		quantity = qty
 	}
 
 	return &TradeSignal{
@@ -99,6 +170,28 @@ func (s *SimpleVolatility) OnCandle(ctx context.Context, candle *model.Candle) (
 	}, nil
 }
 
+// entryQuantity 开仓数量（未配置仓位计算器时使用固定数量）
+func (s *SimpleVolatility) entryQuantity(ctx context.Context, price model.Money) (model.Money, error) {
+	if s.sizer == nil {
+		return defaultQuantity, nil
+	}
+
+	if !s.atr.Ready() {
+		return model.Zero(), nil // ATR 预热中，不开仓
+	}
+
+	qty, err := s.sizer.Size(ctx, s.Name(), &sizing.Request{
+		AccountID: s.accountID,
+		Symbol:    s.symbol,
+		Price:     price,
+		ATR:       s.atr.Value(),
+	})
+	if err != nil {
+		return model.Zero(), fmt.Errorf("size position failed: %w", err)
+	}
+	return qty, nil
+}
+
 // OnTick Tick事件处理
 func (s *SimpleVolatility) OnTick(ctx context.Context, tick *model.Tick) (*TradeSignal, error) {
 	// 此策略不处理Tick
@@ -5,9 +5,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/iluyuns/alpha-trade/internal/core/sizing"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
+	"github.com/iluyuns/alpha-trade/internal/infra/risk"
 )
 
+// fillSignal 模拟信号对应的订单全部成交
+func fillSignal(ctx context.Context, s *SimpleVolatility, signal *TradeSignal) {
+	side := model.OrderSideBuy
+	if signal.Signal == SignalSell {
+		side = model.OrderSideSell
+	}
+	s.OnFill(ctx, &model.Trade{Symbol: signal.Symbol, Side: side, Price: signal.Price, Quantity: signal.Quantity})
+}
+
 func TestSimpleVolatility_OnCandle(t *testing.T) {
 	ctx := context.Background()
 	strategy := NewSimpleVolatility("BTCUSDT", model.MustMoney("0.02")) // 2% threshold
@@ -45,7 +56,7 @@ func TestSimpleVolatility_OnCandle(t *testing.T) {
 			name: "下跌超过阈值且有持仓产生卖出信号",
 			candles: []*model.Candle{
 				{Symbol: "BTCUSDT", Close: model.MustMoney("50000"), OpenTime: time.Now()},
-				{Symbol: "BTCUSDT", Close: model.MustMoney("51100"), OpenTime: time.Now()}, // Buy
+				{Symbol: "BTCUSDT", Close: model.MustMoney("51100"), OpenTime: time.Now()}, // Buy（成交）
 				{Symbol: "BTCUSDT", Close: model.MustMoney("50000"), OpenTime: time.Now()}, // 下跌 2.15% > 2%
 			},
 			wantSignal: SignalSell,
@@ -66,6 +77,7 @@ func TestSimpleVolatility_OnCandle(t *testing.T) {
 				}
 				if signal != nil {
 					lastSignal = signal
+					fillSignal(ctx, strategy, signal)
 				}
 			}
 
@@ -88,3 +100,134 @@ func TestSimpleVolatility_OnCandle(t *testing.T) {
 		})
 	}
 }
+
+func TestSimpleVolatility_WithSizer(t *testing.T) {
+	ctx := context.Background()
+	riskRepo := risk.NewMemoryRiskRepo()
+	_ = riskRepo.SaveState(ctx, model.NewRiskState("sizer-account", model.MustMoney("10000")))
+
+	sizer := sizing.NewSizer(riskRepo, sizing.Config{
+		Mode:         sizing.ModeVolatility,
+		RiskPerTrade: 0.01,
+		ATRMultiple:  2,
+		LotSize:      model.MustMoney("0.0001"),
+	})
+
+	strategy := NewSimpleVolatility("BTCUSDT", model.MustMoney("0.02"))
+	strategy.SetSizer(sizer, "sizer-account", 2)
+
+	candle := func(high, low, close string) *model.Candle {
+		return &model.Candle{
+			Symbol:   "BTCUSDT",
+			High:     model.MustMoney(high),
+			Low:      model.MustMoney(low),
+			Close:    model.MustMoney(close),
+			OpenTime: time.Now(),
+		}
+	}
+
+	// 两根 K 线完成 ATR 预热：TR = 500, 500 -> ATR = 500
+	for _, c := range []*model.Candle{
+		candle("50250", "49750", "50000"),
+		candle("50250", "49750", "50000"),
+	} {
+		if signal, err := strategy.OnCandle(ctx, c); err != nil || signal != nil {
+			t.Fatalf("unexpected signal during warmup: %v, %v", signal, err)
+		}
+	}
+
+	// 上涨 3% 触发买入：TR = 1500 -> ATR = (500 + 1500) / 2 = 1000
+	// 数量 = 10000 * 1% / (1000 * 2) = 0.05
+	signal, err := strategy.OnCandle(ctx, candle("51500", "50000", "51500"))
+	if err != nil {
+		t.Fatalf("OnCandle failed: %v", err)
+	}
+	if signal == nil || signal.Signal != SignalBuy {
+		t.Fatalf("expected buy signal, got %v", signal)
+	}
+	if !signal.Quantity.EQ(model.MustMoney("0.05")) {
+		t.Errorf("quantity = %s, want 0.05", signal.Quantity)
+	}
+	fillSignal(ctx, strategy, signal)
+
+	// 下跌卖出：平掉全部持仓
+	signal, err = strategy.OnCandle(ctx, candle("51500", "50000", "50000"))
+	if err != nil {
+		t.Fatalf("OnCandle failed: %v", err)
+	}
+	if signal == nil || signal.Signal != SignalSell {
+		t.Fatalf("expected sell signal, got %v", signal)
+	}
+	if !signal.Quantity.EQ(model.MustMoney("0.05")) {
+		t.Errorf("sell quantity = %s, want 0.05", signal.Quantity)
+	}
+}
+
+type fixedPositions map[string]model.Money
+
+func (p fixedPositions) Position(strategyID, symbol string) model.Position {
+	quantity, ok := p[strategyID+"|"+symbol]
+	if !ok {
+		quantity = model.Zero()
+	}
+	return model.Position{StrategyID: strategyID, Symbol: symbol, Quantity: quantity}
+}
+
+func TestSimpleVolatility_PositionReader(t *testing.T) {
+	ctx := context.Background()
+	positions := fixedPositions{}
+	strategy := NewSimpleVolatility("BTCUSDT", model.MustMoney("0.02"))
+	strategy.SetPositionReader(positions)
+
+	_, _ = strategy.OnCandle(ctx, &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney("50000")})
+	signal, _ := strategy.OnCandle(ctx, &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney("51100")})
+	if signal == nil || signal.Signal != SignalBuy {
+		t.Fatalf("expected buy signal, got %+v", signal)
+	}
+
+	// 买单尚未成交：账本无持仓，下跌不产生卖出信号
+	if signal, _ := strategy.OnCandle(ctx, &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney("50000")}); signal != nil {
+		t.Fatalf("no position in ledger, expected no signal, got %+v", signal)
+	}
+
+	// 账本记入成交后按实际成交数量平仓
+	positions["SimpleVolatility|BTCUSDT"] = model.MustMoney("0.008")
+	_, _ = strategy.OnCandle(ctx, &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney("51100")})
+	signal, _ = strategy.OnCandle(ctx, &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney("50000")})
+	if signal == nil || signal.Signal != SignalSell || !signal.Quantity.EQ(model.MustMoney("0.008")) {
+		t.Fatalf("expected sell of ledger quantity, got %+v", signal)
+	}
+}
+
+func TestSimpleVolatility_PositionFromFills(t *testing.T) {
+	ctx := context.Background()
+	strategy := NewSimpleVolatility("BTCUSDT", model.MustMoney("0.02"))
+	candle := func(close string) *model.Candle {
+		return &model.Candle{Symbol: "BTCUSDT", Close: model.MustMoney(close)}
+	}
+
+	_, _ = strategy.OnCandle(ctx, candle("50000"))
+	signal, _ := strategy.OnCandle(ctx, candle("51100"))
+	if signal == nil || signal.Signal != SignalBuy {
+		t.Fatalf("expected buy signal, got %+v", signal)
+	}
+
+	// 买单挂出未成交：有在途订单，不产生新信号
+	order := &model.Order{ClientOrderID: "buy-1", Symbol: "BTCUSDT", Side: model.OrderSideBuy, Status: model.OrderStatusSubmitted}
+	strategy.OnOrderUpdate(ctx, order)
+	if signal, _ := strategy.OnCandle(ctx, candle("50000")); signal != nil {
+		t.Fatalf("working order pending, expected no signal, got %+v", signal)
+	}
+
+	// 部分成交后撤单：按实际成交数量持仓
+	strategy.OnFill(ctx, &model.Trade{Symbol: "BTCUSDT", Side: model.OrderSideBuy, Quantity: model.MustMoney("0.004")})
+	strategy.OnFill(ctx, &model.Trade{Symbol: "ETHUSDT", Side: model.OrderSideBuy, Quantity: model.MustMoney("1")})
+	order.Status = model.OrderStatusCancelled
+	strategy.OnOrderUpdate(ctx, order)
+
+	_, _ = strategy.OnCandle(ctx, candle("51100"))
+	signal, _ = strategy.OnCandle(ctx, candle("50000"))
+	if signal == nil || signal.Signal != SignalSell || !signal.Quantity.EQ(model.MustMoney("0.004")) {
+		t.Fatalf("expected sell of filled quantity, got %+v", signal)
+	}
+}
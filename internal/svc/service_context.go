@@ -11,9 +11,25 @@ import (
 	"github.com/iluyuns/alpha-trade/internal/gateway/binance"
 	"github.com/iluyuns/alpha-trade/internal/domain/port"
 	orderrepo "github.com/iluyuns/alpha-trade/internal/infra/order"
+	orderlistrepo "github.com/iluyuns/alpha-trade/internal/infra/orderlist"
+	execrepo "github.com/iluyuns/alpha-trade/internal/infra/execution"
+	assetsnapshotrepo "github.com/iluyuns/alpha-trade/internal/infra/assetsnapshot"
 	riskrepo "github.com/iluyuns/alpha-trade/internal/infra/risk"
+	risklimitrepo "github.com/iluyuns/alpha-trade/internal/infra/risklimit"
+	riskhistoryrepo "github.com/iluyuns/alpha-trade/internal/infra/riskhistory"
+	riskrecordrepo "github.com/iluyuns/alpha-trade/internal/infra/riskrecord"
+	riskrulerepo "github.com/iluyuns/alpha-trade/internal/infra/riskrule"
+	settlementrepo "github.com/iluyuns/alpha-trade/internal/infra/settlement"
+	stoprepo "github.com/iluyuns/alpha-trade/internal/infra/stop"
+	tradinghaltrepo "github.com/iluyuns/alpha-trade/internal/infra/tradinghalt"
+	traderepo "github.com/iluyuns/alpha-trade/internal/infra/trade"
+	"github.com/iluyuns/alpha-trade/internal/core/execution"
+	"github.com/iluyuns/alpha-trade/internal/core/killswitch"
 	"github.com/iluyuns/alpha-trade/internal/core/oms"
+	"github.com/iluyuns/alpha-trade/internal/core/position"
+	"github.com/iluyuns/alpha-trade/internal/core/reconcile"
 	risklogic "github.com/iluyuns/alpha-trade/internal/core/risk"
+	"github.com/iluyuns/alpha-trade/internal/core/sizing"
 	"github.com/iluyuns/alpha-trade/internal/middleware"
 	"github.com/iluyuns/alpha-trade/internal/pkg/email"
 	"github.com/iluyuns/alpha-trade/internal/pkg/revocation"
@@ -46,10 +62,22 @@ type ServiceContext struct {
 	BinanceWSClient   *binance.WSClient
 	OrderRepo         port.OrderRepo
 	RiskRepo          port.RiskRepo
+	RiskRecords       port.RiskRecordRepo
+	RiskRecorder      *risklogic.Recorder
+	RiskHistory       port.RiskHistoryRepo
+	HistoryRecorder   *risklogic.HistoryRecorder
 	RiskManager       *risklogic.Manager
+	SessionScheduler  *risklogic.SessionScheduler
+	Performance       *risklogic.PerformanceTracker
+	Sizer             *sizing.Sizer
 	OMSManager        *oms.Manager
+	StopManager       *position.StopManager
+	Ledger            *position.Ledger
+	Executor          *execution.Executor
 	StrategyEngine    *strategy.Engine
 	TradingLoop       *TradingLoop
+	KillSwitch        *killswitch.KillSwitch
+	Reconciler        *reconcile.Reconciler
 }
 
 func (sc *ServiceContext) Close() error {
@@ -72,6 +100,14 @@ func (sc *ServiceContext) Close() error {
 		}
 	}
 
+	// 写入缓冲中的风控决策记录与状态历史
+	if sc.RiskRecorder != nil {
+		sc.RiskRecorder.Flush(context.Background())
+	}
+	if sc.HistoryRecorder != nil {
+		sc.HistoryRecorder.Flush(context.Background())
+	}
+
 	// 关闭数据库连接
 	if sc.DB != nil {
 		if err := sc.DB.Close(); err != nil {
@@ -155,43 +191,100 @@ func initTradingComponents(ctx *ServiceContext, c config.Config) error {
 	// 2. 初始化 OrderRepo
 	ctx.OrderRepo = orderrepo.NewPostgresRepo(ctx.DB)
 
-	// 3. 初始化 RiskRepo（根据配置选择 Redis 或 Postgres）
-	var riskRepo port.RiskRepo
-	if strings.ToLower(c.Risk.RepoType) == "redis" {
-		// 解析 Redis URL
-		opt, err := redis.ParseURL(c.Redis.URL)
-		if err != nil {
-			return fmt.Errorf("parse redis URL: %w", err)
-		}
-		redisClient := redis.NewClient(opt)
-		riskRepo = riskrepo.NewRedisRepo(redisClient)
-		logx.Infof("Using Redis for risk state storage: %s", c.Redis.URL)
-	} else {
-		riskRepo = riskrepo.NewPostgresRepo(ctx.DB)
-		logx.Infof("Using PostgreSQL for risk state storage")
+	// 3-4. 初始化 RiskRepo 与 RiskManager（限额、规则集与交易日定义）
+	riskManager, riskRepo, err := NewRiskManager(ctx.DB, c)
+	if err != nil {
+		return err
 	}
 	ctx.RiskRepo = riskRepo
+	ctx.RiskManager = riskManager
+
+	// 风控决策记录（异步批量写入 risk_records）
+	ctx.RiskRecords = riskrecordrepo.NewPostgresRepo(ctx.DB)
+	if c.Risk.Records.Enabled {
+		ctx.RiskRecorder = risklogic.NewRecorder(ctx.RiskRecords, risklogic.RecorderConfig{
+			BufferSize:      c.Risk.Records.BufferSize,
+			BatchSize:       c.Risk.Records.BatchSize,
+			FlushInterval:   time.Duration(c.Risk.Records.FlushIntervalMs) * time.Millisecond,
+			AllowSampleRate: c.Risk.Records.AllowSampleRate,
+		})
+		ctx.RiskRecorder.Start(context.Background())
+		ctx.RiskManager.SetRecorder(ctx.RiskRecorder)
+	}
 
-	// 4. 初始化 RiskManager
-	riskConfig := risklogic.RiskConfig{
-		MaxConsecutiveLosses:       c.Risk.MaxConsecutiveLosses,
-		MaxDailyDrawdown:           c.Risk.MaxDailyDrawdown,
-		MaxTotalMDD:                c.Risk.MaxTotalMDD,
-		MaxSinglePositionPercent:   c.Risk.MaxSinglePositionPercent,
-		MaxTotalExposurePercent:    c.Risk.MaxTotalExposurePercent,
-		MinCashReservePercent:      c.Risk.MinCashReservePercent,
-		MaxLeverage:                c.Risk.MaxLeverage,
+	// 风控状态历史（状态变更与定时快照写入 risk_state_history）
+	ctx.RiskHistory = riskhistoryrepo.NewPostgresRepo(ctx.DB)
+	if c.Risk.History.Enabled {
+		day := 24 * time.Hour
+		ctx.HistoryRecorder = risklogic.NewHistoryRecorder(ctx.RiskHistory, risklogic.HistoryConfig{
+			BufferSize:        c.Risk.History.BufferSize,
+			BatchSize:         c.Risk.History.BatchSize,
+			FlushInterval:     time.Duration(c.Risk.History.FlushIntervalMs) * time.Millisecond,
+			PeriodicRetention: time.Duration(c.Risk.History.PeriodicRetentionDays) * day,
+			EventRetention:    time.Duration(c.Risk.History.EventRetentionDays) * day,
+		})
+		ctx.HistoryRecorder.Start(context.Background())
+		ctx.RiskManager.SetHistoryRecorder(ctx.HistoryRecorder)
+		if c.Risk.History.SnapshotIntervalSec > 0 {
+			ctx.RiskManager.StartHistorySnapshots(context.Background(), time.Duration(c.Risk.History.SnapshotIntervalSec)*time.Second)
+		}
+	}
+
+	// 交易日开始净值写入 asset_snapshots
+	ctx.RiskManager.SetSnapshotRepo(assetsnapshotrepo.NewPostgresRepo(ctx.DB))
+	ctx.SessionScheduler = risklogic.NewSessionScheduler(ctx.RiskManager, time.Duration(c.Risk.SessionCheckSeconds)*time.Second)
+	ctx.SessionScheduler.OnError(func(accountID string, err error) {
+		logx.Errorf("Failed to roll daily risk state for account %s: %v", accountID, err)
+	})
+	ctx.SessionScheduler.Start(context.Background())
+
+	// 策略绩效降级（从结算表加载最近交易，定时刷新）
+	if c.Risk.Performance.Enabled {
+		ctx.Performance = NewPerformanceTracker(ctx.DB, c.Risk.Performance)
+		if err := ctx.Performance.Load(context.Background()); err != nil {
+			logx.Errorf("Failed to load strategy performance: %v", err)
+		}
+		ctx.Performance.Start(context.Background(), time.Minute)
+		ctx.RiskManager.SetPerformanceTracker(ctx.Performance)
 	}
-	ctx.RiskManager = risklogic.NewManager(riskRepo, riskConfig)
+
+	accountID := "default-account" // 默认账户ID，后续可从配置读取
 
 	// 5. 初始化 OMS Manager
 	omsConfig := oms.Config{
-		SyncInterval: 5 * time.Second,
-		AutoSync:     true,
+		SyncInterval:  5 * time.Second,
+		AutoSync:      true,
+		AccountID:     accountID,
+		SubmitTimeout: time.Duration(c.OMS.SubmitTimeoutSeconds) * time.Second,
+		ResolveGrace:  time.Duration(c.OMS.ResolveGraceSeconds) * time.Second,
 	}
 	ctx.OMSManager = oms.NewManager(spotClient, ctx.OrderRepo, ctx.RiskManager, omsConfig)
+	// 解析崩溃前写入但未确认的下单意图（宽限期内查无此单或查询失败的保留，由自动同步按意图超时继续处理）
+	resolved, err := ctx.OMSManager.ResolvePendingOrders(context.Background())
+	if err != nil {
+		logx.Errorf("Failed to resolve pending order intents: %v", err)
+	}
+	if resolved > 0 {
+		logx.Infof("Resolved %d pending order intents", resolved)
+	}
+	// 启用 OCO 与括号单（入场成交、止盈成交由自动同步推进，本地模拟的止损由 TradingLoop 按行情触发）
+	ctx.OMSManager.SetOrderListRepo(orderlistrepo.NewPostgresRepo(ctx.DB))
+
+	// 初始化持仓账本（注册策略后按 executions 重放重建，定时拉取交易所成交；平仓写入结算并计入风控盈亏）
+	ctx.Ledger = newLedger(ctx, c, accountID)
+	// 超时挂单按账本的最新标记价格改价
+	ctx.OMSManager.SetPriceSource(ctx.Ledger)
+
+	// 6. 初始化仓位计算器（可选）
+	if c.Sizing.Enabled {
+		sizer, err := newSizer(riskRepo, c)
+		if err != nil {
+			return fmt.Errorf("init sizer: %w", err)
+		}
+		ctx.Sizer = sizer
+	}
 
-	// 6. 初始化 Strategy Engine
+	// 7. 初始化 Strategy Engine
 	// 默认使用 SimpleVolatility 策略
 	var strategyInstance strategy.Strategy
 	if c.Trading.StrategyType == "simple_volatility" || c.Trading.StrategyType == "" {
@@ -205,19 +298,86 @@ func initTradingComponents(ctx *ServiceContext, c config.Config) error {
 		if len(c.Trading.Symbols) > 0 {
 			symbol = c.Trading.Symbols[0]
 		}
-		strategyInstance = strategy.NewSimpleVolatility(symbol, threshold)
+		sv := strategy.NewSimpleVolatility(symbol, threshold)
+		sv.SetPositionReader(ctx.Ledger)
+		if ctx.Sizer != nil {
+			sv.SetSizer(ctx.Sizer, accountID, c.Sizing.ATRPeriod)
+		}
+		strategyInstance = sv
 	} else {
 		return fmt.Errorf("unsupported strategy type: %s", c.Trading.StrategyType)
 	}
 
+	// 注册策略编码，使本地无订单的成交（如重启前的订单）可按客户端订单 ID 还原下单策略
+	strategies := model.NewStrategyRegistry()
+	if _, err := strategies.Register(strategyInstance.Name()); err != nil {
+		return fmt.Errorf("register strategy: %w", err)
+	}
+	ctx.Ledger.SetStrategyRegistry(strategies)
+
 	// 创建策略引擎（通过 OMS 下单，集成风控）
 	// 使用适配器将 OMS Manager 适配到 Strategy Engine 接口
 	omsAdapter := oms.NewStrategyOMSAdapter(ctx.OMSManager)
-	accountID := "default-account" // 默认账户ID，后续可从配置读取
 	ctx.StrategyEngine = strategy.NewEngineWithOMS(strategyInstance, omsAdapter, accountID)
+	// 挂单超时撤单或改价后通知下单策略
+	ctx.OMSManager.SetExpiryHandler(oms.StrategyExpiryHandler(ctx.StrategyEngine))
+	// 成交回调下单策略
+	ctx.Ledger.SetFillHandler(ctx.StrategyEngine.HandleFill)
+	// 策略已注册：重放成交时可按客户端订单 ID 还原归属
+	if err := ctx.Ledger.Load(context.Background()); err != nil {
+		return fmt.Errorf("init ledger: %w", err)
+	}
+	ctx.Ledger.Start(context.Background(), time.Duration(c.Ledger.SyncSeconds)*time.Second, func(err error) {
+		logx.Errorf("Ledger sync failed: %v", err)
+	})
+
+	// 8. 初始化算法执行器（大额订单拆分为子单经 OMS 下发，恢复重启前未完成的母单）
+	ctx.Executor = execution.NewExecutor(ctx.OMSManager, execrepo.NewPostgresRepo(ctx.DB), execution.Config{})
+	ctx.Executor.SetDepthSource(spotClient)
+	if err := ctx.Executor.Restore(context.Background()); err != nil {
+		return fmt.Errorf("init executor: %w", err)
+	}
+	// 订单状态变化回调下单策略，子单回报更新母单成交进度
+	ctx.OMSManager.SetOrderUpdateHandler(func(callCtx context.Context, order *model.Order) {
+		ctx.StrategyEngine.HandleOrderUpdate(callCtx, order)
+		ctx.Executor.HandleOrderUpdate(callCtx, order)
+	})
+
+	// 9. 初始化止损管理器（平仓经 OMS 以只减仓、高优先级下单，超过盘口深度阈值时经执行器拆单；止损调整持久化）
+	stopManager, err := newStopManager(ctx, c)
+	if err != nil {
+		return fmt.Errorf("init stop manager: %w", err)
+	}
+	ctx.StopManager = stopManager
+	ctx.StrategyEngine.SetStopManager(ctx.StopManager)
+	// 止损按持仓账本的净持仓登记与调整（账本成交回调 HandleFill 时已完成记账）
+	ctx.StrategyEngine.SetPositionReader(ctx.Ledger)
 
-	// 7. 初始化 TradingLoop
+	// 10. 初始化 TradingLoop
 	ctx.TradingLoop = NewTradingLoop(wsClient, ctx.StrategyEngine, c.Trading.Symbols, c.Trading.KlineInterval)
+	ctx.TradingLoop.SetStopManager(ctx.StopManager)
+	ctx.TradingLoop.SetMarkPriceSource(c.Stops.MarkPriceSource)
+	ctx.TradingLoop.SetExecutor(ctx.Executor)
+	ctx.TradingLoop.SetOrderListManager(ctx.OMSManager)
+	ctx.TradingLoop.SetLedger(ctx.Ledger)
+
+	// 11. 初始化 Kill Switch（恢复重启前的全局交易暂停，暂停期间拒绝非只减仓订单）
+	ctx.KillSwitch = newKillSwitch(ctx, c)
+	if err := ctx.KillSwitch.Restore(context.Background()); err != nil {
+		return fmt.Errorf("init kill switch: %w", err)
+	}
+	if halt := ctx.RiskManager.Halt(); halt != nil {
+		logx.Errorf("Trading is halted since %s by user %d: %s", halt.HaltedAt.Format(time.RFC3339), halt.HaltedBy, halt.Reason)
+	}
+	if c.KillSwitch.SyncSeconds > 0 {
+		ctx.KillSwitch.Start(context.Background(), time.Duration(c.KillSwitch.SyncSeconds)*time.Second)
+	}
+
+	// 12. 初始化对账器（以交易所为准校对订单、成交与持仓，对账成功前仅允许只减仓订单）
+	ctx.Reconciler = newReconciler(ctx, c, accountID)
+	if c.Reconcile.Enabled {
+		ctx.Reconciler.Start(context.Background())
+	}
 
 	// 启动 OMS 自动同步
 	ctx.OMSManager.StartAutoSync(context.Background())
@@ -227,3 +387,297 @@ func initTradingComponents(ctx *ServiceContext, c config.Config) error {
 
 	return nil
 }
+
+// NewRiskManager 按配置创建风控状态仓储与风控管理器（限额、规则集与交易日定义已装配）
+// 决策记录、状态历史与策略绩效等需要后台运行的组件由调用方按需挂载
+func NewRiskManager(db *sql.DB, c config.Config) (*risklogic.Manager, port.RiskRepo, error) {
+	// 风控状态仓储（根据配置选择 Redis 或 Postgres）
+	var riskRepo port.RiskRepo
+	if strings.ToLower(c.Risk.RepoType) == "redis" {
+		// 解析 Redis URL
+		opt, err := redis.ParseURL(c.Redis.URL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse redis URL: %w", err)
+		}
+		redisClient := redis.NewClient(opt)
+		riskRepo = riskrepo.NewRedisRepo(redisClient)
+		logx.Infof("Using Redis for risk state storage: %s", c.Redis.URL)
+	} else {
+		riskRepo = riskrepo.NewPostgresRepo(db)
+		logx.Infof("Using PostgreSQL for risk state storage")
+	}
+
+	riskConfig := risklogic.RiskConfig{
+		MaxConsecutiveLosses:     c.Risk.MaxConsecutiveLosses,
+		MaxDailyDrawdown:         c.Risk.MaxDailyDrawdown,
+		MaxTotalMDD:              c.Risk.MaxTotalMDD,
+		MaxSinglePositionPercent: c.Risk.MaxSinglePositionPercent,
+		MaxTotalExposurePercent:  c.Risk.MaxTotalExposurePercent,
+		MinCashReservePercent:    c.Risk.MinCashReservePercent,
+		MaxLeverage:              c.Risk.MaxLeverage,
+	}
+	manager := risklogic.NewManager(riskRepo, riskConfig)
+
+	// 运行时限额变更：最新版本覆盖配置文件中的限额
+	manager.SetLimitRepo(risklimitrepo.NewPostgresRepo(db))
+	if err := manager.RestoreLimits(context.Background()); err != nil {
+		logx.Errorf("Failed to restore risk limits: %v", err)
+	}
+
+	// 规则集：YAML 声明在前，strategy_configs 表配置覆盖同账户规则集
+	if err := applyRiskRuleSets(manager, c.Risk.RuleSets); err != nil {
+		return nil, nil, fmt.Errorf("init risk rule sets: %w", err)
+	}
+	if err := manager.LoadRuleSets(context.Background(), riskrulerepo.NewPostgresRepo(db)); err != nil {
+		logx.Errorf("Failed to load risk rule sets from strategy_configs: %v", err)
+	}
+
+	// 交易日定义（按账户时区滚动当日统计）
+	if err := applyRiskSessions(manager, c.Risk.Sessions); err != nil {
+		return nil, nil, fmt.Errorf("init risk sessions: %w", err)
+	}
+	return manager, riskRepo, nil
+}
+
+// applyRiskRuleSets 应用配置文件中的规则集
+func applyRiskRuleSets(manager *risklogic.Manager, sets map[string][]config.RiskRuleConfig) error {
+	for accountID, rules := range sets {
+		specs := make([]*model.RiskRuleSpec, 0, len(rules))
+		for _, rc := range rules {
+			spec := &model.RiskRuleSpec{
+				Name:     rc.Name,
+				Priority: rc.Priority,
+				Target:   rc.Target,
+				Enabled:  rc.Enabled,
+				Params:   rc.Params,
+			}
+			if rc.Scope != "" {
+				spec.Scope = model.ParseRiskRuleScope(rc.Scope)
+				if spec.Scope == 0 {
+					return fmt.Errorf("account %s rule %s: invalid scope %q", accountID, rc.Name, rc.Scope)
+				}
+			}
+			specs = append(specs, spec)
+		}
+		if err := manager.SetRuleSet(accountID, specs); err != nil {
+			return fmt.Errorf("account %s: %w", accountID, err)
+		}
+	}
+	return nil
+}
+
+// applyRiskSessions 应用配置文件中的交易日定义
+func applyRiskSessions(manager *risklogic.Manager, sessions map[string]config.RiskSessionConfig) error {
+	for accountID, sc := range sessions {
+		session := model.TradingSession{
+			Timezone:  sc.Timezone,
+			ResetHour: sc.ResetHour,
+		}
+		if sc.WeeklyResetDay != "" {
+			day, ok := parseWeekday(sc.WeeklyResetDay)
+			if !ok {
+				return fmt.Errorf("account %s: invalid weekly reset day %q", accountID, sc.WeeklyResetDay)
+			}
+			session.WeeklyReset = true
+			session.WeeklyResetDay = day
+		}
+		if err := manager.SetSession(accountID, session); err != nil {
+			return fmt.Errorf("account %s: %w", accountID, err)
+		}
+	}
+	return nil
+}
+
+// parseWeekday 解析星期名称（不区分大小写，如 Monday）
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// NewPerformanceTracker 根据配置创建策略绩效统计
+func NewPerformanceTracker(db *sql.DB, pc config.PerformanceConfig) *risklogic.PerformanceTracker {
+	return risklogic.NewPerformanceTracker(
+		settlementrepo.NewPostgresRepo(db),
+		risklogic.PerformanceConfig{
+			Window:             pc.Window,
+			MinWinRate:         pc.MinWinRate,
+			HalfSizeMultiplier: pc.HalfSizeMultiplier,
+			LossStreak:         pc.LossStreak,
+			CoolingOff:         time.Duration(pc.CoolingOffHours) * time.Hour,
+		},
+	)
+}
+
+// newStopManager 根据配置创建止损管理器，并恢复重启前的止损位
+func newStopManager(ctx *ServiceContext, c config.Config) (*position.StopManager, error) {
+	exits := oms.NewPositionExitAdapter(ctx.OMSManager)
+	exits.SetExitSplitter(ctx.Executor)
+	stopManager := position.NewStopManager(
+		exits,
+		stoprepo.NewPostgresRepo(ctx.DB),
+		position.StopConfig{
+			ConfirmWindow:     time.Duration(c.Stops.ConfirmWindowMs) * time.Millisecond,
+			BypassMovePercent: c.Stops.BypassMovePercent,
+			ATRPeriod:         c.Stops.ATRPeriod,
+			DefaultPolicy:     toStopPolicy(c.Stops.Default),
+		},
+	)
+	for name, pc := range c.Stops.Strategies {
+		stopManager.SetStrategyPolicy(name, toStopPolicy(pc))
+	}
+
+	if err := stopManager.Restore(context.Background()); err != nil {
+		return nil, err
+	}
+	return stopManager, nil
+}
+
+// newKillSwitch 根据配置创建 Kill Switch（日志与邮件通知，暂停时停止交易循环）
+func newKillSwitch(ctx *ServiceContext, c config.Config) *killswitch.KillSwitch {
+	ks := killswitch.NewKillSwitch(
+		tradinghaltrepo.NewPostgresRepo(ctx.DB),
+		ctx.RiskManager,
+		killswitch.Config{RequireSecondApproval: c.KillSwitch.RequireSecondApproval},
+	)
+	ks.SetOrderCanceller(ctx.OMSManager)
+	ks.SetAlgoCanceller(ctx.Executor)
+	// 按持仓账本平仓（不要求持仓登记止损），平仓后移除对应止损
+	flattener := oms.NewPositionFlattenAdapter(ctx.OMSManager, ctx.Ledger)
+	flattener.SetStopManager(ctx.StopManager)
+	ks.SetPositionFlattener(flattener)
+	ks.OnHalt(ctx.TradingLoop.Stop)
+	ks.OnError(func(err error) {
+		logx.Errorf("Kill switch error: %v", err)
+	})
+
+	ks.AddNotifier(killswitch.NotifierFunc(func(_ context.Context, event *killswitch.Event) error {
+		logx.Errorf("%s\n%s", event.Subject(), event.Body())
+		return nil
+	}))
+	if len(c.KillSwitch.NotifyEmails) > 0 {
+		ks.AddNotifier(killswitch.NewEmailNotifier(ctx.Email, c.AWS.SESConfig.Email, c.KillSwitch.NotifyEmails))
+	}
+	return ks
+}
+
+// newReconciler 创建对账器（差异与失败记录日志，配置邮箱时同时邮件告警）
+func newReconciler(ctx *ServiceContext, c config.Config, accountID string) *reconcile.Reconciler {
+	r := reconcile.NewReconciler(
+		ctx.BinanceSpotClient,
+		ctx.OrderRepo,
+		traderepo.NewPostgresRepo(ctx.DB),
+		reconcile.Config{
+			AccountID:         accountID,
+			Symbols:           c.Trading.Symbols,
+			QuoteAsset:        c.Reconcile.QuoteAsset,
+			Lookback:          time.Duration(c.Reconcile.LookbackMinutes) * time.Minute,
+			PositionTolerance: c.Reconcile.PositionTolerance,
+		},
+	)
+	r.SetTradingGate(ctx.OMSManager)
+	r.SetPositionBook(ctx.RiskManager)
+	r.SetPriceSource(ctx.BinanceWSClient)
+	r.OnError(func(err error) {
+		logx.Errorf("Reconcile failed: %v", err)
+	})
+
+	r.AddAlerter(reconcile.AlerterFunc(func(_ context.Context, report *reconcile.Report) error {
+		logx.Errorf("%s\n%s", report.Subject(), report.Body())
+		return nil
+	}))
+	if len(c.Reconcile.NotifyEmails) > 0 {
+		r.AddAlerter(reconcile.NewEmailAlerter(ctx.Email, c.AWS.SESConfig.Email, c.Reconcile.NotifyEmails))
+	}
+	return r
+}
+
+// newLedger 创建持仓账本（成交来自现货网关并写入 executions，策略按订单归属）
+func newLedger(ctx *ServiceContext, c config.Config, accountID string) *position.Ledger {
+	method := model.ParseCostMethod(c.Ledger.CostMethod)
+	if method == 0 {
+		logx.Errorf("Unknown ledger cost method %q, using %s", c.Ledger.CostMethod, model.CostMethodWeightedAverage)
+	}
+
+	l := position.NewLedger(ctx.BinanceSpotClient, position.LedgerConfig{
+		AccountID:  accountID,
+		Symbols:    c.Trading.Symbols,
+		Method:     method,
+		QuoteAsset: c.Reconcile.QuoteAsset,
+		Lookback:   time.Duration(c.Reconcile.LookbackMinutes) * time.Minute,
+	})
+	l.SetTradeRepo(traderepo.NewPostgresRepo(ctx.DB))
+	l.SetOrderRepo(ctx.OrderRepo)
+	l.SetSettlementRepo(settlementrepo.NewPostgresRepo(ctx.DB))
+	l.SetPnLRecorder(ctx.RiskManager)
+	l.SetExposureBook(ctx.RiskManager)
+	l.OnUnattributed(func(trade *model.Trade, strategyCode string) {
+		logx.Errorf("Trade %s of order %s has unregistered strategy code %s, booked as unattributed",
+			trade.ExecID, trade.ClientOrderID, strategyCode)
+	})
+	// 以第三种资产（如 BNB）收取的手续费按最新价格折算为计价资产，无法折算时单独记录并告警
+	l.SetFeeRateSource(binance.NewFeeRates(ctx.BinanceWSClient, c.Reconcile.QuoteAsset, time.Minute))
+	l.OnUnpricedFee(func(trade *model.Trade, err error) {
+		logx.Errorf("Fee %s %s of trade %s not priced in quote asset, recorded separately: %v",
+			trade.Fee, trade.FeeAsset, trade.ExecID, err)
+	})
+	return l
+}
+
+// toStopPolicy 转换配置文件中的止损移动策略
+func toStopPolicy(pc config.StopPolicyConfig) position.StopPolicy {
+	return position.StopPolicy{
+		BreakevenR:          pc.BreakevenR,
+		TrailingATRMultiple: pc.TrailingATRMultiple,
+	}
+}
+
+// newSizer 根据配置创建仓位计算器
+func newSizer(repo port.RiskRepo, c config.Config) (*sizing.Sizer, error) {
+	fallback, err := toSizingConfig(c.Sizing.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	sizer := sizing.NewSizer(repo, fallback)
+	for name, sc := range c.Sizing.Strategies {
+		cfg, err := toSizingConfig(sc)
+		if err != nil {
+			return nil, fmt.Errorf("strategy %s: %w", name, err)
+		}
+		sizer.SetStrategyConfig(name, cfg)
+	}
+	return sizer, nil
+}
+
+// toSizingConfig 转换配置文件中的仓位计算配置
+func toSizingConfig(sc config.SizingConfig) (sizing.Config, error) {
+	mode, err := sizing.ParseMode(sc.Mode)
+	if err != nil {
+		return sizing.Config{}, err
+	}
+
+	cfg := sizing.Config{
+		Mode:         mode,
+		RiskPerTrade: sc.RiskPerTrade,
+		ATRMultiple:  sc.ATRMultiple,
+		Fraction:     sc.Fraction,
+		WinRate:      sc.WinRate,
+		PayoffRatio:  sc.PayoffRatio,
+		KellyCap:     sc.KellyCap,
+	}
+	if sc.LotSize != "" {
+		if cfg.LotSize, err = model.NewMoney(sc.LotSize); err != nil {
+			return sizing.Config{}, fmt.Errorf("invalid lot size: %w", err)
+		}
+	}
+	if sc.MinQty != "" {
+		if cfg.MinQty, err = model.NewMoney(sc.MinQty); err != nil {
+			return sizing.Config{}, fmt.Errorf("invalid min qty: %w", err)
+		}
+	}
+	return cfg, nil
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/iluyuns/alpha-trade/internal/core/execution"
+	"github.com/iluyuns/alpha-trade/internal/core/oms"
+	"github.com/iluyuns/alpha-trade/internal/core/position"
 	"github.com/iluyuns/alpha-trade/internal/gateway/binance"
 	"github.com/iluyuns/alpha-trade/internal/strategy"
 	"github.com/iluyuns/alpha-trade/internal/domain/model"
@@ -16,6 +20,11 @@ import (
 type TradingLoop struct {
 	wsClient      *binance.WSClient
 	strategyEngine *strategy.Engine
+	stopManager   *position.StopManager
+	executor      *execution.Executor
+	orderLists    *oms.Manager
+	ledger        *position.Ledger
+	markSource    binance.MarkPriceSource // 止损标记价格来源（为空时沿用 K 线收盘价）
 	symbols       []string
 	interval      string
 	ctx           context.Context
@@ -44,6 +53,34 @@ func NewTradingLoop(
 	}
 }
 
+// SetStopManager 设置止损管理器（行情驱动止损检查）
+func (tl *TradingLoop) SetStopManager(stopManager *position.StopManager) {
+	tl.stopManager = stopManager
+}
+
+// SetMarkPriceSource 设置止损使用的标记价格来源（book_ticker / futures_mark；kline 或空值沿用收盘价）
+func (tl *TradingLoop) SetMarkPriceSource(source string) {
+	if source == "kline" {
+		source = ""
+	}
+	tl.markSource = binance.MarkPriceSource(source)
+}
+
+// SetExecutor 设置算法执行器（行情驱动子单调度）
+func (tl *TradingLoop) SetExecutor(executor *execution.Executor) {
+	tl.executor = executor
+}
+
+// SetOrderListManager 设置订单组管理（行情驱动本地模拟的 OCO 止损触发）
+func (tl *TradingLoop) SetOrderListManager(manager *oms.Manager) {
+	tl.orderLists = manager
+}
+
+// SetLedger 设置持仓账本（行情驱动未实现盈亏标记）
+func (tl *TradingLoop) SetLedger(ledger *position.Ledger) {
+	tl.ledger = ledger
+}
+
 // Start 启动交易循环
 func (tl *TradingLoop) Start(ctx context.Context) error {
 	tl.mu.Lock()
@@ -65,8 +102,32 @@ func (tl *TradingLoop) Start(ctx context.Context) error {
 		return fmt.Errorf("subscribe klines failed: %w", err)
 	}
 
+	// 订阅标记价格（止损以实时标记价格触发，确认窗口内需再次收到穿过止损位的标记价格）
+	var markCh <-chan *model.MarkPrice
+	if tl.stopManager != nil && tl.markSource != "" {
+		markCh, err = tl.wsClient.SubscribeMarkPrices(ctx, tl.symbols, tl.markSource)
+		if err != nil {
+			return fmt.Errorf("subscribe mark prices failed: %w", err)
+		}
+	}
+
 	tl.started = true
 
+	// 启动止损确认窗口巡检
+	if tl.stopManager != nil {
+		tl.stopManager.Start(tl.ctx, 100*time.Millisecond)
+	}
+
+	if markCh != nil {
+		tl.wg.Add(1)
+		go tl.processMarkPrices(markCh)
+	}
+
+	// 启动母单子单调度
+	if tl.executor != nil {
+		tl.executor.Start(tl.ctx, time.Second)
+	}
+
 	// 启动处理协程
 	tl.wg.Add(1)
 	go tl.processCandles(candleCh)
@@ -120,8 +181,55 @@ func (tl *TradingLoop) processCandles(ch <-chan *model.Candle) {
 	}
 }
 
+// processMarkPrices 处理标记价格（驱动止损判定）
+func (tl *TradingLoop) processMarkPrices(ch <-chan *model.MarkPrice) {
+	defer tl.wg.Done()
+
+	for {
+		select {
+		case <-tl.ctx.Done():
+			return
+		case mark, ok := <-ch:
+			if !ok {
+				logx.Errorf("Mark price channel closed, stops will no longer be evaluated")
+				return
+			}
+
+			if err := tl.stopManager.OnMarkPrice(tl.ctx, mark.Symbol, mark.Price, mark.RecvTime); err != nil {
+				logx.Errorf("Stop manager exit failed for %s: %v", mark.Symbol, err)
+			}
+		}
+	}
+}
+
 // handleCandle 处理单个 K线
 func (tl *TradingLoop) handleCandle(candle *model.Candle) error {
+	// 止损优先于策略信号：有标记价格推送时 K 线仅刷新 ATR，否则以收盘价作为标记价格
+	if tl.stopManager != nil {
+		if tl.markSource != "" {
+			tl.stopManager.UpdateATR(candle)
+		} else if err := tl.stopManager.OnCandle(tl.ctx, candle, time.Now()); err != nil {
+			logx.Errorf("Stop manager exit failed for %s: %v", candle.Symbol, err)
+		}
+	}
+
+	// 本地模拟的 OCO 止损同样以收盘价触发
+	if tl.orderLists != nil {
+		if _, err := tl.orderLists.CheckTriggers(tl.ctx, candle.Symbol, candle.Close); err != nil {
+			logx.Errorf("Order list stop trigger failed for %s: %v", candle.Symbol, err)
+		}
+	}
+
+	// 持仓未实现盈亏按收盘价标记
+	if tl.ledger != nil {
+		tl.ledger.Mark(candle.Symbol, candle.Close)
+	}
+
+	// 子单风控计算使用最新收盘价
+	if tl.executor != nil {
+		tl.executor.OnMarkPrice(candle.Symbol, candle.Close)
+	}
+
 	// 调用策略引擎处理 K线
 	if err := tl.strategyEngine.ProcessCandle(tl.ctx, candle); err != nil {
 		return fmt.Errorf("strategy engine process candle failed: %w", err)
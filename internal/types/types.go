@@ -39,6 +39,26 @@ type DashboardResp struct {
 	SystemHealth  []SystemHealthItem `json:"system_health"`  // 系统健康状态
 	RiskStatus    RiskStatus         `json:"risk_status"`    // 风控状态
 	Strategies    []StrategyOverview `json:"strategies"`     // 策略概览
+	Stops         []PositionStop     `json:"stops"`          // 持仓止损
+	Positions     []PositionItem     `json:"positions"`      // 持仓账本（按策略、标的）
+}
+
+type HaltReleaseResponse struct {
+	Released bool            `json:"released"` // 是否已解除（false 表示等待第二位管理员确认）
+	Halt     TradingHaltInfo `json:"halt"`
+}
+
+type KillSwitchRequest struct {
+	Reason  string `json:"reason"`           // 触发原因
+	Flatten bool   `json:"flatten,optional"` // 是否以只减仓市价单平掉全部持仓
+}
+
+type KillSwitchResponse struct {
+	Halt               TradingHaltInfo `json:"halt"`
+	CancelledOrders    int             `json:"cancelled_orders"`    // 本次撤销的活跃订单数
+	CancelledAlgos     int             `json:"cancelled_algos"`     // 本次撤销的算法母单数
+	FlattenedPositions int             `json:"flattened_positions"` // 本次发出平仓指令的持仓数
+	Errors             []string        `json:"errors"`              // 处置失败项（不中断其余处置）
 }
 
 type ListResponse struct {
@@ -75,10 +95,243 @@ type OAuth2Request struct {
 	Provider string `json:"provider"` // "google", "github"
 }
 
+type OrderEventItem struct {
+	ID           int64  `json:"id"`
+	FromStatus   string `json:"from_status"`            // 转换前状态（订单创建事件为空）
+	ToStatus     string `json:"to_status"`              // 转换后状态
+	Filled       string `json:"filled"`                 // 转换后的累计成交数量
+	Sequence     int64  `json:"sequence"`               // 交易所更新序号（0 表示无）
+	ExchangeTime string `json:"exchange_time,optional"` // 交易所更新时间
+	Source       string `json:"source"`                 // 事件来源：CREATE / SUBMIT / CANCEL / AMEND / SYNC / EXCHANGE / RECONCILE
+	Reason       string `json:"reason,optional"`        // 说明
+	CreatedAt    string `json:"created_at"`             // 记录时间
+}
+
+type OrderEventsReq struct {
+	ClientOrderID string `form:"client_oid"` // 客户端订单 ID
+}
+
+type OrderEventsResp struct {
+	ClientOrderID string           `json:"client_oid"`
+	Status        string           `json:"status"` // 当前状态
+	Events        []OrderEventItem `json:"events"` // 状态转换事件（按发生顺序）
+}
+
+type PositionItem struct {
+	StrategyID    string `json:"strategy_id"`         // 策略 ID（为空表示人工交易）
+	Symbol        string `json:"symbol"`              // 交易对
+	Direction     string `json:"direction"`           // 方向: Long, Short, Flat
+	Quantity      string `json:"quantity"`            // 净持仓（空头为负数）
+	AvgCost       string `json:"avg_cost"`            // 持仓均价
+	MarkPrice     string `json:"mark_price,optional"` // 标记价格
+	UnrealizedPnL string `json:"unrealized_pnl"`      // 未实现盈亏
+	RealizedPnL   string `json:"realized_pnl"`        // 累计已实现净盈亏（扣费后）
+	Fees          string `json:"fees"`                // 累计手续费
+	OpenedAt      string `json:"opened_at,optional"`  // 开仓时间
+	UpdatedAt     string `json:"updated_at"`          // 最后成交时间
+}
+
+type PositionStop struct {
+	PositionID     string `json:"position_id"`              // 持仓标识
+	StrategyID     string `json:"strategy_id"`              // 策略 ID
+	Symbol         string `json:"symbol"`                   // 交易对
+	Direction      string `json:"direction"`                // 方向: Long, Short
+	EntryPrice     string `json:"entry_price"`              // 开仓均价
+	StopLoss       string `json:"stop_loss"`                // 当前止损价
+	InitialStop    string `json:"initial_stop"`             // 初始止损价
+	TakeProfit     string `json:"take_profit,optional"`     // 止盈价
+	Stage          string `json:"stage"`                    // 阶段: INITIAL, BREAKEVEN, TRAILING
+	LastAdjustment string `json:"last_adjustment,optional"` // 最近一次调整原因
+	UpdatedAt      string `json:"updated_at"`               // 最后调整时间
+}
+
+type ReconcileDiffItem struct {
+	Kind          string `json:"kind"`                // 差异类型：ORPHAN_ORDER / ORDER_STATUS / ORDER_UNKNOWN / MISSING_TRADE / UNKNOWN_TRADE / POSITION
+	Symbol        string `json:"symbol"`              // 交易对
+	ClientOrderID string `json:"client_oid,optional"` // 关联的客户端订单 ID
+	Detail        string `json:"detail"`              // 差异说明
+	Repaired      bool   `json:"repaired"`            // 是否已自动修复
+}
+
+type ReconcileReport struct {
+	StartedAt  string              `json:"started_at"`
+	FinishedAt string              `json:"finished_at"`
+	Repaired   int                 `json:"repaired"`       // 自动修复的差异数
+	Unresolved int                 `json:"unresolved"`     // 需人工确认的差异数
+	Diffs      []ReconcileDiffItem `json:"diffs"`          // 全部差异
+	Error      string              `json:"error,optional"` // 对账中断原因（非空时交易保持暂停）
+}
+
+type ReconcileResponse struct {
+	Running bool             `json:"running"`         // 是否正在对账
+	Blocked bool             `json:"blocked"`         // 是否因对账未完成而暂停非只减仓订单
+	Report  *ReconcileReport `json:"report,optional"` // 最近一次对账报告
+}
+
 type RemoveRequest struct {
 	Id int64 `json:"id"`
 }
 
+type RiskExposure struct {
+	Equity         string  `json:"equity"`          // 账户净值
+	OrderNotional  string  `json:"order_notional"`  // 订单名义价值（只减仓为负）
+	SymbolExposure string  `json:"symbol_exposure"` // 标的敞口
+	SymbolPercent  float64 `json:"symbol_percent"`  // 标的敞口占净值比例
+	TotalExposure  string  `json:"total_exposure"`  // 总敞口
+	TotalPercent   float64 `json:"total_percent"`   // 总敞口占净值比例
+}
+
+type RiskHistoryEvent struct {
+	Time                string `json:"time"`                  // 发生时间
+	Kind                string `json:"kind"`                  // DAILY_ROLL / CIRCUIT_OPEN / CIRCUIT_CLOSE
+	Reason              string `json:"reason"`                // 触发原因（熔断时为触发规则与拦截原因）
+	CircuitBreakerUntil string `json:"circuit_breaker_until"` // 熔断解除时间（仅 CIRCUIT_OPEN）
+}
+
+type RiskHistoryPoint struct {
+	Time              string `json:"time"`               // 快照时间
+	Kind              string `json:"kind"`               // PERIODIC / CHANGE / DAILY_ROLL / CIRCUIT_OPEN / CIRCUIT_CLOSE
+	Equity            string `json:"equity"`             // 当前净值
+	PeakEquity        string `json:"peak_equity"`        // 峰值净值
+	Drawdown          string `json:"drawdown"`           // 回撤金额
+	DrawdownPercent   string `json:"drawdown_percent"`   // 回撤百分比
+	DailyPnL          string `json:"daily_pnl"`          // 当日盈亏
+	Exposure          string `json:"exposure"`           // 总敞口
+	ConsecutiveLosses int64  `json:"consecutive_losses"` // 连续亏损次数
+	CircuitBreakerOn  bool   `json:"circuit_breaker_on"` // 熔断器是否打开
+}
+
+type RiskHistoryReq struct {
+	AccountID  string `form:"account_id"`                  // 账户 ID
+	StrategyID string `form:"strategy_id,optional"`        // 策略 ID（为空时查询账户级）
+	Symbol     string `form:"symbol,optional"`             // 交易对（为空时查询账户 / 策略级）
+	From       int64  `form:"from,optional"`               // 起始时间（Unix 秒，含）
+	To         int64  `form:"to,optional"`                 // 截止时间（Unix 秒，不含）
+	Limit      int64  `form:"limit,optional,default=2000"` // 最大点数（最大 10000）
+}
+
+type RiskHistoryResp struct {
+	Points []RiskHistoryPoint `json:"points"` // 时间序列（按时间升序）
+	Events []RiskHistoryEvent `json:"events"` // 日切与熔断开关事件（按时间升序）
+}
+
+type RiskLimitChange struct {
+	Field    string  `json:"field"`    // 参数名（如 max_leverage）
+	Old      float64 `json:"old"`      // 变更前
+	New      float64 `json:"new"`      // 变更后
+	Loosened bool    `json:"loosened"` // 是否放宽
+}
+
+type RiskLimitHistoryReq struct {
+	Limit int64 `form:"limit,optional,default=20"` // 返回条数
+}
+
+type RiskLimitHistoryResp struct {
+	Items []RiskLimitVersionItem `json:"items"` // 按版本号倒序
+}
+
+type RiskLimitVersionItem struct {
+	Version   int64              `json:"version"`    // 版本号
+	Limits    map[string]float64 `json:"limits"`     // 该版本的完整限额
+	Changes   []RiskLimitChange  `json:"changes"`    // 相对上一版本的变更
+	ChangedBy int64              `json:"changed_by"` // 操作人 ID
+	Verified  bool               `json:"verified"`   // 是否通过提级认证
+	Reason    string             `json:"reason"`     // 变更原因
+	CreatedAt string             `json:"created_at"` // 变更时间
+}
+
+type RiskLimitsReq struct {
+}
+
+type RiskLimitsResp struct {
+	Version int64              `json:"version"` // 当前生效版本（0 表示启动配置）
+	Limits  map[string]float64 `json:"limits"`  // 当前限额
+}
+
+type RiskLimitsUpdateReq struct {
+	SudoToken string             `header:"X-Sudo-Token,optional"` // 提级认证令牌（放宽限额时必须）
+	Limits    map[string]float64 `json:"limits"`                  // 变更的限额（未列出的保持不变）
+	Reason    string             `json:"reason,optional"`         // 变更原因
+}
+
+type RiskLimitsUpdateResp struct {
+	Changed  bool               `json:"changed"`  // 是否产生新版本
+	Version  int64              `json:"version"`  // 当前生效版本
+	Verified bool               `json:"verified"` // 是否通过提级认证
+	Changes  []RiskLimitChange  `json:"changes"`  // 变更明细
+	Limits   map[string]float64 `json:"limits"`   // 变更后的限额
+}
+
+type RiskRecordItem struct {
+	ID                int64  `json:"id"`                 // 记录 ID
+	Rule              string `json:"rule"`               // 触发规则（放行为 ALLOW）
+	Level             string `json:"level"`              // INFO / WARNING / BLOCK
+	Decision          string `json:"decision"`           // ALLOW / BLOCK / REDUCE
+	Reason            string `json:"reason"`             // 决策原因
+	SuggestedQuantity string `json:"suggested_quantity"` // 降档建议数量
+	SuggestedLeverage int64  `json:"suggested_leverage"` // 降档建议杠杆
+	AccountID         string `json:"account_id"`         // 账户 ID
+	Symbol            string `json:"symbol"`             // 交易对
+	StrategyID        string `json:"strategy_id"`        // 策略 ID
+	Order             string `json:"order"`              // 订单上下文快照（JSON）
+	State             string `json:"state"`              // RiskState 快照（JSON）
+	TriggeredAt       string `json:"triggered_at"`       // 决策时间
+}
+
+type RiskRecordsReq struct {
+	Rule       string `form:"rule,optional"`                 // 规则名称（完整名称或前缀，如 PositionLimit）
+	Decision   string `form:"decision,optional"`             // ALLOW / BLOCK / REDUCE
+	Symbol     string `form:"symbol,optional"`               // 交易对
+	StrategyID string `form:"strategy_id,optional"`          // 策略 ID
+	From       int64  `form:"from,optional"`                 // 起始时间（Unix 秒，含）
+	To         int64  `form:"to,optional"`                   // 截止时间（Unix 秒，不含）
+	Page       int64  `form:"page,optional,default=1"`       // 页码（从 1 开始）
+	PageSize   int64  `form:"page_size,optional,default=20"` // 每页条数（最大 200）
+}
+
+type RiskRecordsResp struct {
+	Total int64            `json:"total"` // 满足条件的总数
+	Items []RiskRecordItem `json:"items"` // 按决策时间倒序
+}
+
+type RiskSimulateReq struct {
+	AccountID    string `json:"account_id"`                        // 账户 ID
+	StrategyID   string `json:"strategy_id,optional"`              // 策略 ID（绩效风控按策略降级）
+	Symbol       string `json:"symbol"`                            // 交易对
+	MarketType   string `json:"market_type,optional,default=SPOT"` // SPOT / FUTURE
+	Side         string `json:"side"`                              // BUY / SELL
+	Type         string `json:"type,optional,default=LIMIT"`       // LIMIT / MARKET / IOC / FOK
+	Price        string `json:"price,optional"`                    // 委托价（市价单为空）
+	Quantity     string `json:"quantity"`                          // 数量
+	CurrentPrice string `json:"current_price"`                     // 当前市价（计算名义价值）
+	ProtectPrice string `json:"protect_price,optional"`            // 保护价
+	Leverage     int64  `json:"leverage,optional"`                 // 杠杆（合约）
+	ReduceOnly   bool   `json:"reduce_only,optional"`              // 只减仓
+}
+
+type RiskSimulateResp struct {
+	Decision          string             `json:"decision"`           // 最终决策 ALLOW / BLOCK / REDUCE
+	Reason            string             `json:"reason"`             // 最终决策原因
+	TriggeredRule     string             `json:"triggered_rule"`     // 触发规则
+	SuggestedQuantity string             `json:"suggested_quantity"` // 放行数量（拦截时为 0）
+	SuggestedLeverage int64              `json:"suggested_leverage"` // 放行杠杆
+	Rules             []RiskSimulateRule `json:"rules"`              // 逐条规则评估结果（按评估顺序）
+	Before            RiskExposure       `json:"before"`             // 当前敞口
+	After             RiskExposure       `json:"after"`              // 按放行数量成交后的预计敞口
+}
+
+type RiskSimulateRule struct {
+	Rule              string `json:"rule"`               // 规则名称（全局交易暂停为 trading_halt）
+	Priority          int64  `json:"priority"`           // 优先级
+	Decision          string `json:"decision"`           // ALLOW / BLOCK / REDUCE
+	Reason            string `json:"reason"`             // 决策原因
+	TriggeredRule     string `json:"triggered_rule"`     // 触发的规则细项
+	Quantity          string `json:"quantity"`           // 该规则评估的数量（前序规则降档后）
+	Leverage          int64  `json:"leverage"`           // 该规则评估的杠杆
+	SuggestedQuantity string `json:"suggested_quantity"` // 降档建议数量
+	SuggestedLeverage int64  `json:"suggested_leverage"` // 降档建议杠杆
+}
+
 type RiskStatus struct {
 	ConsecutiveLosses    int64  `json:"consecutive_losses"`         // 连续亏损次数
 	MaxConsecutiveLosses int64  `json:"max_consecutive_losses"`     // 最大连续亏损次数
@@ -101,13 +354,17 @@ type SendTestResp struct {
 }
 
 type StrategyOverview struct {
-	ID        string `json:"id"`              // 策略 ID
-	Name      string `json:"name"`            // 策略名称
-	Symbol    string `json:"symbol"`          // 交易对
-	Direction string `json:"direction"`       // 方向: Long, Short, N/A
-	Status    string `json:"status"`          // 状态: running, stopped, cooling
-	WinRate   string `json:"win_rate"`        // 胜率（百分比）
-	Reason    string `json:"reason,optional"` // 状态原因
+	ID             string `json:"id"`                    // 策略 ID
+	Name           string `json:"name"`                  // 策略名称
+	Symbol         string `json:"symbol"`                // 交易对
+	Direction      string `json:"direction"`             // 方向: Long, Short, N/A
+	Status         string `json:"status"`                // 状态: running, stopped, cooling
+	WinRate        string `json:"win_rate"`              // 胜率（百分比）
+	Reason         string `json:"reason,optional"`       // 状态原因
+	RiskMode       string `json:"risk_mode"`             // 风险模式: NORMAL, HALF_SIZE, PAUSED
+	SizeMultiplier string `json:"size_multiplier"`       // 仓位系数
+	Expectancy     string `json:"expectancy,optional"`   // 期望值（每笔净盈亏）
+	PausedUntil    string `json:"paused_until,optional"` // 冷静期结束时间
 }
 
 type SubscribeReq struct {
@@ -136,6 +393,27 @@ type SystemInfoResp struct {
 	CommitHash string `json:"commit_hash"` // 提交哈希
 }
 
+type TradingHaltInfo struct {
+	ID                 int64  `json:"id"`
+	Status             string `json:"status"`                        // ACTIVE / RELEASE_PENDING / RELEASED
+	Reason             string `json:"reason"`                        // 触发原因
+	HaltedBy           int64  `json:"halted_by"`                     // 触发人
+	HaltedAt           string `json:"halted_at"`                     // 触发时间
+	CancelledOrders    int    `json:"cancelled_orders"`              // 撤销的活跃订单数
+	Flattened          bool   `json:"flattened"`                     // 是否请求平掉全部持仓
+	FlattenedPositions int    `json:"flattened_positions"`           // 发出平仓指令的持仓数
+	ReleaseRequestedBy int64  `json:"release_requested_by,optional"` // 解除发起人
+	ReleaseRequestedAt string `json:"release_requested_at,optional"` // 解除发起时间
+	ReleasedBy         int64  `json:"released_by,optional"`          // 解除确认人
+	ReleasedAt         string `json:"released_at,optional"`          // 解除时间
+}
+
+type TradingHaltResponse struct {
+	Halted                bool             `json:"halted"`
+	RequireSecondApproval bool             `json:"require_second_approval"` // 解除是否需第二位管理员确认
+	Halt                  *TradingHaltInfo `json:"halt,optional"`           // 生效中的暂停记录
+}
+
 type TradingStartResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
@@ -148,6 +426,7 @@ type TradingStatusResponse struct {
 	Symbols  []string `json:"symbols"`          // 交易对列表
 	Interval string   `json:"interval"`         // K线周期
 	Strategy string   `json:"strategy"`         // 策略类型
+	Halted   bool     `json:"halted"`           // 是否处于全局交易暂停（Kill Switch）
 	Message  string   `json:"message,optional"` // 状态消息
 }
 
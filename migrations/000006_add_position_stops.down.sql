@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_stop_adjustments_position;
+DROP INDEX IF EXISTS idx_position_stops_symbol;
+DROP TABLE IF EXISTS stop_adjustments;
+DROP TABLE IF EXISTS position_stops;
@@ -0,0 +1,59 @@
+-- Position Stops Table (持仓止损表)
+CREATE TABLE IF NOT EXISTS position_stops (
+    id BIGSERIAL PRIMARY KEY,
+    position_id VARCHAR(128) NOT NULL UNIQUE,
+    account_id VARCHAR(64) NOT NULL,
+    strategy_id VARCHAR(64) NOT NULL DEFAULT '',
+    symbol VARCHAR(32) NOT NULL,
+    side VARCHAR(8) NOT NULL,
+
+    quantity DECIMAL(36, 18) NOT NULL,
+    entry_price DECIMAL(36, 18) NOT NULL,
+    stop_loss DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    take_profit DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    initial_stop DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    stage VARCHAR(16) NOT NULL DEFAULT 'INITIAL',
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE position_stops IS '持仓止损表：存储每个持仓当前生效的止损/止盈位（重启恢复用）';
+COMMENT ON COLUMN position_stops.position_id IS '持仓标识（账户:策略:标的）';
+COMMENT ON COLUMN position_stops.account_id IS '账户ID';
+COMMENT ON COLUMN position_stops.strategy_id IS '策略ID';
+COMMENT ON COLUMN position_stops.symbol IS '标的';
+COMMENT ON COLUMN position_stops.side IS '持仓方向 [ENUM: BUY, SELL]';
+COMMENT ON COLUMN position_stops.quantity IS '持仓数量';
+COMMENT ON COLUMN position_stops.entry_price IS '开仓均价';
+COMMENT ON COLUMN position_stops.stop_loss IS '当前止损价';
+COMMENT ON COLUMN position_stops.take_profit IS '止盈价';
+COMMENT ON COLUMN position_stops.initial_stop IS '初始止损价（1R 基准）';
+COMMENT ON COLUMN position_stops.stage IS '止损阶段 [ENUM: INITIAL, BREAKEVEN, TRAILING]';
+COMMENT ON COLUMN position_stops.updated_at IS '最后调整时间';
+
+-- Stop Adjustments Table (止损调整记录表)
+CREATE TABLE IF NOT EXISTS stop_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    position_id VARCHAR(128) NOT NULL,
+    symbol VARCHAR(32) NOT NULL,
+    old_stop DECIMAL(36, 18) NOT NULL,
+    new_stop DECIMAL(36, 18) NOT NULL,
+    stage VARCHAR(16) NOT NULL,
+    mark_price DECIMAL(36, 18) NOT NULL,
+    reason VARCHAR(128) NOT NULL DEFAULT '',
+    adjusted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE stop_adjustments IS '止损调整记录表：保本/追踪止损的每次移动（只追加）';
+COMMENT ON COLUMN stop_adjustments.position_id IS '持仓标识';
+COMMENT ON COLUMN stop_adjustments.old_stop IS '调整前止损价';
+COMMENT ON COLUMN stop_adjustments.new_stop IS '调整后止损价';
+COMMENT ON COLUMN stop_adjustments.stage IS '调整后止损阶段';
+COMMENT ON COLUMN stop_adjustments.mark_price IS '调整时标记价格';
+COMMENT ON COLUMN stop_adjustments.reason IS '调整原因';
+COMMENT ON COLUMN stop_adjustments.adjusted_at IS '调整时间';
+
+-- 索引
+CREATE INDEX idx_position_stops_symbol ON position_stops(symbol);
+CREATE INDEX idx_stop_adjustments_position ON stop_adjustments(position_id, adjusted_at DESC);
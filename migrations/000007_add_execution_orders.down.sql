@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_execution_children_parent;
+DROP INDEX IF EXISTS idx_execution_parents_active;
+DROP TABLE IF EXISTS execution_children;
+DROP TABLE IF EXISTS execution_parents;
@@ -0,0 +1,64 @@
+-- Execution Parents Table (算法母单表)
+CREATE TABLE IF NOT EXISTS execution_parents (
+    id BIGSERIAL PRIMARY KEY,
+    parent_id VARCHAR(64) NOT NULL UNIQUE,
+    account_id VARCHAR(64) NOT NULL,
+    strategy_id VARCHAR(64) NOT NULL DEFAULT '',
+    symbol VARCHAR(32) NOT NULL,
+    side VARCHAR(8) NOT NULL,
+    algorithm VARCHAR(16) NOT NULL,
+
+    quantity DECIMAL(36, 18) NOT NULL,
+    submitted_qty DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    filled_qty DECIMAL(36, 18) NOT NULL DEFAULT 0,
+
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    slices INT NOT NULL,
+    reduce_only BOOLEAN NOT NULL DEFAULT FALSE,
+
+    status VARCHAR(16) NOT NULL,
+    child_count INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE execution_parents IS '算法母单表：TWAP 等执行算法的母单（拆分为多笔子单执行）';
+COMMENT ON COLUMN execution_parents.parent_id IS '母单ID';
+COMMENT ON COLUMN execution_parents.algorithm IS '执行算法 [ENUM: TWAP, VWAP, POV]';
+COMMENT ON COLUMN execution_parents.quantity IS '母单总数量';
+COMMENT ON COLUMN execution_parents.submitted_qty IS '已下发子单数量';
+COMMENT ON COLUMN execution_parents.filled_qty IS '已成交数量';
+COMMENT ON COLUMN execution_parents.start_time IS '执行开始时间';
+COMMENT ON COLUMN execution_parents.end_time IS '计划完成时间';
+COMMENT ON COLUMN execution_parents.slices IS '计划子单数量';
+COMMENT ON COLUMN execution_parents.reduce_only IS '子单是否只减仓';
+COMMENT ON COLUMN execution_parents.status IS '母单状态 [ENUM: ACTIVE, COMPLETED, CANCELLED, FAILED]';
+COMMENT ON COLUMN execution_parents.child_count IS '已下发子单笔数';
+COMMENT ON COLUMN execution_parents.last_error IS '最近一次子单失败原因';
+
+-- Execution Children Table (母子单关联表)
+CREATE TABLE IF NOT EXISTS execution_children (
+    id BIGSERIAL PRIMARY KEY,
+    client_oid VARCHAR(64) NOT NULL UNIQUE,
+    parent_id VARCHAR(64) NOT NULL REFERENCES execution_parents(parent_id) ON DELETE CASCADE,
+    seq INT NOT NULL,
+    quantity DECIMAL(36, 18) NOT NULL,
+    filled_qty DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE execution_children IS '母子单关联表：记录母单下发的每一笔子单（对应 orders.client_oid）';
+COMMENT ON COLUMN execution_children.client_oid IS '子单客户端订单ID';
+COMMENT ON COLUMN execution_children.parent_id IS '所属母单ID';
+COMMENT ON COLUMN execution_children.seq IS '子单序号（从 1 开始）';
+COMMENT ON COLUMN execution_children.quantity IS '子单数量';
+COMMENT ON COLUMN execution_children.filled_qty IS '子单成交数量';
+COMMENT ON COLUMN execution_children.status IS '子单状态';
+
+-- 索引
+CREATE INDEX idx_execution_parents_active ON execution_parents(status) WHERE status = 'ACTIVE';
+CREATE INDEX idx_execution_children_parent ON execution_children(parent_id, seq);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_settlements_strategy_closed;
+
+COMMENT ON COLUMN settlements.metadata IS '扩展元数据 [JSON: leverage, liq_price]';
@@ -0,0 +1,5 @@
+-- 按策略查询最近结算（绩效风控滚动窗口）
+CREATE INDEX IF NOT EXISTS idx_settlements_strategy_closed
+    ON settlements ((metadata->>'strategy_id'), closed_at DESC);
+
+COMMENT ON COLUMN settlements.metadata IS '扩展元数据 [JSON: strategy_id, risk_amount, leverage, liq_price]';
@@ -0,0 +1,3 @@
+ALTER TABLE strategy_configs ALTER COLUMN key_name TYPE VARCHAR(64);
+
+COMMENT ON COLUMN strategy_configs.key_name IS '配置键名 (e.g., risk.max_pos_ratio)';
@@ -0,0 +1,4 @@
+-- 风控规则集按 risk.rule.<account>.<rule>.<field> 存储，键名需要更长
+ALTER TABLE strategy_configs ALTER COLUMN key_name TYPE VARCHAR(128);
+
+COMMENT ON COLUMN strategy_configs.key_name IS '配置键名 (e.g., risk.max_pos_ratio, risk.rule.<account>.<rule>.param.<key>)';
@@ -0,0 +1,19 @@
+-- Risk Limit Versions Table (风控限额版本表)
+CREATE TABLE IF NOT EXISTS risk_limit_versions (
+    version BIGINT PRIMARY KEY,
+    limits JSONB NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    changed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE risk_limit_versions IS '风控限额版本表：运行时限额变更历史（最新版本在启动时覆盖配置文件）';
+COMMENT ON COLUMN risk_limit_versions.version IS '版本号（单调递增）';
+COMMENT ON COLUMN risk_limit_versions.limits IS '变更后的完整限额 [JSON: max_leverage, max_daily_drawdown, ...]';
+COMMENT ON COLUMN risk_limit_versions.changes IS '相对上一版本的变更 [JSON: field, old, new, loosened]';
+COMMENT ON COLUMN risk_limit_versions.changed_by IS '操作人 ID';
+COMMENT ON COLUMN risk_limit_versions.verified IS '是否通过提级认证（放宽限额时必须）';
+COMMENT ON COLUMN risk_limit_versions.reason IS '变更原因';
+COMMENT ON COLUMN risk_limit_versions.created_at IS '变更时间';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_risk_records_strategy;
+DROP INDEX IF EXISTS idx_risk_records_symbol;
+DROP INDEX IF EXISTS idx_risk_records_event_type;
+DROP INDEX IF EXISTS idx_risk_records_triggered;
+
+COMMENT ON COLUMN risk_records.event_type IS '事件类型 [ENUM: POS_LIMIT, LOSS_CIRCUIT, SLIPPAGE, PRICE_PROTECT]';
+COMMENT ON COLUMN risk_records.level IS '风险级别 [ENUM: INFO, WARNING, BLOCK, CRITICAL]';
+COMMENT ON COLUMN risk_records.details IS '触发时的上下文快照 [JSON]';
@@ -0,0 +1,9 @@
+-- 风控记录查询（按规则 / 标的 / 策略 / 时间过滤，按时间倒序分页）
+CREATE INDEX IF NOT EXISTS idx_risk_records_triggered ON risk_records (triggered_at DESC);
+CREATE INDEX IF NOT EXISTS idx_risk_records_event_type ON risk_records (event_type, triggered_at DESC);
+CREATE INDEX IF NOT EXISTS idx_risk_records_symbol ON risk_records (symbol, triggered_at DESC);
+CREATE INDEX IF NOT EXISTS idx_risk_records_strategy ON risk_records (strategy_id, triggered_at DESC);
+
+COMMENT ON COLUMN risk_records.event_type IS '触发规则 (e.g., PositionLimit:SinglePosition, CircuitBreaker:DailyDrawdown；抽样放行为 ALLOW)';
+COMMENT ON COLUMN risk_records.level IS '风险级别 [ENUM: INFO, WARNING, BLOCK] (INFO=抽样放行, WARNING=降档, BLOCK=拦截)';
+COMMENT ON COLUMN risk_records.details IS '触发时的上下文快照 [JSON: decision, reason, suggested_quantity, suggested_leverage, account_id, order, state]';
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_orders_strategy;
+ALTER TABLE orders ALTER COLUMN strategy_id TYPE VARCHAR(32);
+
+DELETE FROM risk_states WHERE strategy_id <> '';
+ALTER TABLE risk_states DROP CONSTRAINT IF EXISTS risk_states_scope_key;
+ALTER TABLE risk_states ADD CONSTRAINT risk_states_account_id_symbol_key UNIQUE (account_id, symbol);
+ALTER TABLE risk_states DROP COLUMN IF EXISTS strategy_id;
+
+COMMENT ON TABLE risk_states IS '风控状态表：存储账户实时风控状态';
+COMMENT ON COLUMN risk_states.symbol IS '标的（空字符串表示账户全局状态）';
@@ -0,0 +1,13 @@
+-- 风控状态按 账户 -> 策略 -> 标的 分级统计
+ALTER TABLE risk_states ADD COLUMN IF NOT EXISTS strategy_id VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE risk_states DROP CONSTRAINT IF EXISTS risk_states_account_id_symbol_key;
+ALTER TABLE risk_states ADD CONSTRAINT risk_states_scope_key UNIQUE (account_id, strategy_id, symbol);
+
+COMMENT ON TABLE risk_states IS '风控状态表：按 账户 / 策略 / 标的 作用域存储实时风控状态';
+COMMENT ON COLUMN risk_states.strategy_id IS '策略ID（空字符串表示不区分策略）';
+COMMENT ON COLUMN risk_states.symbol IS '标的（空字符串表示不区分标的；策略与标的均为空时为账户全局状态）';
+
+-- 订单归属策略（与 position_stops / execution_parents 保持一致的长度）
+ALTER TABLE orders ALTER COLUMN strategy_id TYPE VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_orders_strategy ON orders (strategy_id, created_at DESC);
@@ -0,0 +1 @@
+ALTER TABLE risk_states DROP COLUMN IF EXISTS version;
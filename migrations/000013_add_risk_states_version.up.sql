@@ -0,0 +1,5 @@
+-- 风控状态乐观锁版本（多进程并发更新时按行版本比较写入）
+-- 已有记录视为版本 1（版本 0 表示尚未持久化）
+ALTER TABLE risk_states ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN risk_states.version IS '乐观锁版本（每次写入加一，CompareAndSwap 按版本比较）';
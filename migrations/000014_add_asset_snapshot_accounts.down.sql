@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_asset_snapshots_account_day;
+
+ALTER TABLE asset_snapshots ALTER COLUMN frozen_balance DROP DEFAULT;
+ALTER TABLE asset_snapshots ALTER COLUMN available_balance DROP DEFAULT;
+ALTER TABLE asset_snapshots ALTER COLUMN exchange DROP DEFAULT;
+ALTER TABLE asset_snapshots DROP COLUMN IF EXISTS trading_day;
+ALTER TABLE asset_snapshots DROP COLUMN IF EXISTS kind;
+ALTER TABLE asset_snapshots DROP COLUMN IF EXISTS account_id;
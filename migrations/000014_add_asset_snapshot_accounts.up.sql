@@ -0,0 +1,14 @@
+-- 资产快照按账户记录交易日开始净值（风控每日重置时生成）
+ALTER TABLE asset_snapshots ADD COLUMN IF NOT EXISTS account_id VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE asset_snapshots ADD COLUMN IF NOT EXISTS kind VARCHAR(32) NOT NULL DEFAULT 'PERIODIC';
+ALTER TABLE asset_snapshots ADD COLUMN IF NOT EXISTS trading_day DATE;
+ALTER TABLE asset_snapshots ALTER COLUMN exchange SET DEFAULT '';
+ALTER TABLE asset_snapshots ALTER COLUMN available_balance SET DEFAULT 0;
+ALTER TABLE asset_snapshots ALTER COLUMN frozen_balance SET DEFAULT 0;
+
+-- 同账户、同类型、同交易日只保留一条（多进程同时滚动时去重）
+CREATE UNIQUE INDEX IF NOT EXISTS idx_asset_snapshots_account_day ON asset_snapshots (account_id, kind, trading_day);
+
+COMMENT ON COLUMN asset_snapshots.account_id IS '账户 ID';
+COMMENT ON COLUMN asset_snapshots.kind IS '快照类型 [ENUM: PERIODIC, DAILY_START]';
+COMMENT ON COLUMN asset_snapshots.trading_day IS '所属交易日（按账户交易时段定义，DAILY_START 必填）';
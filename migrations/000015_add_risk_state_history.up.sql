@@ -0,0 +1,44 @@
+-- 风控状态历史（定时与变更快照，用于回撤曲线与熔断复盘）
+CREATE TABLE IF NOT EXISTS risk_state_history (
+    id BIGSERIAL PRIMARY KEY,
+    account_id VARCHAR(64) NOT NULL,
+    strategy_id VARCHAR(64) NOT NULL DEFAULT '',
+    symbol VARCHAR(32) NOT NULL DEFAULT '',
+    kind VARCHAR(32) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    current_equity DECIMAL(36, 18) NOT NULL,
+    peak_equity DECIMAL(36, 18) NOT NULL,
+    mdd DECIMAL(36, 18) NOT NULL,
+    mdd_percent DECIMAL(36, 18) NOT NULL,
+    daily_pnl DECIMAL(36, 18) NOT NULL,
+    total_exposure DECIMAL(36, 18) NOT NULL,
+    consecutive_losses INT NOT NULL DEFAULT 0,
+    circuit_breaker_open BOOLEAN NOT NULL DEFAULT FALSE,
+    circuit_breaker_until TIMESTAMP WITH TIME ZONE,
+    state_version BIGINT NOT NULL DEFAULT 0,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 按作用域查询时间序列
+CREATE INDEX IF NOT EXISTS idx_risk_state_history_scope ON risk_state_history (account_id, strategy_id, symbol, recorded_at);
+-- 按类型清理过期快照
+CREATE INDEX IF NOT EXISTS idx_risk_state_history_kind ON risk_state_history (kind, recorded_at);
+
+COMMENT ON TABLE risk_state_history IS '风控状态历史：定时与变更快照（净值、回撤、敞口、连续亏损、熔断）';
+COMMENT ON COLUMN risk_state_history.id IS '流水 ID';
+COMMENT ON COLUMN risk_state_history.account_id IS '账户 ID';
+COMMENT ON COLUMN risk_state_history.strategy_id IS '策略ID（空字符串表示不区分策略）';
+COMMENT ON COLUMN risk_state_history.symbol IS '标的（空字符串表示不区分标的）';
+COMMENT ON COLUMN risk_state_history.kind IS '快照类型 [ENUM: PERIODIC, CHANGE, DAILY_ROLL, CIRCUIT_OPEN, CIRCUIT_CLOSE]';
+COMMENT ON COLUMN risk_state_history.reason IS '触发原因（熔断时为拦截原因）';
+COMMENT ON COLUMN risk_state_history.current_equity IS '当前净值';
+COMMENT ON COLUMN risk_state_history.peak_equity IS '峰值净值';
+COMMENT ON COLUMN risk_state_history.mdd IS '当前回撤金额';
+COMMENT ON COLUMN risk_state_history.mdd_percent IS '当前回撤百分比';
+COMMENT ON COLUMN risk_state_history.daily_pnl IS '当日累计盈亏';
+COMMENT ON COLUMN risk_state_history.total_exposure IS '总敞口';
+COMMENT ON COLUMN risk_state_history.consecutive_losses IS '连续亏损次数';
+COMMENT ON COLUMN risk_state_history.circuit_breaker_open IS '熔断器是否打开';
+COMMENT ON COLUMN risk_state_history.circuit_breaker_until IS '熔断解除时间';
+COMMENT ON COLUMN risk_state_history.state_version IS '对应 risk_states.version';
+COMMENT ON COLUMN risk_state_history.recorded_at IS '快照时间';
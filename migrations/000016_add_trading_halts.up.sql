@@ -0,0 +1,34 @@
+-- Trading Halts Table (全局交易暂停表)
+CREATE TABLE IF NOT EXISTS trading_halts (
+    id BIGSERIAL PRIMARY KEY,
+    status VARCHAR(32) NOT NULL DEFAULT 'ACTIVE',
+    reason TEXT NOT NULL DEFAULT '',
+    halted_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    halted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    cancelled_orders INT NOT NULL DEFAULT 0,
+    flattened BOOLEAN NOT NULL DEFAULT FALSE,
+    flattened_positions INT NOT NULL DEFAULT 0,
+    release_requested_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    release_requested_at TIMESTAMP WITH TIME ZONE,
+    released_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    released_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 同一时刻最多一条生效的暂停记录（多进程同时触发时只有一条写入成功）
+CREATE UNIQUE INDEX IF NOT EXISTS uk_trading_halts_active ON trading_halts ((TRUE)) WHERE status <> 'RELEASED';
+
+COMMENT ON TABLE trading_halts IS '全局交易暂停表：Kill Switch 触发与解除记录（生效期间拒绝全部非只减仓订单，重启后恢复）';
+COMMENT ON COLUMN trading_halts.id IS '暂停记录 ID';
+COMMENT ON COLUMN trading_halts.status IS '状态 [ENUM: ACTIVE, RELEASE_PENDING, RELEASED]';
+COMMENT ON COLUMN trading_halts.reason IS '触发原因';
+COMMENT ON COLUMN trading_halts.halted_by IS '触发人 ID';
+COMMENT ON COLUMN trading_halts.halted_at IS '触发时间';
+COMMENT ON COLUMN trading_halts.cancelled_orders IS '撤销的活跃订单数';
+COMMENT ON COLUMN trading_halts.flattened IS '是否请求市价平掉全部持仓';
+COMMENT ON COLUMN trading_halts.flattened_positions IS '发出平仓指令的持仓数';
+COMMENT ON COLUMN trading_halts.release_requested_by IS '解除发起人 ID（双人确认时为第一位管理员）';
+COMMENT ON COLUMN trading_halts.release_requested_at IS '解除发起时间';
+COMMENT ON COLUMN trading_halts.released_by IS '解除确认人 ID';
+COMMENT ON COLUMN trading_halts.released_at IS '解除时间';
+COMMENT ON COLUMN trading_halts.updated_at IS '更新时间';
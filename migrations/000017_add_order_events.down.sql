@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS order_events;
+ALTER TABLE orders DROP COLUMN IF EXISTS exchange_updated_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS last_sequence;
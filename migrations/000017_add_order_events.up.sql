@@ -0,0 +1,34 @@
+-- 订单状态机：记录最近应用的交易所更新，用于丢弃乱序到达的回报
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS last_sequence BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS exchange_updated_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN orders.last_sequence IS '最近应用的交易所更新序号（0 表示无序号）';
+COMMENT ON COLUMN orders.exchange_updated_at IS '最近应用的交易所更新时间';
+
+-- Order Events Table (订单事件表)
+CREATE TABLE IF NOT EXISTS order_events (
+    id BIGSERIAL PRIMARY KEY,
+    client_oid VARCHAR(64) NOT NULL,
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL,
+    filled_qty DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    sequence BIGINT NOT NULL DEFAULT 0,
+    exchange_time TIMESTAMP WITH TIME ZONE,
+    source VARCHAR(16) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_events_client_oid ON order_events (client_oid, id);
+
+COMMENT ON TABLE order_events IS '订单事件表：订单状态转换的追加日志（按 id 顺序可重放订单完整历史）';
+COMMENT ON COLUMN order_events.id IS '事件 ID（单调递增）';
+COMMENT ON COLUMN order_events.client_oid IS '订单 client_oid';
+COMMENT ON COLUMN order_events.from_status IS '转换前状态（空字符串表示订单创建）';
+COMMENT ON COLUMN order_events.to_status IS '转换后状态 [ENUM: PENDING, SUBMITTED, PARTIAL_FILLED, FILLED, CANCELLED, REJECTED]';
+COMMENT ON COLUMN order_events.filled_qty IS '转换后的累计成交数量';
+COMMENT ON COLUMN order_events.sequence IS '交易所更新序号（0 表示无序号）';
+COMMENT ON COLUMN order_events.exchange_time IS '交易所更新时间';
+COMMENT ON COLUMN order_events.source IS '事件来源 [ENUM: CREATE, CANCEL, SYNC, EXCHANGE]';
+COMMENT ON COLUMN order_events.reason IS '说明（撤单、拒单原因等）';
+COMMENT ON COLUMN order_events.created_at IS '事件记录时间';
@@ -0,0 +1,46 @@
+-- Order Lists Table (订单组表：OCO / 括号单)
+CREATE TABLE IF NOT EXISTS order_lists (
+    list_id VARCHAR(64) PRIMARY KEY,
+    list_type VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+    symbol VARCHAR(32) NOT NULL,
+    account_id VARCHAR(64) NOT NULL DEFAULT '',
+    strategy_id VARCHAR(64) NOT NULL DEFAULT '',
+    entry_client_oid VARCHAR(64) NOT NULL DEFAULT '',
+    exit_side VARCHAR(8) NOT NULL,
+    quantity DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    take_profit_price DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    stop_price DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    stop_limit_price DECIMAL(36, 18) NOT NULL DEFAULT 0,
+    take_profit_client_oid VARCHAR(64) NOT NULL DEFAULT '',
+    stop_loss_client_oid VARCHAR(64) NOT NULL DEFAULT '',
+    exchange_list_id VARCHAR(64) NOT NULL DEFAULT '',
+    native BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 重启恢复只扫描未完结的订单组
+CREATE INDEX IF NOT EXISTS idx_order_lists_active ON order_lists (created_at) WHERE status IN ('PENDING', 'ACTIVE');
+
+COMMENT ON TABLE order_lists IS '订单组表：OCO 与括号单的父子关系（子订单按 client_oid 关联 orders 表，本地模拟的止损在重启后恢复）';
+COMMENT ON COLUMN order_lists.list_id IS '订单组客户端 ID（原生 OCO 时即 listClientOrderId）';
+COMMENT ON COLUMN order_lists.list_type IS '类型 [ENUM: OCO, BRACKET]';
+COMMENT ON COLUMN order_lists.status IS '状态 [ENUM: PENDING, ACTIVE, DONE, CANCELLED]';
+COMMENT ON COLUMN order_lists.symbol IS '交易对';
+COMMENT ON COLUMN order_lists.account_id IS '账户 ID';
+COMMENT ON COLUMN order_lists.strategy_id IS '下单策略（手动下单为空）';
+COMMENT ON COLUMN order_lists.entry_client_oid IS '括号单入场单 client_oid（OCO 为空）';
+COMMENT ON COLUMN order_lists.exit_side IS '止盈止损方向 [ENUM: BUY, SELL]';
+COMMENT ON COLUMN order_lists.quantity IS '止盈止损数量（括号单为入场单成交数量）';
+COMMENT ON COLUMN order_lists.take_profit_price IS '止盈限价';
+COMMENT ON COLUMN order_lists.stop_price IS '止损触发价';
+COMMENT ON COLUMN order_lists.stop_limit_price IS '止损触发后的限价（0 表示市价止损）';
+COMMENT ON COLUMN order_lists.take_profit_client_oid IS '止盈腿 client_oid';
+COMMENT ON COLUMN order_lists.stop_loss_client_oid IS '止损腿 client_oid（本地模拟时触发后才下单）';
+COMMENT ON COLUMN order_lists.exchange_list_id IS '交易所订单组 ID（原生 OCO）';
+COMMENT ON COLUMN order_lists.native IS '是否由交易所原生执行（否则由 OMS 本地模拟）';
+COMMENT ON COLUMN order_lists.reason IS '完结原因';
+COMMENT ON COLUMN order_lists.created_at IS '创建时间';
+COMMENT ON COLUMN order_lists.updated_at IS '更新时间';
@@ -0,0 +1,4 @@
+-- 条件单（止损、止盈）的触发价
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS stop_price DECIMAL(36, 18) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN orders.stop_price IS '条件单触发价（STOP_LOSS、STOP_LOSS_LIMIT、TAKE_PROFIT、TAKE_PROFIT_LIMIT，其余类型为 0）';
@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS reprice_count;
+ALTER TABLE orders DROP COLUMN IF EXISTS expire_policy;
+ALTER TABLE orders DROP COLUMN IF EXISTS max_age_ms;
+ALTER TABLE orders DROP COLUMN IF EXISTS expire_at;
@@ -0,0 +1,10 @@
+-- 挂单时效（good-till-time 与最长挂单时长，由 OMS 同步时撤单或改价）
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS expire_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS max_age_ms BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS expire_policy VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS reprice_count INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN orders.expire_at IS '挂单截止时间（到期撤单，NULL 表示不限）';
+COMMENT ON COLUMN orders.max_age_ms IS '最长挂单时长（毫秒，自创建起算，0 表示不限）';
+COMMENT ON COLUMN orders.expire_policy IS '超过最长挂单时长后的处理方式：CANCEL, REPRICE（空值按撤单处理）';
+COMMENT ON COLUMN orders.reprice_count IS '超时改价次数（改单后的新订单继承）';